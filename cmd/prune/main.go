@@ -10,6 +10,7 @@ import (
 	"github.com/xmtp/xmtpd/pkg/config"
 	"github.com/xmtp/xmtpd/pkg/db"
 	"github.com/xmtp/xmtpd/pkg/prune"
+	"github.com/xmtp/xmtpd/pkg/prune/archive"
 	"github.com/xmtp/xmtpd/pkg/utils"
 )
 
@@ -64,7 +65,18 @@ func main() {
 		fatal("could not connect to DB: %s", err)
 	}
 
-	pruneExecutor := prune.NewPruneExecutor(ctx, logger, dbInstance, &options.PruneConfig)
+	archiveStore, err := archive.NewStore(options.PruneConfig.Archive)
+	if err != nil {
+		fatal("could not initialize archive store: %s", err)
+	}
+
+	pruneExecutor := prune.NewPruneExecutor(
+		ctx,
+		logger,
+		dbInstance,
+		&options.PruneConfig,
+		prune.WithArchiveStore(archiveStore),
+	)
 
 	err = pruneExecutor.Run()
 	if err != nil {
@@ -85,7 +85,14 @@ func main() {
 
 	if options.Tracing.Enable {
 		logger.Info("starting tracer")
-		tracing.Start(Version, logger)
+		err := tracing.Start(context.Background(), Version, logger, tracing.Options{
+			Backend:      options.Tracing.Backend,
+			OTLPEndpoint: options.Tracing.OTLPEndpoint,
+			OTLPInsecure: options.Tracing.OTLPInsecure,
+		})
+		if err != nil {
+			fatal("could not start tracer: %s", err)
+		}
 		defer func() {
 			logger.Info("stopping tracer")
 			tracing.Stop()
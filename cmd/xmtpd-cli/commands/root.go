@@ -59,6 +59,7 @@ func configureRootCmd() error {
 		generateCmd(),
 		fundsCmd(),
 		paramsCmd(),
+		archiveCmd(),
 		versionCmd(),
 	)
 
@@ -15,7 +15,7 @@ export interface GatewayConfig {
   contractsConfigFilePath?: string;
   port?: number;
   logLevel?: string;
-  nodeSelectorStrategy?: "stable" | "random" | "ordered" | "closest" | "manual";
+  nodeSelectorStrategy?: "stable" | "random" | "ordered" | "closest" | "manual" | "adaptive";
   /** ms, default 30000 */
   healthCheckTimeout?: number;
 }
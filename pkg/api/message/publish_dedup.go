@@ -0,0 +1,228 @@
+package message
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/xmtp/xmtpd/pkg/db"
+	"github.com/xmtp/xmtpd/pkg/db/queries"
+	"github.com/xmtp/xmtpd/pkg/metrics"
+	envelopesProto "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/envelopes"
+	"github.com/xmtp/xmtpd/pkg/utils"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// stagedPublish is the outcome of staging the payer envelopes of a publish.
+type stagedPublish struct {
+	// originatorEnvelopes holds the originator envelope of each payer envelope, in order.
+	originatorEnvelopes []*envelopesProto.OriginatorEnvelope
+	// staged holds the envelopes staged by this publish. Republished envelopes are not staged
+	// again.
+	staged []queries.StagedOriginatorEnvelope
+	// latestSequenceID is the highest originator sequence ID of the originator envelopes.
+	latestSequenceID int64
+}
+
+// stageAndSign stages the payer envelopes and signs their originator envelopes.
+func (s *Service) stageAndSign(
+	ctx context.Context,
+	querier *queries.Queries,
+	processedEnvelopes []ValidatedBytesWithTopic,
+) (*stagedPublish, error) {
+	stagedEnvelopes, err := s.criticalPathDBInsert(ctx, querier, processedEnvelopes)
+	if err != nil {
+		return nil, fmt.Errorf("could not insert staged envelopes: %w", err)
+	}
+
+	published := &stagedPublish{
+		originatorEnvelopes: make([]*envelopesProto.OriginatorEnvelope, 0, len(stagedEnvelopes)),
+		staged:              stagedEnvelopes,
+	}
+
+	for idx, stagedEnvelope := range stagedEnvelopes {
+		envelope := processedEnvelopes[idx]
+
+		originatorEnvelope, err := s.registrant.SignStagedEnvelope(
+			stagedEnvelope,
+			envelope.BaseFee,
+			envelope.CongestionFee,
+			envelope.RetentionDays,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not sign envelope: %w", err)
+		}
+
+		published.originatorEnvelopes = append(published.originatorEnvelopes, originatorEnvelope)
+		published.latestSequenceID = max(published.latestSequenceID, stagedEnvelope.ID)
+	}
+
+	return published, nil
+}
+
+// stageDeduplicated stages the payer envelopes that were not published within the dedup
+// window, and returns the originator envelope signed when they were first published for
+// the others. A client retrying a publish thus gets a single originator sequence ID, and is
+// charged once.
+//
+// The lookup, the staging and the recording of the published envelopes run in one
+// transaction holding a lock on each payer envelope hash, so concurrent publishes of the same
+// payer envelope cannot both stage it, and an envelope is never staged without being recorded.
+// Publishes of other payer envelopes only wait on the staging lock, which the transaction takes
+// last and holds while signing and recording the staged envelopes.
+func (s *Service) stageDeduplicated(
+	ctx context.Context,
+	processedEnvelopes []ValidatedBytesWithTopic,
+) (*stagedPublish, error) {
+	hashes := make([][]byte, len(processedEnvelopes))
+	for i, envelope := range processedEnvelopes {
+		hash := sha256.Sum256(envelope.EnvelopeBytes)
+		hashes[i] = hash[:]
+	}
+
+	published, err := db.RunInTxWithResult(
+		ctx,
+		s.store.Write(),
+		&sql.TxOptions{Isolation: sql.LevelReadCommitted},
+		func(ctx context.Context, txQueries *queries.Queries) (*stagedPublish, error) {
+			if err := txQueries.LockPayerEnvelopeHashes(ctx, hashes); err != nil {
+				return nil, fmt.Errorf("could not lock payer envelope hashes: %w", err)
+			}
+
+			rows, err := txQueries.SelectPublishedPayerEnvelopes(
+				ctx,
+				queries.SelectPublishedPayerEnvelopesParams{
+					PayerEnvelopeHashes: hashes,
+					WindowSeconds:       s.options.PublishDedupWindow.Seconds(),
+				},
+			)
+			if err != nil {
+				return nil, fmt.Errorf("could not select published envelopes: %w", err)
+			}
+
+			previous := make(map[string]queries.PublishedPayerEnvelope, len(rows))
+			for _, row := range rows {
+				previous[string(row.PayerEnvelopeHash)] = row
+			}
+
+			// Envelopes repeated within the request are staged once, for their first index.
+			var (
+				published = &stagedPublish{
+					originatorEnvelopes: make(
+						[]*envelopesProto.OriginatorEnvelope,
+						len(processedEnvelopes),
+					),
+				}
+				toStage      []ValidatedBytesWithTopic
+				stagedIndex  = make(map[string]int)
+				stagedHashes [][]byte
+			)
+			for i, envelope := range processedEnvelopes {
+				key := string(hashes[i])
+				if row, ok := previous[key]; ok {
+					originatorEnvelope := &envelopesProto.OriginatorEnvelope{}
+					err := proto.Unmarshal(row.OriginatorEnvelope, originatorEnvelope)
+					if err != nil {
+						return nil, fmt.Errorf("could not unmarshal published envelope: %w", err)
+					}
+					published.originatorEnvelopes[i] = originatorEnvelope
+					published.latestSequenceID = max(
+						published.latestSequenceID,
+						row.OriginatorSequenceID,
+					)
+					continue
+				}
+				if _, ok := stagedIndex[key]; ok {
+					continue
+				}
+				stagedIndex[key] = len(toStage)
+				toStage = append(toStage, envelope)
+				stagedHashes = append(stagedHashes, hashes[i])
+			}
+
+			if len(toStage) > 0 {
+				staged, err := s.stageAndSign(ctx, txQueries, toStage)
+				if err != nil {
+					return nil, err
+				}
+
+				var (
+					sequenceIDs         = make([]int64, len(staged.staged))
+					originatorEnvelopes = make([][]byte, len(staged.staged))
+				)
+				for i, stagedEnvelope := range staged.staged {
+					sequenceIDs[i] = stagedEnvelope.ID
+					if originatorEnvelopes[i], err = proto.Marshal(
+						staged.originatorEnvelopes[i],
+					); err != nil {
+						return nil, fmt.Errorf("could not marshal originator envelope: %w", err)
+					}
+				}
+
+				if err := txQueries.InsertPublishedPayerEnvelopes(
+					ctx,
+					queries.InsertPublishedPayerEnvelopesParams{
+						PayerEnvelopeHashes:   stagedHashes,
+						OriginatorSequenceIds: sequenceIDs,
+						OriginatorEnvelopes:   originatorEnvelopes,
+					},
+				); err != nil {
+					return nil, fmt.Errorf("could not insert published envelopes: %w", err)
+				}
+
+				published.staged = staged.staged
+				published.latestSequenceID = max(
+					published.latestSequenceID,
+					staged.latestSequenceID,
+				)
+
+				for i := range processedEnvelopes {
+					if published.originatorEnvelopes[i] == nil {
+						idx := stagedIndex[string(hashes[i])]
+						published.originatorEnvelopes[i] = staged.originatorEnvelopes[idx]
+					}
+				}
+			}
+
+			return published, nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if duplicates := len(published.originatorEnvelopes) - len(published.staged); duplicates > 0 {
+		metrics.EmitAPIDeduplicatedPublishes(duplicates)
+	}
+
+	return published, nil
+}
+
+// prunePublishedPayerEnvelopes periodically deletes the published payer envelopes that
+// fell out of the dedup window.
+func (s *Service) prunePublishedPayerEnvelopes(ctx context.Context) {
+	ticker := time.NewTicker(s.options.PublishDedupWindow)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.store.WriteQuery().DeleteExpiredPublishedPayerEnvelopes(
+				ctx,
+				s.options.PublishDedupWindow.Seconds(),
+			)
+			if err != nil {
+				s.logger.Error("could not prune published payer envelopes", zap.Error(err))
+				continue
+			}
+			if s.logger.Core().Enabled(zap.DebugLevel) {
+				s.logger.Debug("pruned published payer envelopes", utils.CountField(deleted))
+			}
+		}
+	}
+}
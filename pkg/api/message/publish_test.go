@@ -561,6 +561,53 @@ func TestPublishEnvelopeBatchPublish(t *testing.T) {
 	}, 5*time.Second, 50*time.Millisecond)
 }
 
+func TestPublishEnvelopeDeduplicated(t *testing.T) {
+	suite := apiTestUtils.NewTestAPIServer(t, apiTestUtils.WithPublishDedupWindow(time.Minute))
+
+	payerEnvelope := envelopeTestUtils.CreatePayerEnvelope(
+		t,
+		envelopeTestUtils.DefaultClientEnvelopeNodeID,
+	)
+
+	publish := func(payerEnvelopes ...*envelopes.PayerEnvelope) []*envelopes.OriginatorEnvelope {
+		resp, err := suite.ClientReplication.PublishPayerEnvelopes(
+			context.Background(),
+			connect.NewRequest(&message_api.PublishPayerEnvelopesRequest{
+				PayerEnvelopes: payerEnvelopes,
+			}),
+		)
+		require.NoError(t, err)
+		require.Len(t, resp.Msg.GetOriginatorEnvelopes(), len(payerEnvelopes))
+		return resp.Msg.GetOriginatorEnvelopes()
+	}
+
+	first := publish(payerEnvelope, payerEnvelope)
+	require.True(t, proto.Equal(first[0], first[1]))
+
+	// A retried publish returns the original originator envelope.
+	retried := publish(payerEnvelope)
+	require.True(t, proto.Equal(first[0], retried[0]))
+
+	// The envelope is stored, and its usage is counted, once.
+	require.Eventually(t, func() bool {
+		envs, err := queries.New(suite.DB).
+			SelectGatewayEnvelopesUnfiltered(context.Background(), queries.SelectGatewayEnvelopesUnfilteredParams{})
+		require.NoError(t, err)
+
+		return len(envs) == 1
+	}, 5*time.Second, 50*time.Millisecond)
+
+	var publishedCount int
+	require.NoError(
+		t,
+		suite.DB.QueryRowContext(
+			context.Background(),
+			"SELECT COUNT(*) FROM published_payer_envelopes",
+		).Scan(&publishedCount),
+	)
+	require.Equal(t, 1, publishedCount)
+}
+
 func TestPublishEnvelopeBatchPublishNoPartialError(t *testing.T) {
 	suite := apiTestUtils.NewTestAPIServer(t)
 
@@ -673,6 +720,7 @@ func TestPublishEnvelopeBalanceEnforcement(t *testing.T) {
 						payerID,
 						tc.deposit,
 						eventID,
+						0,
 					)
 					require.NoError(t, err)
 				}
@@ -747,6 +795,7 @@ func TestPublishEnvelopeMultiEnvelopeBatchBalance(t *testing.T) {
 		payerID,
 		currency.PicoDollar(1), // 1 picodollar — nearly nothing
 		eventID,
+		0,
 	)
 	require.NoError(t, err)
 
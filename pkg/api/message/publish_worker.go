@@ -399,16 +399,17 @@ func (p *publishWorker) persistBatch(
 	originatorTimes := make([]time.Time, 0, len(prepared))
 	for _, prep := range prepared {
 		batchInput.Add(types.GatewayEnvelopeRow{
-			OriginatorNodeID:     originatorID,
-			OriginatorSequenceID: prep.staged.ID,
-			Topic:                prep.staged.Topic,
-			PayerID:              payerMap[prep.payerAddress],
-			GatewayTime:          prep.staged.OriginatorTime,
-			Expiry:               prep.expiry,
-			OriginatorEnvelope:   prep.originatorBytes,
-			SpendPicodollars:     int64(prep.baseFee) + int64(prep.congestionFee),
-			CountUsage:           !prep.isReserved,
-			CountCongestion:      !prep.isReserved,
+			OriginatorNodeID:      originatorID,
+			OriginatorSequenceID:  prep.staged.ID,
+			Topic:                 prep.staged.Topic,
+			PayerID:               payerMap[prep.payerAddress],
+			GatewayTime:           prep.staged.OriginatorTime,
+			Expiry:                prep.expiry,
+			OriginatorEnvelope:    prep.originatorBytes,
+			SpendPicodollars:      int64(prep.baseFee) + int64(prep.congestionFee),
+			CongestionPicodollars: int64(prep.congestionFee),
+			CountUsage:            !prep.isReserved,
+			CountCongestion:       !prep.isReserved,
 		})
 		stagedIDs = append(stagedIDs, prep.staged.ID)
 		originatorTimes = append(originatorTimes, prep.staged.OriginatorTime)
@@ -0,0 +1,171 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"go.uber.org/zap"
+
+	"github.com/xmtp/xmtpd/pkg/config"
+	"github.com/xmtp/xmtpd/pkg/ratelimiter"
+	"github.com/xmtp/xmtpd/pkg/topic"
+)
+
+// ReadCaller identifies the caller of a read. Subject is keyed like rate
+// limiting: the operator ID of a Tier 1 caller, the client IP otherwise.
+type ReadCaller struct {
+	Tier    ratelimiter.Tier
+	Subject string
+}
+
+// ReadDecision is a read authorizer's verdict on one topic. Cost is a weight in
+// tokens charged to the caller's rate-limit bucket on top of the request's own
+// cost; it is ignored when rate limiting is disabled.
+type ReadDecision struct {
+	Allowed bool
+	Cost    uint64
+}
+
+// AuthorizeReadFn decides whether a caller may read a topic. It is invoked for
+// every topic of a QueryEnvelopes, SubscribeEnvelopes or SubscribeTopics request
+// and every topic a Subscribe Mutate adds. Returning an error fails the request
+// with Internal. Reads by originator cannot be authorized per topic, and are
+// only allowed to Tier 1 callers while any authorizer is configured.
+type AuthorizeReadFn func(
+	ctx context.Context,
+	caller ReadCaller,
+	t *topic.Topic,
+) (ReadDecision, error)
+
+// NewTopicKindReadAuthorizer returns an AuthorizeReadFn applying policy to
+// Tier 2 callers: topic kinds with a deny rule cannot be read, and the others
+// cost their configured weight. Tier 1 callers are always allowed at no cost.
+func NewTopicKindReadAuthorizer(policy config.TopicKindReadPolicy) AuthorizeReadFn {
+	return func(_ context.Context, caller ReadCaller, t *topic.Topic) (ReadDecision, error) {
+		if caller.Tier != ratelimiter.Tier2 {
+			return ReadDecision{Allowed: true}, nil
+		}
+		rule, ok := policy[t.Kind()]
+		if !ok {
+			return ReadDecision{Allowed: true}, nil
+		}
+		return ReadDecision{Allowed: !rule.Deny, Cost: rule.Cost}, nil
+	}
+}
+
+// parseReadTopics parses the topics of a read request for authorization.
+// Unparseable topics are skipped: no envelope is ever stored under one.
+func parseReadTopics(raw [][]byte) []*topic.Topic {
+	topics := make([]*topic.Topic, 0, len(raw))
+	for _, b := range raw {
+		if t, err := topic.ParseTopic(b); err == nil {
+			topics = append(topics, t)
+		}
+	}
+	return topics
+}
+
+// authorizeQueryReads authorizes the reads of a query: the topics it names, and
+// any originators it reads from.
+func (s *Service) authorizeQueryReads(
+	ctx context.Context,
+	clientIP string,
+	query *subscribeFilter,
+) error {
+	if len(query.originatorNodeIDs) > 0 {
+		if err := s.authorizeUnscopedReads(ctx); err != nil {
+			return err
+		}
+	}
+	return s.authorizeReads(ctx, clientIP, parseReadTopics(query.topics))
+}
+
+// authorizeUnscopedReads decides whether the caller may read envelopes of any
+// topic, by originator or from every originator. Read authorizers decide per
+// topic, so while any are configured, only Tier 1 callers may. Tier 0 callers
+// are never checked.
+func (s *Service) authorizeUnscopedReads(ctx context.Context) error {
+	if len(s.readAuthorizers) == 0 {
+		return nil
+	}
+	if ratelimiter.ClassifyTier(ctx) != ratelimiter.Tier2 {
+		return nil
+	}
+	return connect.NewError(
+		connect.CodePermissionDenied,
+		errors.New("reads by originator require authentication"),
+	)
+}
+
+// authorizeReads asks every read authorizer whether the caller may read
+// topics, then charges the summed cost weight to the caller's bucket. Tier 0
+// callers are never checked, like they are never rate limited.
+func (s *Service) authorizeReads(
+	ctx context.Context,
+	clientIP string,
+	topics []*topic.Topic,
+) error {
+	if len(s.readAuthorizers) == 0 || len(topics) == 0 {
+		return nil
+	}
+
+	tier := ratelimiter.ClassifyTier(ctx)
+	if tier == ratelimiter.Tier0 {
+		return nil
+	}
+	caller := ReadCaller{Tier: tier, Subject: clientIP}
+	if operatorID, ok := ratelimiter.OperatorID(ctx); ok {
+		caller.Subject = operatorID
+	}
+
+	var cost uint64
+	for _, t := range topics {
+		for _, authorize := range s.readAuthorizers {
+			decision, err := authorize(ctx, caller, t)
+			if err != nil {
+				s.logger.Warn("read authorization error",
+					zap.String("subject", caller.Subject),
+					zap.String("topic", t.String()),
+					zap.Error(err),
+				)
+				return connect.NewError(
+					connect.CodeInternal,
+					fmt.Errorf("read authorization error: %w", err),
+				)
+			}
+			if !decision.Allowed {
+				s.logger.Debug("unauthorized read",
+					zap.String("subject", caller.Subject),
+					zap.String("topic", t.String()),
+				)
+				return connect.NewError(
+					connect.CodePermissionDenied,
+					fmt.Errorf("not authorized to read %s topics", t.Kind()),
+				)
+			}
+			cost += decision.Cost
+		}
+	}
+
+	if cost == 0 {
+		return nil
+	}
+	limiter, subject, _, ok := callerBucket(ctx, s.rateLimiter, s.rlConfig, clientIP)
+	if !ok {
+		return nil
+	}
+	res, err := limiter.Allow(ctx, subject, cost)
+	if err != nil {
+		// BreakerLimiter handles errors and fails open; defensive no-op here.
+		return nil //nolint:nilerr // intentional fail-open
+	}
+	if !res.Allowed {
+		return connect.NewError(
+			connect.CodeResourceExhausted,
+			errors.New("read rate limit exceeded"),
+		)
+	}
+	return nil
+}
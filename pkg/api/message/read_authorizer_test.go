@@ -0,0 +1,200 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/xmtp/xmtpd/pkg/config"
+	"github.com/xmtp/xmtpd/pkg/constants"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/message_api"
+	"github.com/xmtp/xmtpd/pkg/ratelimiter"
+	"github.com/xmtp/xmtpd/pkg/topic"
+)
+
+var (
+	readTestWelcome = topic.NewTopic(topic.TopicKindWelcomeMessagesV1, []byte("installation"))
+	readTestGroup   = topic.NewTopic(topic.TopicKindGroupMessagesV1, []byte("group"))
+)
+
+func newReadTestService(limiter ratelimiter.RateLimiter, authorizers ...AuthorizeReadFn) *Service {
+	return &Service{
+		ctx:             context.Background(),
+		logger:          zap.NewNop(),
+		rateLimiter:     limiter,
+		rlConfig:        RateLimitConfig{Enabled: limiter != nil},
+		readAuthorizers: authorizers,
+	}
+}
+
+func TestAuthorizeReads_DeniedTopicIsPermissionDenied(t *testing.T) {
+	svc := newReadTestService(nil, NewTopicKindReadAuthorizer(config.TopicKindReadPolicy{
+		topic.TopicKindWelcomeMessagesV1: {Deny: true},
+	}))
+
+	err := svc.authorizeReads(
+		context.Background(),
+		"203.0.113.1",
+		[]*topic.Topic{readTestGroup, readTestWelcome},
+	)
+	require.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+
+	require.NoError(t, svc.authorizeReads(
+		context.Background(),
+		"203.0.113.1",
+		[]*topic.Topic{readTestGroup},
+	))
+}
+
+func TestAuthorizeReads_ChargesCostToCallerBucket(t *testing.T) {
+	limiter := &spyLimiter{}
+	svc := newReadTestService(limiter, NewTopicKindReadAuthorizer(config.TopicKindReadPolicy{
+		topic.TopicKindWelcomeMessagesV1: {Cost: 3},
+	}))
+
+	require.NoError(t, svc.authorizeReads(
+		context.Background(),
+		"203.0.113.1",
+		[]*topic.Topic{readTestWelcome, readTestGroup, readTestWelcome},
+	))
+	require.Equal(t, "203.0.113.1", limiter.allowSubject)
+	require.Equal(t, uint64(6), limiter.allowCost)
+
+	limiter.allowResult = &ratelimiter.Result{Allowed: false}
+	err := svc.authorizeReads(context.Background(), "203.0.113.1", []*topic.Topic{readTestWelcome})
+	require.Equal(t, connect.CodeResourceExhausted, connect.CodeOf(err))
+}
+
+func TestAuthorizeReads_PassesCallerIdentity(t *testing.T) {
+	var got ReadCaller
+	svc := newReadTestService(
+		nil,
+		func(_ context.Context, caller ReadCaller, _ *topic.Topic) (ReadDecision, error) {
+			got = caller
+			return ReadDecision{Allowed: true}, nil
+		},
+	)
+	ctx := context.WithValue(context.Background(), constants.VerifiedOperatorIDCtxKey{}, "acme")
+
+	require.NoError(t, svc.authorizeReads(ctx, "203.0.113.1", []*topic.Topic{readTestGroup}))
+	require.Equal(t, ReadCaller{Tier: ratelimiter.Tier1, Subject: "acme"}, got)
+}
+
+func TestAuthorizeReads_Tier0IsNotAuthorized(t *testing.T) {
+	svc := newReadTestService(
+		nil,
+		func(context.Context, ReadCaller, *topic.Topic) (ReadDecision, error) {
+			return ReadDecision{}, nil
+		},
+	)
+	ctx := context.WithValue(context.Background(), constants.VerifiedNodeRequestCtxKey{}, true)
+
+	require.NoError(t, svc.authorizeReads(ctx, "203.0.113.1", []*topic.Topic{readTestGroup}))
+}
+
+func TestAuthorizeReads_AuthorizerErrorIsInternal(t *testing.T) {
+	svc := newReadTestService(
+		nil,
+		func(context.Context, ReadCaller, *topic.Topic) (ReadDecision, error) {
+			return ReadDecision{}, errors.New("policy store unavailable")
+		},
+	)
+
+	err := svc.authorizeReads(context.Background(), "203.0.113.1", []*topic.Topic{readTestGroup})
+	require.Equal(t, connect.CodeInternal, connect.CodeOf(err))
+}
+
+func TestTopicKindReadAuthorizer_OnlyAppliesToTier2(t *testing.T) {
+	authorize := NewTopicKindReadAuthorizer(config.TopicKindReadPolicy{
+		topic.TopicKindKeyPackagesV1: {Deny: true},
+	})
+	keyPackages := topic.NewTopic(topic.TopicKindKeyPackagesV1, []byte("installation"))
+
+	decision, err := authorize(
+		context.Background(),
+		ReadCaller{Tier: ratelimiter.Tier2},
+		keyPackages,
+	)
+	require.NoError(t, err)
+	require.False(t, decision.Allowed)
+
+	decision, err = authorize(
+		context.Background(),
+		ReadCaller{Tier: ratelimiter.Tier1, Subject: "acme"},
+		keyPackages,
+	)
+	require.NoError(t, err)
+	require.True(t, decision.Allowed)
+}
+
+func TestAuthorizeQueryReads_OriginatorReadsRequireTier1(t *testing.T) {
+	svc := newReadTestService(nil, NewTopicKindReadAuthorizer(config.TopicKindReadPolicy{
+		topic.TopicKindWelcomeMessagesV1: {Deny: true},
+	}))
+	byOriginator := &subscribeFilter{originatorNodeIDs: []uint32{100}}
+
+	err := svc.authorizeQueryReads(context.Background(), "203.0.113.1", byOriginator)
+	require.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+	require.Equal(
+		t,
+		connect.CodePermissionDenied,
+		connect.CodeOf(svc.authorizeUnscopedReads(context.Background())),
+	)
+
+	tier1 := context.WithValue(context.Background(), constants.VerifiedOperatorIDCtxKey{}, "acme")
+	require.NoError(t, svc.authorizeQueryReads(tier1, "203.0.113.1", byOriginator))
+	require.NoError(t, svc.authorizeUnscopedReads(tier1))
+
+	tier0 := context.WithValue(context.Background(), constants.VerifiedNodeRequestCtxKey{}, true)
+	require.NoError(t, svc.authorizeQueryReads(tier0, "203.0.113.1", byOriginator))
+
+	// Topic reads are still authorized per topic.
+	require.NoError(t, svc.authorizeQueryReads(
+		context.Background(),
+		"203.0.113.1",
+		&subscribeFilter{topics: [][]byte{readTestGroup.Bytes()}},
+	))
+}
+
+func TestAuthorizeQueryReads_NoAuthorizersAllowsOriginatorReads(t *testing.T) {
+	svc := newReadTestService(nil)
+
+	require.NoError(t, svc.authorizeQueryReads(
+		context.Background(),
+		"203.0.113.1",
+		&subscribeFilter{originatorNodeIDs: []uint32{100}},
+	))
+	require.NoError(t, svc.authorizeUnscopedReads(context.Background()))
+}
+
+// TestSubscribeSessionMutateDeniedTopicRejected covers read authorization on the bidi stream: a
+// Mutate adding a denied topic fails as a whole, before any topic is gated or any wave starts.
+func TestSubscribeSessionMutateDeniedTopicRejected(t *testing.T) {
+	ctx := context.Background()
+	svc := newReadTestService(nil, NewTopicKindReadAuthorizer(config.TopicKindReadPolicy{
+		topic.TopicKindWelcomeMessagesV1: {Deny: true},
+	}))
+	sess := &subscribeSession{
+		svc: svc, logger: zap.NewNop(), ctx: ctx, keepAlive: time.Second,
+		outbound: make(chan *message_api.SubscribeResponse, 16),
+		topics:   make(map[string]*topicState),
+		waves:    make(map[int]*subscribeWave),
+		clientIP: "203.0.113.1",
+	}
+
+	err := sess.handleMutate(&message_api.SubscribeRequest_V1_Mutate{
+		MutateId: 1,
+		Adds: []*message_api.SubscribeRequest_V1_Mutate_Subscription{
+			{Topic: readTestGroup.Bytes()},
+			{Topic: readTestWelcome.Bytes()},
+		},
+	})
+	require.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+	require.Empty(t, sess.topics, "a denied Mutate must not gate any topic")
+	require.Empty(t, sess.waves, "a denied Mutate must not start a wave")
+}
@@ -3,10 +3,12 @@ package message
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"math"
 	"strings"
+	"sync"
 	"time"
 
 	"connectrpc.com/connect"
@@ -23,6 +25,7 @@ import (
 	"github.com/xmtp/xmtpd/pkg/ledger"
 	"github.com/xmtp/xmtpd/pkg/metrics"
 	"github.com/xmtp/xmtpd/pkg/mlsvalidate"
+	"github.com/xmtp/xmtpd/pkg/proto/identity/associations"
 	envelopesProto "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/envelopes"
 	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/message_api"
 	message_apiconnect "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/message_api/message_apiconnect"
@@ -32,6 +35,7 @@ import (
 	"github.com/xmtp/xmtpd/pkg/topic"
 	"github.com/xmtp/xmtpd/pkg/tracing"
 	"github.com/xmtp/xmtpd/pkg/utils"
+	"github.com/xmtp/xmtpd/pkg/utils/clientip"
 	"github.com/xmtp/xmtpd/pkg/utils/retryerrors"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/proto"
@@ -40,6 +44,7 @@ import (
 const (
 	maxRequestedRows      int32         = 1000
 	maxInboxIdsPerRequest int           = 1000
+	inboxIDLength         int           = 32
 	maxQueriesPerRequest  int           = 10000
 	maxTopicLength        int           = 128
 	maxVectorClockLength  int           = 100
@@ -49,11 +54,19 @@ const (
 )
 
 // RateLimitConfig is the subset of rate-limit options the message Service
-// needs at handler-time. Currently only a feature flag — the admission cost
-// formula lives in the ratelimiter package. Kept as a struct so future knobs
-// (see xmtp/xmtpd#1957) have a place to land without changing call sites.
+// needs at handler-time. The admission cost formula lives in the ratelimiter
+// package.
 type RateLimitConfig struct {
 	Enabled bool
+	// Tier1Limiter bills app operators by operator ID. When nil, Tier 1
+	// requests are billed to the Service's rate limiter by client IP.
+	Tier1Limiter ratelimiter.RateLimiter
+	// StreamBytesPerToken is the number of bytes a subscribe stream delivers
+	// per token charged to its caller's bucket. Zero disables the drain.
+	StreamBytesPerToken int
+	// StreamMaxWait bounds how long a subscribe stream waits for its caller's
+	// bucket to refill before it is terminated with ResourceExhausted.
+	StreamMaxWait time.Duration
 }
 
 type Service struct {
@@ -74,6 +87,8 @@ type Service struct {
 	ledger            ledger.ILedger
 	rateLimiter       ratelimiter.RateLimiter // nil when rate limiting disabled
 	rlConfig          RateLimitConfig
+	readAuthorizers   []AuthorizeReadFn
+	wg                sync.WaitGroup
 }
 
 var (
@@ -99,6 +114,7 @@ func NewReplicationAPIService(
 	ledger ledger.ILedger,
 	rateLimiter ratelimiter.RateLimiter,
 	rlConfig RateLimitConfig,
+	readAuthorizers []AuthorizeReadFn,
 ) (*Service, error) {
 	if validationService == nil {
 		return nil, errors.New("validation service must not be nil")
@@ -127,7 +143,7 @@ func NewReplicationAPIService(
 		return nil, err
 	}
 
-	return &Service{
+	svc := &Service{
 		ctx:               ctx,
 		logger:            logger,
 		registrant:        registrant,
@@ -143,10 +159,23 @@ func NewReplicationAPIService(
 		ledger:            ledger,
 		rateLimiter:       rateLimiter,
 		rlConfig:          rlConfig,
-	}, nil
+		readAuthorizers:   readAuthorizers,
+	}
+
+	if options.PublishDedupWindow > 0 {
+		tracing.GoPanicWrap(
+			ctx,
+			&svc.wg,
+			"published-payer-envelopes-pruner",
+			svc.prunePublishedPayerEnvelopes,
+		)
+	}
+
+	return svc, nil
 }
 
 func (s *Service) Close() {
+	s.wg.Wait()
 	s.logger.Debug("closed")
 }
 
@@ -190,6 +219,15 @@ func (s *Service) SubscribeEnvelopes(
 		)
 	}
 
+	clientIP := clientip.Extract(
+		req.Peer().Addr,
+		req.Header().Get("X-Forwarded-For"),
+		subscribeTrustedCIDRs,
+	)
+	if err := s.authorizeQueryReads(ctx, clientIP, query); err != nil {
+		return err
+	}
+
 	return s.doSubscribe(ctx, query, stream, logger)
 }
 
@@ -213,6 +251,10 @@ func (s *Service) SubscribeAllEnvelopes(
 		)
 	}
 
+	if err := s.authorizeUnscopedReads(ctx); err != nil {
+		return err
+	}
+
 	query := &subscribeFilter{
 		catchUpMode: catchUpNone,
 		cursor:      make(map[uint32]uint64),
@@ -391,6 +433,16 @@ func (s *Service) QueryEnvelopes(
 		)
 	}
 
+	clientIP := clientip.Extract(
+		req.Peer().Addr,
+		req.Header().Get("X-Forwarded-For"),
+		subscribeTrustedCIDRs,
+	)
+	if err := s.authorizeQueryReads(ctx, clientIP, query); err != nil {
+		tracing.SpanTag(span, "error", err)
+		return nil, err
+	}
+
 	var limit int32
 	if req.Msg.GetLimit() > uint32(maxRequestedRows) || req.Msg.GetLimit() == 0 {
 		limit = maxRequestedRows
@@ -716,54 +768,43 @@ func (s *Service) PublishPayerEnvelopes(
 		}
 	}
 
-	var results []*envelopesProto.OriginatorEnvelope
-	var latestStaged *queries.StagedOriginatorEnvelope
-
-	stagedEnvelopes, err := s.criticalPathDBInsert(ctx, processedEnvelopes)
+	var published *stagedPublish
+	if s.options.PublishDedupWindow > 0 {
+		published, err = s.stageDeduplicated(ctx, processedEnvelopes)
+	} else {
+		published, err = s.stageAndSign(ctx, s.store.WriteQuery(), processedEnvelopes)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("could not insert staged envelopes: %w", err)
+		return nil, err
 	}
 
-	for idx, stagedEnvelope := range stagedEnvelopes {
-		envelope := processedEnvelopes[idx]
-
-		originatorEnvelope, err := s.registrant.SignStagedEnvelope(
-			stagedEnvelope,
-			envelope.BaseFee,
-			envelope.CongestionFee,
-			envelope.RetentionDays,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("could not sign envelope: %w", err)
+	if len(published.staged) > 0 {
+		for _, stagedEnvelope := range published.staged {
+			s.publishWorker.storeTraceContext(stagedEnvelope.ID, span)
 		}
 
-		results = append(results, originatorEnvelope)
-
-		s.publishWorker.storeTraceContext(stagedEnvelope.ID, span)
-
-		latestStaged = &stagedEnvelope
+		// Notify publish worker - this triggers the async processing
+		s.publishWorker.notifyStagedPublish()
 	}
 
-	// Notify publish worker - this triggers the async processing
-	s.publishWorker.notifyStagedPublish()
-
 	// Wait for gateway publish - this is where we wait for the envelope to be fully processed
 	waitSpan, waitCtx := tracing.StartSpanFromContext(ctx, tracing.SpanNodeWaitGatewayPublish)
-	s.waitForGatewayPublish(waitCtx, latestStaged, logger)
+	s.waitForGatewayPublish(waitCtx, published.latestSequenceID, logger)
 	waitSpan.Finish()
 
 	metrics.EmitSyncLastSeenOriginatorSequenceID(
 		s.registrant.NodeID(),
-		uint64(latestStaged.ID),
+		uint64(published.latestSequenceID),
 	)
 
 	return connect.NewResponse(&message_api.PublishPayerEnvelopesResponse{
-		OriginatorEnvelopes: results,
+		OriginatorEnvelopes: published.originatorEnvelopes,
 	}), nil
 }
 
 func (s *Service) criticalPathDBInsert(
 	ctx context.Context,
+	querier *queries.Queries,
 	processedEnvelopes []ValidatedBytesWithTopic,
 ) ([]queries.StagedOriginatorEnvelope, error) {
 	topics := make([][]byte, 0, len(processedEnvelopes))
@@ -777,7 +818,7 @@ func (s *Service) criticalPathDBInsert(
 	stageStart := time.Now()
 	defer func() { metrics.EmitAPIStageEnvelope(time.Since(stageStart)) }()
 
-	insertedStaged, err := s.store.WriteQuery().InsertStagedOriginatorEnvelopeBatch(
+	insertedStaged, err := querier.InsertStagedOriginatorEnvelopeBatch(
 		ctx,
 		queries.InsertStagedOriginatorEnvelopeBatchParams{
 			Topics:         topics,
@@ -973,32 +1014,52 @@ func (s *Service) GetInboxIds(
 	}
 
 	var (
-		requests  = req.Msg.GetRequests()
-		addresses = make([]string, 0, len(requests))
+		requests        = req.Msg.GetRequests()
+		addresses       = make([]string, 0, len(requests))
+		identifierKinds = make([]int16, 0, len(requests))
 	)
 
 	for _, request := range requests {
 		addresses = append(addresses, request.GetIdentifier())
+		identifierKinds = append(
+			identifierKinds,
+			int16(normalizeIdentifierKind(request.GetIdentifierKind())),
+		)
 	}
 
-	addressLogEntries, err := s.store.ReadQuery().GetAddressLogs(ctx, addresses)
+	addressLogEntries, err := s.store.ReadQuery().GetAddressLogs(
+		ctx,
+		queries.GetAddressLogsParams{
+			Addresses:       addresses,
+			IdentifierKinds: identifierKinds,
+		},
+	)
 	if err != nil {
 		return nil, err
 	}
 
+	type identifierKey struct {
+		identifier string
+		kind       int16
+	}
+
+	inboxIDs := make(map[identifierKey]string, len(addressLogEntries))
+	for _, logEntry := range addressLogEntries {
+		inboxIDs[identifierKey{logEntry.Address, logEntry.IdentifierKind}] = logEntry.InboxID
+	}
+
 	response := connect.NewResponse(&message_api.GetInboxIdsResponse{
 		Responses: make([]*message_api.GetInboxIdsResponse_Response, len(addresses)),
 	})
 
 	for index, address := range addresses {
-		resp := message_api.GetInboxIdsResponse_Response{}
-		resp.Identifier = address
+		resp := message_api.GetInboxIdsResponse_Response{
+			Identifier:     address,
+			IdentifierKind: associations.IdentifierKind(identifierKinds[index]),
+		}
 
-		for _, logEntry := range addressLogEntries {
-			if logEntry.Address == address {
-				inboxID := logEntry.InboxID
-				resp.InboxId = &inboxID
-			}
+		if inboxID, ok := inboxIDs[identifierKey{address, identifierKinds[index]}]; ok {
+			resp.InboxId = &inboxID
 		}
 		response.Msg.Responses[index] = &resp
 	}
@@ -1008,6 +1069,131 @@ func (s *Service) GetInboxIds(
 	return response, nil
 }
 
+func (s *Service) GetIdentifiersForInbox(
+	ctx context.Context,
+	req *connect.Request[message_api.GetIdentifiersForInboxRequest],
+) (*connect.Response[message_api.GetIdentifiersForInboxResponse], error) {
+	if req.Msg == nil {
+		return nil, connect.NewError(
+			connect.CodeInvalidArgument,
+			errors.New(requestMissingMessageError),
+		)
+	}
+
+	logger := s.logger.With(utils.MethodField(req.Spec().Procedure))
+
+	if s.logger.Core().Enabled(zap.DebugLevel) {
+		logger.Debug("received request", utils.BodyField(req))
+	}
+
+	inboxID, err := parseInboxID(req.Msg.GetInboxId())
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.store.ReadQuery().GetIdentifiersForInbox(
+		ctx,
+		queries.GetIdentifiersForInboxParams{
+			InboxID:        inboxID,
+			IncludeRevoked: req.Msg.GetIncludeRevoked(),
+		},
+	)
+	if err != nil {
+		return nil, connect.NewError(
+			connect.CodeInternal,
+			fmt.Errorf("could not select identifiers: %w", err),
+		)
+	}
+
+	identifiers := make([]*message_api.GetIdentifiersForInboxResponse_Identifier, 0, len(rows))
+	for _, row := range rows {
+		identifiers = append(identifiers, &message_api.GetIdentifiersForInboxResponse_Identifier{
+			Identifier:            row.Address,
+			IdentifierKind:        associations.IdentifierKind(row.IdentifierKind),
+			AssociationSequenceId: uint64(row.AssociationSequenceID.Int64),
+			RevocationSequenceId:  uint64(row.RevocationSequenceID.Int64),
+		})
+	}
+
+	logger.Debug("got identifiers for inbox", utils.NumResponsesField(len(identifiers)))
+
+	return connect.NewResponse(&message_api.GetIdentifiersForInboxResponse{
+		Identifiers: identifiers,
+	}), nil
+}
+
+func (s *Service) GetInboxAssociationState(
+	ctx context.Context,
+	req *connect.Request[message_api.GetInboxAssociationStateRequest],
+) (*connect.Response[message_api.GetInboxAssociationStateResponse], error) {
+	if req.Msg == nil {
+		return nil, connect.NewError(
+			connect.CodeInvalidArgument,
+			errors.New(requestMissingMessageError),
+		)
+	}
+
+	logger := s.logger.With(utils.MethodField(req.Spec().Procedure))
+
+	if s.logger.Core().Enabled(zap.DebugLevel) {
+		logger.Debug("received request", utils.BodyField(req))
+	}
+
+	inboxID, err := parseInboxID(req.Msg.GetInboxId())
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := s.store.ReadQuery().GetInboxAssociationState(ctx, inboxID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, connect.NewError(
+			connect.CodeNotFound,
+			errors.New("no association state stored for inbox"),
+		)
+	}
+	if err != nil {
+		return nil, connect.NewError(
+			connect.CodeInternal,
+			fmt.Errorf("could not select association state: %w", err),
+		)
+	}
+
+	associationState := &associations.AssociationState{}
+	if err := proto.Unmarshal(snapshot.AssociationState, associationState); err != nil {
+		return nil, connect.NewError(
+			connect.CodeInternal,
+			fmt.Errorf("could not unmarshal association state: %w", err),
+		)
+	}
+
+	return connect.NewResponse(&message_api.GetInboxAssociationStateResponse{
+		AssociationState: associationState,
+		SequenceId:       uint64(snapshot.SequenceID),
+	}), nil
+}
+
+// parseInboxID validates a hex-encoded inbox ID, and returns it in the encoding used by the
+// database.
+func parseInboxID(inboxID string) (string, error) {
+	decoded, err := utils.HexDecode(inboxID)
+	if err != nil || len(decoded) != inboxIDLength {
+		return "", connect.NewError(
+			connect.CodeInvalidArgument,
+			errors.New("inbox_id must be a hex-encoded 32 byte inbox ID"),
+		)
+	}
+	return utils.HexEncode(decoded), nil
+}
+
+// normalizeIdentifierKind maps the unspecified identifier kind, sent by old clients, to
+// Ethereum.
+func normalizeIdentifierKind(kind associations.IdentifierKind) associations.IdentifierKind {
+	if kind == associations.IdentifierKind_IDENTIFIER_KIND_UNSPECIFIED {
+		return associations.IdentifierKind_IDENTIFIER_KIND_ETHEREUM
+	}
+	return kind
+}
+
 func (s *Service) GetNewestEnvelope(
 	ctx context.Context,
 	req *connect.Request[message_api.GetNewestEnvelopeRequest],
@@ -1309,13 +1495,13 @@ func (s *Service) getAvailableBalance(
 
 func (s *Service) waitForGatewayPublish(
 	ctx context.Context,
-	stagedEnv *queries.StagedOriginatorEnvelope,
+	sequenceID int64,
 	logger *zap.Logger,
 ) {
 	if s.logger.Core().Enabled(zap.DebugLevel) {
 		logger = logger.With(
-			utils.SequenceIDField(stagedEnv.ID),
-			utils.EnvelopeIDField(stagedEnv.ID),
+			utils.SequenceIDField(sequenceID),
+			utils.EnvelopeIDField(sequenceID),
 		)
 	}
 
@@ -1349,7 +1535,7 @@ func (s *Service) waitForGatewayPublish(
 
 		case <-ticker.C:
 			// Check if the last processed ID has reached or exceeded the current ID
-			if s.publishWorker.lastProcessed.Load() >= stagedEnv.ID {
+			if s.publishWorker.lastProcessed.Load() >= sequenceID {
 				if s.logger.Core().Enabled(zap.DebugLevel) {
 					logger.Debug(
 						"finished waiting for publisher",
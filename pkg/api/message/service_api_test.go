@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 	dbPkg "github.com/xmtp/xmtpd/pkg/db"
 	"github.com/xmtp/xmtpd/pkg/db/queries"
+	"github.com/xmtp/xmtpd/pkg/proto/identity/associations"
 	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/message_api"
 	payer_api "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/payer_api"
 	"github.com/xmtp/xmtpd/pkg/testutils"
@@ -63,6 +64,113 @@ func TestQueryApi_GetInboxIds(t *testing.T) {
 	require.NotNil(t, resp)
 }
 
+// TestQueryApi_GetInboxIdsByIdentifierKind verifies that GetInboxIds only resolves an
+// identifier associated with the requested kind, and treats the unspecified kind as Ethereum.
+func TestQueryApi_GetInboxIdsByIdentifierKind(t *testing.T) {
+	suite := apiTestUtils.NewTestAPIServer(t)
+
+	identifier := testutils.RandomString(20)
+	inboxID := testutils.RandomInboxIDString()
+
+	_, err := queries.New(suite.DB).InsertAddressLog(
+		context.Background(),
+		queries.InsertAddressLogParams{
+			Address:               identifier,
+			IdentifierKind:        int16(associations.IdentifierKind_IDENTIFIER_KIND_PASSKEY),
+			InboxID:               inboxID,
+			AssociationSequenceID: dbPkg.NullInt64(1),
+		},
+	)
+	require.NoError(t, err)
+
+	resp, err := suite.ClientQuery.GetInboxIds(
+		context.Background(),
+		connect.NewRequest(&message_api.GetInboxIdsRequest{
+			Requests: []*message_api.GetInboxIdsRequest_Request{
+				{Identifier: identifier},
+				{
+					Identifier:     identifier,
+					IdentifierKind: associations.IdentifierKind_IDENTIFIER_KIND_PASSKEY,
+				},
+			},
+		}),
+	)
+	require.NoError(t, err)
+	require.Len(t, resp.Msg.GetResponses(), 2)
+
+	ethereum := resp.Msg.GetResponses()[0]
+	require.Equal(
+		t,
+		associations.IdentifierKind_IDENTIFIER_KIND_ETHEREUM,
+		ethereum.GetIdentifierKind(),
+	)
+	require.Nil(t, ethereum.InboxId)
+
+	passkey := resp.Msg.GetResponses()[1]
+	require.Equal(
+		t,
+		associations.IdentifierKind_IDENTIFIER_KIND_PASSKEY,
+		passkey.GetIdentifierKind(),
+	)
+	require.Equal(t, inboxID, passkey.GetInboxId())
+}
+
+// TestQueryApi_GetIdentifiersForInbox verifies that GetIdentifiersForInbox is reachable via the
+// QueryApi client, and rejects malformed inbox IDs.
+func TestQueryApi_GetIdentifiersForInbox(t *testing.T) {
+	suite := apiTestUtils.NewTestAPIServer(t)
+
+	resp, err := suite.ClientQuery.GetIdentifiersForInbox(
+		context.Background(),
+		connect.NewRequest(&message_api.GetIdentifiersForInboxRequest{
+			InboxId: testutils.RandomInboxIDString(),
+		}),
+	)
+	require.NoError(t, err)
+	require.Empty(t, resp.Msg.GetIdentifiers())
+
+	_, err = suite.ClientQuery.GetIdentifiersForInbox(
+		context.Background(),
+		connect.NewRequest(&message_api.GetIdentifiersForInboxRequest{InboxId: "not-hex"}),
+	)
+	require.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+}
+
+// TestQueryApi_GetInboxAssociationState verifies that GetInboxAssociationState returns the stored
+// association state of an inbox, and NotFound for inboxes without one.
+func TestQueryApi_GetInboxAssociationState(t *testing.T) {
+	suite := apiTestUtils.NewTestAPIServer(t)
+
+	inboxID := testutils.RandomInboxIDString()
+	state := &associations.AssociationState{InboxId: inboxID}
+
+	_, err := queries.New(suite.DB).UpsertInboxAssociationState(
+		context.Background(),
+		queries.UpsertInboxAssociationStateParams{
+			InboxID:          inboxID,
+			AssociationState: testutils.Marshal(t, state),
+			SequenceID:       7,
+		},
+	)
+	require.NoError(t, err)
+
+	resp, err := suite.ClientQuery.GetInboxAssociationState(
+		context.Background(),
+		connect.NewRequest(&message_api.GetInboxAssociationStateRequest{InboxId: inboxID}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), resp.Msg.GetSequenceId())
+	require.Equal(t, inboxID, resp.Msg.GetAssociationState().GetInboxId())
+
+	_, err = suite.ClientQuery.GetInboxAssociationState(
+		context.Background(),
+		connect.NewRequest(&message_api.GetInboxAssociationStateRequest{
+			InboxId: testutils.RandomInboxIDString(),
+		}),
+	)
+	require.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+}
+
 // TestGatewayApi_GetNodes verifies that GetNodes is reachable via the GatewayApi client
 // and returns the same response as the PayerApi client.
 func TestGatewayApi_GetNodes(t *testing.T) {
@@ -22,8 +22,10 @@ import (
 	"github.com/xmtp/xmtpd/pkg/envelopes"
 	envelopesProto "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/envelopes"
 	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/message_api"
+	"github.com/xmtp/xmtpd/pkg/ratelimiter"
 	"github.com/xmtp/xmtpd/pkg/topic"
 	"github.com/xmtp/xmtpd/pkg/utils"
+	"github.com/xmtp/xmtpd/pkg/utils/clientip"
 	"github.com/xmtp/xmtpd/pkg/utils/retryerrors"
 )
 
@@ -156,6 +158,15 @@ func (s *Service) Subscribe(
 	streamCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	// Admission only bills the opening of a stream; its deliveries are billed as they go out, so a
+	// stream that mutates in many topics cannot pull unbounded catch-up on one admission.
+	clientIP := clientip.Extract(
+		stream.Peer().Addr,
+		stream.RequestHeader().Get("X-Forwarded-For"),
+		subscribeTrustedCIDRs,
+	)
+	budget, tier := newSubscribeBudget(ctx, s.rateLimiter, s.rlConfig, clientIP)
+
 	sess := &subscribeSession{
 		svc:        s,
 		logger:     logger,
@@ -167,6 +178,9 @@ func (s *Service) Subscribe(
 		catchUpCh:  make(chan catchUpBatch, subscribeCatchUpQueueDepth),
 		topics:     make(map[string]*topicState),
 		waves:      make(map[int]*subscribeWave),
+		clientIP:   clientIP,
+		budget:     budget,
+		tier:       tier,
 	}
 	defer sess.sub.close()
 
@@ -348,6 +362,13 @@ type subscribeSession struct {
 	maxAdds int
 	// maxCursorEntries overrides maxMutateCursorEntries when > 0 (tests only).
 	maxCursorEntries int
+	// clientIP keys the caller for read authorization and rate limiting.
+	clientIP string
+	// budget charges every delivered frame to the caller's rate-limit bucket; nil when the stream's
+	// deliveries are not billed (rate limiting or the drain disabled, or a Tier 0 caller). tier is
+	// the caller's rate-limit tier, for metrics.
+	budget *ratelimiter.StreamBudget
+	tier   ratelimiter.Tier
 
 	outbound chan *message_api.SubscribeResponse
 	// senderDone is closed exactly once, when the sender goroutine exits; sendErr is its terminal
@@ -486,6 +507,9 @@ func (sess *subscribeSession) sendEnvelopes(
 		if len(frame) == 0 {
 			return nil
 		}
+		if err := sess.charge(frameBytes); err != nil {
+			return err
+		}
 		if err := sess.send(newSubscribeEnvelopes(frame, mutateID)); err != nil {
 			return err
 		}
@@ -529,6 +553,15 @@ func (sess *subscribeSession) sendEnvelopes(
 	return flush()
 }
 
+// charge bills a frame about to be delivered against the stream's budget. An empty bucket is
+// backpressure: the writer waits (at most the configured StreamMaxWait) for it to refill, during
+// which the stream delivers nothing — live batches queue in the worker, which reaps the stream as
+// too slow if the wait outlasts its buffer. A bucket that does not refill in time terminates the
+// stream with ResourceExhausted; the client reconnects from its cursors once it has budget again.
+func (sess *subscribeSession) charge(bytes int) error {
+	return chargeDelivery(sess.ctx, sess.budget, sess.tier, "Subscribe", bytes)
+}
+
 // routeLive splits a live batch: envelopes for a topic still catching up are buffered (gated) so
 // they cannot overtake that topic's history; the rest are deduped against the live cursor and sent.
 func (sess *subscribeSession) routeLive(batch []*envelopes.OriginatorEnvelope) error {
@@ -804,6 +837,7 @@ func (sess *subscribeSession) handleMutate(m *message_api.SubscribeRequest_V1_Mu
 	// way to replay/reset a topic is remove+re-add (which clears its floor first).
 	type addReq struct {
 		t        subscribeTopic
+		parsed   *topic.Topic
 		provided db.VectorClock
 	}
 	order := make([]string, 0, len(m.GetAdds()))
@@ -867,6 +901,7 @@ func (sess *subscribeSession) handleMutate(m *message_api.SubscribeRequest_V1_Mu
 				cursorKey: cursorKey,
 				listenKey: parsed.String(),
 			},
+			parsed:   parsed,
 			provided: provided,
 		}
 		order = append(order, cursorKey)
@@ -907,6 +942,18 @@ func (sess *subscribeSession) handleMutate(m *message_api.SubscribeRequest_V1_Mu
 		)
 	}
 
+	// Authorize the adds that start a catch-up; a denied topic fails the whole Mutate. No-op re-adds
+	// of already-live topics were authorized when first added.
+	if len(order) > 0 {
+		parsedAdds := make([]*topic.Topic, 0, len(order))
+		for _, k := range order {
+			parsedAdds = append(parsedAdds, byKey[k].parsed)
+		}
+		if err := sess.svc.authorizeReads(sess.ctx, sess.clientIP, parsedAdds); err != nil {
+			return err
+		}
+	}
+
 	// ---- Apply (validation passed; safe to mutate state). Removes first, then adds. ----
 	for _, parsed := range removes {
 		sess.removeTopic(parsed)
@@ -17,6 +17,7 @@ import (
 	"github.com/xmtp/xmtpd/pkg/envelopes"
 	envelopesProto "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/envelopes"
 	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/message_api"
+	"github.com/xmtp/xmtpd/pkg/ratelimiter"
 	envelopeTestUtils "github.com/xmtp/xmtpd/pkg/testutils/envelopes"
 	"github.com/xmtp/xmtpd/pkg/topic"
 )
@@ -516,6 +517,36 @@ func TestSubscribeSessionSendEnvelopesSkipsOverCapEnvelope(t *testing.T) {
 		"the over-cap envelope must be skipped; its neighbors delivered in order")
 }
 
+// TestSubscribeSessionSendEnvelopesChargesBudget covers the continual drain: every frame is billed
+// to the caller's bucket by its bytes before it is delivered, and a bucket that does not refill
+// within the wait terminates the stream with ResourceExhausted instead of delivering unbilled.
+func TestSubscribeSessionSendEnvelopesChargesBudget(t *testing.T) {
+	ctx := context.Background()
+	limiter, err := ratelimiter.NewMemoryLimiter([]ratelimiter.Limit{
+		{Capacity: 2, RefillEvery: time.Hour},
+	})
+	require.NoError(t, err)
+	sess := &subscribeSession{
+		svc: &Service{ctx: ctx}, logger: zap.NewNop(), ctx: ctx, keepAlive: time.Second,
+		outbound:      make(chan *message_api.SubscribeResponse, 16),
+		maxFrameBytes: 120, // each env below is ~52 bytes → 2 fit per frame
+		budget:        ratelimiter.NewStreamBudget(limiter, "203.0.113.1", 100, 0),
+	}
+	mkEnv := func(payload int) *envelopesProto.OriginatorEnvelope {
+		return &envelopesProto.OriginatorEnvelope{UnsignedOriginatorEnvelope: make([]byte, payload)}
+	}
+
+	// ~104 bytes per frame at 100 bytes per token: the first two frames spend the bucket.
+	require.NoError(t, sess.sendEnvelopes([]*envelopesProto.OriginatorEnvelope{
+		mkEnv(50), mkEnv(50), mkEnv(50), mkEnv(50),
+	}, 0))
+	require.Len(t, drainResponses(sess.outbound), 2)
+
+	err = sess.sendEnvelopes([]*envelopesProto.OriginatorEnvelope{mkEnv(50), mkEnv(50)}, 0)
+	require.Equal(t, connect.CodeResourceExhausted, connect.CodeOf(err))
+	require.Empty(t, drainResponses(sess.outbound), "an unbilled frame must not be delivered")
+}
+
 // TestSubscribeSessionFoldTagsAndOrders is the deterministic pin on the wave-completion fold: the
 // live envelopes buffered while the wave's topics were gated go out stamped with the wave's
 // mutate_id, merged into per-originator sequence order across topics, then TopicsLive, then
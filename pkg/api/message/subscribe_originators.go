@@ -51,6 +51,10 @@ func (s *Service) SubscribeOriginators(
 		)
 	}
 
+	if err := s.authorizeUnscopedReads(ctx); err != nil {
+		return err
+	}
+
 	// Send a keepalive immediately so wasm-based clients maintain the connection open.
 	if err := stream.Send(&message_api.SubscribeOriginatorsResponse{}); err != nil {
 		return connect.NewError(
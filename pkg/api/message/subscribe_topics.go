@@ -11,6 +11,7 @@ import (
 	"connectrpc.com/connect"
 	"github.com/cenkalti/backoff/v4"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/xmtp/xmtpd/pkg/db"
 	"github.com/xmtp/xmtpd/pkg/db/queries"
@@ -51,6 +52,18 @@ func (s *Service) SubscribeTopics(
 		return err
 	}
 
+	// Admission only bills the opening of a stream; its catch-up and live deliveries are billed
+	// as they go out.
+	budget, tier := newSubscribeBudget(ctx, s.rateLimiter, s.rlConfig, subject)
+
+	rawTopics := make([][]byte, 0, len(filters))
+	for _, f := range filters {
+		rawTopics = append(rawTopics, f.GetTopic())
+	}
+	if err := s.authorizeReads(ctx, subject, parseReadTopics(rawTopics)); err != nil {
+		return err
+	}
+
 	logger := s.logger.With(utils.MethodField(req.Spec().Procedure))
 
 	if s.logger.Core().Enabled(zap.DebugLevel) {
@@ -87,7 +100,7 @@ func (s *Service) SubscribeTopics(
 		cursor: make(map[uint32]uint64),
 	})
 
-	err = s.catchUpTopics(ctx, stream, cursors, topicKeys, logger)
+	err = s.catchUpTopics(ctx, stream, budget, tier, cursors, topicKeys, logger)
 	if err != nil {
 		return err
 	}
@@ -129,7 +142,7 @@ func (s *Service) SubscribeTopics(
 
 			// Advance cursors to filter duplicates between catch-up and live delivery.
 			envsToSend := advanceTopicCursors(cursors, envs, logger)
-			err = s.sendTopicEnvelopes(stream, envsToSend)
+			err = s.sendTopicEnvelopes(ctx, stream, budget, tier, envsToSend)
 			if err != nil {
 				return err
 			}
@@ -243,12 +256,12 @@ func subscribeSubjectFromRequest(req *connect.Request[message_api.SubscribeTopic
 }
 
 // applySubscribeAdmission charges the admission cost (ceil(sqrt(numFilters))
-// tokens) for opening a SubscribeTopics stream. It does not track the stream's
-// lifetime — continual billing of long-held streams is intentionally deferred
-// to xmtp/xmtpd#1957.
+// tokens) for opening a SubscribeTopics stream. The stream's deliveries are
+// billed as they go out, see newSubscribeBudget.
 //
-// When rate limiting is disabled (cfg.Enabled == false) or the limiter is nil
-// this is a no-op.
+// Tier 0 requests are never charged, and Tier 1 requests are charged to the
+// operator's bucket when cfg.Tier1Limiter is set. When rate limiting is disabled
+// (cfg.Enabled == false) or the limiter is nil this is a no-op.
 func applySubscribeAdmission(
 	ctx context.Context,
 	limiter ratelimiter.RateLimiter,
@@ -256,7 +269,8 @@ func applySubscribeAdmission(
 	subject string,
 	numFilters int,
 ) error {
-	if !cfg.Enabled || limiter == nil {
+	limiter, subject, _, ok := callerBucket(ctx, limiter, cfg, subject)
+	if !ok {
 		return nil
 	}
 
@@ -275,6 +289,81 @@ func applySubscribeAdmission(
 	return nil
 }
 
+// newSubscribeBudget returns the delivery budget charging a subscribe stream's
+// outbound bytes to its caller's bucket, keyed like admission. It returns nil
+// when the stream is not billed: rate limiting or the drain is disabled, or the
+// caller is Tier 0.
+func newSubscribeBudget(
+	ctx context.Context,
+	limiter ratelimiter.RateLimiter,
+	cfg RateLimitConfig,
+	subject string,
+) (*ratelimiter.StreamBudget, ratelimiter.Tier) {
+	if cfg.StreamBytesPerToken <= 0 {
+		return nil, ratelimiter.ClassifyTier(ctx)
+	}
+	limiter, subject, tier, ok := callerBucket(ctx, limiter, cfg, subject)
+	if !ok {
+		return nil, tier
+	}
+	return ratelimiter.NewStreamBudget(
+		limiter,
+		subject,
+		cfg.StreamBytesPerToken,
+		cfg.StreamMaxWait,
+	), tier
+}
+
+// chargeDelivery bills a frame of bytes that a stream of method is about to deliver against its
+// budget, which may be nil. It returns ResourceExhausted when the bucket does not refill in time,
+// and the context's error when the stream ends while waiting.
+func chargeDelivery(
+	ctx context.Context,
+	budget *ratelimiter.StreamBudget,
+	tier ratelimiter.Tier,
+	method string,
+	bytes int,
+) error {
+	if budget == nil {
+		return nil
+	}
+	err := budget.Charge(ctx, bytes)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ratelimiter.ErrStreamBudgetExhausted):
+		ratelimiter.DecisionsTotal.WithLabelValues(
+			"QueryApi", method, tier.String(), "denied",
+		).Inc()
+		return connect.NewError(
+			connect.CodeResourceExhausted,
+			fmt.Errorf("%s delivery rate limit exceeded", method),
+		)
+	default:
+		return err
+	}
+}
+
+// callerBucket resolves the limiter and subject a request is billed to: the
+// operator's bucket for Tier 1 when cfg.Tier1Limiter is set, the client IP's
+// otherwise. ok is false when the request is not billed at all.
+func callerBucket(
+	ctx context.Context,
+	limiter ratelimiter.RateLimiter,
+	cfg RateLimitConfig,
+	subject string,
+) (_ ratelimiter.RateLimiter, _ string, tier ratelimiter.Tier, ok bool) {
+	tier = ratelimiter.ClassifyTier(ctx)
+	if !cfg.Enabled || limiter == nil || tier == ratelimiter.Tier0 {
+		return nil, "", tier, false
+	}
+	if tier == ratelimiter.Tier1 && cfg.Tier1Limiter != nil {
+		operatorID, _ := ratelimiter.OperatorID(ctx)
+		return cfg.Tier1Limiter, operatorID, tier, true
+	}
+	return limiter, subject, tier, true
+}
+
 // fetchTopicEnvelopesWithRetry fetches envelopes using exponential backoff.
 func (s *Service) fetchTopicEnvelopesWithRetry(
 	ctx context.Context,
@@ -381,17 +470,25 @@ func newEnvelopesMessage(
 	}
 }
 
-// sendTopicEnvelopes sends the given envelopes to the stream.
-// No-ops if the slice is empty.
+// sendTopicEnvelopes sends the given envelopes to the stream, after charging them to the
+// stream's budget. No-ops if the slice is empty.
 func (s *Service) sendTopicEnvelopes(
+	ctx context.Context,
 	stream *connect.ServerStream[message_api.SubscribeTopicsResponse],
+	budget *ratelimiter.StreamBudget,
+	tier ratelimiter.Tier,
 	envs []*envelopesProto.OriginatorEnvelope,
 ) error {
 	if len(envs) == 0 {
 		return nil
 	}
 
-	err := stream.Send(newEnvelopesMessage(envs))
+	msg := newEnvelopesMessage(envs)
+	if err := chargeDelivery(ctx, budget, tier, "SubscribeTopics", proto.Size(msg)); err != nil {
+		return err
+	}
+
+	err := stream.Send(msg)
 	if err != nil {
 		return connect.NewError(
 			connect.CodeInternal,
@@ -408,6 +505,8 @@ func (s *Service) sendTopicEnvelopes(
 func (s *Service) catchUpTopics(
 	ctx context.Context,
 	stream *connect.ServerStream[message_api.SubscribeTopicsResponse],
+	budget *ratelimiter.StreamBudget,
+	tier ratelimiter.Tier,
 	cursors db.TopicCursors,
 	topicKeys []string,
 	logger *zap.Logger,
@@ -445,7 +544,7 @@ func (s *Service) catchUpTopics(
 
 			// Advance cursors so the next query page starts after these envelopes.
 			envsToSend := advanceTopicCursors(cursors, envs, logger)
-			err = s.sendTopicEnvelopes(stream, envsToSend)
+			err = s.sendTopicEnvelopes(ctx, stream, budget, tier, envsToSend)
 			if err != nil {
 				return err
 			}
@@ -3,8 +3,11 @@ package message
 import (
 	"context"
 	"testing"
+	"time"
 
+	"connectrpc.com/connect"
 	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/constants"
 	"github.com/xmtp/xmtpd/pkg/ratelimiter"
 )
 
@@ -64,3 +67,91 @@ func TestApplySubscribeAdmission_DenialReturnsError(t *testing.T) {
 	)
 	require.Error(t, err)
 }
+
+func TestApplySubscribeAdmission_Tier0IsNoOp(t *testing.T) {
+	limiter := &spyLimiter{}
+	ctx := context.WithValue(context.Background(), constants.VerifiedNodeRequestCtxKey{}, true)
+	err := applySubscribeAdmission(ctx, limiter, RateLimitConfig{Enabled: true}, "subj", 4)
+	require.NoError(t, err)
+	require.Empty(t, limiter.allowSubject)
+}
+
+func TestApplySubscribeAdmission_Tier1ChargesOperator(t *testing.T) {
+	tier2Limiter := &spyLimiter{}
+	tier1Limiter := &spyLimiter{}
+	ctx := context.WithValue(context.Background(), constants.VerifiedOperatorIDCtxKey{}, "acme")
+	err := applySubscribeAdmission(
+		ctx,
+		tier2Limiter,
+		RateLimitConfig{Enabled: true, Tier1Limiter: tier1Limiter},
+		"203.0.113.1",
+		4,
+	)
+	require.NoError(t, err)
+	require.Equal(t, "acme", tier1Limiter.allowSubject)
+	require.Empty(t, tier2Limiter.allowSubject)
+}
+
+func TestNewSubscribeBudget_DrainDisabledIsNil(t *testing.T) {
+	budget, _ := newSubscribeBudget(
+		context.Background(), &spyLimiter{}, RateLimitConfig{Enabled: true}, "subj",
+	)
+	require.Nil(t, budget)
+}
+
+func TestNewSubscribeBudget_Tier0IsNil(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constants.VerifiedNodeRequestCtxKey{}, true)
+	budget, tier := newSubscribeBudget(
+		ctx,
+		&spyLimiter{},
+		RateLimitConfig{Enabled: true, StreamBytesPerToken: 10},
+		"subj",
+	)
+	require.Nil(t, budget)
+	require.Equal(t, ratelimiter.Tier0, tier)
+}
+
+func TestNewSubscribeBudget_Tier1ChargesOperator(t *testing.T) {
+	tier2Limiter := &spyLimiter{}
+	tier1Limiter := &spyLimiter{}
+	ctx := context.WithValue(context.Background(), constants.VerifiedOperatorIDCtxKey{}, "acme")
+	budget, tier := newSubscribeBudget(
+		ctx,
+		tier2Limiter,
+		RateLimitConfig{Enabled: true, Tier1Limiter: tier1Limiter, StreamBytesPerToken: 10},
+		"203.0.113.1",
+	)
+	require.NotNil(t, budget)
+	require.Equal(t, ratelimiter.Tier1, tier)
+
+	require.NoError(t, budget.Charge(ctx, 25))
+	require.Equal(t, "acme", tier1Limiter.allowSubject)
+	require.Equal(t, uint64(2), tier1Limiter.allowCost)
+	require.Empty(t, tier2Limiter.allowSubject)
+}
+
+func TestChargeDelivery(t *testing.T) {
+	newBudget := func(maxWait time.Duration) *ratelimiter.StreamBudget {
+		return ratelimiter.NewStreamBudget(
+			&spyLimiter{allowResult: &ratelimiter.Result{Allowed: false}},
+			"subj",
+			10,
+			maxWait,
+		)
+	}
+
+	require.NoError(t, chargeDelivery(
+		context.Background(), nil, ratelimiter.Tier2, "SubscribeTopics", 100,
+	))
+
+	err := chargeDelivery(
+		context.Background(), newBudget(0), ratelimiter.Tier2, "SubscribeTopics", 100,
+	)
+	require.Equal(t, connect.CodeResourceExhausted, connect.CodeOf(err))
+
+	// A stream that ends while waiting for its bucket reports the cancellation.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = chargeDelivery(ctx, newBudget(time.Minute), ratelimiter.Tier2, "SubscribeTopics", 100)
+	require.ErrorIs(t, err, context.Canceled)
+}
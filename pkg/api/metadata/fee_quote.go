@@ -0,0 +1,50 @@
+package metadata
+
+import (
+	"context"
+	"time"
+
+	"github.com/xmtp/xmtpd/pkg/db"
+	"github.com/xmtp/xmtpd/pkg/fees"
+)
+
+type IFeeQuoter interface {
+	QuoteFee(
+		ctx context.Context,
+		messageTime time.Time,
+		messageSize int64,
+		retentionDays uint32,
+		originatorID uint32,
+	) (*fees.FeeQuote, error)
+}
+
+type FeeQuoter struct {
+	db            *db.Handler
+	feeCalculator fees.IFeeCalculator
+}
+
+func NewFeeQuoter(db *db.Handler, feeCalculator fees.IFeeCalculator) *FeeQuoter {
+	return &FeeQuoter{
+		db:            db,
+		feeCalculator: feeCalculator,
+	}
+}
+
+// QuoteFee quotes the fees originatorID would charge for a message published at messageTime,
+// using the congestion it has recorded for the minutes leading up to it.
+func (f *FeeQuoter) QuoteFee(
+	ctx context.Context,
+	messageTime time.Time,
+	messageSize int64,
+	retentionDays uint32,
+	originatorID uint32,
+) (*fees.FeeQuote, error) {
+	return f.feeCalculator.QuoteFee(
+		ctx,
+		f.db.ReadQuery(),
+		messageTime,
+		messageSize,
+		retentionDays,
+		originatorID,
+	)
+}
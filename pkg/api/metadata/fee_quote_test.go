@@ -0,0 +1,193 @@
+package metadata_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/api/metadata"
+	"github.com/xmtp/xmtpd/pkg/currency"
+	"github.com/xmtp/xmtpd/pkg/fees"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/metadata_api"
+	"github.com/xmtp/xmtpd/pkg/testutils"
+	testfees "github.com/xmtp/xmtpd/pkg/testutils/fees"
+	"github.com/xmtp/xmtpd/pkg/topic"
+)
+
+type staticFeeQuoter struct {
+	quote *fees.FeeQuote
+	calls int
+}
+
+func (q *staticFeeQuoter) QuoteFee(
+	_ context.Context,
+	_ time.Time,
+	_ int64,
+	_ uint32,
+	_ uint32,
+) (*fees.FeeQuote, error) {
+	q.calls++
+	return q.quote, nil
+}
+
+func newFeeQuoteService(t *testing.T, quoter metadata.IFeeQuoter) *metadata.Service {
+	svc, err := metadata.NewMetadataAPIService(
+		t.Context(),
+		testutils.NewLog(t),
+		nil,
+		nil,
+		nil,
+		quoter,
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+	return svc
+}
+
+func validFeeQuoteRequest() *metadata_api.GetFeeQuoteRequest {
+	return &metadata_api.GetFeeQuoteRequest{
+		TopicKind:        uint32(topic.TopicKindGroupMessagesV1),
+		PayloadSize:      1000,
+		RetentionDays:    30,
+		TargetOriginator: 100,
+	}
+}
+
+func TestGetFeeQuote(t *testing.T) {
+	validFrom := time.Unix(1_700_000_000, 0)
+	quoter := &staticFeeQuoter{quote: &fees.FeeQuote{
+		BaseFee:         3100,
+		StorageFee:      3000,
+		CongestionFee:   500,
+		CongestionUnits: 5,
+		Rates: &fees.RatesWindow{
+			Rates: &fees.Rates{
+				MessageFee:          100,
+				StorageFee:          1,
+				CongestionFee:       100,
+				TargetRatePerMinute: 60,
+			},
+			ValidFrom: validFrom,
+		},
+	}}
+	svc := newFeeQuoteService(t, quoter)
+
+	resp, err := svc.GetFeeQuote(t.Context(), connect.NewRequest(validFeeQuoteRequest()))
+	require.NoError(t, err)
+
+	require.Equal(t, uint64(3100), resp.Msg.GetBaseFeePicodollars())
+	require.Equal(t, uint64(3000), resp.Msg.GetStorageFeePicodollars())
+	require.Equal(t, uint64(500), resp.Msg.GetCongestionFeePicodollars())
+	require.Equal(t, uint32(5), resp.Msg.GetCongestionUnits())
+
+	rates := resp.Msg.GetRates()
+	require.Equal(t, uint64(100), rates.GetMessageFeePicodollars())
+	require.Equal(t, uint64(1), rates.GetStorageFeePicodollars())
+	require.Equal(t, uint64(100), rates.GetCongestionFeePicodollars())
+	require.Equal(t, uint64(60), rates.GetTargetRatePerMinute())
+	require.Equal(t, uint64(validFrom.Unix()), rates.GetValidFromUnixSeconds())
+	require.Zero(t, rates.GetValidToUnixSeconds())
+}
+
+func TestGetFeeQuote_InvalidRequests(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(req *metadata_api.GetFeeQuoteRequest)
+	}{
+		{
+			name: "unknown topic kind",
+			modify: func(req *metadata_api.GetFeeQuoteRequest) {
+				req.TopicKind = 42
+			},
+		},
+		{
+			name: "reserved topic kind",
+			modify: func(req *metadata_api.GetFeeQuoteRequest) {
+				req.TopicKind = uint32(topic.TopicKindPayerReportsV1)
+			},
+		},
+		{
+			name: "identity update",
+			modify: func(req *metadata_api.GetFeeQuoteRequest) {
+				req.TopicKind = uint32(topic.TopicKindIdentityUpdatesV1)
+			},
+		},
+		{
+			name: "empty payload",
+			modify: func(req *metadata_api.GetFeeQuoteRequest) {
+				req.PayloadSize = 0
+			},
+		},
+		{
+			name: "payload too large",
+			modify: func(req *metadata_api.GetFeeQuoteRequest) {
+				req.PayloadSize = math.MaxInt64
+			},
+		},
+		{
+			name: "retention too short",
+			modify: func(req *metadata_api.GetFeeQuoteRequest) {
+				req.RetentionDays = 1
+			},
+		},
+		{
+			name: "retention too long",
+			modify: func(req *metadata_api.GetFeeQuoteRequest) {
+				req.RetentionDays = 366
+			},
+		},
+		{
+			name: "missing target originator",
+			modify: func(req *metadata_api.GetFeeQuoteRequest) {
+				req.TargetOriginator = 0
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quoter := &staticFeeQuoter{}
+			svc := newFeeQuoteService(t, quoter)
+
+			req := validFeeQuoteRequest()
+			tt.modify(req)
+
+			_, err := svc.GetFeeQuote(t.Context(), connect.NewRequest(req))
+			require.Error(t, err)
+			require.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+			require.Zero(t, quoter.calls)
+		})
+	}
+}
+
+func TestGetFeeQuote_InfiniteRetention(t *testing.T) {
+	quoter := &staticFeeQuoter{quote: &fees.FeeQuote{
+		Rates: &fees.RatesWindow{Rates: &fees.Rates{}},
+	}}
+	svc := newFeeQuoteService(t, quoter)
+
+	req := validFeeQuoteRequest()
+	req.RetentionDays = math.MaxUint32
+
+	_, err := svc.GetFeeQuote(t.Context(), connect.NewRequest(req))
+	require.NoError(t, err)
+	require.Equal(t, 1, quoter.calls)
+}
+
+func TestFeeQuoter_NoCongestion(t *testing.T) {
+	database, _ := testutils.NewDB(t, t.Context())
+	quoter := metadata.NewFeeQuoter(database, testfees.NewTestFeeCalculator())
+
+	quote, err := quoter.QuoteFee(t.Context(), time.Now(), 100, 10, 100)
+	require.NoError(t, err)
+
+	// The test rates charge 100 picodollars per message and per byte-day of storage
+	require.Equal(t, currency.PicoDollar(100*100*10), quote.StorageFee)
+	require.Equal(t, currency.PicoDollar(100)+quote.StorageFee, quote.BaseFee)
+	require.Equal(t, currency.PicoDollar(0), quote.CongestionFee)
+	require.Equal(t, int32(0), quote.CongestionUnits)
+}
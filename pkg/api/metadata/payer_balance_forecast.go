@@ -0,0 +1,44 @@
+package metadata
+
+import (
+	"context"
+	"time"
+
+	"github.com/xmtp/xmtpd/pkg/payerbalance"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/metadata_api"
+)
+
+type IBalanceForecaster interface {
+	Forecast(ctx context.Context, payerID int32) (payerbalance.Forecast, error)
+}
+
+var _ IBalanceForecaster = (*payerbalance.Forecaster)(nil)
+
+func payerBalanceForecastToProto(
+	forecast payerbalance.Forecast,
+) *metadata_api.PayerBalanceForecast {
+	return &metadata_api.PayerBalanceForecast{
+		BalancePicodollars:        int64(forecast.Balance),
+		UnsettledUsagePicodollars: int64(forecast.UnsettledUsage),
+		AvailablePicodollars:      int64(forecast.Available),
+		SpendPerHourPicodollars:   int64(forecast.SpendPerHour),
+		SecondsToDepletion:        uint64(forecast.TimeToDepletion / time.Second),
+		Level:                     payerBalanceLevel(forecast.Level),
+		ComputedAtUnixSeconds:     uint64(forecast.ComputedAt.Unix()),
+	}
+}
+
+func payerBalanceLevel(level payerbalance.Level) metadata_api.PayerBalanceLevel {
+	switch level {
+	case payerbalance.LevelOK:
+		return metadata_api.PayerBalanceLevel_PAYER_BALANCE_LEVEL_OK
+	case payerbalance.LevelLow:
+		return metadata_api.PayerBalanceLevel_PAYER_BALANCE_LEVEL_LOW
+	case payerbalance.LevelCritical:
+		return metadata_api.PayerBalanceLevel_PAYER_BALANCE_LEVEL_CRITICAL
+	case payerbalance.LevelDepleted:
+		return metadata_api.PayerBalanceLevel_PAYER_BALANCE_LEVEL_DEPLETED
+	default:
+		return metadata_api.PayerBalanceLevel_PAYER_BALANCE_LEVEL_UNSPECIFIED
+	}
+}
@@ -0,0 +1,135 @@
+package metadata_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/api/metadata"
+	"github.com/xmtp/xmtpd/pkg/payerbalance"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/metadata_api"
+	"github.com/xmtp/xmtpd/pkg/testutils"
+)
+
+type stubBalanceForecaster struct {
+	forecast payerbalance.Forecast
+	err      error
+	payerIDs []int32
+}
+
+func (f *stubBalanceForecaster) Forecast(
+	_ context.Context,
+	payerID int32,
+) (payerbalance.Forecast, error) {
+	f.payerIDs = append(f.payerIDs, payerID)
+	return f.forecast, f.err
+}
+
+func newPayerBalanceForecastService(
+	t *testing.T,
+	fetcher metadata.IPayerInfoFetcher,
+	forecaster metadata.IBalanceForecaster,
+) *metadata.Service {
+	svc, err := metadata.NewMetadataAPIService(
+		t.Context(),
+		testutils.NewLog(t),
+		nil,
+		nil,
+		fetcher,
+		nil,
+		nil,
+		forecaster,
+	)
+	require.NoError(t, err)
+	return svc
+}
+
+func TestGetPayerBalanceForecast(t *testing.T) {
+	computedAt := time.Unix(1_700_000_000, 0)
+	forecaster := &stubBalanceForecaster{
+		forecast: payerbalance.Forecast{
+			PayerID:         7,
+			Balance:         1000,
+			UnsettledUsage:  600,
+			Available:       400,
+			SpendPerHour:    20,
+			TimeToDepletion: 20 * time.Hour,
+			Level:           payerbalance.LevelCritical,
+			ComputedAt:      computedAt,
+		},
+	}
+	svc := newPayerBalanceForecastService(t, &stubPayerSpendFetcher{payerID: 7}, forecaster)
+
+	resp, err := svc.GetPayerBalanceForecast(
+		t.Context(),
+		connect.NewRequest(&metadata_api.GetPayerBalanceForecastRequest{
+			PayerAddresses: []string{"0x1"},
+		}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, []int32{7}, forecaster.payerIDs)
+
+	forecast := resp.Msg.GetForecasts()["0x1"]
+	require.NotNil(t, forecast)
+	require.Equal(t, int64(1000), forecast.GetBalancePicodollars())
+	require.Equal(t, int64(600), forecast.GetUnsettledUsagePicodollars())
+	require.Equal(t, int64(400), forecast.GetAvailablePicodollars())
+	require.Equal(t, int64(20), forecast.GetSpendPerHourPicodollars())
+	require.Equal(t, uint64(72_000), forecast.GetSecondsToDepletion())
+	require.Equal(
+		t,
+		metadata_api.PayerBalanceLevel_PAYER_BALANCE_LEVEL_CRITICAL,
+		forecast.GetLevel(),
+	)
+	require.Equal(t, uint64(computedAt.Unix()), forecast.GetComputedAtUnixSeconds())
+}
+
+func TestGetPayerBalanceForecast_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        *metadata_api.GetPayerBalanceForecastRequest
+		fetcher    *stubPayerSpendFetcher
+		forecaster *stubBalanceForecaster
+		code       connect.Code
+	}{
+		{
+			name:       "missing payer addresses",
+			req:        &metadata_api.GetPayerBalanceForecastRequest{},
+			fetcher:    &stubPayerSpendFetcher{},
+			forecaster: &stubBalanceForecaster{},
+			code:       connect.CodeInvalidArgument,
+		},
+		{
+			name: "unknown payer",
+			req: &metadata_api.GetPayerBalanceForecastRequest{
+				PayerAddresses: []string{"0x1"},
+			},
+			fetcher:    &stubPayerSpendFetcher{payerErr: sql.ErrNoRows},
+			forecaster: &stubBalanceForecaster{},
+			code:       connect.CodeNotFound,
+		},
+		{
+			name: "forecast fails",
+			req: &metadata_api.GetPayerBalanceForecastRequest{
+				PayerAddresses: []string{"0x1"},
+			},
+			fetcher:    &stubPayerSpendFetcher{payerID: 7},
+			forecaster: &stubBalanceForecaster{err: errors.New("db unavailable")},
+			code:       connect.CodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newPayerBalanceForecastService(t, tt.fetcher, tt.forecaster)
+
+			_, err := svc.GetPayerBalanceForecast(t.Context(), connect.NewRequest(tt.req))
+			require.Error(t, err)
+			require.Equal(t, tt.code, connect.CodeOf(err))
+		})
+	}
+}
@@ -2,6 +2,7 @@ package metadata
 
 import (
 	"context"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/xmtp/xmtpd/pkg/db"
@@ -23,6 +24,8 @@ type IPayerInfoFetcher interface {
 		payerID int32,
 		groupBy PayerInfoGroupBy,
 	) (*metadata_api.GetPayerInfoResponse_PayerInfo, error)
+	GetPayerSpend(ctx context.Context, query PayerSpendQuery) ([]*metadata_api.PayerSpendRow, error)
+	GetPayerSpendStart(ctx context.Context, payerID int32) (time.Time, bool, error)
 }
 
 type PayerInfoFetcher struct {
@@ -71,6 +71,7 @@ func (ts *testSetup) insertMessages(t *testing.T, messages []testMessage) {
 			ts.database.DB(),
 			insertParams,
 			incrementParams,
+			0,
 			true,
 		)
 		require.NoError(t, err, msg)
@@ -0,0 +1,39 @@
+package metadata
+
+import (
+	"github.com/xmtp/xmtpd/pkg/ledger"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/metadata_api"
+)
+
+func payerLedgerEventToProto(event ledger.Event) *metadata_api.PayerLedgerEvent {
+	out := &metadata_api.PayerLedgerEvent{
+		Cursor:               uint64(event.Sequence),
+		EventId:              event.ID[:],
+		EventType:            payerLedgerEventType(event.Type),
+		AmountPicodollars:    int64(event.Amount),
+		BlockNumber:          event.BlockNumber,
+		CreatedAtUnixSeconds: uint64(event.CreatedAt.Unix()),
+	}
+	if event.ReversedEventID != nil {
+		out.ReversedEventId = event.ReversedEventID[:]
+	}
+
+	return out
+}
+
+func payerLedgerEventType(eventType ledger.EventType) metadata_api.PayerLedgerEventType {
+	switch eventType {
+	case ledger.EventTypeDeposit:
+		return metadata_api.PayerLedgerEventType_PAYER_LEDGER_EVENT_TYPE_DEPOSIT
+	case ledger.EventTypeWithdrawal:
+		return metadata_api.PayerLedgerEventType_PAYER_LEDGER_EVENT_TYPE_WITHDRAWAL
+	case ledger.EventTypeSettlement:
+		return metadata_api.PayerLedgerEventType_PAYER_LEDGER_EVENT_TYPE_SETTLEMENT
+	case ledger.EventTypeCanceledWithdrawal:
+		return metadata_api.PayerLedgerEventType_PAYER_LEDGER_EVENT_TYPE_CANCELED_WITHDRAWAL
+	case ledger.EventTypeReorgReversal:
+		return metadata_api.PayerLedgerEventType_PAYER_LEDGER_EVENT_TYPE_REORG_REVERSAL
+	default:
+		return metadata_api.PayerLedgerEventType_PAYER_LEDGER_EVENT_TYPE_UNSPECIFIED
+	}
+}
@@ -0,0 +1,209 @@
+package metadata_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/api/metadata"
+	"github.com/xmtp/xmtpd/pkg/currency"
+	"github.com/xmtp/xmtpd/pkg/ledger"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/metadata_api"
+	"github.com/xmtp/xmtpd/pkg/testutils"
+)
+
+type stubLedger struct {
+	ledger.ILedger
+	events  []ledger.Event
+	balance currency.PicoDollar
+	since   int64
+	limit   int32
+}
+
+func (l *stubLedger) ListEvents(
+	_ context.Context,
+	_ int32,
+	since int64,
+	limit int32,
+) ([]ledger.Event, error) {
+	l.since, l.limit = since, limit
+	return l.events, nil
+}
+
+func (l *stubLedger) GetBalance(_ context.Context, _ int32) (currency.PicoDollar, error) {
+	return l.balance, nil
+}
+
+func newPayerLedgerEventsService(
+	t *testing.T,
+	fetcher metadata.IPayerInfoFetcher,
+	payerLedger ledger.ILedger,
+) *metadata.Service {
+	svc, err := metadata.NewMetadataAPIService(
+		t.Context(),
+		testutils.NewLog(t),
+		nil,
+		nil,
+		fetcher,
+		nil,
+		payerLedger,
+		nil,
+	)
+	require.NoError(t, err)
+	return svc
+}
+
+func TestGetPayerLedgerEvents(t *testing.T) {
+	depositID := ledger.EventID{1}
+	settlementID := ledger.EventID{2}
+	reversalID := ledger.BuildReversalEventID(settlementID)
+	createdAt := time.Unix(1_700_000_000, 0)
+
+	payerLedger := &stubLedger{
+		events: []ledger.Event{
+			{
+				ID:          depositID,
+				Sequence:    11,
+				Type:        ledger.EventTypeDeposit,
+				Amount:      1000,
+				BlockNumber: 100,
+				CreatedAt:   createdAt,
+			},
+			{
+				ID:          settlementID,
+				Sequence:    12,
+				Type:        ledger.EventTypeSettlement,
+				Amount:      -300,
+				BlockNumber: 101,
+				CreatedAt:   createdAt,
+			},
+			{
+				ID:              reversalID,
+				Sequence:        13,
+				Type:            ledger.EventTypeReorgReversal,
+				Amount:          300,
+				BlockNumber:     101,
+				ReversedEventID: &settlementID,
+				CreatedAt:       createdAt,
+			},
+		},
+		balance: 1000,
+	}
+	svc := newPayerLedgerEventsService(t, &stubPayerSpendFetcher{payerID: 7}, payerLedger)
+
+	resp, err := svc.GetPayerLedgerEvents(
+		t.Context(),
+		connect.NewRequest(&metadata_api.GetPayerLedgerEventsRequest{
+			PayerAddress: "0x1",
+			SinceCursor:  10,
+		}),
+	)
+	require.NoError(t, err)
+
+	// The default page size is used when no limit is given
+	require.Equal(t, int64(10), payerLedger.since)
+	require.Equal(t, int32(100), payerLedger.limit)
+
+	require.Equal(t, uint64(13), resp.Msg.GetNextCursor())
+	require.Equal(t, int64(1000), resp.Msg.GetBalancePicodollars())
+
+	events := resp.Msg.GetEvents()
+	require.Len(t, events, 3)
+
+	require.Equal(t, uint64(11), events[0].GetCursor())
+	require.Equal(t, depositID[:], events[0].GetEventId())
+	require.Equal(
+		t,
+		metadata_api.PayerLedgerEventType_PAYER_LEDGER_EVENT_TYPE_DEPOSIT,
+		events[0].GetEventType(),
+	)
+	require.Equal(t, int64(1000), events[0].GetAmountPicodollars())
+	require.Equal(t, uint64(100), events[0].GetBlockNumber())
+	require.Equal(t, uint64(createdAt.Unix()), events[0].GetCreatedAtUnixSeconds())
+	require.Empty(t, events[0].GetReversedEventId())
+
+	require.Equal(
+		t,
+		metadata_api.PayerLedgerEventType_PAYER_LEDGER_EVENT_TYPE_SETTLEMENT,
+		events[1].GetEventType(),
+	)
+	require.Equal(t, int64(-300), events[1].GetAmountPicodollars())
+
+	require.Equal(
+		t,
+		metadata_api.PayerLedgerEventType_PAYER_LEDGER_EVENT_TYPE_REORG_REVERSAL,
+		events[2].GetEventType(),
+	)
+	require.Equal(t, reversalID[:], events[2].GetEventId())
+	require.Equal(t, settlementID[:], events[2].GetReversedEventId())
+	require.Equal(t, uint64(101), events[2].GetBlockNumber())
+}
+
+func TestGetPayerLedgerEvents_NoMoreEvents(t *testing.T) {
+	svc := newPayerLedgerEventsService(t, &stubPayerSpendFetcher{payerID: 7}, &stubLedger{})
+
+	resp, err := svc.GetPayerLedgerEvents(
+		t.Context(),
+		connect.NewRequest(&metadata_api.GetPayerLedgerEventsRequest{
+			PayerAddress: "0x1",
+			SinceCursor:  42,
+			Limit:        10,
+		}),
+	)
+	require.NoError(t, err)
+	require.Empty(t, resp.Msg.GetEvents())
+	require.Equal(t, uint64(42), resp.Msg.GetNextCursor())
+}
+
+func TestGetPayerLedgerEvents_InvalidRequests(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *metadata_api.GetPayerLedgerEventsRequest
+		fetcher *stubPayerSpendFetcher
+		code    connect.Code
+	}{
+		{
+			name:    "missing payer address",
+			req:     &metadata_api.GetPayerLedgerEventsRequest{},
+			fetcher: &stubPayerSpendFetcher{},
+			code:    connect.CodeInvalidArgument,
+		},
+		{
+			name: "limit too large",
+			req: &metadata_api.GetPayerLedgerEventsRequest{
+				PayerAddress: "0x1",
+				Limit:        1001,
+			},
+			fetcher: &stubPayerSpendFetcher{},
+			code:    connect.CodeInvalidArgument,
+		},
+		{
+			name: "cursor out of range",
+			req: &metadata_api.GetPayerLedgerEventsRequest{
+				PayerAddress: "0x1",
+				SinceCursor:  1 << 63,
+			},
+			fetcher: &stubPayerSpendFetcher{},
+			code:    connect.CodeInvalidArgument,
+		},
+		{
+			name:    "unknown payer",
+			req:     &metadata_api.GetPayerLedgerEventsRequest{PayerAddress: "0x1"},
+			fetcher: &stubPayerSpendFetcher{payerErr: sql.ErrNoRows},
+			code:    connect.CodeNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newPayerLedgerEventsService(t, tt.fetcher, &stubLedger{})
+
+			_, err := svc.GetPayerLedgerEvents(t.Context(), connect.NewRequest(tt.req))
+			require.Error(t, err)
+			require.Equal(t, tt.code, connect.CodeOf(err))
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package metadata
+
+import (
+	"context"
+	"time"
+
+	"github.com/xmtp/xmtpd/pkg/db/queries"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/metadata_api"
+	"github.com/xmtp/xmtpd/pkg/topic"
+	"github.com/xmtp/xmtpd/pkg/utils"
+)
+
+// PayerSpendQuery selects a payer's spend between Start (inclusive) and End (exclusive), and the
+// dimensions to break it down by.
+type PayerSpendQuery struct {
+	PayerID        int32
+	Start          time.Time
+	End            time.Time
+	GroupBy        PayerInfoGroupBy
+	ByOriginator   bool
+	ByTopicKind    bool
+	ByFeeComponent bool
+	// RowLimit caps the number of rows returned. There is no cap when it is 0.
+	RowLimit int32
+}
+
+// GetPayerSpend gets a payer's spend from its usage history, grouped by period and the
+// dimensions selected in the query. Rows are ordered by period.
+func (f *PayerInfoFetcher) GetPayerSpend(
+	ctx context.Context,
+	query PayerSpendQuery,
+) ([]*metadata_api.PayerSpendRow, error) {
+	result, err := f.db.ReadQuery().GetPayerUsageHistory(ctx, queries.GetPayerUsageHistoryParams{
+		GroupBy:                string(query.GroupBy),
+		GroupByOriginator:      query.ByOriginator,
+		GroupByTopicKind:       query.ByTopicKind,
+		PayerID:                query.PayerID,
+		StartMinutesSinceEpoch: utils.MinutesSinceEpoch(query.Start),
+		EndMinutesSinceEpoch:   minutesSinceEpochCeil(query.End),
+		RowLimit:               historyRowLimit(query),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]*metadata_api.PayerSpendRow, 0, len(result))
+	for _, row := range result {
+		spendRow := &metadata_api.PayerSpendRow{
+			PeriodStartUnixSeconds: uint64(row.TimePeriod),
+			NumMessages:            uint64(row.TotalMessageCount),
+		}
+		if query.ByOriginator {
+			spendRow.OriginatorId = uint32(row.OriginatorID)
+		}
+		if query.ByTopicKind {
+			spendRow.TopicKind = topicKindName(row.TopicKind)
+		}
+
+		if !query.ByFeeComponent {
+			spendRow.AmountSpentPicodollars = uint64(
+				row.TotalBaseFeePicodollars + row.TotalCongestionFeePicodollars,
+			)
+			rows = append(rows, spendRow)
+			continue
+		}
+
+		congestionRow := &metadata_api.PayerSpendRow{
+			PeriodStartUnixSeconds: spendRow.PeriodStartUnixSeconds,
+			OriginatorId:           spendRow.OriginatorId,
+			TopicKind:              spendRow.TopicKind,
+			FeeComponent:           metadata_api.FeeComponent_FEE_COMPONENT_CONGESTION,
+			AmountSpentPicodollars: uint64(row.TotalCongestionFeePicodollars),
+			NumMessages:            spendRow.NumMessages,
+		}
+		spendRow.FeeComponent = metadata_api.FeeComponent_FEE_COMPONENT_BASE
+		spendRow.AmountSpentPicodollars = uint64(row.TotalBaseFeePicodollars)
+
+		rows = append(rows, spendRow, congestionRow)
+	}
+
+	return rows, nil
+}
+
+// GetPayerSpendStart gets the start of the first minute a payer has usage history for.
+// It returns false if the payer has none.
+func (f *PayerInfoFetcher) GetPayerSpendStart(
+	ctx context.Context,
+	payerID int32,
+) (time.Time, bool, error) {
+	minutes, err := f.db.ReadQuery().GetPayerUsageHistoryStart(ctx, payerID)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if minutes < 0 {
+		return time.Time{}, false, nil
+	}
+
+	return time.Unix(int64(minutes)*60, 0), true, nil
+}
+
+// historyRowLimit is the number of usage history rows that make up at most query.RowLimit
+// rows of spend. A history row is split into two rows of spend by fee component.
+func historyRowLimit(query PayerSpendQuery) int32 {
+	if query.ByFeeComponent {
+		return (query.RowLimit + 1) / 2
+	}
+	return query.RowLimit
+}
+
+// minutesSinceEpochCeil rounds up to the next minute, so that an exclusive end bound that is
+// not on a minute boundary still includes the minute it falls in.
+func minutesSinceEpochCeil(t time.Time) int32 {
+	minutes := utils.MinutesSinceEpoch(t)
+	if t.After(time.Unix(int64(minutes)*60, 0)) {
+		minutes++
+	}
+	return minutes
+}
+
+// topicKindName names a topic kind from the usage history, where -1 marks an empty topic.
+func topicKindName(kind int16) string {
+	if kind < 0 {
+		return "unknown"
+	}
+	return topic.TopicKind(kind).String()
+}
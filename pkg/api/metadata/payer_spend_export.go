@@ -0,0 +1,113 @@
+package metadata
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/metadata_api"
+)
+
+var payerSpendCSVHeader = []string{
+	"period_start_unix_seconds",
+	"originator_id",
+	"topic_kind",
+	"fee_component",
+	"amount_spent_picodollars",
+	"num_messages",
+}
+
+// payerSpendExportRow is the NDJSON encoding of a PayerSpendRow. Dimensions that were not
+// grouped by are omitted.
+type payerSpendExportRow struct {
+	PeriodStartUnixSeconds uint64 `json:"period_start_unix_seconds"`
+	OriginatorID           uint32 `json:"originator_id,omitempty"`
+	TopicKind              string `json:"topic_kind,omitempty"`
+	FeeComponent           string `json:"fee_component,omitempty"`
+	AmountSpentPicodollars uint64 `json:"amount_spent_picodollars"`
+	NumMessages            uint64 `json:"num_messages"`
+}
+
+// payerSpendWriter encodes payer spend rows in an export format.
+type payerSpendWriter interface {
+	WriteRow(row *metadata_api.PayerSpendRow) error
+	// Flush writes any buffered rows to the underlying writer.
+	Flush() error
+}
+
+func newPayerSpendWriter(
+	w io.Writer,
+	format metadata_api.PayerSpendExportFormat,
+) (payerSpendWriter, error) {
+	switch format {
+	case metadata_api.PayerSpendExportFormat_PAYER_SPEND_EXPORT_FORMAT_UNSPECIFIED,
+		metadata_api.PayerSpendExportFormat_PAYER_SPEND_EXPORT_FORMAT_CSV:
+		writer := &csvPayerSpendWriter{w: csv.NewWriter(w)}
+		if err := writer.w.Write(payerSpendCSVHeader); err != nil {
+			return nil, err
+		}
+		return writer, nil
+	case metadata_api.PayerSpendExportFormat_PAYER_SPEND_EXPORT_FORMAT_NDJSON:
+		return &ndjsonPayerSpendWriter{enc: json.NewEncoder(w)}, nil
+	default:
+		return nil, fmt.Errorf("unknown export format %s", format)
+	}
+}
+
+type csvPayerSpendWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvPayerSpendWriter) WriteRow(row *metadata_api.PayerSpendRow) error {
+	originatorID := ""
+	if row.GetOriginatorId() != 0 {
+		originatorID = strconv.FormatUint(uint64(row.GetOriginatorId()), 10)
+	}
+
+	return c.w.Write([]string{
+		strconv.FormatUint(row.GetPeriodStartUnixSeconds(), 10),
+		originatorID,
+		row.GetTopicKind(),
+		feeComponentName(row.GetFeeComponent()),
+		strconv.FormatUint(row.GetAmountSpentPicodollars(), 10),
+		strconv.FormatUint(row.GetNumMessages(), 10),
+	})
+}
+
+func (c *csvPayerSpendWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type ndjsonPayerSpendWriter struct {
+	enc *json.Encoder
+}
+
+func (n *ndjsonPayerSpendWriter) WriteRow(row *metadata_api.PayerSpendRow) error {
+	// Encode writes each row followed by a newline.
+	return n.enc.Encode(payerSpendExportRow{
+		PeriodStartUnixSeconds: row.GetPeriodStartUnixSeconds(),
+		OriginatorID:           row.GetOriginatorId(),
+		TopicKind:              row.GetTopicKind(),
+		FeeComponent:           feeComponentName(row.GetFeeComponent()),
+		AmountSpentPicodollars: row.GetAmountSpentPicodollars(),
+		NumMessages:            row.GetNumMessages(),
+	})
+}
+
+func (n *ndjsonPayerSpendWriter) Flush() error {
+	return nil
+}
+
+func feeComponentName(component metadata_api.FeeComponent) string {
+	switch component {
+	case metadata_api.FeeComponent_FEE_COMPONENT_BASE:
+		return "base"
+	case metadata_api.FeeComponent_FEE_COMPONENT_CONGESTION:
+		return "congestion"
+	default:
+		return ""
+	}
+}
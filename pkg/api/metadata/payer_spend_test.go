@@ -0,0 +1,535 @@
+package metadata_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/api/metadata"
+	"github.com/xmtp/xmtpd/pkg/db"
+	"github.com/xmtp/xmtpd/pkg/db/queries"
+	"github.com/xmtp/xmtpd/pkg/db/types"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/metadata_api"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/metadata_api/metadata_apiconnect"
+	"github.com/xmtp/xmtpd/pkg/testutils"
+	"github.com/xmtp/xmtpd/pkg/topic"
+	"github.com/xmtp/xmtpd/pkg/utils"
+)
+
+type testSpend struct {
+	timestamp     time.Time
+	originatorID  int32
+	topicKind     topic.TopicKind
+	baseFee       int64
+	congestionFee int64
+}
+
+func newTestTopic(kind topic.TopicKind) []byte {
+	return topic.NewTopic(kind, testutils.RandomBytes(32)).Bytes()
+}
+
+// insertBatch inserts the envelopes the way the publish worker does.
+func (ts *testSetup) insertBatch(t *testing.T, spends []testSpend) {
+	batch := types.NewGatewayEnvelopeBatch()
+	for i, spend := range spends {
+		batch.Add(types.GatewayEnvelopeRow{
+			OriginatorNodeID:      spend.originatorID,
+			OriginatorSequenceID:  int64(utils.MinutesSinceEpoch(spend.timestamp))*100 + int64(i),
+			Topic:                 newTestTopic(spend.topicKind),
+			PayerID:               ts.payerID,
+			GatewayTime:           spend.timestamp,
+			OriginatorEnvelope:    testutils.RandomBytes(100),
+			SpendPicodollars:      spend.baseFee + spend.congestionFee,
+			CongestionPicodollars: spend.congestionFee,
+			CountUsage:            true,
+			CountCongestion:       true,
+		})
+	}
+
+	_, err := db.InsertGatewayEnvelopeBatchV2AndIncrementUnsettledUsage(
+		t.Context(),
+		ts.database.DB(),
+		testutils.NewLog(t),
+		batch,
+	)
+	require.NoError(t, err)
+}
+
+// insertSynced inserts an envelope the way the sync worker does.
+func (ts *testSetup) insertSynced(t *testing.T, sequenceID int64, spend testSpend) {
+	numInserted, err := db.InsertGatewayEnvelopeAndIncrementUnsettledUsage(
+		t.Context(),
+		ts.database.DB(),
+		queries.InsertGatewayEnvelopeV3Params{
+			OriginatorNodeID:     spend.originatorID,
+			OriginatorSequenceID: sequenceID,
+			Topic:                newTestTopic(spend.topicKind),
+			OriginatorEnvelope:   testutils.RandomBytes(100),
+			PayerID:              db.NullInt32(ts.payerID),
+		},
+		queries.IncrementUnsettledUsageParams{
+			PayerID:           ts.payerID,
+			OriginatorID:      spend.originatorID,
+			MinutesSinceEpoch: utils.MinutesSinceEpoch(spend.timestamp),
+			SpendPicodollars:  spend.baseFee + spend.congestionFee,
+		},
+		spend.congestionFee,
+		true,
+	)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), numInserted)
+}
+
+func TestPayerSpend_Breakdown(t *testing.T) {
+	setup := setupPayerInfoTest(t)
+	ctx := t.Context()
+
+	setup.insertBatch(t, []testSpend{
+		{setup.baseTime, 100, topic.TopicKindGroupMessagesV1, 100, 10},
+		{setup.baseTime.Add(time.Minute), 100, topic.TopicKindWelcomeMessagesV1, 200, 0},
+		{setup.baseTime.Add(time.Hour), 200, topic.TopicKindGroupMessagesV1, 300, 30},
+	})
+	setup.insertSynced(t, 1, testSpend{
+		setup.baseTime.Add(2 * time.Minute), 300, topic.TopicKindKeyPackagesV1, 400, 40,
+	})
+
+	query := metadata.PayerSpendQuery{
+		PayerID: setup.payerID,
+		Start:   setup.baseTime.Add(-time.Hour),
+		End:     setup.baseTime.Add(24 * time.Hour),
+		GroupBy: metadata.PayerInfoGroupByHour,
+	}
+
+	t.Run("totals", func(t *testing.T) {
+		rows, err := setup.fetcher.GetPayerSpend(ctx, query)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+
+		require.Equal(t, uint64(setup.baseTime.Unix()), rows[0].GetPeriodStartUnixSeconds())
+		require.Equal(t, uint64(100+10+200+400+40), rows[0].GetAmountSpentPicodollars())
+		require.Equal(t, uint64(3), rows[0].GetNumMessages())
+		require.Zero(t, rows[0].GetOriginatorId())
+		require.Empty(t, rows[0].GetTopicKind())
+
+		require.Equal(t, uint64(330), rows[1].GetAmountSpentPicodollars())
+		require.Equal(t, uint64(1), rows[1].GetNumMessages())
+	})
+
+	t.Run("by originator and topic kind", func(t *testing.T) {
+		query := query
+		query.GroupBy = metadata.PayerInfoGroupByDay
+		query.ByOriginator = true
+		query.ByTopicKind = true
+
+		rows, err := setup.fetcher.GetPayerSpend(ctx, query)
+		require.NoError(t, err)
+		require.Len(t, rows, 4)
+
+		expected := []struct {
+			originatorID uint32
+			topicKind    topic.TopicKind
+			amount       uint64
+		}{
+			{100, topic.TopicKindGroupMessagesV1, 110},
+			{100, topic.TopicKindWelcomeMessagesV1, 200},
+			{200, topic.TopicKindGroupMessagesV1, 330},
+			{300, topic.TopicKindKeyPackagesV1, 440},
+		}
+		for i, e := range expected {
+			require.Equal(t, e.originatorID, rows[i].GetOriginatorId())
+			require.Equal(t, e.topicKind.String(), rows[i].GetTopicKind())
+			require.Equal(t, e.amount, rows[i].GetAmountSpentPicodollars())
+			require.Equal(t, uint64(1), rows[i].GetNumMessages())
+		}
+	})
+
+	t.Run("by fee component", func(t *testing.T) {
+		query := query
+		query.GroupBy = metadata.PayerInfoGroupByDay
+		query.ByFeeComponent = true
+
+		rows, err := setup.fetcher.GetPayerSpend(ctx, query)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+
+		require.Equal(t, metadata_api.FeeComponent_FEE_COMPONENT_BASE, rows[0].GetFeeComponent())
+		require.Equal(t, uint64(100+200+300+400), rows[0].GetAmountSpentPicodollars())
+		require.Equal(t, uint64(4), rows[0].GetNumMessages())
+
+		require.Equal(
+			t,
+			metadata_api.FeeComponent_FEE_COMPONENT_CONGESTION,
+			rows[1].GetFeeComponent(),
+		)
+		require.Equal(t, uint64(10+30+40), rows[1].GetAmountSpentPicodollars())
+		require.Equal(t, uint64(4), rows[1].GetNumMessages())
+	})
+
+	t.Run("range", func(t *testing.T) {
+		query := query
+		query.Start = setup.baseTime.Add(time.Minute)
+		query.End = setup.baseTime.Add(2 * time.Minute)
+
+		rows, err := setup.fetcher.GetPayerSpend(ctx, query)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, uint64(200), rows[0].GetAmountSpentPicodollars())
+		require.Equal(t, uint64(1), rows[0].GetNumMessages())
+	})
+}
+
+func TestPayerSpend_SurvivesSettlement(t *testing.T) {
+	setup := setupPayerInfoTest(t)
+	ctx := t.Context()
+
+	setup.insertBatch(t, []testSpend{
+		{setup.baseTime, 100, topic.TopicKindGroupMessagesV1, 100, 10},
+	})
+
+	minute := utils.MinutesSinceEpoch(setup.baseTime)
+	require.NoError(t, setup.database.WriteQuery().ClearUnsettledUsage(
+		ctx,
+		queries.ClearUnsettledUsageParams{
+			OriginatorID:                  100,
+			PrevReportEndMinuteSinceEpoch: minute - 1,
+			EndMinuteSinceEpoch:           minute,
+		},
+	))
+
+	rows, err := setup.fetcher.GetPayerSpend(ctx, metadata.PayerSpendQuery{
+		PayerID: setup.payerID,
+		Start:   setup.baseTime,
+		End:     setup.baseTime.Add(time.Hour),
+		GroupBy: metadata.PayerInfoGroupByHour,
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, uint64(110), rows[0].GetAmountSpentPicodollars())
+}
+
+func TestPayerSpendStart(t *testing.T) {
+	setup := setupPayerInfoTest(t)
+	ctx := t.Context()
+
+	_, ok, err := setup.fetcher.GetPayerSpendStart(ctx, setup.payerID)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	setup.insertBatch(t, []testSpend{
+		{setup.baseTime.Add(time.Hour), 100, topic.TopicKindGroupMessagesV1, 100, 0},
+		{setup.baseTime.Add(30 * time.Second), 100, topic.TopicKindGroupMessagesV1, 100, 0},
+	})
+
+	start, ok, err := setup.fetcher.GetPayerSpendStart(ctx, setup.payerID)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, setup.baseTime.Unix(), start.Unix())
+}
+
+type stubPayerSpendFetcher struct {
+	metadata.IPayerInfoFetcher
+	payerID      int32
+	payerErr     error
+	rows         []*metadata_api.PayerSpendRow
+	historyStart time.Time
+	hasHistory   bool
+	queries      []metadata.PayerSpendQuery
+}
+
+func (f *stubPayerSpendFetcher) GetPayerByAddress(_ context.Context, _ string) (int32, error) {
+	return f.payerID, f.payerErr
+}
+
+func (f *stubPayerSpendFetcher) GetPayerSpend(
+	_ context.Context,
+	query metadata.PayerSpendQuery,
+) ([]*metadata_api.PayerSpendRow, error) {
+	f.queries = append(f.queries, query)
+	var rows []*metadata_api.PayerSpendRow
+	for _, row := range f.rows {
+		periodStart := time.Unix(int64(row.GetPeriodStartUnixSeconds()), 0)
+		if !periodStart.Before(query.Start) && periodStart.Before(query.End) {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+func (f *stubPayerSpendFetcher) GetPayerSpendStart(
+	_ context.Context,
+	_ int32,
+) (time.Time, bool, error) {
+	return f.historyStart, f.hasHistory, nil
+}
+
+func newPayerSpendClient(
+	t *testing.T,
+	fetcher metadata.IPayerInfoFetcher,
+) metadata_apiconnect.MetadataApiClient {
+	svc, err := metadata.NewMetadataAPIService(
+		t.Context(),
+		testutils.NewLog(t),
+		nil,
+		nil,
+		fetcher,
+		nil,
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+
+	_, handler := metadata_apiconnect.NewMetadataApiHandler(svc)
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	return metadata_apiconnect.NewMetadataApiClient(http.DefaultClient, srv.URL)
+}
+
+func exportPayerSpend(
+	t *testing.T,
+	client metadata_apiconnect.MetadataApiClient,
+	req *metadata_api.ExportPayerSpendRequest,
+) string {
+	stream, err := client.ExportPayerSpend(t.Context(), connect.NewRequest(req))
+	require.NoError(t, err)
+
+	var data []byte
+	for stream.Receive() {
+		data = append(data, stream.Msg().GetData()...)
+	}
+	require.NoError(t, stream.Err())
+
+	return string(data)
+}
+
+func TestGetPayerSpend_InvalidRequests(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *metadata_api.GetPayerSpendRequest
+		code connect.Code
+	}{
+		{
+			name: "missing payer address",
+			req:  &metadata_api.GetPayerSpendRequest{},
+			code: connect.CodeInvalidArgument,
+		},
+		{
+			name: "end before start",
+			req: &metadata_api.GetPayerSpendRequest{
+				PayerAddress:     "0x1",
+				StartUnixSeconds: 2000,
+				EndUnixSeconds:   1000,
+			},
+			code: connect.CodeInvalidArgument,
+		},
+		{
+			name: "unspecified dimension",
+			req: &metadata_api.GetPayerSpendRequest{
+				PayerAddress: "0x1",
+				GroupBy: []metadata_api.PayerSpendDimension{
+					metadata_api.PayerSpendDimension_PAYER_SPEND_DIMENSION_UNSPECIFIED,
+				},
+			},
+			code: connect.CodeInvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newPayerSpendClient(t, &stubPayerSpendFetcher{})
+
+			_, err := client.GetPayerSpend(t.Context(), connect.NewRequest(tt.req))
+			require.Error(t, err)
+			require.Equal(t, tt.code, connect.CodeOf(err))
+		})
+	}
+}
+
+func TestGetPayerSpend_UnknownPayer(t *testing.T) {
+	client := newPayerSpendClient(t, &stubPayerSpendFetcher{payerErr: sql.ErrNoRows})
+
+	_, err := client.GetPayerSpend(
+		t.Context(),
+		connect.NewRequest(&metadata_api.GetPayerSpendRequest{PayerAddress: "0x1"}),
+	)
+	require.Error(t, err)
+	require.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+}
+
+func TestGetPayerSpend_Query(t *testing.T) {
+	fetcher := &stubPayerSpendFetcher{
+		payerID: 7,
+		rows: []*metadata_api.PayerSpendRow{
+			{PeriodStartUnixSeconds: 3600, AmountSpentPicodollars: 100, NumMessages: 1},
+		},
+	}
+	client := newPayerSpendClient(t, fetcher)
+
+	resp, err := client.GetPayerSpend(t.Context(), connect.NewRequest(
+		&metadata_api.GetPayerSpendRequest{
+			PayerAddress:     "0x1",
+			StartUnixSeconds: 3600,
+			EndUnixSeconds:   7200,
+			Granularity:      metadata_api.PayerInfoGranularity_PAYER_INFO_GRANULARITY_DAY,
+			GroupBy: []metadata_api.PayerSpendDimension{
+				metadata_api.PayerSpendDimension_PAYER_SPEND_DIMENSION_ORIGINATOR,
+				metadata_api.PayerSpendDimension_PAYER_SPEND_DIMENSION_FEE_COMPONENT,
+			},
+		},
+	))
+	require.NoError(t, err)
+	require.Len(t, resp.Msg.GetRows(), 1)
+
+	require.Len(t, fetcher.queries, 1)
+	require.Equal(t, metadata.PayerSpendQuery{
+		PayerID:        7,
+		Start:          time.Unix(3600, 0),
+		End:            time.Unix(7200, 0),
+		GroupBy:        metadata.PayerInfoGroupByDay,
+		ByOriginator:   true,
+		ByFeeComponent: true,
+		RowLimit:       10_001,
+	}, fetcher.queries[0])
+}
+
+func TestGetPayerSpend_EndClampedToNow(t *testing.T) {
+	fetcher := &stubPayerSpendFetcher{}
+	client := newPayerSpendClient(t, fetcher)
+
+	before := time.Now()
+	_, err := client.GetPayerSpend(t.Context(), connect.NewRequest(
+		&metadata_api.GetPayerSpendRequest{
+			PayerAddress:   "0x1",
+			EndUnixSeconds: uint64(before.Add(365 * 24 * time.Hour).Unix()),
+		},
+	))
+	require.NoError(t, err)
+
+	require.Len(t, fetcher.queries, 1)
+	require.False(t, fetcher.queries[0].End.Before(before))
+	require.False(t, fetcher.queries[0].End.After(time.Now()))
+}
+
+func TestGetPayerSpend_TooManyRows(t *testing.T) {
+	rows := make([]*metadata_api.PayerSpendRow, 10_001)
+	for i := range rows {
+		rows[i] = &metadata_api.PayerSpendRow{PeriodStartUnixSeconds: 3600}
+	}
+	client := newPayerSpendClient(t, &stubPayerSpendFetcher{rows: rows})
+
+	_, err := client.GetPayerSpend(t.Context(), connect.NewRequest(
+		&metadata_api.GetPayerSpendRequest{PayerAddress: "0x1", EndUnixSeconds: 7200},
+	))
+	require.Error(t, err)
+	require.Equal(t, connect.CodeResourceExhausted, connect.CodeOf(err))
+}
+
+func TestExportPayerSpend(t *testing.T) {
+	day := uint64(24 * 60 * 60)
+	fetcher := &stubPayerSpendFetcher{
+		rows: []*metadata_api.PayerSpendRow{
+			{
+				PeriodStartUnixSeconds: day,
+				OriginatorId:           100,
+				TopicKind:              topic.TopicKindGroupMessagesV1.String(),
+				AmountSpentPicodollars: 100,
+				NumMessages:            1,
+			},
+			{
+				PeriodStartUnixSeconds: 3*day + 3600,
+				OriginatorId:           200,
+				TopicKind:              topic.TopicKindWelcomeMessagesV1.String(),
+				FeeComponent:           metadata_api.FeeComponent_FEE_COMPONENT_CONGESTION,
+				AmountSpentPicodollars: 20,
+				NumMessages:            2,
+			},
+		},
+		historyStart: time.Unix(int64(day), 0),
+		hasHistory:   true,
+	}
+	client := newPayerSpendClient(t, fetcher)
+
+	query := &metadata_api.GetPayerSpendRequest{
+		PayerAddress:   "0x1",
+		EndUnixSeconds: 5 * day,
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		fetcher.queries = nil
+
+		data := exportPayerSpend(t, client, &metadata_api.ExportPayerSpendRequest{Query: query})
+		require.Equal(
+			t,
+			"period_start_unix_seconds,originator_id,topic_kind,fee_component,"+
+				"amount_spent_picodollars,num_messages\n"+
+				"86400,100,group_messages_v1,,100,1\n"+
+				"262800,200,welcome_message_v1,congestion,20,2\n",
+			data,
+		)
+
+		// The range is clamped to the start of the payer's history and read a day at a time.
+		require.Len(t, fetcher.queries, 4)
+		for i, q := range fetcher.queries {
+			require.Equal(t, time.Unix(int64(uint64(i+1)*day), 0), q.Start)
+			require.Equal(t, time.Unix(int64(uint64(i+2)*day), 0), q.End)
+		}
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		data := exportPayerSpend(t, client, &metadata_api.ExportPayerSpendRequest{
+			Query:  query,
+			Format: metadata_api.PayerSpendExportFormat_PAYER_SPEND_EXPORT_FORMAT_NDJSON,
+		})
+		require.Equal(
+			t,
+			`{"period_start_unix_seconds":86400,"originator_id":100,`+
+				`"topic_kind":"group_messages_v1","amount_spent_picodollars":100,`+
+				`"num_messages":1}`+"\n"+
+				`{"period_start_unix_seconds":262800,"originator_id":200,`+
+				`"topic_kind":"welcome_message_v1","fee_component":"congestion",`+
+				`"amount_spent_picodollars":20,"num_messages":2}`+"\n",
+			data,
+		)
+	})
+}
+
+func TestExportPayerSpend_RangeTooLong(t *testing.T) {
+	fetcher := &stubPayerSpendFetcher{historyStart: time.Unix(0, 0), hasHistory: true}
+	client := newPayerSpendClient(t, fetcher)
+
+	stream, err := client.ExportPayerSpend(t.Context(), connect.NewRequest(
+		&metadata_api.ExportPayerSpendRequest{
+			Query: &metadata_api.GetPayerSpendRequest{
+				PayerAddress:   "0x1",
+				EndUnixSeconds: 367 * 24 * 60 * 60,
+			},
+		},
+	))
+	require.NoError(t, err)
+	for stream.Receive() {
+	}
+	require.Error(t, stream.Err())
+	require.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(stream.Err()))
+	require.Empty(t, fetcher.queries)
+}
+
+func TestExportPayerSpend_NoHistory(t *testing.T) {
+	fetcher := &stubPayerSpendFetcher{}
+	client := newPayerSpendClient(t, fetcher)
+
+	data := exportPayerSpend(t, client, &metadata_api.ExportPayerSpendRequest{
+		Query: &metadata_api.GetPayerSpendRequest{PayerAddress: "0x1"},
+	})
+	require.Equal(
+		t,
+		"period_start_unix_seconds,originator_id,topic_kind,fee_component,"+
+			"amount_spent_picodollars,num_messages\n",
+		data,
+	)
+	require.Empty(t, fetcher.queries)
+}
@@ -2,16 +2,21 @@
 package metadata
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"connectrpc.com/connect"
 	"github.com/Masterminds/semver/v3"
+	"github.com/xmtp/xmtpd/pkg/constants"
+	"github.com/xmtp/xmtpd/pkg/ledger"
 	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/metadata_api"
 	metadata_apiconnect "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/metadata_api/metadata_apiconnect"
+	"github.com/xmtp/xmtpd/pkg/topic"
 	"github.com/xmtp/xmtpd/pkg/utils"
 	"go.uber.org/zap"
 )
@@ -24,16 +29,32 @@ const (
 	// intermediary (LB, reverse proxy) with an idle timeout will terminate
 	// the HTTP/2 stream and force clients to reconnect.
 	subscribeSyncCursorKeepaliveInterval = 30 * time.Second
+
+	// maxPayerSpendRows caps the rows GetPayerSpend returns. Longer ranges must be exported.
+	maxPayerSpendRows = 10_000
+
+	// maxPayerSpendExportRange caps the range of an export, after it is clamped to the
+	// payer's history.
+	maxPayerSpendExportRange = 366 * 24 * time.Hour
+
+	// payerSpendExportChunkSize is roughly how many bytes of an export are sent per message.
+	payerSpendExportChunkSize = 64 * 1024
+
+	defaultPayerLedgerEventsLimit = 100
+	maxPayerLedgerEventsLimit     = 1000
 )
 
 type Service struct {
 	metadata_apiconnect.UnimplementedMetadataApiHandler
 
-	ctx              context.Context
-	logger           *zap.Logger
-	cu               CursorUpdater
-	version          *semver.Version
-	payerInfoFetcher IPayerInfoFetcher
+	ctx               context.Context
+	logger            *zap.Logger
+	cu                CursorUpdater
+	version           *semver.Version
+	payerInfoFetcher  IPayerInfoFetcher
+	feeQuoter         IFeeQuoter
+	ledger            ledger.ILedger
+	balanceForecaster IBalanceForecaster
 }
 
 var _ metadata_apiconnect.MetadataApiHandler = (*Service)(nil)
@@ -44,13 +65,19 @@ func NewMetadataAPIService(
 	updater CursorUpdater,
 	version *semver.Version,
 	payerInfoFetcher IPayerInfoFetcher,
+	feeQuoter IFeeQuoter,
+	payerLedger ledger.ILedger,
+	balanceForecaster IBalanceForecaster,
 ) (*Service, error) {
 	return &Service{
-		ctx:              ctx,
-		logger:           logger,
-		cu:               updater,
-		version:          version,
-		payerInfoFetcher: payerInfoFetcher,
+		ctx:               ctx,
+		logger:            logger,
+		cu:                updater,
+		version:           version,
+		payerInfoFetcher:  payerInfoFetcher,
+		feeQuoter:         feeQuoter,
+		ledger:            payerLedger,
+		balanceForecaster: balanceForecaster,
 	}, nil
 }
 
@@ -192,17 +219,7 @@ func (s *Service) GetPayerInfo(
 		)
 	}
 
-	// Map the granularity enum to the internal type
-	var groupBy PayerInfoGroupBy
-	switch req.Msg.GetGranularity() {
-	case metadata_api.PayerInfoGranularity_PAYER_INFO_GRANULARITY_HOUR:
-		groupBy = PayerInfoGroupByHour
-	case metadata_api.PayerInfoGranularity_PAYER_INFO_GRANULARITY_DAY:
-		groupBy = PayerInfoGroupByDay
-	default:
-		// Default to hour granularity if unspecified
-		groupBy = PayerInfoGroupByHour
-	}
+	groupBy := payerInfoGroupBy(req.Msg.GetGranularity())
 
 	// Initialize response
 	response := connect.NewResponse(&metadata_api.GetPayerInfoResponse{
@@ -212,23 +229,9 @@ func (s *Service) GetPayerInfo(
 	// Look up each payer address and fetch their info
 	for _, address := range req.Msg.GetPayerAddresses() {
 		// Look up payer ID from the database
-		payerID, err := s.payerInfoFetcher.GetPayerByAddress(ctx, address)
+		payerID, err := s.lookupPayer(ctx, req.Spec().Procedure, address)
 		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				return nil, connect.NewError(
-					connect.CodeNotFound,
-					fmt.Errorf("payer address not found: %s", address),
-				)
-			}
-			s.logger.Error("failed to find payer",
-				utils.MethodField(req.Spec().Procedure),
-				utils.PayerAddressField(address),
-				zap.Error(err),
-			)
-			return nil, connect.NewError(
-				connect.CodeInternal,
-				errors.New("failed to look up payer"),
-			)
+			return nil, err
 		}
 
 		// Fetch payer info from the fetcher
@@ -255,3 +258,530 @@ func (s *Service) GetPayerInfo(
 
 	return response, nil
 }
+
+func (s *Service) GetFeeQuote(
+	ctx context.Context,
+	req *connect.Request[metadata_api.GetFeeQuoteRequest],
+) (*connect.Response[metadata_api.GetFeeQuoteResponse], error) {
+	if req.Msg == nil {
+		return nil, connect.NewError(
+			connect.CodeInvalidArgument,
+			errors.New(requestMissingMessageError),
+		)
+	}
+
+	if s.logger.Core().Enabled(zap.DebugLevel) {
+		s.logger.Debug(
+			"received request",
+			utils.MethodField(req.Spec().Procedure),
+			utils.BodyField(req),
+		)
+	}
+
+	if err := validateFeeQuoteRequest(req.Msg); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	quote, err := s.feeQuoter.QuoteFee(
+		ctx,
+		time.Now(),
+		int64(req.Msg.GetPayloadSize()),
+		req.Msg.GetRetentionDays(),
+		req.Msg.GetTargetOriginator(),
+	)
+	if err != nil {
+		s.logger.Error("failed to quote fee",
+			utils.MethodField(req.Spec().Procedure),
+			utils.OriginatorIDField(req.Msg.GetTargetOriginator()),
+			zap.Error(err),
+		)
+		return nil, connect.NewError(
+			connect.CodeInternal,
+			errors.New("failed to quote fee"),
+		)
+	}
+
+	rates := &metadata_api.FeeRates{
+		MessageFeePicodollars:    uint64(quote.Rates.Rates.MessageFee),
+		StorageFeePicodollars:    uint64(quote.Rates.Rates.StorageFee),
+		CongestionFeePicodollars: uint64(quote.Rates.Rates.CongestionFee),
+		TargetRatePerMinute:      quote.Rates.Rates.TargetRatePerMinute,
+		ValidFromUnixSeconds:     uint64(quote.Rates.ValidFrom.Unix()),
+	}
+	if !quote.Rates.ValidTo.IsZero() {
+		rates.ValidToUnixSeconds = uint64(quote.Rates.ValidTo.Unix())
+	}
+
+	return connect.NewResponse(&metadata_api.GetFeeQuoteResponse{
+		BaseFeePicodollars:       uint64(quote.BaseFee),
+		StorageFeePicodollars:    uint64(quote.StorageFee),
+		CongestionFeePicodollars: uint64(quote.CongestionFee),
+		CongestionUnits:          uint32(quote.CongestionUnits),
+		Rates:                    rates,
+	}), nil
+}
+
+// validateFeeQuoteRequest applies the rules the node enforces on published envelopes,
+// so that a quote is only returned for a message that could actually be published.
+func validateFeeQuoteRequest(req *metadata_api.GetFeeQuoteRequest) error {
+	if req.GetTopicKind() > uint32(topic.TopicKindPayerReportAttestationsV1) {
+		return fmt.Errorf("unknown topic kind %d", req.GetTopicKind())
+	}
+
+	topicKind := topic.TopicKind(req.GetTopicKind())
+	if topic.NewTopic(topicKind, nil).IsReserved() {
+		return fmt.Errorf("reserved topic kind %s cannot be published to by gateways", topicKind)
+	}
+	if topicKind == topic.TopicKindIdentityUpdatesV1 {
+		return errors.New("identity updates must be published via the blockchain")
+	}
+
+	if req.GetPayloadSize() == 0 {
+		return errors.New("payload_size must be greater than 0")
+	}
+	if req.GetPayloadSize() > constants.GRPCPayloadLimit {
+		return fmt.Errorf("payload_size must be <= %d", constants.GRPCPayloadLimit)
+	}
+
+	retentionDays := req.GetRetentionDays()
+	if retentionDays < 2 {
+		return errors.New("invalid retention days. Must be >= 2")
+	}
+	if retentionDays != math.MaxUint32 && retentionDays > 365 {
+		return errors.New("invalid retention days. Must be <= 365")
+	}
+
+	if req.GetTargetOriginator() == 0 {
+		return errors.New("target_originator is required")
+	}
+
+	return nil
+}
+
+// payerInfoGroupBy maps the granularity enum to the internal type.
+func payerInfoGroupBy(granularity metadata_api.PayerInfoGranularity) PayerInfoGroupBy {
+	switch granularity {
+	case metadata_api.PayerInfoGranularity_PAYER_INFO_GRANULARITY_HOUR:
+		return PayerInfoGroupByHour
+	case metadata_api.PayerInfoGranularity_PAYER_INFO_GRANULARITY_DAY:
+		return PayerInfoGroupByDay
+	default:
+		// Default to hour granularity if unspecified
+		return PayerInfoGroupByHour
+	}
+}
+
+func (s *Service) GetPayerSpend(
+	ctx context.Context,
+	req *connect.Request[metadata_api.GetPayerSpendRequest],
+) (*connect.Response[metadata_api.GetPayerSpendResponse], error) {
+	if req.Msg == nil {
+		return nil, connect.NewError(
+			connect.CodeInvalidArgument,
+			errors.New(requestMissingMessageError),
+		)
+	}
+
+	if s.logger.Core().Enabled(zap.DebugLevel) {
+		s.logger.Debug(
+			"received request",
+			utils.MethodField(req.Spec().Procedure),
+			utils.BodyField(req),
+		)
+	}
+
+	query, err := s.payerSpendQuery(ctx, req.Spec().Procedure, req.Msg)
+	if err != nil {
+		return nil, err
+	}
+	// One row past the cap is enough to tell the range is too long.
+	query.RowLimit = maxPayerSpendRows + 1
+
+	rows, err := s.payerInfoFetcher.GetPayerSpend(ctx, query)
+	if err != nil {
+		s.logger.Error("failed to get payer spend",
+			utils.MethodField(req.Spec().Procedure),
+			utils.PayerIDField(query.PayerID),
+			zap.Error(err),
+		)
+		return nil, connect.NewError(
+			connect.CodeInternal,
+			errors.New("failed to get payer spend"),
+		)
+	}
+
+	if len(rows) > maxPayerSpendRows {
+		return nil, connect.NewError(
+			connect.CodeResourceExhausted,
+			fmt.Errorf(
+				"payer spend has more than %d rows. Narrow the range or use ExportPayerSpend",
+				maxPayerSpendRows,
+			),
+		)
+	}
+
+	return connect.NewResponse(&metadata_api.GetPayerSpendResponse{
+		Rows: rows,
+	}), nil
+}
+
+func (s *Service) ExportPayerSpend(
+	ctx context.Context,
+	req *connect.Request[metadata_api.ExportPayerSpendRequest],
+	stream *connect.ServerStream[metadata_api.ExportPayerSpendResponse],
+) error {
+	if req.Msg == nil || req.Msg.GetQuery() == nil {
+		return connect.NewError(
+			connect.CodeInvalidArgument,
+			errors.New(requestMissingMessageError),
+		)
+	}
+
+	if s.logger.Core().Enabled(zap.DebugLevel) {
+		s.logger.Debug(
+			"received request",
+			utils.MethodField(req.Spec().Procedure),
+			utils.BodyField(req),
+		)
+	}
+
+	query, err := s.payerSpendQuery(ctx, req.Spec().Procedure, req.Msg.GetQuery())
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	writer, err := newPayerSpendWriter(&buf, req.Msg.GetFormat())
+	if err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	send := func() error {
+		if err := writer.Flush(); err != nil {
+			return connect.NewError(
+				connect.CodeInternal,
+				fmt.Errorf("error encoding payer spend: %w", err),
+			)
+		}
+		if err := stream.Send(&metadata_api.ExportPayerSpendResponse{
+			Data: bytes.Clone(buf.Bytes()),
+		}); err != nil {
+			return connect.NewError(
+				connect.CodeInternal,
+				fmt.Errorf("error sending payer spend: %w", err),
+			)
+		}
+		buf.Reset()
+		return nil
+	}
+
+	// Skip the part of the range before the payer's history begins.
+	historyStart, ok, err := s.payerInfoFetcher.GetPayerSpendStart(ctx, query.PayerID)
+	if err != nil {
+		s.logger.Error("failed to get payer spend",
+			utils.MethodField(req.Spec().Procedure),
+			utils.PayerIDField(query.PayerID),
+			zap.Error(err),
+		)
+		return connect.NewError(
+			connect.CodeInternal,
+			errors.New("failed to get payer spend"),
+		)
+	}
+	if !ok {
+		return send()
+	}
+	if historyStart.After(query.Start) {
+		query.Start = historyStart
+	}
+	if query.End.Sub(query.Start) > maxPayerSpendExportRange {
+		return connect.NewError(
+			connect.CodeInvalidArgument,
+			fmt.Errorf(
+				"cannot export more than %d days of payer spend. Narrow the range",
+				int(maxPayerSpendExportRange.Hours()/24),
+			),
+		)
+	}
+
+	// Read the range a window at a time, so that the export is never held in memory.
+	for windowStart := query.Start; windowStart.Before(query.End); {
+		windowQuery := query
+		windowQuery.Start = windowStart
+		windowQuery.End = payerSpendExportWindowEnd(windowStart, query.End, query.GroupBy)
+		windowStart = windowQuery.End
+
+		rows, err := s.payerInfoFetcher.GetPayerSpend(ctx, windowQuery)
+		if err != nil {
+			s.logger.Error("failed to get payer spend",
+				utils.MethodField(req.Spec().Procedure),
+				utils.PayerIDField(query.PayerID),
+				zap.Error(err),
+			)
+			return connect.NewError(
+				connect.CodeInternal,
+				errors.New("failed to get payer spend"),
+			)
+		}
+
+		for _, row := range rows {
+			if err := writer.WriteRow(row); err != nil {
+				return connect.NewError(
+					connect.CodeInternal,
+					fmt.Errorf("error encoding payer spend: %w", err),
+				)
+			}
+		}
+
+		if buf.Len() >= payerSpendExportChunkSize {
+			if err := send(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return send()
+}
+
+func (s *Service) GetPayerLedgerEvents(
+	ctx context.Context,
+	req *connect.Request[metadata_api.GetPayerLedgerEventsRequest],
+) (*connect.Response[metadata_api.GetPayerLedgerEventsResponse], error) {
+	if req.Msg == nil {
+		return nil, connect.NewError(
+			connect.CodeInvalidArgument,
+			errors.New(requestMissingMessageError),
+		)
+	}
+
+	if s.logger.Core().Enabled(zap.DebugLevel) {
+		s.logger.Debug(
+			"received request",
+			utils.MethodField(req.Spec().Procedure),
+			utils.BodyField(req),
+		)
+	}
+
+	if req.Msg.GetPayerAddress() == "" {
+		return nil, connect.NewError(
+			connect.CodeInvalidArgument,
+			errors.New("payer_address is required"),
+		)
+	}
+
+	if req.Msg.GetSinceCursor() > math.MaxInt64 {
+		return nil, connect.NewError(
+			connect.CodeInvalidArgument,
+			errors.New("invalid since_cursor"),
+		)
+	}
+
+	limit := req.Msg.GetLimit()
+	if limit == 0 {
+		limit = defaultPayerLedgerEventsLimit
+	}
+	if limit > maxPayerLedgerEventsLimit {
+		return nil, connect.NewError(
+			connect.CodeInvalidArgument,
+			fmt.Errorf("limit must be at most %d", maxPayerLedgerEventsLimit),
+		)
+	}
+
+	payerID, err := s.lookupPayer(ctx, req.Spec().Procedure, req.Msg.GetPayerAddress())
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.ledger.ListEvents(
+		ctx,
+		payerID,
+		int64(req.Msg.GetSinceCursor()),
+		int32(limit),
+	)
+	if err != nil {
+		s.logger.Error("failed to list payer ledger events",
+			utils.MethodField(req.Spec().Procedure),
+			utils.PayerIDField(payerID),
+			zap.Error(err),
+		)
+		return nil, connect.NewError(
+			connect.CodeInternal,
+			errors.New("failed to list payer ledger events"),
+		)
+	}
+
+	balance, err := s.ledger.GetBalance(ctx, payerID)
+	if err != nil {
+		s.logger.Error("failed to get payer balance",
+			utils.MethodField(req.Spec().Procedure),
+			utils.PayerIDField(payerID),
+			zap.Error(err),
+		)
+		return nil, connect.NewError(
+			connect.CodeInternal,
+			errors.New("failed to get payer balance"),
+		)
+	}
+
+	response := &metadata_api.GetPayerLedgerEventsResponse{
+		Events:             make([]*metadata_api.PayerLedgerEvent, 0, len(events)),
+		NextCursor:         req.Msg.GetSinceCursor(),
+		BalancePicodollars: int64(balance),
+	}
+	for _, event := range events {
+		response.Events = append(response.Events, payerLedgerEventToProto(event))
+		response.NextCursor = uint64(event.Sequence)
+	}
+
+	return connect.NewResponse(response), nil
+}
+
+func (s *Service) GetPayerBalanceForecast(
+	ctx context.Context,
+	req *connect.Request[metadata_api.GetPayerBalanceForecastRequest],
+) (*connect.Response[metadata_api.GetPayerBalanceForecastResponse], error) {
+	if req.Msg == nil {
+		return nil, connect.NewError(
+			connect.CodeInvalidArgument,
+			errors.New(requestMissingMessageError),
+		)
+	}
+
+	if s.logger.Core().Enabled(zap.DebugLevel) {
+		s.logger.Debug(
+			"received request",
+			utils.MethodField(req.Spec().Procedure),
+			utils.BodyField(req),
+		)
+	}
+
+	if len(req.Msg.GetPayerAddresses()) == 0 {
+		return nil, connect.NewError(
+			connect.CodeInvalidArgument,
+			errors.New("payer_addresses cannot be empty"),
+		)
+	}
+
+	response := &metadata_api.GetPayerBalanceForecastResponse{
+		Forecasts: make(map[string]*metadata_api.PayerBalanceForecast),
+	}
+
+	for _, address := range req.Msg.GetPayerAddresses() {
+		payerID, err := s.lookupPayer(ctx, req.Spec().Procedure, address)
+		if err != nil {
+			return nil, err
+		}
+
+		forecast, err := s.balanceForecaster.Forecast(ctx, payerID)
+		if err != nil {
+			s.logger.Error("failed to forecast payer balance",
+				utils.MethodField(req.Spec().Procedure),
+				utils.PayerAddressField(address),
+				utils.PayerIDField(payerID),
+				zap.Error(err),
+			)
+			return nil, connect.NewError(
+				connect.CodeInternal,
+				fmt.Errorf("failed to forecast payer balance for address: %s", address),
+			)
+		}
+
+		response.Forecasts[address] = payerBalanceForecastToProto(forecast)
+	}
+
+	return connect.NewResponse(response), nil
+}
+
+// payerSpendQuery validates a payer spend request and resolves it to a query.
+func (s *Service) payerSpendQuery(
+	ctx context.Context,
+	procedure string,
+	req *metadata_api.GetPayerSpendRequest,
+) (PayerSpendQuery, error) {
+	if req.GetPayerAddress() == "" {
+		return PayerSpendQuery{}, connect.NewError(
+			connect.CodeInvalidArgument,
+			errors.New("payer_address is required"),
+		)
+	}
+
+	query := PayerSpendQuery{
+		Start:   time.Unix(int64(req.GetStartUnixSeconds()), 0),
+		End:     time.Now(),
+		GroupBy: payerInfoGroupBy(req.GetGranularity()),
+	}
+	// Spend is only recorded up to now, so a later end is clamped to it.
+	if end := req.GetEndUnixSeconds(); end != 0 && time.Unix(int64(end), 0).Before(query.End) {
+		query.End = time.Unix(int64(end), 0)
+	}
+	if !query.End.After(query.Start) {
+		return PayerSpendQuery{}, connect.NewError(
+			connect.CodeInvalidArgument,
+			errors.New("end_unix_seconds must be after start_unix_seconds"),
+		)
+	}
+
+	for _, dimension := range req.GetGroupBy() {
+		switch dimension {
+		case metadata_api.PayerSpendDimension_PAYER_SPEND_DIMENSION_ORIGINATOR:
+			query.ByOriginator = true
+		case metadata_api.PayerSpendDimension_PAYER_SPEND_DIMENSION_TOPIC_KIND:
+			query.ByTopicKind = true
+		case metadata_api.PayerSpendDimension_PAYER_SPEND_DIMENSION_FEE_COMPONENT:
+			query.ByFeeComponent = true
+		default:
+			return PayerSpendQuery{}, connect.NewError(
+				connect.CodeInvalidArgument,
+				fmt.Errorf("unsupported group_by dimension %s", dimension),
+			)
+		}
+	}
+
+	payerID, err := s.lookupPayer(ctx, procedure, req.GetPayerAddress())
+	if err != nil {
+		return PayerSpendQuery{}, err
+	}
+	query.PayerID = payerID
+
+	return query, nil
+}
+
+// lookupPayer resolves a payer address to its ID, returning NotFound for unknown payers.
+func (s *Service) lookupPayer(ctx context.Context, procedure, address string) (int32, error) {
+	payerID, err := s.payerInfoFetcher.GetPayerByAddress(ctx, address)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, connect.NewError(
+				connect.CodeNotFound,
+				fmt.Errorf("payer address not found: %s", address),
+			)
+		}
+		s.logger.Error("failed to find payer",
+			utils.MethodField(procedure),
+			utils.PayerAddressField(address),
+			zap.Error(err),
+		)
+		return 0, connect.NewError(
+			connect.CodeInternal,
+			errors.New("failed to look up payer"),
+		)
+	}
+
+	return payerID, nil
+}
+
+// payerSpendExportWindowEnd returns the end of the export window starting at start. Windows
+// end on day boundaries, other than the last one, so that no hour or day period is split
+// between two windows.
+func payerSpendExportWindowEnd(start, end time.Time, groupBy PayerInfoGroupBy) time.Time {
+	size := 24 * time.Hour
+	if groupBy == PayerInfoGroupByDay {
+		size = 30 * 24 * time.Hour
+	}
+
+	windowEnd := start.Truncate(size).Add(size)
+	if windowEnd.After(end) {
+		return end
+	}
+	return windowEnd
+}
@@ -0,0 +1,204 @@
+// Package misbehavior implements the misbehavior API service.
+package misbehavior
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"connectrpc.com/connect"
+	"github.com/xmtp/xmtpd/pkg/constants"
+	misbehaviorPkg "github.com/xmtp/xmtpd/pkg/misbehavior"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/message_api"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/message_api/message_apiconnect"
+	"github.com/xmtp/xmtpd/pkg/registry"
+	"github.com/xmtp/xmtpd/pkg/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	requestMissingMessageError = "missing request message"
+
+	defaultQueryLimit = 100
+	maxQueryLimit     = 1000
+)
+
+type Service struct {
+	message_apiconnect.UnimplementedMisbehaviorApiHandler
+
+	ctx          context.Context
+	logger       *zap.Logger
+	nodeRegistry registry.NodeRegistry
+	store        misbehaviorPkg.ReportStore
+}
+
+var _ message_apiconnect.MisbehaviorApiHandler = (*Service)(nil)
+
+func NewMisbehaviorAPIService(
+	ctx context.Context,
+	logger *zap.Logger,
+	nodeRegistry registry.NodeRegistry,
+	store misbehaviorPkg.ReportStore,
+) (*Service, error) {
+	if nodeRegistry == nil {
+		return nil, errors.New("node registry is nil")
+	}
+
+	if store == nil {
+		return nil, errors.New("report store is nil")
+	}
+
+	return &Service{
+		ctx:          ctx,
+		logger:       logger.Named(utils.MisbehaviorLoggerName),
+		nodeRegistry: nodeRegistry,
+		store:        store,
+	}, nil
+}
+
+// SubmitMisbehaviorReport accepts a report from another node, authenticated by its node JWT.
+// The report is validated, signed by this node and stored.
+func (s *Service) SubmitMisbehaviorReport(
+	ctx context.Context,
+	req *connect.Request[message_api.SubmitMisbehaviorReportRequest],
+) (*connect.Response[message_api.SubmitMisbehaviorReportResponse], error) {
+	if req.Msg == nil || req.Msg.GetReport() == nil {
+		return nil, connect.NewError(
+			connect.CodeInvalidArgument,
+			errors.New(requestMissingMessageError),
+		)
+	}
+
+	if s.logger.Core().Enabled(zap.DebugLevel) {
+		s.logger.Debug("received request", utils.MethodField(req.Spec().Procedure))
+	}
+
+	reporterNodeID, ok := ctx.Value(constants.VerifiedNodeIDCtxKey{}).(uint32)
+	if !ok {
+		return nil, connect.NewError(
+			connect.CodeUnauthenticated,
+			errors.New("node authentication required"),
+		)
+	}
+
+	report := req.Msg.GetReport()
+
+	if !report.GetSubmittedByNode() {
+		return nil, connect.NewError(
+			connect.CodeInvalidArgument,
+			errors.New("submitted_by_node must be set for node-submitted reports"),
+		)
+	}
+
+	if report.GetMisbehavingNodeId() == reporterNodeID {
+		return nil, connect.NewError(
+			connect.CodeInvalidArgument,
+			errors.New("nodes cannot report themselves"),
+		)
+	}
+
+	if err := misbehaviorPkg.ValidateReport(report, s.nodeRegistry); err != nil {
+		s.logger.Warn(
+			"rejected misbehavior report",
+			utils.OriginatorIDField(reporterNodeID),
+			zap.Uint32("misbehaving_node_id", report.GetMisbehavingNodeId()),
+			zap.String("misbehavior_type", report.GetType().String()),
+			zap.Error(err),
+		)
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	if _, err := s.store.StoreReport(ctx, reporterNodeID, report); err != nil {
+		s.logger.Error(
+			"failed to store misbehavior report",
+			utils.OriginatorIDField(reporterNodeID),
+			zap.Error(err),
+		)
+		return nil, connect.NewError(
+			connect.CodeInternal,
+			errors.New("failed to store misbehavior report"),
+		)
+	}
+
+	return connect.NewResponse(&message_api.SubmitMisbehaviorReportResponse{}), nil
+}
+
+// QueryMisbehaviorReports returns stored reports ordered by server time.
+// Clients page through results by passing the server_time_ns and id of the last report as
+// after_ns and after_id.
+func (s *Service) QueryMisbehaviorReports(
+	ctx context.Context,
+	req *connect.Request[message_api.QueryMisbehaviorReportsRequest],
+) (*connect.Response[message_api.QueryMisbehaviorReportsResponse], error) {
+	if req.Msg == nil {
+		return nil, connect.NewError(
+			connect.CodeInvalidArgument,
+			errors.New(requestMissingMessageError),
+		)
+	}
+
+	if s.logger.Core().Enabled(zap.DebugLevel) {
+		s.logger.Debug(
+			"received request",
+			utils.MethodField(req.Spec().Procedure),
+			utils.BodyField(req),
+		)
+	}
+
+	// Stored server times and IDs are signed 64-bit integers.
+	for _, field := range []struct {
+		name  string
+		value uint64
+	}{
+		{"after_ns", req.Msg.GetAfterNs()},
+		{"after_id", req.Msg.GetAfterId()},
+		{"before_ns", req.Msg.GetBeforeNs()},
+	} {
+		if field.value > math.MaxInt64 {
+			return nil, connect.NewError(
+				connect.CodeInvalidArgument,
+				fmt.Errorf("%s must not exceed %d", field.name, int64(math.MaxInt64)),
+			)
+		}
+	}
+
+	if req.Msg.GetBeforeNs() != 0 && req.Msg.GetBeforeNs() <= req.Msg.GetAfterNs() {
+		return nil, connect.NewError(
+			connect.CodeInvalidArgument,
+			errors.New("before_ns must be greater than after_ns"),
+		)
+	}
+
+	reports, err := s.store.QueryReports(ctx, misbehaviorPkg.QueryFilter{
+		AfterNs:           req.Msg.GetAfterNs(),
+		AfterID:           req.Msg.GetAfterId(),
+		BeforeNs:          req.Msg.GetBeforeNs(),
+		MisbehavingNodeID: req.Msg.GetMisbehavingNodeId(),
+		Type:              req.Msg.GetType(),
+		Limit:             queryLimit(req.Msg.GetLimit()),
+	})
+	if err != nil {
+		s.logger.Error("failed to query misbehavior reports", zap.Error(err))
+		return nil, connect.NewError(
+			connect.CodeInternal,
+			errors.New("failed to query misbehavior reports"),
+		)
+	}
+
+	return connect.NewResponse(&message_api.QueryMisbehaviorReportsResponse{
+		Reports: reports,
+	}), nil
+}
+
+func queryLimit(requested uint32) int32 {
+	if requested == 0 {
+		return defaultQueryLimit
+	}
+
+	if requested > maxQueryLimit {
+		return maxQueryLimit
+	}
+
+	return int32(requested)
+}
@@ -0,0 +1,202 @@
+package misbehavior_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/require"
+	misbehaviorAPI "github.com/xmtp/xmtpd/pkg/api/misbehavior"
+	"github.com/xmtp/xmtpd/pkg/constants"
+	"github.com/xmtp/xmtpd/pkg/misbehavior"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/envelopes"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/message_api"
+	r "github.com/xmtp/xmtpd/pkg/registry"
+	"github.com/xmtp/xmtpd/pkg/testutils"
+	registryTestUtils "github.com/xmtp/xmtpd/pkg/testutils/registry"
+)
+
+const (
+	reporterNodeID    = uint32(100)
+	misbehavingNodeID = uint32(200)
+)
+
+type fakeReportStore struct {
+	stored []*message_api.UnsignedMisbehaviorReport
+	filter misbehavior.QueryFilter
+}
+
+func (f *fakeReportStore) StoreReport(
+	_ context.Context,
+	_ uint32,
+	report *message_api.UnsignedMisbehaviorReport,
+) (*message_api.MisbehaviorReport, error) {
+	f.stored = append(f.stored, report)
+	return &message_api.MisbehaviorReport{}, nil
+}
+
+func (f *fakeReportStore) QueryReports(
+	_ context.Context,
+	filter misbehavior.QueryFilter,
+) ([]*message_api.MisbehaviorReport, error) {
+	f.filter = filter
+	return []*message_api.MisbehaviorReport{}, nil
+}
+
+func setupService(t *testing.T) (*misbehaviorAPI.Service, *fakeReportStore) {
+	nodeRegistry := registryTestUtils.CreateMockRegistry(t, []r.Node{
+		registryTestUtils.CreateNode(misbehavingNodeID, 5050, testutils.RandomPrivateKey(t)),
+	})
+	store := &fakeReportStore{}
+
+	svc, err := misbehaviorAPI.NewMisbehaviorAPIService(
+		t.Context(),
+		testutils.NewLog(t),
+		nodeRegistry,
+		store,
+	)
+	require.NoError(t, err)
+
+	return svc, store
+}
+
+func livenessReport(misbehavingNodeID uint32) *message_api.UnsignedMisbehaviorReport {
+	return &message_api.UnsignedMisbehaviorReport{
+		ReporterTimeNs:    uint64(time.Now().UnixNano()),
+		MisbehavingNodeId: misbehavingNodeID,
+		Type:              message_api.Misbehavior_MISBEHAVIOR_UNRESPONSIVE_NODE,
+		Failure: &message_api.UnsignedMisbehaviorReport_Liveness{
+			Liveness: &message_api.LivenessFailure{ResponseTimeNs: 1000},
+		},
+		SubmittedByNode: true,
+	}
+}
+
+func nodeContext(t *testing.T, nodeID uint32) context.Context {
+	return context.WithValue(t.Context(), constants.VerifiedNodeIDCtxKey{}, nodeID)
+}
+
+func TestSubmitMisbehaviorReport(t *testing.T) {
+	svc, store := setupService(t)
+
+	_, err := svc.SubmitMisbehaviorReport(
+		nodeContext(t, reporterNodeID),
+		connect.NewRequest(&message_api.SubmitMisbehaviorReportRequest{
+			Report: livenessReport(misbehavingNodeID),
+		}),
+	)
+	require.NoError(t, err)
+	require.Len(t, store.stored, 1)
+}
+
+func TestSubmitMisbehaviorReportRequiresNodeAuth(t *testing.T) {
+	svc, store := setupService(t)
+
+	_, err := svc.SubmitMisbehaviorReport(
+		t.Context(),
+		connect.NewRequest(&message_api.SubmitMisbehaviorReportRequest{
+			Report: livenessReport(misbehavingNodeID),
+		}),
+	)
+	require.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+	require.Empty(t, store.stored)
+}
+
+func TestSubmitMisbehaviorReportInvalid(t *testing.T) {
+	svc, store := setupService(t)
+
+	notSubmittedByNode := livenessReport(misbehavingNodeID)
+	notSubmittedByNode.SubmittedByNode = false
+
+	missingFailure := livenessReport(misbehavingNodeID)
+	missingFailure.Failure = nil
+
+	oversizedEvidence := &message_api.UnsignedMisbehaviorReport{
+		ReporterTimeNs:    uint64(time.Now().UnixNano()),
+		MisbehavingNodeId: misbehavingNodeID,
+		Type:              message_api.Misbehavior_MISBEHAVIOR_OUT_OF_ORDER,
+		Failure: &message_api.UnsignedMisbehaviorReport_Safety{
+			Safety: &message_api.SafetyFailure{
+				Envelopes: []*envelopes.OriginatorEnvelope{{
+					UnsignedOriginatorEnvelope: make([]byte, misbehavior.MaxEvidenceBytes),
+				}},
+			},
+		},
+		SubmittedByNode: true,
+	}
+
+	tests := []struct {
+		name   string
+		report *message_api.UnsignedMisbehaviorReport
+	}{
+		{name: "missing report", report: nil},
+		{name: "not submitted by node", report: notSubmittedByNode},
+		{name: "self report", report: livenessReport(reporterNodeID)},
+		{name: "missing liveness failure", report: missingFailure},
+		{name: "oversized evidence", report: oversizedEvidence},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.SubmitMisbehaviorReport(
+				nodeContext(t, reporterNodeID),
+				connect.NewRequest(&message_api.SubmitMisbehaviorReportRequest{
+					Report: tt.report,
+				}),
+			)
+			require.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+		})
+	}
+
+	require.Empty(t, store.stored)
+}
+
+func TestQueryMisbehaviorReports(t *testing.T) {
+	svc, store := setupService(t)
+
+	_, err := svc.QueryMisbehaviorReports(
+		t.Context(),
+		connect.NewRequest(&message_api.QueryMisbehaviorReportsRequest{
+			AfterNs:           10,
+			AfterId:           3,
+			BeforeNs:          20,
+			MisbehavingNodeId: misbehavingNodeID,
+			Type:              message_api.Misbehavior_MISBEHAVIOR_OUT_OF_ORDER,
+		}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, misbehavior.QueryFilter{
+		AfterNs:           10,
+		AfterID:           3,
+		BeforeNs:          20,
+		MisbehavingNodeID: misbehavingNodeID,
+		Type:              message_api.Misbehavior_MISBEHAVIOR_OUT_OF_ORDER,
+		Limit:             100,
+	}, store.filter)
+
+	_, err = svc.QueryMisbehaviorReports(
+		t.Context(),
+		connect.NewRequest(&message_api.QueryMisbehaviorReportsRequest{Limit: 1_000_000}),
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, store.filter.Limit)
+
+	_, err = svc.QueryMisbehaviorReports(
+		t.Context(),
+		connect.NewRequest(&message_api.QueryMisbehaviorReportsRequest{
+			AfterNs:  20,
+			BeforeNs: 10,
+		}),
+	)
+	require.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+
+	_, err = svc.QueryMisbehaviorReports(
+		t.Context(),
+		connect.NewRequest(&message_api.QueryMisbehaviorReportsRequest{
+			AfterNs: math.MaxUint64,
+		}),
+	)
+	require.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+}
@@ -0,0 +1,409 @@
+package payer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/ethereum/go-ethereum/common"
+	gm "github.com/xmtp/xmtpd/pkg/abi/groupmessagebroadcaster"
+	iu "github.com/xmtp/xmtpd/pkg/abi/identityupdatebroadcaster"
+	"github.com/xmtp/xmtpd/pkg/blockchain"
+	"github.com/xmtp/xmtpd/pkg/constants"
+	"github.com/xmtp/xmtpd/pkg/envelopes"
+	"github.com/xmtp/xmtpd/pkg/metrics"
+	envelopesProto "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/envelopes"
+	"github.com/xmtp/xmtpd/pkg/topic"
+	"github.com/xmtp/xmtpd/pkg/tracing"
+	"github.com/xmtp/xmtpd/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// batchedEnvelope is a blockchain-bound envelope waiting for its batch to be published.
+type batchedEnvelope struct {
+	payload *envelopes.ClientEnvelope
+	result  chan batchResult
+}
+
+type batchResult struct {
+	envelope *envelopesProto.OriginatorEnvelope
+	err      error
+}
+
+// resolve delivers the outcome of publishing the envelope to its caller.
+func (e *batchedEnvelope) resolve(envelope *envelopesProto.OriginatorEnvelope, err error) {
+	e.result <- batchResult{envelope: envelope, err: err}
+}
+
+type pendingBatch struct {
+	envelopes []*batchedEnvelope
+}
+
+// publishBatchFn publishes a batch of envelopes of the same topic kind in order, resolving
+// every envelope of the batch.
+type publishBatchFn func(ctx context.Context, kind topic.TopicKind, batch []*batchedEnvelope)
+
+// publishQueue holds the batches of a topic kind waiting to be published.
+type publishQueue struct {
+	batches [][]*batchedEnvelope
+	notify  chan struct{}
+}
+
+// blockchainBatcher coalesces the blockchain-bound envelopes of concurrent requests into
+// batches, one per topic kind. A batch is published once it has been open for window, or
+// as soon as it holds maxSize envelopes.
+//
+// The envelopes of a request are never split across batches, and the batches of a topic
+// kind are published one at a time, in the order they were closed, by a single publisher.
+// Envelopes are therefore published in request order, and batch transactions never
+// compete for nonces. A request holding more than maxSize envelopes gets a batch of its
+// own.
+//
+// Batches are published with the service context rather than the context of any of the
+// requests they hold, so a caller canceling its request does not fail the others. Its
+// envelopes may still be published.
+type blockchainBatcher struct {
+	ctx     context.Context
+	wg      *sync.WaitGroup
+	window  time.Duration
+	maxSize int
+	publish publishBatchFn
+
+	mu      sync.Mutex
+	pending map[topic.TopicKind]*pendingBatch
+	queues  map[topic.TopicKind]*publishQueue
+}
+
+func newBlockchainBatcher(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	window time.Duration,
+	maxSize int,
+	publish publishBatchFn,
+) *blockchainBatcher {
+	return &blockchainBatcher{
+		ctx:     ctx,
+		wg:      wg,
+		window:  window,
+		maxSize: max(maxSize, 1),
+		publish: publish,
+		pending: make(map[topic.TopicKind]*pendingBatch),
+		queues:  make(map[topic.TopicKind]*publishQueue),
+	}
+}
+
+// submit adds payloads to the open batches of their topic kinds, and waits for the
+// batches to be published. The originator envelopes and errors are returned in the order
+// of payloads.
+func (b *blockchainBatcher) submit(
+	ctx context.Context,
+	payloads []*envelopes.ClientEnvelope,
+) ([]*envelopesProto.OriginatorEnvelope, []error) {
+	var (
+		batched = make([]*batchedEnvelope, len(payloads))
+		byKind  = make(map[topic.TopicKind][]*batchedEnvelope)
+	)
+	for i, payload := range payloads {
+		batched[i] = &batchedEnvelope{payload: payload, result: make(chan batchResult, 1)}
+		kind := payload.TargetTopic().Kind()
+		byKind[kind] = append(byKind[kind], batched[i])
+	}
+
+	for kind, kindEnvelopes := range byKind {
+		b.add(kind, kindEnvelopes)
+	}
+
+	var (
+		out  = make([]*envelopesProto.OriginatorEnvelope, len(batched))
+		errs = make([]error, len(batched))
+	)
+	for i, envelope := range batched {
+		select {
+		case res := <-envelope.result:
+			out[i], errs[i] = res.envelope, res.err
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+		}
+	}
+
+	return out, errs
+}
+
+// add appends items to the open batch of kind, first publishing the batch if they do not
+// fit in it.
+func (b *blockchainBatcher) add(kind topic.TopicKind, items []*batchedEnvelope) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	batch, ok := b.pending[kind]
+	if ok && len(batch.envelopes)+len(items) > b.maxSize {
+		b.flushLocked(kind, batch)
+		ok = false
+	}
+	if !ok {
+		batch = &pendingBatch{}
+		b.pending[kind] = batch
+		time.AfterFunc(b.window, func() {
+			b.flush(kind, batch)
+		})
+	}
+
+	batch.envelopes = append(batch.envelopes, items...)
+	if len(batch.envelopes) >= b.maxSize {
+		b.flushLocked(kind, batch)
+	}
+}
+
+// flush publishes batch, unless it was already published.
+func (b *blockchainBatcher) flush(kind topic.TopicKind, batch *pendingBatch) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.pending[kind] == batch {
+		b.flushLocked(kind, batch)
+	}
+}
+
+// flushLocked queues batch for the publisher of kind, starting the publisher on the first
+// batch of kind.
+func (b *blockchainBatcher) flushLocked(kind topic.TopicKind, batch *pendingBatch) {
+	delete(b.pending, kind)
+
+	if b.ctx.Err() != nil {
+		resolveAll(batch.envelopes, b.ctx.Err())
+		return
+	}
+
+	queue, ok := b.queues[kind]
+	if !ok {
+		queue = &publishQueue{notify: make(chan struct{}, 1)}
+		b.queues[kind] = queue
+		tracing.GoPanicWrap(
+			b.ctx,
+			b.wg,
+			"payer-blockchain-batch-publisher-"+kind.String(),
+			func(ctx context.Context) {
+				b.runPublisher(ctx, kind, queue)
+			},
+		)
+	}
+
+	queue.batches = append(queue.batches, batch.envelopes)
+	select {
+	case queue.notify <- struct{}{}:
+	default:
+	}
+}
+
+// runPublisher publishes the queued batches of kind one at a time until ctx is done. The
+// envelopes of batches still queued then are failed with the context error.
+func (b *blockchainBatcher) runPublisher(
+	ctx context.Context,
+	kind topic.TopicKind,
+	queue *publishQueue,
+) {
+	for {
+		select {
+		case <-ctx.Done():
+			b.mu.Lock()
+			batches := queue.batches
+			queue.batches = nil
+			b.mu.Unlock()
+
+			for _, batch := range batches {
+				resolveAll(batch, ctx.Err())
+			}
+			return
+		case <-queue.notify:
+		}
+
+		for {
+			b.mu.Lock()
+			if len(queue.batches) == 0 {
+				b.mu.Unlock()
+				break
+			}
+			batch := queue.batches[0]
+			queue.batches = queue.batches[1:]
+			b.mu.Unlock()
+
+			b.publish(ctx, kind, batch)
+		}
+	}
+}
+
+func resolveAll(batch []*batchedEnvelope, err error) {
+	for _, envelope := range batch {
+		envelope.resolve(nil, err)
+	}
+}
+
+// publishBatchToBlockchain publishes a batch of envelopes of the same topic kind in a
+// single transaction. If the batch transaction would revert, the envelopes are published
+// one by one instead, so that an invalid envelope does not fail the others.
+func (s *Service) publishBatchToBlockchain(
+	ctx context.Context,
+	kind topic.TopicKind,
+	batch []*batchedEnvelope,
+) {
+	if len(batch) == 1 {
+		batch[0].resolve(s.publishToBlockchain(ctx, batch[0].payload))
+		return
+	}
+
+	originatorEnvelopes, err := s.publishBatch(ctx, kind, batch)
+	if errors.Is(err, blockchain.ErrBatchReverted) {
+		s.logger.Warn(
+			"batch transaction would revert, publishing envelopes one by one",
+			utils.NumEnvelopesField(len(batch)),
+			zap.Error(err),
+		)
+		for _, envelope := range batch {
+			envelope.resolve(s.publishToBlockchain(ctx, envelope.payload))
+		}
+		return
+	}
+
+	for i, envelope := range batch {
+		if err != nil {
+			envelope.resolve(nil, err)
+		} else {
+			envelope.resolve(originatorEnvelopes[i], nil)
+		}
+	}
+}
+
+func (s *Service) publishBatch(
+	ctx context.Context,
+	kind topic.TopicKind,
+	batch []*batchedEnvelope,
+) ([]*envelopesProto.OriginatorEnvelope, error) {
+	var (
+		start        = time.Now()
+		originatorID uint32
+		hash         common.Hash
+		sequenceIDs  = make([]uint64, len(batch))
+		payloads     = make([][]byte, len(batch))
+	)
+
+	for i, envelope := range batch {
+		payload, err := envelope.payload.Bytes()
+		if err != nil {
+			return nil, connect.NewError(
+				connect.CodeInternal,
+				fmt.Errorf("error getting client envelope bytes: %w", err),
+			)
+		}
+		payloads[i] = payload
+	}
+
+	switch kind {
+	case topic.TopicKindGroupMessagesV1:
+		originatorID = constants.GroupMessageOriginatorID
+
+		groupIDs := make([][16]byte, len(batch))
+		for i, envelope := range batch {
+			groupID, err := utils.ParseGroupID(envelope.payload.TargetTopic().Identifier())
+			if err != nil {
+				return nil, connect.NewError(
+					connect.CodeInternal,
+					fmt.Errorf("error converting identifier to group ID: %w", err),
+				)
+			}
+			groupIDs[i] = groupID
+		}
+
+		logMessages, err := metrics.MeasurePublishToBlockchainMethod(
+			"group_message_batch",
+			func() ([]*gm.GroupMessageBroadcasterMessageSent, error) {
+				return s.blockchainPublisher.PublishGroupMessages(ctx, groupIDs, payloads)
+			},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error publishing group messages: %w", err)
+		}
+		if len(logMessages) != len(batch) {
+			return nil, connect.NewError(
+				connect.CodeInternal,
+				fmt.Errorf("received %d logMessages for %d envelopes", len(logMessages), len(batch)),
+			)
+		}
+
+		hash = logMessages[0].Raw.TxHash
+		for i, logMessage := range logMessages {
+			sequenceIDs[i] = logMessage.SequenceId
+		}
+
+	case topic.TopicKindIdentityUpdatesV1:
+		originatorID = constants.IdentityUpdateOriginatorID
+
+		inboxIDs := make([][32]byte, len(batch))
+		for i, envelope := range batch {
+			inboxID, err := utils.ParseInboxID(envelope.payload.TargetTopic().Identifier())
+			if err != nil {
+				return nil, connect.NewError(
+					connect.CodeInternal,
+					fmt.Errorf("error converting identifier to inbox ID: %w", err),
+				)
+			}
+			inboxIDs[i] = inboxID
+		}
+
+		logMessages, err := metrics.MeasurePublishToBlockchainMethod(
+			"identity_update_batch",
+			func() ([]*iu.IdentityUpdateBroadcasterIdentityUpdateCreated, error) {
+				return s.blockchainPublisher.PublishIdentityUpdates(ctx, inboxIDs, payloads)
+			},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error publishing identity updates: %w", err)
+		}
+		if len(logMessages) != len(batch) {
+			return nil, connect.NewError(
+				connect.CodeInternal,
+				fmt.Errorf("received %d logMessages for %d envelopes", len(logMessages), len(batch)),
+			)
+		}
+
+		hash = logMessages[0].Raw.TxHash
+		for i, logMessage := range logMessages {
+			sequenceIDs[i] = logMessage.SequenceId
+		}
+
+	default:
+		return nil, connect.NewError(
+			connect.CodeInvalidArgument,
+			fmt.Errorf("unknown blockchain message for topic kind %s", kind),
+		)
+	}
+
+	originatorEnvelopes := make([]*envelopesProto.OriginatorEnvelope, len(batch))
+	for i := range batch {
+		originatorEnvelope, err := newBlockchainOriginatorEnvelope(
+			originatorID,
+			sequenceIDs[i],
+			payloads[i],
+			hash,
+		)
+		if err != nil {
+			return nil, err
+		}
+		originatorEnvelopes[i] = originatorEnvelope
+	}
+
+	s.logger.Debug(
+		"published batch to blockchain",
+		utils.DurationMsField(time.Since(start)),
+		utils.NumEnvelopesField(len(batch)),
+		utils.HashField(hash.String()),
+	)
+
+	metrics.EmitGatewayPublishDuration(originatorID, time.Since(start).Seconds())
+	metrics.EmitGatewayMessageOriginated(originatorID, len(batch))
+	metrics.EmitGatewayBlockchainBatchSize(originatorID, len(batch))
+
+	return originatorEnvelopes, nil
+}
@@ -7,6 +7,7 @@ import (
 
 	grpcprom "github.com/grpc-ecosystem/go-grpc-middleware/providers/prometheus"
 	"github.com/xmtp/xmtpd/pkg/registry"
+	"github.com/xmtp/xmtpd/pkg/tracing"
 	"github.com/xmtp/xmtpd/pkg/utils"
 	"go.uber.org/zap"
 )
@@ -73,8 +74,14 @@ func (c *ClientManager) newClientConnection(
 	}
 
 	conn, err := node.BuildConn(
-		grpc.WithUnaryInterceptor(c.clientMetrics.UnaryClientInterceptor()),
-		grpc.WithStreamInterceptor(c.clientMetrics.StreamClientInterceptor()),
+		grpc.WithChainUnaryInterceptor(
+			tracing.UnaryClientInterceptor(),
+			c.clientMetrics.UnaryClientInterceptor(),
+		),
+		grpc.WithChainStreamInterceptor(
+			tracing.StreamClientInterceptor(),
+			c.clientMetrics.StreamClientInterceptor(),
+		),
 	)
 	if err != nil {
 		return nil, err
@@ -2,21 +2,42 @@ package payer
 
 import (
 	"time"
+
+	"github.com/xmtp/xmtpd/pkg/api/payer/outbox"
+	"github.com/xmtp/xmtpd/pkg/misbehavior"
 )
 
 const (
-	defaultPublishTimeout = 30 * time.Second
-	defaultPublishRetries = 5
+	defaultPublishTimeout     = 30 * time.Second
+	defaultPublishRetries     = 5
+	defaultPublishParallelism = 8
 )
 
 type Config struct {
 	PublishTimeout time.Duration
 	PublishRetries uint
+	// PublishParallelism is the number of destinations the envelopes of a request are
+	// published to concurrently.
+	PublishParallelism int
+	LivenessTracker    *misbehavior.LivenessTracker
+	// BlockchainBatchWindow is how long blockchain-bound envelopes are coalesced into a
+	// batch transaction sent through Multicall3, so the broadcasters see Multicall3 as the
+	// sender. Batching is disabled when it is 0.
+	BlockchainBatchWindow  time.Duration
+	BlockchainBatchMaxSize int
+	// Outbox persists envelopes until they are published. It is disabled when nil.
+	Outbox outbox.Outbox
+	// OutboxReplayInterval is how often envelopes left pending in the outbox are published.
+	OutboxReplayInterval time.Duration
+	// OutboxMaxAttempts is the number of attempts after which an envelope replayed from the
+	// outbox fails.
+	OutboxMaxAttempts int
 }
 
 var defaultConfig = Config{
-	PublishTimeout: defaultPublishTimeout,
-	PublishRetries: defaultPublishRetries,
+	PublishTimeout:     defaultPublishTimeout,
+	PublishRetries:     defaultPublishRetries,
+	PublishParallelism: defaultPublishParallelism,
 }
 
 type Option func(*Config)
@@ -32,3 +53,39 @@ func WithPublishRetries(n uint) Option {
 		cfg.PublishRetries = n
 	}
 }
+
+// WithPublishParallelism sets the number of destinations the envelopes of a request are
+// published to concurrently.
+func WithPublishParallelism(n int) Option {
+	return func(cfg *Config) {
+		cfg.PublishParallelism = n
+	}
+}
+
+// WithLivenessTracker sets the tracker that publish outcomes are reported to.
+// Defaults to a tracker that logs liveness failures.
+func WithLivenessTracker(tracker *misbehavior.LivenessTracker) Option {
+	return func(cfg *Config) {
+		cfg.LivenessTracker = tracker
+	}
+}
+
+// WithBlockchainBatching coalesces the blockchain-bound envelopes published within window
+// into batch transactions of at most maxSize envelopes.
+func WithBlockchainBatching(window time.Duration, maxSize int) Option {
+	return func(cfg *Config) {
+		cfg.BlockchainBatchWindow = window
+		cfg.BlockchainBatchMaxSize = maxSize
+	}
+}
+
+// WithOutbox persists envelopes in ob until they are published. Envelopes left pending,
+// such as the envelopes of async requests, are published every replayInterval, and fail
+// after maxAttempts.
+func WithOutbox(ob outbox.Outbox, replayInterval time.Duration, maxAttempts int) Option {
+	return func(cfg *Config) {
+		cfg.Outbox = ob
+		cfg.OutboxReplayInterval = replayInterval
+		cfg.OutboxMaxAttempts = maxAttempts
+	}
+}
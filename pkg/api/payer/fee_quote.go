@@ -0,0 +1,65 @@
+package payer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/metadata_api"
+	"github.com/xmtp/xmtpd/pkg/utils"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/status"
+)
+
+// GetFeeQuote asks the target originator what it would currently charge to publish an envelope.
+// Quotes are not cached, as the congestion fee changes from minute to minute.
+func (s *Service) GetFeeQuote(
+	ctx context.Context,
+	req *connect.Request[metadata_api.GetFeeQuoteRequest],
+) (*connect.Response[metadata_api.GetFeeQuoteResponse], error) {
+	if req.Msg == nil {
+		return nil, connect.NewError(
+			connect.CodeInvalidArgument,
+			errors.New(requestMissingMessageError),
+		)
+	}
+
+	if s.logger.Core().Enabled(zap.DebugLevel) {
+		s.logger.Debug(
+			"received request",
+			utils.MethodField(req.Spec().Procedure),
+			utils.BodyField(req),
+		)
+	}
+
+	originatorID := req.Msg.GetTargetOriginator()
+	if originatorID == 0 {
+		return nil, connect.NewError(
+			connect.CodeInvalidArgument,
+			errors.New("target_originator is required"),
+		)
+	}
+
+	conn, err := s.clientManager.GetClientConnection(originatorID)
+	if err != nil {
+		s.logger.Error("error getting client", zap.Error(err))
+		return nil, connect.NewError(
+			connect.CodeInternal,
+			fmt.Errorf("error getting client: %w", err),
+		)
+	}
+
+	client := metadata_api.NewMetadataApiClient(conn)
+
+	response, err := client.GetFeeQuote(ctx, req.Msg)
+	if err != nil {
+		// Preserve the node's status code so that invalid requests are reported as such.
+		return nil, connect.NewError(
+			connect.Code(status.Code(err)),
+			fmt.Errorf("error getting fee quote from node %d: %w", originatorID, err),
+		)
+	}
+
+	return connect.NewResponse(response), nil
+}
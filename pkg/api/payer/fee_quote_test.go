@@ -0,0 +1,116 @@
+package payer_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/metadata_api"
+	"github.com/xmtp/xmtpd/pkg/registry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type feeQuoteServer struct {
+	metadata_api.UnimplementedMetadataApiServer
+	response *metadata_api.GetFeeQuoteResponse
+	err      error
+	requests []*metadata_api.GetFeeQuoteRequest
+}
+
+func (s *feeQuoteServer) GetFeeQuote(
+	_ context.Context,
+	req *metadata_api.GetFeeQuoteRequest,
+) (*metadata_api.GetFeeQuoteResponse, error) {
+	s.requests = append(s.requests, req)
+	return s.response, s.err
+}
+
+func startFeeQuoteServer(t *testing.T, srv *feeQuoteServer) string {
+	listen, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := grpc.NewServer()
+	metadata_api.RegisterMetadataApiServer(s, srv)
+
+	go func() {
+		err := s.Serve(listen)
+		assert.NoError(t, err)
+	}()
+	t.Cleanup(s.Stop)
+
+	return formatAddress(listen.Addr().String())
+}
+
+func TestGetFeeQuoteForwardsToTargetOriginator(t *testing.T) {
+	srv := &feeQuoteServer{
+		response: &metadata_api.GetFeeQuoteResponse{
+			BaseFeePicodollars:       3100,
+			StorageFeePicodollars:    3000,
+			CongestionFeePicodollars: 500,
+			CongestionUnits:          5,
+			Rates: &metadata_api.FeeRates{
+				MessageFeePicodollars: 100,
+				ValidFromUnixSeconds:  1_700_000_000,
+			},
+		},
+	}
+	addr := startFeeQuoteServer(t, srv)
+
+	svc, _, mockRegistry, _ := buildPayerService(t)
+	mockRegistry.EXPECT().GetNode(uint32(100)).Return(&registry.Node{
+		NodeID:      100,
+		HTTPAddress: addr,
+	}, nil)
+
+	req := &metadata_api.GetFeeQuoteRequest{
+		TopicKind:        0,
+		PayloadSize:      1000,
+		RetentionDays:    30,
+		TargetOriginator: 100,
+	}
+	resp, err := svc.GetFeeQuote(t.Context(), connect.NewRequest(req))
+	require.NoError(t, err)
+	require.True(t, proto.Equal(srv.response, resp.Msg))
+
+	require.Len(t, srv.requests, 1)
+	require.True(t, proto.Equal(req, srv.requests[0]))
+}
+
+func TestGetFeeQuotePreservesNodeErrorCode(t *testing.T) {
+	srv := &feeQuoteServer{
+		err: status.Error(codes.InvalidArgument, "invalid retention days. Must be >= 2"),
+	}
+	addr := startFeeQuoteServer(t, srv)
+
+	svc, _, mockRegistry, _ := buildPayerService(t)
+	mockRegistry.EXPECT().GetNode(mock.Anything).Return(&registry.Node{
+		NodeID:      100,
+		HTTPAddress: addr,
+	}, nil)
+
+	_, err := svc.GetFeeQuote(t.Context(), connect.NewRequest(&metadata_api.GetFeeQuoteRequest{
+		PayloadSize:      1000,
+		RetentionDays:    1,
+		TargetOriginator: 100,
+	}))
+	require.Error(t, err)
+	require.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+}
+
+func TestGetFeeQuoteRequiresTargetOriginator(t *testing.T) {
+	svc, _, _, _ := buildPayerService(t)
+
+	_, err := svc.GetFeeQuote(t.Context(), connect.NewRequest(&metadata_api.GetFeeQuoteRequest{
+		PayloadSize:   1000,
+		RetentionDays: 30,
+	}))
+	require.Error(t, err)
+	require.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+}
@@ -0,0 +1,64 @@
+// Package outbox persists the client envelopes published through the gateway until they
+// are published, so an envelope accepted by the gateway is published at least once, even
+// if the gateway restarts while publishing it.
+package outbox
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusPublished Status = "published"
+	StatusFailed    Status = "failed"
+)
+
+// Entry is a client envelope stored in the outbox.
+type Entry struct {
+	// ID is the hash of the client envelope, and is the receipt ID returned to clients.
+	ID             string
+	ClientEnvelope []byte
+	Status         Status
+	// Attempts is the number of times publishing the envelope was started.
+	Attempts int
+	// OriginatorEnvelope is set once the envelope is published.
+	OriginatorEnvelope []byte
+	// ErrorCode and ErrorMessage are set once the envelope failed to publish, as a gRPC
+	// status code and message.
+	ErrorCode    uint32
+	ErrorMessage string
+}
+
+// Outbox stores client envelopes until they are published.
+//
+// A pending entry is leased to whoever is publishing it. Once its lease expires, for
+// instance because the gateway publishing it stopped, Claim hands it out again.
+type Outbox interface {
+	// Add stores a client envelope as pending, leased to the caller for lease. A lease of
+	// zero makes the entry claimable right away. If the envelope is already stored and has
+	// not failed, the existing entry is returned and created is false. An envelope that
+	// failed is stored afresh.
+	Add(ctx context.Context, clientEnvelope []byte, lease time.Duration) (*Entry, bool, error)
+	// Get returns the entries of ids, in order. Entries that are unknown or expired are nil.
+	Get(ctx context.Context, ids []string) ([]*Entry, error)
+	// Claim leases up to limit pending entries whose lease expired, counting an attempt for
+	// each of them.
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]*Entry, error)
+	// Retry makes a pending entry claimable again once delay has passed.
+	Retry(ctx context.Context, id string, delay time.Duration) error
+	// Complete records that a pending entry was published.
+	Complete(ctx context.Context, id string, originatorEnvelope []byte) error
+	// Fail records that a pending entry failed to publish.
+	Fail(ctx context.Context, id string, code uint32, message string) error
+}
+
+// EntryID returns the ID of the entry of a client envelope.
+func EntryID(clientEnvelope []byte) string {
+	hash := sha256.Sum256(clientEnvelope)
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,335 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Lua scripts for atomic operations
+var (
+	// luaAddScript stores an envelope unless it is already stored and has not failed, in
+	// which case the stored entry is returned.
+	luaAddScript = redis.NewScript(`
+		local entryKey = KEYS[1]
+		local pendingKey = KEYS[2]
+		local id = ARGV[1]
+
+		local status = redis.call('HGET', entryKey, 'status')
+		if status and status ~= 'failed' then
+			return redis.call('HGETALL', entryKey)
+		end
+
+		redis.call('DEL', entryKey)
+		redis.call(
+			'HSET', entryKey,
+			'envelope', ARGV[2],
+			'status', 'pending',
+			'attempts', ARGV[4]
+		)
+		redis.call('ZADD', pendingKey, ARGV[3], id)
+
+		return {}
+	`)
+
+	// luaClaimScript leases the pending entries whose lease expired, and returns their ID,
+	// envelope and attempts.
+	luaClaimScript = redis.NewScript(`
+		local pendingKey = KEYS[1]
+		local entryPrefix = ARGV[4]
+
+		local ids = redis.call('ZRANGEBYSCORE', pendingKey, '-inf', ARGV[1], 'LIMIT', 0, ARGV[3])
+		local claimed = {}
+
+		for _, id in ipairs(ids) do
+			local entryKey = entryPrefix .. id
+			local envelope = redis.call('HGET', entryKey, 'envelope')
+			if envelope then
+				redis.call('ZADD', pendingKey, ARGV[2], id)
+				local attempts = redis.call('HINCRBY', entryKey, 'attempts', 1)
+				table.insert(claimed, id)
+				table.insert(claimed, envelope)
+				table.insert(claimed, attempts)
+			else
+				redis.call('ZREM', pendingKey, id)
+			end
+		end
+
+		return claimed
+	`)
+
+	// luaFinishScript records the outcome of a pending entry, which then expires after the
+	// retention period.
+	luaFinishScript = redis.NewScript(`
+		local entryKey = KEYS[1]
+		local pendingKey = KEYS[2]
+
+		if redis.call('HGET', entryKey, 'status') ~= 'pending' then
+			return 0
+		end
+
+		redis.call(
+			'HSET', entryKey,
+			'status', ARGV[2],
+			'originator_envelope', ARGV[3],
+			'error_code', ARGV[4],
+			'error_message', ARGV[5]
+		)
+		redis.call('ZREM', pendingKey, ARGV[1])
+		redis.call('PEXPIRE', entryKey, ARGV[6])
+
+		return 1
+	`)
+)
+
+// RedisOutbox implements Outbox using a Redis hash per entry, and a sorted set of the
+// pending entries scored by the expiry of their lease.
+type RedisOutbox struct {
+	client    redis.UniversalClient
+	keyPrefix string
+	retention time.Duration
+}
+
+var _ Outbox = (*RedisOutbox)(nil)
+
+// NewRedisOutbox creates an outbox keeping published and failed entries for retention.
+func NewRedisOutbox(
+	client redis.UniversalClient,
+	keyPrefix string,
+	retention time.Duration,
+) (*RedisOutbox, error) {
+	if client == nil {
+		return nil, errors.New("redis client cannot be nil")
+	}
+	if retention <= 0 {
+		return nil, errors.New("retention must be greater than 0")
+	}
+	if keyPrefix == "" {
+		keyPrefix = "xmtpd:outbox:"
+	}
+
+	return &RedisOutbox{
+		client:    client,
+		keyPrefix: keyPrefix,
+		retention: retention,
+	}, nil
+}
+
+// entryKey returns the Redis key for the hash of an entry
+func (r *RedisOutbox) entryKey(id string) string {
+	return r.entryPrefix() + id
+}
+
+func (r *RedisOutbox) entryPrefix() string {
+	return r.keyPrefix + "entry:"
+}
+
+// pendingKey returns the Redis key for the pending entries sorted set
+func (r *RedisOutbox) pendingKey() string {
+	return r.keyPrefix + "pending"
+}
+
+func (r *RedisOutbox) Add(
+	ctx context.Context,
+	clientEnvelope []byte,
+	lease time.Duration,
+) (*Entry, bool, error) {
+	var (
+		id       = EntryID(clientEnvelope)
+		attempts = 0
+	)
+	if lease > 0 {
+		attempts = 1
+	}
+
+	raw, err := luaAddScript.Run(
+		ctx,
+		r.client,
+		[]string{r.entryKey(id), r.pendingKey()},
+		id,
+		clientEnvelope,
+		time.Now().Add(lease).UnixMilli(),
+		attempts,
+	).StringSlice()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to add envelope to outbox: %w", err)
+	}
+
+	if len(raw) == 0 {
+		return &Entry{
+			ID:             id,
+			ClientEnvelope: clientEnvelope,
+			Status:         StatusPending,
+			Attempts:       attempts,
+		}, true, nil
+	}
+
+	fields := make(map[string]string, len(raw)/2)
+	for i := 0; i+1 < len(raw); i += 2 {
+		fields[raw[i]] = raw[i+1]
+	}
+
+	entry, err := parseEntry(id, fields)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return entry, false, nil
+}
+
+func (r *RedisOutbox) Get(ctx context.Context, ids []string) ([]*Entry, error) {
+	pipe := r.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(ids))
+	for i, id := range ids {
+		cmds[i] = pipe.HGetAll(ctx, r.entryKey(id))
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get outbox entries: %w", err)
+	}
+
+	entries := make([]*Entry, len(ids))
+	for i, cmd := range cmds {
+		fields := cmd.Val()
+		if len(fields) == 0 {
+			continue
+		}
+
+		entry, err := parseEntry(ids[i], fields)
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = entry
+	}
+
+	return entries, nil
+}
+
+func (r *RedisOutbox) Claim(
+	ctx context.Context,
+	limit int,
+	lease time.Duration,
+) ([]*Entry, error) {
+	now := time.Now()
+
+	raw, err := luaClaimScript.Run(
+		ctx,
+		r.client,
+		[]string{r.pendingKey()},
+		now.UnixMilli(),
+		now.Add(lease).UnixMilli(),
+		limit,
+		r.entryPrefix(),
+	).Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox entries: %w", err)
+	}
+
+	if len(raw)%3 != 0 {
+		return nil, fmt.Errorf("unexpected claim response of %d values", len(raw))
+	}
+
+	entries := make([]*Entry, 0, len(raw)/3)
+	for i := 0; i < len(raw); i += 3 {
+		id, idOK := raw[i].(string)
+		envelope, envelopeOK := raw[i+1].(string)
+		attempts, attemptsOK := raw[i+2].(int64)
+		if !idOK || !envelopeOK || !attemptsOK {
+			return nil, errors.New("unexpected claim response")
+		}
+
+		entries = append(entries, &Entry{
+			ID:             id,
+			ClientEnvelope: []byte(envelope),
+			Status:         StatusPending,
+			Attempts:       int(attempts),
+		})
+	}
+
+	return entries, nil
+}
+
+func (r *RedisOutbox) Retry(ctx context.Context, id string, delay time.Duration) error {
+	err := r.client.ZAddXX(ctx, r.pendingKey(), redis.Z{
+		Score:  float64(time.Now().Add(delay).UnixMilli()),
+		Member: id,
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to reschedule outbox entry: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RedisOutbox) Complete(
+	ctx context.Context,
+	id string,
+	originatorEnvelope []byte,
+) error {
+	return r.finish(ctx, id, StatusPublished, originatorEnvelope, 0, "")
+}
+
+func (r *RedisOutbox) Fail(ctx context.Context, id string, code uint32, message string) error {
+	return r.finish(ctx, id, StatusFailed, nil, code, message)
+}
+
+func (r *RedisOutbox) finish(
+	ctx context.Context,
+	id string,
+	status Status,
+	originatorEnvelope []byte,
+	code uint32,
+	message string,
+) error {
+	err := luaFinishScript.Run(
+		ctx,
+		r.client,
+		[]string{r.entryKey(id), r.pendingKey()},
+		id,
+		string(status),
+		originatorEnvelope,
+		code,
+		message,
+		r.retention.Milliseconds(),
+	).Err()
+	if err != nil {
+		return fmt.Errorf("failed to record outcome of outbox entry: %w", err)
+	}
+
+	return nil
+}
+
+func parseEntry(id string, fields map[string]string) (*Entry, error) {
+	entry := &Entry{
+		ID:             id,
+		ClientEnvelope: []byte(fields["envelope"]),
+		Status:         Status(fields["status"]),
+		ErrorMessage:   fields["error_message"],
+	}
+
+	if originatorEnvelope := fields["originator_envelope"]; originatorEnvelope != "" {
+		entry.OriginatorEnvelope = []byte(originatorEnvelope)
+	}
+
+	if attempts := fields["attempts"]; attempts != "" {
+		value, err := strconv.Atoi(attempts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attempts of outbox entry %s: %w", id, err)
+		}
+		entry.Attempts = value
+	}
+
+	if code := fields["error_code"]; code != "" {
+		value, err := strconv.ParseUint(code, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid error code of outbox entry %s: %w", id, err)
+		}
+		entry.ErrorCode = uint32(value)
+	}
+
+	return entry, nil
+}
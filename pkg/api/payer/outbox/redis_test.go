@@ -0,0 +1,112 @@
+package outbox_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/api/payer/outbox"
+	redistestutils "github.com/xmtp/xmtpd/pkg/testutils/redis"
+)
+
+func newTestOutbox(t *testing.T) *outbox.RedisOutbox {
+	client, keyPrefix := redistestutils.NewRedisForTest(t)
+	ob, err := outbox.NewRedisOutbox(client, keyPrefix, time.Hour)
+	require.NoError(t, err)
+	return ob
+}
+
+func TestRedisOutbox_AddIsIdempotent(t *testing.T) {
+	ob := newTestOutbox(t)
+
+	entry, created, err := ob.Add(t.Context(), []byte("envelope"), time.Minute)
+	require.NoError(t, err)
+	require.True(t, created)
+	require.Equal(t, outbox.EntryID([]byte("envelope")), entry.ID)
+	require.Equal(t, outbox.StatusPending, entry.Status)
+	require.Equal(t, 1, entry.Attempts)
+
+	again, created, err := ob.Add(t.Context(), []byte("envelope"), time.Minute)
+	require.NoError(t, err)
+	require.False(t, created)
+	require.Equal(t, entry.ID, again.ID)
+	require.Equal(t, outbox.StatusPending, again.Status)
+}
+
+func TestRedisOutbox_Complete(t *testing.T) {
+	ob := newTestOutbox(t)
+
+	entry, _, err := ob.Add(t.Context(), []byte("envelope"), time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, ob.Complete(t.Context(), entry.ID, []byte("originator")))
+
+	// A published envelope is not stored again.
+	again, created, err := ob.Add(t.Context(), []byte("envelope"), time.Minute)
+	require.NoError(t, err)
+	require.False(t, created)
+	require.Equal(t, outbox.StatusPublished, again.Status)
+	require.Equal(t, []byte("originator"), again.OriginatorEnvelope)
+
+	// Outcomes are only recorded once.
+	require.NoError(t, ob.Fail(t.Context(), entry.ID, 13, "failed"))
+	entries, err := ob.Get(t.Context(), []string{entry.ID, "unknown"})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, outbox.StatusPublished, entries[0].Status)
+	require.Nil(t, entries[1])
+}
+
+func TestRedisOutbox_FailedEntryIsStoredAfresh(t *testing.T) {
+	ob := newTestOutbox(t)
+
+	entry, _, err := ob.Add(t.Context(), []byte("envelope"), time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, ob.Fail(t.Context(), entry.ID, 13, "failed"))
+
+	entries, err := ob.Get(t.Context(), []string{entry.ID})
+	require.NoError(t, err)
+	require.Equal(t, outbox.StatusFailed, entries[0].Status)
+	require.Equal(t, uint32(13), entries[0].ErrorCode)
+	require.Equal(t, "failed", entries[0].ErrorMessage)
+
+	again, created, err := ob.Add(t.Context(), []byte("envelope"), time.Minute)
+	require.NoError(t, err)
+	require.True(t, created)
+	require.Equal(t, outbox.StatusPending, again.Status)
+}
+
+func TestRedisOutbox_Claim(t *testing.T) {
+	ob := newTestOutbox(t)
+
+	leased, _, err := ob.Add(t.Context(), []byte("leased"), time.Minute)
+	require.NoError(t, err)
+	async, _, err := ob.Add(t.Context(), []byte("async"), 0)
+	require.NoError(t, err)
+
+	// Only the entry whose lease expired is claimed.
+	claimed, err := ob.Claim(t.Context(), 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	require.Equal(t, async.ID, claimed[0].ID)
+	require.Equal(t, []byte("async"), claimed[0].ClientEnvelope)
+	require.Equal(t, 1, claimed[0].Attempts)
+
+	// A claimed entry is leased to its claimer.
+	claimed, err = ob.Claim(t.Context(), 10, time.Minute)
+	require.NoError(t, err)
+	require.Empty(t, claimed)
+
+	require.NoError(t, ob.Retry(t.Context(), leased.ID, 0))
+	claimed, err = ob.Claim(t.Context(), 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	require.Equal(t, leased.ID, claimed[0].ID)
+	require.Equal(t, 2, claimed[0].Attempts)
+
+	// Finished entries are never claimed again.
+	require.NoError(t, ob.Complete(t.Context(), leased.ID, []byte("originator")))
+	require.NoError(t, ob.Retry(t.Context(), leased.ID, 0))
+	claimed, err = ob.Claim(t.Context(), 10, time.Minute)
+	require.NoError(t, err)
+	require.Empty(t, claimed)
+}
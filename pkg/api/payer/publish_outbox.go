@@ -0,0 +1,363 @@
+package payer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/xmtp/xmtpd/pkg/api/payer/outbox"
+	"github.com/xmtp/xmtpd/pkg/metrics"
+	envelopesProto "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/envelopes"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/gateway_api"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/payer_api"
+	"github.com/xmtp/xmtpd/pkg/tracing"
+	"github.com/xmtp/xmtpd/pkg/utils"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// outboxReplayBatchSize is the number of pending envelopes replayed per round.
+	outboxReplayBatchSize = 100
+	// outboxLeaseMargin is added to the time a publish may take, before an envelope that is
+	// still pending is considered abandoned and replayed.
+	outboxLeaseMargin = 1 * time.Minute
+	// outboxMaxRetryDelay bounds the delay between two attempts of a replayed envelope.
+	outboxMaxRetryDelay = 5 * time.Minute
+	// maxReceiptIDs bounds the receipts queried per GetPublishReceipts call.
+	maxReceiptIDs = 1000
+)
+
+// publishThroughOutbox stores the envelopes of a request in the outbox before publishing
+// them, and records their outcome once published. Envelopes that were already published
+// are not published again, their stored originator envelope is returned instead.
+//
+// Async requests return once the envelopes are stored, and leave publishing them to the
+// replay loop.
+func (s *Service) publishThroughOutbox(
+	ctx context.Context,
+	req *payer_api.PublishClientEnvelopesRequest,
+	grouped *groupedEnvelopes,
+) (*connect.Response[payer_api.PublishClientEnvelopesResponse], error) {
+	var (
+		numEnvelopes   = len(req.GetEnvelopes())
+		partialFailure = req.GetAllowPartialFailure()
+		results        = newPublishResults(numEnvelopes)
+		receiptIDs     = make([]string, numEnvelopes)
+		created        = make(map[int]struct{}, numEnvelopes)
+		lease          = s.outboxLease()
+	)
+
+	if req.GetAsync() {
+		lease = 0
+	}
+
+	for i, envelope := range req.GetEnvelopes() {
+		clientEnvelope, err := proto.Marshal(envelope)
+		if err != nil {
+			return nil, connect.NewError(
+				connect.CodeInternal,
+				fmt.Errorf("error marshaling client envelope at index %d: %w", i, err),
+			)
+		}
+
+		entry, isNew, err := s.cfg.Outbox.Add(ctx, clientEnvelope, lease)
+		if err != nil {
+			return nil, connect.NewError(
+				connect.CodeUnavailable,
+				fmt.Errorf("error storing envelope in outbox: %w", err),
+			)
+		}
+		receiptIDs[i] = entry.ID
+
+		if isNew {
+			created[i] = struct{}{}
+			continue
+		}
+
+		if entry.Status == outbox.StatusPublished {
+			originatorEnvelope := &envelopesProto.OriginatorEnvelope{}
+			if err := proto.Unmarshal(entry.OriginatorEnvelope, originatorEnvelope); err != nil {
+				return nil, connect.NewError(
+					connect.CodeInternal,
+					fmt.Errorf("error unmarshaling stored originator envelope: %w", err),
+				)
+			}
+			results.succeed(i, originatorEnvelope)
+			continue
+		}
+
+		results.fail(i, connect.NewError(
+			connect.CodeAlreadyExists,
+			fmt.Errorf("envelope is already being published, see receipt %s", entry.ID),
+		))
+	}
+
+	if req.GetAsync() {
+		s.notifyOutbox()
+		return connect.NewResponse(&payer_api.PublishClientEnvelopesResponse{
+			ReceiptIds: receiptIDs,
+		}), nil
+	}
+
+	// The request fails as a whole when one of its envelopes is already being published, so
+	// the envelopes it stored are failed rather than published behind the back of the
+	// client. Publishing them again stores them afresh.
+	if !partialFailure && results.firstErr != nil {
+		for i := range created {
+			s.recordOutboxOutcome(receiptIDs[i], nil, connect.NewError(
+				connect.CodeAborted,
+				errors.New("another envelope of the request is already being published"),
+			))
+		}
+		return nil, results.firstErr
+	}
+
+	// Unless partial failure is allowed, the first destination to fail cancels the others.
+	publishCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if !partialFailure {
+		results.onFailure = cancel
+	}
+
+	s.publishToDestinations(
+		publishCtx,
+		ctx,
+		grouped.filter(func(index int) bool {
+			_, ok := created[index]
+			return ok
+		}),
+		results,
+	)
+
+	for i := range created {
+		s.recordOutboxOutcome(receiptIDs[i], results.envelopes[i], results.errs[i])
+	}
+
+	response, err := newPublishResponse(results, partialFailure)
+	if err != nil {
+		return nil, err
+	}
+	response.Msg.ReceiptIds = receiptIDs
+
+	return response, nil
+}
+
+// recordOutboxOutcome records whether the envelope of an outbox entry was published.
+// Outcomes are recorded with the service context, so they are recorded for requests that
+// were canceled too.
+func (s *Service) recordOutboxOutcome(
+	id string,
+	originatorEnvelope *envelopesProto.OriginatorEnvelope,
+	publishErr error,
+) {
+	var err error
+	if publishErr != nil {
+		status := newEnvelopeStatus(publishErr)
+		err = s.cfg.Outbox.Fail(s.ctx, id, status.GetCode(), status.GetMessage())
+	} else {
+		var bytes []byte
+		if bytes, err = proto.Marshal(originatorEnvelope); err == nil {
+			err = s.cfg.Outbox.Complete(s.ctx, id, bytes)
+		}
+	}
+
+	if err != nil {
+		s.logger.Error(
+			"error recording outcome of outbox entry",
+			zap.String("receipt_id", id),
+			zap.Error(err),
+		)
+	}
+}
+
+// GetPublishReceipts returns the status of envelopes published through the outbox.
+func (s *Service) GetPublishReceipts(
+	ctx context.Context,
+	req *connect.Request[gateway_api.GetPublishReceiptsRequest],
+) (*connect.Response[gateway_api.GetPublishReceiptsResponse], error) {
+	if req.Msg == nil {
+		return nil, connect.NewError(
+			connect.CodeInvalidArgument,
+			errors.New(requestMissingMessageError),
+		)
+	}
+
+	if s.logger.Core().Enabled(zap.DebugLevel) {
+		s.logger.Debug("received request", utils.MethodField(req.Spec().Procedure))
+	}
+
+	if s.cfg.Outbox == nil {
+		return nil, connect.NewError(
+			connect.CodeFailedPrecondition,
+			errors.New("the gateway publish outbox is not enabled"),
+		)
+	}
+
+	receiptIDs := req.Msg.GetReceiptIds()
+	if len(receiptIDs) > maxReceiptIDs {
+		return nil, connect.NewError(
+			connect.CodeInvalidArgument,
+			fmt.Errorf("cannot query more than %d receipts", maxReceiptIDs),
+		)
+	}
+
+	entries, err := s.cfg.Outbox.Get(ctx, receiptIDs)
+	if err != nil {
+		return nil, connect.NewError(
+			connect.CodeUnavailable,
+			fmt.Errorf("error getting outbox entries: %w", err),
+		)
+	}
+
+	receipts := make([]*gateway_api.PublishReceipt, len(receiptIDs))
+	for i, entry := range entries {
+		receipt, err := newPublishReceipt(receiptIDs[i], entry)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		receipts[i] = receipt
+	}
+
+	return connect.NewResponse(&gateway_api.GetPublishReceiptsResponse{
+		Receipts: receipts,
+	}), nil
+}
+
+func newPublishReceipt(id string, entry *outbox.Entry) (*gateway_api.PublishReceipt, error) {
+	receipt := &gateway_api.PublishReceipt{ReceiptId: id}
+	if entry == nil {
+		return receipt, nil
+	}
+
+	receipt.Attempts = uint32(entry.Attempts)
+
+	switch entry.Status {
+	case outbox.StatusPending:
+		receipt.Status = gateway_api.PublishReceiptStatus_PUBLISH_RECEIPT_STATUS_PENDING
+
+	case outbox.StatusPublished:
+		receipt.Status = gateway_api.PublishReceiptStatus_PUBLISH_RECEIPT_STATUS_PUBLISHED
+		originatorEnvelope := &envelopesProto.OriginatorEnvelope{}
+		if err := proto.Unmarshal(entry.OriginatorEnvelope, originatorEnvelope); err != nil {
+			return nil, fmt.Errorf("error unmarshaling stored originator envelope: %w", err)
+		}
+		receipt.OriginatorEnvelope = originatorEnvelope
+
+	case outbox.StatusFailed:
+		receipt.Status = gateway_api.PublishReceiptStatus_PUBLISH_RECEIPT_STATUS_FAILED
+		receipt.ErrorCode = entry.ErrorCode
+		receipt.ErrorMessage = entry.ErrorMessage
+	}
+
+	return receipt, nil
+}
+
+// outboxLease is how long an envelope stays leased to whoever publishes it. It outlasts
+// the deadline of a publish to a node, including retries.
+func (s *Service) outboxLease() time.Duration {
+	return s.cfg.PublishTimeout*time.Duration(max(s.cfg.PublishRetries, 1)) + outboxLeaseMargin
+}
+
+// notifyOutbox wakes the replay loop up, without waiting for its next round.
+func (s *Service) notifyOutbox() {
+	select {
+	case s.outboxNotify <- struct{}{}:
+	default:
+	}
+}
+
+// replayOutbox publishes the envelopes left pending in the outbox until the service shuts
+// down. These are the envelopes of async requests, of requests that failed before
+// publishing them, and of requests that were interrupted by a restart of a gateway.
+func (s *Service) replayOutbox() {
+	tracing.GoPanicWrap(
+		s.ctx,
+		&s.wg,
+		"gateway-outbox-replay",
+		func(ctx context.Context) {
+			ticker := time.NewTicker(s.cfg.OutboxReplayInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				case <-s.outboxNotify:
+				}
+
+				// Keep replaying while full batches are claimed, to drain a backlog.
+				for ctx.Err() == nil && s.replayOutboxOnce(ctx) == outboxReplayBatchSize {
+				}
+			}
+		})
+}
+
+// replayOutboxOnce publishes a batch of pending envelopes, and returns its size.
+func (s *Service) replayOutboxOnce(ctx context.Context) int {
+	entries, err := s.cfg.Outbox.Claim(ctx, outboxReplayBatchSize, s.outboxLease())
+	if err != nil {
+		s.logger.Error("error claiming outbox entries", zap.Error(err))
+		return 0
+	}
+	if len(entries) == 0 {
+		return 0
+	}
+
+	// Entries are grouped one by one, so an entry that cannot be published only fails
+	// itself.
+	var (
+		claimed = make([]*outbox.Entry, 0, len(entries))
+		grouped = &groupedEnvelopes{forNodes: make(map[uint32][]clientEnvelopeWithIndex)}
+		errs    = make(map[int]error)
+	)
+	for _, entry := range entries {
+		envelope := &envelopesProto.ClientEnvelope{}
+		if err := proto.Unmarshal(entry.ClientEnvelope, envelope); err != nil {
+			s.recordOutboxOutcome(entry.ID, nil, connect.NewError(
+				connect.CodeInvalidArgument,
+				fmt.Errorf("error unmarshaling stored client envelope: %w", err),
+			))
+			metrics.EmitGatewayOutboxEnvelopes("failed", 1)
+			continue
+		}
+
+		if err := s.groupEnvelope(grouped, len(claimed), envelope); err != nil {
+			errs[len(claimed)] = err
+		}
+		claimed = append(claimed, entry)
+	}
+
+	results := newPublishResults(len(claimed))
+	for i, err := range errs {
+		results.fail(i, err)
+	}
+	s.publishToDestinations(ctx, ctx, grouped, results)
+
+	for i, entry := range claimed {
+		publishErr := results.errs[i]
+		switch {
+		case publishErr == nil:
+			s.recordOutboxOutcome(entry.ID, results.envelopes[i], nil)
+			metrics.EmitGatewayOutboxEnvelopes("published", 1)
+
+		case connect.CodeOf(publishErr) == connect.CodeInvalidArgument ||
+			entry.Attempts >= s.cfg.OutboxMaxAttempts:
+			s.recordOutboxOutcome(entry.ID, nil, publishErr)
+			metrics.EmitGatewayOutboxEnvelopes("failed", 1)
+
+		default:
+			delay := min(s.cfg.OutboxReplayInterval<<min(entry.Attempts, 16), outboxMaxRetryDelay)
+			if err := s.cfg.Outbox.Retry(ctx, entry.ID, delay); err != nil {
+				s.logger.Error("error rescheduling outbox entry", zap.Error(err))
+			}
+			metrics.EmitGatewayOutboxEnvelopes("retried", 1)
+		}
+	}
+
+	s.logger.Debug("replayed outbox entries", utils.NumEnvelopesField(len(entries)))
+
+	return len(entries)
+}
@@ -0,0 +1,337 @@
+package payer_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	iu "github.com/xmtp/xmtpd/pkg/abi/identityupdatebroadcaster"
+	"github.com/xmtp/xmtpd/pkg/api/payer"
+	"github.com/xmtp/xmtpd/pkg/api/payer/outbox"
+	envelopesProto "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/envelopes"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/gateway_api"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/payer_api"
+	"google.golang.org/protobuf/proto"
+)
+
+// memoryOutbox is an in-memory outbox.Outbox for tests.
+type memoryOutbox struct {
+	mu      sync.Mutex
+	entries map[string]*outbox.Entry
+	leases  map[string]time.Time
+}
+
+var _ outbox.Outbox = (*memoryOutbox)(nil)
+
+func newMemoryOutbox() *memoryOutbox {
+	return &memoryOutbox{
+		entries: make(map[string]*outbox.Entry),
+		leases:  make(map[string]time.Time),
+	}
+}
+
+func (m *memoryOutbox) Add(
+	_ context.Context,
+	clientEnvelope []byte,
+	lease time.Duration,
+) (*outbox.Entry, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := outbox.EntryID(clientEnvelope)
+	if entry, ok := m.entries[id]; ok && entry.Status != outbox.StatusFailed {
+		copied := *entry
+		return &copied, false, nil
+	}
+
+	entry := &outbox.Entry{ID: id, ClientEnvelope: clientEnvelope, Status: outbox.StatusPending}
+	if lease > 0 {
+		entry.Attempts = 1
+	}
+	m.entries[id] = entry
+	m.leases[id] = time.Now().Add(lease)
+
+	copied := *entry
+	return &copied, true, nil
+}
+
+func (m *memoryOutbox) Get(_ context.Context, ids []string) ([]*outbox.Entry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries := make([]*outbox.Entry, len(ids))
+	for i, id := range ids {
+		if entry, ok := m.entries[id]; ok {
+			copied := *entry
+			entries[i] = &copied
+		}
+	}
+	return entries, nil
+}
+
+func (m *memoryOutbox) Claim(
+	_ context.Context,
+	limit int,
+	lease time.Duration,
+) ([]*outbox.Entry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var claimed []*outbox.Entry
+	for id, expiry := range m.leases {
+		if len(claimed) == limit {
+			break
+		}
+		if expiry.After(time.Now()) {
+			continue
+		}
+		entry := m.entries[id]
+		entry.Attempts++
+		m.leases[id] = time.Now().Add(lease)
+		copied := *entry
+		claimed = append(claimed, &copied)
+	}
+	return claimed, nil
+}
+
+func (m *memoryOutbox) Retry(_ context.Context, id string, delay time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.leases[id]; ok {
+		m.leases[id] = time.Now().Add(delay)
+	}
+	return nil
+}
+
+func (m *memoryOutbox) Complete(_ context.Context, id string, originatorEnvelope []byte) error {
+	return m.finish(id, func(entry *outbox.Entry) {
+		entry.Status = outbox.StatusPublished
+		entry.OriginatorEnvelope = originatorEnvelope
+	})
+}
+
+func (m *memoryOutbox) Fail(_ context.Context, id string, code uint32, message string) error {
+	return m.finish(id, func(entry *outbox.Entry) {
+		entry.Status = outbox.StatusFailed
+		entry.ErrorCode = code
+		entry.ErrorMessage = message
+	})
+}
+
+func (m *memoryOutbox) finish(id string, update func(entry *outbox.Entry)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[id]
+	if !ok || entry.Status != outbox.StatusPending {
+		return nil
+	}
+	update(entry)
+	delete(m.leases, id)
+	return nil
+}
+
+func getPublishReceipt(
+	t *testing.T,
+	svc *payer.Service,
+	id string,
+) *gateway_api.PublishReceipt {
+	response, err := svc.GetPublishReceipts(
+		t.Context(),
+		connect.NewRequest(&gateway_api.GetPublishReceiptsRequest{ReceiptIds: []string{id}}),
+	)
+	require.NoError(t, err)
+	require.Len(t, response.Msg.GetReceipts(), 1)
+	return response.Msg.GetReceipts()[0]
+}
+
+func TestPublishThroughOutboxIsIdempotent(t *testing.T) {
+	ob := newMemoryOutbox()
+	svc, mockMessagePublisher, _, _ := buildPayerService(
+		t,
+		payer.WithOutbox(ob, time.Hour, 3),
+	)
+
+	envelope, envelopeBytes := newIdentityUpdateEnvelope(t)
+	mockMessagePublisher.EXPECT().
+		PublishIdentityUpdate(mock.Anything, mock.Anything, envelopeBytes).
+		Return(&iu.IdentityUpdateBroadcasterIdentityUpdateCreated{
+			Raw:        types.Log{TxHash: common.Hash{1}},
+			SequenceId: 50,
+			Update:     envelopeBytes,
+		}, nil).
+		Once()
+
+	request := &payer_api.PublishClientEnvelopesRequest{
+		Envelopes: []*envelopesProto.ClientEnvelope{envelope},
+	}
+
+	first, err := svc.PublishClientEnvelopes(t.Context(), connect.NewRequest(request))
+	require.NoError(t, err)
+	require.Equal(t, []string{outbox.EntryID(envelopeBytes)}, first.Msg.GetReceiptIds())
+	requireBlockchainEnvelope(t, first.Msg.GetOriginatorEnvelopes()[0], common.Hash{1}, 50)
+
+	// Publishing the same envelope again returns the stored outcome.
+	second, err := svc.PublishClientEnvelopes(t.Context(), connect.NewRequest(request))
+	require.NoError(t, err)
+	require.Equal(t, first.Msg.GetReceiptIds(), second.Msg.GetReceiptIds())
+	requireBlockchainEnvelope(t, second.Msg.GetOriginatorEnvelopes()[0], common.Hash{1}, 50)
+
+	receipt := getPublishReceipt(t, svc, first.Msg.GetReceiptIds()[0])
+	require.Equal(
+		t,
+		gateway_api.PublishReceiptStatus_PUBLISH_RECEIPT_STATUS_PUBLISHED,
+		receipt.GetStatus(),
+	)
+	requireBlockchainEnvelope(t, receipt.GetOriginatorEnvelope(), common.Hash{1}, 50)
+}
+
+func TestPublishThroughOutboxAsync(t *testing.T) {
+	ob := newMemoryOutbox()
+	svc, mockMessagePublisher, _, _ := buildPayerService(
+		t,
+		payer.WithOutbox(ob, time.Hour, 3),
+	)
+
+	envelope, envelopeBytes := newIdentityUpdateEnvelope(t)
+	mockMessagePublisher.EXPECT().
+		PublishIdentityUpdate(mock.Anything, mock.Anything, envelopeBytes).
+		Return(&iu.IdentityUpdateBroadcasterIdentityUpdateCreated{
+			Raw:        types.Log{TxHash: common.Hash{2}},
+			SequenceId: 60,
+			Update:     envelopeBytes,
+		}, nil).
+		Once()
+
+	response, err := svc.PublishClientEnvelopes(
+		t.Context(),
+		connect.NewRequest(&payer_api.PublishClientEnvelopesRequest{
+			Envelopes: []*envelopesProto.ClientEnvelope{envelope},
+			Async:     true,
+		}),
+	)
+	require.NoError(t, err)
+	require.Empty(t, response.Msg.GetOriginatorEnvelopes())
+	require.Len(t, response.Msg.GetReceiptIds(), 1)
+
+	receiptID := response.Msg.GetReceiptIds()[0]
+	require.Eventually(t, func() bool {
+		return getPublishReceipt(t, svc, receiptID).GetStatus() ==
+			gateway_api.PublishReceiptStatus_PUBLISH_RECEIPT_STATUS_PUBLISHED
+	}, 5*time.Second, 10*time.Millisecond)
+
+	receipt := getPublishReceipt(t, svc, receiptID)
+	require.Equal(t, uint32(1), receipt.GetAttempts())
+	requireBlockchainEnvelope(t, receipt.GetOriginatorEnvelope(), common.Hash{2}, 60)
+}
+
+func TestReplayOutboxFailsOnlyInvalidEntries(t *testing.T) {
+	ob := newMemoryOutbox()
+
+	envelope, envelopeBytes := newIdentityUpdateEnvelope(t)
+	valid, _, err := ob.Add(t.Context(), envelopeBytes, 0)
+	require.NoError(t, err)
+
+	invalidBytes, err := proto.Marshal(&envelopesProto.ClientEnvelope{
+		Payload: envelope.GetPayload(),
+	})
+	require.NoError(t, err)
+	invalid, _, err := ob.Add(t.Context(), invalidBytes, 0)
+	require.NoError(t, err)
+
+	svc, mockMessagePublisher, _, _ := buildPayerService(
+		t,
+		payer.WithOutbox(ob, 10*time.Millisecond, 3),
+	)
+	mockMessagePublisher.EXPECT().
+		PublishIdentityUpdate(mock.Anything, mock.Anything, envelopeBytes).
+		Return(&iu.IdentityUpdateBroadcasterIdentityUpdateCreated{
+			Raw:        types.Log{TxHash: common.Hash{3}},
+			SequenceId: 70,
+			Update:     envelopeBytes,
+		}, nil).
+		Once()
+
+	require.Eventually(t, func() bool {
+		return getPublishReceipt(t, svc, valid.ID).GetStatus() ==
+			gateway_api.PublishReceiptStatus_PUBLISH_RECEIPT_STATUS_PUBLISHED
+	}, 5*time.Second, 10*time.Millisecond)
+
+	receipt := getPublishReceipt(t, svc, invalid.ID)
+	require.Equal(
+		t,
+		gateway_api.PublishReceiptStatus_PUBLISH_RECEIPT_STATUS_FAILED,
+		receipt.GetStatus(),
+	)
+	require.Equal(t, uint32(connect.CodeInvalidArgument), receipt.GetErrorCode())
+}
+
+func TestPublishThroughOutboxFailsEnvelopesOfRejectedRequest(t *testing.T) {
+	ob := newMemoryOutbox()
+	svc, _, _, _ := buildPayerService(
+		t,
+		payer.WithOutbox(ob, time.Hour, 3),
+	)
+
+	// The first envelope is already being published by another request.
+	first, firstBytes := newIdentityUpdateEnvelope(t)
+	_, _, err := ob.Add(t.Context(), firstBytes, time.Hour)
+	require.NoError(t, err)
+
+	second, secondBytes := newIdentityUpdateEnvelope(t)
+	_, err = svc.PublishClientEnvelopes(
+		t.Context(),
+		connect.NewRequest(&payer_api.PublishClientEnvelopesRequest{
+			Envelopes: []*envelopesProto.ClientEnvelope{first, second},
+		}),
+	)
+	require.Equal(t, connect.CodeAlreadyExists, connect.CodeOf(err))
+
+	// The envelope stored by the rejected request is not published behind its back.
+	receipt := getPublishReceipt(t, svc, outbox.EntryID(secondBytes))
+	require.Equal(
+		t,
+		gateway_api.PublishReceiptStatus_PUBLISH_RECEIPT_STATUS_FAILED,
+		receipt.GetStatus(),
+	)
+	require.Equal(t, uint32(connect.CodeAborted), receipt.GetErrorCode())
+}
+
+func TestPublishReceiptOfUnknownEnvelope(t *testing.T) {
+	svc, _, _, _ := buildPayerService(t, payer.WithOutbox(newMemoryOutbox(), time.Hour, 3))
+
+	receipt := getPublishReceipt(t, svc, "unknown")
+	require.Equal(t, "unknown", receipt.GetReceiptId())
+	require.Equal(
+		t,
+		gateway_api.PublishReceiptStatus_PUBLISH_RECEIPT_STATUS_UNSPECIFIED,
+		receipt.GetStatus(),
+	)
+}
+
+func TestPublishAsyncRequiresOutbox(t *testing.T) {
+	svc, _, _, _ := buildPayerService(t)
+
+	envelope, _ := newIdentityUpdateEnvelope(t)
+	_, err := svc.PublishClientEnvelopes(
+		t.Context(),
+		connect.NewRequest(&payer_api.PublishClientEnvelopesRequest{
+			Envelopes: []*envelopesProto.ClientEnvelope{envelope},
+			Async:     true,
+		}),
+	)
+	require.Equal(t, connect.CodeFailedPrecondition, connect.CodeOf(err))
+
+	_, err = svc.GetPublishReceipts(
+		t.Context(),
+		connect.NewRequest(&gateway_api.GetPublishReceiptsRequest{ReceiptIds: []string{"id"}}),
+	)
+	require.Equal(t, connect.CodeFailedPrecondition, connect.CodeOf(err))
+}
@@ -2,7 +2,9 @@ package payer_test
 
 import (
 	"context"
+	"errors"
 	"net"
+	"sync"
 	"testing"
 	"time"
 
@@ -18,6 +20,7 @@ import (
 	"github.com/stretchr/testify/require"
 	iu "github.com/xmtp/xmtpd/pkg/abi/identityupdatebroadcaster"
 	"github.com/xmtp/xmtpd/pkg/api/payer"
+	"github.com/xmtp/xmtpd/pkg/blockchain"
 	"github.com/xmtp/xmtpd/pkg/envelopes"
 	"github.com/xmtp/xmtpd/pkg/proto/identity/associations"
 	envelopesProto "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/envelopes"
@@ -179,6 +182,282 @@ func TestPublishIdentityUpdate(t *testing.T) {
 	)
 }
 
+func newIdentityUpdateEnvelope(t *testing.T) (*envelopesProto.ClientEnvelope, []byte) {
+	inboxID := testutils.RandomInboxIDBytes()
+	envelope := envelopesTestUtils.CreateIdentityUpdateClientEnvelope(
+		inboxID,
+		&associations.IdentityUpdate{InboxId: utils.HexEncode(inboxID[:])},
+	)
+	envelopeBytes, err := proto.Marshal(envelope)
+	require.NoError(t, err)
+	return envelope, envelopeBytes
+}
+
+func identityUpdateBatchLogs(
+	txnHash common.Hash,
+	firstSequenceID uint64,
+	updates [][]byte,
+) []*iu.IdentityUpdateBroadcasterIdentityUpdateCreated {
+	logs := make([]*iu.IdentityUpdateBroadcasterIdentityUpdateCreated, len(updates))
+	for i, update := range updates {
+		logs[i] = &iu.IdentityUpdateBroadcasterIdentityUpdateCreated{
+			Raw:        types.Log{TxHash: txnHash},
+			SequenceId: firstSequenceID + uint64(i),
+			Update:     update,
+		}
+	}
+	return logs
+}
+
+func requireBlockchainEnvelope(
+	t *testing.T,
+	responseEnvelope *envelopesProto.OriginatorEnvelope,
+	txnHash common.Hash,
+	sequenceID uint64,
+) {
+	parsed, err := envelopes.NewOriginatorEnvelope(responseEnvelope)
+	require.NoError(t, err)
+	require.Equal(t, txnHash[:], parsed.Proto().GetBlockchainProof().GetTransactionHash())
+	require.Equal(t, sequenceID, parsed.UnsignedOriginatorEnvelope.OriginatorSequenceID())
+}
+
+func TestPublishIdentityUpdatesBatchedWhenFull(t *testing.T) {
+	svc, mockMessagePublisher, _, _ := buildPayerService(
+		t,
+		payer.WithBlockchainBatching(time.Hour, 2),
+	)
+
+	first, firstBytes := newIdentityUpdateEnvelope(t)
+	second, secondBytes := newIdentityUpdateEnvelope(t)
+	txnHash := common.Hash{4, 5, 6}
+
+	mockMessagePublisher.EXPECT().
+		PublishIdentityUpdates(mock.Anything, mock.Anything, [][]byte{firstBytes, secondBytes}).
+		Return(identityUpdateBatchLogs(txnHash, 10, [][]byte{firstBytes, secondBytes}), nil).
+		Once()
+
+	publishResponse, err := svc.PublishClientEnvelopes(
+		t.Context(),
+		connect.NewRequest(&payer_api.PublishClientEnvelopesRequest{
+			Envelopes: []*envelopesProto.ClientEnvelope{first, second},
+		}),
+	)
+	require.NoError(t, err)
+	require.Len(t, publishResponse.Msg.GetOriginatorEnvelopes(), 2)
+	requireBlockchainEnvelope(t, publishResponse.Msg.GetOriginatorEnvelopes()[0], txnHash, 10)
+	requireBlockchainEnvelope(t, publishResponse.Msg.GetOriginatorEnvelopes()[1], txnHash, 11)
+}
+
+func TestPublishIdentityUpdatesBatchedAcrossRequests(t *testing.T) {
+	svc, mockMessagePublisher, _, _ := buildPayerService(
+		t,
+		payer.WithBlockchainBatching(100*time.Millisecond, 10),
+	)
+
+	txnHash := common.Hash{7, 8, 9}
+	mockMessagePublisher.EXPECT().
+		PublishIdentityUpdates(mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(
+			_ context.Context,
+			inboxIDs [][32]byte,
+			updates [][]byte,
+		) ([]*iu.IdentityUpdateBroadcasterIdentityUpdateCreated, error) {
+			return identityUpdateBatchLogs(txnHash, 20, updates), nil
+		}).
+		Once()
+
+	var (
+		wg        sync.WaitGroup
+		responses = make([]*envelopesProto.OriginatorEnvelope, 2)
+	)
+	for i := range responses {
+		envelope, _ := newIdentityUpdateEnvelope(t)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			publishResponse, err := svc.PublishClientEnvelopes(
+				t.Context(),
+				connect.NewRequest(&payer_api.PublishClientEnvelopesRequest{
+					Envelopes: []*envelopesProto.ClientEnvelope{envelope},
+				}),
+			)
+			assert.NoError(t, err)
+			responses[i] = publishResponse.Msg.GetOriginatorEnvelopes()[0]
+		}()
+	}
+	wg.Wait()
+
+	sequenceIDs := make([]uint64, 0, len(responses))
+	for _, responseEnvelope := range responses {
+		parsed, err := envelopes.NewOriginatorEnvelope(responseEnvelope)
+		require.NoError(t, err)
+		require.Equal(t, txnHash[:], parsed.Proto().GetBlockchainProof().GetTransactionHash())
+		sequenceIDs = append(sequenceIDs, parsed.OriginatorSequenceID())
+	}
+	require.ElementsMatch(t, []uint64{20, 21}, sequenceIDs)
+}
+
+func TestPublishIdentityUpdatesBatchesPublishedSerially(t *testing.T) {
+	svc, mockMessagePublisher, _, _ := buildPayerService(
+		t,
+		payer.WithBlockchainBatching(time.Hour, 2),
+	)
+
+	var (
+		inFlight   atomic.Int32
+		overlapped atomic.Bool
+		nextSeqID  atomic.Uint64
+	)
+	nextSeqID.Store(30)
+	mockMessagePublisher.EXPECT().
+		PublishIdentityUpdates(mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(
+			_ context.Context,
+			inboxIDs [][32]byte,
+			updates [][]byte,
+		) ([]*iu.IdentityUpdateBroadcasterIdentityUpdateCreated, error) {
+			if inFlight.Inc() > 1 {
+				overlapped.Store(true)
+			}
+			defer inFlight.Dec()
+			time.Sleep(20 * time.Millisecond)
+
+			startSeqID := nextSeqID.Add(uint64(len(updates))) - uint64(len(updates))
+			return identityUpdateBatchLogs(common.Hash{1}, startSeqID, updates), nil
+		}).
+		Times(3)
+
+	var wg sync.WaitGroup
+	for range 3 {
+		first, _ := newIdentityUpdateEnvelope(t)
+		second, _ := newIdentityUpdateEnvelope(t)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.PublishClientEnvelopes(
+				t.Context(),
+				connect.NewRequest(&payer_api.PublishClientEnvelopesRequest{
+					Envelopes: []*envelopesProto.ClientEnvelope{first, second},
+				}),
+			)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	require.False(t, overlapped.Load(), "batches were published concurrently")
+}
+
+func TestPublishIdentityUpdatesFallsBackWhenBatchReverts(t *testing.T) {
+	svc, mockMessagePublisher, _, _ := buildPayerService(
+		t,
+		payer.WithBlockchainBatching(time.Hour, 2),
+	)
+
+	first, firstBytes := newIdentityUpdateEnvelope(t)
+	second, secondBytes := newIdentityUpdateEnvelope(t)
+
+	mockMessagePublisher.EXPECT().
+		PublishIdentityUpdates(mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, blockchain.ErrBatchReverted).
+		Once()
+	for i, update := range [][]byte{firstBytes, secondBytes} {
+		mockMessagePublisher.EXPECT().
+			PublishIdentityUpdate(mock.Anything, mock.Anything, update).
+			Return(&iu.IdentityUpdateBroadcasterIdentityUpdateCreated{
+				Raw:        types.Log{TxHash: common.Hash{byte(i + 1)}},
+				SequenceId: uint64(30 + i),
+				Update:     update,
+			}, nil).
+			Once()
+	}
+
+	publishResponse, err := svc.PublishClientEnvelopes(
+		t.Context(),
+		connect.NewRequest(&payer_api.PublishClientEnvelopesRequest{
+			Envelopes: []*envelopesProto.ClientEnvelope{first, second},
+		}),
+	)
+	require.NoError(t, err)
+	require.Len(t, publishResponse.Msg.GetOriginatorEnvelopes(), 2)
+	requireBlockchainEnvelope(
+		t,
+		publishResponse.Msg.GetOriginatorEnvelopes()[0],
+		common.Hash{1},
+		30,
+	)
+	requireBlockchainEnvelope(
+		t,
+		publishResponse.Msg.GetOriginatorEnvelopes()[1],
+		common.Hash{2},
+		31,
+	)
+}
+
+func TestPublishIdentityUpdatesAllowPartialFailure(t *testing.T) {
+	svc, mockMessagePublisher, _, _ := buildPayerService(t)
+
+	first, firstBytes := newIdentityUpdateEnvelope(t)
+	second, secondBytes := newIdentityUpdateEnvelope(t)
+
+	mockMessagePublisher.EXPECT().
+		PublishIdentityUpdate(mock.Anything, mock.Anything, firstBytes).
+		Return(&iu.IdentityUpdateBroadcasterIdentityUpdateCreated{
+			Raw:        types.Log{TxHash: common.Hash{1}},
+			SequenceId: 40,
+			Update:     firstBytes,
+		}, nil).
+		Once()
+	mockMessagePublisher.EXPECT().
+		PublishIdentityUpdate(mock.Anything, mock.Anything, secondBytes).
+		Return(nil, errors.New("execution reverted")).
+		Once()
+
+	publishResponse, err := svc.PublishClientEnvelopes(
+		t.Context(),
+		connect.NewRequest(&payer_api.PublishClientEnvelopesRequest{
+			Envelopes:           []*envelopesProto.ClientEnvelope{first, second},
+			AllowPartialFailure: true,
+		}),
+	)
+	require.NoError(t, err)
+
+	statuses := publishResponse.Msg.GetEnvelopeStatuses()
+	require.Len(t, statuses, 2)
+	require.Zero(t, statuses[0].GetCode())
+	require.Equal(t, uint32(connect.CodeInternal), statuses[1].GetCode())
+	require.Contains(t, statuses[1].GetMessage(), "execution reverted")
+
+	require.Len(t, publishResponse.Msg.GetOriginatorEnvelopes(), 2)
+	requireBlockchainEnvelope(
+		t,
+		publishResponse.Msg.GetOriginatorEnvelopes()[0],
+		common.Hash{1},
+		40,
+	)
+	require.Empty(t, publishResponse.Msg.GetOriginatorEnvelopes()[1].GetUnsignedOriginatorEnvelope())
+}
+
+func TestPublishIdentityUpdatesFailsWithoutPartialFailure(t *testing.T) {
+	svc, mockMessagePublisher, _, _ := buildPayerService(t)
+
+	first, _ := newIdentityUpdateEnvelope(t)
+
+	mockMessagePublisher.EXPECT().
+		PublishIdentityUpdate(mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, errors.New("execution reverted")).
+		Once()
+
+	_, err := svc.PublishClientEnvelopes(
+		t.Context(),
+		connect.NewRequest(&payer_api.PublishClientEnvelopesRequest{
+			Envelopes: []*envelopesProto.ClientEnvelope{first},
+		}),
+	)
+	require.Error(t, err)
+	require.Equal(t, connect.CodeInternal, connect.CodeOf(err))
+}
+
 func TestPublishToNodes(t *testing.T) {
 	suite := apiTestUtils.NewTestAPIServer(t)
 
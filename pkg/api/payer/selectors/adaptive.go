@@ -0,0 +1,355 @@
+package selectors
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/xmtp/xmtpd/pkg/metrics"
+	"github.com/xmtp/xmtpd/pkg/registry"
+	"github.com/xmtp/xmtpd/pkg/topic"
+)
+
+// AdaptiveNodeSelectorConfig controls how the AdaptiveNodeSelectorAlgorithm scores and
+// ejects nodes.
+type AdaptiveNodeSelectorConfig struct {
+	// Window is the duration over which publish outcomes are aggregated.
+	Window time.Duration
+	// MaxSamples is the number of most recent outcomes kept per node.
+	MaxSamples int
+	// MinSamples is the number of outcomes required before the success rate and latency of
+	// a node are evaluated.
+	MinSamples int
+	// MinSuccessRate is the fraction of successful publishes below which a node is ejected.
+	MinSuccessRate float64
+	// MaxConsecutiveFailures is the number of failures in a row after which a node is
+	// ejected, regardless of MinSamples.
+	MaxConsecutiveFailures int
+	// BaseEjection is how long a node is ejected for the first time. It doubles with every
+	// consecutive ejection, up to MaxEjection.
+	BaseEjection time.Duration
+	MaxEjection  time.Duration
+	// LatencyTolerance is the factor of the best p95 latency within which nodes are
+	// considered equally fast.
+	LatencyTolerance float64
+	// LatencySlack is added to the tolerated latency, so nodes that are all fast are not
+	// told apart by a few milliseconds.
+	LatencySlack time.Duration
+}
+
+func DefaultAdaptiveNodeSelectorConfig() AdaptiveNodeSelectorConfig {
+	return AdaptiveNodeSelectorConfig{
+		Window:                 5 * time.Minute,
+		MaxSamples:             100,
+		MinSamples:             10,
+		MinSuccessRate:         0.5,
+		MaxConsecutiveFailures: 5,
+		BaseEjection:           10 * time.Second,
+		MaxEjection:            5 * time.Minute,
+		LatencyTolerance:       2,
+		LatencySlack:           50 * time.Millisecond,
+	}
+}
+
+type adaptiveSample struct {
+	at      time.Time
+	latency time.Duration
+	failed  bool
+}
+
+type adaptiveNodeState struct {
+	// samples is a ring buffer of the most recent outcomes, next is where the next one goes.
+	samples             []adaptiveSample
+	next                int
+	consecutiveFailures int
+	// ejections is the number of consecutive ejections, reset once the node succeeds after
+	// being re-admitted.
+	ejections    int
+	ejectedUntil time.Time
+	// probation is set when the node is re-admitted, and ejects it again on its first
+	// failure.
+	probation bool
+}
+
+// stats returns the number of outcomes within the window, their success rate and their
+// p95 latency.
+func (s *adaptiveNodeState) stats(
+	now time.Time,
+	window time.Duration,
+) (int, float64, time.Duration) {
+	var (
+		latencies = make([]time.Duration, 0, len(s.samples))
+		failures  int
+	)
+	for _, sample := range s.samples {
+		if now.Sub(sample.at) > window {
+			continue
+		}
+		latencies = append(latencies, sample.latency)
+		if sample.failed {
+			failures++
+		}
+	}
+
+	if len(latencies) == 0 {
+		return 0, 1, 0
+	}
+
+	slices.Sort(latencies)
+	p95 := latencies[(len(latencies)*95+99)/100-1]
+	successRate := float64(len(latencies)-failures) / float64(len(latencies))
+
+	return len(latencies), successRate, p95
+}
+
+// AdaptiveNodeSelectorAlgorithm selects nodes using the outcome of past publishes.
+//
+// Nodes failing too many publishes are ejected, and re-admitted after an ejection time
+// that doubles each time they fail again right after re-admission. Among the nodes that
+// are not ejected, and whose p95 publish latency is close to the fastest one, topics are
+// mapped to nodes with rendezvous hashing. A topic therefore sticks to the same node as
+// long as that node stays healthy, and only the topics of a node that is ejected move.
+type AdaptiveNodeSelectorAlgorithm struct {
+	reg registry.NodeRegistry
+	cfg AdaptiveNodeSelectorConfig
+	now func() time.Time
+
+	mu    sync.Mutex
+	nodes map[uint32]*adaptiveNodeState
+}
+
+var (
+	_ NodeSelectorAlgorithm = (*AdaptiveNodeSelectorAlgorithm)(nil)
+	_ NodeOutcomeRecorder   = (*AdaptiveNodeSelectorAlgorithm)(nil)
+)
+
+func NewAdaptiveNodeSelectorAlgorithm(
+	reg registry.NodeRegistry,
+	cfg AdaptiveNodeSelectorConfig,
+) *AdaptiveNodeSelectorAlgorithm {
+	return &AdaptiveNodeSelectorAlgorithm{
+		reg:   reg,
+		cfg:   cfg,
+		now:   time.Now,
+		nodes: make(map[uint32]*adaptiveNodeState),
+	}
+}
+
+// GetNode selects the node of topic among the healthy and fast nodes not in the banlist.
+// When every node is ejected, it selects among all nodes not in the banlist instead.
+func (a *AdaptiveNodeSelectorAlgorithm) GetNode(
+	topic topic.Topic,
+	banlist ...[]uint32,
+) (uint32, error) {
+	nodes, err := a.reg.GetNodes()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(nodes) == 0 {
+		return 0, errors.New("no available nodes")
+	}
+
+	a.forgetRemovedNodes(nodes)
+
+	banned := make(map[uint32]struct{})
+	for _, list := range banlist {
+		for _, id := range list {
+			banned[id] = struct{}{}
+		}
+	}
+
+	candidates := make([]uint32, 0, len(nodes))
+	for _, node := range nodes {
+		if _, isBanned := banned[node.NodeID]; !isBanned {
+			candidates = append(candidates, node.NodeID)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return 0, errors.New("no available nodes after considering banlist")
+	}
+
+	if eligible := a.eligibleNodes(candidates); len(eligible) > 0 {
+		candidates = eligible
+	}
+
+	return rendezvousNode(topic, candidates), nil
+}
+
+// forgetRemovedNodes drops the outcomes of the nodes that left the registry.
+func (a *AdaptiveNodeSelectorAlgorithm) forgetRemovedNodes(nodes []registry.Node) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	registered := make(map[uint32]struct{}, len(nodes))
+	for _, node := range nodes {
+		registered[node.NodeID] = struct{}{}
+	}
+	for nodeID := range a.nodes {
+		if _, ok := registered[nodeID]; !ok {
+			delete(a.nodes, nodeID)
+		}
+	}
+}
+
+// eligibleNodes returns the candidates that are not ejected, and whose p95 latency is
+// within the tolerance of the fastest candidate. Nodes with too few outcomes to be scored
+// are eligible.
+func (a *AdaptiveNodeSelectorAlgorithm) eligibleNodes(candidates []uint32) []uint32 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var (
+		now       = a.now()
+		healthy   = make([]uint32, 0, len(candidates))
+		latencies = make(map[uint32]time.Duration, len(candidates))
+		best      time.Duration
+	)
+
+	for _, nodeID := range candidates {
+		state, ok := a.nodes[nodeID]
+		if !ok {
+			healthy = append(healthy, nodeID)
+			continue
+		}
+		if now.Before(state.ejectedUntil) {
+			continue
+		}
+		healthy = append(healthy, nodeID)
+
+		count, _, p95 := state.stats(now, a.cfg.Window)
+		if count < a.cfg.MinSamples {
+			continue
+		}
+		latencies[nodeID] = p95
+		if best == 0 || p95 < best {
+			best = p95
+		}
+	}
+
+	tolerated := time.Duration(float64(best)*a.cfg.LatencyTolerance) + a.cfg.LatencySlack
+
+	eligible := make([]uint32, 0, len(healthy))
+	for _, nodeID := range healthy {
+		if p95, scored := latencies[nodeID]; !scored || p95 <= tolerated {
+			eligible = append(eligible, nodeID)
+		}
+	}
+
+	return eligible
+}
+
+// RecordSuccess records a publish to the node that succeeded after latency. A success
+// re-admits a node on probation for good.
+func (a *AdaptiveNodeSelectorAlgorithm) RecordSuccess(nodeID uint32, latency time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// Outcomes of publishes that started before the node was ejected are ignored, so they
+	// do not re-admit it early.
+	if state, ok := a.nodes[nodeID]; ok && a.now().Before(state.ejectedUntil) {
+		return
+	}
+
+	state := a.record(nodeID, latency, false)
+	state.consecutiveFailures = 0
+	if state.probation {
+		state.probation = false
+		state.ejections = 0
+		state.ejectedUntil = time.Time{}
+	}
+}
+
+// RecordFailure records a publish to the node that failed after latency, ejecting the node
+// if it is failing too often.
+func (a *AdaptiveNodeSelectorAlgorithm) RecordFailure(nodeID uint32, latency time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+
+	// Outcomes of publishes that started before the node was ejected are ignored.
+	if state, ok := a.nodes[nodeID]; ok && now.Before(state.ejectedUntil) {
+		return
+	}
+
+	state := a.record(nodeID, latency, true)
+	state.consecutiveFailures++
+
+	count, successRate, _ := state.stats(now, a.cfg.Window)
+	if state.probation ||
+		state.consecutiveFailures >= a.cfg.MaxConsecutiveFailures ||
+		(count >= a.cfg.MinSamples && successRate < a.cfg.MinSuccessRate) {
+		a.eject(nodeID, state, now)
+	}
+}
+
+func (a *AdaptiveNodeSelectorAlgorithm) record(
+	nodeID uint32,
+	latency time.Duration,
+	failed bool,
+) *adaptiveNodeState {
+	state, ok := a.nodes[nodeID]
+	if !ok {
+		state = &adaptiveNodeState{}
+		a.nodes[nodeID] = state
+	}
+
+	sample := adaptiveSample{at: a.now(), latency: latency, failed: failed}
+	if len(state.samples) < max(a.cfg.MaxSamples, 1) {
+		state.samples = append(state.samples, sample)
+	} else {
+		state.samples[state.next] = sample
+	}
+	state.next = (state.next + 1) % max(a.cfg.MaxSamples, 1)
+
+	return state
+}
+
+// eject excludes the node from selection until its ejection time has passed. The outcomes
+// of the node are cleared, so it is scored afresh once re-admitted.
+func (a *AdaptiveNodeSelectorAlgorithm) eject(
+	nodeID uint32,
+	state *adaptiveNodeState,
+	now time.Time,
+) {
+	ejection := a.cfg.BaseEjection << min(state.ejections, 30)
+	if ejection <= 0 || ejection > a.cfg.MaxEjection {
+		ejection = a.cfg.MaxEjection
+	}
+
+	state.ejections++
+	state.ejectedUntil = now.Add(ejection)
+	state.probation = true
+	state.consecutiveFailures = 0
+	state.samples = state.samples[:0]
+	state.next = 0
+
+	metrics.EmitGatewayNodeEjected(nodeID, ejection)
+}
+
+// rendezvousNode returns the candidate with the highest hash of the topic identifier and
+// its node ID. Removing a candidate only moves the topics that were mapped to it.
+func rendezvousNode(topic topic.Topic, candidates []uint32) uint32 {
+	var (
+		selected  uint32
+		bestScore uint64
+		buf       = make([]byte, len(topic.Identifier())+4)
+	)
+
+	copy(buf, topic.Identifier())
+	for i, nodeID := range candidates {
+		binary.BigEndian.PutUint32(buf[len(buf)-4:], nodeID)
+		hash := sha256.Sum256(buf)
+		score := binary.BigEndian.Uint64(hash[:8])
+		if i == 0 || score > bestScore {
+			selected, bestScore = nodeID, score
+		}
+	}
+
+	return selected
+}
@@ -0,0 +1,217 @@
+package selectors
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/registry"
+	registryMocks "github.com/xmtp/xmtpd/pkg/testutils/mocks/registry"
+	nodeRegistry "github.com/xmtp/xmtpd/pkg/testutils/registry"
+	"github.com/xmtp/xmtpd/pkg/topic"
+)
+
+func newTestAdaptiveSelector(
+	t *testing.T,
+	now *time.Time,
+	nodeIDs ...uint32,
+) *AdaptiveNodeSelectorAlgorithm {
+	nodes := make([]registry.Node, len(nodeIDs))
+	for i, nodeID := range nodeIDs {
+		nodes[i] = nodeRegistry.GetHealthyNode(nodeID)
+	}
+
+	mockRegistry := registryMocks.NewMockNodeRegistry(t)
+	mockRegistry.On("GetNodes").Return(nodes, nil).Maybe()
+
+	selector := NewAdaptiveNodeSelectorAlgorithm(mockRegistry, DefaultAdaptiveNodeSelectorConfig())
+	selector.now = func() time.Time { return *now }
+	return selector
+}
+
+func adaptiveTestTopic(i int) topic.Topic {
+	return *topic.NewTopic(topic.TopicKindGroupMessagesV1, fmt.Appendf(nil, "topic-%d", i))
+}
+
+func failNode(selector *AdaptiveNodeSelectorAlgorithm, nodeID uint32, times int) {
+	for range times {
+		selector.RecordFailure(nodeID, 10*time.Millisecond)
+	}
+}
+
+func TestAdaptiveNodeSelector_StickyTopic(t *testing.T) {
+	now := time.Now()
+	selector := newTestAdaptiveSelector(t, &now, 100, 200, 300)
+
+	for i := range 20 {
+		tpc := adaptiveTestTopic(i)
+		first, err := selector.GetNode(tpc)
+		require.NoError(t, err)
+		second, err := selector.GetNode(tpc)
+		require.NoError(t, err)
+		require.Equal(t, first, second)
+	}
+}
+
+func TestAdaptiveNodeSelector_EjectionOnlyMovesTopicsOfEjectedNode(t *testing.T) {
+	now := time.Now()
+	selector := newTestAdaptiveSelector(t, &now, 100, 200, 300)
+
+	before := make(map[int]uint32)
+	for i := range 50 {
+		nodeID, err := selector.GetNode(adaptiveTestTopic(i))
+		require.NoError(t, err)
+		before[i] = nodeID
+	}
+
+	failNode(selector, 200, 5)
+
+	for i, previous := range before {
+		nodeID, err := selector.GetNode(adaptiveTestTopic(i))
+		require.NoError(t, err)
+		require.NotEqual(t, uint32(200), nodeID)
+		if previous != 200 {
+			require.Equal(t, previous, nodeID, "topic %d moved off a healthy node", i)
+		}
+	}
+
+	// Once re-admitted, the node gets its topics back.
+	now = now.Add(10 * time.Second)
+	for i, previous := range before {
+		nodeID, err := selector.GetNode(adaptiveTestTopic(i))
+		require.NoError(t, err)
+		require.Equal(t, previous, nodeID)
+	}
+}
+
+func TestAdaptiveNodeSelector_ExponentialReadmission(t *testing.T) {
+	now := time.Now()
+	selector := newTestAdaptiveSelector(t, &now, 100)
+
+	isEjected := func() bool {
+		return len(selector.eligibleNodes([]uint32{100})) == 0
+	}
+
+	failNode(selector, 100, 4)
+	require.False(t, isEjected())
+	failNode(selector, 100, 1)
+	require.True(t, isEjected())
+
+	now = now.Add(10 * time.Second)
+	require.False(t, isEjected())
+
+	// A node on probation is ejected again on its first failure, for twice as long.
+	failNode(selector, 100, 1)
+	require.True(t, isEjected())
+	now = now.Add(19 * time.Second)
+	require.True(t, isEjected())
+	now = now.Add(1 * time.Second)
+	require.False(t, isEjected())
+
+	// A success re-admits the node for good, so the next ejection is short again.
+	selector.RecordSuccess(100, 10*time.Millisecond)
+	failNode(selector, 100, 5)
+	require.True(t, isEjected())
+	now = now.Add(10 * time.Second)
+	require.False(t, isEjected())
+}
+
+func TestAdaptiveNodeSelector_LowSuccessRate(t *testing.T) {
+	now := time.Now()
+	selector := newTestAdaptiveSelector(t, &now, 100)
+
+	for range 5 {
+		selector.RecordSuccess(100, 10*time.Millisecond)
+		failNode(selector, 100, 1)
+	}
+	require.NotEmpty(t, selector.eligibleNodes([]uint32{100}))
+
+	failNode(selector, 100, 1)
+	require.Empty(t, selector.eligibleNodes([]uint32{100}))
+}
+
+func TestAdaptiveNodeSelector_AvoidsSlowNodes(t *testing.T) {
+	now := time.Now()
+	selector := newTestAdaptiveSelector(t, &now, 100, 200, 300)
+
+	for range 10 {
+		selector.RecordSuccess(100, 20*time.Millisecond)
+		selector.RecordSuccess(200, 30*time.Millisecond)
+		selector.RecordSuccess(300, 2*time.Second)
+	}
+
+	require.ElementsMatch(t, []uint32{100, 200}, selector.eligibleNodes([]uint32{100, 200, 300}))
+	for i := range 50 {
+		nodeID, err := selector.GetNode(adaptiveTestTopic(i))
+		require.NoError(t, err)
+		require.NotEqual(t, uint32(300), nodeID)
+	}
+
+	// Outcomes older than the window are forgotten.
+	now = now.Add(6 * time.Minute)
+	require.Len(t, selector.eligibleNodes([]uint32{100, 200, 300}), 3)
+}
+
+func TestAdaptiveNodeSelector_AllEjected(t *testing.T) {
+	now := time.Now()
+	selector := newTestAdaptiveSelector(t, &now, 100, 200)
+
+	failNode(selector, 100, 5)
+	failNode(selector, 200, 5)
+
+	nodeID, err := selector.GetNode(adaptiveTestTopic(0))
+	require.NoError(t, err)
+	require.Contains(t, []uint32{100, 200}, nodeID)
+
+	nodeID, err = selector.GetNode(adaptiveTestTopic(0), []uint32{100})
+	require.NoError(t, err)
+	require.Equal(t, uint32(200), nodeID)
+
+	_, err = selector.GetNode(adaptiveTestTopic(0), []uint32{100, 200})
+	require.Error(t, err)
+}
+
+func TestAdaptiveNodeSelector_IgnoresSuccessesWhileEjected(t *testing.T) {
+	now := time.Now()
+	selector := newTestAdaptiveSelector(t, &now, 100)
+
+	failNode(selector, 100, 5)
+	require.Empty(t, selector.eligibleNodes([]uint32{100}))
+
+	// A publish that started before the ejection does not re-admit the node early, nor
+	// take it off probation.
+	selector.RecordSuccess(100, 10*time.Millisecond)
+	require.Empty(t, selector.eligibleNodes([]uint32{100}))
+
+	now = now.Add(10 * time.Second)
+	failNode(selector, 100, 1)
+	require.Empty(t, selector.eligibleNodes([]uint32{100}))
+}
+
+func TestAdaptiveNodeSelector_ForgetsRemovedNodes(t *testing.T) {
+	now := time.Now()
+	nodes := []registry.Node{
+		nodeRegistry.GetHealthyNode(100),
+		nodeRegistry.GetHealthyNode(200),
+	}
+
+	mockRegistry := registryMocks.NewMockNodeRegistry(t)
+	mockRegistry.On("GetNodes").Return(nodes, nil).Once()
+	mockRegistry.On("GetNodes").Return(nodes[:1], nil).Once()
+
+	selector := NewAdaptiveNodeSelectorAlgorithm(mockRegistry, DefaultAdaptiveNodeSelectorConfig())
+	selector.now = func() time.Time { return now }
+
+	selector.RecordSuccess(100, 10*time.Millisecond)
+	selector.RecordSuccess(200, 10*time.Millisecond)
+
+	_, err := selector.GetNode(adaptiveTestTopic(0))
+	require.NoError(t, err)
+	require.Len(t, selector.nodes, 2)
+
+	_, err = selector.GetNode(adaptiveTestTopic(0))
+	require.NoError(t, err)
+	require.Len(t, selector.nodes, 1)
+	require.Contains(t, selector.nodes, uint32(100))
+}
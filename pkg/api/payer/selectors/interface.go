@@ -13,14 +13,22 @@ type NodeSelectorAlgorithm interface {
 	GetNode(topic topic.Topic, banlist ...[]uint32) (uint32, error)
 }
 
+// NodeOutcomeRecorder is implemented by selectors that take the outcome of publishing to
+// the nodes they selected into account.
+type NodeOutcomeRecorder interface {
+	RecordSuccess(nodeID uint32, latency time.Duration)
+	RecordFailure(nodeID uint32, latency time.Duration)
+}
+
 type NodeSelectorStrategy string
 
 const (
-	NodeSelectorStrategyStable  NodeSelectorStrategy = "stable"
-	NodeSelectorStrategyManual  NodeSelectorStrategy = "manual"
-	NodeSelectorStrategyOrdered NodeSelectorStrategy = "ordered"
-	NodeSelectorStrategyRandom  NodeSelectorStrategy = "random"
-	NodeSelectorStrategyClosest NodeSelectorStrategy = "closest"
+	NodeSelectorStrategyStable   NodeSelectorStrategy = "stable"
+	NodeSelectorStrategyManual   NodeSelectorStrategy = "manual"
+	NodeSelectorStrategyOrdered  NodeSelectorStrategy = "ordered"
+	NodeSelectorStrategyRandom   NodeSelectorStrategy = "random"
+	NodeSelectorStrategyClosest  NodeSelectorStrategy = "closest"
+	NodeSelectorStrategyAdaptive NodeSelectorStrategy = "adaptive"
 )
 
 type NodeSelectorConfig struct {
@@ -28,6 +36,8 @@ type NodeSelectorConfig struct {
 	PreferredNodes []uint32
 	CacheExpiry    time.Duration
 	ConnectTimeout time.Duration
+	// Adaptive configures the adaptive strategy. The defaults are used when it is unset.
+	Adaptive *AdaptiveNodeSelectorConfig
 }
 
 func NewNodeSelector(
@@ -56,6 +66,12 @@ func NewNodeSelector(
 			config.ConnectTimeout,
 			config.PreferredNodes,
 		), nil
+	case NodeSelectorStrategyAdaptive:
+		adaptiveConfig := DefaultAdaptiveNodeSelectorConfig()
+		if config.Adaptive != nil {
+			adaptiveConfig = *config.Adaptive
+		}
+		return NewAdaptiveNodeSelectorAlgorithm(reg, adaptiveConfig), nil
 	default:
 		return nil, fmt.Errorf("unknown node selector strategy: %s", config.Strategy)
 	}
@@ -615,6 +615,17 @@ func TestNewNodeSelector_ClosestStrategy(t *testing.T) {
 	require.NotNil(t, selector)
 }
 
+func TestNewNodeSelector_AdaptiveStrategy(t *testing.T) {
+	mockRegistry := registryMocks.NewMockNodeRegistry(t)
+
+	selector, err := selectors.NewNodeSelector(mockRegistry, selectors.NodeSelectorConfig{
+		Strategy: selectors.NodeSelectorStrategyAdaptive,
+	})
+	require.NoError(t, err)
+	require.IsType(t, &selectors.AdaptiveNodeSelectorAlgorithm{}, selector)
+	require.Implements(t, (*selectors.NodeOutcomeRecorder)(nil), selector)
+}
+
 func TestNewNodeSelector_DefaultStrategy(t *testing.T) {
 	mockRegistry := registryMocks.NewMockNodeRegistry(t)
 	mockRegistry.On("GetNodes").Return([]registry.Node{
@@ -7,6 +7,7 @@ import (
 	"crypto/ecdsa"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/xmtp/xmtpd/pkg/api/payer/selectors"
@@ -22,6 +23,7 @@ import (
 	"github.com/xmtp/xmtpd/pkg/blockchain"
 	"github.com/xmtp/xmtpd/pkg/constants"
 	"github.com/xmtp/xmtpd/pkg/envelopes"
+	"github.com/xmtp/xmtpd/pkg/misbehavior"
 	"github.com/xmtp/xmtpd/pkg/proto/identity/associations"
 	envelopesProto "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/envelopes"
 	gateway_apiconnect "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/gateway_api/gateway_apiconnect"
@@ -32,6 +34,7 @@ import (
 	"github.com/xmtp/xmtpd/pkg/topic"
 	"github.com/xmtp/xmtpd/pkg/utils"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
@@ -52,6 +55,9 @@ type Service struct {
 	nodeSelector        selectors.NodeSelectorAlgorithm
 	nodeRegistry        registry.NodeRegistry
 	maxPayerMessageSize uint64
+	blockchainBatcher   *blockchainBatcher
+	outboxNotify        chan struct{}
+	wg                  sync.WaitGroup
 }
 
 var (
@@ -83,9 +89,17 @@ func NewPayerAPIService(
 		nodeSelector = selectors.NewStableHashingNodeSelectorAlgorithm(nodeRegistry)
 	}
 
+	if cfg.LivenessTracker == nil {
+		cfg.LivenessTracker = misbehavior.NewLivenessTracker(
+			logger,
+			misbehavior.NewLoggingMisbehaviorService(logger),
+			misbehavior.DefaultLivenessConfig(),
+		)
+	}
+
 	clientManager := NewClientManager(logger, nodeRegistry, clientMetrics)
 
-	return &Service{
+	s := &Service{
 		cfg:                 cfg,
 		ctx:                 ctx,
 		logger:              logger,
@@ -95,7 +109,24 @@ func NewPayerAPIService(
 		nodeSelector:        nodeSelector,
 		nodeRegistry:        nodeRegistry,
 		maxPayerMessageSize: maxPayerMessageSize,
-	}, nil
+	}
+
+	if cfg.BlockchainBatchWindow > 0 {
+		s.blockchainBatcher = newBlockchainBatcher(
+			ctx,
+			&s.wg,
+			cfg.BlockchainBatchWindow,
+			cfg.BlockchainBatchMaxSize,
+			s.publishBatchToBlockchain,
+		)
+	}
+
+	if cfg.Outbox != nil {
+		s.outboxNotify = make(chan struct{}, 1)
+		s.replayOutbox()
+	}
+
+	return s, nil
 }
 
 // GetNodes returns the complete endpoint list of canonical nodes.
@@ -167,63 +198,261 @@ func (s *Service) PublishClientEnvelopes(
 		}
 	}
 
+	if s.cfg.Outbox != nil {
+		return s.publishThroughOutbox(ctx, req.Msg, grouped)
+	}
+
+	if req.Msg.GetAsync() {
+		return nil, connect.NewError(
+			connect.CodeFailedPrecondition,
+			errors.New("async publishing requires the gateway publish outbox"),
+		)
+	}
+
+	var (
+		partialFailure = req.Msg.GetAllowPartialFailure()
+		results        = newPublishResults(len(req.Msg.GetEnvelopes()))
+	)
+
+	// Unless partial failure is allowed, the first destination to fail cancels the others.
+	publishCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if !partialFailure {
+		results.onFailure = cancel
+	}
+
+	s.publishToDestinations(publishCtx, ctx, grouped, results)
+
+	return newPublishResponse(results, partialFailure)
+}
+
+// newPublishResponse builds the response of a request from the outcome of its envelopes.
+// Unless partial failure is allowed, the request fails with the first envelope to fail.
+func newPublishResponse(
+	results *publishResults,
+	partialFailure bool,
+) (*connect.Response[payer_api.PublishClientEnvelopesResponse], error) {
+	if !partialFailure && results.firstErr != nil {
+		return nil, results.firstErr
+	}
+
 	response := connect.NewResponse(&payer_api.PublishClientEnvelopesResponse{
-		OriginatorEnvelopes: make(
-			[]*envelopesProto.OriginatorEnvelope,
-			len(req.Msg.GetEnvelopes()),
-		),
+		OriginatorEnvelopes: results.envelopes,
 	})
 
+	if partialFailure {
+		response.Msg.EnvelopeStatuses = make(
+			[]*payer_api.PublishClientEnvelopesResponse_EnvelopeStatus,
+			len(results.errs),
+		)
+		for i, err := range results.errs {
+			response.Msg.EnvelopeStatuses[i] = newEnvelopeStatus(err)
+			if err != nil {
+				response.Msg.OriginatorEnvelopes[i] = &envelopesProto.OriginatorEnvelope{}
+			}
+		}
+	}
+
+	return response, nil
+}
+
+// publishResults collects the outcome of publishing each envelope of a request, indexed
+// like the request.
+type publishResults struct {
+	mu        sync.Mutex
+	envelopes []*envelopesProto.OriginatorEnvelope
+	errs      []error
+	firstErr  error
+	// onFailure is called when the first envelope fails.
+	onFailure func()
+}
+
+func newPublishResults(numEnvelopes int) *publishResults {
+	return &publishResults{
+		envelopes: make([]*envelopesProto.OriginatorEnvelope, numEnvelopes),
+		errs:      make([]error, numEnvelopes),
+	}
+}
+
+func (r *publishResults) succeed(index int, envelope *envelopesProto.OriginatorEnvelope) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.envelopes[index] = envelope
+}
+
+func (r *publishResults) fail(index int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs[index] = err
+	if r.firstErr == nil {
+		r.firstErr = err
+		if r.onFailure != nil {
+			r.onFailure()
+		}
+	}
+}
+
+func newEnvelopeStatus(err error) *payer_api.PublishClientEnvelopesResponse_EnvelopeStatus {
+	if err == nil {
+		return &payer_api.PublishClientEnvelopesResponse_EnvelopeStatus{}
+	}
+
+	status := &payer_api.PublishClientEnvelopesResponse_EnvelopeStatus{
+		Code:    uint32(connect.CodeOf(err)),
+		Message: err.Error(),
+	}
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		status.Message = connectErr.Message()
+	}
+	return status
+}
+
+// publishToDestinations publishes the envelopes of each originator node, and the envelopes
+// bound for the blockchain, concurrently. At most PublishParallelism destinations are
+// published to at a time. reqCtx is the context of the request, which tells a canceled
+// request apart from a canceled destination.
+func (s *Service) publishToDestinations(
+	ctx context.Context,
+	reqCtx context.Context,
+	grouped *groupedEnvelopes,
+	results *publishResults,
+) {
+	var group errgroup.Group
+	group.SetLimit(max(s.cfg.PublishParallelism, 1))
+
 	// For each originator found in the request, publish all matching envelopes to the node
 	for originatorID, payloadsWithIndex := range grouped.forNodes {
-		s.logger.Debug(
-			"publishing to originator",
-			utils.OriginatorIDField(originatorID),
-			utils.MethodField(req.Spec().Procedure),
-			utils.NumEnvelopesField(len(payloadsWithIndex)),
-		)
+		group.Go(func() error {
+			s.publishToDestinationNode(ctx, reqCtx, originatorID, payloadsWithIndex, results)
+			return nil
+		})
+	}
 
-		originatorEnvelopes, err := s.publishToNodeWithRetry(ctx, originatorID, payloadsWithIndex)
-		if err != nil {
-			if ctx.Err() != nil {
-				return nil, connect.NewError(
-					connect.CodeCanceled,
-					errors.New("request canceled by client"),
-				)
-			}
+	if len(grouped.forBlockchain) > 0 {
+		group.Go(func() error {
+			s.publishToDestinationBlockchain(ctx, grouped.forBlockchain, results)
+			return nil
+		})
+	}
 
-			return nil, connect.NewError(
+	_ = group.Wait()
+}
+
+// publishToDestinationNode publishes envelopes to an originator node, falling back to other
+// nodes on failure. The destination is given a deadline of PublishTimeout per retry, so a
+// slow originator does not hold up the request for longer than its own retries allow.
+func (s *Service) publishToDestinationNode(
+	ctx context.Context,
+	reqCtx context.Context,
+	originatorID uint32,
+	payloadsWithIndex []clientEnvelopeWithIndex,
+	results *publishResults,
+) {
+	s.logger.Debug(
+		"publishing to originator",
+		utils.OriginatorIDField(originatorID),
+		utils.NumEnvelopesField(len(payloadsWithIndex)),
+	)
+
+	deadline := s.cfg.PublishTimeout * time.Duration(cmp.Or(s.cfg.PublishRetries, 1))
+	ctx, cancel := context.WithTimeout(ctx, deadline)
+	defer cancel()
+
+	originatorEnvelopes, err := s.publishToNodeWithRetry(ctx, originatorID, payloadsWithIndex)
+	if err == nil && len(originatorEnvelopes) != len(payloadsWithIndex) {
+		err = fmt.Errorf(
+			"received %d originator envelopes for %d payer envelopes",
+			len(originatorEnvelopes),
+			len(payloadsWithIndex),
+		)
+	}
+	if err != nil {
+		if reqCtx.Err() != nil {
+			err = connect.NewError(
+				connect.CodeCanceled,
+				errors.New("request canceled by client"),
+			)
+		} else {
+			err = connect.NewError(
 				connect.CodeInternal,
 				fmt.Errorf("error publishing payer envelopes: %w", err),
 			)
 		}
+		for _, payload := range payloadsWithIndex {
+			results.fail(payload.originalIndex, err)
+		}
+		return
+	}
 
-		// The originator envelopes come back from the API in the same order as the request
-		for idx, originatorEnvelope := range originatorEnvelopes {
-			response.Msg.OriginatorEnvelopes[payloadsWithIndex[idx].originalIndex] = originatorEnvelope
+	// The originator envelopes come back from the API in the same order as the request
+	for idx, originatorEnvelope := range originatorEnvelopes {
+		results.succeed(payloadsWithIndex[idx].originalIndex, originatorEnvelope)
+	}
+}
+
+// publishToDestinationBlockchain publishes envelopes to the blockchain in request order.
+// An envelope following a failed envelope on the same topic is not published.
+func (s *Service) publishToDestinationBlockchain(
+	ctx context.Context,
+	payloads []clientEnvelopeWithIndex,
+	results *publishResults,
+) {
+	if s.blockchainBatcher != nil {
+		clientEnvelopes := make([]*envelopes.ClientEnvelope, len(payloads))
+		for i, payload := range payloads {
+			clientEnvelopes[i] = payload.payload
 		}
+
+		originatorEnvelopes, errs := s.blockchainBatcher.submit(ctx, clientEnvelopes)
+		for i, payload := range payloads {
+			if errs[i] != nil {
+				s.logger.Error("error publishing payer envelopes", zap.Error(errs[i]))
+				results.fail(payload.originalIndex, connect.NewError(
+					connect.CodeInternal,
+					fmt.Errorf("error publishing to blockchain: %w", errs[i]),
+				))
+				continue
+			}
+			results.succeed(payload.originalIndex, originatorEnvelopes[i])
+		}
+		return
 	}
 
-	for _, payload := range grouped.forBlockchain {
-		s.logger.Debug(
-			"publishing to blockchain",
-			utils.MethodField(req.Spec().Procedure),
-			utils.TopicField(payload.payload.TargetTopic().String()),
-		)
+	failedTopics := make(map[string]struct{})
+	for _, payload := range payloads {
+		targetTopic := payload.payload.TargetTopic().String()
 
-		var originatorEnvelope *envelopesProto.OriginatorEnvelope
-		if originatorEnvelope, err = s.publishToBlockchain(ctx, payload.payload); err != nil {
+		if ctx.Err() != nil {
+			results.fail(payload.originalIndex, connect.NewError(
+				connect.CodeCanceled,
+				errors.New("request canceled"),
+			))
+			continue
+		}
+		if _, ok := failedTopics[targetTopic]; ok {
+			results.fail(payload.originalIndex, connect.NewError(
+				connect.CodeAborted,
+				errors.New("an earlier envelope on the same topic failed to publish"),
+			))
+			continue
+		}
+
+		s.logger.Debug("publishing to blockchain", utils.TopicField(targetTopic))
+
+		originatorEnvelope, err := s.publishToBlockchain(ctx, payload.payload)
+		if err != nil {
 			s.logger.Error("error publishing payer envelopes", zap.Error(err))
-			return nil, connect.NewError(
+			failedTopics[targetTopic] = struct{}{}
+			results.fail(payload.originalIndex, connect.NewError(
 				connect.CodeInternal,
 				fmt.Errorf("error publishing group message: %w", err),
-			)
+			))
+			continue
 		}
 
-		response.Msg.OriginatorEnvelopes[payload.originalIndex] = originatorEnvelope
+		results.succeed(payload.originalIndex, originatorEnvelope)
 	}
-
-	return response, nil
 }
 
 // A struct that groups client envelopes by their intended destination.
@@ -234,57 +463,94 @@ type groupedEnvelopes struct {
 	forBlockchain []clientEnvelopeWithIndex
 }
 
+// filter returns the envelopes whose index in the request is kept.
+func (g *groupedEnvelopes) filter(keep func(index int) bool) *groupedEnvelopes {
+	out := groupedEnvelopes{forNodes: make(map[uint32][]clientEnvelopeWithIndex)}
+
+	for originatorID, payloads := range g.forNodes {
+		for _, payload := range payloads {
+			if keep(payload.originalIndex) {
+				out.forNodes[originatorID] = append(out.forNodes[originatorID], payload)
+			}
+		}
+	}
+
+	for _, payload := range g.forBlockchain {
+		if keep(payload.originalIndex) {
+			out.forBlockchain = append(out.forBlockchain, payload)
+		}
+	}
+
+	return &out
+}
+
 func (s *Service) groupEnvelopes(
 	rawEnvelopes []*envelopesProto.ClientEnvelope,
 ) (*groupedEnvelopes, error) {
-	out := groupedEnvelopes{forNodes: make(map[uint32][]clientEnvelopeWithIndex)}
+	out := &groupedEnvelopes{forNodes: make(map[uint32][]clientEnvelopeWithIndex)}
 
 	for i, rawClientEnvelope := range rawEnvelopes {
-		clientEnvelope, err := envelopes.NewClientEnvelope(rawClientEnvelope)
-		if err != nil {
-			return nil, connect.NewError(
-				connect.CodeInvalidArgument,
-				fmt.Errorf("invalid client envelope at index %d: %w", i, err),
-			)
+		if err := s.groupEnvelope(out, i, rawClientEnvelope); err != nil {
+			return nil, err
 		}
+	}
 
-		if !clientEnvelope.TopicMatchesPayload() {
-			return nil, connect.NewError(
-				connect.CodeInvalidArgument,
-				fmt.Errorf("client envelope at index %d does not match topic", i),
-			)
-		}
+	return out, nil
+}
 
-		toBlockchain, err := shouldSendToBlockchain(clientEnvelope)
-		if err != nil {
-			return nil, connect.NewError(
-				connect.CodeInvalidArgument,
-				fmt.Errorf("client envelope at index %d can not be parsed: %w", i, err),
-			)
-		}
+// groupEnvelope adds the envelope at index to out, under the blockchain or the node it
+// is published to. Envelopes that are invalid fail with InvalidArgument, while failing to
+// select a node is reported as Unavailable so it can be retried.
+func (s *Service) groupEnvelope(
+	out *groupedEnvelopes,
+	index int,
+	rawClientEnvelope *envelopesProto.ClientEnvelope,
+) error {
+	clientEnvelope, err := envelopes.NewClientEnvelope(rawClientEnvelope)
+	if err != nil {
+		return connect.NewError(
+			connect.CodeInvalidArgument,
+			fmt.Errorf("invalid client envelope at index %d: %w", index, err),
+		)
+	}
 
-		if toBlockchain {
-			out.forBlockchain = append(
-				out.forBlockchain,
-				newClientEnvelopeWithIndex(i, clientEnvelope),
-			)
-		} else {
-			targetNodeID, err := s.nodeSelector.GetNode(clientEnvelope.TargetTopic())
-			if err != nil {
-				return nil, connect.NewError(
-					connect.CodeInvalidArgument,
-					fmt.Errorf("error getting node for topic: %w", err),
-				)
-			}
+	if !clientEnvelope.TopicMatchesPayload() {
+		return connect.NewError(
+			connect.CodeInvalidArgument,
+			fmt.Errorf("client envelope at index %d does not match topic", index),
+		)
+	}
 
-			out.forNodes[targetNodeID] = append(
-				out.forNodes[targetNodeID],
-				newClientEnvelopeWithIndex(i, clientEnvelope),
-			)
-		}
+	toBlockchain, err := shouldSendToBlockchain(clientEnvelope)
+	if err != nil {
+		return connect.NewError(
+			connect.CodeInvalidArgument,
+			fmt.Errorf("client envelope at index %d can not be parsed: %w", index, err),
+		)
+	}
+
+	if toBlockchain {
+		out.forBlockchain = append(
+			out.forBlockchain,
+			newClientEnvelopeWithIndex(index, clientEnvelope),
+		)
+		return nil
+	}
+
+	targetNodeID, err := s.nodeSelector.GetNode(clientEnvelope.TargetTopic())
+	if err != nil {
+		return connect.NewError(
+			connect.CodeUnavailable,
+			fmt.Errorf("error getting node for topic: %w", err),
+		)
 	}
 
-	return &out, nil
+	out.forNodes[targetNodeID] = append(
+		out.forNodes[targetNodeID],
+		newClientEnvelopeWithIndex(index, clientEnvelope),
+	)
+
+	return nil
 }
 
 func (s *Service) publishToNodeWithRetry(
@@ -307,14 +573,21 @@ func (s *Service) publishToNodeWithRetry(
 		nctx, cancel := context.WithTimeout(ctx, s.cfg.PublishTimeout)
 		defer cancel()
 
+		start := time.Now()
 		result, err = s.publishToNode(nctx, nodeID, indexedEnvelopes)
 		if err == nil {
+			s.recordNodeSuccess(nodeID, time.Since(start))
 			if retries != 0 {
 				metrics.EmitGatewayBanlistRetries(originatorID, int(retries))
 			}
 			return result, nil
 		}
 
+		// Don't retry or ban nodes once the destination was cancelled or ran out of time.
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
 		// Don't retry or ban nodes if context was cancelled,
 		// but a deadline is treated as the nodes fault.
 		nctxErr := nctx.Err()
@@ -323,6 +596,8 @@ func (s *Service) publishToNodeWithRetry(
 			return nil, err
 		}
 
+		s.recordNodeFailure(nodeID, time.Since(start), err)
+
 		s.logger.Error(
 			"error publishing to node, will retry with the next one",
 			utils.OriginatorIDField(nodeID),
@@ -342,6 +617,43 @@ func (s *Service) publishToNodeWithRetry(
 	return nil, err
 }
 
+// recordNodeSuccess reports a successful publish to the liveness tracker, and to the node
+// selector if it learns from publish outcomes.
+func (s *Service) recordNodeSuccess(nodeID uint32, latency time.Duration) {
+	s.cfg.LivenessTracker.RecordSuccess(nodeID, latency)
+	if recorder, ok := s.nodeSelector.(selectors.NodeOutcomeRecorder); ok {
+		recorder.RecordSuccess(nodeID, latency)
+	}
+}
+
+// recordNodeFailure reports a failed publish to the liveness tracker, and to the node
+// selector if it learns from publish outcomes. Only failures that reflect on the health of
+// the node are reported to the node selector, so a node rejecting invalid envelopes is not
+// ejected.
+func (s *Service) recordNodeFailure(nodeID uint32, latency time.Duration, err error) {
+	s.cfg.LivenessTracker.RecordFailure(nodeID, latency, err)
+	if recorder, ok := s.nodeSelector.(selectors.NodeOutcomeRecorder); ok &&
+		isNodeHealthFailure(err) {
+		recorder.RecordFailure(nodeID, latency)
+	}
+}
+
+// isNodeHealthFailure reports whether a publish to a node failed because of the node. The
+// code returned by the node takes precedence over the code the error was wrapped with.
+func isNodeHealthFailure(err error) bool {
+	code := codes.Code(connect.CodeOf(err))
+	if st, ok := status.FromError(err); ok {
+		code = st.Code()
+	}
+
+	switch code {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
 func (s *Service) publishToNode(
 	ctx context.Context,
 	originatorID uint32,
@@ -408,8 +720,11 @@ func (s *Service) publishToBlockchain(
 		)
 	}
 
-	var unsignedOriginatorEnvelope *envelopesProto.UnsignedOriginatorEnvelope
-	var hash common.Hash
+	var (
+		hash       common.Hash
+		sequenceID uint64
+		message    []byte
+	)
 	switch kind {
 	case topic.TopicKindGroupMessagesV1:
 		desiredOriginatorID = constants.GroupMessageOriginatorID
@@ -444,17 +759,8 @@ func (s *Service) publishToBlockchain(
 		}
 
 		hash = logMessage.Raw.TxHash
-		unsignedOriginatorEnvelope, err = buildUnsignedOriginatorEnvelopeFromChain(
-			desiredOriginatorID,
-			logMessage.SequenceId,
-			logMessage.Message,
-		)
-		if err != nil {
-			return nil, connect.NewError(
-				connect.CodeInternal,
-				fmt.Errorf("error building unsigned originator envelope: %w", err),
-			)
-		}
+		sequenceID = logMessage.SequenceId
+		message = logMessage.Message
 
 	case topic.TopicKindIdentityUpdatesV1:
 		desiredOriginatorID = constants.IdentityUpdateOriginatorID
@@ -488,17 +794,8 @@ func (s *Service) publishToBlockchain(
 		}
 
 		hash = logMessage.Raw.TxHash
-		unsignedOriginatorEnvelope, err = buildUnsignedOriginatorEnvelopeFromChain(
-			desiredOriginatorID,
-			logMessage.SequenceId,
-			logMessage.Update,
-		)
-		if err != nil {
-			return nil, connect.NewError(
-				connect.CodeInternal,
-				fmt.Errorf("error building unsigned originator envelope: %w", err),
-			)
-		}
+		sequenceID = logMessage.SequenceId
+		message = logMessage.Update
 
 	default:
 		return nil, connect.NewError(
@@ -507,14 +804,6 @@ func (s *Service) publishToBlockchain(
 		)
 	}
 
-	unsignedBytes, err := proto.Marshal(unsignedOriginatorEnvelope)
-	if err != nil {
-		return nil, connect.NewError(
-			connect.CodeInternal,
-			fmt.Errorf("error marshalling unsigned originator envelope: %w", err),
-		)
-	}
-
 	defer func() {
 		if err != nil {
 			s.logger.Error(
@@ -536,11 +825,42 @@ func (s *Service) publishToBlockchain(
 		metrics.EmitGatewayMessageOriginated(desiredOriginatorID, 1)
 	}()
 
+	return newBlockchainOriginatorEnvelope(desiredOriginatorID, sequenceID, message, hash)
+}
+
+// newBlockchainOriginatorEnvelope builds the originator envelope of a client envelope
+// published to the blockchain, proven by the transaction that published it.
+func newBlockchainOriginatorEnvelope(
+	originatorID uint32,
+	sequenceID uint64,
+	clientEnvelope []byte,
+	txHash common.Hash,
+) (*envelopesProto.OriginatorEnvelope, error) {
+	unsignedOriginatorEnvelope, err := buildUnsignedOriginatorEnvelopeFromChain(
+		originatorID,
+		sequenceID,
+		clientEnvelope,
+	)
+	if err != nil {
+		return nil, connect.NewError(
+			connect.CodeInternal,
+			fmt.Errorf("error building unsigned originator envelope: %w", err),
+		)
+	}
+
+	unsignedBytes, err := proto.Marshal(unsignedOriginatorEnvelope)
+	if err != nil {
+		return nil, connect.NewError(
+			connect.CodeInternal,
+			fmt.Errorf("error marshalling unsigned originator envelope: %w", err),
+		)
+	}
+
 	return &envelopesProto.OriginatorEnvelope{
 		UnsignedOriginatorEnvelope: unsignedBytes,
 		Proof: &envelopesProto.OriginatorEnvelope_BlockchainProof{
 			BlockchainProof: &envelopesProto.BlockchainProof{
-				TransactionHash: hash.Bytes(),
+				TransactionHash: txHash.Bytes(),
 			},
 		},
 	}, nil
@@ -17,6 +17,7 @@ import (
 	"connectrpc.com/connect"
 	"connectrpc.com/grpchealth"
 	"connectrpc.com/grpcreflect"
+	"github.com/xmtp/xmtpd/pkg/authn"
 	interceptors "github.com/xmtp/xmtpd/pkg/interceptors/server"
 	"github.com/xmtp/xmtpd/pkg/tracing"
 	"github.com/xmtp/xmtpd/pkg/utils"
@@ -30,6 +31,7 @@ type APIServerConfig struct {
 	RegistrationFunc RegistrationFunc
 	Listener         net.Listener
 	EnableReflection bool
+	NodeVerifier     authn.JWTVerifier
 }
 
 type APIServerOption func(*APIServerConfig)
@@ -56,6 +58,11 @@ func WithReflection(enabled bool) APIServerOption {
 	return func(cfg *APIServerConfig) { cfg.EnableReflection = enabled }
 }
 
+// WithNodeVerifier sets the verifier used to trust trace context propagated by other nodes.
+func WithNodeVerifier(verifier authn.JWTVerifier) APIServerOption {
+	return func(cfg *APIServerConfig) { cfg.NodeVerifier = verifier }
+}
+
 func WithRegistrationFunc(registrationFunc RegistrationFunc) APIServerOption {
 	return func(cfg *APIServerConfig) { cfg.RegistrationFunc = registrationFunc }
 }
@@ -135,7 +142,7 @@ func NewAPIServer(opts ...APIServerOption) (*APIServer, error) {
 	if tracing.IsEnabled() {
 		serverInterceptors = append(
 			serverInterceptors,
-			interceptors.NewTracingInterceptor(),
+			interceptors.NewTracingInterceptor(cfg.NodeVerifier),
 		)
 	}
 	serverInterceptors = append(serverInterceptors,
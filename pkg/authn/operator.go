@@ -0,0 +1,109 @@
+package authn
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	OperatorAPIKeyScheme = "ApiKey"
+	OperatorBearerScheme = "Bearer"
+)
+
+var (
+	ErrInvalidOperatorCredential = errors.New("invalid operator credential")
+	ErrNoOperatorCredentials     = errors.New("no operator credentials configured")
+)
+
+// OperatorVerifier verifies the credentials app operators present to be rate
+// limited as Tier 1. A credential is either "ApiKey <key>", where the SHA-256
+// digest of the key is configured for the operator, or "Bearer <jwt>", where the
+// JWT is signed with HS256 using the configured secret, carries an expiry, and
+// names the operator in its subject.
+type OperatorVerifier struct {
+	apiKeys   map[string][]byte
+	jwtSecret []byte
+}
+
+// NewOperatorVerifier returns a verifier for the given API key hashes, keyed by
+// operator ID, and JWT secret. Either may be empty, but not both.
+func NewOperatorVerifier(
+	apiKeyHashes map[string][]byte,
+	jwtSecret string,
+) (*OperatorVerifier, error) {
+	if len(apiKeyHashes) == 0 && jwtSecret == "" {
+		return nil, ErrNoOperatorCredentials
+	}
+
+	return &OperatorVerifier{
+		apiKeys:   apiKeyHashes,
+		jwtSecret: []byte(jwtSecret),
+	}, nil
+}
+
+// Verify returns the operator ID the credential belongs to.
+func (v *OperatorVerifier) Verify(credential string) (string, error) {
+	scheme, value, ok := strings.Cut(strings.TrimSpace(credential), " ")
+	if !ok || value == "" {
+		return "", ErrInvalidOperatorCredential
+	}
+
+	switch {
+	case strings.EqualFold(scheme, OperatorAPIKeyScheme):
+		return v.verifyAPIKey(value)
+	case strings.EqualFold(scheme, OperatorBearerScheme):
+		return v.verifyJWT(value)
+	default:
+		return "", fmt.Errorf("%w: unsupported scheme %q", ErrInvalidOperatorCredential, scheme)
+	}
+}
+
+func (v *OperatorVerifier) verifyAPIKey(key string) (string, error) {
+	digest := sha256.Sum256([]byte(key))
+
+	// Compare against every key so that timing does not reveal which operator matched.
+	var operatorID string
+	for id, hash := range v.apiKeys {
+		if subtle.ConstantTimeCompare(digest[:], hash) == 1 {
+			operatorID = id
+		}
+	}
+
+	if operatorID == "" {
+		return "", ErrInvalidOperatorCredential
+	}
+
+	return operatorID, nil
+}
+
+func (v *OperatorVerifier) verifyJWT(tokenString string) (string, error) {
+	if len(v.jwtSecret) == 0 {
+		return "", ErrInvalidOperatorCredential
+	}
+
+	token, err := jwt.ParseWithClaims(
+		tokenString,
+		&jwt.RegisteredClaims{},
+		func(*jwt.Token) (any, error) {
+			return v.jwtSecret, nil
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(MaxClockSkew),
+	)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidOperatorCredential, err)
+	}
+
+	operatorID, err := token.Claims.GetSubject()
+	if err != nil || operatorID == "" {
+		return "", fmt.Errorf("%w: missing subject", ErrInvalidOperatorCredential)
+	}
+
+	return operatorID, nil
+}
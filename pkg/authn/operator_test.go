@@ -0,0 +1,134 @@
+package authn_test
+
+import (
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/authn"
+)
+
+const (
+	testOperatorID     = "acme"
+	testOperatorAPIKey = "acme-api-key"
+	testOperatorSecret = "operator-jwt-secret"
+)
+
+func buildOperatorVerifier(t *testing.T) *authn.OperatorVerifier {
+	hash := sha256.Sum256([]byte(testOperatorAPIKey))
+	verifier, err := authn.NewOperatorVerifier(
+		map[string][]byte{testOperatorID: hash[:]},
+		testOperatorSecret,
+	)
+	require.NoError(t, err)
+	return verifier
+}
+
+func buildOperatorJwt(
+	t *testing.T,
+	method jwt.SigningMethod,
+	secret string,
+	claims *jwt.RegisteredClaims,
+) string {
+	signed, err := jwt.NewWithClaims(method, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return "Bearer " + signed
+}
+
+func TestOperatorVerifierAPIKey(t *testing.T) {
+	verifier := buildOperatorVerifier(t)
+
+	operatorID, err := verifier.Verify("ApiKey " + testOperatorAPIKey)
+	require.NoError(t, err)
+	require.Equal(t, testOperatorID, operatorID)
+
+	_, err = verifier.Verify("ApiKey wrong-key")
+	require.ErrorIs(t, err, authn.ErrInvalidOperatorCredential)
+}
+
+func TestOperatorVerifierJWT(t *testing.T) {
+	verifier := buildOperatorVerifier(t)
+
+	valid := &jwt.RegisteredClaims{
+		Subject:   testOperatorID,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+
+	operatorID, err := verifier.Verify(
+		buildOperatorJwt(t, jwt.SigningMethodHS256, testOperatorSecret, valid),
+	)
+	require.NoError(t, err)
+	require.Equal(t, testOperatorID, operatorID)
+
+	tests := []struct {
+		name   string
+		method jwt.SigningMethod
+		secret string
+		claims *jwt.RegisteredClaims
+	}{
+		{
+			name:   "wrong secret",
+			method: jwt.SigningMethodHS256,
+			secret: "other-secret",
+			claims: valid,
+		},
+		{
+			name:   "unexpected algorithm",
+			method: jwt.SigningMethodHS512,
+			secret: testOperatorSecret,
+			claims: valid,
+		},
+		{
+			name:   "expired",
+			method: jwt.SigningMethodHS256,
+			secret: testOperatorSecret,
+			claims: &jwt.RegisteredClaims{
+				Subject:   testOperatorID,
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+			},
+		},
+		{
+			name:   "no expiry",
+			method: jwt.SigningMethodHS256,
+			secret: testOperatorSecret,
+			claims: &jwt.RegisteredClaims{Subject: testOperatorID},
+		},
+		{
+			name:   "no subject",
+			method: jwt.SigningMethodHS256,
+			secret: testOperatorSecret,
+			claims: &jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier.Verify(buildOperatorJwt(t, tt.method, tt.secret, tt.claims))
+			require.ErrorIs(t, err, authn.ErrInvalidOperatorCredential)
+		})
+	}
+}
+
+func TestOperatorVerifierMalformedCredential(t *testing.T) {
+	verifier := buildOperatorVerifier(t)
+
+	for _, credential := range []string{"", "ApiKey", "Basic dXNlcjpwYXNz", testOperatorAPIKey} {
+		_, err := verifier.Verify(credential)
+		require.ErrorIs(t, err, authn.ErrInvalidOperatorCredential, credential)
+	}
+}
+
+func TestOperatorVerifierRequiresCredentials(t *testing.T) {
+	_, err := authn.NewOperatorVerifier(nil, "")
+	require.ErrorIs(t, err, authn.ErrNoOperatorCredentials)
+
+	verifier, err := authn.NewOperatorVerifier(nil, testOperatorSecret)
+	require.NoError(t, err)
+
+	_, err = verifier.Verify("ApiKey " + testOperatorAPIKey)
+	require.ErrorIs(t, err, authn.ErrInvalidOperatorCredential)
+}
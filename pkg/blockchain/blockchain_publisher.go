@@ -44,6 +44,8 @@ type BlockchainPublisher struct {
 	identityUpdateABI     abi.ABI
 	groupMessageAddress   common.Address
 	identityUpdateAddress common.Address
+	multicallABI          abi.ABI
+	multicallAddress      common.Address
 }
 
 func NewBlockchainPublisher(
@@ -69,6 +71,11 @@ func NewBlockchainPublisher(
 		return nil, errors.New("failed to parse IdentityUpdateBroadcaster ABI: " + err.Error())
 	}
 
+	multicallABI, err := abi.JSON(strings.NewReader(multicall3ABI))
+	if err != nil {
+		return nil, errors.New("failed to parse Multicall3 ABI: " + err.Error())
+	}
+
 	nonce, err := client.PendingNonceAt(ctx, signer.FromAddress())
 	if err != nil {
 		return nil, err
@@ -100,6 +107,8 @@ func NewBlockchainPublisher(
 		),
 		groupMessageABI:   groupMessageABI,
 		identityUpdateABI: identityUpdateABI,
+		multicallABI:      multicallABI,
+		multicallAddress:  common.HexToAddress(contractOptions.AppChain.MulticallAddress),
 		oracle:            oracle,
 	}
 
@@ -135,6 +144,7 @@ func (m *BlockchainPublisher) sendRawTransaction(
 	ctx context.Context,
 	to common.Address,
 	data []byte,
+	gasLimit uint64,
 	nonce *big.Int,
 ) (*types.Transaction, error) {
 	gasPrice := m.oracle.GetGasPrice()
@@ -143,7 +153,7 @@ func (m *BlockchainPublisher) sendRawTransaction(
 		Nonce:    nonce.Uint64(),
 		To:       &to,
 		Value:    big.NewInt(0),
-		Gas:      defaultGasLimit,
+		Gas:      gasLimit,
 		GasPrice: big.NewInt(gasPrice),
 		Data:     data,
 	})
@@ -181,7 +191,7 @@ func (m *BlockchainPublisher) PublishGroupMessage(
 				return nil, errors.New("failed to pack addMessage: " + err.Error())
 			}
 
-			return m.sendRawTransaction(ctx, m.groupMessageAddress, data, &nonce)
+			return m.sendRawTransaction(ctx, m.groupMessageAddress, data, defaultGasLimit, &nonce)
 		},
 		func(ctx context.Context, transaction *types.Transaction) ([]*gm.GroupMessageBroadcasterMessageSent, error) {
 			receipt, err := waitForTransaction(
@@ -238,7 +248,7 @@ func (m *BlockchainPublisher) BootstrapGroupMessages(
 				return nil, errors.New("failed to pack bootstrapMessages: " + err.Error())
 			}
 
-			return m.sendRawTransaction(ctx, m.groupMessageAddress, data, &nonce)
+			return m.sendRawTransaction(ctx, m.groupMessageAddress, data, defaultGasLimit, &nonce)
 		},
 		func(ctx context.Context, transaction *types.Transaction) ([]*gm.GroupMessageBroadcasterMessageSent, error) {
 			receipt, err := waitForTransaction(
@@ -280,7 +290,7 @@ func (m *BlockchainPublisher) PublishIdentityUpdate(
 				return nil, errors.New("failed to pack addIdentityUpdate: " + err.Error())
 			}
 
-			return m.sendRawTransaction(ctx, m.identityUpdateAddress, data, &nonce)
+			return m.sendRawTransaction(ctx, m.identityUpdateAddress, data, defaultGasLimit, &nonce)
 		},
 		func(ctx context.Context, transaction *types.Transaction) ([]*iu.IdentityUpdateBroadcasterIdentityUpdateCreated, error) {
 			receipt, err := waitForTransaction(
@@ -339,7 +349,7 @@ func (m *BlockchainPublisher) BootstrapIdentityUpdates(
 				return nil, errors.New("failed to pack bootstrapIdentityUpdates: " + err.Error())
 			}
 
-			return m.sendRawTransaction(ctx, m.identityUpdateAddress, data, &nonce)
+			return m.sendRawTransaction(ctx, m.identityUpdateAddress, data, defaultGasLimit, &nonce)
 		},
 		func(ctx context.Context, transaction *types.Transaction) ([]*iu.IdentityUpdateBroadcasterIdentityUpdateCreated, error) {
 			receipt, err := waitForTransaction(
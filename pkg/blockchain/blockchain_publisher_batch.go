@@ -0,0 +1,206 @@
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	gm "github.com/xmtp/xmtpd/pkg/abi/groupmessagebroadcaster"
+	iu "github.com/xmtp/xmtpd/pkg/abi/identityupdatebroadcaster"
+)
+
+// ErrBatchReverted is returned when a batch transaction would revert, so none of its
+// payloads were published. Publishing them one by one isolates the failing payload.
+var ErrBatchReverted = errors.New("batch transaction would revert")
+
+// batchGasMarginPercent is the margin added to the estimated gas of a batch transaction.
+const batchGasMarginPercent = 20
+
+// multicall3ABI is the aggregate3 method of the canonical Multicall3 contract.
+const multicall3ABI = `[{"inputs":[{"components":[{"internalType":"address","name":"target","type":"address"},{"internalType":"bool","name":"allowFailure","type":"bool"},{"internalType":"bytes","name":"callData","type":"bytes"}],"internalType":"struct Multicall3.Call3[]","name":"calls","type":"tuple[]"}],"name":"aggregate3","outputs":[{"components":[{"internalType":"bool","name":"success","type":"bool"},{"internalType":"bytes","name":"returnData","type":"bytes"}],"internalType":"struct Multicall3.Result[]","name":"returnData","type":"tuple[]"}],"stateMutability":"payable","type":"function"}]`
+
+// multicall3Call mirrors the Multicall3.Call3 struct.
+type multicall3Call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// PublishGroupMessages publishes messages in a single transaction through the Multicall3
+// contract. The events are returned in the order of messages.
+func (m *BlockchainPublisher) PublishGroupMessages(
+	ctx context.Context,
+	groupIDs [][16]byte,
+	messages [][]byte,
+) ([]*gm.GroupMessageBroadcasterMessageSent, error) {
+	if len(messages) != len(groupIDs) {
+		return nil, errors.New("messages and groupIDs must have the same length")
+	}
+
+	calls := make([][]byte, len(messages))
+	for i, message := range messages {
+		if len(message) == 0 {
+			return nil, fmt.Errorf("message %d is empty", i)
+		}
+		data, err := m.groupMessageABI.Pack("addMessage", groupIDs[i], message)
+		if err != nil {
+			return nil, errors.New("failed to pack addMessage: " + err.Error())
+		}
+		calls[i] = data
+	}
+
+	events, err := publishBatch(
+		ctx,
+		m,
+		"publish_group_messages",
+		m.groupMessageAddress,
+		calls,
+		func(receipt *types.Receipt) ([]*gm.GroupMessageBroadcasterMessageSent, error) {
+			return findGroupMessageLogs(receipt, &m.groupMessageABI, len(calls))
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, event := range events {
+		if event.GroupId != groupIDs[i] || !bytes.Equal(event.Message, messages[i]) {
+			return nil, fmt.Errorf("event %d does not match its message", i)
+		}
+	}
+
+	return events, nil
+}
+
+// PublishIdentityUpdates publishes identity updates in a single transaction through the
+// Multicall3 contract. The events are returned in the order of identityUpdates.
+func (m *BlockchainPublisher) PublishIdentityUpdates(
+	ctx context.Context,
+	inboxIDs [][32]byte,
+	identityUpdates [][]byte,
+) ([]*iu.IdentityUpdateBroadcasterIdentityUpdateCreated, error) {
+	if len(identityUpdates) != len(inboxIDs) {
+		return nil, errors.New("identityUpdates and inboxIDs must have the same length")
+	}
+
+	calls := make([][]byte, len(identityUpdates))
+	for i, identityUpdate := range identityUpdates {
+		if len(identityUpdate) == 0 {
+			return nil, fmt.Errorf("identity update %d is empty", i)
+		}
+		data, err := m.identityUpdateABI.Pack("addIdentityUpdate", inboxIDs[i], identityUpdate)
+		if err != nil {
+			return nil, errors.New("failed to pack addIdentityUpdate: " + err.Error())
+		}
+		calls[i] = data
+	}
+
+	events, err := publishBatch(
+		ctx,
+		m,
+		"publish_identity_updates",
+		m.identityUpdateAddress,
+		calls,
+		func(receipt *types.Receipt) ([]*iu.IdentityUpdateBroadcasterIdentityUpdateCreated, error) {
+			return findIdentityUpdateLogs(receipt, &m.identityUpdateABI, len(calls))
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, event := range events {
+		if event.InboxId != inboxIDs[i] || !bytes.Equal(event.Update, identityUpdates[i]) {
+			return nil, fmt.Errorf("event %d does not match its identity update", i)
+		}
+	}
+
+	return events, nil
+}
+
+// publishBatch sends calls to target in a single Multicall3 transaction, and returns the
+// events target emitted, one per call. Calls are not allowed to fail: a batch containing
+// a failing call reverts as a whole, which is reported as ErrBatchReverted before the
+// transaction is sent.
+//
+// Multicall3 makes the calls itself, so target sees the Multicall3 contract as msg.sender
+// instead of the payer. This is safe for addMessage and addIdentityUpdate, which accept
+// any caller: they only check the payload size and the paused flag, and their events do
+// not record the sender. The broadcasters have no batch entrypoint of their own besides
+// bootstrapMessages and bootstrapIdentityUpdates, which are restricted to the payload
+// bootstrapper. Batching must not be used for methods that authorize msg.sender.
+func publishBatch[T any](
+	ctx context.Context,
+	m *BlockchainPublisher,
+	payloadType string,
+	target common.Address,
+	calls [][]byte,
+	findLogs func(*types.Receipt) ([]*T, error),
+) ([]*T, error) {
+	if m.multicallAddress == (common.Address{}) {
+		return nil, errors.New("multicall address is not configured")
+	}
+
+	multicalls := make([]multicall3Call, len(calls))
+	for i, call := range calls {
+		multicalls[i] = multicall3Call{Target: target, CallData: call}
+	}
+
+	data, err := m.multicallABI.Pack("aggregate3", multicalls)
+	if err != nil {
+		return nil, errors.New("failed to pack aggregate3: " + err.Error())
+	}
+
+	// The gas of a batch grows with its payloads, so it is estimated rather than using
+	// the default gas limit of a single payload.
+	gas, err := m.client.EstimateGas(ctx, ethereum.CallMsg{
+		From: m.signer.FromAddress(),
+		To:   &m.multicallAddress,
+		Data: data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBatchReverted, err)
+	}
+	gas += gas * batchGasMarginPercent / 100
+
+	events, err := withNonce(
+		ctx,
+		m.logger,
+		m.nonceManager,
+		payloadType,
+		func(ctx context.Context, nonce big.Int) (*types.Transaction, error) {
+			return m.sendRawTransaction(ctx, m.multicallAddress, data, gas, &nonce)
+		},
+		func(ctx context.Context, transaction *types.Transaction) ([]*T, error) {
+			receipt, err := waitForTransaction(
+				ctx,
+				m.logger,
+				m.client,
+				transaction.Hash(),
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			if receipt == nil {
+				return nil, errors.New("transaction receipt is nil")
+			}
+
+			return findLogs(logsFrom(receipt, target))
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(events) != len(calls) {
+		return nil, fmt.Errorf("expected %d events, found %d", len(calls), len(events))
+	}
+
+	return events, nil
+}
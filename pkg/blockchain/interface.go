@@ -33,6 +33,7 @@ type ChainClient interface {
 	ethereum.LogFilterer
 	ethereum.ChainIDReader
 	ethereum.ChainReader
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
 }
 
 type TransactionSigner interface {
@@ -47,6 +48,11 @@ type IBlockchainPublisher interface {
 		inboxID [32]byte,
 		identityUpdate []byte,
 	) (*iu.IdentityUpdateBroadcasterIdentityUpdateCreated, error)
+	PublishIdentityUpdates(
+		ctx context.Context,
+		inboxIDs [][32]byte,
+		identityUpdates [][]byte,
+	) ([]*iu.IdentityUpdateBroadcasterIdentityUpdateCreated, error)
 	BootstrapIdentityUpdates(
 		ctx context.Context,
 		inboxIDs [][32]byte,
@@ -58,6 +64,11 @@ type IBlockchainPublisher interface {
 		groupID [16]byte,
 		message []byte,
 	) (*gm.GroupMessageBroadcasterMessageSent, error)
+	PublishGroupMessages(
+		ctx context.Context,
+		groupIDs [][16]byte,
+		messages [][]byte,
+	) ([]*gm.GroupMessageBroadcasterMessageSent, error)
 	BootstrapGroupMessages(
 		ctx context.Context,
 		groupIDs [][16]byte,
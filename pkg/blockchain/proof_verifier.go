@@ -0,0 +1,205 @@
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	lru "github.com/hashicorp/golang-lru/v2"
+	gm "github.com/xmtp/xmtpd/pkg/abi/groupmessagebroadcaster"
+	iu "github.com/xmtp/xmtpd/pkg/abi/identityupdatebroadcaster"
+	"github.com/xmtp/xmtpd/pkg/config"
+	"github.com/xmtp/xmtpd/pkg/constants"
+	"github.com/xmtp/xmtpd/pkg/envelopes"
+)
+
+// ErrInvalidBlockchainProof is returned when a blockchain proof does not prove its envelope
+// was originated on the app chain. Other errors returned by VerifyEnvelope are transient.
+var ErrInvalidBlockchainProof = errors.New("invalid blockchain proof")
+
+// EnvelopeProofVerifier verifies the blockchain proof of a chain-originated envelope.
+type EnvelopeProofVerifier interface {
+	VerifyEnvelope(ctx context.Context, env *envelopes.OriginatorEnvelope) error
+}
+
+// BlockchainProofVerifier verifies that a chain-originated envelope was emitted by the app
+// chain broadcaster contracts, in the transaction named by its BlockchainProof.
+// Receipts of successful transactions are cached, as every envelope of a batch
+// transaction names the same one.
+type BlockchainProofVerifier struct {
+	client                ChainClient
+	groupMessageAddress   common.Address
+	identityUpdateAddress common.Address
+	groupMessageABI       abi.ABI
+	identityUpdateABI     abi.ABI
+	receipts              *lru.Cache[common.Hash, *types.Receipt]
+}
+
+var _ EnvelopeProofVerifier = (*BlockchainProofVerifier)(nil)
+
+// NewBlockchainProofVerifier creates a verifier reading receipts from an app chain client.
+// At most cacheSize receipts are cached.
+func NewBlockchainProofVerifier(
+	client ChainClient,
+	options config.AppChainOptions,
+	cacheSize int,
+) (*BlockchainProofVerifier, error) {
+	if client == nil {
+		return nil, errors.New("client is nil")
+	}
+
+	groupMessageABI, err := abi.JSON(strings.NewReader(gm.GroupMessageBroadcasterMetaData.ABI))
+	if err != nil {
+		return nil, errors.New("failed to parse GroupMessageBroadcaster ABI: " + err.Error())
+	}
+
+	identityUpdateABI, err := abi.JSON(strings.NewReader(iu.IdentityUpdateBroadcasterMetaData.ABI))
+	if err != nil {
+		return nil, errors.New("failed to parse IdentityUpdateBroadcaster ABI: " + err.Error())
+	}
+
+	receipts, err := lru.New[common.Hash, *types.Receipt](cacheSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlockchainProofVerifier{
+		client:                client,
+		groupMessageAddress:   common.HexToAddress(options.GroupMessageBroadcasterAddress),
+		identityUpdateAddress: common.HexToAddress(options.IdentityUpdateBroadcasterAddress),
+		groupMessageABI:       groupMessageABI,
+		identityUpdateABI:     identityUpdateABI,
+		receipts:              receipts,
+	}, nil
+}
+
+// VerifyEnvelope checks that the transaction named by env's BlockchainProof succeeded and
+// emitted a broadcaster event with env's sequence ID, topic and payload.
+func (v *BlockchainProofVerifier) VerifyEnvelope(
+	ctx context.Context,
+	env *envelopes.OriginatorEnvelope,
+) error {
+	proof := env.Proto().GetBlockchainProof()
+	if proof == nil {
+		return fmt.Errorf("%w: envelope has no blockchain proof", ErrInvalidBlockchainProof)
+	}
+	if len(proof.GetTransactionHash()) != common.HashLength {
+		return fmt.Errorf(
+			"%w: transaction hash has %d bytes",
+			ErrInvalidBlockchainProof,
+			len(proof.GetTransactionHash()),
+		)
+	}
+
+	receipt, err := v.receipt(ctx, common.BytesToHash(proof.GetTransactionHash()))
+	if err != nil {
+		return err
+	}
+
+	var (
+		seqID      = env.OriginatorSequenceID()
+		identifier = env.TargetTopic().Identifier()
+		payload    = env.UnsignedOriginatorEnvelope.PayerEnvelope.Proto().
+				GetUnsignedClientEnvelope()
+	)
+
+	switch env.OriginatorNodeID() {
+	case constants.GroupMessageOriginatorID:
+		events, err := findGroupMessageLogs(
+			logsFrom(receipt, v.groupMessageAddress),
+			&v.groupMessageABI,
+			len(receipt.Logs),
+		)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidBlockchainProof, err)
+		}
+		for _, event := range events {
+			if event.SequenceId == seqID {
+				return matchEvent(event.GroupId[:], event.Message, identifier, payload)
+			}
+		}
+
+	case constants.IdentityUpdateOriginatorID:
+		events, err := findIdentityUpdateLogs(
+			logsFrom(receipt, v.identityUpdateAddress),
+			&v.identityUpdateABI,
+			len(receipt.Logs),
+		)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidBlockchainProof, err)
+		}
+		for _, event := range events {
+			if event.SequenceId == seqID {
+				return matchEvent(event.InboxId[:], event.Update, identifier, payload)
+			}
+		}
+
+	default:
+		return fmt.Errorf(
+			"%w: originator %d does not publish to the app chain",
+			ErrInvalidBlockchainProof,
+			env.OriginatorNodeID(),
+		)
+	}
+
+	return fmt.Errorf(
+		"%w: transaction %s has no event for sequence id %d",
+		ErrInvalidBlockchainProof,
+		receipt.TxHash.Hex(),
+		seqID,
+	)
+}
+
+// receipt returns the receipt of a successful transaction.
+func (v *BlockchainProofVerifier) receipt(
+	ctx context.Context,
+	txHash common.Hash,
+) (*types.Receipt, error) {
+	if receipt, ok := v.receipts.Get(txHash); ok {
+		return receipt, nil
+	}
+
+	// A transaction that is not found may be missing from a lagging RPC node only, so it
+	// does not invalidate the proof.
+	receipt, err := v.client.TransactionReceipt(ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipt of transaction %s: %w", txHash.Hex(), err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf(
+			"%w: transaction %s failed",
+			ErrInvalidBlockchainProof,
+			txHash.Hex(),
+		)
+	}
+
+	v.receipts.Add(txHash, receipt)
+	return receipt, nil
+}
+
+// logsFrom returns a receipt holding only the logs emitted by address.
+func logsFrom(receipt *types.Receipt, address common.Address) *types.Receipt {
+	filtered := *receipt
+	filtered.Logs = make([]*types.Log, 0, len(receipt.Logs))
+	for _, log := range receipt.Logs {
+		if log != nil && log.Address == address {
+			filtered.Logs = append(filtered.Logs, log)
+		}
+	}
+	return &filtered
+}
+
+func matchEvent(eventIdentifier, eventPayload, identifier, payload []byte) error {
+	if !bytes.Equal(eventIdentifier, identifier) {
+		return fmt.Errorf("%w: topic does not match the event", ErrInvalidBlockchainProof)
+	}
+	if !bytes.Equal(eventPayload, payload) {
+		return fmt.Errorf("%w: payload does not match the event", ErrInvalidBlockchainProof)
+	}
+	return nil
+}
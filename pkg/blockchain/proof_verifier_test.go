@@ -0,0 +1,178 @@
+package blockchain_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	gm "github.com/xmtp/xmtpd/pkg/abi/groupmessagebroadcaster"
+	"github.com/xmtp/xmtpd/pkg/blockchain"
+	"github.com/xmtp/xmtpd/pkg/config"
+	"github.com/xmtp/xmtpd/pkg/constants"
+	"github.com/xmtp/xmtpd/pkg/envelopes"
+	envelopesProto "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/envelopes"
+	envelopeTestUtils "github.com/xmtp/xmtpd/pkg/testutils/envelopes"
+	blockchainMocks "github.com/xmtp/xmtpd/pkg/testutils/mocks/blockchain"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	testGroupMessageAddress = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	testTxHash              = common.HexToHash("0xabcdef")
+	testGroupID             = [16]byte{1, 2, 3}
+)
+
+func newTestProofVerifier(
+	t *testing.T,
+) (*blockchain.BlockchainProofVerifier, *blockchainMocks.MockChainClient) {
+	client := blockchainMocks.NewMockChainClient(t)
+	verifier, err := blockchain.NewBlockchainProofVerifier(
+		client,
+		config.AppChainOptions{
+			GroupMessageBroadcasterAddress:   testGroupMessageAddress.Hex(),
+			IdentityUpdateBroadcasterAddress: common.HexToAddress("0x2").Hex(),
+		},
+		10,
+	)
+	require.NoError(t, err)
+	return verifier, client
+}
+
+func groupMessageClientEnvelope(t *testing.T, message string) []byte {
+	clientEnv, err := proto.Marshal(
+		envelopeTestUtils.CreateGroupMessageClientEnvelope(testGroupID, []byte(message)),
+	)
+	require.NoError(t, err)
+	return clientEnv
+}
+
+func chainEnvelope(t *testing.T, seqID uint64, clientEnv []byte) *envelopes.OriginatorEnvelope {
+	payerBytes, err := proto.Marshal(&envelopesProto.PayerEnvelope{
+		UnsignedClientEnvelope: clientEnv,
+	})
+	require.NoError(t, err)
+	unsignedBytes, err := proto.Marshal(&envelopesProto.UnsignedOriginatorEnvelope{
+		OriginatorNodeId:     constants.GroupMessageOriginatorID,
+		OriginatorSequenceId: seqID,
+		OriginatorNs:         1,
+		PayerEnvelopeBytes:   payerBytes,
+	})
+	require.NoError(t, err)
+
+	env, err := envelopes.NewOriginatorEnvelope(&envelopesProto.OriginatorEnvelope{
+		UnsignedOriginatorEnvelope: unsignedBytes,
+		Proof: &envelopesProto.OriginatorEnvelope_BlockchainProof{
+			BlockchainProof: &envelopesProto.BlockchainProof{TransactionHash: testTxHash[:]},
+		},
+	})
+	require.NoError(t, err)
+	return env
+}
+
+func messageSentLog(
+	t *testing.T,
+	address common.Address,
+	seqID uint64,
+	message []byte,
+) *types.Log {
+	contractABI, err := abi.JSON(strings.NewReader(gm.GroupMessageBroadcasterMetaData.ABI))
+	require.NoError(t, err)
+	event := contractABI.Events["MessageSent"]
+
+	data, err := event.Inputs.NonIndexed().Pack(message)
+	require.NoError(t, err)
+
+	var groupTopic, seqTopic common.Hash
+	copy(groupTopic[:], testGroupID[:])
+	binary.BigEndian.PutUint64(seqTopic[24:], seqID)
+
+	return &types.Log{
+		Address: address,
+		Topics:  []common.Hash{event.ID, groupTopic, seqTopic},
+		Data:    data,
+	}
+}
+
+func successfulReceipt(logs ...*types.Log) *types.Receipt {
+	return &types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: testTxHash, Logs: logs}
+}
+
+func TestBlockchainProofVerifierAcceptsMatchingEvent(t *testing.T) {
+	verifier, client := newTestProofVerifier(t)
+	clientEnv := groupMessageClientEnvelope(t, "hello")
+	client.EXPECT().
+		TransactionReceipt(mock.Anything, testTxHash).
+		Return(successfulReceipt(
+			messageSentLog(t, testGroupMessageAddress, 6, []byte("other")),
+			messageSentLog(t, testGroupMessageAddress, 7, clientEnv),
+		), nil).
+		Once()
+
+	require.NoError(t, verifier.VerifyEnvelope(t.Context(), chainEnvelope(t, 7, clientEnv)))
+
+	// The receipt is cached: the mock would fail on a second call.
+	err := verifier.VerifyEnvelope(t.Context(), chainEnvelope(t, 6, clientEnv))
+	require.ErrorIs(t, err, blockchain.ErrInvalidBlockchainProof)
+}
+
+func TestBlockchainProofVerifierRejectsMismatches(t *testing.T) {
+	clientEnv := groupMessageClientEnvelope(t, "hello")
+
+	tests := []struct {
+		name    string
+		receipt *types.Receipt
+	}{
+		{
+			name: "failed transaction",
+			receipt: &types.Receipt{
+				Status: types.ReceiptStatusFailed,
+				TxHash: testTxHash,
+				Logs:   []*types.Log{messageSentLog(t, testGroupMessageAddress, 7, clientEnv)},
+			},
+		},
+		{
+			name:    "other contract",
+			receipt: successfulReceipt(messageSentLog(t, common.HexToAddress("0x3"), 7, clientEnv)),
+		},
+		{
+			name:    "other sequence id",
+			receipt: successfulReceipt(messageSentLog(t, testGroupMessageAddress, 8, clientEnv)),
+		},
+		{
+			name: "other payload",
+			receipt: successfulReceipt(
+				messageSentLog(t, testGroupMessageAddress, 7, groupMessageClientEnvelope(t, "bye")),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier, client := newTestProofVerifier(t)
+			client.EXPECT().TransactionReceipt(mock.Anything, testTxHash).Return(tt.receipt, nil)
+
+			err := verifier.VerifyEnvelope(t.Context(), chainEnvelope(t, 7, clientEnv))
+			require.ErrorIs(t, err, blockchain.ErrInvalidBlockchainProof)
+		})
+	}
+}
+
+func TestBlockchainProofVerifierReceiptErrorIsTransient(t *testing.T) {
+	verifier, client := newTestProofVerifier(t)
+	client.EXPECT().
+		TransactionReceipt(mock.Anything, testTxHash).
+		Return(nil, errors.New("connection refused"))
+
+	err := verifier.VerifyEnvelope(
+		t.Context(),
+		chainEnvelope(t, 7, groupMessageClientEnvelope(t, "hello")),
+	)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, blockchain.ErrInvalidBlockchainProof)
+}
@@ -1,8 +1,14 @@
 package config
 
 import (
+	"encoding/hex"
+	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/xmtp/xmtpd/pkg/topic"
 )
 
 // Uint32Slice parses a comma-separated list of uint32 values.
@@ -58,3 +64,308 @@ func (s Uint32Slice) String() string {
 	}
 	return strings.Join(parts, ",")
 }
+
+// TopicKindRetention parses a comma-separated list of topic kind retention overrides,
+// in the form <topic kind>=<duration>. Topic kinds use the names returned by
+// topic.TopicKind.String.
+//
+// Examples:
+//
+//	""                                       -> nil
+//	"key_packages_v1=720h"                   -> {KeyPackagesV1: 720h}
+//	"key_packages_v1=720h,group_messages_v1=2160h" -> {KeyPackagesV1: 720h, GroupMessagesV1: 2160h}
+type TopicKindRetention map[topic.TopicKind]time.Duration
+
+// UnmarshalFlag is used by github.com/jessevdk/go-flags to parse flag/env values.
+func (r *TopicKindRetention) UnmarshalFlag(value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		*r = nil
+		return nil
+	}
+
+	out := make(TopicKindRetention)
+
+	for _, p := range strings.Split(value, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+
+		name, rawDuration, ok := strings.Cut(p, "=")
+		if !ok {
+			return fmt.Errorf(
+				"retention override %q must be in the form <topic kind>=<duration>",
+				p,
+			)
+		}
+
+		kind, err := topic.ParseTopicKind(strings.TrimSpace(name))
+		if err != nil {
+			return err
+		}
+
+		duration, err := time.ParseDuration(strings.TrimSpace(rawDuration))
+		if err != nil {
+			return fmt.Errorf("retention override for %s: %w", kind, err)
+		}
+
+		if duration <= 0 {
+			return fmt.Errorf("retention override for %s must be positive", kind)
+		}
+
+		out[kind] = duration
+	}
+
+	*r = out
+	return nil
+}
+
+func (r TopicKindRetention) String() string {
+	if len(r) == 0 {
+		return ""
+	}
+
+	kinds := make([]topic.TopicKind, 0, len(r))
+	for kind := range r {
+		kinds = append(kinds, kind)
+	}
+	slices.Sort(kinds)
+
+	parts := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		parts = append(parts, fmt.Sprintf("%s=%s", kind, r[kind]))
+	}
+	return strings.Join(parts, ",")
+}
+
+// DailyWindow parses a daily UTC time window, in the form <start>-<end> with times as HH:MM.
+// A window whose end is before its start spans midnight. The empty window covers the whole day.
+//
+// Examples:
+//
+//	""            -> the whole day
+//	"02:00-05:00" -> from 02:00 until 05:00
+//	"22:00-02:00" -> from 22:00 until 02:00 the next day
+type DailyWindow struct {
+	// Start and End are offsets from midnight UTC.
+	Start time.Duration
+	End   time.Duration
+}
+
+// UnmarshalFlag is used by github.com/jessevdk/go-flags to parse flag/env values.
+func (w *DailyWindow) UnmarshalFlag(value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		*w = DailyWindow{}
+		return nil
+	}
+
+	rawStart, rawEnd, ok := strings.Cut(value, "-")
+	if !ok {
+		return fmt.Errorf("window %q must be in the form HH:MM-HH:MM", value)
+	}
+
+	start, err := parseTimeOfDay(rawStart)
+	if err != nil {
+		return fmt.Errorf("window start: %w", err)
+	}
+
+	end, err := parseTimeOfDay(rawEnd)
+	if err != nil {
+		return fmt.Errorf("window end: %w", err)
+	}
+
+	if start == end {
+		return fmt.Errorf("window %q must not be empty", value)
+	}
+
+	*w = DailyWindow{Start: start, End: end}
+	return nil
+}
+
+func (w DailyWindow) String() string {
+	if w.Start == w.End {
+		return ""
+	}
+	return formatTimeOfDay(w.Start) + "-" + formatTimeOfDay(w.End)
+}
+
+// Contains reports whether t falls within the window.
+func (w DailyWindow) Contains(t time.Time) bool {
+	if w.Start == w.End {
+		return true
+	}
+
+	t = t.UTC()
+	offset := t.Sub(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC))
+	if w.Start < w.End {
+		return offset >= w.Start && offset < w.End
+	}
+	return offset >= w.Start || offset < w.End
+}
+
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func formatTimeOfDay(offset time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(offset/time.Hour), int(offset%time.Hour/time.Minute))
+}
+
+// TopicKindReadRule is the read rule of one topic kind: either Deny, or a Cost
+// in rate-limit tokens charged per topic read.
+type TopicKindReadRule struct {
+	Deny bool
+	Cost uint64
+}
+
+// TopicKindReadPolicy parses a comma-separated list of topic kind read rules,
+// in the form <topic kind>=deny or <topic kind>=<extra token cost>. Topic kinds
+// use the names returned by topic.TopicKind.String.
+//
+// Examples:
+//
+//	""                                         -> nil
+//	"key_packages_v1=deny"                     -> {KeyPackagesV1: deny}
+//	"key_packages_v1=deny,welcome_message_v1=5" -> {KeyPackagesV1: deny, WelcomeMessagesV1: 5}
+type TopicKindReadPolicy map[topic.TopicKind]TopicKindReadRule
+
+// UnmarshalFlag is used by github.com/jessevdk/go-flags to parse flag/env values.
+func (r *TopicKindReadPolicy) UnmarshalFlag(value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		*r = nil
+		return nil
+	}
+
+	out := make(TopicKindReadPolicy)
+
+	for _, p := range strings.Split(value, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+
+		name, rawRule, ok := strings.Cut(p, "=")
+		if !ok {
+			return fmt.Errorf(
+				"read rule %q must be in the form <topic kind>=<deny | cost>",
+				p,
+			)
+		}
+
+		kind, err := topic.ParseTopicKind(strings.TrimSpace(name))
+		if err != nil {
+			return err
+		}
+
+		rawRule = strings.TrimSpace(rawRule)
+		if rawRule == "deny" {
+			out[kind] = TopicKindReadRule{Deny: true}
+			continue
+		}
+
+		cost, err := strconv.ParseUint(rawRule, 10, 64)
+		if err != nil {
+			return fmt.Errorf("read rule for %s must be deny or a token cost: %w", kind, err)
+		}
+
+		out[kind] = TopicKindReadRule{Cost: cost}
+	}
+
+	*r = out
+	return nil
+}
+
+func (r TopicKindReadPolicy) String() string {
+	if len(r) == 0 {
+		return ""
+	}
+
+	kinds := make([]topic.TopicKind, 0, len(r))
+	for kind := range r {
+		kinds = append(kinds, kind)
+	}
+	slices.Sort(kinds)
+
+	parts := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		if r[kind].Deny {
+			parts = append(parts, fmt.Sprintf("%s=deny", kind))
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s=%d", kind, r[kind].Cost))
+	}
+	return strings.Join(parts, ",")
+}
+
+// OperatorAPIKeys parses a comma-separated list of app operator API keys, in the
+// form <operator id>=<hex sha256 of the api key>. Only key hashes are configured,
+// so the node never stores the keys themselves.
+//
+// Examples:
+//
+//	""                    -> nil
+//	"acme=9f86d08...0a08" -> {"acme": sha256("test")}
+type OperatorAPIKeys map[string][]byte
+
+// UnmarshalFlag is used by github.com/jessevdk/go-flags to parse flag/env values.
+func (k *OperatorAPIKeys) UnmarshalFlag(value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		*k = nil
+		return nil
+	}
+
+	out := make(OperatorAPIKeys)
+
+	for _, p := range strings.Split(value, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+
+		operatorID, rawHash, ok := strings.Cut(p, "=")
+		operatorID = strings.TrimSpace(operatorID)
+		if !ok || operatorID == "" {
+			return fmt.Errorf("api key %q must be in the form <operator id>=<sha256 hex>", p)
+		}
+
+		hash, err := hex.DecodeString(strings.TrimSpace(rawHash))
+		if err != nil || len(hash) != 32 {
+			return fmt.Errorf("api key hash for %s must be a hex-encoded sha256 digest", operatorID)
+		}
+
+		if _, exists := out[operatorID]; exists {
+			return fmt.Errorf("duplicate api key for operator %s", operatorID)
+		}
+
+		out[operatorID] = hash
+	}
+
+	*k = out
+	return nil
+}
+
+func (k OperatorAPIKeys) String() string {
+	if len(k) == 0 {
+		return ""
+	}
+
+	operatorIDs := make([]string, 0, len(k))
+	for operatorID := range k {
+		operatorIDs = append(operatorIDs, operatorID)
+	}
+	slices.Sort(operatorIDs)
+
+	parts := make([]string, 0, len(operatorIDs))
+	for _, operatorID := range operatorIDs {
+		parts = append(parts, fmt.Sprintf("%s=%x", operatorID, k[operatorID]))
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDailyWindow_Contains(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	var always DailyWindow
+	require.NoError(t, always.UnmarshalFlag(""))
+	require.True(t, always.Contains(at(12, 0)))
+
+	var night DailyWindow
+	require.NoError(t, night.UnmarshalFlag("02:00-05:30"))
+	require.Equal(t, "02:00-05:30", night.String())
+	require.True(t, night.Contains(at(2, 0)))
+	require.True(t, night.Contains(at(5, 29)))
+	require.False(t, night.Contains(at(5, 30)))
+	require.False(t, night.Contains(at(1, 59)))
+
+	var midnight DailyWindow
+	require.NoError(t, midnight.UnmarshalFlag("22:00-02:00"))
+	require.True(t, midnight.Contains(at(23, 0)))
+	require.True(t, midnight.Contains(at(1, 0)))
+	require.False(t, midnight.Contains(at(12, 0)))
+
+	var invalid DailyWindow
+	require.Error(t, invalid.UnmarshalFlag("02:00"))
+	require.Error(t, invalid.UnmarshalFlag("02:00-02:00"))
+	require.Error(t, invalid.UnmarshalFlag("25:00-02:00"))
+}
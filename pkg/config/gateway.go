@@ -7,6 +7,6 @@ type GatewayConfig struct {
 	Metrics    MetricsOptions    `group:"Metrics Options"        namespace:"metrics"`
 	Payer      PayerOptions      `group:"Payer Options"          namespace:"payer"`
 	Redis      RedisOptions      `group:"Redis Options"          namespace:"redis"`
-	Tracing    TracingOptions    `group:"DD APM Tracing Options" namespace:"tracing"`
+	Tracing    TracingOptions    `group:"Tracing Options"        namespace:"tracing"`
 	Reflection ReflectionOptions `group:"Reflection Options"     namespace:"reflection"`
 }
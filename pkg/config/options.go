@@ -8,12 +8,14 @@ import (
 )
 
 type APIOptions struct {
-	Enable                      bool          `long:"enable"                         env:"XMTPD_API_ENABLE"                         description:"Enable the client API"`
-	SendKeepAliveInterval       time.Duration `long:"send-keep-alive-interval"       env:"XMTPD_API_SEND_KEEP_ALIVE_INTERVAL"       description:"Send empty application level keepalive package interval"        default:"30s"`
-	Port                        int           `long:"port"                           env:"XMTPD_API_PORT"                           description:"Port to listen on"                                              default:"5050" short:"p"`
-	OriginatorCacheTTL          time.Duration `long:"originator-cache-ttl"           env:"XMTPD_API_ORIGINATOR_CACHE_TTL"           description:"TTL for originator list cache"                                  default:"5m"`
-	RequirePayerPositiveBalance bool          `long:"require-payer-positive-balance" env:"XMTPD_API_REQUIRE_PAYER_POSITIVE_BALANCE" description:"Reject publishes when payer balance is insufficient"`
-	RequireReplicationNodeAuth  bool          `long:"require-replication-node-auth"  env:"XMTPD_API_REQUIRE_REPLICATION_NODE_AUTH"  description:"Require node JWT authentication for all ReplicationApi methods"`
+	Enable                      bool                `long:"enable"                         env:"XMTPD_API_ENABLE"                         description:"Enable the client API"`
+	SendKeepAliveInterval       time.Duration       `long:"send-keep-alive-interval"       env:"XMTPD_API_SEND_KEEP_ALIVE_INTERVAL"       description:"Send empty application level keepalive package interval"                              default:"30s"`
+	Port                        int                 `long:"port"                           env:"XMTPD_API_PORT"                           description:"Port to listen on"                                                                    default:"5050" short:"p"`
+	OriginatorCacheTTL          time.Duration       `long:"originator-cache-ttl"           env:"XMTPD_API_ORIGINATOR_CACHE_TTL"           description:"TTL for originator list cache"                                                        default:"5m"`
+	RequirePayerPositiveBalance bool                `long:"require-payer-positive-balance" env:"XMTPD_API_REQUIRE_PAYER_POSITIVE_BALANCE" description:"Reject publishes when payer balance is insufficient"`
+	RequireReplicationNodeAuth  bool                `long:"require-replication-node-auth"  env:"XMTPD_API_REQUIRE_REPLICATION_NODE_AUTH"  description:"Require node JWT authentication for all ReplicationApi methods"`
+	ReadTopicKindPolicy         TopicKindReadPolicy `long:"read-topic-kind-policy"         env:"XMTPD_API_READ_TOPIC_KIND_POLICY"         description:"Comma-separated <topic kind>=<deny | extra token cost> read rules for Tier 2 callers. When set, Tier 2 callers cannot read by originator"`
+	PublishDedupWindow          time.Duration       `long:"publish-dedup-window"           env:"XMTPD_API_PUBLISH_DEDUP_WINDOW"           description:"Window in which republished payer envelopes are deduplicated (0 disables)"            default:"0s"`
 }
 
 type ContractsOptions struct {
@@ -37,6 +39,7 @@ type AppChainOptions struct {
 	ParameterRegistryAddress         string        `long:"parameter-registry-address"          env:"XMTPD_APP_CHAIN_PARAMETER_REGISTRY_ADDRESS"        description:"Parameter Registry contract address"`
 	DeploymentBlock                  uint64        `long:"deployment-block"                    env:"XMTPD_APP_CHAIN_DEPLOYMENT_BLOCK"                  description:"Deployment block for the application chain"                   default:"0"`
 	MaxBlockchainPayloadSize         uint64        `long:"max-blockchain-payload-size"         env:"XMTPD_APP_CHAIN_MAX_BLOCKCHAIN_PAYLOAD_SIZE"       description:"Max payload size for the App Chain in bytes"                  default:"200000"`
+	MulticallAddress                 string        `long:"multicall-address"                   env:"XMTPD_APP_CHAIN_MULTICALL_ADDRESS"                 description:"Multicall3 contract address for batch transactions"`
 }
 
 type SettlementChainOptions struct {
@@ -95,6 +98,30 @@ type PayerOptions struct {
 	NodeSelectorTimeout        time.Duration `long:"node-selector-connect-timeout" env:"XMTPD_PAYER_NODE_SELECTOR_CONNECT_TIMEOUT" description:"Connection timeout"                             default:"2s"`
 	EnvelopePublishTimeout     time.Duration `long:"envelope-publish-timeout"      env:"XMTPD_PAYER_ENVELOPE_PUBLISH_TIMEOUT"      description:"Envelope publish timeout"                       default:"30s"`
 	EnvelopePublishRetries     uint          `long:"envelope-publish-retries"      env:"XMTPD_PAYER_ENVELOPE_PUBLISH_RETRIES"      description:"How many times to retry publishing an envelope" default:"5"`
+	EnvelopePublishParallelism int           `long:"envelope-publish-parallelism"  env:"XMTPD_PAYER_ENVELOPE_PUBLISH_PARALLELISM"  description:"Destinations published to concurrently"         default:"8"`
+	BlockchainBatchWindow      time.Duration `long:"blockchain-batch-window"       env:"XMTPD_PAYER_BLOCKCHAIN_BATCH_WINDOW"       description:"Time to batch blockchain envelopes, 0 disables" default:"0s"`
+	BlockchainBatchMaxSize     int           `long:"blockchain-batch-max-size"     env:"XMTPD_PAYER_BLOCKCHAIN_BATCH_MAX_SIZE"     description:"Maximum envelopes per batch transaction"        default:"20"`
+	OutboxEnable               bool          `long:"outbox-enable"                 env:"XMTPD_PAYER_OUTBOX_ENABLE"                 description:"Store envelopes in Redis until published"`
+	OutboxRetention            time.Duration `long:"outbox-retention"              env:"XMTPD_PAYER_OUTBOX_RETENTION"              description:"How long publish receipts are kept"             default:"24h"`
+	OutboxReplayInterval       time.Duration `long:"outbox-replay-interval"        env:"XMTPD_PAYER_OUTBOX_REPLAY_INTERVAL"        description:"How often pending envelopes are replayed"       default:"5s"`
+	OutboxMaxAttempts          int           `long:"outbox-max-attempts"           env:"XMTPD_PAYER_OUTBOX_MAX_ATTEMPTS"           description:"Attempts before an envelope fails"              default:"10"`
+
+	NodeSelectorAdaptiveWindow                 time.Duration `long:"node-selector-adaptive-window"                   env:"XMTPD_PAYER_NODE_SELECTOR_ADAPTIVE_WINDOW"                   description:"Window of publish outcomes scored by the adaptive strategy" default:"5m"`
+	NodeSelectorAdaptiveMaxSamples             int           `long:"node-selector-adaptive-max-samples"              env:"XMTPD_PAYER_NODE_SELECTOR_ADAPTIVE_MAX_SAMPLES"              description:"Publish outcomes kept per node by the adaptive strategy"    default:"100"`
+	NodeSelectorAdaptiveMinSamples             int           `long:"node-selector-adaptive-min-samples"              env:"XMTPD_PAYER_NODE_SELECTOR_ADAPTIVE_MIN_SAMPLES"              description:"Publish outcomes required before a node is scored"          default:"10"`
+	NodeSelectorAdaptiveMinSuccessRate         float64       `long:"node-selector-adaptive-min-success-rate"         env:"XMTPD_PAYER_NODE_SELECTOR_ADAPTIVE_MIN_SUCCESS_RATE"         description:"Success rate below which a node is ejected"                 default:"0.5"`
+	NodeSelectorAdaptiveMaxConsecutiveFailures int           `long:"node-selector-adaptive-max-consecutive-failures" env:"XMTPD_PAYER_NODE_SELECTOR_ADAPTIVE_MAX_CONSECUTIVE_FAILURES" description:"Failures in a row after which a node is ejected"            default:"5"`
+	NodeSelectorAdaptiveBaseEjection           time.Duration `long:"node-selector-adaptive-base-ejection"            env:"XMTPD_PAYER_NODE_SELECTOR_ADAPTIVE_BASE_EJECTION"            description:"First ejection time of a node, doubled on every ejection"   default:"10s"`
+	NodeSelectorAdaptiveMaxEjection            time.Duration `long:"node-selector-adaptive-max-ejection"             env:"XMTPD_PAYER_NODE_SELECTOR_ADAPTIVE_MAX_EJECTION"             description:"Maximum ejection time of a node"                            default:"5m"`
+	NodeSelectorAdaptiveLatencyTolerance       float64       `long:"node-selector-adaptive-latency-tolerance"        env:"XMTPD_PAYER_NODE_SELECTOR_ADAPTIVE_LATENCY_TOLERANCE"        description:"Factor of the best p95 latency within which nodes are used" default:"2"`
+	NodeSelectorAdaptiveLatencySlack           time.Duration `long:"node-selector-adaptive-latency-slack"            env:"XMTPD_PAYER_NODE_SELECTOR_ADAPTIVE_LATENCY_SLACK"            description:"Latency added to the tolerated p95 latency"                 default:"50ms"`
+
+	LivenessWindow           time.Duration `long:"liveness-window"             env:"XMTPD_PAYER_LIVENESS_WINDOW"             description:"Window over which the publish outcomes of a node are aggregated"            default:"5m"`
+	LivenessMinRequests      int           `long:"liveness-min-requests"       env:"XMTPD_PAYER_LIVENESS_MIN_REQUESTS"       description:"Publishes to a node required before its error and slow rates are evaluated" default:"10"`
+	LivenessMaxErrorRate     float64       `long:"liveness-max-error-rate"     env:"XMTPD_PAYER_LIVENESS_MAX_ERROR_RATE"     description:"Failed publish rate at which a node is reported unresponsive"               default:"0.5"`
+	LivenessMaxTimeouts      int           `long:"liveness-max-timeouts"       env:"XMTPD_PAYER_LIVENESS_MAX_TIMEOUTS"       description:"Timed out publishes at which a node is reported unresponsive, 0 disables"   default:"5"`
+	LivenessSlowResponseTime time.Duration `long:"liveness-slow-response-time" env:"XMTPD_PAYER_LIVENESS_SLOW_RESPONSE_TIME" description:"Response time at which a publish is slow"                                   default:"5s"`
+	LivenessMaxSlowRate      float64       `long:"liveness-max-slow-rate"      env:"XMTPD_PAYER_LIVENESS_MAX_SLOW_RATE"      description:"Slow publish rate at which a node is reported slow"                         default:"0.5"`
 }
 
 type ReplicationOptions struct {
@@ -102,17 +129,26 @@ type ReplicationOptions struct {
 	Enable bool `long:"enable" env:"XMTPD_REPLICATION_ENABLE" description:"Enable the replication API"`
 }
 
+// SyncOptions are settings for the sync server. Verifying blockchain proofs checks envelopes
+// originated on the app chain against their transaction receipts, instead of trusting the
+// node they were received from.
 type SyncOptions struct {
-	Enable bool `long:"enable" env:"XMTPD_SYNC_ENABLE" description:"Enable the sync server"`
+	Enable                   bool `long:"enable"                      env:"XMTPD_SYNC_ENABLE"                      description:"Enable the sync server"`
+	VerifyBlockchainProofs   bool `long:"verify-blockchain-proofs"    env:"XMTPD_SYNC_VERIFY_BLOCKCHAIN_PROOFS"    description:"Verify the blockchain proofs of synced chain-originated envelopes against the app chain"`
+	BlockchainProofCacheSize int  `long:"blockchain-proof-cache-size" env:"XMTPD_SYNC_BLOCKCHAIN_PROOF_CACHE_SIZE" description:"Number of verified transaction receipts to cache"                                        default:"10000"`
 }
 
 type MlsValidationOptions struct {
-	GrpcAddress string `long:"grpc-address" env:"XMTPD_MLS_VALIDATION_GRPC_ADDRESS" description:"Address of the MLS validation service"`
+	GrpcAddress        string `long:"grpc-address"        env:"XMTPD_MLS_VALIDATION_GRPC_ADDRESS"        description:"Address of the MLS validation service"`
+	SnapshotValidation bool   `long:"snapshot-validation" env:"XMTPD_MLS_VALIDATION_SNAPSHOT_VALIDATION" description:"Validate identity updates against the stored association state of their inbox instead of replaying its history. Requires a validation service accepting GetAssociationStateRequest.old_state"`
 }
 
-// TracingOptions are settings controlling collection of DD APM traces and error tracking.
+// TracingOptions are settings controlling collection of APM traces and error tracking.
 type TracingOptions struct {
-	Enable bool `long:"enable" env:"XMTPD_TRACING_ENABLE" description:"Enable DD APM trace collection"`
+	Enable       bool   `long:"enable"        env:"XMTPD_TRACING_ENABLE"        description:"Enable APM trace collection"`
+	Backend      string `long:"backend"       env:"XMTPD_TRACING_BACKEND"       description:"Tracing backend (datadog or otlp)"            default:"datadog"`
+	OTLPEndpoint string `long:"otlp-endpoint" env:"XMTPD_TRACING_OTLP_ENDPOINT" description:"Host and port of the OTLP gRPC trace collector" default:"localhost:4317"`
+	OTLPInsecure bool   `long:"otlp-insecure" env:"XMTPD_TRACING_OTLP_INSECURE" description:"Connect to the OTLP trace collector without TLS"`
 }
 
 // ReflectionOptions are settings controlling collection of GRPC reflection settings.
@@ -122,12 +158,22 @@ type ReflectionOptions struct {
 
 // RateLimitOptions controls the QueryApi rate-limiting interceptor.
 //
-// Iteration 1 only provides admission-time enforcement: per-IP token buckets
-// on unary QueryApi calls plus a subscribe-opens-per-minute sub-limit.
-// Continual drain of long-held subscribe streams is deferred to
-// xmtp/xmtpd#1957.
+// Admission-time enforcement charges per-IP token buckets on unary QueryApi
+// calls plus a subscribe-opens-per-minute sub-limit. App operators presenting a
+// configured API key or a JWT signed with T1JWTSecret are rate limited as
+// Tier 1, with buckets keyed by operator. Long-held Subscribe and
+// SubscribeTopics streams are continually drained: every StreamBytesPerToken
+// bytes delivered cost one token from the caller's bucket.
 type RateLimitOptions struct {
-	Enable bool `long:"enable" env:"XMTPD_RATE_LIMIT_ENABLE" description:"Enable QueryApi rate limiting (requires Redis)"`
+	Enable  bool   `long:"enable"  env:"XMTPD_RATE_LIMIT_ENABLE"  description:"Enable QueryApi rate limiting"`
+	Backend string `long:"backend" env:"XMTPD_RATE_LIMIT_BACKEND" description:"Rate limiter backend, either redis (shared across replicas) or memory (per process)" default:"redis"`
+
+	// Tier 1 credentials and bucket limits
+	T1APIKeys                 OperatorAPIKeys `long:"t1-api-keys"                   env:"XMTPD_RATE_LIMIT_T1_API_KEYS"                   description:"Comma-separated <operator id>=<hex sha256 of api key> pairs of Tier 1 app operators"`
+	T1JWTSecret               string          `long:"t1-jwt-secret"                 env:"XMTPD_RATE_LIMIT_T1_JWT_SECRET"                 description:"HMAC secret of HS256 JWTs issued to Tier 1 app operators; the subject is the operator id"`
+	T1PerMinuteCapacity       int             `long:"t1-per-minute-capacity"        env:"XMTPD_RATE_LIMIT_T1_PER_MINUTE_CAPACITY"        description:"Tier 1 per-minute token capacity"                                                         default:"6000"`
+	T1PerHourCapacity         int             `long:"t1-per-hour-capacity"          env:"XMTPD_RATE_LIMIT_T1_PER_HOUR_CAPACITY"          description:"Tier 1 per-hour token capacity"                                                           default:"120000"`
+	T1SubscribeOpensPerMinute int             `long:"t1-subscribe-opens-per-minute" env:"XMTPD_RATE_LIMIT_T1_SUBSCRIBE_OPENS_PER_MINUTE" description:"Tier 1 subscribe-opens per minute"                                                        default:"600"`
 
 	// Tier 2 bucket limits
 	T2PerMinuteCapacity       int `long:"t2-per-minute-capacity"        env:"XMTPD_RATE_LIMIT_T2_PER_MINUTE_CAPACITY"        description:"Tier 2 per-minute token capacity"  default:"60"`
@@ -146,6 +192,10 @@ type RateLimitOptions struct {
 	T1MaxConcurrentSubscribeAll int           `long:"t-1-max-concurrent-subscribe-all" env:"XMTPD_RATE_LIMIT_T1_MAX_CONCURRENT_SUBSCRIBE_ALL" description:"Max concurrent SubscribeAllEnvelopes streams per IP"        default:"2"`
 	StreamTTL                   time.Duration `long:"stream-ttl"                       env:"XMTPD_RATE_LIMIT_STREAM_TTL"                      description:"Redis key TTL for stream counters (crash self-heal window)" default:"15m"`
 	StreamRefreshInterval       time.Duration `long:"stream-refresh-interval"          env:"XMTPD_RATE_LIMIT_STREAM_REFRESH_INTERVAL"         description:"How often to refresh stream counter TTL"                    default:"5m"`
+
+	// Continual drain of Subscribe and SubscribeTopics streams
+	StreamBytesPerToken int           `long:"stream-bytes-per-token" env:"XMTPD_RATE_LIMIT_STREAM_BYTES_PER_TOKEN" description:"Subscribe and SubscribeTopics stream bytes delivered per token charged to the caller's bucket; 0 disables the drain" default:"65536"`
+	StreamMaxWait       time.Duration `long:"stream-max-wait"        env:"XMTPD_RATE_LIMIT_STREAM_MAX_WAIT"        description:"How long a subscribe stream waits for its bucket to refill before it is terminated"                                  default:"5s"`
 }
 
 type LogOptions struct {
@@ -166,6 +216,18 @@ type PayerReportOptions struct {
 	ExpiryOthersPeriod            time.Duration `long:"expiry-others-period"             env:"XMTPD_PAYER_REPORT_EXPIRY_OTHERS_PERIOD"             description:"Period of time we should use to expire backup payer reports about other nodes"   default:"18h"`
 }
 
+// PayerBalanceOptions controls the worker that forecasts when payers will run out of funds.
+type PayerBalanceOptions struct {
+	Enable            bool          `long:"enable"             env:"XMTPD_PAYER_BALANCE_ENABLE"             description:"Periodically forecast payer balance depletion and notify when payers cross a balance level"`
+	Interval          time.Duration `long:"interval"           env:"XMTPD_PAYER_BALANCE_INTERVAL"           description:"Interval between balance forecasts"                                                         default:"5m"`
+	SpendWindow       time.Duration `long:"spend-window"       env:"XMTPD_PAYER_BALANCE_SPEND_WINDOW"       description:"Period of recent usage the spend rate is averaged over"                                     default:"24h"`
+	WarningThreshold  time.Duration `long:"warning-threshold"  env:"XMTPD_PAYER_BALANCE_WARNING_THRESHOLD"  description:"Payers forecast to run out of funds within this period have a low balance"                  default:"72h"`
+	CriticalThreshold time.Duration `long:"critical-threshold" env:"XMTPD_PAYER_BALANCE_CRITICAL_THRESHOLD" description:"Payers forecast to run out of funds within this period have a critical balance"             default:"24h"`
+	WebhookURL        string        `long:"webhook-url"        env:"XMTPD_PAYER_BALANCE_WEBHOOK_URL"        description:"URL to POST balance level changes to. Changes are only logged when empty"`
+	WebhookSecret     string        `long:"webhook-secret"     env:"XMTPD_PAYER_BALANCE_WEBHOOK_SECRET"     description:"Secret to sign webhook requests with, in the X-XMTPD-Signature header"`
+	WebhookTimeout    time.Duration `long:"webhook-timeout"    env:"XMTPD_PAYER_BALANCE_WEBHOOK_TIMEOUT"    description:"Timeout for webhook requests"                                                               default:"10s"`
+}
+
 type ServerOptions struct {
 	API             APIOptions             `group:"API Options"              namespace:"api"`
 	Contracts       ContractsOptions       `group:"Contracts Options"        namespace:"contracts"`
@@ -176,13 +238,15 @@ type ServerOptions struct {
 	MlsValidation   MlsValidationOptions   `group:"MLS Validation Options"   namespace:"mls-validation"`
 	Payer           PayerOptions           `group:"Payer Options"            namespace:"payer"`
 	PayerReport     PayerReportOptions     `group:"Payer Report Options"     namespace:"payer-report"`
+	PayerBalance    PayerBalanceOptions    `group:"Payer Balance Options"    namespace:"payer-balance"`
+	Prune           PruneServiceOptions    `group:"Prune Options"            namespace:"prune"`
 	Redis           RedisOptions           `group:"Redis Options"            namespace:"redis"`
 	RateLimit       RateLimitOptions       `group:"Rate Limit Options"       namespace:"rate-limit"`
 	Reflection      ReflectionOptions      `group:"Reflection Options"       namespace:"reflection"`
 	Replication     ReplicationOptions     `group:"API Options"              namespace:"replication"`
 	Signer          SignerOptions          `group:"Signer Options"           namespace:"signer"`
 	Sync            SyncOptions            `group:"Sync Options"             namespace:"sync"`
-	Tracing         TracingOptions         `group:"DD APM Tracing Options"   namespace:"tracing"`
+	Tracing         TracingOptions         `group:"Tracing Options"          namespace:"tracing"`
 	MigrationServer MigrationServerOptions `group:"Migration Server Options" namespace:"migration-server"`
 	MigrationClient MigrationClientOptions `group:"Migration Client Options" namespace:"migration-client"`
 	Debug           DebugOptions           `group:"Debug Options"            namespace:"debug"`
@@ -1,13 +1,37 @@
 package config
 
+import "time"
+
 type PruneConfig struct {
-	MaxCycles      int   `long:"max-prune-cycles" env:"XMTPD_PRUNE_MAX_CYCLES"      description:"Maximum pruning cycles"                   default:"10"`
-	BatchSize      int32 `long:"batch-size"       env:"XMTPD_PRUNE_BATCH_SIZE"      description:"Batch size"                               default:"10000"`
-	CountDeletable bool  `long:"count-deletable"  env:"XMTPD_PRUNE_COUNT_DELETABLE" description:"Attempt to count all deletable envelopes"`
-	DryRun         bool  `long:"dry-run"          env:"XMTPD_PRUNE_DRY_RUN"         description:"Dry run mode"`
+	MaxCycles          int                `long:"max-prune-cycles"    env:"XMTPD_PRUNE_MAX_CYCLES"         description:"Maximum pruning cycles"                                                                 default:"10"`
+	BatchSize          int32              `long:"batch-size"          env:"XMTPD_PRUNE_BATCH_SIZE"         description:"Batch size"                                                                             default:"10000"`
+	CountDeletable     bool               `long:"count-deletable"     env:"XMTPD_PRUNE_COUNT_DELETABLE"    description:"Attempt to count all deletable envelopes"`
+	DryRun             bool               `long:"dry-run"             env:"XMTPD_PRUNE_DRY_RUN"            description:"Dry run mode"`
+	RetentionOverrides TopicKindRetention `long:"retention-overrides" env:"XMTPD_PRUNE_RETENTION_OVERRIDES" description:"Comma-separated per topic kind retention, replacing the envelope expiry (e.g. key_packages_v1=720h)"`
+	UsageRetention     time.Duration      `long:"usage-retention"     env:"XMTPD_PRUNE_USAGE_RETENTION"    description:"How long payer usage history is kept, 0 keeps it forever"                               default:"8760h"`
+	Archive            ArchiveOptions     `                                                                  group:"Archive Options" namespace:"archive"`
+}
+
+// ArchiveOptions configures where pruned envelopes are archived before they are deleted.
+type ArchiveOptions struct {
+	Store             string `long:"store"                env:"XMTPD_PRUNE_ARCHIVE_STORE"                description:"Archive pruned envelopes to this store before deleting them. Either filesystem or s3. Disabled when empty"`
+	Dir               string `long:"dir"                  env:"XMTPD_PRUNE_ARCHIVE_DIR"                  description:"Directory of the filesystem archive store"`
+	S3Endpoint        string `long:"s3-endpoint"          env:"XMTPD_PRUNE_ARCHIVE_S3_ENDPOINT"          description:"Endpoint URL of the S3-compatible archive store"`
+	S3Bucket          string `long:"s3-bucket"            env:"XMTPD_PRUNE_ARCHIVE_S3_BUCKET"            description:"Bucket of the S3-compatible archive store"`
+	S3Region          string `long:"s3-region"            env:"XMTPD_PRUNE_ARCHIVE_S3_REGION"            description:"Region of the S3-compatible archive store"                                                               default:"us-east-1"`
+	S3AccessKeyID     string `long:"s3-access-key-id"     env:"XMTPD_PRUNE_ARCHIVE_S3_ACCESS_KEY_ID"     description:"Access key ID of the S3-compatible archive store"`
+	S3SecretAccessKey string `long:"s3-secret-access-key" env:"XMTPD_PRUNE_ARCHIVE_S3_SECRET_ACCESS_KEY" description:"Secret access key of the S3-compatible archive store"`
 }
 type PruneOptions struct {
 	DB          DBOptions   `group:"Database Options" namespace:"db"`
 	Log         LogOptions  `group:"Log Options"      namespace:"log"`
 	PruneConfig PruneConfig `group:"Prune Options"    namespace:"prune"`
 }
+
+// PruneServiceOptions controls the pruning service running inside the node.
+type PruneServiceOptions struct {
+	Enable   bool          `long:"enable"   env:"XMTPD_PRUNE_ENABLE"   description:"Periodically prune expired envelopes from within the node"`
+	Interval time.Duration `long:"interval" env:"XMTPD_PRUNE_INTERVAL" description:"Interval between pruning runs"                                                                  default:"1h"`
+	Window   DailyWindow   `long:"window"   env:"XMTPD_PRUNE_WINDOW"   description:"Daily UTC window to prune in, as HH:MM-HH:MM (e.g. 02:00-05:00). Prunes at any time when empty"`
+	PruneConfig
+}
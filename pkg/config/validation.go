@@ -49,6 +49,9 @@ func (v *OptionsValidator) ValidateServerOptions(options *ServerOptions) error {
 			"mls-validation.grpc-address",
 			missingSet,
 		)
+		if options.API.PublishDedupWindow < 0 {
+			customSet["--api.publish-dedup-window must be greater than or equal to 0"] = struct{}{}
+		}
 	}
 
 	if options.Indexer.Enable {
@@ -63,6 +66,31 @@ func (v *OptionsValidator) ValidateServerOptions(options *ServerOptions) error {
 		if err := v.validatePayerOptions(&options.Payer, customSet); err != nil {
 			return err
 		}
+		if options.Payer.BlockchainBatchWindow > 0 &&
+			!common.IsHexAddress(options.Contracts.AppChain.MulticallAddress) {
+			customSet["--contracts.app-chain.multicall-address is required to batch"] = struct{}{}
+		}
+	}
+
+	if options.Prune.Enable {
+		v.validatePruneServiceOptions(&options.Prune, customSet)
+	}
+
+	if options.PayerBalance.Enable {
+		v.validatePayerBalanceOptions(&options.PayerBalance, customSet)
+	}
+
+	if options.RateLimit.Enable {
+		v.validateRateLimitOptions(&options.RateLimit, options.Redis, customSet)
+	}
+
+	if options.Tracing.Enable {
+		v.validateTracingOptions(&options.Tracing, customSet)
+	}
+
+	if options.Sync.Enable && options.Sync.VerifyBlockchainProofs &&
+		options.Sync.BlockchainProofCacheSize <= 0 {
+		customSet["--sync.blockchain-proof-cache-size must be greater than 0"] = struct{}{}
 	}
 
 	if options.MigrationServer.Enable {
@@ -115,7 +143,7 @@ func (v *OptionsValidator) ValidatePruneOptions(options PruneOptions) error {
 		return errors.New("max-cycles must be greater than 0")
 	}
 
-	return nil
+	return v.validateArchiveOptions(&options.PruneConfig.Archive)
 }
 
 func (v *OptionsValidator) validateMigrationOptions(
@@ -477,16 +505,17 @@ func (v *OptionsValidator) validatePayerOptions(
 	customSet map[string]struct{},
 ) error {
 	validStrategies := map[string]bool{
-		"stable":  true,
-		"manual":  true,
-		"ordered": true,
-		"random":  true,
-		"closest": true,
+		"stable":   true,
+		"manual":   true,
+		"ordered":  true,
+		"random":   true,
+		"closest":  true,
+		"adaptive": true,
 	}
 
 	if options.NodeSelectorStrategy != "" && !validStrategies[options.NodeSelectorStrategy] {
 		return fmt.Errorf(
-			"invalid node-selector-strategy: %s (must be one of: stable, manual, ordered, random, closest)",
+			"invalid node-selector-strategy: %s (must be one of: stable, manual, ordered, random, closest, adaptive)",
 			options.NodeSelectorStrategy,
 		)
 	}
@@ -499,6 +528,55 @@ func (v *OptionsValidator) validatePayerOptions(
 		)
 	}
 
+	if options.NodeSelectorStrategy == "adaptive" {
+		if options.NodeSelectorAdaptiveWindow <= 0 {
+			customSet["--payer.node-selector-adaptive-window must be greater than 0"] = struct{}{}
+		}
+		if options.NodeSelectorAdaptiveMaxSamples <= 0 {
+			customSet["--payer.node-selector-adaptive-max-samples must be greater than 0"] = struct{}{}
+		}
+		if options.NodeSelectorAdaptiveMinSuccessRate < 0 ||
+			options.NodeSelectorAdaptiveMinSuccessRate > 1 {
+			customSet["--payer.node-selector-adaptive-min-success-rate must be between 0 and 1"] = struct{}{}
+		}
+		if options.NodeSelectorAdaptiveMaxConsecutiveFailures <= 0 {
+			customSet["--payer.node-selector-adaptive-max-consecutive-failures must be greater than 0"] = struct{}{}
+		}
+		if options.NodeSelectorAdaptiveBaseEjection <= 0 {
+			customSet["--payer.node-selector-adaptive-base-ejection must be greater than 0"] = struct{}{}
+		}
+		if options.NodeSelectorAdaptiveMaxEjection < options.NodeSelectorAdaptiveBaseEjection {
+			customSet["--payer.node-selector-adaptive-max-ejection must be at least the base ejection"] = struct{}{}
+		}
+		if options.NodeSelectorAdaptiveLatencyTolerance < 1 {
+			customSet["--payer.node-selector-adaptive-latency-tolerance must be at least 1"] = struct{}{}
+		}
+	}
+
+	if options.LivenessWindow <= 0 {
+		customSet["--payer.liveness-window must be greater than 0"] = struct{}{}
+	}
+
+	if options.LivenessMinRequests <= 0 {
+		customSet["--payer.liveness-min-requests must be greater than 0"] = struct{}{}
+	}
+
+	if options.LivenessMaxErrorRate < 0 || options.LivenessMaxErrorRate > 1 {
+		customSet["--payer.liveness-max-error-rate must be between 0 and 1"] = struct{}{}
+	}
+
+	if options.LivenessMaxTimeouts < 0 {
+		customSet["--payer.liveness-max-timeouts must not be negative"] = struct{}{}
+	}
+
+	if options.LivenessSlowResponseTime <= 0 {
+		customSet["--payer.liveness-slow-response-time must be greater than 0"] = struct{}{}
+	}
+
+	if options.LivenessMaxSlowRate < 0 || options.LivenessMaxSlowRate > 1 {
+		customSet["--payer.liveness-max-slow-rate must be between 0 and 1"] = struct{}{}
+	}
+
 	if options.NodeSelectorCacheExpiry <= 0 {
 		customSet["--payer.node-selector-cache-expiry must be greater than 0"] = struct{}{}
 	}
@@ -507,6 +585,146 @@ func (v *OptionsValidator) validatePayerOptions(
 		customSet["--payer.node-selector-connect-timeout must be greater than 0"] = struct{}{}
 	}
 
+	if options.EnvelopePublishParallelism <= 0 {
+		customSet["--payer.envelope-publish-parallelism must be greater than 0"] = struct{}{}
+	}
+
+	if options.BlockchainBatchWindow > 0 && options.BlockchainBatchMaxSize <= 0 {
+		customSet["--payer.blockchain-batch-max-size must be greater than 0"] = struct{}{}
+	}
+
+	if options.OutboxEnable {
+		if options.OutboxRetention <= 0 {
+			customSet["--payer.outbox-retention must be greater than 0"] = struct{}{}
+		}
+		if options.OutboxReplayInterval <= 0 {
+			customSet["--payer.outbox-replay-interval must be greater than 0"] = struct{}{}
+		}
+		if options.OutboxMaxAttempts <= 0 {
+			customSet["--payer.outbox-max-attempts must be greater than 0"] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+func (v *OptionsValidator) validatePruneServiceOptions(
+	options *PruneServiceOptions,
+	customSet map[string]struct{},
+) {
+	if options.Interval <= 0 {
+		customSet["--prune.interval must be greater than 0"] = struct{}{}
+	}
+
+	if options.MaxCycles < 1 {
+		customSet["--prune.max-prune-cycles must be greater than 0"] = struct{}{}
+	}
+
+	if options.BatchSize < 1 {
+		customSet["--prune.batch-size must be greater than 0"] = struct{}{}
+	}
+
+	if err := v.validateArchiveOptions(&options.Archive); err != nil {
+		customSet[err.Error()] = struct{}{}
+	}
+}
+
+func (v *OptionsValidator) validatePayerBalanceOptions(
+	options *PayerBalanceOptions,
+	customSet map[string]struct{},
+) {
+	if options.Interval <= 0 {
+		customSet["--payer-balance.interval must be greater than 0"] = struct{}{}
+	}
+
+	if options.SpendWindow < time.Minute {
+		customSet["--payer-balance.spend-window must be at least 1m"] = struct{}{}
+	}
+
+	if options.CriticalThreshold <= 0 {
+		customSet["--payer-balance.critical-threshold must be greater than 0"] = struct{}{}
+	}
+
+	if options.WarningThreshold < options.CriticalThreshold {
+		customSet["--payer-balance.warning-threshold is below the critical threshold"] = struct{}{}
+	}
+
+	if options.WebhookURL != "" {
+		u, err := url.Parse(options.WebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			customSet["--payer-balance.webhook-url must be an http or https URL"] = struct{}{}
+		}
+		if options.WebhookTimeout <= 0 {
+			customSet["--payer-balance.webhook-timeout must be greater than 0"] = struct{}{}
+		}
+	}
+}
+
+func (v *OptionsValidator) validateRateLimitOptions(
+	options *RateLimitOptions,
+	redisOptions RedisOptions,
+	customSet map[string]struct{},
+) {
+	switch options.Backend {
+	case "redis":
+		if redisOptions.RedisURL == "" {
+			customSet["--redis.redis-url is required for the redis rate limit backend"] = struct{}{}
+		}
+	case "memory":
+	default:
+		customSet[fmt.Sprintf(
+			"--rate-limit.backend %q is invalid (must be redis or memory)",
+			options.Backend,
+		)] = struct{}{}
+	}
+
+	if options.StreamBytesPerToken < 0 {
+		customSet["--rate-limit.stream-bytes-per-token must not be negative"] = struct{}{}
+	}
+	if options.StreamMaxWait < 0 {
+		customSet["--rate-limit.stream-max-wait must not be negative"] = struct{}{}
+	}
+}
+
+func (v *OptionsValidator) validateTracingOptions(
+	options *TracingOptions,
+	customSet map[string]struct{},
+) {
+	switch options.Backend {
+	case "datadog":
+	case "otlp":
+		if options.OTLPEndpoint == "" {
+			customSet["--tracing.otlp-endpoint is required for the otlp backend"] = struct{}{}
+		}
+	default:
+		customSet[fmt.Sprintf(
+			"--tracing.backend %q is invalid (must be datadog or otlp)",
+			options.Backend,
+		)] = struct{}{}
+	}
+}
+
+func (v *OptionsValidator) validateArchiveOptions(options *ArchiveOptions) error {
+	switch options.Store {
+	case "":
+		return nil
+	case "filesystem":
+		if options.Dir == "" {
+			return errors.New("--prune.archive.dir is required for the filesystem archive store")
+		}
+	case "s3":
+		if options.S3Endpoint == "" || options.S3Bucket == "" {
+			return errors.New(
+				"--prune.archive.s3-endpoint and --prune.archive.s3-bucket are required for the s3 archive store",
+			)
+		}
+	default:
+		return fmt.Errorf(
+			"--prune.archive.store %q is invalid (must be filesystem or s3)",
+			options.Store,
+		)
+	}
+
 	return nil
 }
 
@@ -6,7 +6,9 @@ const (
 	PayerDomainSeparationLabel            = "payer|"
 	TargetOriginatorDomainSeparationLabel = "target|"
 	OriginatorDomainSeparationLabel       = "originator|"
+	MisbehaviorDomainSeparationLabel      = "misbehavior|"
 	NodeAuthorizationHeaderName           = "node-authorization"
+	OperatorAuthorizationHeaderName       = "operator-authorization"
 	DefaultStorageDurationDays            = 60
 
 	// Indexer originator IDs for group messages and identity updates.
@@ -18,3 +20,10 @@ const (
 )
 
 type VerifiedNodeRequestCtxKey struct{}
+
+// VerifiedNodeIDCtxKey holds the node ID of a request carrying a verified node JWT.
+type VerifiedNodeIDCtxKey struct{}
+
+// VerifiedOperatorIDCtxKey holds the app operator ID of a request carrying verified
+// Tier 1 credentials.
+type VerifiedOperatorIDCtxKey struct{}
@@ -2,11 +2,14 @@
 // It includes two styles:
 //   - AdvisoryLocker: inherits the caller's transaction/connection.
 //   - TransactionScopedAdvisoryLocker: creates and owns its own transaction.
+//   - SessionScopedAdvisoryLocker: creates and owns its own connection, without a transaction.
 package db
 
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"time"
 
 	"github.com/xmtp/xmtpd/pkg/db/queries"
 )
@@ -15,12 +18,15 @@ import (
 type LockKind uint8
 
 const (
-	LockKindIdentityUpdateInsert LockKind = 0x00
-	LockKindAttestationWorker    LockKind = 0x01
-	LockKindSubmitterWorker      LockKind = 0x02
-	LockKindSettlementWorker     LockKind = 0x03
-	LockKindGeneratorWorker      LockKind = 0x04
-	LockKindPartitionCreation    LockKind = 0x05
+	LockKindIdentityUpdateInsert  LockKind = 0x00
+	LockKindAttestationWorker     LockKind = 0x01
+	LockKindSubmitterWorker       LockKind = 0x02
+	LockKindSettlementWorker      LockKind = 0x03
+	LockKindGeneratorWorker       LockKind = 0x04
+	LockKindPartitionCreation     LockKind = 0x05
+	LockKindPruneWorker           LockKind = 0x06
+	LockKindBalanceForecastWorker LockKind = 0x07
+	LockKindPayerLedgerInsert     LockKind = 0x08
 )
 
 // partitionCreationLockKey is the single, global advisory-lock key coordinating lazy
@@ -46,6 +52,15 @@ func (a *AdvisoryLocker) LockIdentityUpdateInsert(
 	return queries.AdvisoryLockWithKey(ctx, key)
 }
 
+// LockPayerLedgerInsert serializes payer ledger inserts, so that ledger events commit in the
+// order of their sequence IDs and a reader paging by sequence ID never skips an event.
+func (a *AdvisoryLocker) LockPayerLedgerInsert(
+	ctx context.Context,
+	queries *queries.Queries,
+) error {
+	return queries.AdvisoryLockWithKey(ctx, int64(LockKindPayerLedgerInsert))
+}
+
 // SharedLockPartitionCreation takes the shared (reader) side of the partition-creation
 // reader/writer advisory lock. Ordinary gateway-envelope inserts hold it so they run
 // concurrently with each other, but block (and are blocked by) exclusive partition creation.
@@ -107,12 +122,30 @@ func (a *AdvisoryLocker) TryLockSettlementWorker(
 	return queries.TryAdvisoryLockWithKey(ctx, key)
 }
 
+func (a *AdvisoryLocker) TryLockPruneWorker(
+	ctx context.Context,
+	queries *queries.Queries,
+) (bool, error) {
+	key := int64(LockKindPruneWorker)
+	return queries.TryAdvisoryLockWithKey(ctx, key)
+}
+
+func (a *AdvisoryLocker) TryLockBalanceForecastWorker(
+	ctx context.Context,
+	queries *queries.Queries,
+) (bool, error) {
+	key := int64(LockKindBalanceForecastWorker)
+	return queries.TryAdvisoryLockWithKey(ctx, key)
+}
+
 type ITransactionScopedAdvisoryLocker interface {
 	Release() error
 	TryLockGeneratorWorker() (bool, error)
 	TryLockAttestationWorker() (bool, error)
 	TryLockSubmitterWorker() (bool, error)
 	TryLockSettlementWorker() (bool, error)
+	TryLockPruneWorker() (bool, error)
+	TryLockBalanceForecastWorker() (bool, error)
 	LockIdentityUpdateInsert(nodeID uint32) error
 }
 
@@ -158,6 +191,72 @@ func (a *TransactionScopedAdvisoryLocker) TryLockSettlementWorker() (bool, error
 	return a.locker.TryLockSettlementWorker(a.ctx, queries.New(a.tx))
 }
 
+func (a *TransactionScopedAdvisoryLocker) TryLockPruneWorker() (bool, error) {
+	return a.locker.TryLockPruneWorker(a.ctx, queries.New(a.tx))
+}
+
+func (a *TransactionScopedAdvisoryLocker) TryLockBalanceForecastWorker() (bool, error) {
+	return a.locker.TryLockBalanceForecastWorker(a.ctx, queries.New(a.tx))
+}
+
 func (a *TransactionScopedAdvisoryLocker) LockIdentityUpdateInsert(nodeID uint32) error {
 	return a.locker.LockIdentityUpdateInsert(a.ctx, queries.New(a.tx), nodeID)
 }
+
+// sessionUnlockTimeout bounds releasing a session-level lock, which must happen even after the
+// locker's context is done.
+const sessionUnlockTimeout = 5 * time.Second
+
+// SessionScopedAdvisoryLocker creates and owns a connection; methods acquire session-level
+// advisory locks on it, without holding a transaction open. Release() unlocks and returns the
+// connection to the pool.
+type SessionScopedAdvisoryLocker struct {
+	conn *sql.Conn
+	ctx  context.Context
+	key  *int64
+}
+
+func NewSessionScopedAdvisoryLocker(
+	ctx context.Context,
+	db *sql.DB,
+) (*SessionScopedAdvisoryLocker, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &SessionScopedAdvisoryLocker{conn: conn, ctx: ctx}, nil
+}
+
+// Release unlocks any lock held and returns the connection to the pool. A connection whose lock
+// could not be released is discarded instead, which drops the lock with the session.
+func (a *SessionScopedAdvisoryLocker) Release() error {
+	if a.key != nil {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(a.ctx), sessionUnlockTimeout)
+		defer cancel()
+
+		if _, err := queries.New(a.conn).AdvisoryUnlockWithKey(ctx, *a.key); err != nil {
+			_ = a.conn.Raw(func(any) error { return driver.ErrBadConn })
+			_ = a.conn.Close()
+			return err
+		}
+		a.key = nil
+	}
+	return a.conn.Close()
+}
+
+func (a *SessionScopedAdvisoryLocker) TryLockBalanceForecastWorker() (bool, error) {
+	return a.tryLock(int64(LockKindBalanceForecastWorker))
+}
+
+func (a *SessionScopedAdvisoryLocker) tryLock(key int64) (bool, error) {
+	locked, err := queries.New(a.conn).TrySessionAdvisoryLockWithKey(a.ctx, key)
+	if err != nil {
+		// The lock may have been taken before the error, so Release still unlocks it.
+		a.key = &key
+		return false, err
+	}
+	if locked {
+		a.key = &key
+	}
+	return locked, nil
+}
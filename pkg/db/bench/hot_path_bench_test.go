@@ -152,6 +152,7 @@ func BenchmarkHotPathInsertGatewayWithUsage(b *testing.B) {
 				MinutesSinceEpoch: minute,
 				SpendPicodollars:  1_000_000,
 			},
+			0,
 			true,
 		)
 		require.NoError(b, err)
@@ -245,6 +246,7 @@ func BenchmarkHotPathFullCycle(b *testing.B) {
 				MinutesSinceEpoch: minute,
 				SpendPicodollars:  1_000_000,
 			},
+			0,
 			true,
 		)
 		require.NoError(b, err)
@@ -55,8 +55,9 @@ func EnsureGatewayPartitions(
 // Steps:
 //  1. Calls InsertGatewayEnvelopeWithChecksTransactional() to insert the envelope,
 //     automatically creating any missing partitions if needed.
-//  2. If a new envelope is inserted, increments unsettled usage and congestion
-//     counters for the originator within the same transaction.
+//  2. If a new envelope is inserted, increments unsettled usage, the payer's usage
+//     history and congestion counters for the originator within the same transaction.
+//     congestionPicodollars is the part of the spend that was charged for congestion.
 //  3. If the envelope already exists (duplicate insert), metrics are not updated.
 //
 // The function ensures atomicity between envelope insertion and usage updates.
@@ -67,6 +68,7 @@ func InsertGatewayEnvelopeAndIncrementUnsettledUsage(
 	db *sql.DB,
 	insertParams queries.InsertGatewayEnvelopeV3Params,
 	incrementParams queries.IncrementUnsettledUsageParams,
+	congestionPicodollars int64,
 	incrementCongestion bool,
 ) (int64, error) {
 	insertTx := func(ctx context.Context, txQueries *queries.Queries) (int64, error) {
@@ -98,6 +100,22 @@ func InsertGatewayEnvelopeAndIncrementUnsettledUsage(
 			return 0, err
 		}
 
+		err = txQueries.IncrementPayerUsageHistory(
+			ctx,
+			queries.IncrementPayerUsageHistoryParams{
+				PayerID:                  incrementParams.PayerID,
+				MinutesSinceEpoch:        incrementParams.MinutesSinceEpoch,
+				OriginatorID:             incrementParams.OriginatorID,
+				TopicKind:                topicKind(insertParams.Topic),
+				BaseFeePicodollars:       incrementParams.SpendPicodollars - congestionPicodollars,
+				CongestionFeePicodollars: congestionPicodollars,
+				MessageCount:             incrementParams.MessageCount,
+			},
+		)
+		if err != nil {
+			return 0, err
+		}
+
 		if !incrementCongestion {
 			return numInserted.InsertedMetaRows, nil
 		}
@@ -220,3 +238,11 @@ func InsertGatewayEnvelopeWithChecksStandalone(
 	// retry insert
 	return q.InsertGatewayEnvelopeV3(ctx, row)
 }
+
+// topicKind returns the kind of a serialized topic, which is its first byte.
+func topicKind(topic []byte) int16 {
+	if len(topic) == 0 {
+		return -1
+	}
+	return int16(topic[0])
+}
@@ -12,7 +12,8 @@ import (
 )
 
 // InsertGatewayEnvelopeBatchV2AndIncrementUnsettledUsage inserts a batch of gateway envelopes,
-// updates unsettled usage, and tracks originator congestion within a single database transaction.
+// updates unsettled usage and payer usage history, and tracks originator congestion within a
+// single database transaction.
 //
 // This is a convenience wrapper that creates its own transaction and, on a missing partition,
 // creates it out-of-band under the exclusive partition-creation lock before retrying. Use
@@ -57,7 +58,7 @@ func InsertGatewayEnvelopeBatchV2Transactional(
 		return 0, errors.New("empty input")
 	}
 
-	params := input.ToParamsV3()
+	params := input.ToParamsV4()
 
 	if err := NewAdvisoryLocker().SharedLockPartitionCreation(ctx, q); err != nil {
 		return 0, err
@@ -68,7 +69,7 @@ func InsertGatewayEnvelopeBatchV2Transactional(
 		return 0, err
 	}
 
-	result, err := q.InsertGatewayEnvelopeBatchV3(ctx, params)
+	result, err := q.InsertGatewayEnvelopeBatchV4(ctx, params)
 	if err == nil {
 		_ = q.InsertSavePointRelease(ctx)
 		return result.InsertedMetaRows, nil
@@ -56,6 +56,7 @@ func TestInsertAndIncrement(t *testing.T) {
 		db,
 		insertParams,
 		incrementParams,
+		0,
 		true,
 	)
 	require.NoError(t, err)
@@ -92,6 +93,7 @@ func TestPayerMustExist(t *testing.T) {
 		db,
 		insertParams,
 		incrementParams,
+		0,
 		true,
 	)
 	require.Error(t, err)
@@ -121,6 +123,7 @@ func TestInsertAndIncrementParallel(t *testing.T) {
 			db,
 			insertParams,
 			incrementParams,
+			0,
 			true,
 		)
 		require.NoError(t, err)
@@ -173,6 +176,7 @@ func TestInsertAndIncrementWithOutOfOrderSequenceID(t *testing.T) {
 		db,
 		insertParams,
 		incrementParams,
+		0,
 		true,
 	)
 	require.NoError(t, err)
@@ -186,6 +190,7 @@ func TestInsertAndIncrementWithOutOfOrderSequenceID(t *testing.T) {
 		db,
 		insertParams,
 		incrementParams,
+		0,
 		true,
 	)
 	require.NoError(t, err)
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS misbehavior_reports;
\ No newline at end of file
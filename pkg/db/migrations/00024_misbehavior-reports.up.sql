@@ -0,0 +1,19 @@
+-- Misbehavior reports detected locally or submitted by other nodes.
+CREATE TABLE misbehavior_reports (
+    id BIGSERIAL PRIMARY KEY,
+    -- Server time when the report was stored. Used for querying and pagination.
+    server_time_ns BIGINT NOT NULL,
+    reporter_node_id INT NOT NULL,
+    misbehaving_node_id INT NOT NULL,
+    -- Matches the xmtp.xmtpv4.message_api.Misbehavior enum.
+    misbehavior_type SMALLINT NOT NULL,
+    submitted_by_node BOOLEAN NOT NULL,
+    -- Serialized UnsignedMisbehaviorReport.
+    unsigned_report BYTEA NOT NULL,
+    -- Signature of the unsigned report by the node hosting the report.
+    signature BYTEA NOT NULL
+);
+
+CREATE INDEX misbehavior_reports_server_time_idx ON misbehavior_reports (server_time_ns, id);
+
+CREATE INDEX misbehavior_reports_misbehaving_node_idx ON misbehavior_reports (misbehaving_node_id, server_time_ns);
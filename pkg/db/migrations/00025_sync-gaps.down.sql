@@ -0,0 +1 @@
+DROP TABLE IF EXISTS sync_gaps;
\ No newline at end of file
@@ -0,0 +1,12 @@
+-- Ranges of originator sequence IDs the sync worker skipped over and has yet to backfill.
+CREATE TABLE sync_gaps (
+    originator_node_id INT NOT NULL,
+    -- First and last missing sequence IDs, inclusive.
+    start_sequence_id BIGINT NOT NULL,
+    end_sequence_id BIGINT NOT NULL,
+    -- Server time when the gap was detected.
+    detected_at_ns BIGINT NOT NULL,
+    -- Backfill attempts that did not make progress, since the gap was detected or last shrunk.
+    attempts INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (originator_node_id, start_sequence_id)
+);
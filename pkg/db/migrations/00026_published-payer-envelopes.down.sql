@@ -0,0 +1 @@
+DROP TABLE IF EXISTS published_payer_envelopes;
@@ -0,0 +1,12 @@
+-- Originator envelopes signed for recently published payer envelopes, keyed on the hash of the
+-- payer envelope, so a client retrying a publish gets the original originator envelope back.
+CREATE TABLE published_payer_envelopes (
+    payer_envelope_hash BYTEA PRIMARY KEY,
+    originator_sequence_id BIGINT NOT NULL,
+    -- Serialized OriginatorEnvelope returned to the client.
+    originator_envelope BYTEA NOT NULL,
+    published_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX published_payer_envelopes_published_at_idx
+    ON published_payer_envelopes (published_at);
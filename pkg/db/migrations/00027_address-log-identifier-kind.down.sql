@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS address_log_inbox_id_idx;
+
+DELETE FROM address_log WHERE identifier_kind <> 1;
+
+ALTER TABLE address_log DROP CONSTRAINT address_log_pkey;
+
+ALTER TABLE address_log ADD PRIMARY KEY (address, inbox_id);
+
+ALTER TABLE address_log DROP COLUMN identifier_kind;
@@ -0,0 +1,11 @@
+-- Identifiers are unique within their kind only. Existing rows are all Ethereum addresses
+-- (IDENTIFIER_KIND_ETHEREUM = 1).
+ALTER TABLE address_log
+    ADD COLUMN identifier_kind SMALLINT NOT NULL DEFAULT 1;
+
+ALTER TABLE address_log DROP CONSTRAINT address_log_pkey;
+
+ALTER TABLE address_log ADD PRIMARY KEY (address, identifier_kind, inbox_id);
+
+-- Serves reverse lookups of the identifiers of an inbox.
+CREATE INDEX address_log_inbox_id_idx ON address_log (inbox_id);
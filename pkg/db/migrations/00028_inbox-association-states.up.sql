@@ -0,0 +1,8 @@
+-- The latest association state of each inbox, as of the identity update with sequence_id.
+CREATE TABLE inbox_association_states (
+    inbox_id BYTEA PRIMARY KEY,
+    -- Serialized xmtp.identity.associations.AssociationState.
+    association_state BYTEA NOT NULL,
+    sequence_id BIGINT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
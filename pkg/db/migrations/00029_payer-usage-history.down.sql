@@ -0,0 +1,6 @@
+DROP FUNCTION IF EXISTS insert_gateway_envelope_batch_v4(
+    int[], bigint[], bytea[], int[], timestamp[], bigint[], bytea[], bigint[], bigint[], boolean[],
+    boolean[]
+);
+
+DROP TABLE IF EXISTS payer_usage_history;
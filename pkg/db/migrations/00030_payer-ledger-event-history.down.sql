@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_payer_ledger_events_payer_id_sequence_id;
+
+ALTER TABLE payer_ledger_events DROP COLUMN IF EXISTS reverses_event_id;
+
+ALTER TABLE payer_ledger_events DROP COLUMN IF EXISTS block_number;
+
+ALTER TABLE payer_ledger_events DROP COLUMN IF EXISTS sequence_id;
@@ -0,0 +1,15 @@
+-- Orders a payer's events for pagination. Existing rows are numbered in storage order.
+ALTER TABLE payer_ledger_events
+    ADD COLUMN sequence_id BIGSERIAL NOT NULL;
+
+-- The settlement chain block the event was emitted in. Events recorded before this column was
+-- added have no block number and are stored as 0.
+ALTER TABLE payer_ledger_events
+    ADD COLUMN block_number BIGINT NOT NULL DEFAULT 0;
+
+-- Set on reorg reversals to the event they reverse. An event can only be reversed once.
+ALTER TABLE payer_ledger_events
+    ADD COLUMN reverses_event_id BYTEA UNIQUE REFERENCES payer_ledger_events(event_id);
+
+CREATE INDEX idx_payer_ledger_events_payer_id_sequence_id
+    ON payer_ledger_events(payer_id, sequence_id);
@@ -0,0 +1,7 @@
+-- The last balance level the balance forecast worker notified about for each payer, so that
+-- notifications are only sent when a payer crosses into a different level.
+CREATE TABLE payer_balance_levels (
+    payer_id INTEGER PRIMARY KEY REFERENCES payers(id),
+    level SMALLINT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_payer_usage_history_minutes_since_epoch;
+
+-- The backfilled usage history cannot be told apart from the usage recorded since, so it is
+-- kept.
+DROP TABLE IF EXISTS payer_usage_history_backfill;
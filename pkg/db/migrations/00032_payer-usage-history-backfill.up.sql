@@ -0,0 +1,20 @@
+-- Tracks the backfill of payer_usage_history with the usage that was still unsettled before it
+-- existed. Only unsettled usage can be recovered: settled usage is cleared from unsettled_usage,
+-- and envelopes do not record what they cost. The pruner backfills it in batches, in
+-- unsettled_usage primary key order after the cursor, and deletes the row once it is done.
+CREATE TABLE payer_usage_history_backfill (
+    singleton_id SMALLINT PRIMARY KEY DEFAULT 1,
+    -- Usage from this minute on has been recorded as it was spent.
+    before_minutes_since_epoch INTEGER NOT NULL,
+    after_payer_id INTEGER NOT NULL DEFAULT -1,
+    after_originator_id INTEGER NOT NULL DEFAULT -1,
+    after_minutes_since_epoch INTEGER NOT NULL DEFAULT -1,
+    CONSTRAINT is_singleton CHECK (singleton_id = 1)
+);
+
+INSERT INTO payer_usage_history_backfill(before_minutes_since_epoch)
+SELECT FLOOR(EXTRACT(EPOCH FROM NOW()) / 60)::INTEGER + 1;
+
+-- Usage history older than the prune retention is deleted by minute.
+CREATE INDEX idx_payer_usage_history_minutes_since_epoch
+    ON payer_usage_history(minutes_since_epoch);
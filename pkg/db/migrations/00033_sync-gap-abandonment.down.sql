@@ -0,0 +1 @@
+ALTER TABLE sync_gaps DROP COLUMN IF EXISTS abandoned_at_ns;
@@ -0,0 +1,4 @@
+-- Server time when the backfill of a gap was given up on, after too many attempts without
+-- progress. NULL while the gap is open.
+ALTER TABLE sync_gaps
+    ADD COLUMN abandoned_at_ns BIGINT;
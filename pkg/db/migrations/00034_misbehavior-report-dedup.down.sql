@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS misbehavior_reports_dedup_key_idx;
+
+ALTER TABLE misbehavior_reports DROP COLUMN IF EXISTS dedup_key;
@@ -0,0 +1,6 @@
+-- Identifies the misbehavior a report is about, so that it is reported once. NULL for reports
+-- that are not deduplicated, which never conflict as NULLs are distinct.
+ALTER TABLE misbehavior_reports
+    ADD COLUMN dedup_key BYTEA;
+
+CREATE UNIQUE INDEX misbehavior_reports_dedup_key_idx ON misbehavior_reports (dedup_key);
@@ -14,7 +14,7 @@ import (
 	"github.com/xmtp/xmtpd/pkg/topic"
 )
 
-const currentMigration int64 = 23
+const currentMigration int64 = 34
 
 var (
 	originatorIDs = []int32{100, 200, 300}
@@ -18,6 +18,18 @@ func (q *Queries) AdvisoryLockWithKey(ctx context.Context, lockingKey int64) err
 	return err
 }
 
+const advisoryUnlockWithKey = `-- name: AdvisoryUnlockWithKey :one
+SELECT pg_advisory_unlock($1) as unlocked
+`
+
+// Releases a session-level advisory lock taken with TrySessionAdvisoryLockWithKey.
+func (q *Queries) AdvisoryUnlockWithKey(ctx context.Context, lockingKey int64) (bool, error) {
+	row := q.queryRow(ctx, q.advisoryUnlockWithKeyStmt, advisoryUnlockWithKey, lockingKey)
+	var unlocked bool
+	err := row.Scan(&unlocked)
+	return unlocked, err
+}
+
 const sharedAdvisoryLockWithKey = `-- name: SharedAdvisoryLockWithKey :exec
 SELECT pg_advisory_xact_lock_shared($1)
 `
@@ -37,3 +49,16 @@ func (q *Queries) TryAdvisoryLockWithKey(ctx context.Context, lockingKey int64)
 	err := row.Scan(&lock_succeeded)
 	return lock_succeeded, err
 }
+
+const trySessionAdvisoryLockWithKey = `-- name: TrySessionAdvisoryLockWithKey :one
+SELECT pg_try_advisory_lock($1) as lock_succeeded
+`
+
+// Takes a session-level advisory lock, which is held until it is released or the connection
+// is closed.
+func (q *Queries) TrySessionAdvisoryLockWithKey(ctx context.Context, lockingKey int64) (bool, error) {
+	row := q.queryRow(ctx, q.trySessionAdvisoryLockWithKeyStmt, trySessionAdvisoryLockWithKey, lockingKey)
+	var lock_succeeded bool
+	err := row.Scan(&lock_succeeded)
+	return lock_succeeded, err
+}
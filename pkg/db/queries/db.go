@@ -24,9 +24,18 @@ func New(db DBTX) *Queries {
 func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	q := Queries{db: db}
 	var err error
+	if q.abandonSyncGapStmt, err = db.PrepareContext(ctx, abandonSyncGap); err != nil {
+		return nil, fmt.Errorf("error preparing query AbandonSyncGap: %w", err)
+	}
 	if q.advisoryLockWithKeyStmt, err = db.PrepareContext(ctx, advisoryLockWithKey); err != nil {
 		return nil, fmt.Errorf("error preparing query AdvisoryLockWithKey: %w", err)
 	}
+	if q.advisoryUnlockWithKeyStmt, err = db.PrepareContext(ctx, advisoryUnlockWithKey); err != nil {
+		return nil, fmt.Errorf("error preparing query AdvisoryUnlockWithKey: %w", err)
+	}
+	if q.backfillPayerUsageHistoryStmt, err = db.PrepareContext(ctx, backfillPayerUsageHistory); err != nil {
+		return nil, fmt.Errorf("error preparing query BackfillPayerUsageHistory: %w", err)
+	}
 	if q.buildPayerReportStmt, err = db.PrepareContext(ctx, buildPayerReport); err != nil {
 		return nil, fmt.Errorf("error preparing query BuildPayerReport: %w", err)
 	}
@@ -45,12 +54,24 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.deleteAvailableNonceStmt, err = db.PrepareContext(ctx, deleteAvailableNonce); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteAvailableNonce: %w", err)
 	}
+	if q.deleteExpiredPublishedPayerEnvelopesStmt, err = db.PrepareContext(ctx, deleteExpiredPublishedPayerEnvelopes); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteExpiredPublishedPayerEnvelopes: %w", err)
+	}
 	if q.deleteMigrationDeadLetterBoxStmt, err = db.PrepareContext(ctx, deleteMigrationDeadLetterBox); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteMigrationDeadLetterBox: %w", err)
 	}
 	if q.deleteObsoleteNoncesStmt, err = db.PrepareContext(ctx, deleteObsoleteNonces); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteObsoleteNonces: %w", err)
 	}
+	if q.deletePayerUsageHistoryBackfillStmt, err = db.PrepareContext(ctx, deletePayerUsageHistoryBackfill); err != nil {
+		return nil, fmt.Errorf("error preparing query DeletePayerUsageHistoryBackfill: %w", err)
+	}
+	if q.deletePayerUsageHistoryBeforeStmt, err = db.PrepareContext(ctx, deletePayerUsageHistoryBefore); err != nil {
+		return nil, fmt.Errorf("error preparing query DeletePayerUsageHistoryBefore: %w", err)
+	}
+	if q.deleteSyncGapStmt, err = db.PrepareContext(ctx, deleteSyncGap); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteSyncGap: %w", err)
+	}
 	if q.ensureGatewayPartsStmt, err = db.PrepareContext(ctx, ensureGatewayParts); err != nil {
 		return nil, fmt.Errorf("error preparing query EnsureGatewayParts: %w", err)
 	}
@@ -78,6 +99,12 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getGatewayEnvelopeByIDStmt, err = db.PrepareContext(ctx, getGatewayEnvelopeByID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetGatewayEnvelopeByID: %w", err)
 	}
+	if q.getIdentifiersForInboxStmt, err = db.PrepareContext(ctx, getIdentifiersForInbox); err != nil {
+		return nil, fmt.Errorf("error preparing query GetIdentifiersForInbox: %w", err)
+	}
+	if q.getInboxAssociationStateStmt, err = db.PrepareContext(ctx, getInboxAssociationState); err != nil {
+		return nil, fmt.Errorf("error preparing query GetInboxAssociationState: %w", err)
+	}
 	if q.getLastEventStmt, err = db.PrepareContext(ctx, getLastEvent); err != nil {
 		return nil, fmt.Errorf("error preparing query GetLastEvent: %w", err)
 	}
@@ -99,6 +126,12 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getPayerBalanceStmt, err = db.PrepareContext(ctx, getPayerBalance); err != nil {
 		return nil, fmt.Errorf("error preparing query GetPayerBalance: %w", err)
 	}
+	if q.getPayerBalanceLevelsStmt, err = db.PrepareContext(ctx, getPayerBalanceLevels); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPayerBalanceLevels: %w", err)
+	}
+	if q.getPayerBalanceSnapshotsStmt, err = db.PrepareContext(ctx, getPayerBalanceSnapshots); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPayerBalanceSnapshots: %w", err)
+	}
 	if q.getPayerByAddressStmt, err = db.PrepareContext(ctx, getPayerByAddress); err != nil {
 		return nil, fmt.Errorf("error preparing query GetPayerByAddress: %w", err)
 	}
@@ -108,6 +141,12 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getPayerUnsettledUsageStmt, err = db.PrepareContext(ctx, getPayerUnsettledUsage); err != nil {
 		return nil, fmt.Errorf("error preparing query GetPayerUnsettledUsage: %w", err)
 	}
+	if q.getPayerUsageHistoryStmt, err = db.PrepareContext(ctx, getPayerUsageHistory); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPayerUsageHistory: %w", err)
+	}
+	if q.getPayerUsageHistoryStartStmt, err = db.PrepareContext(ctx, getPayerUsageHistoryStart); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPayerUsageHistoryStart: %w", err)
+	}
 	if q.getPrunableCeilingStmt, err = db.PrepareContext(ctx, getPrunableCeiling); err != nil {
 		return nil, fmt.Errorf("error preparing query GetPrunableCeiling: %w", err)
 	}
@@ -126,6 +165,12 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.incrementOriginatorCongestionStmt, err = db.PrepareContext(ctx, incrementOriginatorCongestion); err != nil {
 		return nil, fmt.Errorf("error preparing query IncrementOriginatorCongestion: %w", err)
 	}
+	if q.incrementPayerUsageHistoryStmt, err = db.PrepareContext(ctx, incrementPayerUsageHistory); err != nil {
+		return nil, fmt.Errorf("error preparing query IncrementPayerUsageHistory: %w", err)
+	}
+	if q.incrementSyncGapAttemptsStmt, err = db.PrepareContext(ctx, incrementSyncGapAttempts); err != nil {
+		return nil, fmt.Errorf("error preparing query IncrementSyncGapAttempts: %w", err)
+	}
 	if q.incrementUnsettledUsageStmt, err = db.PrepareContext(ctx, incrementUnsettledUsage); err != nil {
 		return nil, fmt.Errorf("error preparing query IncrementUnsettledUsage: %w", err)
 	}
@@ -141,12 +186,18 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.insertGatewayEnvelopeBatchV3Stmt, err = db.PrepareContext(ctx, insertGatewayEnvelopeBatchV3); err != nil {
 		return nil, fmt.Errorf("error preparing query InsertGatewayEnvelopeBatchV3: %w", err)
 	}
+	if q.insertGatewayEnvelopeBatchV4Stmt, err = db.PrepareContext(ctx, insertGatewayEnvelopeBatchV4); err != nil {
+		return nil, fmt.Errorf("error preparing query InsertGatewayEnvelopeBatchV4: %w", err)
+	}
 	if q.insertGatewayEnvelopeV3Stmt, err = db.PrepareContext(ctx, insertGatewayEnvelopeV3); err != nil {
 		return nil, fmt.Errorf("error preparing query InsertGatewayEnvelopeV3: %w", err)
 	}
 	if q.insertMigrationDeadLetterBoxStmt, err = db.PrepareContext(ctx, insertMigrationDeadLetterBox); err != nil {
 		return nil, fmt.Errorf("error preparing query InsertMigrationDeadLetterBox: %w", err)
 	}
+	if q.insertMisbehaviorReportStmt, err = db.PrepareContext(ctx, insertMisbehaviorReport); err != nil {
+		return nil, fmt.Errorf("error preparing query InsertMisbehaviorReport: %w", err)
+	}
 	if q.insertNodeInfoStmt, err = db.PrepareContext(ctx, insertNodeInfo); err != nil {
 		return nil, fmt.Errorf("error preparing query InsertNodeInfo: %w", err)
 	}
@@ -159,6 +210,12 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.insertPayerLedgerEventStmt, err = db.PrepareContext(ctx, insertPayerLedgerEvent); err != nil {
 		return nil, fmt.Errorf("error preparing query InsertPayerLedgerEvent: %w", err)
 	}
+	if q.insertPayerLedgerReversalStmt, err = db.PrepareContext(ctx, insertPayerLedgerReversal); err != nil {
+		return nil, fmt.Errorf("error preparing query InsertPayerLedgerReversal: %w", err)
+	}
+	if q.insertPublishedPayerEnvelopesStmt, err = db.PrepareContext(ctx, insertPublishedPayerEnvelopes); err != nil {
+		return nil, fmt.Errorf("error preparing query InsertPublishedPayerEnvelopes: %w", err)
+	}
 	if q.insertSavePointStmt, err = db.PrepareContext(ctx, insertSavePoint); err != nil {
 		return nil, fmt.Errorf("error preparing query InsertSavePoint: %w", err)
 	}
@@ -174,6 +231,15 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.insertStagedOriginatorEnvelopeBatchStmt, err = db.PrepareContext(ctx, insertStagedOriginatorEnvelopeBatch); err != nil {
 		return nil, fmt.Errorf("error preparing query InsertStagedOriginatorEnvelopeBatch: %w", err)
 	}
+	if q.insertSyncGapStmt, err = db.PrepareContext(ctx, insertSyncGap); err != nil {
+		return nil, fmt.Errorf("error preparing query InsertSyncGap: %w", err)
+	}
+	if q.listPayerLedgerEventsStmt, err = db.PrepareContext(ctx, listPayerLedgerEvents); err != nil {
+		return nil, fmt.Errorf("error preparing query ListPayerLedgerEvents: %w", err)
+	}
+	if q.lockPayerEnvelopeHashesStmt, err = db.PrepareContext(ctx, lockPayerEnvelopeHashes); err != nil {
+		return nil, fmt.Errorf("error preparing query LockPayerEnvelopeHashes: %w", err)
+	}
 	if q.makeBlobOriginatorPartStmt, err = db.PrepareContext(ctx, makeBlobOriginatorPart); err != nil {
 		return nil, fmt.Errorf("error preparing query MakeBlobOriginatorPart: %w", err)
 	}
@@ -219,6 +285,12 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.selectGatewayEnvelopesWaveScanStmt, err = db.PrepareContext(ctx, selectGatewayEnvelopesWaveScan); err != nil {
 		return nil, fmt.Errorf("error preparing query SelectGatewayEnvelopesWaveScan: %w", err)
 	}
+	if q.selectInboxesWithoutAssociationStateStmt, err = db.PrepareContext(ctx, selectInboxesWithoutAssociationState); err != nil {
+		return nil, fmt.Errorf("error preparing query SelectInboxesWithoutAssociationState: %w", err)
+	}
+	if q.selectMisbehaviorReportsStmt, err = db.PrepareContext(ctx, selectMisbehaviorReports); err != nil {
+		return nil, fmt.Errorf("error preparing query SelectMisbehaviorReports: %w", err)
+	}
 	if q.selectNewestFromTopicsStmt, err = db.PrepareContext(ctx, selectNewestFromTopics); err != nil {
 		return nil, fmt.Errorf("error preparing query SelectNewestFromTopics: %w", err)
 	}
@@ -231,9 +303,18 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.selectOriginatorNodeIDsStmt, err = db.PrepareContext(ctx, selectOriginatorNodeIDs); err != nil {
 		return nil, fmt.Errorf("error preparing query SelectOriginatorNodeIDs: %w", err)
 	}
+	if q.selectPublishedPayerEnvelopesStmt, err = db.PrepareContext(ctx, selectPublishedPayerEnvelopes); err != nil {
+		return nil, fmt.Errorf("error preparing query SelectPublishedPayerEnvelopes: %w", err)
+	}
 	if q.selectStagedOriginatorEnvelopesStmt, err = db.PrepareContext(ctx, selectStagedOriginatorEnvelopes); err != nil {
 		return nil, fmt.Errorf("error preparing query SelectStagedOriginatorEnvelopes: %w", err)
 	}
+	if q.selectSyncGapStatsStmt, err = db.PrepareContext(ctx, selectSyncGapStats); err != nil {
+		return nil, fmt.Errorf("error preparing query SelectSyncGapStats: %w", err)
+	}
+	if q.selectSyncGapsStmt, err = db.PrepareContext(ctx, selectSyncGaps); err != nil {
+		return nil, fmt.Errorf("error preparing query SelectSyncGaps: %w", err)
+	}
 	if q.selectVectorClockStmt, err = db.PrepareContext(ctx, selectVectorClock); err != nil {
 		return nil, fmt.Errorf("error preparing query SelectVectorClock: %w", err)
 	}
@@ -243,6 +324,9 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.setLocalWorkMemStmt, err = db.PrepareContext(ctx, setLocalWorkMem); err != nil {
 		return nil, fmt.Errorf("error preparing query SetLocalWorkMem: %w", err)
 	}
+	if q.setPayerBalanceLevelStmt, err = db.PrepareContext(ctx, setPayerBalanceLevel); err != nil {
+		return nil, fmt.Errorf("error preparing query SetPayerBalanceLevel: %w", err)
+	}
 	if q.setReportAttestationStatusStmt, err = db.PrepareContext(ctx, setReportAttestationStatus); err != nil {
 		return nil, fmt.Errorf("error preparing query SetReportAttestationStatus: %w", err)
 	}
@@ -261,19 +345,43 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.tryAdvisoryLockWithKeyStmt, err = db.PrepareContext(ctx, tryAdvisoryLockWithKey); err != nil {
 		return nil, fmt.Errorf("error preparing query TryAdvisoryLockWithKey: %w", err)
 	}
+	if q.trySessionAdvisoryLockWithKeyStmt, err = db.PrepareContext(ctx, trySessionAdvisoryLockWithKey); err != nil {
+		return nil, fmt.Errorf("error preparing query TrySessionAdvisoryLockWithKey: %w", err)
+	}
 	if q.updateMigrationProgressStmt, err = db.PrepareContext(ctx, updateMigrationProgress); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateMigrationProgress: %w", err)
 	}
+	if q.updateSyncGapStartStmt, err = db.PrepareContext(ctx, updateSyncGapStart); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateSyncGapStart: %w", err)
+	}
+	if q.upsertInboxAssociationStateStmt, err = db.PrepareContext(ctx, upsertInboxAssociationState); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertInboxAssociationState: %w", err)
+	}
 	return &q, nil
 }
 
 func (q *Queries) Close() error {
 	var err error
+	if q.abandonSyncGapStmt != nil {
+		if cerr := q.abandonSyncGapStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing abandonSyncGapStmt: %w", cerr)
+		}
+	}
 	if q.advisoryLockWithKeyStmt != nil {
 		if cerr := q.advisoryLockWithKeyStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing advisoryLockWithKeyStmt: %w", cerr)
 		}
 	}
+	if q.advisoryUnlockWithKeyStmt != nil {
+		if cerr := q.advisoryUnlockWithKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing advisoryUnlockWithKeyStmt: %w", cerr)
+		}
+	}
+	if q.backfillPayerUsageHistoryStmt != nil {
+		if cerr := q.backfillPayerUsageHistoryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing backfillPayerUsageHistoryStmt: %w", cerr)
+		}
+	}
 	if q.buildPayerReportStmt != nil {
 		if cerr := q.buildPayerReportStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing buildPayerReportStmt: %w", cerr)
@@ -304,6 +412,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteAvailableNonceStmt: %w", cerr)
 		}
 	}
+	if q.deleteExpiredPublishedPayerEnvelopesStmt != nil {
+		if cerr := q.deleteExpiredPublishedPayerEnvelopesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteExpiredPublishedPayerEnvelopesStmt: %w", cerr)
+		}
+	}
 	if q.deleteMigrationDeadLetterBoxStmt != nil {
 		if cerr := q.deleteMigrationDeadLetterBoxStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteMigrationDeadLetterBoxStmt: %w", cerr)
@@ -314,6 +427,21 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteObsoleteNoncesStmt: %w", cerr)
 		}
 	}
+	if q.deletePayerUsageHistoryBackfillStmt != nil {
+		if cerr := q.deletePayerUsageHistoryBackfillStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deletePayerUsageHistoryBackfillStmt: %w", cerr)
+		}
+	}
+	if q.deletePayerUsageHistoryBeforeStmt != nil {
+		if cerr := q.deletePayerUsageHistoryBeforeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deletePayerUsageHistoryBeforeStmt: %w", cerr)
+		}
+	}
+	if q.deleteSyncGapStmt != nil {
+		if cerr := q.deleteSyncGapStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteSyncGapStmt: %w", cerr)
+		}
+	}
 	if q.ensureGatewayPartsStmt != nil {
 		if cerr := q.ensureGatewayPartsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing ensureGatewayPartsStmt: %w", cerr)
@@ -359,6 +487,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getGatewayEnvelopeByIDStmt: %w", cerr)
 		}
 	}
+	if q.getIdentifiersForInboxStmt != nil {
+		if cerr := q.getIdentifiersForInboxStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getIdentifiersForInboxStmt: %w", cerr)
+		}
+	}
+	if q.getInboxAssociationStateStmt != nil {
+		if cerr := q.getInboxAssociationStateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getInboxAssociationStateStmt: %w", cerr)
+		}
+	}
 	if q.getLastEventStmt != nil {
 		if cerr := q.getLastEventStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getLastEventStmt: %w", cerr)
@@ -394,6 +532,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getPayerBalanceStmt: %w", cerr)
 		}
 	}
+	if q.getPayerBalanceLevelsStmt != nil {
+		if cerr := q.getPayerBalanceLevelsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPayerBalanceLevelsStmt: %w", cerr)
+		}
+	}
+	if q.getPayerBalanceSnapshotsStmt != nil {
+		if cerr := q.getPayerBalanceSnapshotsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPayerBalanceSnapshotsStmt: %w", cerr)
+		}
+	}
 	if q.getPayerByAddressStmt != nil {
 		if cerr := q.getPayerByAddressStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getPayerByAddressStmt: %w", cerr)
@@ -409,6 +557,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getPayerUnsettledUsageStmt: %w", cerr)
 		}
 	}
+	if q.getPayerUsageHistoryStmt != nil {
+		if cerr := q.getPayerUsageHistoryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPayerUsageHistoryStmt: %w", cerr)
+		}
+	}
+	if q.getPayerUsageHistoryStartStmt != nil {
+		if cerr := q.getPayerUsageHistoryStartStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPayerUsageHistoryStartStmt: %w", cerr)
+		}
+	}
 	if q.getPrunableCeilingStmt != nil {
 		if cerr := q.getPrunableCeilingStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getPrunableCeilingStmt: %w", cerr)
@@ -439,6 +597,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing incrementOriginatorCongestionStmt: %w", cerr)
 		}
 	}
+	if q.incrementPayerUsageHistoryStmt != nil {
+		if cerr := q.incrementPayerUsageHistoryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing incrementPayerUsageHistoryStmt: %w", cerr)
+		}
+	}
+	if q.incrementSyncGapAttemptsStmt != nil {
+		if cerr := q.incrementSyncGapAttemptsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing incrementSyncGapAttemptsStmt: %w", cerr)
+		}
+	}
 	if q.incrementUnsettledUsageStmt != nil {
 		if cerr := q.incrementUnsettledUsageStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing incrementUnsettledUsageStmt: %w", cerr)
@@ -464,6 +632,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing insertGatewayEnvelopeBatchV3Stmt: %w", cerr)
 		}
 	}
+	if q.insertGatewayEnvelopeBatchV4Stmt != nil {
+		if cerr := q.insertGatewayEnvelopeBatchV4Stmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing insertGatewayEnvelopeBatchV4Stmt: %w", cerr)
+		}
+	}
 	if q.insertGatewayEnvelopeV3Stmt != nil {
 		if cerr := q.insertGatewayEnvelopeV3Stmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing insertGatewayEnvelopeV3Stmt: %w", cerr)
@@ -474,6 +647,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing insertMigrationDeadLetterBoxStmt: %w", cerr)
 		}
 	}
+	if q.insertMisbehaviorReportStmt != nil {
+		if cerr := q.insertMisbehaviorReportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing insertMisbehaviorReportStmt: %w", cerr)
+		}
+	}
 	if q.insertNodeInfoStmt != nil {
 		if cerr := q.insertNodeInfoStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing insertNodeInfoStmt: %w", cerr)
@@ -494,6 +672,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing insertPayerLedgerEventStmt: %w", cerr)
 		}
 	}
+	if q.insertPayerLedgerReversalStmt != nil {
+		if cerr := q.insertPayerLedgerReversalStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing insertPayerLedgerReversalStmt: %w", cerr)
+		}
+	}
+	if q.insertPublishedPayerEnvelopesStmt != nil {
+		if cerr := q.insertPublishedPayerEnvelopesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing insertPublishedPayerEnvelopesStmt: %w", cerr)
+		}
+	}
 	if q.insertSavePointStmt != nil {
 		if cerr := q.insertSavePointStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing insertSavePointStmt: %w", cerr)
@@ -519,6 +707,21 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing insertStagedOriginatorEnvelopeBatchStmt: %w", cerr)
 		}
 	}
+	if q.insertSyncGapStmt != nil {
+		if cerr := q.insertSyncGapStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing insertSyncGapStmt: %w", cerr)
+		}
+	}
+	if q.listPayerLedgerEventsStmt != nil {
+		if cerr := q.listPayerLedgerEventsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listPayerLedgerEventsStmt: %w", cerr)
+		}
+	}
+	if q.lockPayerEnvelopeHashesStmt != nil {
+		if cerr := q.lockPayerEnvelopeHashesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing lockPayerEnvelopeHashesStmt: %w", cerr)
+		}
+	}
 	if q.makeBlobOriginatorPartStmt != nil {
 		if cerr := q.makeBlobOriginatorPartStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing makeBlobOriginatorPartStmt: %w", cerr)
@@ -594,6 +797,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing selectGatewayEnvelopesWaveScanStmt: %w", cerr)
 		}
 	}
+	if q.selectInboxesWithoutAssociationStateStmt != nil {
+		if cerr := q.selectInboxesWithoutAssociationStateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing selectInboxesWithoutAssociationStateStmt: %w", cerr)
+		}
+	}
+	if q.selectMisbehaviorReportsStmt != nil {
+		if cerr := q.selectMisbehaviorReportsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing selectMisbehaviorReportsStmt: %w", cerr)
+		}
+	}
 	if q.selectNewestFromTopicsStmt != nil {
 		if cerr := q.selectNewestFromTopicsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing selectNewestFromTopicsStmt: %w", cerr)
@@ -614,11 +827,26 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing selectOriginatorNodeIDsStmt: %w", cerr)
 		}
 	}
+	if q.selectPublishedPayerEnvelopesStmt != nil {
+		if cerr := q.selectPublishedPayerEnvelopesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing selectPublishedPayerEnvelopesStmt: %w", cerr)
+		}
+	}
 	if q.selectStagedOriginatorEnvelopesStmt != nil {
 		if cerr := q.selectStagedOriginatorEnvelopesStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing selectStagedOriginatorEnvelopesStmt: %w", cerr)
 		}
 	}
+	if q.selectSyncGapStatsStmt != nil {
+		if cerr := q.selectSyncGapStatsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing selectSyncGapStatsStmt: %w", cerr)
+		}
+	}
+	if q.selectSyncGapsStmt != nil {
+		if cerr := q.selectSyncGapsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing selectSyncGapsStmt: %w", cerr)
+		}
+	}
 	if q.selectVectorClockStmt != nil {
 		if cerr := q.selectVectorClockStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing selectVectorClockStmt: %w", cerr)
@@ -634,6 +862,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing setLocalWorkMemStmt: %w", cerr)
 		}
 	}
+	if q.setPayerBalanceLevelStmt != nil {
+		if cerr := q.setPayerBalanceLevelStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setPayerBalanceLevelStmt: %w", cerr)
+		}
+	}
 	if q.setReportAttestationStatusStmt != nil {
 		if cerr := q.setReportAttestationStatusStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing setReportAttestationStatusStmt: %w", cerr)
@@ -664,11 +897,26 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing tryAdvisoryLockWithKeyStmt: %w", cerr)
 		}
 	}
+	if q.trySessionAdvisoryLockWithKeyStmt != nil {
+		if cerr := q.trySessionAdvisoryLockWithKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing trySessionAdvisoryLockWithKeyStmt: %w", cerr)
+		}
+	}
 	if q.updateMigrationProgressStmt != nil {
 		if cerr := q.updateMigrationProgressStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateMigrationProgressStmt: %w", cerr)
 		}
 	}
+	if q.updateSyncGapStartStmt != nil {
+		if cerr := q.updateSyncGapStartStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateSyncGapStartStmt: %w", cerr)
+		}
+	}
+	if q.upsertInboxAssociationStateStmt != nil {
+		if cerr := q.upsertInboxAssociationStateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertInboxAssociationStateStmt: %w", cerr)
+		}
+	}
 	return err
 }
 
@@ -708,15 +956,22 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 type Queries struct {
 	db                                           DBTX
 	tx                                           *sql.Tx
+	abandonSyncGapStmt                           *sql.Stmt
 	advisoryLockWithKeyStmt                      *sql.Stmt
+	advisoryUnlockWithKeyStmt                    *sql.Stmt
+	backfillPayerUsageHistoryStmt                *sql.Stmt
 	buildPayerReportStmt                         *sql.Stmt
 	bulkDeleteStagedOriginatorEnvelopesStmt      *sql.Stmt
 	bulkFindOrCreatePayersStmt                   *sql.Stmt
 	clearUnsettledUsageStmt                      *sql.Stmt
 	countExpiredEnvelopesStmt                    *sql.Stmt
 	deleteAvailableNonceStmt                     *sql.Stmt
+	deleteExpiredPublishedPayerEnvelopesStmt     *sql.Stmt
 	deleteMigrationDeadLetterBoxStmt             *sql.Stmt
 	deleteObsoleteNoncesStmt                     *sql.Stmt
+	deletePayerUsageHistoryBackfillStmt          *sql.Stmt
+	deletePayerUsageHistoryBeforeStmt            *sql.Stmt
+	deleteSyncGapStmt                            *sql.Stmt
 	ensureGatewayPartsStmt                       *sql.Stmt
 	ensureGatewayPartsV3Stmt                     *sql.Stmt
 	fetchPayerReportStmt                         *sql.Stmt
@@ -726,6 +981,8 @@ type Queries struct {
 	findOrCreatePayerStmt                        *sql.Stmt
 	getAddressLogsStmt                           *sql.Stmt
 	getGatewayEnvelopeByIDStmt                   *sql.Stmt
+	getIdentifiersForInboxStmt                   *sql.Stmt
+	getInboxAssociationStateStmt                 *sql.Stmt
 	getLastEventStmt                             *sql.Stmt
 	getLastSequenceIDForOriginatorMinuteStmt     *sql.Stmt
 	getLatestBlockStmt                           *sql.Stmt
@@ -733,31 +990,44 @@ type Queries struct {
 	getMigrationProgressStmt                     *sql.Stmt
 	getNextAvailableNonceStmt                    *sql.Stmt
 	getPayerBalanceStmt                          *sql.Stmt
+	getPayerBalanceLevelsStmt                    *sql.Stmt
+	getPayerBalanceSnapshotsStmt                 *sql.Stmt
 	getPayerByAddressStmt                        *sql.Stmt
 	getPayerInfoReportStmt                       *sql.Stmt
 	getPayerUnsettledUsageStmt                   *sql.Stmt
+	getPayerUsageHistoryStmt                     *sql.Stmt
+	getPayerUsageHistoryStartStmt                *sql.Stmt
 	getPrunableCeilingStmt                       *sql.Stmt
 	getPrunableMetaPartitionsStmt                *sql.Stmt
 	getRecentOriginatorCongestionStmt            *sql.Stmt
 	getRetryableMigrationDeadLetterBoxesStmt     *sql.Stmt
 	getSecondNewestMinuteStmt                    *sql.Stmt
 	incrementOriginatorCongestionStmt            *sql.Stmt
+	incrementPayerUsageHistoryStmt               *sql.Stmt
+	incrementSyncGapAttemptsStmt                 *sql.Stmt
 	incrementUnsettledUsageStmt                  *sql.Stmt
 	insertAddressLogStmt                         *sql.Stmt
 	insertAddressLogsBatchStmt                   *sql.Stmt
 	insertGatewayEnvelopeBatchV2Stmt             *sql.Stmt
 	insertGatewayEnvelopeBatchV3Stmt             *sql.Stmt
+	insertGatewayEnvelopeBatchV4Stmt             *sql.Stmt
 	insertGatewayEnvelopeV3Stmt                  *sql.Stmt
 	insertMigrationDeadLetterBoxStmt             *sql.Stmt
+	insertMisbehaviorReportStmt                  *sql.Stmt
 	insertNodeInfoStmt                           *sql.Stmt
 	insertOrIgnorePayerReportStmt                *sql.Stmt
 	insertOrIgnorePayerReportAttestationStmt     *sql.Stmt
 	insertPayerLedgerEventStmt                   *sql.Stmt
+	insertPayerLedgerReversalStmt                *sql.Stmt
+	insertPublishedPayerEnvelopesStmt            *sql.Stmt
 	insertSavePointStmt                          *sql.Stmt
 	insertSavePointReleaseStmt                   *sql.Stmt
 	insertSavePointRollbackStmt                  *sql.Stmt
 	insertStagedOriginatorEnvelopeStmt           *sql.Stmt
 	insertStagedOriginatorEnvelopeBatchStmt      *sql.Stmt
+	insertSyncGapStmt                            *sql.Stmt
+	listPayerLedgerEventsStmt                    *sql.Stmt
+	lockPayerEnvelopeHashesStmt                  *sql.Stmt
 	makeBlobOriginatorPartStmt                   *sql.Stmt
 	makeBlobOriginatorPartV3Stmt                 *sql.Stmt
 	makeBlobSeqBandStmt                          *sql.Stmt
@@ -773,36 +1043,52 @@ type Queries struct {
 	selectGatewayEnvelopesByTopicsStmt           *sql.Stmt
 	selectGatewayEnvelopesUnfilteredStmt         *sql.Stmt
 	selectGatewayEnvelopesWaveScanStmt           *sql.Stmt
+	selectInboxesWithoutAssociationStateStmt     *sql.Stmt
+	selectMisbehaviorReportsStmt                 *sql.Stmt
 	selectNewestFromTopicsStmt                   *sql.Stmt
 	selectNodeInfoStmt                           *sql.Stmt
 	selectOriginatorCeilingsStmt                 *sql.Stmt
 	selectOriginatorNodeIDsStmt                  *sql.Stmt
+	selectPublishedPayerEnvelopesStmt            *sql.Stmt
 	selectStagedOriginatorEnvelopesStmt          *sql.Stmt
+	selectSyncGapStatsStmt                       *sql.Stmt
+	selectSyncGapsStmt                           *sql.Stmt
 	selectVectorClockStmt                        *sql.Stmt
 	setLatestBlockStmt                           *sql.Stmt
 	setLocalWorkMemStmt                          *sql.Stmt
+	setPayerBalanceLevelStmt                     *sql.Stmt
 	setReportAttestationStatusStmt               *sql.Stmt
 	setReportSubmissionStatusStmt                *sql.Stmt
 	setReportSubmittedStmt                       *sql.Stmt
 	sharedAdvisoryLockWithKeyStmt                *sql.Stmt
 	sumOriginatorCongestionStmt                  *sql.Stmt
 	tryAdvisoryLockWithKeyStmt                   *sql.Stmt
+	trySessionAdvisoryLockWithKeyStmt            *sql.Stmt
 	updateMigrationProgressStmt                  *sql.Stmt
+	updateSyncGapStartStmt                       *sql.Stmt
+	upsertInboxAssociationStateStmt              *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
 		db:                                           tx,
 		tx:                                           tx,
+		abandonSyncGapStmt:                           q.abandonSyncGapStmt,
 		advisoryLockWithKeyStmt:                      q.advisoryLockWithKeyStmt,
+		advisoryUnlockWithKeyStmt:                    q.advisoryUnlockWithKeyStmt,
+		backfillPayerUsageHistoryStmt:                q.backfillPayerUsageHistoryStmt,
 		buildPayerReportStmt:                         q.buildPayerReportStmt,
 		bulkDeleteStagedOriginatorEnvelopesStmt:      q.bulkDeleteStagedOriginatorEnvelopesStmt,
 		bulkFindOrCreatePayersStmt:                   q.bulkFindOrCreatePayersStmt,
 		clearUnsettledUsageStmt:                      q.clearUnsettledUsageStmt,
 		countExpiredEnvelopesStmt:                    q.countExpiredEnvelopesStmt,
 		deleteAvailableNonceStmt:                     q.deleteAvailableNonceStmt,
+		deleteExpiredPublishedPayerEnvelopesStmt:     q.deleteExpiredPublishedPayerEnvelopesStmt,
 		deleteMigrationDeadLetterBoxStmt:             q.deleteMigrationDeadLetterBoxStmt,
 		deleteObsoleteNoncesStmt:                     q.deleteObsoleteNoncesStmt,
+		deletePayerUsageHistoryBackfillStmt:          q.deletePayerUsageHistoryBackfillStmt,
+		deletePayerUsageHistoryBeforeStmt:            q.deletePayerUsageHistoryBeforeStmt,
+		deleteSyncGapStmt:                            q.deleteSyncGapStmt,
 		ensureGatewayPartsStmt:                       q.ensureGatewayPartsStmt,
 		ensureGatewayPartsV3Stmt:                     q.ensureGatewayPartsV3Stmt,
 		fetchPayerReportStmt:                         q.fetchPayerReportStmt,
@@ -812,6 +1098,8 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		findOrCreatePayerStmt:                        q.findOrCreatePayerStmt,
 		getAddressLogsStmt:                           q.getAddressLogsStmt,
 		getGatewayEnvelopeByIDStmt:                   q.getGatewayEnvelopeByIDStmt,
+		getIdentifiersForInboxStmt:                   q.getIdentifiersForInboxStmt,
+		getInboxAssociationStateStmt:                 q.getInboxAssociationStateStmt,
 		getLastEventStmt:                             q.getLastEventStmt,
 		getLastSequenceIDForOriginatorMinuteStmt:     q.getLastSequenceIDForOriginatorMinuteStmt,
 		getLatestBlockStmt:                           q.getLatestBlockStmt,
@@ -819,31 +1107,44 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		getMigrationProgressStmt:                     q.getMigrationProgressStmt,
 		getNextAvailableNonceStmt:                    q.getNextAvailableNonceStmt,
 		getPayerBalanceStmt:                          q.getPayerBalanceStmt,
+		getPayerBalanceLevelsStmt:                    q.getPayerBalanceLevelsStmt,
+		getPayerBalanceSnapshotsStmt:                 q.getPayerBalanceSnapshotsStmt,
 		getPayerByAddressStmt:                        q.getPayerByAddressStmt,
 		getPayerInfoReportStmt:                       q.getPayerInfoReportStmt,
 		getPayerUnsettledUsageStmt:                   q.getPayerUnsettledUsageStmt,
+		getPayerUsageHistoryStmt:                     q.getPayerUsageHistoryStmt,
+		getPayerUsageHistoryStartStmt:                q.getPayerUsageHistoryStartStmt,
 		getPrunableCeilingStmt:                       q.getPrunableCeilingStmt,
 		getPrunableMetaPartitionsStmt:                q.getPrunableMetaPartitionsStmt,
 		getRecentOriginatorCongestionStmt:            q.getRecentOriginatorCongestionStmt,
 		getRetryableMigrationDeadLetterBoxesStmt:     q.getRetryableMigrationDeadLetterBoxesStmt,
 		getSecondNewestMinuteStmt:                    q.getSecondNewestMinuteStmt,
 		incrementOriginatorCongestionStmt:            q.incrementOriginatorCongestionStmt,
+		incrementPayerUsageHistoryStmt:               q.incrementPayerUsageHistoryStmt,
+		incrementSyncGapAttemptsStmt:                 q.incrementSyncGapAttemptsStmt,
 		incrementUnsettledUsageStmt:                  q.incrementUnsettledUsageStmt,
 		insertAddressLogStmt:                         q.insertAddressLogStmt,
 		insertAddressLogsBatchStmt:                   q.insertAddressLogsBatchStmt,
 		insertGatewayEnvelopeBatchV2Stmt:             q.insertGatewayEnvelopeBatchV2Stmt,
 		insertGatewayEnvelopeBatchV3Stmt:             q.insertGatewayEnvelopeBatchV3Stmt,
+		insertGatewayEnvelopeBatchV4Stmt:             q.insertGatewayEnvelopeBatchV4Stmt,
 		insertGatewayEnvelopeV3Stmt:                  q.insertGatewayEnvelopeV3Stmt,
 		insertMigrationDeadLetterBoxStmt:             q.insertMigrationDeadLetterBoxStmt,
+		insertMisbehaviorReportStmt:                  q.insertMisbehaviorReportStmt,
 		insertNodeInfoStmt:                           q.insertNodeInfoStmt,
 		insertOrIgnorePayerReportStmt:                q.insertOrIgnorePayerReportStmt,
 		insertOrIgnorePayerReportAttestationStmt:     q.insertOrIgnorePayerReportAttestationStmt,
 		insertPayerLedgerEventStmt:                   q.insertPayerLedgerEventStmt,
+		insertPayerLedgerReversalStmt:                q.insertPayerLedgerReversalStmt,
+		insertPublishedPayerEnvelopesStmt:            q.insertPublishedPayerEnvelopesStmt,
 		insertSavePointStmt:                          q.insertSavePointStmt,
 		insertSavePointReleaseStmt:                   q.insertSavePointReleaseStmt,
 		insertSavePointRollbackStmt:                  q.insertSavePointRollbackStmt,
 		insertStagedOriginatorEnvelopeStmt:           q.insertStagedOriginatorEnvelopeStmt,
 		insertStagedOriginatorEnvelopeBatchStmt:      q.insertStagedOriginatorEnvelopeBatchStmt,
+		insertSyncGapStmt:                            q.insertSyncGapStmt,
+		listPayerLedgerEventsStmt:                    q.listPayerLedgerEventsStmt,
+		lockPayerEnvelopeHashesStmt:                  q.lockPayerEnvelopeHashesStmt,
 		makeBlobOriginatorPartStmt:                   q.makeBlobOriginatorPartStmt,
 		makeBlobOriginatorPartV3Stmt:                 q.makeBlobOriginatorPartV3Stmt,
 		makeBlobSeqBandStmt:                          q.makeBlobSeqBandStmt,
@@ -859,20 +1160,29 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		selectGatewayEnvelopesByTopicsStmt:           q.selectGatewayEnvelopesByTopicsStmt,
 		selectGatewayEnvelopesUnfilteredStmt:         q.selectGatewayEnvelopesUnfilteredStmt,
 		selectGatewayEnvelopesWaveScanStmt:           q.selectGatewayEnvelopesWaveScanStmt,
+		selectInboxesWithoutAssociationStateStmt:     q.selectInboxesWithoutAssociationStateStmt,
+		selectMisbehaviorReportsStmt:                 q.selectMisbehaviorReportsStmt,
 		selectNewestFromTopicsStmt:                   q.selectNewestFromTopicsStmt,
 		selectNodeInfoStmt:                           q.selectNodeInfoStmt,
 		selectOriginatorCeilingsStmt:                 q.selectOriginatorCeilingsStmt,
 		selectOriginatorNodeIDsStmt:                  q.selectOriginatorNodeIDsStmt,
+		selectPublishedPayerEnvelopesStmt:            q.selectPublishedPayerEnvelopesStmt,
 		selectStagedOriginatorEnvelopesStmt:          q.selectStagedOriginatorEnvelopesStmt,
+		selectSyncGapStatsStmt:                       q.selectSyncGapStatsStmt,
+		selectSyncGapsStmt:                           q.selectSyncGapsStmt,
 		selectVectorClockStmt:                        q.selectVectorClockStmt,
 		setLatestBlockStmt:                           q.setLatestBlockStmt,
 		setLocalWorkMemStmt:                          q.setLocalWorkMemStmt,
+		setPayerBalanceLevelStmt:                     q.setPayerBalanceLevelStmt,
 		setReportAttestationStatusStmt:               q.setReportAttestationStatusStmt,
 		setReportSubmissionStatusStmt:                q.setReportSubmissionStatusStmt,
 		setReportSubmittedStmt:                       q.setReportSubmittedStmt,
 		sharedAdvisoryLockWithKeyStmt:                q.sharedAdvisoryLockWithKeyStmt,
 		sumOriginatorCongestionStmt:                  q.sumOriginatorCongestionStmt,
 		tryAdvisoryLockWithKeyStmt:                   q.tryAdvisoryLockWithKeyStmt,
+		trySessionAdvisoryLockWithKeyStmt:            q.trySessionAdvisoryLockWithKeyStmt,
 		updateMigrationProgressStmt:                  q.updateMigrationProgressStmt,
+		updateSyncGapStartStmt:                       q.updateSyncGapStartStmt,
+		upsertInboxAssociationStateStmt:              q.upsertInboxAssociationStateStmt,
 	}
 }
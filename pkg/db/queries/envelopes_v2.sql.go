@@ -122,8 +122,8 @@ type InsertGatewayEnvelopeBatchV3Row struct {
 }
 
 // Batch envelope insert calling the renamed insert_gateway_envelope_batch_v3
-// stored function (which targets gateway_envelopes_blob). This is the
-// production version.
+// stored function (which targets gateway_envelopes_blob). Superseded by
+// InsertGatewayEnvelopeBatchV4, which also records payer usage history.
 func (q *Queries) InsertGatewayEnvelopeBatchV3(ctx context.Context, arg InsertGatewayEnvelopeBatchV3Params) (InsertGatewayEnvelopeBatchV3Row, error) {
 	row := q.queryRow(ctx, q.insertGatewayEnvelopeBatchV3Stmt, insertGatewayEnvelopeBatchV3,
 		pq.Array(arg.OriginatorNodeIds),
@@ -147,6 +147,78 @@ func (q *Queries) InsertGatewayEnvelopeBatchV3(ctx context.Context, arg InsertGa
 	return i, err
 }
 
+const insertGatewayEnvelopeBatchV4 = `-- name: InsertGatewayEnvelopeBatchV4 :one
+SELECT
+    inserted_meta_rows::bigint,
+    inserted_blob_rows::bigint,
+    affected_usage_rows::bigint,
+    affected_history_rows::bigint,
+    affected_congestion_rows::bigint
+FROM insert_gateway_envelope_batch_v4(
+    $1::int[],
+    $2::bigint[],
+    $3::bytea[],
+    $4::int[],
+    $5::timestamp[],
+    $6::bigint[],
+    $7::bytea[],
+    $8::bigint[],
+    $9::bigint[],
+    $10::boolean[],
+    $11::boolean[]
+)
+`
+
+type InsertGatewayEnvelopeBatchV4Params struct {
+	OriginatorNodeIds     []int32
+	OriginatorSequenceIds []int64
+	Topics                [][]byte
+	PayerIds              []int32
+	GatewayTimes          []time.Time
+	Expiries              []int64
+	OriginatorEnvelopes   [][]byte
+	SpendPicodollars      []int64
+	CongestionPicodollars []int64
+	CountUsage            []bool
+	CountCongestion       []bool
+}
+
+type InsertGatewayEnvelopeBatchV4Row struct {
+	InsertedMetaRows       int64
+	InsertedBlobRows       int64
+	AffectedUsageRows      int64
+	AffectedHistoryRows    int64
+	AffectedCongestionRows int64
+}
+
+// Batch envelope insert calling insert_gateway_envelope_batch_v4, which also
+// records the spend of each envelope in payer_usage_history. This is the
+// production version.
+func (q *Queries) InsertGatewayEnvelopeBatchV4(ctx context.Context, arg InsertGatewayEnvelopeBatchV4Params) (InsertGatewayEnvelopeBatchV4Row, error) {
+	row := q.queryRow(ctx, q.insertGatewayEnvelopeBatchV4Stmt, insertGatewayEnvelopeBatchV4,
+		pq.Array(arg.OriginatorNodeIds),
+		pq.Array(arg.OriginatorSequenceIds),
+		pq.Array(arg.Topics),
+		pq.Array(arg.PayerIds),
+		pq.Array(arg.GatewayTimes),
+		pq.Array(arg.Expiries),
+		pq.Array(arg.OriginatorEnvelopes),
+		pq.Array(arg.SpendPicodollars),
+		pq.Array(arg.CongestionPicodollars),
+		pq.Array(arg.CountUsage),
+		pq.Array(arg.CountCongestion),
+	)
+	var i InsertGatewayEnvelopeBatchV4Row
+	err := row.Scan(
+		&i.InsertedMetaRows,
+		&i.InsertedBlobRows,
+		&i.AffectedUsageRows,
+		&i.AffectedHistoryRows,
+		&i.AffectedCongestionRows,
+	)
+	return i, err
+}
+
 const insertGatewayEnvelopeV3 = `-- name: InsertGatewayEnvelopeV3 :one
 WITH m AS (
     INSERT INTO gateway_envelopes_meta (
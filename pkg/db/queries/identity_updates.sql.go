@@ -15,32 +15,44 @@ import (
 const getAddressLogs = `-- name: GetAddressLogs :many
 SELECT
 	a.address,
+	a.identifier_kind,
 	encode(a.inbox_id, 'hex') AS inbox_id,
 	a.association_sequence_id
 FROM
 	address_log a
 	INNER JOIN (
 		SELECT
-			address,
-			MAX(association_sequence_id) AS max_association_sequence_id
+			l.address,
+			l.identifier_kind,
+			MAX(l.association_sequence_id) AS max_association_sequence_id
 		FROM
-			address_log
+			address_log l
+			INNER JOIN unnest($1::TEXT[], $2::SMALLINT[]) AS i(address, identifier_kind)
+				ON l.address = i.address
+				AND l.identifier_kind = i.identifier_kind
 		WHERE
-			address = ANY ($1::TEXT[])
-			AND revocation_sequence_id IS NULL
+			l.revocation_sequence_id IS NULL
 		GROUP BY
-			address) b ON a.address = b.address
+			l.address,
+			l.identifier_kind) b ON a.address = b.address
+	AND a.identifier_kind = b.identifier_kind
 	AND a.association_sequence_id = b.max_association_sequence_id
 `
 
+type GetAddressLogsParams struct {
+	Addresses       []string
+	IdentifierKinds []int16
+}
+
 type GetAddressLogsRow struct {
 	Address               string
+	IdentifierKind        int16
 	InboxID               string
 	AssociationSequenceID sql.NullInt64
 }
 
-func (q *Queries) GetAddressLogs(ctx context.Context, addresses []string) ([]GetAddressLogsRow, error) {
-	rows, err := q.query(ctx, q.getAddressLogsStmt, getAddressLogs, pq.Array(addresses))
+func (q *Queries) GetAddressLogs(ctx context.Context, arg GetAddressLogsParams) ([]GetAddressLogsRow, error) {
+	rows, err := q.query(ctx, q.getAddressLogsStmt, getAddressLogs, pq.Array(arg.Addresses), pq.Array(arg.IdentifierKinds))
 	if err != nil {
 		return nil, err
 	}
@@ -48,7 +60,12 @@ func (q *Queries) GetAddressLogs(ctx context.Context, addresses []string) ([]Get
 	var items []GetAddressLogsRow
 	for rows.Next() {
 		var i GetAddressLogsRow
-		if err := rows.Scan(&i.Address, &i.InboxID, &i.AssociationSequenceID); err != nil {
+		if err := rows.Scan(
+			&i.Address,
+			&i.IdentifierKind,
+			&i.InboxID,
+			&i.AssociationSequenceID,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -62,25 +79,110 @@ func (q *Queries) GetAddressLogs(ctx context.Context, addresses []string) ([]Get
 	return items, nil
 }
 
+const getIdentifiersForInbox = `-- name: GetIdentifiersForInbox :many
+SELECT
+	address,
+	identifier_kind,
+	association_sequence_id,
+	revocation_sequence_id
+FROM
+	address_log
+WHERE
+	inbox_id = decode($1, 'hex')
+	AND ($2::BOOLEAN
+		OR revocation_sequence_id IS NULL)
+ORDER BY
+	association_sequence_id,
+	identifier_kind,
+	address
+`
+
+type GetIdentifiersForInboxParams struct {
+	InboxID        string
+	IncludeRevoked bool
+}
+
+type GetIdentifiersForInboxRow struct {
+	Address               string
+	IdentifierKind        int16
+	AssociationSequenceID sql.NullInt64
+	RevocationSequenceID  sql.NullInt64
+}
+
+func (q *Queries) GetIdentifiersForInbox(ctx context.Context, arg GetIdentifiersForInboxParams) ([]GetIdentifiersForInboxRow, error) {
+	rows, err := q.query(ctx, q.getIdentifiersForInboxStmt, getIdentifiersForInbox, arg.InboxID, arg.IncludeRevoked)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetIdentifiersForInboxRow
+	for rows.Next() {
+		var i GetIdentifiersForInboxRow
+		if err := rows.Scan(
+			&i.Address,
+			&i.IdentifierKind,
+			&i.AssociationSequenceID,
+			&i.RevocationSequenceID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getInboxAssociationState = `-- name: GetInboxAssociationState :one
+SELECT
+	inbox_id, association_state, sequence_id, updated_at
+FROM
+	inbox_association_states
+WHERE
+	inbox_id = decode($1, 'hex')
+`
+
+func (q *Queries) GetInboxAssociationState(ctx context.Context, inboxID string) (InboxAssociationState, error) {
+	row := q.queryRow(ctx, q.getInboxAssociationStateStmt, getInboxAssociationState, inboxID)
+	var i InboxAssociationState
+	err := row.Scan(
+		&i.InboxID,
+		&i.AssociationState,
+		&i.SequenceID,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const insertAddressLog = `-- name: InsertAddressLog :execrows
-INSERT INTO address_log(address, inbox_id, association_sequence_id, revocation_sequence_id)
-	VALUES ($1, decode($2, 'hex'), $3, NULL)
-ON CONFLICT (address, inbox_id)
+INSERT INTO address_log(address, identifier_kind, inbox_id, association_sequence_id, revocation_sequence_id)
+	VALUES ($1, $2, decode($3, 'hex'), $4, NULL)
+ON CONFLICT (address, identifier_kind, inbox_id)
 	DO UPDATE SET
-		revocation_sequence_id = NULL, association_sequence_id = $3
+		revocation_sequence_id = NULL, association_sequence_id = $4
 	WHERE (address_log.revocation_sequence_id IS NULL
-		OR address_log.revocation_sequence_id < $3)
-		AND address_log.association_sequence_id < $3
+		OR address_log.revocation_sequence_id < $4)
+		AND address_log.association_sequence_id < $4
 `
 
 type InsertAddressLogParams struct {
 	Address               string
+	IdentifierKind        int16
 	InboxID               string
 	AssociationSequenceID sql.NullInt64
 }
 
 func (q *Queries) InsertAddressLog(ctx context.Context, arg InsertAddressLogParams) (int64, error) {
-	result, err := q.exec(ctx, q.insertAddressLogStmt, insertAddressLog, arg.Address, arg.InboxID, arg.AssociationSequenceID)
+	result, err := q.exec(ctx, q.insertAddressLogStmt, insertAddressLog,
+		arg.Address,
+		arg.IdentifierKind,
+		arg.InboxID,
+		arg.AssociationSequenceID,
+	)
 	if err != nil {
 		return 0, err
 	}
@@ -90,13 +192,14 @@ func (q *Queries) InsertAddressLog(ctx context.Context, arg InsertAddressLogPara
 const insertAddressLogsBatch = `-- name: InsertAddressLogsBatch :execrows
 WITH input AS (
     SELECT unnest($1::TEXT[]) AS address, 
-           decode($2, 'hex') AS inbox_id, 
-           $3::BIGINT AS association_sequence_id
+           $2::SMALLINT AS identifier_kind,
+           decode($3, 'hex') AS inbox_id, 
+           $4::BIGINT AS association_sequence_id
 )
-INSERT INTO address_log(address, inbox_id, association_sequence_id, revocation_sequence_id)
-SELECT address, inbox_id, association_sequence_id, NULL
+INSERT INTO address_log(address, identifier_kind, inbox_id, association_sequence_id, revocation_sequence_id)
+SELECT address, identifier_kind, inbox_id, association_sequence_id, NULL
 FROM input
-ON CONFLICT (address, inbox_id)
+ON CONFLICT (address, identifier_kind, inbox_id)
     DO UPDATE SET
         revocation_sequence_id = NULL, 
         association_sequence_id = EXCLUDED.association_sequence_id
@@ -107,12 +210,18 @@ ON CONFLICT (address, inbox_id)
 
 type InsertAddressLogsBatchParams struct {
 	Addresses             []string
+	IdentifierKind        int16
 	InboxID               string
 	AssociationSequenceID int64
 }
 
 func (q *Queries) InsertAddressLogsBatch(ctx context.Context, arg InsertAddressLogsBatchParams) (int64, error) {
-	result, err := q.exec(ctx, q.insertAddressLogsBatchStmt, insertAddressLogsBatch, pq.Array(arg.Addresses), arg.InboxID, arg.AssociationSequenceID)
+	result, err := q.exec(ctx, q.insertAddressLogsBatchStmt, insertAddressLogsBatch,
+		pq.Array(arg.Addresses),
+		arg.IdentifierKind,
+		arg.InboxID,
+		arg.AssociationSequenceID,
+	)
 	if err != nil {
 		return 0, err
 	}
@@ -126,17 +235,24 @@ SET
 	revocation_sequence_id = $1
 WHERE
 	address = $2
-	AND inbox_id = decode($3, 'hex')
+	AND identifier_kind = $3
+	AND inbox_id = decode($4, 'hex')
 `
 
 type RevokeAddressFromLogParams struct {
 	RevocationSequenceID sql.NullInt64
 	Address              string
+	IdentifierKind       int16
 	InboxID              string
 }
 
 func (q *Queries) RevokeAddressFromLog(ctx context.Context, arg RevokeAddressFromLogParams) (int64, error) {
-	result, err := q.exec(ctx, q.revokeAddressFromLogStmt, revokeAddressFromLog, arg.RevocationSequenceID, arg.Address, arg.InboxID)
+	result, err := q.exec(ctx, q.revokeAddressFromLogStmt, revokeAddressFromLog,
+		arg.RevocationSequenceID,
+		arg.Address,
+		arg.IdentifierKind,
+		arg.InboxID,
+	)
 	if err != nil {
 		return 0, err
 	}
@@ -146,24 +262,105 @@ func (q *Queries) RevokeAddressFromLog(ctx context.Context, arg RevokeAddressFro
 const revokeAddressFromLogBatch = `-- name: RevokeAddressFromLogBatch :execrows
 WITH input AS (
     SELECT unnest($1::TEXT[]) AS address,
-           decode($2, 'hex') AS inbox_id,
-           $3::BIGINT AS revocation_sequence_id
+           $2::SMALLINT AS identifier_kind,
+           decode($3, 'hex') AS inbox_id,
+           $4::BIGINT AS revocation_sequence_id
 )
 UPDATE address_log AS al
 SET revocation_sequence_id = input.revocation_sequence_id
 FROM input
 WHERE al.address = input.address
+  AND al.identifier_kind = input.identifier_kind
   AND al.inbox_id = input.inbox_id
 `
 
 type RevokeAddressFromLogBatchParams struct {
 	Addresses            []string
+	IdentifierKind       int16
 	InboxID              string
 	RevocationSequenceID int64
 }
 
 func (q *Queries) RevokeAddressFromLogBatch(ctx context.Context, arg RevokeAddressFromLogBatchParams) (int64, error) {
-	result, err := q.exec(ctx, q.revokeAddressFromLogBatchStmt, revokeAddressFromLogBatch, pq.Array(arg.Addresses), arg.InboxID, arg.RevocationSequenceID)
+	result, err := q.exec(ctx, q.revokeAddressFromLogBatchStmt, revokeAddressFromLogBatch,
+		pq.Array(arg.Addresses),
+		arg.IdentifierKind,
+		arg.InboxID,
+		arg.RevocationSequenceID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const selectInboxesWithoutAssociationState = `-- name: SelectInboxesWithoutAssociationState :many
+SELECT DISTINCT
+	a.inbox_id
+FROM
+	address_log a
+WHERE
+	a.inbox_id > $1::BYTEA
+	AND NOT EXISTS (
+		SELECT
+			1
+		FROM
+			inbox_association_states s
+		WHERE
+			s.inbox_id = a.inbox_id)
+ORDER BY
+	a.inbox_id
+LIMIT $2
+`
+
+type SelectInboxesWithoutAssociationStateParams struct {
+	AfterInboxID []byte
+	RowLimit     int32
+}
+
+// The inboxes in the address log with no stored association state, in inbox ID order, after
+// after_inbox_id.
+func (q *Queries) SelectInboxesWithoutAssociationState(ctx context.Context, arg SelectInboxesWithoutAssociationStateParams) ([][]byte, error) {
+	rows, err := q.query(ctx, q.selectInboxesWithoutAssociationStateStmt, selectInboxesWithoutAssociationState, arg.AfterInboxID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items [][]byte
+	for rows.Next() {
+		var inbox_id []byte
+		if err := rows.Scan(&inbox_id); err != nil {
+			return nil, err
+		}
+		items = append(items, inbox_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertInboxAssociationState = `-- name: UpsertInboxAssociationState :execrows
+INSERT INTO inbox_association_states(inbox_id, association_state, sequence_id)
+	VALUES (decode($1, 'hex'), $2, $3)
+ON CONFLICT (inbox_id)
+	DO UPDATE SET
+		association_state = EXCLUDED.association_state, sequence_id = EXCLUDED.sequence_id, updated_at = now()
+	WHERE
+		inbox_association_states.sequence_id < EXCLUDED.sequence_id
+`
+
+type UpsertInboxAssociationStateParams struct {
+	InboxID          string
+	AssociationState []byte
+	SequenceID       int64
+}
+
+func (q *Queries) UpsertInboxAssociationState(ctx context.Context, arg UpsertInboxAssociationStateParams) (int64, error) {
+	result, err := q.exec(ctx, q.upsertInboxAssociationStateStmt, upsertInboxAssociationState, arg.InboxID, arg.AssociationState, arg.SequenceID)
 	if err != nil {
 		return 0, err
 	}
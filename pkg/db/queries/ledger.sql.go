@@ -10,11 +10,16 @@ import (
 )
 
 const getLastEvent = `-- name: GetLastEvent :one
-SELECT event_id, payer_id, amount_picodollars, event_type, created_at
-FROM payer_ledger_events
-WHERE payer_id = $1
-    AND event_type = $2
-ORDER BY created_at DESC
+SELECT e.event_id, e.payer_id, e.amount_picodollars, e.event_type, e.created_at, e.sequence_id, e.block_number, e.reverses_event_id
+FROM payer_ledger_events e
+WHERE e.payer_id = $1
+    AND e.event_type = $2
+    AND NOT EXISTS (
+        SELECT 1
+        FROM payer_ledger_events r
+        WHERE r.reverses_event_id = e.event_id
+    )
+ORDER BY e.created_at DESC
 LIMIT 1
 `
 
@@ -23,6 +28,7 @@ type GetLastEventParams struct {
 	EventType int16
 }
 
+// Events that have been reversed by a reorg are skipped.
 func (q *Queries) GetLastEvent(ctx context.Context, arg GetLastEventParams) (PayerLedgerEvent, error) {
 	row := q.queryRow(ctx, q.getLastEventStmt, getLastEvent, arg.PayerID, arg.EventType)
 	var i PayerLedgerEvent
@@ -32,6 +38,9 @@ func (q *Queries) GetLastEvent(ctx context.Context, arg GetLastEventParams) (Pay
 		&i.AmountPicodollars,
 		&i.EventType,
 		&i.CreatedAt,
+		&i.SequenceID,
+		&i.BlockNumber,
+		&i.ReversesEventID,
 	)
 	return i, err
 }
@@ -54,13 +63,15 @@ INSERT INTO payer_ledger_events(
         event_id,
         payer_id,
         amount_picodollars,
-        event_type
+        event_type,
+        block_number
     )
 VALUES (
         $1,
         $2,
         $3,
-        $4
+        $4,
+        $5
     ) ON CONFLICT (event_id) DO NOTHING
 `
 
@@ -69,6 +80,7 @@ type InsertPayerLedgerEventParams struct {
 	PayerID           int32
 	AmountPicodollars int64
 	EventType         int16
+	BlockNumber       int64
 }
 
 func (q *Queries) InsertPayerLedgerEvent(ctx context.Context, arg InsertPayerLedgerEventParams) error {
@@ -77,6 +89,90 @@ func (q *Queries) InsertPayerLedgerEvent(ctx context.Context, arg InsertPayerLed
 		arg.PayerID,
 		arg.AmountPicodollars,
 		arg.EventType,
+		arg.BlockNumber,
 	)
 	return err
 }
+
+const insertPayerLedgerReversal = `-- name: InsertPayerLedgerReversal :execrows
+INSERT INTO payer_ledger_events(
+        event_id,
+        payer_id,
+        amount_picodollars,
+        event_type,
+        block_number,
+        reverses_event_id
+    )
+SELECT $1,
+    payer_id,
+    - amount_picodollars,
+    $2,
+    block_number,
+    event_id
+FROM payer_ledger_events
+WHERE event_id = $3
+    AND event_type <> $2 ON CONFLICT DO NOTHING
+`
+
+type InsertPayerLedgerReversalParams struct {
+	EventID         []byte
+	EventType       int16
+	ReversedEventID []byte
+}
+
+// Reverses an event by inserting its negation. Nothing is inserted if the event does not exist,
+// is itself a reversal, or has already been reversed.
+func (q *Queries) InsertPayerLedgerReversal(ctx context.Context, arg InsertPayerLedgerReversalParams) (int64, error) {
+	result, err := q.exec(ctx, q.insertPayerLedgerReversalStmt, insertPayerLedgerReversal, arg.EventID, arg.EventType, arg.ReversedEventID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const listPayerLedgerEvents = `-- name: ListPayerLedgerEvents :many
+SELECT event_id, payer_id, amount_picodollars, event_type, created_at, sequence_id, block_number, reverses_event_id
+FROM payer_ledger_events
+WHERE payer_id = $1
+    AND sequence_id > $2
+ORDER BY sequence_id ASC
+LIMIT $3
+`
+
+type ListPayerLedgerEventsParams struct {
+	PayerID         int32
+	SinceSequenceID int64
+	RowLimit        int32
+}
+
+func (q *Queries) ListPayerLedgerEvents(ctx context.Context, arg ListPayerLedgerEventsParams) ([]PayerLedgerEvent, error) {
+	rows, err := q.query(ctx, q.listPayerLedgerEventsStmt, listPayerLedgerEvents, arg.PayerID, arg.SinceSequenceID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PayerLedgerEvent
+	for rows.Next() {
+		var i PayerLedgerEvent
+		if err := rows.Scan(
+			&i.EventID,
+			&i.PayerID,
+			&i.AmountPicodollars,
+			&i.EventType,
+			&i.CreatedAt,
+			&i.SequenceID,
+			&i.BlockNumber,
+			&i.ReversesEventID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
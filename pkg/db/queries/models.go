@@ -14,6 +14,7 @@ type AddressLog struct {
 	InboxID               []byte
 	AssociationSequenceID sql.NullInt64
 	RevocationSequenceID  sql.NullInt64
+	IdentifierKind        int16
 }
 
 type BlockchainMessage struct {
@@ -53,6 +54,13 @@ type GatewayEnvelopesView struct {
 	OriginatorEnvelope   []byte
 }
 
+type InboxAssociationState struct {
+	InboxID          []byte
+	AssociationState []byte
+	SequenceID       int64
+	UpdatedAt        time.Time
+}
+
 type LatestBlock struct {
 	ContractAddress string
 	BlockNumber     int64
@@ -76,6 +84,18 @@ type MigrationTracker struct {
 	UpdatedAt      time.Time
 }
 
+type MisbehaviorReport struct {
+	ID                int64
+	ServerTimeNs      int64
+	ReporterNodeID    int32
+	MisbehavingNodeID int32
+	MisbehaviorType   int16
+	SubmittedByNode   bool
+	UnsignedReport    []byte
+	Signature         []byte
+	DedupKey          []byte
+}
+
 type NodeInfo struct {
 	NodeID      int32
 	PublicKey   []byte
@@ -98,12 +118,21 @@ type Payer struct {
 	Address string
 }
 
+type PayerBalanceLevel struct {
+	PayerID   int32
+	Level     int16
+	UpdatedAt time.Time
+}
+
 type PayerLedgerEvent struct {
 	EventID           []byte
 	PayerID           int32
 	AmountPicodollars int64
 	EventType         int16
 	CreatedAt         time.Time
+	SequenceID        int64
+	BlockNumber       int64
+	ReversesEventID   []byte
 }
 
 type PayerReport struct {
@@ -127,6 +156,31 @@ type PayerReportAttestation struct {
 	CreatedAt     sql.NullTime
 }
 
+type PayerUsageHistory struct {
+	PayerID                  int32
+	MinutesSinceEpoch        int32
+	OriginatorID             int32
+	TopicKind                int16
+	BaseFeePicodollars       int64
+	CongestionFeePicodollars int64
+	MessageCount             int32
+}
+
+type PayerUsageHistoryBackfill struct {
+	SingletonID             int16
+	BeforeMinutesSinceEpoch int32
+	AfterPayerID            int32
+	AfterOriginatorID       int32
+	AfterMinutesSinceEpoch  int32
+}
+
+type PublishedPayerEnvelope struct {
+	PayerEnvelopeHash    []byte
+	OriginatorSequenceID int64
+	OriginatorEnvelope   []byte
+	PublishedAt          time.Time
+}
+
 type StagedOriginatorEnvelope struct {
 	ID             int64
 	OriginatorTime time.Time
@@ -134,6 +188,15 @@ type StagedOriginatorEnvelope struct {
 	PayerEnvelope  []byte
 }
 
+type SyncGap struct {
+	OriginatorNodeID int32
+	StartSequenceID  int64
+	EndSequenceID    int64
+	DetectedAtNs     int64
+	Attempts         int32
+	AbandonedAtNs    sql.NullInt64
+}
+
 type UnsettledUsage struct {
 	PayerID           int32
 	OriginatorID      int32
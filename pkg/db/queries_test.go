@@ -11,13 +11,24 @@ import (
 	"github.com/xmtp/xmtpd/pkg/testutils"
 )
 
+const (
+	ethereumIdentifierKind int16 = 1
+	passkeyIdentifierKind  int16 = 2
+)
+
 func getAddressLogState(
 	t *testing.T,
 	querier *queries.Queries,
 	address string,
 	inboxID string,
 ) *queries.GetAddressLogsRow {
-	addressLogs, err := querier.GetAddressLogs(context.Background(), []string{address})
+	addressLogs, err := querier.GetAddressLogs(
+		context.Background(),
+		queries.GetAddressLogsParams{
+			Addresses:       []string{address},
+			IdentifierKinds: []int16{ethereumIdentifierKind},
+		},
+	)
 	require.NoError(t, err)
 
 	if len(addressLogs) == 0 {
@@ -42,6 +53,7 @@ func TestInsertAddressLog(t *testing.T) {
 		ctx,
 		queries.InsertAddressLogParams{
 			Address:               address,
+			IdentifierKind:        ethereumIdentifierKind,
 			InboxID:               inboxID,
 			AssociationSequenceID: xmtpd_db.NullInt64(1),
 		},
@@ -57,6 +69,7 @@ func TestInsertAddressLog(t *testing.T) {
 		ctx,
 		queries.InsertAddressLogParams{
 			Address:               address,
+			IdentifierKind:        ethereumIdentifierKind,
 			InboxID:               inboxID,
 			AssociationSequenceID: xmtpd_db.NullInt64(2),
 		},
@@ -72,6 +85,7 @@ func TestInsertAddressLog(t *testing.T) {
 		ctx,
 		queries.InsertAddressLogParams{
 			Address:               address,
+			IdentifierKind:        ethereumIdentifierKind,
 			InboxID:               inboxID,
 			AssociationSequenceID: xmtpd_db.NullInt64(1),
 		},
@@ -97,6 +111,7 @@ func TestRevokeAddressLog(t *testing.T) {
 		ctx,
 		queries.InsertAddressLogParams{
 			Address:               address,
+			IdentifierKind:        ethereumIdentifierKind,
 			InboxID:               inboxID,
 			AssociationSequenceID: xmtpd_db.NullInt64(1),
 		},
@@ -107,6 +122,7 @@ func TestRevokeAddressLog(t *testing.T) {
 		ctx,
 		queries.RevokeAddressFromLogParams{
 			Address:              address,
+			IdentifierKind:       ethereumIdentifierKind,
 			InboxID:              inboxID,
 			RevocationSequenceID: xmtpd_db.NullInt64(2),
 		},
@@ -123,6 +139,7 @@ func TestRevokeAddressLog(t *testing.T) {
 		ctx,
 		queries.InsertAddressLogParams{
 			Address:               address,
+			IdentifierKind:        ethereumIdentifierKind,
 			InboxID:               inboxID,
 			AssociationSequenceID: xmtpd_db.NullInt64(3),
 		},
@@ -134,3 +151,93 @@ func TestRevokeAddressLog(t *testing.T) {
 	require.NotNil(t, addressLog)
 	require.Equal(t, int64(3), addressLog.AssociationSequenceID.Int64)
 }
+
+func TestAddressLogIdentifierKinds(t *testing.T) {
+	ctx := context.Background()
+	db, _ := testutils.NewRawDB(t, ctx)
+
+	querier := queries.New(db)
+
+	identifier := testutils.RandomString(20)
+	ethereumInboxID := testutils.RandomInboxIDString()
+	passkeyInboxID := testutils.RandomInboxIDString()
+
+	for _, params := range []queries.InsertAddressLogParams{
+		{
+			Address:               identifier,
+			IdentifierKind:        ethereumIdentifierKind,
+			InboxID:               ethereumInboxID,
+			AssociationSequenceID: xmtpd_db.NullInt64(1),
+		},
+		{
+			Address:               identifier,
+			IdentifierKind:        passkeyIdentifierKind,
+			InboxID:               passkeyInboxID,
+			AssociationSequenceID: xmtpd_db.NullInt64(2),
+		},
+	} {
+		_, err := querier.InsertAddressLog(ctx, params)
+		require.NoError(t, err)
+	}
+
+	addressLogs, err := querier.GetAddressLogs(ctx, queries.GetAddressLogsParams{
+		Addresses:       []string{identifier, identifier},
+		IdentifierKinds: []int16{ethereumIdentifierKind, passkeyIdentifierKind},
+	})
+	require.NoError(t, err)
+	require.Len(t, addressLogs, 2)
+
+	inboxIDs := make(map[int16]string)
+	for _, addressLog := range addressLogs {
+		inboxIDs[addressLog.IdentifierKind] = addressLog.InboxID
+	}
+	require.Equal(t, ethereumInboxID, inboxIDs[ethereumIdentifierKind])
+	require.Equal(t, passkeyInboxID, inboxIDs[passkeyIdentifierKind])
+}
+
+func TestGetIdentifiersForInbox(t *testing.T) {
+	ctx := context.Background()
+	db, _ := testutils.NewRawDB(t, ctx)
+
+	querier := queries.New(db)
+
+	inboxID := testutils.RandomInboxIDString()
+	current := testutils.RandomString(20)
+	revoked := testutils.RandomString(20)
+
+	for i, address := range []string{current, revoked} {
+		_, err := querier.InsertAddressLog(ctx, queries.InsertAddressLogParams{
+			Address:               address,
+			IdentifierKind:        ethereumIdentifierKind,
+			InboxID:               inboxID,
+			AssociationSequenceID: xmtpd_db.NullInt64(int64(i + 1)),
+		})
+		require.NoError(t, err)
+	}
+
+	_, err := querier.RevokeAddressFromLog(ctx, queries.RevokeAddressFromLogParams{
+		RevocationSequenceID: xmtpd_db.NullInt64(3),
+		Address:              revoked,
+		IdentifierKind:       ethereumIdentifierKind,
+		InboxID:              inboxID,
+	})
+	require.NoError(t, err)
+
+	identifiers, err := querier.GetIdentifiersForInbox(ctx, queries.GetIdentifiersForInboxParams{
+		InboxID: inboxID,
+	})
+	require.NoError(t, err)
+	require.Len(t, identifiers, 1)
+	require.Equal(t, current, identifiers[0].Address)
+	require.Equal(t, int64(1), identifiers[0].AssociationSequenceID.Int64)
+	require.False(t, identifiers[0].RevocationSequenceID.Valid)
+
+	identifiers, err = querier.GetIdentifiersForInbox(ctx, queries.GetIdentifiersForInboxParams{
+		InboxID:        inboxID,
+		IncludeRevoked: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, identifiers, 2)
+	require.Equal(t, revoked, identifiers[1].Address)
+	require.Equal(t, int64(3), identifiers[1].RevocationSequenceID.Int64)
+}
@@ -1,10 +1,17 @@
 -- name: AdvisoryLockWithKey :exec
 SELECT pg_advisory_xact_lock(@locking_key);
 
+-- name: AdvisoryUnlockWithKey :one
+-- Releases a session-level advisory lock taken with TrySessionAdvisoryLockWithKey.
+SELECT pg_advisory_unlock(@locking_key) as unlocked;
+
 -- name: SharedAdvisoryLockWithKey :exec
 SELECT pg_advisory_xact_lock_shared(@locking_key);
 
 -- name: TryAdvisoryLockWithKey :one
 SELECT pg_try_advisory_xact_lock(@locking_key) as lock_succeeded;
 
-
+-- name: TrySessionAdvisoryLockWithKey :one
+-- Takes a session-level advisory lock, which is held until it is released or the connection
+-- is closed.
+SELECT pg_try_advisory_lock(@locking_key) as lock_succeeded;
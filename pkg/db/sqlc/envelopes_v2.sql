@@ -339,8 +339,8 @@ FROM insert_gateway_envelope_batch_v2(
 
 -- name: InsertGatewayEnvelopeBatchV3 :one
 -- Batch envelope insert calling the renamed insert_gateway_envelope_batch_v3
--- stored function (which targets gateway_envelopes_blob). This is the
--- production version.
+-- stored function (which targets gateway_envelopes_blob). Superseded by
+-- InsertGatewayEnvelopeBatchV4, which also records payer usage history.
 SELECT
     inserted_meta_rows::bigint,
     inserted_blob_rows::bigint,
@@ -358,3 +358,27 @@ FROM insert_gateway_envelope_batch_v3(
     @count_usage::boolean[],
     @count_congestion::boolean[]
 );
+
+-- name: InsertGatewayEnvelopeBatchV4 :one
+-- Batch envelope insert calling insert_gateway_envelope_batch_v4, which also
+-- records the spend of each envelope in payer_usage_history. This is the
+-- production version.
+SELECT
+    inserted_meta_rows::bigint,
+    inserted_blob_rows::bigint,
+    affected_usage_rows::bigint,
+    affected_history_rows::bigint,
+    affected_congestion_rows::bigint
+FROM insert_gateway_envelope_batch_v4(
+    @originator_node_ids::int[],
+    @originator_sequence_ids::bigint[],
+    @topics::bytea[],
+    @payer_ids::int[],
+    @gateway_times::timestamp[],
+    @expiries::bigint[],
+    @originator_envelopes::bytea[],
+    @spend_picodollars::bigint[],
+    @congestion_picodollars::bigint[],
+    @count_usage::boolean[],
+    @count_congestion::boolean[]
+);
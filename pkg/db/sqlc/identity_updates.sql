@@ -1,27 +1,50 @@
 -- name: GetAddressLogs :many
 SELECT
 	a.address,
+	a.identifier_kind,
 	encode(a.inbox_id, 'hex') AS inbox_id,
 	a.association_sequence_id
 FROM
 	address_log a
 	INNER JOIN (
 		SELECT
-			address,
-			MAX(association_sequence_id) AS max_association_sequence_id
+			l.address,
+			l.identifier_kind,
+			MAX(l.association_sequence_id) AS max_association_sequence_id
 		FROM
-			address_log
+			address_log l
+			INNER JOIN unnest(@addresses::TEXT[], @identifier_kinds::SMALLINT[]) AS i(address, identifier_kind)
+				ON l.address = i.address
+				AND l.identifier_kind = i.identifier_kind
 		WHERE
-			address = ANY (@addresses::TEXT[])
-			AND revocation_sequence_id IS NULL
+			l.revocation_sequence_id IS NULL
 		GROUP BY
-			address) b ON a.address = b.address
+			l.address,
+			l.identifier_kind) b ON a.address = b.address
+	AND a.identifier_kind = b.identifier_kind
 	AND a.association_sequence_id = b.max_association_sequence_id;
 
+-- name: GetIdentifiersForInbox :many
+SELECT
+	address,
+	identifier_kind,
+	association_sequence_id,
+	revocation_sequence_id
+FROM
+	address_log
+WHERE
+	inbox_id = decode(@inbox_id, 'hex')
+	AND (@include_revoked::BOOLEAN
+		OR revocation_sequence_id IS NULL)
+ORDER BY
+	association_sequence_id,
+	identifier_kind,
+	address;
+
 -- name: InsertAddressLog :execrows
-INSERT INTO address_log(address, inbox_id, association_sequence_id, revocation_sequence_id)
-	VALUES (@address, decode(@inbox_id, 'hex'), @association_sequence_id, NULL)
-ON CONFLICT (address, inbox_id)
+INSERT INTO address_log(address, identifier_kind, inbox_id, association_sequence_id, revocation_sequence_id)
+	VALUES (@address, @identifier_kind, decode(@inbox_id, 'hex'), @association_sequence_id, NULL)
+ON CONFLICT (address, identifier_kind, inbox_id)
 	DO UPDATE SET
 		revocation_sequence_id = NULL, association_sequence_id = @association_sequence_id
 	WHERE (address_log.revocation_sequence_id IS NULL
@@ -31,13 +54,14 @@ ON CONFLICT (address, inbox_id)
 -- name: InsertAddressLogsBatch :execrows
 WITH input AS (
     SELECT unnest(@addresses::TEXT[]) AS address, 
+           @identifier_kind::SMALLINT AS identifier_kind,
            decode(@inbox_id, 'hex') AS inbox_id, 
            @association_sequence_id::BIGINT AS association_sequence_id
 )
-INSERT INTO address_log(address, inbox_id, association_sequence_id, revocation_sequence_id)
-SELECT address, inbox_id, association_sequence_id, NULL
+INSERT INTO address_log(address, identifier_kind, inbox_id, association_sequence_id, revocation_sequence_id)
+SELECT address, identifier_kind, inbox_id, association_sequence_id, NULL
 FROM input
-ON CONFLICT (address, inbox_id)
+ON CONFLICT (address, identifier_kind, inbox_id)
     DO UPDATE SET
         revocation_sequence_id = NULL, 
         association_sequence_id = EXCLUDED.association_sequence_id
@@ -52,11 +76,13 @@ SET
 	revocation_sequence_id = @revocation_sequence_id
 WHERE
 	address = @address
+	AND identifier_kind = @identifier_kind
 	AND inbox_id = decode(@inbox_id, 'hex');
 
 -- name: RevokeAddressFromLogBatch :execrows
 WITH input AS (
     SELECT unnest(@addresses::TEXT[]) AS address,
+           @identifier_kind::SMALLINT AS identifier_kind,
            decode(@inbox_id, 'hex') AS inbox_id,
            @revocation_sequence_id::BIGINT AS revocation_sequence_id
 )
@@ -64,4 +90,42 @@ UPDATE address_log AS al
 SET revocation_sequence_id = input.revocation_sequence_id
 FROM input
 WHERE al.address = input.address
+  AND al.identifier_kind = input.identifier_kind
   AND al.inbox_id = input.inbox_id;
+
+-- name: GetInboxAssociationState :one
+SELECT
+	*
+FROM
+	inbox_association_states
+WHERE
+	inbox_id = decode(@inbox_id, 'hex');
+
+-- name: UpsertInboxAssociationState :execrows
+INSERT INTO inbox_association_states(inbox_id, association_state, sequence_id)
+	VALUES (decode(@inbox_id, 'hex'), @association_state, @sequence_id)
+ON CONFLICT (inbox_id)
+	DO UPDATE SET
+		association_state = EXCLUDED.association_state, sequence_id = EXCLUDED.sequence_id, updated_at = now()
+	WHERE
+		inbox_association_states.sequence_id < EXCLUDED.sequence_id;
+
+-- name: SelectInboxesWithoutAssociationState :many
+-- The inboxes in the address log with no stored association state, in inbox ID order, after
+-- after_inbox_id.
+SELECT DISTINCT
+	a.inbox_id
+FROM
+	address_log a
+WHERE
+	a.inbox_id > @after_inbox_id::BYTEA
+	AND NOT EXISTS (
+		SELECT
+			1
+		FROM
+			inbox_association_states s
+		WHERE
+			s.inbox_id = a.inbox_id)
+ORDER BY
+	a.inbox_id
+LIMIT @row_limit;
@@ -3,24 +3,61 @@ INSERT INTO payer_ledger_events(
         event_id,
         payer_id,
         amount_picodollars,
-        event_type
+        event_type,
+        block_number
     )
 VALUES (
         @event_id,
         @payer_id,
         @amount_picodollars,
-        @event_type
+        @event_type,
+        @block_number
     ) ON CONFLICT (event_id) DO NOTHING;
 
+-- name: InsertPayerLedgerReversal :execrows
+-- Reverses an event by inserting its negation. Nothing is inserted if the event does not exist,
+-- is itself a reversal, or has already been reversed.
+INSERT INTO payer_ledger_events(
+        event_id,
+        payer_id,
+        amount_picodollars,
+        event_type,
+        block_number,
+        reverses_event_id
+    )
+SELECT @event_id,
+    payer_id,
+    - amount_picodollars,
+    @event_type,
+    block_number,
+    event_id
+FROM payer_ledger_events
+WHERE event_id = @reversed_event_id
+    AND event_type <> @event_type ON CONFLICT DO NOTHING;
+
 -- name: GetPayerBalance :one
 SELECT COALESCE(SUM(amount_picodollars), 0)::BIGINT AS balance
 FROM payer_ledger_events
 WHERE payer_id = @payer_id;
 
 -- name: GetLastEvent :one
+-- Events that have been reversed by a reorg are skipped.
+SELECT *
+FROM payer_ledger_events e
+WHERE e.payer_id = @payer_id
+    AND e.event_type = @event_type
+    AND NOT EXISTS (
+        SELECT 1
+        FROM payer_ledger_events r
+        WHERE r.reverses_event_id = e.event_id
+    )
+ORDER BY e.created_at DESC
+LIMIT 1;
+
+-- name: ListPayerLedgerEvents :many
 SELECT *
 FROM payer_ledger_events
 WHERE payer_id = @payer_id
-    AND event_type = @event_type
-ORDER BY created_at DESC
-LIMIT 1;
\ No newline at end of file
+    AND sequence_id > @since_sequence_id
+ORDER BY sequence_id ASC
+LIMIT @row_limit;
@@ -0,0 +1,43 @@
+-- name: InsertMisbehaviorReport :one
+INSERT INTO misbehavior_reports(
+		server_time_ns,
+		reporter_node_id,
+		misbehaving_node_id,
+		misbehavior_type,
+		submitted_by_node,
+		unsigned_report,
+		signature,
+		dedup_key
+	)
+VALUES (
+		@server_time_ns,
+		@reporter_node_id,
+		@misbehaving_node_id,
+		@misbehavior_type,
+		@submitted_by_node,
+		@unsigned_report,
+		@signature,
+		@dedup_key
+	) ON CONFLICT (dedup_key) DO NOTHING
+RETURNING id;
+
+-- name: SelectMisbehaviorReports :many
+-- Selects the reports after the (after_ns, after_id) cursor, ordered by server time and ID.
+SELECT *
+FROM misbehavior_reports
+WHERE (server_time_ns, id) > (@after_ns::BIGINT, @after_id::BIGINT)
+	AND (
+		@before_ns::BIGINT = 0
+		OR server_time_ns < @before_ns::BIGINT
+	)
+	AND (
+		@misbehaving_node_id::INT = 0
+		OR misbehaving_node_id = @misbehaving_node_id::INT
+	)
+	AND (
+		@misbehavior_type::SMALLINT = 0
+		OR misbehavior_type = @misbehavior_type::SMALLINT
+	)
+ORDER BY server_time_ns ASC,
+	id ASC
+LIMIT @row_limit;
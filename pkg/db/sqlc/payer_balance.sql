@@ -0,0 +1,61 @@
+-- name: GetPayerBalanceSnapshots :many
+-- Returns the settled balance, unsettled usage and spend since a minute for every payer with
+-- any ledger events or usage, or only for @payer_id if it is not 0.
+WITH balances AS (
+	SELECT payer_id,
+		SUM(amount_picodollars)::BIGINT AS balance_picodollars
+	FROM payer_ledger_events
+	WHERE @payer_id::INTEGER = 0
+		OR payer_id = @payer_id::INTEGER
+	GROUP BY payer_id
+),
+unsettled AS (
+	SELECT payer_id,
+		SUM(spend_picodollars)::BIGINT AS unsettled_picodollars
+	FROM unsettled_usage
+	WHERE @payer_id::INTEGER = 0
+		OR payer_id = @payer_id::INTEGER
+	GROUP BY payer_id
+),
+recent AS (
+	SELECT payer_id,
+		SUM(base_fee_picodollars + congestion_fee_picodollars)::BIGINT AS recent_spend_picodollars
+	FROM payer_usage_history
+	WHERE minutes_since_epoch >= @spend_since_minute::INTEGER
+		AND (
+			@payer_id::INTEGER = 0
+			OR payer_id = @payer_id::INTEGER
+		)
+	GROUP BY payer_id
+)
+SELECT p.id AS payer_id,
+	p.address AS payer_address,
+	COALESCE(b.balance_picodollars, 0)::BIGINT AS balance_picodollars,
+	COALESCE(u.unsettled_picodollars, 0)::BIGINT AS unsettled_picodollars,
+	COALESCE(r.recent_spend_picodollars, 0)::BIGINT AS recent_spend_picodollars
+FROM payers p
+	LEFT JOIN balances b ON b.payer_id = p.id
+	LEFT JOIN unsettled u ON u.payer_id = p.id
+	LEFT JOIN recent r ON r.payer_id = p.id
+WHERE (
+		@payer_id::INTEGER = 0
+		OR p.id = @payer_id::INTEGER
+	)
+	AND (
+		b.payer_id IS NOT NULL
+		OR u.payer_id IS NOT NULL
+		OR r.payer_id IS NOT NULL
+	)
+ORDER BY p.id;
+
+-- name: GetPayerBalanceLevels :many
+SELECT payer_id,
+	level
+FROM payer_balance_levels;
+
+-- name: SetPayerBalanceLevel :exec
+INSERT INTO payer_balance_levels(payer_id, level, updated_at)
+VALUES (@payer_id, @level, now()) ON CONFLICT (payer_id) DO
+UPDATE
+SET level = EXCLUDED.level,
+	updated_at = EXCLUDED.updated_at;
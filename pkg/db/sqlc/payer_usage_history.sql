@@ -0,0 +1,160 @@
+-- name: IncrementPayerUsageHistory :exec
+INSERT INTO payer_usage_history(
+		payer_id,
+		minutes_since_epoch,
+		originator_id,
+		topic_kind,
+		base_fee_picodollars,
+		congestion_fee_picodollars,
+		message_count
+	)
+VALUES (
+		@payer_id,
+		@minutes_since_epoch,
+		@originator_id,
+		@topic_kind,
+		@base_fee_picodollars,
+		@congestion_fee_picodollars,
+		@message_count
+	) ON CONFLICT (payer_id, minutes_since_epoch, originator_id, topic_kind) DO
+UPDATE
+SET base_fee_picodollars = payer_usage_history.base_fee_picodollars + @base_fee_picodollars,
+	congestion_fee_picodollars = payer_usage_history.congestion_fee_picodollars + @congestion_fee_picodollars,
+	message_count = payer_usage_history.message_count + @message_count;
+
+-- name: GetPayerUsageHistory :many
+-- Aggregates a payer's spend between two minutes (start inclusive, end exclusive) by hour or
+-- day, and optionally by originator and topic kind. Dimensions that are not grouped by are
+-- returned as -1. A row limit of 0 returns every row.
+SELECT EXTRACT(
+		EPOCH
+		FROM DATE_TRUNC(
+				CASE
+					WHEN @group_by = 'hour' THEN 'hour'
+					ELSE 'day'
+				END,
+				TO_TIMESTAMP(minutes_since_epoch * 60)
+			)
+	)::BIGINT AS time_period,
+	(
+		CASE
+			WHEN @group_by_originator::BOOLEAN THEN originator_id
+			ELSE -1
+		END
+	)::INTEGER AS originator_id,
+	(
+		CASE
+			WHEN @group_by_topic_kind::BOOLEAN THEN topic_kind
+			ELSE -1
+		END
+	)::SMALLINT AS topic_kind,
+	COALESCE(SUM(base_fee_picodollars), 0)::BIGINT AS total_base_fee_picodollars,
+	COALESCE(SUM(congestion_fee_picodollars), 0)::BIGINT AS total_congestion_fee_picodollars,
+	COALESCE(SUM(message_count), 0)::BIGINT AS total_message_count
+FROM payer_usage_history
+WHERE payer_id = @payer_id
+	AND minutes_since_epoch >= @start_minutes_since_epoch::INTEGER
+	AND minutes_since_epoch < @end_minutes_since_epoch::INTEGER
+GROUP BY 1,
+	2,
+	3
+ORDER BY 1,
+	2,
+	3
+LIMIT NULLIF(@row_limit::INTEGER, 0);
+
+-- name: GetPayerUsageHistoryStart :one
+-- The first minute with usage history for a payer, or -1 if it has none.
+SELECT COALESCE(MIN(minutes_since_epoch), -1)::INTEGER AS minutes_since_epoch
+FROM payer_usage_history
+WHERE payer_id = @payer_id;
+
+-- name: DeletePayerUsageHistoryBefore :execrows
+-- Deletes up to row_limit rows of usage history older than a minute.
+WITH deletable AS (
+	SELECT ctid
+	FROM payer_usage_history
+	WHERE minutes_since_epoch < @before_minutes_since_epoch::INTEGER
+	LIMIT @row_limit::INTEGER
+)
+DELETE FROM payer_usage_history
+WHERE ctid IN (
+		SELECT ctid
+		FROM deletable
+	);
+
+-- name: BackfillPayerUsageHistory :one
+-- Backfills the usage history from up to row_limit rows of unsettled usage after the backfill
+-- cursor, and moves the cursor past them. Fee components and topics were not recorded, so the
+-- spend is recorded as base fee under topic kind -1. Usage that already has history for its
+-- payer, originator and minute is skipped. Returns the number of unsettled usage rows read.
+WITH batch AS (
+	SELECT u.payer_id,
+		u.originator_id,
+		u.minutes_since_epoch,
+		u.spend_picodollars,
+		u.message_count
+	FROM unsettled_usage u
+		JOIN payer_usage_history_backfill c ON (
+			u.payer_id,
+			u.originator_id,
+			u.minutes_since_epoch
+		) > (
+			c.after_payer_id,
+			c.after_originator_id,
+			c.after_minutes_since_epoch
+		)
+	WHERE u.minutes_since_epoch < c.before_minutes_since_epoch
+	ORDER BY u.payer_id,
+		u.originator_id,
+		u.minutes_since_epoch
+	LIMIT @row_limit::INTEGER
+), inserted AS (
+	INSERT INTO payer_usage_history(
+			payer_id,
+			minutes_since_epoch,
+			originator_id,
+			topic_kind,
+			base_fee_picodollars,
+			congestion_fee_picodollars,
+			message_count
+		)
+	SELECT b.payer_id,
+		b.minutes_since_epoch,
+		b.originator_id,
+		-1,
+		b.spend_picodollars,
+		0,
+		b.message_count
+	FROM batch b
+		JOIN payers p ON p.id = b.payer_id
+	WHERE NOT EXISTS (
+			SELECT 1
+			FROM payer_usage_history h
+			WHERE h.payer_id = b.payer_id
+				AND h.minutes_since_epoch = b.minutes_since_epoch
+				AND h.originator_id = b.originator_id
+		)
+	ON CONFLICT DO NOTHING
+), last_read AS (
+	SELECT payer_id,
+		originator_id,
+		minutes_since_epoch
+	FROM batch
+	ORDER BY payer_id DESC,
+		originator_id DESC,
+		minutes_since_epoch DESC
+	LIMIT 1
+), moved AS (
+	UPDATE payer_usage_history_backfill
+	SET after_payer_id = last_read.payer_id,
+		after_originator_id = last_read.originator_id,
+		after_minutes_since_epoch = last_read.minutes_since_epoch
+	FROM last_read
+)
+SELECT COUNT(*)::INTEGER AS num_rows
+FROM batch;
+
+-- name: DeletePayerUsageHistoryBackfill :execrows
+-- Deletes the backfill cursor once the backfill is done.
+DELETE FROM payer_usage_history_backfill;
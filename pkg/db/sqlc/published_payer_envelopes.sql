@@ -0,0 +1,42 @@
+-- name: SelectPublishedPayerEnvelopes :many
+SELECT *
+FROM published_payer_envelopes
+WHERE payer_envelope_hash = ANY(@payer_envelope_hashes::BYTEA[])
+	AND published_at >= now() - make_interval(secs => @window_seconds::FLOAT);
+
+-- name: InsertPublishedPayerEnvelopes :exec
+INSERT INTO published_payer_envelopes(
+		payer_envelope_hash,
+		originator_sequence_id,
+		originator_envelope
+	)
+SELECT u.payer_envelope_hash,
+	u.originator_sequence_id,
+	u.originator_envelope
+FROM unnest(
+		@payer_envelope_hashes::BYTEA[],
+		@originator_sequence_ids::BIGINT[],
+		@originator_envelopes::BYTEA[]
+	) AS u(
+		payer_envelope_hash,
+		originator_sequence_id,
+		originator_envelope
+	) ON CONFLICT (payer_envelope_hash) DO
+UPDATE
+SET originator_sequence_id = EXCLUDED.originator_sequence_id,
+	originator_envelope = EXCLUDED.originator_envelope,
+	published_at = now();
+
+-- name: LockPayerEnvelopeHashes :exec
+-- Takes a lock on each payer envelope hash until the end of the enclosing transaction. The
+-- locks are taken in a fixed order, so that concurrent publishes cannot deadlock.
+SELECT pg_advisory_xact_lock(hashtext('published_payer_envelopes'), k.lock_key)
+FROM (
+		SELECT DISTINCT hashtext(encode(h, 'hex')) AS lock_key
+		FROM unnest(@payer_envelope_hashes::BYTEA[]) AS h
+	) AS k
+ORDER BY k.lock_key;
+
+-- name: DeleteExpiredPublishedPayerEnvelopes :execrows
+DELETE FROM published_payer_envelopes
+WHERE published_at < now() - make_interval(secs => @window_seconds::FLOAT);
@@ -0,0 +1,64 @@
+-- name: InsertSyncGap :exec
+INSERT INTO sync_gaps(
+		originator_node_id,
+		start_sequence_id,
+		end_sequence_id,
+		detected_at_ns
+	)
+VALUES (
+		@originator_node_id,
+		@start_sequence_id,
+		@end_sequence_id,
+		@detected_at_ns
+	) ON CONFLICT DO NOTHING;
+
+-- name: SelectSyncGaps :many
+SELECT *
+FROM sync_gaps
+WHERE abandoned_at_ns IS NULL
+ORDER BY attempts ASC,
+	detected_at_ns ASC
+LIMIT @row_limit;
+
+-- name: UpdateSyncGapStart :exec
+UPDATE sync_gaps
+SET start_sequence_id = @new_start_sequence_id,
+	attempts = 0
+WHERE originator_node_id = @originator_node_id
+	AND start_sequence_id = @start_sequence_id;
+
+-- name: IncrementSyncGapAttempts :exec
+UPDATE sync_gaps
+SET attempts = attempts + 1
+WHERE originator_node_id = @originator_node_id
+	AND start_sequence_id = @start_sequence_id;
+
+-- name: AbandonSyncGap :exec
+-- Stops backfilling a gap. Abandoned gaps are kept for operators to inspect.
+UPDATE sync_gaps
+SET abandoned_at_ns = @abandoned_at_ns
+WHERE originator_node_id = @originator_node_id
+	AND start_sequence_id = @start_sequence_id;
+
+-- name: DeleteSyncGap :exec
+DELETE FROM sync_gaps
+WHERE originator_node_id = @originator_node_id
+	AND start_sequence_id = @start_sequence_id;
+
+-- name: SelectSyncGapStats :many
+-- The age of the oldest open gap is 0 when an originator only has abandoned gaps.
+SELECT originator_node_id,
+	COUNT(*) FILTER (
+		WHERE abandoned_at_ns IS NULL
+	)::BIGINT AS open_gaps,
+	COUNT(*) FILTER (
+		WHERE abandoned_at_ns IS NOT NULL
+	)::BIGINT AS abandoned_gaps,
+	COALESCE(
+		MIN(detected_at_ns) FILTER (
+			WHERE abandoned_at_ns IS NULL
+		),
+		0
+	)::BIGINT AS oldest_detected_at_ns
+FROM sync_gaps
+GROUP BY originator_node_id;
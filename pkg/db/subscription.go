@@ -9,7 +9,6 @@ import (
 	"github.com/xmtp/xmtpd/pkg/tracing"
 	"github.com/xmtp/xmtpd/pkg/utils"
 	"go.uber.org/zap"
-	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/ext"
 )
 
 type PollableDBQuery[ValueType any, CursorType any] func(
@@ -114,7 +113,7 @@ func (s *DBSubscription[ValueType, CursorType]) poll(trigger string) {
 		}
 
 		if err != nil {
-			span.SetTag(ext.Error, true)
+			span.SetTag(tracing.TagError, true)
 			// Log is extremely noisy during test teardown
 			s.logger.Error(
 				"error querying for database subscription",
@@ -22,8 +22,10 @@ type GatewayEnvelopeRow struct {
 	Expiry             int64
 	OriginatorEnvelope []byte
 	SpendPicodollars   int64
-	CountUsage         bool // track unsettled usage for this envelope
-	CountCongestion    bool // track originator congestion for this envelope
+	// The part of SpendPicodollars charged for congestion, recorded in the payer's usage history.
+	CongestionPicodollars int64
+	CountUsage            bool // track unsettled usage for this envelope
+	CountCongestion       bool // track originator congestion for this envelope
 }
 
 type GatewayEnvelopeBatch struct {
@@ -61,12 +63,12 @@ func (b *GatewayEnvelopeBatch) Reset() {
 	b.Envelopes = make([]GatewayEnvelopeRow, 0)
 }
 
-func (b *GatewayEnvelopeBatch) ToParamsV3() queries.InsertGatewayEnvelopeBatchV3Params {
+func (b *GatewayEnvelopeBatch) ToParamsV4() queries.InsertGatewayEnvelopeBatchV4Params {
 	n := b.Len()
 
 	b.ensureOrdered()
 
-	params := queries.InsertGatewayEnvelopeBatchV3Params{
+	params := queries.InsertGatewayEnvelopeBatchV4Params{
 		OriginatorNodeIds:     make([]int32, n),
 		OriginatorSequenceIds: make([]int64, n),
 		Topics:                make([][]byte, n),
@@ -75,6 +77,7 @@ func (b *GatewayEnvelopeBatch) ToParamsV3() queries.InsertGatewayEnvelopeBatchV3
 		Expiries:              make([]int64, n),
 		OriginatorEnvelopes:   make([][]byte, n),
 		SpendPicodollars:      make([]int64, n),
+		CongestionPicodollars: make([]int64, n),
 		CountUsage:            make([]bool, n),
 		CountCongestion:       make([]bool, n),
 	}
@@ -88,6 +91,7 @@ func (b *GatewayEnvelopeBatch) ToParamsV3() queries.InsertGatewayEnvelopeBatchV3
 		params.Expiries[i] = row.Expiry
 		params.OriginatorEnvelopes[i] = row.OriginatorEnvelope
 		params.SpendPicodollars[i] = row.SpendPicodollars
+		params.CongestionPicodollars[i] = row.CongestionPicodollars
 		params.CountUsage[i] = row.CountUsage
 		params.CountCongestion[i] = row.CountCongestion
 	}
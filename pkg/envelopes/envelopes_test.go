@@ -184,3 +184,38 @@ func TestRecoverSigner(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEqual(t, signer.Hex(), newSigner.Hex())
 }
+
+func TestRecoverOriginatorSigner(t *testing.T) {
+	originatorPrivateKey := testutils.RandomPrivateKey(t)
+	rawOriginatorEnv := envelopeTestUtils.CreateOriginatorEnvelope(t, 1, 1)
+
+	originatorSignature, err := ethcrypto.Sign(
+		utils.HashOriginatorSignatureInput(rawOriginatorEnv.GetUnsignedOriginatorEnvelope()),
+		originatorPrivateKey,
+	)
+	require.NoError(t, err)
+	rawOriginatorEnv.Proof = &envelopesProto.OriginatorEnvelope_OriginatorSignature{
+		OriginatorSignature: &associations.RecoverableEcdsaSignature{
+			Bytes: originatorSignature,
+		},
+	}
+
+	originatorEnv, err := NewOriginatorEnvelope(rawOriginatorEnv)
+	require.NoError(t, err)
+
+	signer, err := originatorEnv.RecoverSigner()
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		ethcrypto.PubkeyToAddress(originatorPrivateKey.PublicKey).Hex(),
+		signer.Hex(),
+	)
+
+	// Envelopes without an originator signature cannot be attributed to a signer
+	rawOriginatorEnv.Proof = nil
+	originatorEnv, err = NewOriginatorEnvelope(rawOriginatorEnv)
+	require.NoError(t, err)
+
+	_, err = originatorEnv.RecoverSigner()
+	require.Error(t, err)
+}
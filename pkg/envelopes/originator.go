@@ -4,6 +4,8 @@ import (
 	"errors"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
 	envelopesProto "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/envelopes"
 	"github.com/xmtp/xmtpd/pkg/topic"
 	"github.com/xmtp/xmtpd/pkg/utils"
@@ -70,3 +72,22 @@ func (o *OriginatorEnvelope) OriginatorTime() time.Time {
 func (o *OriginatorEnvelope) TargetTopic() topic.Topic {
 	return o.UnsignedOriginatorEnvelope.TargetTopic()
 }
+
+// RecoverSigner returns the address of the key that signed the unsigned originator envelope.
+// Envelopes carrying a blockchain proof instead of an originator signature return an error.
+func (o *OriginatorEnvelope) RecoverSigner() (*common.Address, error) {
+	originatorSignature := o.proto.GetOriginatorSignature()
+	if originatorSignature == nil {
+		return nil, errors.New("originator signature is missing")
+	}
+
+	hash := utils.HashOriginatorSignatureInput(o.proto.GetUnsignedOriginatorEnvelope())
+	signer, err := ethcrypto.SigToPub(hash, originatorSignature.GetBytes())
+	if err != nil {
+		return nil, err
+	}
+
+	address := ethcrypto.PubkeyToAddress(*signer)
+
+	return &address, nil
+}
@@ -2,8 +2,6 @@ package fees
 
 import (
 	"context"
-	"errors"
-	"fmt"
 	"time"
 
 	"github.com/xmtp/xmtpd/pkg/currency"
@@ -77,23 +75,13 @@ func (b *BatchFeeCalculator) CalculateCongestionFee(
 
 	congestionUnits := CalculateCongestion(adjusted, int32(rates.TargetRatePerMinute))
 
-	if congestionUnits < 0 || congestionUnits > 100 {
-		return 0, fmt.Errorf(
-			"congestionUnits must be between 0 and 100, got %d",
-			congestionUnits,
-		)
+	result, err := calculateCongestionFee(rates, congestionUnits)
+	if err != nil {
+		return 0, err
 	}
 
 	// Increment count AFTER computing fee (this message hasn't been "committed" yet)
 	b.batchCounts[minute]++
 
-	if congestionUnits == 0 {
-		return 0, nil
-	}
-
-	result := rates.CongestionFee * currency.PicoDollar(congestionUnits)
-	if result/currency.PicoDollar(congestionUnits) != rates.CongestionFee {
-		return 0, errors.New("congestion fee calculation overflow")
-	}
 	return result, nil
 }
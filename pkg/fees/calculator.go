@@ -40,18 +40,12 @@ func (c *FeeCalculator) CalculateBaseFee(
 		return 0, err
 	}
 
-	// Calculate storage fee components separately to check for overflow
-	storageFeePerByte := rates.StorageFee * currency.PicoDollar(messageSize)
-	if storageFeePerByte/currency.PicoDollar(messageSize) != rates.StorageFee {
-		return 0, errors.New("storage fee calculation overflow")
-	}
-
-	totalStorageFee := storageFeePerByte * currency.PicoDollar(storageDurationDays)
-	if totalStorageFee/currency.PicoDollar(storageDurationDays) != storageFeePerByte {
-		return 0, errors.New("storage fee calculation overflow")
+	storageFee, err := calculateStorageFee(rates, messageSize, storageDurationDays)
+	if err != nil {
+		return 0, err
 	}
 
-	return rates.MessageFee + totalStorageFee, nil
+	return rates.MessageFee + storageFee, nil
 }
 
 func (c *FeeCalculator) CalculateCongestionFee(
@@ -74,6 +68,88 @@ func (c *FeeCalculator) CalculateCongestionFee(
 	}
 
 	congestionUnits := CalculateCongestion(snapshot, int32(rates.TargetRatePerMinute))
+	return calculateCongestionFee(rates, congestionUnits)
+}
+
+// QuoteFee quotes the fees for publishing a message of messageSize bytes, stored for
+// storageDurationDays, to originatorID at messageTime.
+// The congestion fee is the one the originator would charge for the next message.
+func (c *FeeCalculator) QuoteFee(
+	ctx context.Context,
+	querier *queries.Queries,
+	messageTime time.Time,
+	messageSize int64,
+	storageDurationDays uint32,
+	originatorID uint32,
+) (*FeeQuote, error) {
+	if messageSize <= 0 {
+		return nil, fmt.Errorf("messageSize must be greater than 0, got %d", messageSize)
+	}
+	if storageDurationDays <= 0 {
+		return nil, fmt.Errorf(
+			"storageDurationDays must be greater than 0, got %d",
+			storageDurationDays,
+		)
+	}
+
+	window, err := c.ratesFetcher.GetRatesWindow(messageTime)
+	if err != nil {
+		return nil, err
+	}
+
+	storageFee, err := calculateStorageFee(window.Rates, messageSize, storageDurationDays)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := db.Get5MinutesOfCongestion(
+		ctx, querier, int32(originatorID),
+		int32(utils.MinutesSinceEpoch(messageTime)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	congestionUnits := CalculateCongestion(snapshot, int32(window.Rates.TargetRatePerMinute))
+	congestionFee, err := calculateCongestionFee(window.Rates, congestionUnits)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FeeQuote{
+		BaseFee:         window.Rates.MessageFee + storageFee,
+		StorageFee:      storageFee,
+		CongestionFee:   congestionFee,
+		CongestionUnits: congestionUnits,
+		Rates:           window,
+	}, nil
+}
+
+// calculateStorageFee returns the fee for storing messageSize bytes for storageDurationDays.
+func calculateStorageFee(
+	rates *Rates,
+	messageSize int64,
+	storageDurationDays uint32,
+) (currency.PicoDollar, error) {
+	// Calculate storage fee components separately to check for overflow
+	storageFeePerByte := rates.StorageFee * currency.PicoDollar(messageSize)
+	if storageFeePerByte/currency.PicoDollar(messageSize) != rates.StorageFee {
+		return 0, errors.New("storage fee calculation overflow")
+	}
+
+	totalStorageFee := storageFeePerByte * currency.PicoDollar(storageDurationDays)
+	if totalStorageFee/currency.PicoDollar(storageDurationDays) != storageFeePerByte {
+		return 0, errors.New("storage fee calculation overflow")
+	}
+
+	return totalStorageFee, nil
+}
+
+// calculateCongestionFee returns the fee for congestionUnits of congestion.
+func calculateCongestionFee(
+	rates *Rates,
+	congestionUnits int32,
+) (currency.PicoDollar, error) {
 	if congestionUnits < 0 || congestionUnits > 100 {
 		return 0, fmt.Errorf(
 			"congestionUnits must be between 0 and 100, got %d",
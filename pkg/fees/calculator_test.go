@@ -101,6 +101,42 @@ func TestCalculateCongestionFee(t *testing.T) {
 	require.Equal(t, currency.PicoDollar(20000), congestionFee)
 }
 
+func TestQuoteFee(t *testing.T) {
+	calculator := setupCalculator()
+	db, _ := testutils.NewRawDB(t, context.Background())
+
+	ctx := context.Background()
+	querier := queries.New(db)
+	originatorID := uint32(testutils.RandomInt32())
+	messageTime := time.Now()
+	messageSize := int64(100)
+	storageDurationDays := uint32(30)
+
+	// Congestion rate is 100 because this is double the max
+	addCongestion(t, querier, originatorID, utils.MinutesSinceEpoch(messageTime), 8)
+
+	quote, err := calculator.QuoteFee(
+		ctx,
+		querier,
+		messageTime,
+		messageSize,
+		storageDurationDays,
+		originatorID,
+	)
+	require.NoError(t, err)
+
+	storageFee := currency.PicoDollar(rateStorageFee * messageSize * int64(storageDurationDays))
+	require.Equal(t, storageFee, quote.StorageFee)
+	require.Equal(t, rateMessageFee+storageFee, quote.BaseFee)
+	require.Equal(t, currency.PicoDollar(20000), quote.CongestionFee)
+	require.Equal(t, int32(100), quote.CongestionUnits)
+	require.Equal(t, currency.PicoDollar(rateMessageFee), quote.Rates.Rates.MessageFee)
+	require.True(t, quote.Rates.ValidTo.IsZero())
+
+	_, err = calculator.QuoteFee(ctx, querier, messageTime, 0, storageDurationDays, originatorID)
+	require.Error(t, err)
+}
+
 func TestCongestionFeeParity_BatchVsSequential(t *testing.T) {
 	calculator := setupCalculator()
 	ctx := context.Background()
@@ -160,33 +160,62 @@ func (c *ContractRatesFetcher) refreshData() error {
 }
 
 func (c *ContractRatesFetcher) GetRates(timestamp time.Time) (*Rates, error) {
+	index, err := c.findRatesIndex(timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.rates[index].rates, nil
+}
+
+// GetRatesWindow returns the rates in effect at timestamp, and when the next rates in the
+// registry supersede them.
+func (c *ContractRatesFetcher) GetRatesWindow(timestamp time.Time) (*RatesWindow, error) {
+	index, err := c.findRatesIndex(timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	window := &RatesWindow{
+		Rates:     c.rates[index].rates,
+		ValidFrom: c.rates[index].startTime,
+	}
+	if index+1 < len(c.rates) {
+		window.ValidTo = c.rates[index+1].startTime
+	}
+
+	return window, nil
+}
+
+// findRatesIndex returns the index of the rates in effect at timestamp.
+func (c *ContractRatesFetcher) findRatesIndex(timestamp time.Time) (int, error) {
 	if time.Since(c.lastRefresh) > maxRefreshInterval {
 		c.logger.Warn(
 			"last rates refresh was too long ago for accurate rates",
 			utils.DurationMsField(time.Since(c.lastRefresh)),
 		)
-		return nil, errors.New("last rates refresh was too long ago")
+		return 0, errors.New("last rates refresh was too long ago")
 	}
 
 	if len(c.rates) == 0 {
-		return nil, errors.New("no rates found")
+		return 0, errors.New("no rates found")
 	}
 
 	// If the timestamp is before the oldest rate, return an error
 	if timestamp.Before(c.rates[0].startTime) {
-		return nil, errors.New("timestamp is before the oldest rate")
+		return 0, errors.New("timestamp is before the oldest rate")
 	}
 
 	// Most messages should using the current rate, so check that before doing a binary search
-	newestRate := c.rates[len(c.rates)-1]
-	if timestamp.After(newestRate.startTime) {
-		return newestRate.rates, nil
+	newestIndex := len(c.rates) - 1
+	if timestamp.After(c.rates[newestIndex].startTime) {
+		return newestIndex, nil
 	}
 
 	return c.findMatchingRate(timestamp), nil
 }
 
-func (c *ContractRatesFetcher) findMatchingRate(timestamp time.Time) *Rates {
+func (c *ContractRatesFetcher) findMatchingRate(timestamp time.Time) int {
 	// Binary search to find the rate with the closest startTime that is before or equal to the provided timestamp
 	left, right := 0, len(c.rates)-1
 
@@ -194,13 +223,13 @@ func (c *ContractRatesFetcher) findMatchingRate(timestamp time.Time) *Rates {
 		mid := left + (right-left)/2
 
 		if c.rates[mid].startTime.Equal(timestamp) {
-			return c.rates[mid].rates
+			return mid
 		}
 
 		if c.rates[mid].startTime.Before(timestamp) {
 			// Check if this is the closest rate before the timestamp
 			if mid == len(c.rates)-1 || c.rates[mid+1].startTime.After(timestamp) {
-				return c.rates[mid].rates
+				return mid
 			}
 
 			left = mid + 1
@@ -210,7 +239,7 @@ func (c *ContractRatesFetcher) findMatchingRate(timestamp time.Time) *Rates {
 	}
 
 	// Fallback to the first rate if no exact or closest match is found
-	return c.rates[0].rates
+	return 0
 }
 
 func (c *ContractRatesFetcher) refreshLoop() {
@@ -237,6 +266,7 @@ func transformRates(rates []rateregistry.IRateRegistryRates) []*indexedRates {
 				StorageFee:          currency.PicoDollar(rate.StorageFee),
 				CongestionFee:       currency.PicoDollar(rate.CongestionFee),
 				TargetRatePerMinute: rate.TargetRatePerMinute,
+				StartTime:           rate.StartTime,
 			},
 		}
 	}
@@ -166,6 +166,38 @@ func TestGetRatesBeforeFirstRate(t *testing.T) {
 	require.Nil(t, rates)
 }
 
+func TestGetRatesWindow(t *testing.T) {
+	fetcher, mockContract := buildFetcher(t)
+
+	mockContract.EXPECT().
+		GetRatesCount(mock.Anything).
+		Return(big.NewInt(2), nil)
+
+	mockContract.EXPECT().
+		GetRates(mock.Anything, big.NewInt(0), mock.Anything).
+		Return([]rateregistry.IRateRegistryRates{
+			buildRates(100, 100),
+			buildRates(200, 200),
+		}, nil)
+
+	require.NoError(t, fetcher.refreshData())
+
+	// Superseded rates are valid until the next rates start
+	window, err := fetcher.GetRatesWindow(time.Unix(150, 0))
+	require.NoError(t, err)
+	require.Equal(t, currency.PicoDollar(100), window.Rates.MessageFee)
+	require.Equal(t, uint64(100), window.Rates.StartTime)
+	require.Equal(t, time.Unix(100, 0), window.ValidFrom)
+	require.Equal(t, time.Unix(200, 0), window.ValidTo)
+
+	// The newest rates are valid until further notice
+	window, err = fetcher.GetRatesWindow(time.Unix(250, 0))
+	require.NoError(t, err)
+	require.Equal(t, currency.PicoDollar(200), window.Rates.MessageFee)
+	require.Equal(t, time.Unix(200, 0), window.ValidFrom)
+	require.True(t, window.ValidTo.IsZero())
+}
+
 func TestGetRatesUninitialized(t *testing.T) {
 	fetcher, _ := buildFetcher(t)
 
@@ -15,3 +15,10 @@ func NewFixedRatesFetcher(rates *Rates) *FixedRatesFetcher {
 func (f *FixedRatesFetcher) GetRates(_messageTime time.Time) (*Rates, error) {
 	return f.rates, nil
 }
+
+func (f *FixedRatesFetcher) GetRatesWindow(_messageTime time.Time) (*RatesWindow, error) {
+	return &RatesWindow{
+		Rates:     f.rates,
+		ValidFrom: time.Unix(int64(f.rates.StartTime), 0),
+	}, nil
+}
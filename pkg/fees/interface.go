@@ -18,10 +18,27 @@ type Rates struct {
 	StartTime           uint64              // Unix timestamp when these rates become active
 }
 
+// RatesWindow contains the rates in effect at a given message time, and when they apply.
+type RatesWindow struct {
+	Rates     *Rates
+	ValidFrom time.Time // When the rates became active
+	ValidTo   time.Time // When later rates supersede them. Zero if no later rates are scheduled
+}
+
+// FeeQuote breaks down the fees for publishing a message at the rates in effect.
+type FeeQuote struct {
+	BaseFee         currency.PicoDollar // The message fee plus the storage fee
+	StorageFee      currency.PicoDollar // The storage fee for the message size and retention
+	CongestionFee   currency.PicoDollar // The originator's current congestion fee
+	CongestionUnits int32               // The originator's current congestion, from 0 to 100
+	Rates           *RatesWindow        // The rates the fees were calculated with
+}
+
 // IRatesFetcher is responsible for loading the rates for a given message time.
 // This allows us to roll out new rates over time, and apply them to messages consistently.
 type IRatesFetcher interface {
 	GetRates(messageTime time.Time) (*Rates, error)
+	GetRatesWindow(messageTime time.Time) (*RatesWindow, error)
 }
 
 type IFeeCalculator interface {
@@ -41,4 +58,12 @@ type IFeeCalculator interface {
 		querier *queries.Queries,
 		originatorID uint32,
 	) *BatchFeeCalculator
+	QuoteFee(
+		ctx context.Context,
+		querier *queries.Queries,
+		messageTime time.Time,
+		messageSize int64,
+		storageDurationDays uint32,
+		originatorID uint32,
+	) (*FeeQuote, error)
 }
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/xmtp/xmtpd/pkg/api/payer/outbox"
 	"github.com/xmtp/xmtpd/pkg/api/payer/selectors"
 
 	"connectrpc.com/connect"
@@ -24,9 +25,11 @@ import (
 	"github.com/xmtp/xmtpd/pkg/blockchain/oracle"
 	"github.com/xmtp/xmtpd/pkg/config"
 	"github.com/xmtp/xmtpd/pkg/metrics"
+	"github.com/xmtp/xmtpd/pkg/misbehavior"
 	gateway_apiconnect "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/gateway_api/gateway_apiconnect"
 	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/payer_api/payer_apiconnect"
 	"github.com/xmtp/xmtpd/pkg/registry"
+	"github.com/xmtp/xmtpd/pkg/tracing"
 	"github.com/xmtp/xmtpd/pkg/utils"
 	"go.uber.org/zap"
 )
@@ -172,6 +175,17 @@ func (b *GatewayServiceBuilder) Build() (GatewayService, error) {
 		b.logger = logger.Named(utils.GatewayLoggerName)
 	}
 
+	if b.config.Tracing.Enable {
+		err := tracing.Start(ctx, "", b.logger, tracing.Options{
+			Backend:      b.config.Tracing.Backend,
+			OTLPEndpoint: b.config.Tracing.OTLPEndpoint,
+			OTLPInsecure: b.config.Tracing.OTLPInsecure,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to start tracer")
+		}
+	}
+
 	if b.nonceManager == nil {
 		if err := b.ensureRedis(ctx); err != nil {
 			return nil, errors.Wrap(err, "failed to setup redis")
@@ -183,6 +197,12 @@ func (b *GatewayServiceBuilder) Build() (GatewayService, error) {
 		b.nonceManager = nonceManager
 	}
 
+	if b.config.Payer.OutboxEnable {
+		if err := b.ensureRedis(ctx); err != nil {
+			return nil, errors.Wrap(err, "failed to setup redis")
+		}
+	}
+
 	// Create blockchain publisher if not provided.
 	if b.blockchainPublisher == nil {
 		blockchainPublisher, err := setupBlockchainPublisher(
@@ -250,6 +270,45 @@ func (b *GatewayServiceBuilder) buildGatewayService(
 		return nil, errors.Wrap(err, fmt.Sprintf("failed to listen on port %d", b.config.API.Port))
 	}
 
+	publishOptions := []payer.Option{
+		payer.WithPublishTimeout(b.config.Payer.EnvelopePublishTimeout),
+		payer.WithPublishRetries(b.config.Payer.EnvelopePublishRetries),
+		payer.WithPublishParallelism(b.config.Payer.EnvelopePublishParallelism),
+		payer.WithBlockchainBatching(
+			b.config.Payer.BlockchainBatchWindow,
+			b.config.Payer.BlockchainBatchMaxSize,
+		),
+		payer.WithLivenessTracker(misbehavior.NewLivenessTracker(
+			b.logger,
+			misbehavior.NewLoggingMisbehaviorService(b.logger),
+			misbehavior.LivenessConfig{
+				Window:           b.config.Payer.LivenessWindow,
+				MinRequests:      b.config.Payer.LivenessMinRequests,
+				MaxErrorRate:     b.config.Payer.LivenessMaxErrorRate,
+				MaxTimeouts:      b.config.Payer.LivenessMaxTimeouts,
+				SlowResponseTime: b.config.Payer.LivenessSlowResponseTime,
+				MaxSlowRate:      b.config.Payer.LivenessMaxSlowRate,
+			},
+		)),
+	}
+
+	if b.config.Payer.OutboxEnable {
+		publishOutbox, err := outbox.NewRedisOutbox(
+			b.redisClient,
+			b.config.Redis.KeyPrefix+"outbox:",
+			b.config.Payer.OutboxRetention,
+		)
+		if err != nil {
+			cancel()
+			return nil, errors.Wrap(err, "failed to setup publish outbox")
+		}
+		publishOptions = append(publishOptions, payer.WithOutbox(
+			publishOutbox,
+			b.config.Payer.OutboxReplayInterval,
+			b.config.Payer.OutboxMaxAttempts,
+		))
+	}
+
 	registrationFunc := func(mux *http.ServeMux, interceptors ...connect.Interceptor) (servicePaths []string, err error) {
 		nodeSelector, err := selectors.NewNodeSelector(
 			b.nodeRegistry,
@@ -258,6 +317,17 @@ func (b *GatewayServiceBuilder) buildGatewayService(
 				PreferredNodes: b.config.Payer.NodeSelectorPreferredNodes,
 				CacheExpiry:    b.config.Payer.NodeSelectorCacheExpiry,
 				ConnectTimeout: b.config.Payer.NodeSelectorTimeout,
+				Adaptive: &selectors.AdaptiveNodeSelectorConfig{
+					Window:                 b.config.Payer.NodeSelectorAdaptiveWindow,
+					MaxSamples:             b.config.Payer.NodeSelectorAdaptiveMaxSamples,
+					MinSamples:             b.config.Payer.NodeSelectorAdaptiveMinSamples,
+					MinSuccessRate:         b.config.Payer.NodeSelectorAdaptiveMinSuccessRate,
+					MaxConsecutiveFailures: b.config.Payer.NodeSelectorAdaptiveMaxConsecutiveFailures,
+					BaseEjection:           b.config.Payer.NodeSelectorAdaptiveBaseEjection,
+					MaxEjection:            b.config.Payer.NodeSelectorAdaptiveMaxEjection,
+					LatencyTolerance:       b.config.Payer.NodeSelectorAdaptiveLatencyTolerance,
+					LatencySlack:           b.config.Payer.NodeSelectorAdaptiveLatencySlack,
+				},
 			},
 		)
 		if err != nil {
@@ -273,8 +343,7 @@ func (b *GatewayServiceBuilder) buildGatewayService(
 			clientMetrics,
 			b.config.Contracts.AppChain.MaxBlockchainPayloadSize,
 			nodeSelector,
-			payer.WithPublishTimeout(b.config.Payer.EnvelopePublishTimeout),
-			payer.WithPublishRetries(b.config.Payer.EnvelopePublishRetries),
+			publishOptions...,
 		)
 		if err != nil {
 			return nil, err
@@ -14,6 +14,7 @@ import (
 	"github.com/xmtp/xmtpd/pkg/config"
 	"github.com/xmtp/xmtpd/pkg/metrics"
 	"github.com/xmtp/xmtpd/pkg/registry"
+	"github.com/xmtp/xmtpd/pkg/tracing"
 	"go.uber.org/zap"
 )
 
@@ -59,6 +60,10 @@ func (s *gatewayServiceImpl) Shutdown(timeout time.Duration) error {
 		s.apiServer.Close(timeout)
 	}
 
+	if s.config.Tracing.Enable {
+		tracing.Stop()
+	}
+
 	s.logger.Info("gateway service stopped")
 
 	return nil
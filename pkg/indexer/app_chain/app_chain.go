@@ -46,6 +46,7 @@ type AppChain struct {
 	groupMessageBroadcaster   *contracts.GroupMessageBroadcaster
 	identityUpdateBroadcaster *contracts.IdentityUpdateBroadcaster
 	chainID                   int64
+	snapshotValidation        bool
 }
 
 func NewAppChain(
@@ -54,6 +55,7 @@ func NewAppChain(
 	cfg config.AppChainOptions,
 	db *db.Handler,
 	validationService mlsvalidate.MLSValidationService,
+	snapshotValidation bool,
 ) (*AppChain, error) {
 	ctxwc, cancel := context.WithCancel(ctxwc)
 
@@ -161,6 +163,7 @@ func NewAppChain(
 		chainID:                   cfg.ChainID,
 		groupMessageBroadcaster:   groupMessageBroadcaster,
 		identityUpdateBroadcaster: identityUpdateBroadcaster,
+		snapshotValidation:        snapshotValidation,
 	}, nil
 }
 
@@ -194,6 +197,20 @@ func (a *AppChain) Start() error {
 			)
 		})
 
+	// Stored association states are only read by snapshot validation.
+	if a.snapshotValidation {
+		tracing.GoPanicWrap(
+			a.ctx,
+			&a.wg,
+			"indexer-association-state-backfill",
+			func(ctx context.Context) {
+				err := a.identityUpdateBroadcaster.BackfillAssociationStates(ctx)
+				if err != nil && ctx.Err() == nil {
+					a.logger.Error("failed to backfill association states", zap.Error(err))
+				}
+			})
+	}
+
 	return nil
 }
 
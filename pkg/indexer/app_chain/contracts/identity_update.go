@@ -23,6 +23,7 @@ type IdentityUpdateBroadcaster struct {
 	address common.Address
 	topics  []common.Hash
 	logger  *zap.Logger
+	storer  *IdentityUpdateStorer
 	c.IBlockTracker
 	c.IReorgHandler
 	c.ILogStorer
@@ -72,6 +73,7 @@ func NewIdentityUpdateBroadcaster(
 		address:       address,
 		topics:        []common.Hash{topics},
 		logger:        logger,
+		storer:        identityUpdateStorer,
 		IBlockTracker: identityUpdatesTracker,
 		IReorgHandler: reorgHandler,
 		ILogStorer:    identityUpdateStorer,
@@ -90,6 +92,12 @@ func (iu *IdentityUpdateBroadcaster) Logger() *zap.Logger {
 	return iu.logger
 }
 
+// BackfillAssociationStates stores the association state of inboxes indexed before
+// association states were stored.
+func (iu *IdentityUpdateBroadcaster) BackfillAssociationStates(ctx context.Context) error {
+	return iu.storer.BackfillAssociationStates(ctx)
+}
+
 func identityUpdateBroadcasterContract(
 	address common.Address,
 	client *ethclient.Client,
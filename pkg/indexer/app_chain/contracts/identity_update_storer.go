@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"math"
 	"strings"
 	"time"
@@ -54,6 +55,16 @@ const (
 	ErrValidateIdentityUpdate = "validate identity update failed"
 	ErrInsertAddressLog       = "insert address log failed"
 	ErrRevokeAddressFromLog   = "revoke address from log failed"
+	ErrStoreAssociationState  = "store association state failed"
+)
+
+const (
+	// identityUpdateHistoryPageSize is the number of identity updates read per query when
+	// replaying the history of an inbox.
+	identityUpdateHistoryPageSize = 256
+	// associationStateBackfillPageSize is the number of inboxes without an association state
+	// read per query.
+	associationStateBackfillPageSize = 100
 )
 
 var associationErrorPatterns = []string{
@@ -76,6 +87,12 @@ var associationErrorPatterns = []string{
 	associationErrorConvert,
 }
 
+// identifierKinds are the kinds of identifiers recorded in the address log.
+var identifierKinds = []associations.IdentifierKind{
+	associations.IdentifierKind_IDENTIFIER_KIND_ETHEREUM,
+	associations.IdentifierKind_IDENTIFIER_KIND_PASSKEY,
+}
+
 type IdentityUpdateStorer struct {
 	contract          *iu.IdentityUpdateBroadcaster
 	db                *sql.DB
@@ -193,10 +210,10 @@ func (s *IdentityUpdateStorer) StoreLog(
 		}
 
 		var (
-			inboxID     = utils.HexEncode(msgSent.InboxId[:])
-			sequenceID  = int64(msgSent.SequenceId)
-			insertBatch = make([]string, 0)
-			revokeBatch = make([]string, 0)
+			inboxID       = utils.HexEncode(msgSent.InboxId[:])
+			sequenceID    = int64(msgSent.SequenceId)
+			insertBatches = make(map[associations.IdentifierKind][]string)
+			revokeBatches = make(map[associations.IdentifierKind][]string)
 		)
 
 		for _, newMember := range associationState.StateDiff.GetNewMembers() {
@@ -204,8 +221,8 @@ func (s *IdentityUpdateStorer) StoreLog(
 				s.logger.Debug("new member", utils.BodyField(newMember))
 			}
 
-			if address, ok := newMember.GetKind().(*associations.MemberIdentifier_EthereumAddress); ok {
-				insertBatch = append(insertBatch, address.EthereumAddress)
+			if identifier, kind, ok := memberIdentifier(newMember); ok {
+				insertBatches[kind] = append(insertBatches[kind], identifier)
 			}
 		}
 
@@ -214,36 +231,57 @@ func (s *IdentityUpdateStorer) StoreLog(
 				s.logger.Debug("removed member", utils.BodyField(removedMember))
 			}
 
-			if address, ok := removedMember.GetKind().(*associations.MemberIdentifier_EthereumAddress); ok {
-				revokeBatch = append(revokeBatch, address.EthereumAddress)
+			if identifier, kind, ok := memberIdentifier(removedMember); ok {
+				revokeBatches[kind] = append(revokeBatches[kind], identifier)
 			}
 		}
 
-		if len(insertBatch) > 0 {
-			_, err := querier.InsertAddressLogsBatch(ctx, queries.InsertAddressLogsBatchParams{
-				Addresses:             insertBatch,
-				InboxID:               inboxID,
-				AssociationSequenceID: sequenceID,
-			})
-			if err != nil {
-				return re.NewRecoverableError(ErrInsertAddressLog, err)
+		for _, kind := range identifierKinds {
+			if batch := insertBatches[kind]; len(batch) > 0 {
+				_, err := querier.InsertAddressLogsBatch(ctx, queries.InsertAddressLogsBatchParams{
+					Addresses:             batch,
+					IdentifierKind:        int16(kind),
+					InboxID:               inboxID,
+					AssociationSequenceID: sequenceID,
+				})
+				if err != nil {
+					return re.NewRecoverableError(ErrInsertAddressLog, err)
+				}
 			}
-		}
 
-		if len(revokeBatch) > 0 {
-			_, err := querier.RevokeAddressFromLogBatch(
-				ctx,
-				queries.RevokeAddressFromLogBatchParams{
-					Addresses:            revokeBatch,
-					InboxID:              inboxID,
-					RevocationSequenceID: sequenceID,
-				},
-			)
-			if err != nil {
-				return re.NewRecoverableError(ErrRevokeAddressFromLog, err)
+			if batch := revokeBatches[kind]; len(batch) > 0 {
+				_, err := querier.RevokeAddressFromLogBatch(
+					ctx,
+					queries.RevokeAddressFromLogBatchParams{
+						Addresses:            batch,
+						IdentifierKind:       int16(kind),
+						InboxID:              inboxID,
+						RevocationSequenceID: sequenceID,
+					},
+				)
+				if err != nil {
+					return re.NewRecoverableError(ErrRevokeAddressFromLog, err)
+				}
 			}
 		}
 
+		associationStateBytes, err := proto.Marshal(associationState.AssociationState)
+		if err != nil {
+			return re.NewNonRecoverableError(ErrStoreAssociationState, err)
+		}
+
+		_, err = querier.UpsertInboxAssociationState(
+			ctx,
+			queries.UpsertInboxAssociationStateParams{
+				InboxID:          inboxID,
+				AssociationState: associationStateBytes,
+				SequenceID:       sequenceID,
+			},
+		)
+		if err != nil {
+			return re.NewRecoverableError(ErrStoreAssociationState, err)
+		}
+
 		originatorEnvelope, err := buildOriginatorEnvelope(
 			constants.IdentityUpdateOriginatorID,
 			msgSent.SequenceId,
@@ -340,28 +378,6 @@ func (s *IdentityUpdateStorer) validateIdentityUpdate(
 	inboxID [32]byte,
 	clientEnvelope *envelopes.ClientEnvelope,
 ) (*mlsvalidate.AssociationStateResult, re.RetryableError) {
-	// Identity updates are exclusively produced by IdentityUpdateOriginatorID,
-	// so passing a single originator is safe — no other originator writes to
-	// identity update topics, and FillMissingOriginators is not needed.
-	gatewayEnvelopes, err := querier.SelectGatewayEnvelopesByTopics(
-		ctx,
-		queries.SelectGatewayEnvelopesByTopicsParams{
-			Topics: []db.Topic{
-				topic.NewTopic(topic.TopicKindIdentityUpdatesV1, inboxID[:]).Bytes(),
-			},
-			RowLimit:          256,
-			CursorNodeIds:     []int32{constants.IdentityUpdateOriginatorID},
-			CursorSequenceIds: []int64{0},
-		},
-	)
-	// No rows returned means this is a new identity.
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		return nil, re.NewRecoverableError(
-			"could not retrieve envelopes to validate identity update",
-			err,
-		)
-	}
-
 	identityUpdate, ok := clientEnvelope.Payload().(*envelopesProto.ClientEnvelope_IdentityUpdate)
 	if !ok {
 		return nil, re.NewNonRecoverableError(
@@ -370,9 +386,25 @@ func (s *IdentityUpdateStorer) validateIdentityUpdate(
 		)
 	}
 
-	result, err := s.validationService.GetAssociationStateFromEnvelopes(
+	// Inboxes last updated before snapshots were stored have none until they are backfilled.
+	var previousState *associations.AssociationState
+	snapshot, err := querier.GetInboxAssociationState(ctx, utils.HexEncode(inboxID[:]))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return nil, re.NewRecoverableError("could not get association state snapshot", err)
+	default:
+		previousState = &associations.AssociationState{}
+		if err := proto.Unmarshal(snapshot.AssociationState, previousState); err != nil {
+			return nil, re.NewNonRecoverableError("could not unmarshal association state", err)
+		}
+	}
+
+	result, err := s.getAssociationState(
 		ctx,
-		gatewayEnvelopes,
+		querier,
+		inboxID,
+		previousState,
 		identityUpdate.IdentityUpdate,
 	)
 	if err != nil {
@@ -389,9 +421,267 @@ func (s *IdentityUpdateStorer) validateIdentityUpdate(
 		)
 	}
 
+	if previousState == nil {
+		// Inboxes without a snapshot were last updated before passkeys were recorded in the
+		// address log, so all of their passkeys are recorded, not only the new ones.
+		result.StateDiff = withPasskeyMembers(result.StateDiff, result.AssociationState)
+		return result, nil
+	}
+
+	// Diff the new state against the stored snapshot of the inbox, so the address log always
+	// moves between persisted states.
+	stateDiff, err := mlsvalidate.DiffAssociationStates(previousState, result.AssociationState)
+	if err != nil {
+		return nil, re.NewNonRecoverableError("could not diff association states", err)
+	}
+	result.StateDiff = stateDiff
+
 	return result, nil
 }
 
+// getAssociationState applies update to the association state of an inbox. The stored
+// snapshot of the inbox is used when the validation service accepts it, so the cost of
+// validation does not grow with the history of the inbox. Otherwise the history is replayed.
+func (s *IdentityUpdateStorer) getAssociationState(
+	ctx context.Context,
+	querier *queries.Queries,
+	inboxID [32]byte,
+	previousState *associations.AssociationState,
+	update *associations.IdentityUpdate,
+) (*mlsvalidate.AssociationStateResult, error) {
+	if previousState != nil {
+		result, err := s.validationService.GetAssociationStateFromSnapshot(
+			ctx,
+			previousState,
+			update,
+		)
+		if !errors.Is(err, mlsvalidate.ErrSnapshotValidationUnsupported) {
+			return result, err
+		}
+	}
+
+	history, err := selectIdentityUpdateHistory(ctx, querier, inboxID[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return s.validationService.GetAssociationStateFromEnvelopes(ctx, history, update)
+}
+
+// selectIdentityUpdateHistory returns every stored identity update of an inbox, in sequence
+// ID order.
+func selectIdentityUpdateHistory(
+	ctx context.Context,
+	querier *queries.Queries,
+	inboxID []byte,
+) ([]queries.SelectGatewayEnvelopesByTopicsRow, error) {
+	var (
+		history []queries.SelectGatewayEnvelopesByTopicsRow
+		lastSeq int64
+	)
+	for {
+		// Identity updates are exclusively produced by IdentityUpdateOriginatorID,
+		// so passing a single originator is safe — no other originator writes to
+		// identity update topics, and FillMissingOriginators is not needed.
+		page, err := querier.SelectGatewayEnvelopesByTopics(
+			ctx,
+			queries.SelectGatewayEnvelopesByTopicsParams{
+				Topics: []db.Topic{
+					topic.NewTopic(topic.TopicKindIdentityUpdatesV1, inboxID).Bytes(),
+				},
+				RowLimit:          identityUpdateHistoryPageSize,
+				CursorNodeIds:     []int32{constants.IdentityUpdateOriginatorID},
+				CursorSequenceIds: []int64{lastSeq},
+			},
+		)
+		// No rows returned means this is a new identity.
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("could not retrieve identity updates of inbox: %w", err)
+		}
+
+		history = append(history, page...)
+		if len(page) < identityUpdateHistoryPageSize {
+			return history, nil
+		}
+		lastSeq = page[len(page)-1].OriginatorSequenceID
+	}
+}
+
+// BackfillAssociationStates stores the association state of the inboxes last updated before
+// association states were stored, replaying their history once. Inboxes are read in pages in
+// inbox ID order, and those already backfilled are skipped, so a restarted backfill resumes
+// where it stopped. Inboxes that fail to backfill, or that have no identifier in the address
+// log, are backfilled by their next identity update instead.
+func (s *IdentityUpdateStorer) BackfillAssociationStates(ctx context.Context) error {
+	var (
+		querier = queries.New(s.db)
+		after   = []byte{}
+	)
+
+	for {
+		inboxIDs, err := querier.SelectInboxesWithoutAssociationState(
+			ctx,
+			queries.SelectInboxesWithoutAssociationStateParams{
+				AfterInboxID: after,
+				RowLimit:     associationStateBackfillPageSize,
+			},
+		)
+		if err != nil {
+			return err
+		}
+		if len(inboxIDs) == 0 {
+			return nil
+		}
+
+		for _, inboxID := range inboxIDs {
+			if err := s.backfillAssociationState(ctx, inboxID); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				s.logger.Warn(
+					"failed to backfill association state",
+					utils.InboxIDField(utils.HexEncode(inboxID)),
+					zap.Error(err),
+				)
+			}
+		}
+		after = inboxIDs[len(inboxIDs)-1]
+	}
+}
+
+// backfillAssociationState stores the association state of an inbox without one. It holds
+// the identity update lock, so it never races with the storing of a new update.
+func (s *IdentityUpdateStorer) backfillAssociationState(ctx context.Context, inboxID []byte) error {
+	return db.RunInTx(
+		ctx,
+		s.db,
+		&sql.TxOptions{Isolation: sql.LevelReadCommitted},
+		func(ctx context.Context, querier *queries.Queries) error {
+			err := db.NewAdvisoryLocker().
+				LockIdentityUpdateInsert(ctx, querier, uint32(constants.IdentityUpdateOriginatorID))
+			if err != nil {
+				return err
+			}
+
+			hexInboxID := utils.HexEncode(inboxID)
+			_, err = querier.GetInboxAssociationState(ctx, hexInboxID)
+			if err == nil {
+				return nil
+			}
+			if !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+
+			history, err := selectIdentityUpdateHistory(ctx, querier, inboxID)
+			if err != nil || len(history) == 0 {
+				return err
+			}
+
+			updates, err := mlsvalidate.IdentityUpdatesFromEnvelopes(history)
+			if err != nil {
+				return err
+			}
+
+			result, err := s.validationService.GetAssociationState(ctx, nil, updates)
+			if err != nil {
+				return err
+			}
+
+			associationStateBytes, err := proto.Marshal(result.AssociationState)
+			if err != nil {
+				return err
+			}
+
+			sequenceID := history[len(history)-1].OriginatorSequenceID
+			_, err = querier.UpsertInboxAssociationState(
+				ctx,
+				queries.UpsertInboxAssociationStateParams{
+					InboxID:          hexInboxID,
+					AssociationState: associationStateBytes,
+					SequenceID:       sequenceID,
+				},
+			)
+			if err != nil {
+				return err
+			}
+
+			// Passkeys were not recorded in the address log when the history was indexed.
+			// Their association is recorded at the latest update, as the state does not
+			// keep the update that added them.
+			passkeys := withPasskeyMembers(nil, result.AssociationState).GetNewMembers()
+			if len(passkeys) == 0 {
+				return nil
+			}
+
+			identifiers := make([]string, 0, len(passkeys))
+			for _, member := range passkeys {
+				identifier, _, _ := memberIdentifier(member)
+				identifiers = append(identifiers, identifier)
+			}
+
+			_, err = querier.InsertAddressLogsBatch(ctx, queries.InsertAddressLogsBatchParams{
+				Addresses:             identifiers,
+				IdentifierKind:        int16(associations.IdentifierKind_IDENTIFIER_KIND_PASSKEY),
+				InboxID:               hexInboxID,
+				AssociationSequenceID: sequenceID,
+			})
+			return err
+		},
+	)
+}
+
+// withPasskeyMembers adds the passkey members of state that are missing from the new members
+// of diff, and returns diff.
+func withPasskeyMembers(
+	diff *associations.AssociationStateDiff,
+	state *associations.AssociationState,
+) *associations.AssociationStateDiff {
+	if diff == nil {
+		diff = &associations.AssociationStateDiff{}
+	}
+
+	seen := make(map[string]struct{})
+	for _, member := range diff.GetNewMembers() {
+		if member.GetPasskey() != nil {
+			seen[utils.HexEncode(member.GetPasskey().GetKey())] = struct{}{}
+		}
+	}
+
+	for _, member := range state.GetMembers() {
+		passkey := member.GetKey().GetPasskey()
+		if passkey == nil {
+			continue
+		}
+
+		key := utils.HexEncode(passkey.GetKey())
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		diff.NewMembers = append(diff.NewMembers, member.GetKey())
+	}
+
+	return diff
+}
+
+// memberIdentifier returns the address log identifier of a member and its kind. Passkeys are
+// identified by their hex encoded public key. Installations are not public identifiers.
+func memberIdentifier(
+	member *associations.MemberIdentifier,
+) (string, associations.IdentifierKind, bool) {
+	switch kind := member.GetKind().(type) {
+	case *associations.MemberIdentifier_EthereumAddress:
+		return kind.EthereumAddress, associations.IdentifierKind_IDENTIFIER_KIND_ETHEREUM, true
+	case *associations.MemberIdentifier_Passkey:
+		return utils.HexEncode(kind.Passkey.GetKey()),
+			associations.IdentifierKind_IDENTIFIER_KIND_PASSKEY,
+			true
+	default:
+		return "", associations.IdentifierKind_IDENTIFIER_KIND_UNSPECIFIED, false
+	}
+}
+
 func buildOriginatorEnvelope(
 	originatorID uint32,
 	sequenceID uint64,
@@ -100,7 +100,10 @@ func TestStoreIdentityUpdate(t *testing.T) {
 	require.NoError(t, proto.Unmarshal(firstEnvelope.OriginatorEnvelope, &deserializedEnvelope))
 	require.NotEmpty(t, deserializedEnvelope.GetUnsignedOriginatorEnvelope())
 
-	getInboxIDResult, logsErr := querier.GetAddressLogs(ctx, []string{newAddress})
+	getInboxIDResult, logsErr := querier.GetAddressLogs(ctx, queries.GetAddressLogsParams{
+		Addresses:       []string{newAddress},
+		IdentifierKinds: []int16{int16(associations.IdentifierKind_IDENTIFIER_KIND_ETHEREUM)},
+	})
 	require.NoError(t, logsErr)
 	require.Equal(t, getInboxIDResult[0].InboxID, utils.HexEncode(inboxID[:]))
 
@@ -117,6 +120,10 @@ func TestStoreSequential(t *testing.T) {
 	storer, validationService := buildIdentityUpdateStorer(t)
 	newAddress := "0x12345"
 
+	validationService.EXPECT().
+		GetAssociationStateFromSnapshot(mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, mlsvalidate.ErrSnapshotValidationUnsupported)
+
 	numCalls := 0
 	validationService.EXPECT().
 		GetAssociationStateFromEnvelopes(mock.Anything, mock.Anything, mock.Anything).
@@ -170,3 +177,174 @@ func TestStoreSequential(t *testing.T) {
 		logMessage,
 	))
 }
+
+func TestStoreIdentityUpdateDiffsAgainstSnapshot(t *testing.T) {
+	ctx := context.Background()
+	storer, validationService := buildIdentityUpdateStorer(t)
+
+	memberState := func(address string) *associations.AssociationState {
+		identifier := &associations.MemberIdentifier{
+			Kind: &associations.MemberIdentifier_EthereumAddress{EthereumAddress: address},
+		}
+		return &associations.AssociationState{
+			Members: []*associations.MemberMap{{
+				Key:   identifier,
+				Value: &associations.Member{Identifier: identifier},
+			}},
+		}
+	}
+
+	validationService.EXPECT().
+		GetAssociationStateFromEnvelopes(mock.Anything, mock.Anything, mock.Anything).
+		Return(&mlsvalidate.AssociationStateResult{
+			AssociationState: memberState("0x1"),
+			StateDiff: &associations.AssociationStateDiff{
+				NewMembers: []*associations.MemberIdentifier{
+					memberState("0x1").GetMembers()[0].GetKey(),
+				},
+			},
+		}, nil).
+		Once()
+	validationService.EXPECT().
+		GetAssociationStateFromSnapshot(mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(
+			_ context.Context,
+			snapshot *associations.AssociationState,
+			_ *associations.IdentityUpdate,
+		) (*mlsvalidate.AssociationStateResult, error) {
+			require.True(t, proto.Equal(memberState("0x1"), snapshot))
+
+			return &mlsvalidate.AssociationStateResult{
+				AssociationState: memberState("0x2"),
+				StateDiff:        &associations.AssociationStateDiff{},
+			}, nil
+		}).
+		Once()
+
+	inboxID := testutils.RandomInboxIDBytes()
+	identityUpdate := associations.IdentityUpdate{
+		InboxId: utils.HexEncode(inboxID[:]),
+	}
+
+	for sequenceID := uint64(1); sequenceID <= 2; sequenceID++ {
+		require.NoError(t, storer.StoreLog(ctx, testutils.BuildIdentityUpdateLog(
+			t,
+			inboxID,
+			envelopesTestUtils.CreateIdentityUpdateClientEnvelope(inboxID, &identityUpdate),
+			sequenceID,
+		)))
+	}
+
+	querier := queries.New(storer.db)
+
+	snapshot, err := querier.GetInboxAssociationState(ctx, utils.HexEncode(inboxID[:]))
+	require.NoError(t, err)
+	require.Equal(t, int64(2), snapshot.SequenceID)
+
+	state := &associations.AssociationState{}
+	require.NoError(t, proto.Unmarshal(snapshot.AssociationState, state))
+	require.True(t, proto.Equal(memberState("0x2"), state))
+
+	// The second validation reported no changes, so the removal of the first member and the
+	// addition of the second are derived from the snapshot.
+	identifiers, err := querier.GetIdentifiersForInbox(ctx, queries.GetIdentifiersForInboxParams{
+		InboxID:        utils.HexEncode(inboxID[:]),
+		IncludeRevoked: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, identifiers, 2)
+	require.Equal(t, "0x1", identifiers[0].Address)
+	require.Equal(t, int64(2), identifiers[0].RevocationSequenceID.Int64)
+	require.Equal(t, "0x2", identifiers[1].Address)
+	require.False(t, identifiers[1].RevocationSequenceID.Valid)
+}
+
+func TestBackfillAssociationStates(t *testing.T) {
+	ctx := context.Background()
+	storer, validationService := buildIdentityUpdateStorer(t)
+
+	passkey := []byte{0x01, 0x02}
+	state := &associations.AssociationState{
+		RecoveryIdentifier: "0x1",
+		Members: []*associations.MemberMap{{
+			Key: &associations.MemberIdentifier{
+				Kind: &associations.MemberIdentifier_Passkey{
+					Passkey: &associations.Passkey{Key: passkey},
+				},
+			},
+		}},
+	}
+	validationService.EXPECT().
+		GetAssociationStateFromEnvelopes(mock.Anything, mock.Anything, mock.Anything).
+		Return(&mlsvalidate.AssociationStateResult{
+			AssociationState: state,
+			StateDiff:        &associations.AssociationStateDiff{},
+		}, nil).
+		Once()
+	validationService.EXPECT().
+		GetAssociationState(mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(
+			_ context.Context,
+			oldUpdates []*associations.IdentityUpdate,
+			newUpdates []*associations.IdentityUpdate,
+		) (*mlsvalidate.AssociationStateResult, error) {
+			require.Empty(t, oldUpdates)
+			require.Len(t, newUpdates, 1)
+
+			return &mlsvalidate.AssociationStateResult{AssociationState: state}, nil
+		}).
+		Once()
+
+	inboxID := testutils.RandomInboxIDBytes()
+	identityUpdate := associations.IdentityUpdate{
+		InboxId: utils.HexEncode(inboxID[:]),
+	}
+	require.NoError(t, storer.StoreLog(ctx, testutils.BuildIdentityUpdateLog(
+		t,
+		inboxID,
+		envelopesTestUtils.CreateIdentityUpdateClientEnvelope(inboxID, &identityUpdate),
+		1,
+	)))
+
+	// The first stored state records every passkey of the inbox.
+	querier := queries.New(storer.db)
+	requirePasskeyRecorded := func() {
+		identifiers, err := querier.GetIdentifiersForInbox(ctx, queries.GetIdentifiersForInboxParams{
+			InboxID: utils.HexEncode(inboxID[:]),
+		})
+		require.NoError(t, err)
+		require.Len(t, identifiers, 1)
+		require.Equal(t, utils.HexEncode(passkey), identifiers[0].Address)
+		require.Equal(
+			t,
+			int16(associations.IdentifierKind_IDENTIFIER_KIND_PASSKEY),
+			identifiers[0].IdentifierKind,
+		)
+	}
+	requirePasskeyRecorded()
+
+	// Simulate an inbox indexed before association states and passkeys were stored.
+	_, err := storer.db.ExecContext(
+		ctx,
+		"DELETE FROM inbox_association_states WHERE inbox_id = $1",
+		utils.HexEncode(inboxID[:]),
+	)
+	require.NoError(t, err)
+	_, err = storer.db.ExecContext(
+		ctx,
+		"DELETE FROM address_log WHERE inbox_id = decode($1, 'hex')",
+		utils.HexEncode(inboxID[:]),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, storer.BackfillAssociationStates(ctx))
+
+	snapshot, err := querier.GetInboxAssociationState(ctx, utils.HexEncode(inboxID[:]))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), snapshot.SequenceID)
+
+	stored := &associations.AssociationState{}
+	require.NoError(t, proto.Unmarshal(snapshot.AssociationState, stored))
+	require.True(t, proto.Equal(state, stored))
+	requirePasskeyRecorded()
+}
@@ -33,6 +33,13 @@ type IReorgHandler interface {
 	HandleLog(ctx context.Context, event types.Log) re.RetryableError
 }
 
+// IRemovedLogReverser is implemented by contracts whose IReorgHandler reverses what a removed
+// log stored. Their removed logs are not stored again. The removed logs of other contracts are
+// stored after being handed to their IReorgHandler.
+type IRemovedLogReverser interface {
+	ReversesRemovedLogs() bool
+}
+
 // IContract is a contract that can be indexed.
 type IContract interface {
 	IBlockTracker
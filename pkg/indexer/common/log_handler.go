@@ -56,11 +56,20 @@ func IndexLogs(
 				continue
 			}
 
-			// TODO: Handle reorged event in future PR.
-			// This should be handled by the IReorgHandler, and have a different implementation per contract.
 			// Backfilled logs always have Removed = false. Only subscription logs can be reorged.
+			// A removed log belongs to an orphaned block, so it is handed to the IReorgHandler.
+			// Contracts that reverse removed logs do not store them again.
 			if event.Removed {
-				if err := contract.HandleLog(ctx, event); err != nil {
+				if err := retry(
+					ctx,
+					contract.Logger(),
+					100*time.Millisecond,
+					contract.Address().Hex(),
+					event.BlockNumber,
+					func() re.RetryableError {
+						return contract.HandleLog(ctx, event)
+					},
+				); err != nil {
 					contract.Logger().
 						Error("reorg handling failed",
 							zap.Error(err),
@@ -68,6 +77,11 @@ func IndexLogs(
 							utils.HashField(event.BlockHash.Hex()),
 						)
 				}
+
+				if reverser, ok := contract.(IRemovedLogReverser); ok &&
+					reverser.ReversesRemovedLogs() {
+					continue
+				}
 			}
 
 			now := time.Now()
@@ -150,6 +150,138 @@ func TestIndexLogsRetryableError(t *testing.T) {
 	contract.AssertNumberOfCalls(t, "StoreLog", 2)
 }
 
+// reversingContract is a contract whose reorg handler reverses removed logs.
+type reversingContract struct {
+	*indexerMocks.MockIContract
+}
+
+func (reversingContract) ReversesRemovedLogs() bool {
+	return true
+}
+
+func TestIndexLogsRemovedLog(t *testing.T) {
+	cfg := setup(t)
+	defer func() {
+		cfg.cancel()
+		close(cfg.eventChannel)
+	}()
+
+	removed := cfg.event
+	removed.Removed = true
+
+	var wg sync.WaitGroup
+	// Expecting HandleLog for the removed log, then StoreLog and UpdateLatestBlock for both logs
+	wg.Add(5)
+
+	contract := indexerMocks.NewMockIContract(t)
+
+	contract.EXPECT().
+		Logger().
+		Return(zap.NewNop())
+
+	contract.EXPECT().
+		Address().
+		Return(common.HexToAddress("0x123"))
+
+	contract.EXPECT().
+		HandleLog(mock.Anything, removed).
+		Run(func(ctx context.Context, log types.Log) {
+			wg.Done()
+		}).
+		Return(nil).
+		Once()
+
+	// Contracts that do not reverse removed logs still store them.
+	contract.EXPECT().
+		StoreLog(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, log types.Log) {
+			wg.Done()
+		}).
+		Return(nil).
+		Twice()
+
+	contract.EXPECT().
+		UpdateLatestBlock(mock.Anything, cfg.newBlockNumber, cfg.newBlockHash.Bytes()).
+		Run(func(ctx context.Context, blockNum uint64, blockHash []byte) {
+			wg.Done()
+		}).
+		Return(nil).
+		Twice()
+
+	cfg.eventChannel <- removed
+	cfg.eventChannel <- cfg.event
+
+	go c.IndexLogs(
+		cfg.ctx,
+		cfg.eventChannel,
+		contract,
+	)
+
+	waitForWaitGroup(t, &wg, 10*time.Second,
+		"timed out waiting for HandleLog, StoreLog and UpdateLatestBlock")
+}
+
+func TestIndexLogsRemovedLogReversed(t *testing.T) {
+	cfg := setup(t)
+	defer func() {
+		cfg.cancel()
+		close(cfg.eventChannel)
+	}()
+
+	removed := cfg.event
+	removed.Removed = true
+
+	var wg sync.WaitGroup
+	wg.Add(3) // Expecting HandleLog for the removed log, then StoreLog and UpdateLatestBlock
+
+	contract := indexerMocks.NewMockIContract(t)
+
+	contract.EXPECT().
+		Logger().
+		Return(zap.NewNop())
+
+	contract.EXPECT().
+		Address().
+		Return(common.HexToAddress("0x123"))
+
+	contract.EXPECT().
+		HandleLog(mock.Anything, removed).
+		Run(func(ctx context.Context, log types.Log) {
+			wg.Done()
+		}).
+		Return(nil).
+		Once()
+
+	// The removed log must not be stored or advance the block tracker.
+	contract.EXPECT().
+		StoreLog(mock.Anything, cfg.event).
+		Run(func(ctx context.Context, log types.Log) {
+			wg.Done()
+		}).
+		Return(nil).
+		Once()
+
+	contract.EXPECT().
+		UpdateLatestBlock(mock.Anything, cfg.newBlockNumber, cfg.newBlockHash.Bytes()).
+		Run(func(ctx context.Context, blockNum uint64, blockHash []byte) {
+			wg.Done()
+		}).
+		Return(nil).
+		Once()
+
+	cfg.eventChannel <- removed
+	cfg.eventChannel <- cfg.event
+
+	go c.IndexLogs(
+		cfg.ctx,
+		cfg.eventChannel,
+		reversingContract{contract},
+	)
+
+	waitForWaitGroup(t, &wg, 10*time.Second,
+		"timed out waiting for HandleLog, StoreLog and UpdateLatestBlock")
+}
+
 // waitForWaitGroup blocks until wg signals done or the timeout elapses.
 // The helper goroutine is tracked with t.Cleanup so it can never outlive the
 // test function, even if the timeout path is taken.
@@ -18,11 +18,12 @@ import (
 )
 
 type IndexerConfig struct {
-	ctx               context.Context
-	logger            *zap.Logger
-	db                *db.Handler
-	contractsConfig   *config.ContractsOptions
-	validationService mlsvalidate.MLSValidationService
+	ctx                context.Context
+	logger             *zap.Logger
+	db                 *db.Handler
+	contractsConfig    *config.ContractsOptions
+	validationService  mlsvalidate.MLSValidationService
+	snapshotValidation bool
 }
 
 type IndexerOption func(*IndexerConfig)
@@ -57,6 +58,14 @@ func WithValidationService(vs mlsvalidate.MLSValidationService) IndexerOption {
 	}
 }
 
+// WithSnapshotValidation backfills the association state of inboxes indexed before
+// association states were stored, for identity updates to be validated against.
+func WithSnapshotValidation(enabled bool) IndexerOption {
+	return func(cfg *IndexerConfig) {
+		cfg.snapshotValidation = enabled
+	}
+}
+
 type Indexer struct {
 	ctx             context.Context
 	logger          *zap.Logger
@@ -99,6 +108,7 @@ func NewIndexer(opts ...IndexerOption) (*Indexer, error) {
 		cfg.contractsConfig.AppChain,
 		cfg.db,
 		cfg.validationService,
+		cfg.snapshotValidation,
 	)
 	if err != nil {
 		cancel()
@@ -34,7 +34,10 @@ type PayerRegistry struct {
 	c.ILogStorer
 }
 
-var _ c.IContract = &PayerRegistry{}
+var (
+	_ c.IContract           = &PayerRegistry{}
+	_ c.IRemovedLogReverser = &PayerRegistry{}
+)
 
 func NewPayerRegistry(
 	ctx context.Context,
@@ -69,16 +72,18 @@ func NewPayerRegistry(
 	logger = logger.Named(utils.PayerRegistryContractLoggerName).
 		With(utils.ContractAddressField(address.Hex()))
 
+	payerLedger := ledger.NewLedger(logger, db)
+
 	payerRegistryStorer, err := NewPayerRegistryStorer(
 		logger,
 		contract,
-		ledger.NewLedger(logger, db),
+		payerLedger,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	reorgHandler := NewPayerRegistryReorgHandler(logger)
+	reorgHandler := NewPayerRegistryReorgHandler(logger, payerLedger)
 
 	return &PayerRegistry{
 		address:       address,
@@ -102,6 +107,12 @@ func (pr *PayerRegistry) Logger() *zap.Logger {
 	return pr.logger
 }
 
+// ReversesRemovedLogs reports that the ledger events of removed logs are reversed, so removed
+// logs must not be stored again.
+func (pr *PayerRegistry) ReversesRemovedLogs() bool {
+	return true
+}
+
 func payerRegistryContract(
 	address common.Address,
 	client *ethclient.Client,
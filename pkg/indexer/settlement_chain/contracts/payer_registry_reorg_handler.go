@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/xmtp/xmtpd/pkg/ledger"
 	"github.com/xmtp/xmtpd/pkg/utils"
 	re "github.com/xmtp/xmtpd/pkg/utils/retryerrors"
 	"go.uber.org/zap"
@@ -11,16 +12,37 @@ import (
 
 type PayerRegistryReorgHandler struct {
 	logger *zap.Logger
+	ledger ledger.ILedger
 }
 
-func NewPayerRegistryReorgHandler(logger *zap.Logger) *PayerRegistryReorgHandler {
-	return &PayerRegistryReorgHandler{logger: logger.Named(utils.ReorgHandlerLoggerName)}
+func NewPayerRegistryReorgHandler(
+	logger *zap.Logger,
+	payerLedger ledger.ILedger,
+) *PayerRegistryReorgHandler {
+	return &PayerRegistryReorgHandler{
+		logger: logger.Named(utils.ReorgHandlerLoggerName),
+		ledger: payerLedger,
+	}
 }
 
+// HandleLog reverses the ledger event stored for a log whose block was orphaned.
+// The event ID includes the block hash, so if the transaction is included in the
+// canonical chain its log is stored again as a new event.
 func (h *PayerRegistryReorgHandler) HandleLog(
-	_ context.Context,
+	ctx context.Context,
 	event types.Log,
 ) re.RetryableError {
-	h.logger.Info("handling reorged event", utils.BlockNumberField(event.BlockNumber))
+	eventID := ledger.BuildEventID(event)
+
+	h.logger.Info(
+		"handling reorged event",
+		utils.BlockNumberField(event.BlockNumber),
+		utils.EventIDField(eventID.String()),
+	)
+
+	if err := h.ledger.ReverseEvent(ctx, eventID); err != nil {
+		return wrapLedgerError(err, ErrLedgerReverseEvent)
+	}
+
 	return nil
 }
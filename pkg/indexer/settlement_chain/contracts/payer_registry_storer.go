@@ -23,6 +23,7 @@ const (
 	ErrLedgerInitiateWithdrawal    = "error initiating withdrawal from ledger"
 	ErrInvalidEvent                = "invalid event"
 	ErrLedgerSettleUsage           = "error settling usage in ledger"
+	ErrLedgerReverseEvent          = "error reversing reorged event in ledger"
 
 	// WithdrawalFinalized is not handled, as it might be redundant with WithdrawalRequested.
 	payerRegistryDepositEvent             = "Deposit"
@@ -122,6 +123,7 @@ func (s *PayerRegistryStorer) handleDeposit(
 		payerID,
 		amount,
 		eventID,
+		log.BlockNumber,
 	); err != nil {
 		return wrapLedgerError(err, ErrLedgerDeposit)
 	}
@@ -172,6 +174,7 @@ func (s *PayerRegistryStorer) handleWithdrawalRequested(
 		payerID,
 		amount,
 		eventID,
+		log.BlockNumber,
 	); err != nil {
 		return wrapLedgerError(err, ErrLedgerInitiateWithdrawal)
 	}
@@ -217,7 +220,13 @@ func (s *PayerRegistryStorer) handleUsageSettled(
 	}
 	eventID := ledger.BuildEventID(log)
 
-	if err = s.ledger.SettleUsage(ctx, payerID, amount, eventID); err != nil {
+	if err = s.ledger.SettleUsage(
+		ctx,
+		payerID,
+		amount,
+		eventID,
+		log.BlockNumber,
+	); err != nil {
 		return wrapLedgerError(err, ErrLedgerSettleUsage)
 	}
 
@@ -253,7 +262,7 @@ func (s *PayerRegistryStorer) handleWithdrawalCanceled(
 
 	eventID := ledger.BuildEventID(log)
 
-	if err = s.ledger.CancelWithdrawal(ctx, payerID, eventID); err != nil {
+	if err = s.ledger.CancelWithdrawal(ctx, payerID, eventID, log.BlockNumber); err != nil {
 		return wrapLedgerError(err, "error canceling withdrawal")
 	}
 
@@ -23,10 +23,11 @@ import (
 var address = common.HexToAddress("0x1")
 
 type payerRegistryStorerTester struct {
-	ctx    context.Context
-	abi    *abi.ABI
-	storer *PayerRegistryStorer
-	ledger ledger.ILedger
+	ctx          context.Context
+	abi          *abi.ABI
+	storer       *PayerRegistryStorer
+	reorgHandler *PayerRegistryReorgHandler
+	ledger       ledger.ILedger
 }
 
 type testCase struct {
@@ -328,6 +329,55 @@ func TestStoreLogIdempotency(t *testing.T) {
 	runTestCases(t, testCases)
 }
 
+func TestReorgedLogs(t *testing.T) {
+	tester := buildPayerRegistryStorerTester(t)
+
+	depositLog := tester.newDepositLog(t, address, 100)
+	usageLog := tester.newUsageSettledLog(t, address, 25, common.HexToHash("0x1"))
+	usageLog.BlockNumber = 2
+
+	for _, log := range []types.Log{depositLog, usageLog} {
+		require.NoError(t, tester.storer.StoreLog(tester.ctx, log))
+	}
+	require.Equal(t, currency.FromMicrodollars(75), tester.getBalance(t, address))
+
+	// The block containing the settlement is orphaned
+	removedLog := usageLog
+	removedLog.Removed = true
+	require.NoError(t, tester.reorgHandler.HandleLog(tester.ctx, removedLog))
+	require.Equal(t, currency.FromMicrodollars(100), tester.getBalance(t, address))
+
+	// Handling the same removed log again does not reverse the settlement twice
+	require.NoError(t, tester.reorgHandler.HandleLog(tester.ctx, removedLog))
+	require.Equal(t, currency.FromMicrodollars(100), tester.getBalance(t, address))
+
+	// The transaction is included in a block on the canonical chain
+	reincludedLog := usageLog
+	reincludedLog.BlockNumber = 3
+	reincludedLog.BlockHash = testutils.RandomBlockHash()
+	require.NoError(t, tester.storer.StoreLog(tester.ctx, reincludedLog))
+	require.Equal(t, currency.FromMicrodollars(75), tester.getBalance(t, address))
+
+	payerID, err := tester.ledger.FindOrCreatePayer(tester.ctx, address)
+	require.NoError(t, err)
+
+	events, err := tester.ledger.ListEvents(tester.ctx, payerID, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+
+	require.Equal(t, ledger.EventTypeDeposit, events[0].Type)
+	require.Equal(t, uint64(1), events[0].BlockNumber)
+	require.Equal(t, ledger.EventTypeSettlement, events[1].Type)
+	require.Equal(t, ledger.BuildEventID(usageLog), events[1].ID)
+	require.Equal(t, uint64(2), events[1].BlockNumber)
+	require.Equal(t, ledger.EventTypeReorgReversal, events[2].Type)
+	require.Equal(t, ledger.BuildEventID(usageLog), *events[2].ReversedEventID)
+	require.Equal(t, uint64(2), events[2].BlockNumber)
+	require.Equal(t, ledger.EventTypeSettlement, events[3].Type)
+	require.Equal(t, ledger.BuildEventID(reincludedLog), events[3].ID)
+	require.Equal(t, uint64(3), events[3].BlockNumber)
+}
+
 func buildPayerRegistryStorerTester(t *testing.T) *payerRegistryStorerTester {
 	ctx, cancel := context.WithCancel(context.Background())
 	t.Cleanup(cancel)
@@ -361,10 +411,11 @@ func buildPayerRegistryStorerTester(t *testing.T) *payerRegistryStorerTester {
 	require.NoError(t, err)
 
 	return &payerRegistryStorerTester{
-		ctx:    ctx,
-		abi:    abi,
-		storer: storer,
-		ledger: payerLedger,
+		ctx:          ctx,
+		abi:          abi,
+		storer:       storer,
+		reorgHandler: NewPayerRegistryReorgHandler(testutils.NewLog(t), payerLedger),
+		ledger:       payerLedger,
 	}
 }
 
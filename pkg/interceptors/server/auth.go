@@ -69,6 +69,7 @@ func (i *ServerAuthInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryF
 		i.connectLogIncomingAddress(nodeID)
 
 		ctx = context.WithValue(ctx, constants.VerifiedNodeRequestCtxKey{}, true)
+		ctx = context.WithValue(ctx, constants.VerifiedNodeIDCtxKey{}, nodeID)
 
 		return next(ctx, req)
 	}
@@ -114,6 +115,7 @@ func (i *ServerAuthInterceptor) WrapStreamingHandler(
 		i.connectLogIncomingAddress(nodeID)
 
 		ctx = context.WithValue(ctx, constants.VerifiedNodeRequestCtxKey{}, true)
+		ctx = context.WithValue(ctx, constants.VerifiedNodeIDCtxKey{}, nodeID)
 
 		return next(ctx, conn)
 	}
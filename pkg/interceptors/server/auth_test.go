@@ -139,10 +139,13 @@ func TestUnaryInterceptor(t *testing.T) {
 			} else {
 				require.NoError(t, err)
 				isVerified, hasContextValue := handlerCtx.Value(constants.VerifiedNodeRequestCtxKey{}).(bool)
+				_, hasNodeID := handlerCtx.Value(constants.VerifiedNodeIDCtxKey{}).(uint32)
 				if tt.wantVerifiedNode {
 					require.True(t, isVerified)
+					require.True(t, hasNodeID)
 				} else {
 					require.False(t, hasContextValue)
+					require.False(t, hasNodeID)
 				}
 			}
 		})
@@ -237,10 +240,13 @@ func TestStreamInterceptor(t *testing.T) {
 			} else {
 				require.NoError(t, err)
 				isVerified, hasContextValue := handlerCtx.Value(constants.VerifiedNodeRequestCtxKey{}).(bool)
+				_, hasNodeID := handlerCtx.Value(constants.VerifiedNodeIDCtxKey{}).(uint32)
 				if tt.wantVerifiedNode {
 					require.True(t, isVerified)
+					require.True(t, hasNodeID)
 				} else {
 					require.False(t, hasContextValue)
+					require.False(t, hasNodeID)
 				}
 			}
 		})
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+	"go.uber.org/zap"
+
+	"github.com/xmtp/xmtpd/pkg/authn"
+	"github.com/xmtp/xmtpd/pkg/constants"
+)
+
+// OperatorAuthInterceptor verifies the Tier 1 credentials of app operators and
+// marks the request context with the operator ID, so that the rate limiter
+// bills the request to the operator instead of the client IP.
+type OperatorAuthInterceptor struct {
+	verifier *authn.OperatorVerifier
+	logger   *zap.Logger
+}
+
+var _ connect.Interceptor = (*OperatorAuthInterceptor)(nil)
+
+// NewOperatorAuthInterceptor creates a new OperatorAuthInterceptor.
+func NewOperatorAuthInterceptor(
+	verifier *authn.OperatorVerifier,
+	logger *zap.Logger,
+) *OperatorAuthInterceptor {
+	return &OperatorAuthInterceptor{
+		verifier: verifier,
+		logger:   logger,
+	}
+}
+
+func (i *OperatorAuthInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		credential := req.Header().Get(constants.OperatorAuthorizationHeaderName)
+		// Requests without credentials proceed as unauthenticated clients.
+		if credential == "" {
+			return next(ctx, req)
+		}
+
+		ctx, err := i.authenticate(ctx, credential, req.Spec().Procedure)
+		if err != nil {
+			return nil, err
+		}
+
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient is a no-op for server interceptors.
+// It's only implemented to satisfy the connect.Interceptor interface.
+// This method is never called on the server side.
+func (i *OperatorAuthInterceptor) WrapStreamingClient(
+	next connect.StreamingClientFunc,
+) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *OperatorAuthInterceptor) WrapStreamingHandler(
+	next connect.StreamingHandlerFunc,
+) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		credential := conn.RequestHeader().Get(constants.OperatorAuthorizationHeaderName)
+		// Requests without credentials proceed as unauthenticated clients.
+		if credential == "" {
+			return next(ctx, conn)
+		}
+
+		ctx, err := i.authenticate(ctx, credential, conn.Spec().Procedure)
+		if err != nil {
+			return err
+		}
+
+		return next(ctx, conn)
+	}
+}
+
+func (i *OperatorAuthInterceptor) authenticate(
+	ctx context.Context,
+	credential string,
+	procedure string,
+) (context.Context, error) {
+	operatorID, err := i.verifier.Verify(credential)
+	if err != nil {
+		i.logger.Warn(
+			"operator credential verification failed",
+			zap.String("procedure", procedure),
+			zap.Error(err),
+		)
+
+		// Do not expose too much information to the client (e.g. wrapped errors)
+		return ctx, connect.NewError(
+			connect.CodeUnauthenticated,
+			errors.New("invalid operator credential"),
+		)
+	}
+
+	return context.WithValue(ctx, constants.VerifiedOperatorIDCtxKey{}, operatorID), nil
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"net/http"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/authn"
+	"github.com/xmtp/xmtpd/pkg/constants"
+	"github.com/xmtp/xmtpd/pkg/ratelimiter"
+	"go.uber.org/zap/zaptest"
+)
+
+func newTestOperatorAuthInterceptor(t *testing.T) *OperatorAuthInterceptor {
+	hash := sha256.Sum256([]byte("acme-api-key"))
+	verifier, err := authn.NewOperatorVerifier(map[string][]byte{"acme": hash[:]}, "")
+	require.NoError(t, err)
+
+	return NewOperatorAuthInterceptor(verifier, zaptest.NewLogger(t))
+}
+
+func TestOperatorAuthInterceptorUnary(t *testing.T) {
+	interceptor := newTestOperatorAuthInterceptor(t)
+
+	tests := []struct {
+		name         string
+		credential   string
+		wantErr      bool
+		wantOperator string
+	}{
+		{name: "no credential"},
+		{name: "valid api key", credential: "ApiKey acme-api-key", wantOperator: "acme"},
+		{name: "invalid api key", credential: "ApiKey other-key", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &mockConnectRequestAuthInterceptor{
+				header: http.Header{},
+				spec:   connect.Spec{Procedure: "/xmtp.xmtpv4.message_api.QueryApi/QueryEnvelopes"},
+			}
+			if tt.credential != "" {
+				req.header.Set(constants.OperatorAuthorizationHeaderName, tt.credential)
+			}
+
+			var gotCtx context.Context
+			next := func(ctx context.Context, r connect.AnyRequest) (connect.AnyResponse, error) {
+				gotCtx = ctx
+				return nil, nil
+			}
+
+			_, err := interceptor.WrapUnary(next)(context.Background(), req)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+				require.Nil(t, gotCtx)
+				return
+			}
+
+			require.NoError(t, err)
+			operatorID, ok := ratelimiter.OperatorID(gotCtx)
+			require.Equal(t, tt.wantOperator != "", ok)
+			require.Equal(t, tt.wantOperator, operatorID)
+		})
+	}
+}
+
+func TestOperatorAuthInterceptorStream(t *testing.T) {
+	interceptor := newTestOperatorAuthInterceptor(t)
+
+	conn := &mockStreamingConnAuthInterceptor{
+		header: http.Header{},
+		spec:   connect.Spec{Procedure: "/xmtp.xmtpv4.message_api.QueryApi/SubscribeTopics"},
+	}
+	conn.header.Set(constants.OperatorAuthorizationHeaderName, "ApiKey acme-api-key")
+
+	var tier ratelimiter.Tier
+	next := func(ctx context.Context, c connect.StreamingHandlerConn) error {
+		tier = ratelimiter.ClassifyTier(ctx)
+		return nil
+	}
+
+	require.NoError(t, interceptor.WrapStreamingHandler(next)(context.Background(), conn))
+	require.Equal(t, ratelimiter.Tier1, tier)
+
+	conn.header.Set(constants.OperatorAuthorizationHeaderName, "Bearer not-a-jwt")
+	err := interceptor.WrapStreamingHandler(next)(context.Background(), conn)
+	require.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+}
@@ -20,10 +20,12 @@ import (
 type QueryApiMethod string
 
 const (
-	MethodQueryEnvelopes    QueryApiMethod = "QueryEnvelopes"
-	MethodSubscribeTopics   QueryApiMethod = "SubscribeTopics"
-	MethodGetInboxIds       QueryApiMethod = "GetInboxIds"
-	MethodGetNewestEnvelope QueryApiMethod = "GetNewestEnvelope"
+	MethodQueryEnvelopes           QueryApiMethod = "QueryEnvelopes"
+	MethodSubscribeTopics          QueryApiMethod = "SubscribeTopics"
+	MethodGetInboxIds              QueryApiMethod = "GetInboxIds"
+	MethodGetNewestEnvelope        QueryApiMethod = "GetNewestEnvelope"
+	MethodGetIdentifiersForInbox   QueryApiMethod = "GetIdentifiersForInbox"
+	MethodGetInboxAssociationState QueryApiMethod = "GetInboxAssociationState"
 )
 
 // QueryApiMethodFromProcedure maps a Connect procedure path to a QueryApiMethod.
@@ -43,6 +45,10 @@ func QueryApiMethodFromProcedure(procedure string) (QueryApiMethod, bool) {
 		return MethodGetInboxIds, true
 	case message_apiconnect.QueryApiGetNewestEnvelopeProcedure:
 		return MethodGetNewestEnvelope, true
+	case message_apiconnect.QueryApiGetIdentifiersForInboxProcedure:
+		return MethodGetIdentifiersForInbox, true
+	case message_apiconnect.QueryApiGetInboxAssociationStateProcedure:
+		return MethodGetInboxAssociationState, true
 	}
 	return "", false
 }
@@ -66,14 +72,19 @@ func ComputeCost(method QueryApiMethod, req any) uint64 {
 }
 
 // RateLimitInterceptor enforces rate limits on QueryApi requests.
-// It uses two separate RateLimiter instances:
-//   - queryLimiter: per-IP, per-minute/per-hour buckets for all unary QueryApi methods.
-//   - opensLimiter: per-IP, opens-per-minute sub-limit for SubscribeTopics streaming opens.
+// It uses two RateLimiter instances per limited tier:
+//   - queryLimiter: per-minute/per-hour buckets for all unary QueryApi methods.
+//   - opensLimiter: opens-per-minute sub-limit for SubscribeTopics streaming opens.
+//
+// Tier 2 buckets are keyed by client IP, Tier 1 buckets by operator ID. When no
+// Tier 1 limiters are configured, Tier 1 requests are limited as Tier 2.
 type RateLimitInterceptor struct {
-	logger       *zap.Logger
-	queryLimiter ratelimiter.RateLimiter
-	opensLimiter ratelimiter.RateLimiter
-	trustedCIDRs []*net.IPNet
+	logger            *zap.Logger
+	queryLimiter      ratelimiter.RateLimiter
+	opensLimiter      ratelimiter.RateLimiter
+	tier1QueryLimiter ratelimiter.RateLimiter
+	tier1OpensLimiter ratelimiter.RateLimiter
+	trustedCIDRs      []*net.IPNet
 }
 
 var _ connect.Interceptor = (*RateLimitInterceptor)(nil)
@@ -83,16 +94,36 @@ func NewRateLimitInterceptor(
 	logger *zap.Logger,
 	queryLimiter ratelimiter.RateLimiter,
 	opensLimiter ratelimiter.RateLimiter,
+	tier1QueryLimiter ratelimiter.RateLimiter,
+	tier1OpensLimiter ratelimiter.RateLimiter,
 	trustedCIDRs []*net.IPNet,
 ) *RateLimitInterceptor {
 	return &RateLimitInterceptor{
-		logger:       logger.Named("xmtpd.rate-limiter"),
-		queryLimiter: queryLimiter,
-		opensLimiter: opensLimiter,
-		trustedCIDRs: trustedCIDRs,
+		logger:            logger.Named("xmtpd.rate-limiter"),
+		queryLimiter:      queryLimiter,
+		opensLimiter:      opensLimiter,
+		tier1QueryLimiter: tier1QueryLimiter,
+		tier1OpensLimiter: tier1OpensLimiter,
+		trustedCIDRs:      trustedCIDRs,
 	}
 }
 
+// limiterFor returns the query and opens limiters for a limited request, along
+// with the tier it is billed as and its bucket subject.
+func (i *RateLimitInterceptor) limiterFor(
+	ctx context.Context,
+	peerAddr string,
+	forwardedFor string,
+) (tier ratelimiter.Tier, query, opens ratelimiter.RateLimiter, subject string) {
+	operatorID, ok := ratelimiter.OperatorID(ctx)
+	if ok && i.tier1QueryLimiter != nil && i.tier1OpensLimiter != nil {
+		return ratelimiter.Tier1, i.tier1QueryLimiter, i.tier1OpensLimiter, operatorID
+	}
+
+	subject = clientip.Extract(peerAddr, forwardedFor, i.trustedCIDRs)
+	return ratelimiter.Tier2, i.queryLimiter, i.opensLimiter, subject
+}
+
 // WrapUnary applies rate limiting to unary QueryApi requests.
 func (i *RateLimitInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
 	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
@@ -103,20 +134,20 @@ func (i *RateLimitInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFu
 
 		if ratelimiter.ClassifyTier(ctx) == ratelimiter.Tier0 {
 			ratelimiter.DecisionsTotal.WithLabelValues(
-				"QueryApi", string(method), "tier0", "bypassed",
+				"QueryApi", string(method), ratelimiter.Tier0.String(), "bypassed",
 			).Inc()
 			return next(ctx, req)
 		}
 
-		subject := clientip.Extract(
+		tier, queryLimiter, _, subject := i.limiterFor(
+			ctx,
 			req.Peer().Addr,
 			req.Header().Get("X-Forwarded-For"),
-			i.trustedCIDRs,
 		)
 
 		cost := ComputeCost(method, req.Any())
 
-		result, err := i.queryLimiter.Allow(ctx, subject, cost)
+		result, err := queryLimiter.Allow(ctx, subject, cost)
 		if err != nil {
 			i.logger.Warn("rate limiter error, allowing request",
 				zap.String("procedure", req.Spec().Procedure),
@@ -124,14 +155,14 @@ func (i *RateLimitInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFu
 				zap.Error(err),
 			)
 			ratelimiter.DecisionsTotal.WithLabelValues(
-				"QueryApi", string(method), "tier2", "failed_open",
+				"QueryApi", string(method), tier.String(), "failed_open",
 			).Inc()
 			return next(ctx, req)
 		}
 
 		if !result.Allowed {
 			ratelimiter.DecisionsTotal.WithLabelValues(
-				"QueryApi", string(method), "tier2", "denied",
+				"QueryApi", string(method), tier.String(), "denied",
 			).Inc()
 			return nil, connect.NewError(
 				connect.CodeResourceExhausted,
@@ -140,7 +171,7 @@ func (i *RateLimitInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFu
 		}
 
 		ratelimiter.DecisionsTotal.WithLabelValues(
-			"QueryApi", string(method), "tier2", "allowed",
+			"QueryApi", string(method), tier.String(), "allowed",
 		).Inc()
 		return next(ctx, req)
 	}
@@ -167,7 +198,7 @@ func (i *RateLimitInterceptor) WrapStreamingHandler(
 
 		if ratelimiter.ClassifyTier(ctx) == ratelimiter.Tier0 {
 			ratelimiter.DecisionsTotal.WithLabelValues(
-				"QueryApi", string(method), "tier0", "bypassed",
+				"QueryApi", string(method), ratelimiter.Tier0.String(), "bypassed",
 			).Inc()
 			return next(ctx, conn)
 		}
@@ -176,14 +207,14 @@ func (i *RateLimitInterceptor) WrapStreamingHandler(
 			return next(ctx, conn)
 		}
 
-		clientIP := clientip.Extract(
+		tier, _, opensLimiter, subject := i.limiterFor(
+			ctx,
 			conn.Peer().Addr,
 			conn.RequestHeader().Get("X-Forwarded-For"),
-			i.trustedCIDRs,
 		)
-		opensSubject := clientIP + ":opens"
+		opensSubject := subject + ":opens"
 
-		result, err := i.opensLimiter.Allow(ctx, opensSubject, 1)
+		result, err := opensLimiter.Allow(ctx, opensSubject, 1)
 		if err != nil {
 			i.logger.Warn("subscribe opens rate limiter error, allowing request",
 				zap.String("procedure", conn.Spec().Procedure),
@@ -191,14 +222,14 @@ func (i *RateLimitInterceptor) WrapStreamingHandler(
 				zap.Error(err),
 			)
 			ratelimiter.DecisionsTotal.WithLabelValues(
-				"QueryApi", "SubscribeTopics", "tier2", "failed_open",
+				"QueryApi", "SubscribeTopics", tier.String(), "failed_open",
 			).Inc()
 			return next(ctx, conn)
 		}
 
 		if !result.Allowed {
 			ratelimiter.DecisionsTotal.WithLabelValues(
-				"QueryApi", "SubscribeTopics", "tier2", "denied",
+				"QueryApi", "SubscribeTopics", tier.String(), "denied",
 			).Inc()
 			return connect.NewError(
 				connect.CodeResourceExhausted,
@@ -207,7 +238,7 @@ func (i *RateLimitInterceptor) WrapStreamingHandler(
 		}
 
 		ratelimiter.DecisionsTotal.WithLabelValues(
-			"QueryApi", "SubscribeTopics", "tier2", "allowed",
+			"QueryApi", "SubscribeTopics", tier.String(), "allowed",
 		).Inc()
 		return next(ctx, conn)
 	}
@@ -58,6 +58,8 @@ func TestRateLimitIntegration_Tier2QueryDenyAfterBudgetExhausted(t *testing.T) {
 		queryLimiter,
 		opensLimiter,
 		nil,
+		nil,
+		nil,
 	)
 
 	calls := 0
@@ -94,6 +96,8 @@ func TestRateLimitIntegration_Tier0Bypass(t *testing.T) {
 		limiter,
 		limiter,
 		nil,
+		nil,
+		nil,
 	)
 
 	calls := 0
@@ -129,6 +133,8 @@ func TestRateLimitIntegration_SubscribeOpensSubLimit(t *testing.T) {
 		queryLimiter,
 		opensLimiter,
 		nil,
+		nil,
+		nil,
 	)
 
 	calls := 0
@@ -35,7 +35,7 @@ func (f *fakeLimiter) Allow(
 func newTestInterceptor(t *testing.T, limiter *fakeLimiter) *RateLimitInterceptor {
 	t.Helper()
 	logger := zaptest.NewLogger(t)
-	return NewRateLimitInterceptor(logger, limiter, limiter, nil)
+	return NewRateLimitInterceptor(logger, limiter, limiter, nil, nil, nil)
 }
 
 // mockConnectRequest satisfies connect.AnyRequest for testing.
@@ -97,6 +97,18 @@ func TestQueryApiMethod_FromProcedure(t *testing.T) {
 			wantOk:     true,
 			wantMethod: MethodGetNewestEnvelope,
 		},
+		{
+			name:       "GetIdentifiersForInbox",
+			procedure:  "/xmtp.xmtpv4.message_api.QueryApi/GetIdentifiersForInbox",
+			wantOk:     true,
+			wantMethod: MethodGetIdentifiersForInbox,
+		},
+		{
+			name:       "GetInboxAssociationState",
+			procedure:  "/xmtp.xmtpv4.message_api.QueryApi/GetInboxAssociationState",
+			wantOk:     true,
+			wantMethod: MethodGetInboxAssociationState,
+		},
 		{
 			name:       "PublishApi is not QueryApi",
 			procedure:  "/xmtp.xmtpv4.message_api.PublishApi/PublishEnvelopes",
@@ -330,3 +342,98 @@ func TestRateLimitInterceptor_Streaming_OpensSubLimit_Denied(t *testing.T) {
 	// Verify the opens subject suffix is used.
 	assert.Equal(t, "1.2.3.4:opens", limiter.lastSubject)
 }
+
+// --- Tier 1 tests ---
+
+func newTier1TestInterceptor(
+	t *testing.T,
+	tier2Limiter *fakeLimiter,
+	tier1Limiter *fakeLimiter,
+) *RateLimitInterceptor {
+	t.Helper()
+	logger := zaptest.NewLogger(t)
+	return NewRateLimitInterceptor(
+		logger,
+		tier2Limiter,
+		tier2Limiter,
+		tier1Limiter,
+		tier1Limiter,
+		nil,
+	)
+}
+
+func TestRateLimitInterceptor_Tier1UsesOperatorBuckets(t *testing.T) {
+	tier2Limiter := &fakeLimiter{result: &ratelimiter.Result{Allowed: false}}
+	tier1Limiter := &fakeLimiter{result: &ratelimiter.Result{Allowed: true}}
+	interceptor := newTier1TestInterceptor(t, tier2Limiter, tier1Limiter)
+
+	req := &mockConnectRequest{
+		header: http.Header{},
+		peer:   connect.Peer{Addr: "1.2.3.4:5678"},
+		spec:   connect.Spec{Procedure: "/xmtp.xmtpv4.message_api.QueryApi/QueryEnvelopes"},
+		body:   &message_api.QueryEnvelopesRequest{},
+	}
+
+	handlerCalled := false
+	next := func(ctx context.Context, r connect.AnyRequest) (connect.AnyResponse, error) {
+		handlerCalled = true
+		return nil, nil
+	}
+
+	ctx := context.WithValue(context.Background(), constants.VerifiedOperatorIDCtxKey{}, "acme")
+
+	_, err := interceptor.WrapUnary(next)(ctx, req)
+
+	require.NoError(t, err)
+	assert.True(t, handlerCalled)
+	assert.Equal(t, "acme", tier1Limiter.lastSubject, "Tier1 should be keyed by operator")
+	assert.Empty(t, tier2Limiter.lastSubject, "Tier1 should not be charged to the IP bucket")
+}
+
+func TestRateLimitInterceptor_Tier1DeniedReturnsResourceExhausted(t *testing.T) {
+	tier2Limiter := &fakeLimiter{result: &ratelimiter.Result{Allowed: true}}
+	tier1Limiter := &fakeLimiter{result: &ratelimiter.Result{Allowed: false}}
+	interceptor := newTier1TestInterceptor(t, tier2Limiter, tier1Limiter)
+
+	conn := &mockStreamingConn{
+		header: http.Header{},
+		peer:   connect.Peer{Addr: "1.2.3.4:5678"},
+		spec:   connect.Spec{Procedure: "/xmtp.xmtpv4.message_api.QueryApi/SubscribeTopics"},
+	}
+
+	next := func(ctx context.Context, c connect.StreamingHandlerConn) error {
+		return nil
+	}
+
+	ctx := context.WithValue(context.Background(), constants.VerifiedOperatorIDCtxKey{}, "acme")
+
+	err := interceptor.WrapStreamingHandler(next)(ctx, conn)
+
+	var connectErr *connect.Error
+	require.ErrorAs(t, err, &connectErr)
+	assert.Equal(t, connect.CodeResourceExhausted, connectErr.Code())
+	assert.Equal(t, "acme:opens", tier1Limiter.lastSubject)
+}
+
+func TestRateLimitInterceptor_Tier1FallsBackToTier2WithoutLimiters(t *testing.T) {
+	limiter := &fakeLimiter{result: &ratelimiter.Result{Allowed: true}}
+	interceptor := newTestInterceptor(t, limiter)
+
+	req := &mockConnectRequest{
+		header: http.Header{},
+		peer:   connect.Peer{Addr: "1.2.3.4:5678"},
+		spec:   connect.Spec{Procedure: "/xmtp.xmtpv4.message_api.QueryApi/GetInboxIds"},
+		body:   &message_api.GetInboxIdsRequest{},
+	}
+
+	next := func(ctx context.Context, r connect.AnyRequest) (connect.AnyResponse, error) {
+		return nil, nil
+	}
+
+	ctx := context.WithValue(context.Background(), constants.VerifiedOperatorIDCtxKey{}, "acme")
+
+	_, err := interceptor.WrapUnary(next)(ctx, req)
+
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3.4", limiter.lastSubject)
+}
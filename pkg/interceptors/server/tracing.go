@@ -2,26 +2,34 @@ package server
 
 import (
 	"context"
+	"net/http"
 
 	"connectrpc.com/connect"
+	"github.com/xmtp/xmtpd/pkg/authn"
+	"github.com/xmtp/xmtpd/pkg/constants"
 	"github.com/xmtp/xmtpd/pkg/tracing"
 	grpcUtils "github.com/xmtp/xmtpd/pkg/utils/grpc"
-	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/ext"
 )
 
-// TracingInterceptor creates DD APM spans for all gRPC and gRPC-Web calls.
+// TracingInterceptor creates APM spans for all gRPC and gRPC-Web calls.
 // Provides automatic instrumentation with meaningful span names for flamegraphs.
-type TracingInterceptor struct{}
+// Spans continue the caller's trace only when the request carries a valid node token, so
+// untrusted clients cannot force sampling or attach spans to traces of their choosing.
+type TracingInterceptor struct {
+	verifier authn.JWTVerifier
+}
 
 var _ connect.Interceptor = (*TracingInterceptor)(nil)
 
 // NewTracingInterceptor creates a new instance of TracingInterceptor.
-func NewTracingInterceptor() *TracingInterceptor {
-	return &TracingInterceptor{}
+// A nil verifier starts a new trace for every request.
+func NewTracingInterceptor(verifier authn.JWTVerifier) *TracingInterceptor {
+	return &TracingInterceptor{verifier: verifier}
 }
 
 func (i *TracingInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
 	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		ctx = i.extract(ctx, req.Header())
 		span, ctx := startRPCSpan(ctx, req.Spec().Procedure, "unary")
 		defer span.Finish()
 
@@ -43,6 +51,7 @@ func (i *TracingInterceptor) WrapStreamingHandler(
 	next connect.StreamingHandlerFunc,
 ) connect.StreamingHandlerFunc {
 	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx = i.extract(ctx, conn.RequestHeader())
 		span, ctx := startRPCSpan(ctx, conn.Spec().Procedure, "stream")
 		defer span.Finish()
 
@@ -53,8 +62,29 @@ func (i *TracingInterceptor) WrapStreamingHandler(
 	}
 }
 
+// extract continues the trace propagated in header when the caller is an authenticated
+// node. The auth interceptor runs after this one, so the token is verified here as well.
+func (i *TracingInterceptor) extract(ctx context.Context, header http.Header) context.Context {
+	if i.verifier == nil {
+		return ctx
+	}
+
+	token := header.Get(constants.NodeAuthorizationHeaderName)
+	if token == "" {
+		return ctx
+	}
+
+	_, closeFn, err := i.verifier.Verify(token)
+	if err != nil {
+		return ctx
+	}
+	closeFn()
+
+	return tracing.Extract(ctx, tracing.HeaderCarrier(header))
+}
+
 // startRPCSpan creates a traced span for an RPC call and sets standard tags
-// for Datadog APM filtering and grouping. The caller must call span.Finish().
+// for APM filtering and grouping. The caller must call span.Finish().
 func startRPCSpan(
 	ctx context.Context,
 	procedure string,
@@ -72,9 +102,9 @@ func startRPCSpan(
 	span, ctx := tracing.StartSpanFromContext(ctx, operationName)
 
 	// Set standard tags for filtering and grouping
-	span.SetTag(ext.SpanType, "web")
-	span.SetTag(ext.RPCSystem, "grpc")
-	tracing.SpanResource(span, method) // Shows nicely in DD UI
+	span.SetTag(tracing.TagSpanType, "web")
+	span.SetTag(tracing.TagRPCSystem, "grpc")
+	tracing.SpanResource(span, method) // Shows nicely in the APM UI
 	tracing.SpanTag(span, "rpc.method", method)
 	tracing.SpanTag(span, "rpc.service", service)
 	tracing.SpanTag(span, "rpc.procedure", procedure)
@@ -86,8 +116,8 @@ func startRPCSpan(
 // tagRPCResult sets error or success status tags on the span.
 func tagRPCResult(span tracing.Span, err error) {
 	if err != nil {
-		span.SetTag(ext.Error, true)
-		span.SetTag(ext.ErrorMsg, err.Error())
+		span.SetTag(tracing.TagError, true)
+		span.SetTag(tracing.TagErrorMsg, err.Error())
 		tracing.SpanTag(span, "rpc.status", connect.CodeOf(err).String())
 	} else {
 		tracing.SpanTag(span, "rpc.status", "OK")
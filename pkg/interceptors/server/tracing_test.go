@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/authn"
+	"github.com/xmtp/xmtpd/pkg/constants"
+	authnMocks "github.com/xmtp/xmtpd/pkg/testutils/mocks/authn"
+	"github.com/xmtp/xmtpd/pkg/tracing"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingInterceptorExtractsOnlyForVerifiedNodes(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(tracing.SetBackendForTesting(tracing.NewOTelBackend(provider)))
+
+	caller, callerCtx := tracing.StartSpanFromContext(context.Background(), "test.caller")
+	caller.Finish()
+	traceHeader := make(tracing.HeaderCarrier)
+	tracing.Inject(callerCtx, traceHeader)
+
+	verifier := authnMocks.NewMockJWTVerifier(t)
+	verifier.EXPECT().
+		Verify("valid").
+		Return(uint32(100), authn.CloseFunc(func() {}), nil)
+	verifier.EXPECT().
+		Verify("invalid").
+		Return(uint32(0), authn.CloseFunc(func() {}), errors.New("invalid token"))
+
+	tests := []struct {
+		name      string
+		verifier  authn.JWTVerifier
+		token     string
+		continues bool
+	}{
+		{name: "verified node", verifier: verifier, token: "valid", continues: true},
+		{name: "invalid token", verifier: verifier, token: "invalid", continues: false},
+		{name: "no token", verifier: verifier, continues: false},
+		{name: "no verifier", token: "valid", continues: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header(traceHeader).Clone()
+			if tt.token != "" {
+				header.Set(constants.NodeAuthorizationHeaderName, tt.token)
+			}
+
+			var remote bool
+			interceptor := NewTracingInterceptor(tt.verifier)
+			handler := interceptor.WrapUnary(
+				func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+					remote = recorder.Started()[len(recorder.Started())-1].Parent().IsRemote()
+					return nil, nil
+				},
+			)
+
+			_, err := handler(context.Background(), &mockConnectRequestAuthInterceptor{
+				header: header,
+				spec:   connect.Spec{Procedure: "/xmtp.xmtpv4.message_api.ReplicationApi/Test"},
+			})
+			require.NoError(t, err)
+			require.Equal(t, tt.continues, remote)
+		})
+	}
+}
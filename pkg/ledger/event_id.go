@@ -10,6 +10,8 @@ import (
 
 type EventID [32]byte
 
+const reversalEventIDPrefix = "reorg-reversal"
+
 func (e EventID) String() string {
 	return hex.EncodeToString(e[:])
 }
@@ -32,3 +34,13 @@ func convertIndexToBytes(index uint) []byte {
 	binary.BigEndian.PutUint32(indexBytes, uint32(index))
 	return indexBytes
 }
+
+// BuildReversalEventID builds the EventID of the reorg reversal of an event. It is
+// deterministic, so that handling the same reorged log twice reverses the event once.
+func BuildReversalEventID(eventID EventID) EventID {
+	inputs := make([]byte, 0, len(reversalEventIDPrefix)+len(eventID))
+	inputs = append(inputs, reversalEventIDPrefix...)
+	inputs = append(inputs, eventID[:]...)
+
+	return sha256.Sum256(inputs)
+}
@@ -4,14 +4,30 @@ type EventType int16
 
 const (
 	// Triggered by a Deposit smart contract event
-	eventTypeDeposit EventType = 0
+	EventTypeDeposit EventType = 0
 	// Triggered by a WithdrawalRequested smart contract event
-	eventTypeWithdrawal EventType = 1
+	EventTypeWithdrawal EventType = 1
 	// Triggered by a UsageSettled smart contract event
-	eventTypeSettlement EventType = 2
+	EventTypeSettlement EventType = 2
 	// Triggered by a WithdrawalCancelled smart contract event
-	eventTypeCanceledWithdrawal EventType = 3
+	EventTypeCanceledWithdrawal EventType = 3
 	// Triggered by our reorg handler
-	//nolint
-	eventTypeReorgReversal EventType = 4
+	EventTypeReorgReversal EventType = 4
 )
+
+func (t EventType) String() string {
+	switch t {
+	case EventTypeDeposit:
+		return "deposit"
+	case EventTypeWithdrawal:
+		return "withdrawal"
+	case EventTypeSettlement:
+		return "settlement"
+	case EventTypeCanceledWithdrawal:
+		return "canceled_withdrawal"
+	case EventTypeReorgReversal:
+		return "reorg_reversal"
+	default:
+		return "unknown"
+	}
+}
@@ -15,6 +15,7 @@ type ILedger interface {
 		payerID int32,
 		amount currency.PicoDollar,
 		eventID EventID,
+		blockNumber uint64,
 	) error
 	// Register a withdrawal event, which immediately reduces the payer's balance
 	InitiateWithdrawal(
@@ -22,17 +23,29 @@ type ILedger interface {
 		payerID int32,
 		amount currency.PicoDollar,
 		eventID EventID,
+		blockNumber uint64,
 	) error
 	// Cancel a previous withdrawal
-	CancelWithdrawal(ctx context.Context, payerID int32, eventID EventID) error
+	CancelWithdrawal(
+		ctx context.Context,
+		payerID int32,
+		eventID EventID,
+		blockNumber uint64,
+	) error
 	// Decrement a payer's balance when usage is settled
 	SettleUsage(
 		ctx context.Context,
 		payerID int32,
 		amount currency.PicoDollar,
 		eventID EventID,
+		blockNumber uint64,
 	) error
+	// Reverse an event whose block was orphaned by a reorg. Reversing an event that was never
+	// stored, or was already reversed, is a no-op
+	ReverseEvent(ctx context.Context, eventID EventID) error
 	// Get the balance for a payer from the settled usage ledger
 	GetBalance(ctx context.Context, payerID int32) (currency.PicoDollar, error)
+	// List a payer's events in the order they were recorded, starting after the since sequence
+	ListEvents(ctx context.Context, payerID int32, since int64, limit int32) ([]Event, error)
 	FindOrCreatePayer(ctx context.Context, payerAddress common.Address) (int32, error)
 }
@@ -6,6 +6,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/xmtp/xmtpd/pkg/currency"
@@ -15,6 +16,21 @@ import (
 	"go.uber.org/zap"
 )
 
+// Event is an entry in a payer's ledger.
+type Event struct {
+	ID EventID
+	// Sequence orders a payer's events. Pass the last one to ListEvents to get the next page.
+	Sequence int64
+	Type     EventType
+	// Amount is negative for events that reduce the balance.
+	Amount currency.PicoDollar
+	// BlockNumber is the settlement chain block the event was emitted in, or 0 if unknown.
+	BlockNumber uint64
+	// ReversedEventID is set on reorg reversals to the event they reverse.
+	ReversedEventID *EventID
+	CreatedAt       time.Time
+}
+
 type Ledger struct {
 	db     *db.Handler
 	logger *zap.Logger
@@ -40,6 +56,7 @@ func (l *Ledger) Deposit(
 	payerID int32,
 	amount currency.PicoDollar,
 	eventID EventID,
+	blockNumber uint64,
 ) error {
 	var err error
 	if err = validateAmount(amount); err != nil {
@@ -49,11 +66,12 @@ func (l *Ledger) Deposit(
 		return err
 	}
 
-	return l.db.WriteQuery().InsertPayerLedgerEvent(ctx, queries.InsertPayerLedgerEventParams{
+	return l.insertEvent(ctx, queries.InsertPayerLedgerEventParams{
 		EventID:           eventID[:],
 		PayerID:           payerID,
 		AmountPicodollars: int64(amount),
-		EventType:         int16(eventTypeDeposit),
+		EventType:         int16(EventTypeDeposit),
+		BlockNumber:       int64(blockNumber),
 	})
 }
 
@@ -62,6 +80,7 @@ func (l *Ledger) InitiateWithdrawal(
 	payerID int32,
 	amount currency.PicoDollar,
 	eventID EventID,
+	blockNumber uint64,
 ) error {
 	var err error
 	if err = validateAmount(amount); err != nil {
@@ -71,18 +90,24 @@ func (l *Ledger) InitiateWithdrawal(
 		return err
 	}
 
-	return l.db.WriteQuery().InsertPayerLedgerEvent(ctx, queries.InsertPayerLedgerEventParams{
+	return l.insertEvent(ctx, queries.InsertPayerLedgerEventParams{
 		EventID:           eventID[:],
 		PayerID:           payerID,
 		AmountPicodollars: int64(amount) * -1,
-		EventType:         int16(eventTypeWithdrawal),
+		EventType:         int16(EventTypeWithdrawal),
+		BlockNumber:       int64(blockNumber),
 	})
 }
 
-func (l *Ledger) CancelWithdrawal(ctx context.Context, payerID int32, eventID EventID) error {
+func (l *Ledger) CancelWithdrawal(
+	ctx context.Context,
+	payerID int32,
+	eventID EventID,
+	blockNumber uint64,
+) error {
 	lastWithdrawal, err := l.db.ReadQuery().GetLastEvent(ctx, queries.GetLastEventParams{
 		PayerID:   payerID,
-		EventType: int16(eventTypeWithdrawal),
+		EventType: int16(EventTypeWithdrawal),
 	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -95,7 +120,7 @@ func (l *Ledger) CancelWithdrawal(ctx context.Context, payerID int32, eventID Ev
 	// The smart contract should protect against this, but we are double checking.
 	lastCancel, err := l.db.ReadQuery().GetLastEvent(ctx, queries.GetLastEventParams{
 		PayerID:   payerID,
-		EventType: int16(eventTypeCanceledWithdrawal),
+		EventType: int16(EventTypeCanceledWithdrawal),
 	})
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return err
@@ -116,11 +141,12 @@ func (l *Ledger) CancelWithdrawal(ctx context.Context, payerID int32, eventID Ev
 		return ErrWithdrawalAlreadyCanceled
 	}
 
-	return l.db.WriteQuery().InsertPayerLedgerEvent(ctx, queries.InsertPayerLedgerEventParams{
+	return l.insertEvent(ctx, queries.InsertPayerLedgerEventParams{
 		EventID:           eventID[:],
 		PayerID:           payerID,
 		AmountPicodollars: int64(lastWithdrawal.AmountPicodollars) * -1,
-		EventType:         int16(eventTypeCanceledWithdrawal),
+		EventType:         int16(EventTypeCanceledWithdrawal),
+		BlockNumber:       int64(blockNumber),
 	})
 }
 
@@ -129,6 +155,7 @@ func (l *Ledger) SettleUsage(
 	payerID int32,
 	amount currency.PicoDollar,
 	eventID EventID,
+	blockNumber uint64,
 ) error {
 	var err error
 	if err = validateAmount(amount); err != nil {
@@ -138,12 +165,120 @@ func (l *Ledger) SettleUsage(
 		return err
 	}
 
-	return l.db.WriteQuery().InsertPayerLedgerEvent(ctx, queries.InsertPayerLedgerEventParams{
+	return l.insertEvent(ctx, queries.InsertPayerLedgerEventParams{
 		EventID:           eventID[:],
 		PayerID:           payerID,
 		AmountPicodollars: int64(amount) * -1,
-		EventType:         int16(eventTypeSettlement),
+		EventType:         int16(EventTypeSettlement),
+		BlockNumber:       int64(blockNumber),
+	})
+}
+
+func (l *Ledger) ReverseEvent(ctx context.Context, eventID EventID) error {
+	if err := validateEventID(eventID); err != nil {
+		return err
+	}
+
+	reversalID := BuildReversalEventID(eventID)
+	var numInserted int64
+	err := l.runInsertTx(ctx, func(ctx context.Context, txQueries *queries.Queries) error {
+		var err error
+		numInserted, err = txQueries.InsertPayerLedgerReversal(
+			ctx,
+			queries.InsertPayerLedgerReversalParams{
+				EventID:         reversalID[:],
+				EventType:       int16(EventTypeReorgReversal),
+				ReversedEventID: eventID[:],
+			},
+		)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if numInserted == 0 {
+		// The event was never stored, or was already reversed.
+		l.logger.Debug("no ledger event to reverse", utils.EventIDField(eventID.String()))
+		return nil
+	}
+
+	l.logger.Info(
+		"reversed reorged ledger event",
+		utils.EventIDField(eventID.String()),
+		zap.String("reversal_event_id", reversalID.String()),
+	)
+
+	return nil
+}
+
+// insertEvent stores a ledger event. Events that are already stored are skipped.
+func (l *Ledger) insertEvent(
+	ctx context.Context,
+	params queries.InsertPayerLedgerEventParams,
+) error {
+	return l.runInsertTx(ctx, func(ctx context.Context, txQueries *queries.Queries) error {
+		return txQueries.InsertPayerLedgerEvent(ctx, params)
+	})
+}
+
+// runInsertTx runs insert in a transaction holding the payer ledger insert lock, so that events
+// commit in the order of their sequence IDs. Otherwise an event could commit after a later one
+// was listed, and ListEvents callers paging by sequence ID would never see it.
+func (l *Ledger) runInsertTx(
+	ctx context.Context,
+	insert func(ctx context.Context, txQueries *queries.Queries) error,
+) error {
+	return db.RunInTx(
+		ctx,
+		l.db.Write(),
+		nil,
+		func(ctx context.Context, txQueries *queries.Queries) error {
+			if err := db.NewAdvisoryLocker().LockPayerLedgerInsert(ctx, txQueries); err != nil {
+				return err
+			}
+			return insert(ctx, txQueries)
+		},
+	)
+}
+
+func (l *Ledger) ListEvents(
+	ctx context.Context,
+	payerID int32,
+	since int64,
+	limit int32,
+) ([]Event, error) {
+	if limit <= 0 {
+		return nil, ErrInvalidLimit
+	}
+
+	rows, err := l.db.ReadQuery().ListPayerLedgerEvents(ctx, queries.ListPayerLedgerEventsParams{
+		PayerID:         payerID,
+		SinceSequenceID: since,
+		RowLimit:        limit,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]Event, 0, len(rows))
+	for _, row := range rows {
+		event := Event{
+			ID:          EventID(row.EventID),
+			Sequence:    row.SequenceID,
+			Type:        EventType(row.EventType),
+			Amount:      currency.PicoDollar(row.AmountPicodollars),
+			BlockNumber: uint64(row.BlockNumber),
+			CreatedAt:   row.CreatedAt,
+		}
+		if row.ReversesEventID != nil {
+			reversedID := EventID(row.ReversesEventID)
+			event.ReversedEventID = &reversedID
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
 }
 
 func (l *Ledger) FindOrCreatePayer(
@@ -81,11 +81,11 @@ func setupWithInitialState(t *testing.T, tc testCase) *testFixture {
 		var err error
 		switch event.eventType {
 		case "deposit":
-			err = f.ledger.Deposit(f.ctx, actualPayerID, event.amount, event.eventID)
+			err = f.ledger.Deposit(f.ctx, actualPayerID, event.amount, event.eventID, 0)
 		case "withdrawal":
-			err = f.ledger.InitiateWithdrawal(f.ctx, actualPayerID, event.amount, event.eventID)
+			err = f.ledger.InitiateWithdrawal(f.ctx, actualPayerID, event.amount, event.eventID, 0)
 		case "settlement":
-			err = f.ledger.SettleUsage(f.ctx, actualPayerID, event.amount, event.eventID)
+			err = f.ledger.SettleUsage(f.ctx, actualPayerID, event.amount, event.eventID, 0)
 		default:
 			t.Fatalf("unknown event type: %s", event.eventType)
 		}
@@ -104,7 +104,7 @@ func TestDuplicateEventPrevention(t *testing.T) {
 			},
 			action: func(f *testFixture) error {
 				// Try to deposit again with same eventID
-				return f.ledger.Deposit(f.ctx, f.payer(1), 1000, generateEventID(100))
+				return f.ledger.Deposit(f.ctx, f.payer(1), 1000, generateEventID(100), 0)
 			},
 			validate: func(t *testing.T, f *testFixture) {
 				// Balance should remain unchanged at 1000
@@ -121,7 +121,7 @@ func TestDuplicateEventPrevention(t *testing.T) {
 			},
 			action: func(f *testFixture) error {
 				// Try to withdraw again with same eventID
-				return f.ledger.InitiateWithdrawal(f.ctx, f.payer(2), 1000, generateEventID(201))
+				return f.ledger.InitiateWithdrawal(f.ctx, f.payer(2), 1000, generateEventID(201), 0)
 			},
 			validate: func(t *testing.T, f *testFixture) {
 				// Balance should remain at 4000 (5000 - 1000)
@@ -138,7 +138,7 @@ func TestDuplicateEventPrevention(t *testing.T) {
 			},
 			action: func(f *testFixture) error {
 				// Try to settle again with same eventID
-				return f.ledger.SettleUsage(f.ctx, f.payer(3), 500, generateEventID(301))
+				return f.ledger.SettleUsage(f.ctx, f.payer(3), 500, generateEventID(301), 0)
 			},
 			validate: func(t *testing.T, f *testFixture) {
 				// Balance should remain at 4500 (5000 - 500)
@@ -155,7 +155,7 @@ func TestDuplicateEventPrevention(t *testing.T) {
 			action: func(f *testFixture) error {
 				// Try to use same eventID for different payer
 				// Use f.payer(5) to create payer 5 on demand
-				return f.ledger.Deposit(f.ctx, f.payer(5), 2000, generateEventID(400))
+				return f.ledger.Deposit(f.ctx, f.payer(5), 2000, generateEventID(400), 0)
 			},
 			validate: func(t *testing.T, f *testFixture) {
 				// First payer should have their balance
@@ -267,7 +267,13 @@ func TestBalanceCalculations(t *testing.T) {
 				{payerID: 14, amount: 5000, eventID: generateEventID(1401), eventType: "deposit"},
 			},
 			action: func(f *testFixture) error {
-				return f.ledger.InitiateWithdrawal(f.ctx, f.payer(14), 1500, generateEventID(1402))
+				return f.ledger.InitiateWithdrawal(
+					f.ctx,
+					f.payer(14),
+					1500,
+					generateEventID(1402),
+					0,
+				)
 			},
 			validate: func(t *testing.T, f *testFixture) {
 				balance, err := f.ledger.GetBalance(f.ctx, f.payer(14))
@@ -281,7 +287,7 @@ func TestBalanceCalculations(t *testing.T) {
 				{payerID: 15, amount: 8000, eventID: generateEventID(1501), eventType: "deposit"},
 			},
 			action: func(f *testFixture) error {
-				return f.ledger.SettleUsage(f.ctx, f.payer(15), 2500, generateEventID(1502))
+				return f.ledger.SettleUsage(f.ctx, f.payer(15), 2500, generateEventID(1502), 0)
 			},
 			validate: func(t *testing.T, f *testFixture) {
 				balance, err := f.ledger.GetBalance(f.ctx, f.payer(15))
@@ -294,7 +300,13 @@ func TestBalanceCalculations(t *testing.T) {
 			initialEvents: []initialEvent{},
 			action: func(f *testFixture) error {
 				// Withdraw without deposit - payer created on demand
-				return f.ledger.InitiateWithdrawal(f.ctx, f.payer(16), 1000, generateEventID(1601))
+				return f.ledger.InitiateWithdrawal(
+					f.ctx,
+					f.payer(16),
+					1000,
+					generateEventID(1601),
+					0,
+				)
 			},
 			validate: func(t *testing.T, f *testFixture) {
 				balance, err := f.ledger.GetBalance(f.ctx, f.payer(16))
@@ -349,42 +361,42 @@ func TestAmountValidations(t *testing.T) {
 		{
 			name: "deposit_zero_amount_rejected",
 			action: func(f *testFixture) error {
-				return f.ledger.Deposit(f.ctx, 20, 0, generateEventID(2001))
+				return f.ledger.Deposit(f.ctx, 20, 0, generateEventID(2001), 0)
 			},
 			expectedError: ledger.ErrInvalidAmount,
 		},
 		{
 			name: "deposit_negative_amount_rejected",
 			action: func(f *testFixture) error {
-				return f.ledger.Deposit(f.ctx, 21, -1000, generateEventID(2101))
+				return f.ledger.Deposit(f.ctx, 21, -1000, generateEventID(2101), 0)
 			},
 			expectedError: ledger.ErrInvalidAmount,
 		},
 		{
 			name: "withdrawal_zero_amount_rejected",
 			action: func(f *testFixture) error {
-				return f.ledger.InitiateWithdrawal(f.ctx, 22, 0, generateEventID(2201))
+				return f.ledger.InitiateWithdrawal(f.ctx, 22, 0, generateEventID(2201), 0)
 			},
 			expectedError: ledger.ErrInvalidAmount,
 		},
 		{
 			name: "withdrawal_negative_amount_rejected",
 			action: func(f *testFixture) error {
-				return f.ledger.InitiateWithdrawal(f.ctx, 23, -500, generateEventID(2301))
+				return f.ledger.InitiateWithdrawal(f.ctx, 23, -500, generateEventID(2301), 0)
 			},
 			expectedError: ledger.ErrInvalidAmount,
 		},
 		{
 			name: "settlement_zero_amount_rejected",
 			action: func(f *testFixture) error {
-				return f.ledger.SettleUsage(f.ctx, 24, 0, generateEventID(2401))
+				return f.ledger.SettleUsage(f.ctx, 24, 0, generateEventID(2401), 0)
 			},
 			expectedError: ledger.ErrInvalidAmount,
 		},
 		{
 			name: "settlement_negative_amount_rejected",
 			action: func(f *testFixture) error {
-				return f.ledger.SettleUsage(f.ctx, 25, -100, generateEventID(2501))
+				return f.ledger.SettleUsage(f.ctx, 25, -100, generateEventID(2501), 0)
 			},
 			expectedError: ledger.ErrInvalidAmount,
 		},
@@ -408,7 +420,7 @@ func TestAmountValidations(t *testing.T) {
 			baseEventID := 2600 + i*3
 
 			// Test deposit
-			err := f.ledger.Deposit(f.ctx, payerID, amount, generateEventID(baseEventID))
+			err := f.ledger.Deposit(f.ctx, payerID, amount, generateEventID(baseEventID), 0)
 			require.NoError(t, err)
 
 			// Test withdrawal
@@ -417,11 +429,12 @@ func TestAmountValidations(t *testing.T) {
 				payerID,
 				amount,
 				generateEventID(baseEventID+1),
+				0,
 			)
 			require.NoError(t, err)
 
 			// Test settlement
-			err = f.ledger.SettleUsage(f.ctx, payerID, amount, generateEventID(baseEventID+2))
+			err = f.ledger.SettleUsage(f.ctx, payerID, amount, generateEventID(baseEventID+2), 0)
 			require.NoError(t, err)
 		}
 	})
@@ -436,42 +449,42 @@ func TestEventIDValidations(t *testing.T) {
 		{
 			name: "deposit_zero_event_id_rejected",
 			action: func(f *testFixture) error {
-				return f.ledger.Deposit(f.ctx, 30, 1000, ledger.EventID{})
+				return f.ledger.Deposit(f.ctx, 30, 1000, ledger.EventID{}, 0)
 			},
 			expectedError: ledger.ErrInvalidEventID,
 		},
 		{
 			name: "deposit_negative_event_id_rejected",
 			action: func(f *testFixture) error {
-				return f.ledger.Deposit(f.ctx, 31, 1000, ledger.EventID{})
+				return f.ledger.Deposit(f.ctx, 31, 1000, ledger.EventID{}, 0)
 			},
 			expectedError: ledger.ErrInvalidEventID,
 		},
 		{
 			name: "withdrawal_zero_event_id_rejected",
 			action: func(f *testFixture) error {
-				return f.ledger.InitiateWithdrawal(f.ctx, 32, 1000, ledger.EventID{})
+				return f.ledger.InitiateWithdrawal(f.ctx, 32, 1000, ledger.EventID{}, 0)
 			},
 			expectedError: ledger.ErrInvalidEventID,
 		},
 		{
 			name: "withdrawal_negative_event_id_rejected",
 			action: func(f *testFixture) error {
-				return f.ledger.InitiateWithdrawal(f.ctx, 33, 1000, ledger.EventID{})
+				return f.ledger.InitiateWithdrawal(f.ctx, 33, 1000, ledger.EventID{}, 0)
 			},
 			expectedError: ledger.ErrInvalidEventID,
 		},
 		{
 			name: "settlement_zero_event_id_rejected",
 			action: func(f *testFixture) error {
-				return f.ledger.SettleUsage(f.ctx, 34, 1000, ledger.EventID{})
+				return f.ledger.SettleUsage(f.ctx, 34, 1000, ledger.EventID{}, 0)
 			},
 			expectedError: ledger.ErrInvalidEventID,
 		},
 		{
 			name: "settlement_negative_event_id_rejected",
 			action: func(f *testFixture) error {
-				return f.ledger.SettleUsage(f.ctx, 35, 1000, ledger.EventID{})
+				return f.ledger.SettleUsage(f.ctx, 35, 1000, ledger.EventID{}, 0)
 			},
 			expectedError: ledger.ErrInvalidEventID,
 		},
@@ -493,7 +506,7 @@ func TestEventIDValidations(t *testing.T) {
 		eventIDs := []int{1, 100, 1000, 999999} // Test with various valid IDs
 
 		for _, eventID := range eventIDs {
-			err := f.ledger.Deposit(f.ctx, payerID, amount, generateEventID(eventID))
+			err := f.ledger.Deposit(f.ctx, payerID, amount, generateEventID(eventID), 0)
 			require.NoError(t, err)
 		}
 	})
@@ -527,7 +540,7 @@ func TestConcurrentOperations(t *testing.T) {
 		errChan := make(chan error, numDeposits)
 		for i := range numDeposits {
 			go func(eventNum int) {
-				err := f.ledger.Deposit(f.ctx, payerID, amount, generateEventID(4000+eventNum))
+				err := f.ledger.Deposit(f.ctx, payerID, amount, generateEventID(4000+eventNum), 0)
 				errChan <- err
 			}(i)
 		}
@@ -561,22 +574,22 @@ func TestConcurrentOperations(t *testing.T) {
 
 		operations := []operation{
 			{
-				fn: func() error { return f.ledger.Deposit(f.ctx, payer41, 1000, generateEventID(4101)) },
+				fn: func() error { return f.ledger.Deposit(f.ctx, payer41, 1000, generateEventID(4101), 0) },
 			},
 			{
-				fn: func() error { return f.ledger.Deposit(f.ctx, payer41, 2000, generateEventID(4102)) },
+				fn: func() error { return f.ledger.Deposit(f.ctx, payer41, 2000, generateEventID(4102), 0) },
 			},
 			{
-				fn: func() error { return f.ledger.InitiateWithdrawal(f.ctx, payer41, 500, generateEventID(4103)) },
+				fn: func() error { return f.ledger.InitiateWithdrawal(f.ctx, payer41, 500, generateEventID(4103), 0) },
 			},
 			{
-				fn: func() error { return f.ledger.InitiateWithdrawal(f.ctx, payer41, 700, generateEventID(4104)) },
+				fn: func() error { return f.ledger.InitiateWithdrawal(f.ctx, payer41, 700, generateEventID(4104), 0) },
 			},
 			{
-				fn: func() error { return f.ledger.SettleUsage(f.ctx, payer41, 300, generateEventID(4105)) },
+				fn: func() error { return f.ledger.SettleUsage(f.ctx, payer41, 300, generateEventID(4105), 0) },
 			},
 			{
-				fn: func() error { return f.ledger.SettleUsage(f.ctx, payer41, 400, generateEventID(4106)) },
+				fn: func() error { return f.ledger.SettleUsage(f.ctx, payer41, 400, generateEventID(4106), 0) },
 			},
 		}
 
@@ -607,7 +620,7 @@ func TestEdgeCases(t *testing.T) {
 		// Test with very large amounts (close to max int64)
 		largeAmount := currency.PicoDollar(9223372036854775000)
 
-		err := f.ledger.Deposit(f.ctx, payerID, largeAmount, generateEventID(5001))
+		err := f.ledger.Deposit(f.ctx, payerID, largeAmount, generateEventID(5001), 0)
 		require.NoError(t, err)
 
 		balance, err := f.ledger.GetBalance(f.ctx, payerID)
@@ -625,13 +638,13 @@ func TestEdgeCases(t *testing.T) {
 			eventID := generateEventID(5100 + i)
 			switch i % 3 {
 			case 0:
-				err := f.ledger.Deposit(f.ctx, payerID, 10, eventID)
+				err := f.ledger.Deposit(f.ctx, payerID, 10, eventID, 0)
 				require.NoError(t, err)
 			case 1:
-				err := f.ledger.InitiateWithdrawal(f.ctx, payerID, 3, eventID)
+				err := f.ledger.InitiateWithdrawal(f.ctx, payerID, 3, eventID, 0)
 				require.NoError(t, err)
 			case 2:
-				err := f.ledger.SettleUsage(f.ctx, payerID, 2, eventID)
+				err := f.ledger.SettleUsage(f.ctx, payerID, 2, eventID, 0)
 				require.NoError(t, err)
 			}
 		}
@@ -650,7 +663,7 @@ func TestEdgeCases(t *testing.T) {
 		f := setupTest(t)
 		payerID := f.payer(52)
 
-		err := f.ledger.CancelWithdrawal(f.ctx, payerID, generateEventID(5201))
+		err := f.ledger.CancelWithdrawal(f.ctx, payerID, generateEventID(5201), 0)
 		require.Error(t, err)
 	})
 }
@@ -669,7 +682,7 @@ func TestCancelWithdrawal(t *testing.T) {
 				},
 			},
 			action: func(f *testFixture) error {
-				return f.ledger.CancelWithdrawal(f.ctx, f.payer(60), generateEventID(6003))
+				return f.ledger.CancelWithdrawal(f.ctx, f.payer(60), generateEventID(6003), 0)
 			},
 			validate: func(t *testing.T, f *testFixture) {
 				// Balance should be restored to original 5000
@@ -683,7 +696,7 @@ func TestCancelWithdrawal(t *testing.T) {
 			initialEvents: []initialEvent{},
 			action: func(f *testFixture) error {
 				// Payer created on demand via f.payer()
-				return f.ledger.CancelWithdrawal(f.ctx, f.payer(61), generateEventID(6101))
+				return f.ledger.CancelWithdrawal(f.ctx, f.payer(61), generateEventID(6101), 0)
 			},
 			validate:      func(t *testing.T, f *testFixture) {},
 			expectedError: ledger.ErrWithdrawalNotFound,
@@ -696,12 +709,12 @@ func TestCancelWithdrawal(t *testing.T) {
 			},
 			action: func(f *testFixture) error {
 				// First cancellation
-				err := f.ledger.CancelWithdrawal(f.ctx, f.payer(62), generateEventID(6203))
+				err := f.ledger.CancelWithdrawal(f.ctx, f.payer(62), generateEventID(6203), 0)
 				if err != nil {
 					return err
 				}
 				// Second cancellation with same event ID should succeed (idempotent)
-				return f.ledger.CancelWithdrawal(f.ctx, f.payer(62), generateEventID(6203))
+				return f.ledger.CancelWithdrawal(f.ctx, f.payer(62), generateEventID(6203), 0)
 			},
 			validate: func(t *testing.T, f *testFixture) {
 				balance, err := f.ledger.GetBalance(f.ctx, f.payer(62))
@@ -717,12 +730,12 @@ func TestCancelWithdrawal(t *testing.T) {
 			},
 			action: func(f *testFixture) error {
 				// First cancellation
-				err := f.ledger.CancelWithdrawal(f.ctx, f.payer(63), generateEventID(6303))
+				err := f.ledger.CancelWithdrawal(f.ctx, f.payer(63), generateEventID(6303), 0)
 				if err != nil {
 					return err
 				}
 				// Second cancellation with different event ID should fail
-				return f.ledger.CancelWithdrawal(f.ctx, f.payer(63), generateEventID(6304))
+				return f.ledger.CancelWithdrawal(f.ctx, f.payer(63), generateEventID(6304), 0)
 			},
 			validate:      func(t *testing.T, f *testFixture) {},
 			expectedError: ledger.ErrWithdrawalAlreadyCanceled,
@@ -741,7 +754,7 @@ func TestCancelWithdrawal(t *testing.T) {
 			},
 			action: func(f *testFixture) error {
 				// Should cancel the most recent withdrawal (500)
-				return f.ledger.CancelWithdrawal(f.ctx, f.payer(64), generateEventID(6404))
+				return f.ledger.CancelWithdrawal(f.ctx, f.payer(64), generateEventID(6404), 0)
 			},
 			validate: func(t *testing.T, f *testFixture) {
 				// Balance should be 6000 - 1000 - 500 + 500 = 5000
@@ -768,3 +781,211 @@ func TestCancelWithdrawal(t *testing.T) {
 		})
 	}
 }
+
+func TestReorgReversal(t *testing.T) {
+	testCases := []testCase{
+		{
+			name: "reverse_deposit",
+			initialEvents: []initialEvent{
+				{payerID: 70, amount: 1000, eventID: generateEventID(7001), eventType: "deposit"},
+				{payerID: 70, amount: 300, eventID: generateEventID(7002), eventType: "deposit"},
+			},
+			action: func(f *testFixture) error {
+				return f.ledger.ReverseEvent(f.ctx, generateEventID(7002))
+			},
+			validate: func(t *testing.T, f *testFixture) {
+				balance, err := f.ledger.GetBalance(f.ctx, f.payer(70))
+				require.NoError(t, err)
+				require.Equal(t, currency.PicoDollar(1000), balance)
+			},
+		},
+		{
+			name: "reverse_settlement",
+			initialEvents: []initialEvent{
+				{payerID: 71, amount: 1000, eventID: generateEventID(7101), eventType: "deposit"},
+				{payerID: 71, amount: 400, eventID: generateEventID(7102), eventType: "settlement"},
+			},
+			action: func(f *testFixture) error {
+				return f.ledger.ReverseEvent(f.ctx, generateEventID(7102))
+			},
+			validate: func(t *testing.T, f *testFixture) {
+				balance, err := f.ledger.GetBalance(f.ctx, f.payer(71))
+				require.NoError(t, err)
+				require.Equal(t, currency.PicoDollar(1000), balance)
+			},
+		},
+		{
+			name: "reversal_is_idempotent",
+			initialEvents: []initialEvent{
+				{payerID: 72, amount: 1000, eventID: generateEventID(7201), eventType: "deposit"},
+				{payerID: 72, amount: 200, eventID: generateEventID(7202), eventType: "withdrawal"},
+			},
+			action: func(f *testFixture) error {
+				if err := f.ledger.ReverseEvent(f.ctx, generateEventID(7202)); err != nil {
+					return err
+				}
+				return f.ledger.ReverseEvent(f.ctx, generateEventID(7202))
+			},
+			validate: func(t *testing.T, f *testFixture) {
+				balance, err := f.ledger.GetBalance(f.ctx, f.payer(72))
+				require.NoError(t, err)
+				require.Equal(t, currency.PicoDollar(1000), balance)
+			},
+		},
+		{
+			name: "reversing_unknown_event_is_noop",
+			initialEvents: []initialEvent{
+				{payerID: 73, amount: 1000, eventID: generateEventID(7301), eventType: "deposit"},
+			},
+			action: func(f *testFixture) error {
+				return f.ledger.ReverseEvent(f.ctx, generateEventID(7399))
+			},
+			validate: func(t *testing.T, f *testFixture) {
+				balance, err := f.ledger.GetBalance(f.ctx, f.payer(73))
+				require.NoError(t, err)
+				require.Equal(t, currency.PicoDollar(1000), balance)
+			},
+		},
+		{
+			name: "reversal_of_reversal_is_noop",
+			initialEvents: []initialEvent{
+				{payerID: 74, amount: 1000, eventID: generateEventID(7401), eventType: "deposit"},
+			},
+			action: func(f *testFixture) error {
+				if err := f.ledger.ReverseEvent(f.ctx, generateEventID(7401)); err != nil {
+					return err
+				}
+				reversalID := ledger.BuildReversalEventID(generateEventID(7401))
+				return f.ledger.ReverseEvent(f.ctx, reversalID)
+			},
+			validate: func(t *testing.T, f *testFixture) {
+				balance, err := f.ledger.GetBalance(f.ctx, f.payer(74))
+				require.NoError(t, err)
+				require.Equal(t, currency.PicoDollar(0), balance)
+			},
+		},
+		{
+			name: "reversed_withdrawal_cannot_be_canceled",
+			initialEvents: []initialEvent{
+				{payerID: 75, amount: 1000, eventID: generateEventID(7501), eventType: "deposit"},
+				{payerID: 75, amount: 200, eventID: generateEventID(7502), eventType: "withdrawal"},
+			},
+			action: func(f *testFixture) error {
+				if err := f.ledger.ReverseEvent(f.ctx, generateEventID(7502)); err != nil {
+					return err
+				}
+				return f.ledger.CancelWithdrawal(f.ctx, f.payer(75), generateEventID(7503), 0)
+			},
+			validate: func(t *testing.T, f *testFixture) {
+				balance, err := f.ledger.GetBalance(f.ctx, f.payer(75))
+				require.NoError(t, err)
+				require.Equal(t, currency.PicoDollar(1000), balance)
+			},
+			expectedError: ledger.ErrWithdrawalNotFound,
+		},
+		{
+			name: "reversed_cancellation_restores_withdrawal",
+			initialEvents: []initialEvent{
+				{payerID: 76, amount: 1000, eventID: generateEventID(7601), eventType: "deposit"},
+				{payerID: 76, amount: 200, eventID: generateEventID(7602), eventType: "withdrawal"},
+			},
+			action: func(f *testFixture) error {
+				err := f.ledger.CancelWithdrawal(f.ctx, f.payer(76), generateEventID(7603), 0)
+				if err != nil {
+					return err
+				}
+				if err := f.ledger.ReverseEvent(f.ctx, generateEventID(7603)); err != nil {
+					return err
+				}
+				// The cancellation is included again in a different block
+				return f.ledger.CancelWithdrawal(f.ctx, f.payer(76), generateEventID(7604), 0)
+			},
+			validate: func(t *testing.T, f *testFixture) {
+				balance, err := f.ledger.GetBalance(f.ctx, f.payer(76))
+				require.NoError(t, err)
+				require.Equal(t, currency.PicoDollar(1000), balance)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := setupWithInitialState(t, tc)
+
+			err := tc.action(f)
+			if tc.expectedError != nil {
+				require.Error(t, err)
+				require.Equal(t, tc.expectedError, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			tc.validate(t, f)
+		})
+	}
+}
+
+func TestListEvents(t *testing.T) {
+	f := setupTest(t)
+	payerID := f.payer(80)
+	otherPayerID := f.payer(81)
+
+	require.NoError(t, f.ledger.Deposit(f.ctx, payerID, 1000, generateEventID(8001), 100))
+	require.NoError(t, f.ledger.Deposit(f.ctx, otherPayerID, 1000, generateEventID(8101), 101))
+	require.NoError(
+		t,
+		f.ledger.InitiateWithdrawal(f.ctx, payerID, 200, generateEventID(8002), 102),
+	)
+	require.NoError(t, f.ledger.SettleUsage(f.ctx, payerID, 300, generateEventID(8003), 103))
+	require.NoError(t, f.ledger.ReverseEvent(f.ctx, generateEventID(8003)))
+
+	events, err := f.ledger.ListEvents(f.ctx, payerID, 0, 100)
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+
+	expected := []struct {
+		eventID     ledger.EventID
+		eventType   ledger.EventType
+		amount      currency.PicoDollar
+		blockNumber uint64
+	}{
+		{generateEventID(8001), ledger.EventTypeDeposit, 1000, 100},
+		{generateEventID(8002), ledger.EventTypeWithdrawal, -200, 102},
+		{generateEventID(8003), ledger.EventTypeSettlement, -300, 103},
+		{
+			ledger.BuildReversalEventID(generateEventID(8003)),
+			ledger.EventTypeReorgReversal,
+			300,
+			103,
+		},
+	}
+	for i, e := range expected {
+		require.Equal(t, e.eventID, events[i].ID)
+		require.Equal(t, e.eventType, events[i].Type)
+		require.Equal(t, e.amount, events[i].Amount)
+		require.Equal(t, e.blockNumber, events[i].BlockNumber)
+		if i > 0 {
+			require.Greater(t, events[i].Sequence, events[i-1].Sequence)
+		}
+	}
+	require.Nil(t, events[2].ReversedEventID)
+	require.NotNil(t, events[3].ReversedEventID)
+	require.Equal(t, generateEventID(8003), *events[3].ReversedEventID)
+
+	// Page through the events two at a time
+	firstPage, err := f.ledger.ListEvents(f.ctx, payerID, 0, 2)
+	require.NoError(t, err)
+	require.Len(t, firstPage, 2)
+
+	secondPage, err := f.ledger.ListEvents(f.ctx, payerID, firstPage[1].Sequence, 2)
+	require.NoError(t, err)
+	require.Len(t, secondPage, 2)
+	require.Equal(t, events[2:], secondPage)
+
+	lastPage, err := f.ledger.ListEvents(f.ctx, payerID, secondPage[1].Sequence, 2)
+	require.NoError(t, err)
+	require.Empty(t, lastPage)
+
+	_, err = f.ledger.ListEvents(f.ctx, payerID, 0, 0)
+	require.ErrorIs(t, err, ledger.ErrInvalidLimit)
+}
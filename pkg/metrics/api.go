@@ -101,6 +101,17 @@ func EmitAPIStageEnvelope(duration time.Duration) {
 	apiStageEnvelopeDuration.Observe(duration.Seconds())
 }
 
+var apiDeduplicatedPublishes = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "xmtp_api_deduplicated_publishes_total",
+		Help: "Number of published payer envelopes that were already published within the dedup window",
+	},
+)
+
+func EmitAPIDeduplicatedPublishes(count int) {
+	apiDeduplicatedPublishes.Add(float64(count))
+}
+
 var apiWaitForGatewayPublish = prometheus.NewHistogram(
 	prometheus.HistogramOpts{
 		Name:    "xmtp_api_wait_for_gateway_publish_seconds",
@@ -90,6 +90,10 @@ func registerCollectors(reg prometheus.Registerer) {
 		gatewayCurrentNonce,
 		gatewayBanlistRetry,
 		gatewayMessagesOriginated,
+		gatewayBlockchainBatchSize,
+		gatewayNodeEjections,
+		gatewayNodeEjectionSeconds,
+		gatewayOutboxEnvelopes,
 		syncOriginatorSequenceID,
 		syncOriginatorMessagesReceived,
 		syncOriginatorErrorMessages,
@@ -97,11 +101,17 @@ func registerCollectors(reg prometheus.Registerer) {
 		syncFailedOutgoingSyncConnections,
 		syncFailedOutgoingSyncConnectionCounter,
 		syncSubscribeRPC,
+		syncRelayedStreams,
+		syncOpenGaps,
+		syncAbandonedGaps,
+		syncOldestGapAge,
+		syncGapEnvelopesBackfilled,
 		apiOpenConnections,
 		apiIncomingNodeConnectionByVersionGauge,
 		apiNodeConnectionRequestsByVersionCounter,
 		apiFailedGRPCRequestsCounter,
 		apiStageEnvelopeDuration,
+		apiDeduplicatedPublishes,
 		apiStagedEnvelopeProcessingDelay,
 		apiWaitForGatewayPublish,
 		apiOutgoingEnvelopesTotal,
@@ -131,6 +141,10 @@ func registerCollectors(reg prometheus.Registerer) {
 		migratorWriterRowsMigrated,
 		migratorWriterBytesMigrated,
 		migratorTargetLastSequenceID,
+		pruneRowsReclaimed,
+		pruneBytesReclaimed,
+		prunePartitionsDropped,
+		pruneRunDuration,
 		QueryDuration,
 		QueryErrors,
 	}
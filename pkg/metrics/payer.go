@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -92,3 +93,52 @@ var gatewayGetNodesAvailableNodes = prometheus.NewGauge(
 func EmitGatewayGetNodesAvailableNodes(count int) {
 	gatewayGetNodesAvailableNodes.Set(float64(count))
 }
+
+var gatewayBlockchainBatchSize = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "xmtp_gateway_blockchain_batch_size",
+		Help:    "Number of envelopes published per blockchain batch transaction",
+		Buckets: []float64{1, 2, 5, 10, 20, 50, 100},
+	},
+	[]string{"originator_id"},
+)
+
+func EmitGatewayBlockchainBatchSize(originatorID uint32, size int) {
+	gatewayBlockchainBatchSize.With(prometheus.Labels{"originator_id": strconv.Itoa(int(originatorID))}).
+		Observe(float64(size))
+}
+
+var gatewayNodeEjections = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "xmtp_gateway_node_ejections",
+		Help: "Number of times the adaptive node selector ejected a node",
+	},
+	[]string{"originator_id"},
+)
+
+var gatewayNodeEjectionSeconds = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "xmtp_gateway_node_ejection_seconds",
+		Help:    "Duration nodes are ejected for by the adaptive node selector",
+		Buckets: []float64{10, 20, 40, 80, 160, 300, 600},
+	},
+	[]string{"originator_id"},
+)
+
+func EmitGatewayNodeEjected(nodeID uint32, ejection time.Duration) {
+	labels := prometheus.Labels{"originator_id": strconv.Itoa(int(nodeID))}
+	gatewayNodeEjections.With(labels).Inc()
+	gatewayNodeEjectionSeconds.With(labels).Observe(ejection.Seconds())
+}
+
+var gatewayOutboxEnvelopes = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "xmtp_gateway_outbox_envelopes",
+		Help: "Number of envelopes replayed from the gateway publish outbox, by outcome",
+	},
+	[]string{"outcome"},
+)
+
+func EmitGatewayOutboxEnvelopes(outcome string, count int) {
+	gatewayOutboxEnvelopes.With(prometheus.Labels{"outcome": outcome}).Add(float64(count))
+}
@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var pruneRowsReclaimed = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "xmtp_prune_rows_reclaimed_total",
+		Help: "Total number of envelopes pruned from the database",
+	},
+	[]string{"originator_id"},
+)
+
+var pruneBytesReclaimed = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "xmtp_prune_bytes_reclaimed_total",
+		Help: "Total number of envelope and topic bytes pruned from the database",
+	},
+	[]string{"originator_id"},
+)
+
+func EmitPruneReclaimed(originatorID uint32, rows int64, bytes int64) {
+	labels := prometheus.Labels{"originator_id": strconv.FormatUint(uint64(originatorID), 10)}
+	pruneRowsReclaimed.With(labels).Add(float64(rows))
+	pruneBytesReclaimed.With(labels).Add(float64(bytes))
+}
+
+var prunePartitionsDropped = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "xmtp_prune_partitions_dropped_total",
+		Help: "Total number of empty envelope partitions dropped",
+	},
+)
+
+func EmitPrunePartitionDropped() {
+	prunePartitionsDropped.Inc()
+}
+
+var pruneRunDuration = prometheus.NewHistogram(
+	prometheus.HistogramOpts{
+		Name:    "xmtp_prune_run_duration_seconds",
+		Help:    "Time spent in a single pruning run",
+		Buckets: []float64{0.1, 0.5, 1.0, 5.0, 10.0, 30.0, 60.0, 300.0, 900.0},
+	},
+)
+
+func EmitPruneRunDuration(duration time.Duration) {
+	pruneRunDuration.Observe(duration.Seconds())
+}
@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -125,6 +126,23 @@ var syncSubscribeRPC = prometheus.NewCounterVec(
 	[]string{"rpc", "originator_id"},
 )
 
+var syncRelayedStreams = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "xmtp_sync_relayed_streams_total",
+		Help: "Number of streams of an unreachable originator opened against another node",
+	},
+	[]string{"originator_id", "source_node_id"},
+)
+
+// EmitSyncRelayedStream records a stream of an originator's envelopes opened
+// against another node holding a copy of them.
+func EmitSyncRelayedStream(originatorID, sourceNodeID uint32) {
+	syncRelayedStreams.With(prometheus.Labels{
+		"originator_id":  strconv.Itoa(int(originatorID)),
+		"source_node_id": strconv.Itoa(int(sourceNodeID)),
+	}).Inc()
+}
+
 // EmitSyncSubscribeRPC records which Replication RPC was used to open a sync
 // stream against a given peer. Used to observe the SubscribeOriginators rollout.
 // rpc is expected to be "originators" or "envelopes".
@@ -134,3 +152,60 @@ func EmitSyncSubscribeRPC(rpc string, originatorID uint32) {
 		"originator_id": strconv.Itoa(int(originatorID)),
 	}).Inc()
 }
+
+var syncOpenGaps = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "xmtp_sync_open_gaps",
+		Help: "Number of missing ranges of an originator's sequence IDs not yet backfilled",
+	},
+	[]string{"originator_id"},
+)
+
+var syncAbandonedGaps = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "xmtp_sync_abandoned_gaps",
+		Help: "Number of missing ranges of an originator's sequence IDs no longer backfilled",
+	},
+	[]string{"originator_id"},
+)
+
+var syncOldestGapAge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "xmtp_sync_oldest_gap_age_seconds",
+		Help: "Age of the oldest open sequence gap of an originator",
+	},
+	[]string{"originator_id"},
+)
+
+var syncGapEnvelopesBackfilled = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "xmtp_sync_gap_envelopes_backfilled_total",
+		Help: "Number of envelopes of an originator backfilled into sequence gaps",
+	},
+	[]string{"originator_id"},
+)
+
+// EmitSyncGapStats records the open and abandoned sequence gaps of an originator.
+func EmitSyncGapStats(
+	originatorID uint32,
+	openGaps, abandonedGaps int64,
+	oldestAge time.Duration,
+) {
+	labels := prometheus.Labels{"originator_id": strconv.Itoa(int(originatorID))}
+	syncOpenGaps.With(labels).Set(float64(openGaps))
+	syncAbandonedGaps.With(labels).Set(float64(abandonedGaps))
+	syncOldestGapAge.With(labels).Set(oldestAge.Seconds())
+}
+
+// ClearSyncGapStats removes the gap series of an originator that has no gaps left.
+func ClearSyncGapStats(originatorID uint32) {
+	labels := prometheus.Labels{"originator_id": strconv.Itoa(int(originatorID))}
+	syncOpenGaps.Delete(labels)
+	syncAbandonedGaps.Delete(labels)
+	syncOldestGapAge.Delete(labels)
+}
+
+func EmitSyncGapEnvelopesBackfilled(originatorID uint32, count int) {
+	syncGapEnvelopesBackfilled.With(prometheus.Labels{"originator_id": strconv.Itoa(int(originatorID))}).
+		Add(float64(count))
+}
@@ -2,5 +2,5 @@ package misbehavior
 
 type MisbehaviorService interface {
 	SafetyFailure(report *SafetyFailureReport) error
-	// TODO:nm add liveness failures
+	LivenessFailure(report *LivenessFailureReport) error
 }
@@ -0,0 +1,268 @@
+package misbehavior
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	proto "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/message_api"
+	"github.com/xmtp/xmtpd/pkg/utils"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// LivenessConfig controls when the LivenessTracker reports a node.
+// Requests are aggregated per node over tumbling windows, and each
+// misbehavior type is reported at most once per node and window.
+type LivenessConfig struct {
+	// Window is the duration over which requests are aggregated.
+	Window time.Duration
+	// MinRequests is the number of requests required before error and slow rates are evaluated.
+	MinRequests int
+	// MaxErrorRate is the fraction of failed requests above which a node is unresponsive.
+	MaxErrorRate float64
+	// MaxTimeouts is the number of timed out requests above which a node is unresponsive.
+	MaxTimeouts int
+	// SlowResponseTime is the response time above which a request is considered slow.
+	SlowResponseTime time.Duration
+	// MaxSlowRate is the fraction of slow requests above which a node is slow.
+	MaxSlowRate float64
+}
+
+func DefaultLivenessConfig() LivenessConfig {
+	return LivenessConfig{
+		Window:           5 * time.Minute,
+		MinRequests:      10,
+		MaxErrorRate:     0.5,
+		MaxTimeouts:      5,
+		SlowResponseTime: 5 * time.Second,
+		MaxSlowRate:      0.5,
+	}
+}
+
+type livenessWindow struct {
+	start             time.Time
+	requests          int
+	errors            int
+	timeouts          int
+	slow              int
+	totalResponseTime time.Duration
+	reported          map[proto.Misbehavior]struct{}
+}
+
+func (w *livenessWindow) averageResponseTime() time.Duration {
+	if w.requests == 0 {
+		return 0
+	}
+	return w.totalResponseTime / time.Duration(w.requests)
+}
+
+// LivenessTracker aggregates the outcome of requests made to other nodes and
+// files MISBEHAVIOR_UNRESPONSIVE_NODE and MISBEHAVIOR_SLOW_NODE reports once
+// the configured thresholds are crossed.
+type LivenessTracker struct {
+	logger  *zap.Logger
+	service MisbehaviorService
+	cfg     LivenessConfig
+	now     func() time.Time
+
+	mu        sync.Mutex
+	windows   map[uint32]*livenessWindow
+	lastSweep time.Time
+}
+
+func NewLivenessTracker(
+	logger *zap.Logger,
+	service MisbehaviorService,
+	cfg LivenessConfig,
+) *LivenessTracker {
+	return &LivenessTracker{
+		logger:  logger.Named(utils.MisbehaviorLoggerName),
+		service: service,
+		cfg:     cfg,
+		now:     time.Now,
+		windows: make(map[uint32]*livenessWindow),
+	}
+}
+
+// RecordSuccess records a request to the node that succeeded after responseTime.
+func (t *LivenessTracker) RecordSuccess(nodeID uint32, responseTime time.Duration) {
+	t.record(nodeID, responseTime, nil)
+}
+
+// RecordFailure records a request to the node that failed after responseTime.
+// Deadline errors are counted as timeouts. Only transport, Unavailable and DeadlineExceeded
+// errors count against the node: a node rejecting a request still answered it.
+func (t *LivenessTracker) RecordFailure(nodeID uint32, responseTime time.Duration, err error) {
+	if err == nil {
+		err = errors.New("unknown error")
+	}
+	if !isLivenessFailure(err) {
+		err = nil
+	}
+	t.record(nodeID, responseTime, err)
+}
+
+func (t *LivenessTracker) record(nodeID uint32, responseTime time.Duration, err error) {
+	reports := t.observe(nodeID, responseTime, err)
+
+	// Reports are filed outside the lock, as the service may write to the database.
+	for _, report := range reports {
+		if err := t.service.LivenessFailure(report); err != nil {
+			t.logger.Error(
+				"failed to report liveness failure",
+				utils.OriginatorIDField(nodeID),
+				zap.String("misbehavior_type", report.MisbehaviorType().String()),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+func (t *LivenessTracker) observe(
+	nodeID uint32,
+	responseTime time.Duration,
+	err error,
+) []*LivenessFailureReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.evictIdleWindows(now)
+
+	window, ok := t.windows[nodeID]
+	if !ok || now.Sub(window.start) >= t.cfg.Window {
+		window = &livenessWindow{
+			start:    now,
+			reported: make(map[proto.Misbehavior]struct{}),
+		}
+		t.windows[nodeID] = window
+	}
+
+	window.requests++
+	window.totalResponseTime += responseTime
+
+	if err != nil {
+		window.errors++
+		if isTimeout(err) {
+			window.timeouts++
+		}
+	}
+
+	if responseTime >= t.cfg.SlowResponseTime {
+		window.slow++
+	}
+
+	var reports []*LivenessFailureReport
+
+	if t.isUnresponsive(window) {
+		reports = t.appendReport(
+			reports,
+			window,
+			nodeID,
+			proto.Misbehavior_MISBEHAVIOR_UNRESPONSIVE_NODE,
+		)
+	}
+
+	if t.isSlow(window) {
+		reports = t.appendReport(reports, window, nodeID, proto.Misbehavior_MISBEHAVIOR_SLOW_NODE)
+	}
+
+	return reports
+}
+
+// evictIdleWindows drops the windows of nodes that were not requested during the last
+// window, such as nodes that left the registry. It sweeps at most once per window.
+func (t *LivenessTracker) evictIdleWindows(now time.Time) {
+	if now.Sub(t.lastSweep) < t.cfg.Window {
+		return
+	}
+	t.lastSweep = now
+
+	for nodeID, window := range t.windows {
+		if now.Sub(window.start) >= t.cfg.Window {
+			delete(t.windows, nodeID)
+		}
+	}
+}
+
+func (t *LivenessTracker) isUnresponsive(window *livenessWindow) bool {
+	if t.cfg.MaxTimeouts > 0 && window.timeouts >= t.cfg.MaxTimeouts {
+		return true
+	}
+
+	return window.requests >= t.cfg.MinRequests &&
+		float64(window.errors)/float64(window.requests) >= t.cfg.MaxErrorRate
+}
+
+func (t *LivenessTracker) isSlow(window *livenessWindow) bool {
+	return window.requests >= t.cfg.MinRequests &&
+		float64(window.slow)/float64(window.requests) >= t.cfg.MaxSlowRate
+}
+
+func (t *LivenessTracker) appendReport(
+	reports []*LivenessFailureReport,
+	window *livenessWindow,
+	nodeID uint32,
+	misbehaviorType proto.Misbehavior,
+) []*LivenessFailureReport {
+	if _, ok := window.reported[misbehaviorType]; ok {
+		return reports
+	}
+
+	report, err := NewLivenessFailureReport(
+		nodeID,
+		misbehaviorType,
+		window.averageResponseTime(),
+	)
+	if err != nil {
+		t.logger.Error("failed to build liveness failure report", zap.Error(err))
+		return reports
+	}
+
+	window.reported[misbehaviorType] = struct{}{}
+
+	return append(reports, report)
+}
+
+// isLivenessFailure reports whether a failed request reflects on the liveness of the node.
+// Errors without a status code are transport errors.
+func isLivenessFailure(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if isTimeout(err) {
+		return true
+	}
+
+	if st, ok := status.FromError(err); ok {
+		return st.Code() == codes.Unavailable
+	}
+
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		return connectErr.Code() == connect.CodeUnavailable
+	}
+
+	return true
+}
+
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) && connectErr.Code() == connect.CodeDeadlineExceeded {
+		return true
+	}
+
+	if st, ok := status.FromError(err); ok && st.Code() == codes.DeadlineExceeded {
+		return true
+	}
+
+	return false
+}
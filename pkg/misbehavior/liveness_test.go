@@ -0,0 +1,189 @@
+package misbehavior
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/require"
+	proto "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/message_api"
+	"github.com/xmtp/xmtpd/pkg/testutils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const livenessNodeID = uint32(100)
+
+type recordingService struct {
+	liveness []*LivenessFailureReport
+}
+
+func (r *recordingService) SafetyFailure(*SafetyFailureReport) error {
+	return nil
+}
+
+func (r *recordingService) LivenessFailure(report *LivenessFailureReport) error {
+	r.liveness = append(r.liveness, report)
+	return nil
+}
+
+func newTestLivenessTracker(
+	t *testing.T,
+	now *time.Time,
+) (*LivenessTracker, *recordingService) {
+	service := &recordingService{}
+	tracker := NewLivenessTracker(testutils.NewLog(t), service, LivenessConfig{
+		Window:           time.Minute,
+		MinRequests:      4,
+		MaxErrorRate:     0.5,
+		MaxTimeouts:      3,
+		SlowResponseTime: time.Second,
+		MaxSlowRate:      0.5,
+	})
+	tracker.now = func() time.Time { return *now }
+
+	return tracker, service
+}
+
+func TestLivenessTrackerHealthyNode(t *testing.T) {
+	now := time.Now()
+	tracker, service := newTestLivenessTracker(t, &now)
+
+	for range 10 {
+		tracker.RecordSuccess(livenessNodeID, 10*time.Millisecond)
+	}
+	tracker.RecordFailure(livenessNodeID, 10*time.Millisecond, errors.New("boom"))
+
+	require.Empty(t, service.liveness)
+}
+
+func TestLivenessTrackerErrorRate(t *testing.T) {
+	now := time.Now()
+	tracker, service := newTestLivenessTracker(t, &now)
+
+	tracker.RecordSuccess(livenessNodeID, 10*time.Millisecond)
+	tracker.RecordSuccess(livenessNodeID, 10*time.Millisecond)
+	tracker.RecordFailure(livenessNodeID, 10*time.Millisecond, errors.New("boom"))
+	require.Empty(t, service.liveness, "below the minimum number of requests")
+
+	tracker.RecordFailure(livenessNodeID, 10*time.Millisecond, errors.New("boom"))
+	require.Len(t, service.liveness, 1)
+	require.Equal(t, livenessNodeID, service.liveness[0].MisbehavingNodeID())
+	require.Equal(
+		t,
+		proto.Misbehavior_MISBEHAVIOR_UNRESPONSIVE_NODE,
+		service.liveness[0].MisbehaviorType(),
+	)
+
+	// Reported at most once per window.
+	tracker.RecordFailure(livenessNodeID, 10*time.Millisecond, errors.New("boom"))
+	require.Len(t, service.liveness, 1)
+
+	// A new window starts from scratch.
+	now = now.Add(time.Minute)
+	tracker.RecordFailure(livenessNodeID, 10*time.Millisecond, errors.New("boom"))
+	require.Len(t, service.liveness, 1)
+}
+
+func TestLivenessTrackerTimeouts(t *testing.T) {
+	now := time.Now()
+	tracker, service := newTestLivenessTracker(t, &now)
+
+	timeouts := []error{
+		context.DeadlineExceeded,
+		fmt.Errorf("publishing: %w", context.DeadlineExceeded),
+		status.Error(codes.DeadlineExceeded, "deadline exceeded"),
+	}
+	for _, err := range timeouts {
+		tracker.RecordFailure(livenessNodeID, 30*time.Millisecond, err)
+	}
+
+	require.Len(t, service.liveness, 1)
+	require.Equal(
+		t,
+		proto.Misbehavior_MISBEHAVIOR_UNRESPONSIVE_NODE,
+		service.liveness[0].MisbehaviorType(),
+	)
+	require.Equal(t, 30*time.Millisecond, service.liveness[0].ResponseTime())
+}
+
+func TestLivenessTrackerIgnoresRejectedRequests(t *testing.T) {
+	now := time.Now()
+	tracker, service := newTestLivenessTracker(t, &now)
+
+	rejections := []error{
+		status.Error(codes.InvalidArgument, "invalid envelope"),
+		status.Error(codes.PermissionDenied, "denied"),
+		connect.NewError(connect.CodeResourceExhausted, errors.New("rate limited")),
+		context.Canceled,
+	}
+	for _, err := range rejections {
+		tracker.RecordFailure(livenessNodeID, 10*time.Millisecond, err)
+	}
+	require.Empty(t, service.liveness)
+
+	tracker.RecordFailure(
+		livenessNodeID,
+		10*time.Millisecond,
+		connect.NewError(connect.CodeUnavailable, errors.New("unavailable")),
+	)
+	require.Empty(t, service.liveness, "1 of 5 requests failed")
+}
+
+func TestLivenessTrackerEvictsIdleWindows(t *testing.T) {
+	now := time.Now()
+	tracker, _ := newTestLivenessTracker(t, &now)
+
+	tracker.RecordSuccess(livenessNodeID, 10*time.Millisecond)
+	tracker.RecordSuccess(livenessNodeID+1, 10*time.Millisecond)
+	require.Len(t, tracker.windows, 2)
+
+	now = now.Add(time.Minute)
+	tracker.RecordSuccess(livenessNodeID, 10*time.Millisecond)
+	require.Len(t, tracker.windows, 1)
+	require.Contains(t, tracker.windows, livenessNodeID)
+}
+
+func TestLivenessTrackerSlowNode(t *testing.T) {
+	now := time.Now()
+	tracker, service := newTestLivenessTracker(t, &now)
+
+	tracker.RecordSuccess(livenessNodeID, 10*time.Millisecond)
+	tracker.RecordSuccess(livenessNodeID, 10*time.Millisecond)
+	tracker.RecordSuccess(livenessNodeID, 2*time.Second)
+	tracker.RecordSuccess(livenessNodeID, 2*time.Second)
+
+	require.Len(t, service.liveness, 1)
+	require.Equal(
+		t,
+		proto.Misbehavior_MISBEHAVIOR_SLOW_NODE,
+		service.liveness[0].MisbehaviorType(),
+	)
+
+	// Other nodes are tracked separately.
+	tracker.RecordSuccess(livenessNodeID+1, 2*time.Second)
+	require.Len(t, service.liveness, 1)
+}
+
+func TestLivenessFailureReportProto(t *testing.T) {
+	report, err := NewLivenessFailureReport(
+		livenessNodeID,
+		proto.Misbehavior_MISBEHAVIOR_SLOW_NODE,
+		time.Hour,
+	)
+	require.NoError(t, err)
+
+	unsigned := report.Proto(time.Now())
+	require.True(t, unsigned.GetSubmittedByNode())
+	require.Equal(t, uint32(1<<32-1), unsigned.GetLiveness().GetResponseTimeNs())
+
+	_, err = NewLivenessFailureReport(
+		livenessNodeID,
+		proto.Misbehavior_MISBEHAVIOR_OUT_OF_ORDER,
+		time.Second,
+	)
+	require.Error(t, err)
+}
@@ -2,8 +2,6 @@
 package misbehavior
 
 import (
-	"errors"
-
 	"github.com/xmtp/xmtpd/pkg/utils"
 	"go.uber.org/zap"
 )
@@ -22,7 +20,7 @@ func NewLoggingMisbehaviorService(logger *zap.Logger) *LoggingMisbehaviorService
 
 func (m *LoggingMisbehaviorService) SafetyFailure(report *SafetyFailureReport) error {
 	if report == nil {
-		return errors.New("report is nil")
+		return ErrReportNil
 	}
 	m.logger.Warn(
 		"misbehavior detected",
@@ -33,3 +31,17 @@ func (m *LoggingMisbehaviorService) SafetyFailure(report *SafetyFailureReport) e
 
 	return nil
 }
+
+func (m *LoggingMisbehaviorService) LivenessFailure(report *LivenessFailureReport) error {
+	if report == nil {
+		return ErrReportNil
+	}
+	m.logger.Warn(
+		"misbehavior detected",
+		zap.String("misbehavior_type", report.misbehaviorType.String()),
+		zap.Uint32("misbehaving_node_id", report.misbehavingNodeID),
+		zap.Duration("response_time", report.responseTime),
+	)
+
+	return nil
+}
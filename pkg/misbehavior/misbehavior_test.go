@@ -77,3 +77,34 @@ func TestNewSafetyFailureReportValidations(t *testing.T) {
 	require.Error(t, err)
 	require.Equal(t, "misbehavior type is required", err.Error())
 }
+
+func TestSafetyFailureReportDedupKey(t *testing.T) {
+	env, err := envelopes.NewOriginatorEnvelope(testEnvelopes.CreateOriginatorEnvelope(t, 1, 1))
+	require.NoError(t, err)
+
+	newReport := func(nodeID uint32, misbehaviorType proto.Misbehavior) *SafetyFailureReport {
+		report, err := NewSafetyFailureReport(
+			nodeID,
+			misbehaviorType,
+			true,
+			[]*envelopes.OriginatorEnvelope{env},
+		)
+		require.NoError(t, err)
+		return report
+	}
+
+	withheld := proto.Misbehavior_MISBEHAVIOR_WITHHELD_MESSAGES
+	require.Nil(t, newReport(1, withheld).DedupKey(), "reports without a subject")
+
+	key := newReport(1, withheld).WithSubject([]byte("report")).DedupKey()
+	require.Equal(t, key, newReport(1, withheld).WithSubject([]byte("report")).DedupKey())
+	require.NotEqual(t, key, newReport(2, withheld).WithSubject([]byte("report")).DedupKey())
+	require.NotEqual(t, key, newReport(1, withheld).WithSubject([]byte("other")).DedupKey())
+	require.NotEqual(
+		t,
+		key,
+		newReport(1, proto.Misbehavior_MISBEHAVIOR_DUPLICATE_SEQUENCE_ID).
+			WithSubject([]byte("report")).
+			DedupKey(),
+	)
+}
@@ -0,0 +1,257 @@
+package misbehavior
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/xmtp/xmtpd/pkg/db"
+	"github.com/xmtp/xmtpd/pkg/db/queries"
+	"github.com/xmtp/xmtpd/pkg/proto/identity/associations"
+	proto "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/message_api"
+	"github.com/xmtp/xmtpd/pkg/utils"
+	"go.uber.org/zap"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+// ReportSigner signs the misbehavior reports hosted by this node.
+type ReportSigner interface {
+	NodeID() uint32
+	SignMisbehaviorReport(unsignedReport []byte) (*associations.RecoverableEcdsaSignature, error)
+}
+
+// QueryFilter narrows down the misbehavior reports returned by QueryReports.
+// Zero values disable the corresponding filter.
+type QueryFilter struct {
+	AfterNs           uint64
+	AfterID           uint64
+	BeforeNs          uint64
+	MisbehavingNodeID uint32
+	Type              proto.Misbehavior
+	Limit             int32
+}
+
+// ReportStore persists and serves misbehavior reports.
+type ReportStore interface {
+	StoreReport(
+		ctx context.Context,
+		reporterNodeID uint32,
+		report *proto.UnsignedMisbehaviorReport,
+	) (*proto.MisbehaviorReport, error)
+	QueryReports(ctx context.Context, filter QueryFilter) ([]*proto.MisbehaviorReport, error)
+}
+
+// PersistentMisbehaviorService provides an implementation of the MisbehaviorService interface that
+// signs misbehavior reports and stores them in the database, where they can be queried by operators.
+type PersistentMisbehaviorService struct {
+	ctx    context.Context
+	logger *zap.Logger
+	db     *db.Handler
+	signer ReportSigner
+}
+
+var (
+	_ MisbehaviorService = (*PersistentMisbehaviorService)(nil)
+	_ ReportStore        = (*PersistentMisbehaviorService)(nil)
+)
+
+func NewPersistentMisbehaviorService(
+	ctx context.Context,
+	logger *zap.Logger,
+	db *db.Handler,
+	signer ReportSigner,
+) *PersistentMisbehaviorService {
+	return &PersistentMisbehaviorService{
+		ctx:    ctx,
+		logger: logger.Named(utils.MisbehaviorLoggerName),
+		db:     db,
+		signer: signer,
+	}
+}
+
+// SafetyFailure stores a safety failure detected by this node. A report with a subject that
+// was already reported is dropped.
+func (m *PersistentMisbehaviorService) SafetyFailure(report *SafetyFailureReport) error {
+	if report == nil {
+		return ErrReportNil
+	}
+
+	stored, err := m.storeReport(
+		m.ctx,
+		m.signer.NodeID(),
+		report.Proto(time.Now()),
+		report.DedupKey(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if stored == nil {
+		m.logger.Debug(
+			"misbehavior already reported",
+			zap.String("misbehavior_type", report.misbehaviorType.String()),
+			zap.Uint32("misbehaving_node_id", report.misbehavingNodeID),
+		)
+		return nil
+	}
+
+	m.logger.Warn(
+		"misbehavior detected",
+		zap.String("misbehavior_type", report.misbehaviorType.String()),
+		zap.Uint32("misbehaving_node_id", report.misbehavingNodeID),
+		zap.Bool("submitted_by_node", report.submittedByNode),
+	)
+
+	return nil
+}
+
+// LivenessFailure stores a liveness failure detected by this node.
+func (m *PersistentMisbehaviorService) LivenessFailure(report *LivenessFailureReport) error {
+	if report == nil {
+		return ErrReportNil
+	}
+
+	m.logger.Warn(
+		"misbehavior detected",
+		zap.String("misbehavior_type", report.misbehaviorType.String()),
+		zap.Uint32("misbehaving_node_id", report.misbehavingNodeID),
+		zap.Duration("response_time", report.responseTime),
+	)
+
+	_, err := m.StoreReport(m.ctx, m.signer.NodeID(), report.Proto(time.Now()))
+	return err
+}
+
+// StoreReport signs the report as the hosting node and stores it. No validations are performed.
+func (m *PersistentMisbehaviorService) StoreReport(
+	ctx context.Context,
+	reporterNodeID uint32,
+	report *proto.UnsignedMisbehaviorReport,
+) (*proto.MisbehaviorReport, error) {
+	return m.storeReport(ctx, reporterNodeID, report, nil)
+}
+
+// storeReport signs and stores the report. It returns nil, without an error, if a report with
+// the same dedup key is already stored.
+func (m *PersistentMisbehaviorService) storeReport(
+	ctx context.Context,
+	reporterNodeID uint32,
+	report *proto.UnsignedMisbehaviorReport,
+	dedupKey []byte,
+) (*proto.MisbehaviorReport, error) {
+	if report == nil {
+		return nil, ErrReportNil
+	}
+
+	reporterID, err := utils.Uint32ToInt32(reporterNodeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reporter node id: %w", err)
+	}
+
+	misbehavingNodeID, err := utils.Uint32ToInt32(report.GetMisbehavingNodeId())
+	if err != nil {
+		return nil, fmt.Errorf("invalid misbehaving node id: %w", err)
+	}
+
+	unsignedBytes, err := protobuf.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := m.signer.SignMisbehaviorReport(unsignedBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	serverTimeNs := time.Now().UnixNano()
+
+	id, err := m.db.WriteQuery().InsertMisbehaviorReport(ctx, queries.InsertMisbehaviorReportParams{
+		ServerTimeNs:      serverTimeNs,
+		ReporterNodeID:    reporterID,
+		MisbehavingNodeID: misbehavingNodeID,
+		MisbehaviorType:   int16(report.GetType()),
+		SubmittedByNode:   report.GetSubmittedByNode(),
+		UnsignedReport:    unsignedBytes,
+		Signature:         signature.GetBytes(),
+		DedupKey:          dedupKey,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.MisbehaviorReport{
+		ServerTimeNs:              uint64(serverTimeNs),
+		UnsignedMisbehaviorReport: unsignedBytes,
+		Signature:                 signature,
+		Id:                        uint64(id),
+	}, nil
+}
+
+// QueryReports returns stored reports ordered by server time and ID, oldest first.
+func (m *PersistentMisbehaviorService) QueryReports(
+	ctx context.Context,
+	filter QueryFilter,
+) ([]*proto.MisbehaviorReport, error) {
+	if filter.Limit <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+
+	afterNs, err := utils.Uint64ToInt64(filter.AfterNs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid after_ns: %w", err)
+	}
+
+	// Without an ID, every report stored at AfterNs is skipped.
+	afterID := int64(math.MaxInt64)
+	if filter.AfterID != 0 {
+		afterID, err = utils.Uint64ToInt64(filter.AfterID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid after_id: %w", err)
+		}
+	}
+
+	beforeNs, err := utils.Uint64ToInt64(filter.BeforeNs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid before_ns: %w", err)
+	}
+
+	misbehavingNodeID, err := utils.Uint32ToInt32(filter.MisbehavingNodeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid misbehaving node id: %w", err)
+	}
+
+	rows, err := m.db.ReadQuery().SelectMisbehaviorReports(
+		ctx,
+		queries.SelectMisbehaviorReportsParams{
+			AfterNs:           afterNs,
+			AfterID:           afterID,
+			BeforeNs:          beforeNs,
+			MisbehavingNodeID: misbehavingNodeID,
+			MisbehaviorType:   int16(filter.Type),
+			RowLimit:          filter.Limit,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]*proto.MisbehaviorReport, 0, len(rows))
+	for _, row := range rows {
+		reports = append(reports, &proto.MisbehaviorReport{
+			ServerTimeNs:              uint64(row.ServerTimeNs),
+			UnsignedMisbehaviorReport: row.UnsignedReport,
+			Signature: &associations.RecoverableEcdsaSignature{
+				Bytes: row.Signature,
+			},
+			Id: uint64(row.ID),
+		})
+	}
+
+	return reports, nil
+}
@@ -0,0 +1,55 @@
+package misbehavior
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/db/queries"
+	proto "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/message_api"
+	"github.com/xmtp/xmtpd/pkg/testutils"
+)
+
+func TestQueryReports_PagesThroughReportsAtTheSameServerTime(t *testing.T) {
+	ctx := context.Background()
+	db, _ := testutils.NewDB(t, ctx)
+	service := NewPersistentMisbehaviorService(ctx, testutils.NewLog(t), db, nil)
+
+	for range 3 {
+		_, err := db.WriteQuery().InsertMisbehaviorReport(ctx, queries.InsertMisbehaviorReportParams{
+			ServerTimeNs:      100,
+			ReporterNodeID:    1,
+			MisbehavingNodeID: 2,
+			MisbehaviorType:   int16(proto.Misbehavior_MISBEHAVIOR_OUT_OF_ORDER),
+			UnsignedReport:    []byte("report"),
+			Signature:         []byte("signature"),
+		})
+		require.NoError(t, err)
+	}
+
+	var (
+		filter = QueryFilter{Limit: 2}
+		ids    []uint64
+	)
+	for {
+		reports, err := service.QueryReports(ctx, filter)
+		require.NoError(t, err)
+		if len(reports) == 0 {
+			break
+		}
+		for _, report := range reports {
+			ids = append(ids, report.GetId())
+		}
+
+		last := reports[len(reports)-1]
+		filter.AfterNs, filter.AfterID = last.GetServerTimeNs(), last.GetId()
+	}
+	require.Len(t, ids, 3)
+	require.Less(t, ids[0], ids[1])
+	require.Less(t, ids[1], ids[2])
+
+	// Without an ID, every report at the server time is skipped.
+	reports, err := service.QueryReports(ctx, QueryFilter{AfterNs: 100, Limit: 10})
+	require.NoError(t, err)
+	require.Empty(t, reports)
+}
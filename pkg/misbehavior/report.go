@@ -1,9 +1,13 @@
 package misbehavior
 
 import (
+	"encoding/binary"
 	"errors"
+	"math"
+	"time"
 
 	"github.com/xmtp/xmtpd/pkg/envelopes"
+	envelopesProto "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/envelopes"
 	proto "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/message_api"
 )
 
@@ -12,6 +16,7 @@ type SafetyFailureReport struct {
 	misbehaviorType   proto.Misbehavior
 	submittedByNode   bool
 	envelopes         []*envelopes.OriginatorEnvelope
+	subject           []byte
 }
 
 func NewSafetyFailureReport(
@@ -39,3 +44,110 @@ func NewSafetyFailureReport(
 		envelopes:         envs,
 	}, nil
 }
+
+// WithSubject sets what the misbehavior is about, such as a payer report. Reports of the same
+// misbehavior of a node about the same subject are only stored once.
+func (r *SafetyFailureReport) WithSubject(subject []byte) *SafetyFailureReport {
+	r.subject = subject
+	return r
+}
+
+// DedupKey identifies the misbehavior the report is about, or is nil if the report has no
+// subject and is not deduplicated.
+func (r *SafetyFailureReport) DedupKey() []byte {
+	if r.subject == nil {
+		return nil
+	}
+
+	key := binary.BigEndian.AppendUint16(nil, uint16(r.misbehaviorType))
+	key = binary.BigEndian.AppendUint32(key, r.misbehavingNodeID)
+	return append(key, r.subject...)
+}
+
+func (r *SafetyFailureReport) MisbehavingNodeID() uint32 {
+	return r.misbehavingNodeID
+}
+
+func (r *SafetyFailureReport) MisbehaviorType() proto.Misbehavior {
+	return r.misbehaviorType
+}
+
+// Proto builds the unsigned wire representation of the report, stamped with the reporter's time.
+func (r *SafetyFailureReport) Proto(reporterTime time.Time) *proto.UnsignedMisbehaviorReport {
+	envs := make([]*envelopesProto.OriginatorEnvelope, 0, len(r.envelopes))
+	for _, env := range r.envelopes {
+		envs = append(envs, env.Proto())
+	}
+
+	return &proto.UnsignedMisbehaviorReport{
+		ReporterTimeNs:    uint64(reporterTime.UnixNano()),
+		MisbehavingNodeId: r.misbehavingNodeID,
+		Type:              r.misbehaviorType,
+		Failure: &proto.UnsignedMisbehaviorReport_Safety{
+			Safety: &proto.SafetyFailure{
+				Envelopes: envs,
+			},
+		},
+		SubmittedByNode: r.submittedByNode,
+	}
+}
+
+type LivenessFailureReport struct {
+	misbehavingNodeID uint32
+	misbehaviorType   proto.Misbehavior
+	responseTime      time.Duration
+}
+
+func NewLivenessFailureReport(
+	misbehavingNodeID uint32,
+	misbehaviorType proto.Misbehavior,
+	responseTime time.Duration,
+) (*LivenessFailureReport, error) {
+	if misbehavingNodeID == 0 {
+		return nil, errors.New("misbehaving node id is required")
+	}
+
+	if !IsLivenessMisbehavior(misbehaviorType) {
+		return nil, errors.New("misbehavior type is not a liveness failure")
+	}
+
+	return &LivenessFailureReport{
+		misbehavingNodeID: misbehavingNodeID,
+		misbehaviorType:   misbehaviorType,
+		responseTime:      responseTime,
+	}, nil
+}
+
+func (r *LivenessFailureReport) MisbehavingNodeID() uint32 {
+	return r.misbehavingNodeID
+}
+
+func (r *LivenessFailureReport) MisbehaviorType() proto.Misbehavior {
+	return r.misbehaviorType
+}
+
+func (r *LivenessFailureReport) ResponseTime() time.Duration {
+	return r.responseTime
+}
+
+// Proto builds the unsigned wire representation of the report, stamped with the reporter's time.
+// Liveness failures are always submitted by nodes.
+func (r *LivenessFailureReport) Proto(reporterTime time.Time) *proto.UnsignedMisbehaviorReport {
+	// The wire format only fits ~4.29s of response time, longer responses are capped.
+	responseTimeNs := uint32(math.MaxUint32)
+	if r.responseTime >= 0 && r.responseTime.Nanoseconds() < math.MaxUint32 {
+		responseTimeNs = uint32(r.responseTime.Nanoseconds())
+	}
+
+	return &proto.UnsignedMisbehaviorReport{
+		ReporterTimeNs:    uint64(reporterTime.UnixNano()),
+		MisbehavingNodeId: r.misbehavingNodeID,
+		Type:              r.misbehaviorType,
+		Failure: &proto.UnsignedMisbehaviorReport_Liveness{
+			Liveness: &proto.LivenessFailure{
+				ResponseTimeNs: responseTimeNs,
+			},
+		},
+		SubmittedByNode: true,
+	}
+}
@@ -0,0 +1,157 @@
+package misbehavior
+
+import (
+	"errors"
+	"fmt"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/xmtp/xmtpd/pkg/envelopes"
+	proto "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/message_api"
+	"github.com/xmtp/xmtpd/pkg/registry"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+const (
+	// MaxEvidenceEnvelopes bounds the envelopes attached to a safety failure. Every
+	// misbehavior type is provable with a handful of envelopes.
+	MaxEvidenceEnvelopes = 16
+	// MaxEvidenceBytes bounds the total encoded size of the envelopes attached to a safety
+	// failure, since reports are stored and served back to any client.
+	MaxEvidenceBytes = 1 << 20
+)
+
+var (
+	ErrReportNil                = errors.New("report is nil")
+	ErrMissingReporterTime      = errors.New("reporter time is required")
+	ErrMissingMisbehavingNodeID = errors.New("misbehaving node id is required")
+	ErrMissingMisbehaviorType   = errors.New("misbehavior type is required")
+	ErrMissingLivenessFailure   = errors.New("liveness failure is required for this misbehavior type")
+	ErrMissingSafetyFailure     = errors.New("safety failure is required for this misbehavior type")
+	ErrNoEnvelopes              = errors.New("no envelopes provided")
+	ErrTooManyEnvelopes         = fmt.Errorf("more than %d envelopes", MaxEvidenceEnvelopes)
+	ErrEvidenceTooLarge         = fmt.Errorf("envelopes exceed %d bytes", MaxEvidenceBytes)
+)
+
+// LimitEvidence returns the leading envelopes that fit within MaxEvidenceEnvelopes and
+// MaxEvidenceBytes, so that a report with more evidence than needed is still accepted.
+func LimitEvidence(envs []*envelopes.OriginatorEnvelope) []*envelopes.OriginatorEnvelope {
+	evidenceBytes := 0
+	for i, env := range envs {
+		evidenceBytes += protobuf.Size(env.Proto())
+		if i == MaxEvidenceEnvelopes || (i > 0 && evidenceBytes > MaxEvidenceBytes) {
+			return envs[:i]
+		}
+	}
+
+	return envs
+}
+
+// IsLivenessMisbehavior reports whether the misbehavior type is proven by a liveness failure.
+func IsLivenessMisbehavior(misbehaviorType proto.Misbehavior) bool {
+	switch misbehaviorType {
+	case proto.Misbehavior_MISBEHAVIOR_UNRESPONSIVE_NODE,
+		proto.Misbehavior_MISBEHAVIOR_SLOW_NODE,
+		proto.Misbehavior_MISBEHAVIOR_FAILED_REQUEST:
+		return true
+	default:
+		return false
+	}
+}
+
+// ValidateReport performs all static validation on a misbehavior report received from the network.
+// Safety failures must carry envelopes signed by the misbehaving node, so that a reporter
+// cannot attribute fabricated envelopes to another node.
+func ValidateReport(
+	report *proto.UnsignedMisbehaviorReport,
+	nodeRegistry registry.NodeRegistry,
+) error {
+	if report == nil {
+		return ErrReportNil
+	}
+
+	if report.GetReporterTimeNs() == 0 {
+		return ErrMissingReporterTime
+	}
+
+	if report.GetMisbehavingNodeId() == 0 {
+		return ErrMissingMisbehavingNodeID
+	}
+
+	misbehaviorType := report.GetType()
+	if misbehaviorType == proto.Misbehavior_MISBEHAVIOR_UNSPECIFIED {
+		return ErrMissingMisbehaviorType
+	}
+
+	if _, ok := proto.Misbehavior_name[int32(misbehaviorType)]; !ok {
+		return fmt.Errorf("unknown misbehavior type: %d", misbehaviorType)
+	}
+
+	node, err := nodeRegistry.GetNode(report.GetMisbehavingNodeId())
+	if err != nil {
+		return fmt.Errorf("unknown misbehaving node %d: %w", report.GetMisbehavingNodeId(), err)
+	}
+
+	if IsLivenessMisbehavior(misbehaviorType) {
+		if report.GetLiveness() == nil {
+			return ErrMissingLivenessFailure
+		}
+		return nil
+	}
+
+	if report.GetSafety() == nil {
+		return ErrMissingSafetyFailure
+	}
+
+	return validateSafetyEnvelopes(report.GetSafety(), node)
+}
+
+func validateSafetyEnvelopes(safety *proto.SafetyFailure, node *registry.Node) error {
+	if len(safety.GetEnvelopes()) == 0 {
+		return ErrNoEnvelopes
+	}
+
+	if len(safety.GetEnvelopes()) > MaxEvidenceEnvelopes {
+		return ErrTooManyEnvelopes
+	}
+
+	evidenceBytes := 0
+	for _, envProto := range safety.GetEnvelopes() {
+		evidenceBytes += protobuf.Size(envProto)
+	}
+	if evidenceBytes > MaxEvidenceBytes {
+		return ErrEvidenceTooLarge
+	}
+
+	if node.SigningKey == nil {
+		return fmt.Errorf("node %d has no signing key", node.NodeID)
+	}
+
+	expectedSigner := ethcrypto.PubkeyToAddress(*node.SigningKey)
+
+	for i, envProto := range safety.GetEnvelopes() {
+		env, err := envelopes.NewOriginatorEnvelope(envProto)
+		if err != nil {
+			return fmt.Errorf("envelope %d is invalid: %w", i, err)
+		}
+
+		if env.OriginatorNodeID() != node.NodeID {
+			return fmt.Errorf(
+				"envelope %d was originated by node %d, not the misbehaving node %d",
+				i,
+				env.OriginatorNodeID(),
+				node.NodeID,
+			)
+		}
+
+		signer, err := env.RecoverSigner()
+		if err != nil {
+			return fmt.Errorf("envelope %d has an invalid originator signature: %w", i, err)
+		}
+
+		if *signer != expectedSigner {
+			return fmt.Errorf("envelope %d was not signed by node %d", i, node.NodeID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package misbehavior_test
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	envUtils "github.com/xmtp/xmtpd/pkg/envelopes"
+	"github.com/xmtp/xmtpd/pkg/misbehavior"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/envelopes"
+	proto "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/message_api"
+	r "github.com/xmtp/xmtpd/pkg/registry"
+	"github.com/xmtp/xmtpd/pkg/testutils"
+	envelopeTestUtils "github.com/xmtp/xmtpd/pkg/testutils/envelopes"
+	registryTestUtils "github.com/xmtp/xmtpd/pkg/testutils/registry"
+)
+
+const misbehavingNodeID = uint32(100)
+
+func buildSafetyReport(
+	misbehaviorType proto.Misbehavior,
+	envs ...*envelopes.OriginatorEnvelope,
+) *proto.UnsignedMisbehaviorReport {
+	return &proto.UnsignedMisbehaviorReport{
+		ReporterTimeNs:    uint64(time.Now().UnixNano()),
+		MisbehavingNodeId: misbehavingNodeID,
+		Type:              misbehaviorType,
+		Failure: &proto.UnsignedMisbehaviorReport_Safety{
+			Safety: &proto.SafetyFailure{Envelopes: envs},
+		},
+		SubmittedByNode: true,
+	}
+}
+
+func TestValidateReport(t *testing.T) {
+	nodeKey := testutils.RandomPrivateKey(t)
+	otherKey := testutils.RandomPrivateKey(t)
+	nodeRegistry := registryTestUtils.CreateMockRegistry(t, []r.Node{
+		registryTestUtils.CreateNode(misbehavingNodeID, 5050, nodeKey),
+	})
+	nodeRegistry.On("GetNode", uint32(200)).Maybe().Return(nil, errors.New("node not found"))
+
+	signedEnv := func(
+		key *ecdsa.PrivateKey,
+		originator uint32,
+		seq uint64,
+	) *envelopes.OriginatorEnvelope {
+		return envelopeTestUtils.CreateSignedOriginatorEnvelope(t, key, originator, seq)
+	}
+
+	tests := []struct {
+		name    string
+		report  *proto.UnsignedMisbehaviorReport
+		wantErr bool
+	}{
+		{
+			name: "valid safety failure",
+			report: buildSafetyReport(
+				proto.Misbehavior_MISBEHAVIOR_DUPLICATE_SEQUENCE_ID,
+				signedEnv(nodeKey, misbehavingNodeID, 1),
+				signedEnv(nodeKey, misbehavingNodeID, 1),
+			),
+		},
+		{
+			name: "valid liveness failure",
+			report: &proto.UnsignedMisbehaviorReport{
+				ReporterTimeNs:    uint64(time.Now().UnixNano()),
+				MisbehavingNodeId: misbehavingNodeID,
+				Type:              proto.Misbehavior_MISBEHAVIOR_UNRESPONSIVE_NODE,
+				Failure: &proto.UnsignedMisbehaviorReport_Liveness{
+					Liveness: &proto.LivenessFailure{ResponseTimeNs: 1000},
+				},
+				SubmittedByNode: true,
+			},
+		},
+		{
+			name:    "nil report",
+			report:  nil,
+			wantErr: true,
+		},
+		{
+			name: "missing reporter time",
+			report: func() *proto.UnsignedMisbehaviorReport {
+				report := buildSafetyReport(
+					proto.Misbehavior_MISBEHAVIOR_OUT_OF_ORDER,
+					signedEnv(nodeKey, misbehavingNodeID, 1),
+				)
+				report.ReporterTimeNs = 0
+				return report
+			}(),
+			wantErr: true,
+		},
+		{
+			name:    "unspecified type",
+			report:  buildSafetyReport(proto.Misbehavior_MISBEHAVIOR_UNSPECIFIED),
+			wantErr: true,
+		},
+		{
+			name: "unknown misbehaving node",
+			report: func() *proto.UnsignedMisbehaviorReport {
+				report := buildSafetyReport(
+					proto.Misbehavior_MISBEHAVIOR_OUT_OF_ORDER,
+					signedEnv(nodeKey, misbehavingNodeID, 1),
+				)
+				report.MisbehavingNodeId = 200
+				return report
+			}(),
+			wantErr: true,
+		},
+		{
+			name:    "safety failure without envelopes",
+			report:  buildSafetyReport(proto.Misbehavior_MISBEHAVIOR_OUT_OF_ORDER),
+			wantErr: true,
+		},
+		{
+			name: "liveness type with safety failure",
+			report: buildSafetyReport(
+				proto.Misbehavior_MISBEHAVIOR_SLOW_NODE,
+				signedEnv(nodeKey, misbehavingNodeID, 1),
+			),
+			wantErr: true,
+		},
+		{
+			name: "envelope from another originator",
+			report: buildSafetyReport(
+				proto.Misbehavior_MISBEHAVIOR_OUT_OF_ORDER,
+				signedEnv(nodeKey, 200, 1),
+			),
+			wantErr: true,
+		},
+		{
+			name: "envelope not signed by the misbehaving node",
+			report: buildSafetyReport(
+				proto.Misbehavior_MISBEHAVIOR_OUT_OF_ORDER,
+				signedEnv(otherKey, misbehavingNodeID, 1),
+			),
+			wantErr: true,
+		},
+		{
+			name: "unsigned envelope",
+			report: buildSafetyReport(
+				proto.Misbehavior_MISBEHAVIOR_OUT_OF_ORDER,
+				envelopeTestUtils.CreateOriginatorEnvelope(t, misbehavingNodeID, 1),
+			),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := misbehavior.ValidateReport(tt.report, nodeRegistry)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateReportEvidenceLimits(t *testing.T) {
+	nodeKey := testutils.RandomPrivateKey(t)
+	nodeRegistry := registryTestUtils.CreateMockRegistry(t, []r.Node{
+		registryTestUtils.CreateNode(misbehavingNodeID, 5050, nodeKey),
+	})
+
+	envs := make([]*envelopes.OriginatorEnvelope, misbehavior.MaxEvidenceEnvelopes+1)
+	for i := range envs {
+		envs[i] = envelopeTestUtils.CreateSignedOriginatorEnvelope(
+			t,
+			nodeKey,
+			misbehavingNodeID,
+			uint64(i+1),
+		)
+	}
+
+	err := misbehavior.ValidateReport(
+		buildSafetyReport(proto.Misbehavior_MISBEHAVIOR_OUT_OF_ORDER, envs...),
+		nodeRegistry,
+	)
+	require.ErrorIs(t, err, misbehavior.ErrTooManyEnvelopes)
+
+	wrapped := make([]*envUtils.OriginatorEnvelope, len(envs))
+	for i, env := range envs {
+		wrapped[i], err = envUtils.NewOriginatorEnvelope(env)
+		require.NoError(t, err)
+	}
+	limited := misbehavior.LimitEvidence(wrapped)
+	require.Len(t, limited, misbehavior.MaxEvidenceEnvelopes)
+
+	limitedProtos := make([]*envelopes.OriginatorEnvelope, len(limited))
+	for i, env := range limited {
+		limitedProtos[i] = env.Proto()
+	}
+	require.NoError(t, misbehavior.ValidateReport(
+		buildSafetyReport(proto.Misbehavior_MISBEHAVIOR_OUT_OF_ORDER, limitedProtos...),
+		nodeRegistry,
+	))
+
+	err = misbehavior.ValidateReport(
+		buildSafetyReport(
+			proto.Misbehavior_MISBEHAVIOR_OUT_OF_ORDER,
+			&envelopes.OriginatorEnvelope{
+				UnsignedOriginatorEnvelope: make([]byte, misbehavior.MaxEvidenceBytes),
+			},
+		),
+		nodeRegistry,
+	)
+	require.ErrorIs(t, err, misbehavior.ErrEvidenceTooLarge)
+}
@@ -0,0 +1,64 @@
+package mlsvalidate
+
+import (
+	associations "github.com/xmtp/xmtpd/pkg/proto/identity/associations"
+	"google.golang.org/protobuf/proto"
+)
+
+// DiffAssociationStates returns the members added and removed going from the previous to the
+// current association state. A nil previous state has no members.
+func DiffAssociationStates(
+	previous *associations.AssociationState,
+	current *associations.AssociationState,
+) (*associations.AssociationStateDiff, error) {
+	previousMembers, err := memberKeys(previous)
+	if err != nil {
+		return nil, err
+	}
+	currentMembers, err := memberKeys(current)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &associations.AssociationStateDiff{}
+	for _, member := range current.GetMembers() {
+		key, err := memberKey(member.GetKey())
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := previousMembers[key]; !ok {
+			diff.NewMembers = append(diff.NewMembers, member.GetKey())
+		}
+	}
+	for _, member := range previous.GetMembers() {
+		key, err := memberKey(member.GetKey())
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := currentMembers[key]; !ok {
+			diff.RemovedMembers = append(diff.RemovedMembers, member.GetKey())
+		}
+	}
+
+	return diff, nil
+}
+
+func memberKeys(state *associations.AssociationState) (map[string]struct{}, error) {
+	keys := make(map[string]struct{}, len(state.GetMembers()))
+	for _, member := range state.GetMembers() {
+		key, err := memberKey(member.GetKey())
+		if err != nil {
+			return nil, err
+		}
+		keys[key] = struct{}{}
+	}
+	return keys, nil
+}
+
+func memberKey(identifier *associations.MemberIdentifier) (string, error) {
+	key, err := proto.MarshalOptions{Deterministic: true}.Marshal(identifier)
+	if err != nil {
+		return "", err
+	}
+	return string(key), nil
+}
@@ -0,0 +1,46 @@
+package mlsvalidate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/proto/identity/associations"
+	"google.golang.org/protobuf/proto"
+)
+
+func ethereumMember(address string) *associations.MemberMap {
+	identifier := &associations.MemberIdentifier{
+		Kind: &associations.MemberIdentifier_EthereumAddress{EthereumAddress: address},
+	}
+	return &associations.MemberMap{
+		Key:   identifier,
+		Value: &associations.Member{Identifier: identifier},
+	}
+}
+
+func TestDiffAssociationStates(t *testing.T) {
+	previous := &associations.AssociationState{
+		Members: []*associations.MemberMap{ethereumMember("0x1"), ethereumMember("0x2")},
+	}
+	current := &associations.AssociationState{
+		Members: []*associations.MemberMap{ethereumMember("0x2"), ethereumMember("0x3")},
+	}
+
+	diff, err := DiffAssociationStates(previous, current)
+	require.NoError(t, err)
+	require.Len(t, diff.GetNewMembers(), 1)
+	require.True(t, proto.Equal(ethereumMember("0x3").GetKey(), diff.GetNewMembers()[0]))
+	require.Len(t, diff.GetRemovedMembers(), 1)
+	require.True(t, proto.Equal(ethereumMember("0x1").GetKey(), diff.GetRemovedMembers()[0]))
+}
+
+func TestDiffAssociationStatesFromNothing(t *testing.T) {
+	current := &associations.AssociationState{
+		Members: []*associations.MemberMap{ethereumMember("0x1")},
+	}
+
+	diff, err := DiffAssociationStates(nil, current)
+	require.NoError(t, err)
+	require.Len(t, diff.GetNewMembers(), 1)
+	require.Empty(t, diff.GetRemovedMembers())
+}
@@ -2,6 +2,7 @@ package mlsvalidate
 
 import (
 	"context"
+	"errors"
 
 	"github.com/xmtp/xmtpd/pkg/db/queries"
 	identity_proto "github.com/xmtp/xmtpd/pkg/proto/identity"
@@ -26,6 +27,12 @@ type AssociationStateResult struct {
 	StateDiff        *associations.AssociationStateDiff `protobuf:"bytes,2,opt,name=state_diff,json=stateDiff,proto3"               json:"state_diff,omitempty"`
 }
 
+// ErrSnapshotValidationUnsupported is returned when the validation service is not known to
+// accept an association state in place of the updates it was built from.
+var ErrSnapshotValidationUnsupported = errors.New(
+	"snapshot validation is not enabled for the validation service",
+)
+
 type MLSValidationService interface {
 	ValidateKeyPackages(
 		ctx context.Context,
@@ -45,4 +52,12 @@ type MLSValidationService interface {
 		oldUpdates []queries.SelectGatewayEnvelopesByTopicsRow,
 		newIdentityUpdate *associations.IdentityUpdate,
 	) (*AssociationStateResult, error)
+	// GetAssociationStateFromSnapshot applies newIdentityUpdate to a stored association
+	// state, without replaying the updates it was built from. It returns
+	// ErrSnapshotValidationUnsupported unless snapshot validation is enabled.
+	GetAssociationStateFromSnapshot(
+		ctx context.Context,
+		snapshot *associations.AssociationState,
+		newIdentityUpdate *associations.IdentityUpdate,
+	) (*AssociationStateResult, error)
 }
@@ -21,7 +21,8 @@ import (
 )
 
 type MLSValidationServiceImpl struct {
-	grpcClient svc.ValidationApiClient
+	grpcClient         svc.ValidationApiClient
+	snapshotValidation bool
 }
 
 var _ MLSValidationService = (*MLSValidationServiceImpl)(nil)
@@ -51,7 +52,8 @@ func NewMLSValidationService(
 	}()
 
 	return &MLSValidationServiceImpl{
-		grpcClient: svc.NewValidationApiClient(conn),
+		grpcClient:         svc.NewValidationApiClient(conn),
+		snapshotValidation: cfg.SnapshotValidation,
 	}, nil
 }
 
@@ -81,8 +83,44 @@ func (s *MLSValidationServiceImpl) GetAssociationStateFromEnvelopes(
 	oldUpdateEnvelopes []queries.SelectGatewayEnvelopesByTopicsRow,
 	newUpdate *associations.IdentityUpdate,
 ) (*AssociationStateResult, error) {
-	oldUpdates := make([]*associations.IdentityUpdate, len(oldUpdateEnvelopes))
-	for i, update := range oldUpdateEnvelopes {
+	oldUpdates, err := IdentityUpdatesFromEnvelopes(oldUpdateEnvelopes)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetAssociationState(ctx, oldUpdates, []*associations.IdentityUpdate{newUpdate})
+}
+
+func (s *MLSValidationServiceImpl) GetAssociationStateFromSnapshot(
+	ctx context.Context,
+	snapshot *associations.AssociationState,
+	newUpdate *associations.IdentityUpdate,
+) (*AssociationStateResult, error) {
+	if !s.snapshotValidation {
+		return nil, ErrSnapshotValidationUnsupported
+	}
+
+	response, err := s.grpcClient.GetAssociationState(ctx, &svc.GetAssociationStateRequest{
+		OldState:   snapshot,
+		NewUpdates: []*associations.IdentityUpdate{newUpdate},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &AssociationStateResult{
+		AssociationState: response.GetAssociationState(),
+		StateDiff:        response.GetStateDiff(),
+	}, nil
+}
+
+// IdentityUpdatesFromEnvelopes returns the identity updates of stored identity update
+// envelopes.
+func IdentityUpdatesFromEnvelopes(
+	rows []queries.SelectGatewayEnvelopesByTopicsRow,
+) ([]*associations.IdentityUpdate, error) {
+	updates := make([]*associations.IdentityUpdate, len(rows))
+	for i, update := range rows {
 		originatorEnvelope, err := envelopes.NewOriginatorEnvelopeFromBytes(
 			update.OriginatorEnvelope,
 		)
@@ -97,10 +135,10 @@ func (s *MLSValidationServiceImpl) GetAssociationStateFromEnvelopes(
 			return nil, errors.New("identity update is nil")
 		}
 
-		oldUpdates[i] = payload.IdentityUpdate
+		updates[i] = payload.IdentityUpdate
 	}
 
-	return s.GetAssociationState(ctx, oldUpdates, []*associations.IdentityUpdate{newUpdate})
+	return updates, nil
 }
 
 func (s *MLSValidationServiceImpl) ValidateKeyPackages(
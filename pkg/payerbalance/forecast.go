@@ -0,0 +1,214 @@
+// Package payerbalance forecasts when payers will run out of funds, and notifies when a payer's
+// balance crosses into a different level, so that payers can top up before publishes are
+// rejected.
+package payerbalance
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/xmtp/xmtpd/pkg/currency"
+	"github.com/xmtp/xmtpd/pkg/db"
+	"github.com/xmtp/xmtpd/pkg/db/queries"
+	"github.com/xmtp/xmtpd/pkg/utils"
+)
+
+// Level classifies how close a payer is to running out of funds.
+type Level int16
+
+const (
+	LevelOK Level = iota
+	// LevelLow means the payer is forecast to run out of funds within the warning threshold.
+	LevelLow
+	// LevelCritical means the payer is forecast to run out of funds within the critical threshold.
+	LevelCritical
+	// LevelDepleted means the payer has no funds available, and its publishes are rejected.
+	LevelDepleted
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelOK:
+		return "ok"
+	case LevelLow:
+		return "low"
+	case LevelCritical:
+		return "critical"
+	case LevelDepleted:
+		return "depleted"
+	default:
+		return "unknown"
+	}
+}
+
+// levelRecoveryMargin is how far, as a fraction of a threshold, a payer's time to depletion must
+// clear the threshold before the payer recovers to a less severe level. It keeps a payer whose
+// spend rate hovers near a threshold from flapping between levels on every run.
+const levelRecoveryMargin = 0.1
+
+// Thresholds are the forecast times to depletion below which a payer's balance is low or
+// critical.
+type Thresholds struct {
+	Warning  time.Duration
+	Critical time.Duration
+}
+
+// Forecast is a payer's available balance and how long it will last at its recent spend rate.
+type Forecast struct {
+	PayerID      int32
+	PayerAddress string
+	// Balance is the settled balance from the payer ledger.
+	Balance currency.PicoDollar
+	// UnsettledUsage is the spend that has not been settled on chain yet.
+	UnsettledUsage currency.PicoDollar
+	// Available is the balance minus the unsettled usage, which publishes are checked against.
+	Available currency.PicoDollar
+	// SpendPerHour is the payer's average spend over the spend window.
+	SpendPerHour currency.PicoDollar
+	// TimeToDepletion is how long the available balance lasts at the spend rate. Zero if the
+	// payer is not spending or has no funds available.
+	TimeToDepletion time.Duration
+	Level           Level
+	ComputedAt      time.Time
+}
+
+// Forecaster computes balance forecasts from the payer ledger and usage history.
+type Forecaster struct {
+	db          *db.Handler
+	spendWindow time.Duration
+	thresholds  Thresholds
+}
+
+func NewForecaster(db *db.Handler, spendWindow time.Duration, thresholds Thresholds) *Forecaster {
+	return &Forecaster{
+		db:          db,
+		spendWindow: spendWindow,
+		thresholds:  thresholds,
+	}
+}
+
+// Forecast returns the forecast for a single payer. Payers without any ledger events or usage
+// have nothing available, and are forecast as depleted.
+func (f *Forecaster) Forecast(ctx context.Context, payerID int32) (Forecast, error) {
+	now := time.Now()
+
+	rows, err := f.db.ReadQuery().GetPayerBalanceSnapshots(
+		ctx,
+		queries.GetPayerBalanceSnapshotsParams{
+			PayerID:          payerID,
+			SpendSinceMinute: f.spendSinceMinute(now),
+		},
+	)
+	if err != nil {
+		return Forecast{}, err
+	}
+
+	if len(rows) == 0 {
+		return computeForecast(
+			queries.GetPayerBalanceSnapshotsRow{PayerID: payerID},
+			f.spendWindow,
+			f.thresholds,
+			now,
+		), nil
+	}
+
+	return computeForecast(rows[0], f.spendWindow, f.thresholds, now), nil
+}
+
+// ForecastAll returns forecasts for every payer with ledger events or usage.
+func (f *Forecaster) ForecastAll(ctx context.Context) ([]Forecast, error) {
+	now := time.Now()
+
+	rows, err := f.db.ReadQuery().GetPayerBalanceSnapshots(
+		ctx,
+		queries.GetPayerBalanceSnapshotsParams{SpendSinceMinute: f.spendSinceMinute(now)},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	forecasts := make([]Forecast, 0, len(rows))
+	for _, row := range rows {
+		forecasts = append(forecasts, computeForecast(row, f.spendWindow, f.thresholds, now))
+	}
+
+	return forecasts, nil
+}
+
+func (f *Forecaster) spendSinceMinute(now time.Time) int32 {
+	return utils.MinutesSinceEpoch(now.Add(-f.spendWindow))
+}
+
+func computeForecast(
+	row queries.GetPayerBalanceSnapshotsRow,
+	spendWindow time.Duration,
+	thresholds Thresholds,
+	now time.Time,
+) Forecast {
+	forecast := Forecast{
+		PayerID:        row.PayerID,
+		PayerAddress:   row.PayerAddress,
+		Balance:        currency.PicoDollar(row.BalancePicodollars),
+		UnsettledUsage: currency.PicoDollar(row.UnsettledPicodollars),
+		Available: currency.PicoDollar(
+			row.BalancePicodollars - row.UnsettledPicodollars,
+		),
+		ComputedAt: now,
+	}
+
+	var spendPerHour float64
+	if spendWindow > 0 && row.RecentSpendPicodollars > 0 {
+		spendPerHour = float64(row.RecentSpendPicodollars) / spendWindow.Hours()
+		forecast.SpendPerHour = currency.PicoDollar(math.Round(spendPerHour))
+	}
+
+	if forecast.Available <= 0 {
+		forecast.Level = LevelDepleted
+		return forecast
+	}
+
+	if spendPerHour <= 0 {
+		forecast.Level = LevelOK
+		return forecast
+	}
+
+	hours := float64(forecast.Available) / spendPerHour
+	if hours >= float64(math.MaxInt64)/float64(time.Hour) {
+		forecast.TimeToDepletion = time.Duration(math.MaxInt64)
+	} else {
+		forecast.TimeToDepletion = time.Duration(hours * float64(time.Hour))
+	}
+
+	forecast.Level = thresholds.level(forecast.TimeToDepletion)
+
+	return forecast
+}
+
+func (t Thresholds) level(timeToDepletion time.Duration) Level {
+	switch {
+	case timeToDepletion <= t.Critical:
+		return LevelCritical
+	case timeToDepletion <= t.Warning:
+		return LevelLow
+	default:
+		return LevelOK
+	}
+}
+
+// settleLevel returns the level to notify for forecast, given the level last notified. Moving to
+// a more severe level takes effect at once, but recovering to a less severe level requires the
+// time to depletion to clear the threshold by levelRecoveryMargin. Payers that stopped spending
+// or were topped up from depleted recover at once.
+func (f *Forecaster) settleLevel(forecast Forecast, previous Level) Level {
+	if forecast.Level >= previous || previous == LevelDepleted || forecast.TimeToDepletion == 0 {
+		return forecast.Level
+	}
+
+	withMargin := Thresholds{
+		Warning:  time.Duration(float64(f.thresholds.Warning) * (1 + levelRecoveryMargin)),
+		Critical: time.Duration(float64(f.thresholds.Critical) * (1 + levelRecoveryMargin)),
+	}
+
+	return min(previous, withMargin.level(forecast.TimeToDepletion))
+}
@@ -0,0 +1,166 @@
+package payerbalance
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/currency"
+	"github.com/xmtp/xmtpd/pkg/db/queries"
+)
+
+func TestComputeForecast(t *testing.T) {
+	thresholds := Thresholds{Warning: 72 * time.Hour, Critical: 24 * time.Hour}
+	now := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name            string
+		row             queries.GetPayerBalanceSnapshotsRow
+		window          time.Duration
+		available       currency.PicoDollar
+		spendPerHour    currency.PicoDollar
+		timeToDepletion time.Duration
+		level           Level
+	}{
+		{
+			name: "not spending",
+			row: queries.GetPayerBalanceSnapshotsRow{
+				BalancePicodollars: 1000,
+			},
+			window:    24 * time.Hour,
+			available: 1000,
+			level:     LevelOK,
+		},
+		{
+			name: "lasts beyond the warning threshold",
+			row: queries.GetPayerBalanceSnapshotsRow{
+				BalancePicodollars:     10_000,
+				RecentSpendPicodollars: 240,
+			},
+			window:          24 * time.Hour,
+			available:       10_000,
+			spendPerHour:    10,
+			timeToDepletion: 1000 * time.Hour,
+			level:           LevelOK,
+		},
+		{
+			name: "low",
+			row: queries.GetPayerBalanceSnapshotsRow{
+				BalancePicodollars:     1000,
+				RecentSpendPicodollars: 480,
+			},
+			window:          24 * time.Hour,
+			available:       1000,
+			spendPerHour:    20,
+			timeToDepletion: 50 * time.Hour,
+			level:           LevelLow,
+		},
+		{
+			name: "unsettled usage is not available",
+			row: queries.GetPayerBalanceSnapshotsRow{
+				BalancePicodollars:     1000,
+				UnsettledPicodollars:   600,
+				RecentSpendPicodollars: 480,
+			},
+			window:          24 * time.Hour,
+			available:       400,
+			spendPerHour:    20,
+			timeToDepletion: 20 * time.Hour,
+			level:           LevelCritical,
+		},
+		{
+			name: "spend rate is averaged over the window",
+			row: queries.GetPayerBalanceSnapshotsRow{
+				BalancePicodollars:     1200,
+				RecentSpendPicodollars: 480,
+			},
+			window:          2 * time.Hour,
+			available:       1200,
+			spendPerHour:    240,
+			timeToDepletion: 5 * time.Hour,
+			level:           LevelCritical,
+		},
+		{
+			name: "depleted by unsettled usage",
+			row: queries.GetPayerBalanceSnapshotsRow{
+				BalancePicodollars:     1000,
+				UnsettledPicodollars:   1000,
+				RecentSpendPicodollars: 480,
+			},
+			window:       24 * time.Hour,
+			available:    0,
+			spendPerHour: 20,
+			level:        LevelDepleted,
+		},
+		{
+			name: "no deposits",
+			row: queries.GetPayerBalanceSnapshotsRow{
+				UnsettledPicodollars: 10,
+			},
+			window:    24 * time.Hour,
+			available: -10,
+			level:     LevelDepleted,
+		},
+		{
+			name: "time to depletion saturates",
+			row: queries.GetPayerBalanceSnapshotsRow{
+				BalancePicodollars:     math.MaxInt64,
+				RecentSpendPicodollars: 1,
+			},
+			window:          24 * time.Hour,
+			available:       math.MaxInt64,
+			timeToDepletion: time.Duration(math.MaxInt64),
+			level:           LevelOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forecast := computeForecast(tt.row, tt.window, thresholds, now)
+			require.Equal(t, tt.available, forecast.Available)
+			require.Equal(t, tt.spendPerHour, forecast.SpendPerHour)
+			require.Equal(t, tt.timeToDepletion, forecast.TimeToDepletion)
+			require.Equal(t, tt.level, forecast.Level)
+			require.Equal(t, now, forecast.ComputedAt)
+		})
+	}
+}
+
+func TestSettleLevel(t *testing.T) {
+	forecaster := NewForecaster(nil, 24*time.Hour, Thresholds{
+		Warning:  72 * time.Hour,
+		Critical: 24 * time.Hour,
+	})
+
+	tests := []struct {
+		name            string
+		level           Level
+		timeToDepletion time.Duration
+		previous        Level
+		settled         Level
+	}{
+		{"worsens at once", LevelLow, 71 * time.Hour, LevelOK, LevelLow},
+		{"recovers past the margin", LevelOK, 80 * time.Hour, LevelLow, LevelOK},
+		{"stays low within the margin", LevelOK, 75 * time.Hour, LevelLow, LevelLow},
+		{"stays critical within the margin", LevelLow, 26 * time.Hour, LevelCritical, LevelCritical},
+		{"recovers one level", LevelOK, 30 * time.Hour, LevelCritical, LevelLow},
+		{"not spending recovers at once", LevelOK, 0, LevelCritical, LevelOK},
+		{"topped up from depleted", LevelLow, 60 * time.Hour, LevelDepleted, LevelLow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forecast := Forecast{Level: tt.level, TimeToDepletion: tt.timeToDepletion}
+			require.Equal(t, tt.settled, forecaster.settleLevel(forecast, tt.previous))
+		})
+	}
+}
+
+func TestLevelString(t *testing.T) {
+	require.Equal(t, "ok", LevelOK.String())
+	require.Equal(t, "low", LevelLow.String())
+	require.Equal(t, "critical", LevelCritical.String())
+	require.Equal(t, "depleted", LevelDepleted.String())
+	require.Equal(t, "unknown", Level(42).String())
+}
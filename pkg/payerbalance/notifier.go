@@ -0,0 +1,150 @@
+package payerbalance
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/xmtp/xmtpd/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// SignatureHeader carries the hex encoded HMAC-SHA256 of a webhook request body, prefixed with
+// "sha256=", when the webhook notifier is configured with a secret.
+const SignatureHeader = "X-XMTPD-Signature"
+
+// Event reports that a payer's balance has moved from one level to another.
+type Event struct {
+	Forecast      Forecast
+	PreviousLevel Level
+}
+
+// Notifier delivers balance level events. The worker retries an event on the next run if
+// Notify returns an error, so implementations may be called more than once for a change.
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// LogNotifier logs events. It is used when no webhook is configured.
+type LogNotifier struct {
+	logger *zap.Logger
+}
+
+var _ Notifier = (*LogNotifier)(nil)
+
+func NewLogNotifier(logger *zap.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+func (n *LogNotifier) Notify(_ context.Context, event Event) error {
+	logFn := n.logger.Warn
+	if event.Forecast.Level < event.PreviousLevel {
+		logFn = n.logger.Info
+	}
+
+	logFn(
+		"payer balance level changed",
+		utils.PayerIDField(event.Forecast.PayerID),
+		utils.PayerAddressField(event.Forecast.PayerAddress),
+		zap.Stringer("level", event.Forecast.Level),
+		zap.Stringer("previous_level", event.PreviousLevel),
+		utils.BalanceField(event.Forecast.Available.String()),
+		zap.Duration("time_to_depletion", event.Forecast.TimeToDepletion),
+	)
+
+	return nil
+}
+
+// WebhookPayload is the JSON body the webhook notifier POSTs for each event.
+type WebhookPayload struct {
+	PayerID                   int32  `json:"payer_id"`
+	PayerAddress              string `json:"payer_address"`
+	Level                     string `json:"level"`
+	PreviousLevel             string `json:"previous_level"`
+	BalancePicodollars        int64  `json:"balance_picodollars"`
+	UnsettledUsagePicodollars int64  `json:"unsettled_usage_picodollars"`
+	AvailablePicodollars      int64  `json:"available_picodollars"`
+	SpendPerHourPicodollars   int64  `json:"spend_per_hour_picodollars"`
+	// Zero if the payer is not spending or has no funds available.
+	SecondsToDepletion int64 `json:"seconds_to_depletion"`
+	ComputedAt         int64 `json:"computed_at_unix_seconds"`
+}
+
+func NewWebhookPayload(event Event) WebhookPayload {
+	return WebhookPayload{
+		PayerID:                   event.Forecast.PayerID,
+		PayerAddress:              event.Forecast.PayerAddress,
+		Level:                     event.Forecast.Level.String(),
+		PreviousLevel:             event.PreviousLevel.String(),
+		BalancePicodollars:        int64(event.Forecast.Balance),
+		UnsettledUsagePicodollars: int64(event.Forecast.UnsettledUsage),
+		AvailablePicodollars:      int64(event.Forecast.Available),
+		SpendPerHourPicodollars:   int64(event.Forecast.SpendPerHour),
+		SecondsToDepletion:        int64(event.Forecast.TimeToDepletion / time.Second),
+		ComputedAt:                event.Forecast.ComputedAt.Unix(),
+	}
+}
+
+// WebhookNotifier POSTs each event as JSON to a URL.
+type WebhookNotifier struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+var _ Notifier = (*WebhookNotifier)(nil)
+
+// NewWebhookNotifier creates a notifier that POSTs to url. If secret is not empty, requests are
+// signed with it in the SignatureHeader.
+func NewWebhookNotifier(url, secret string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		secret: []byte(secret),
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, event Event) error {
+	body, err := json.Marshal(NewWebhookPayload(event))
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(n.secret) > 0 {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(n.secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of body, for receivers to verify webhook requests.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
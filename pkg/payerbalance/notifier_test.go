@@ -0,0 +1,112 @@
+package payerbalance_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/payerbalance"
+)
+
+type webhookRequest struct {
+	body      []byte
+	signature string
+}
+
+func newWebhookServer(t *testing.T, status int) (*httptest.Server, <-chan webhookRequest) {
+	requests := make(chan webhookRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		requests <- webhookRequest{
+			body:      body,
+			signature: r.Header.Get(payerbalance.SignatureHeader),
+		}
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, requests
+}
+
+func testEvent() payerbalance.Event {
+	return payerbalance.Event{
+		Forecast: payerbalance.Forecast{
+			PayerID:         7,
+			PayerAddress:    "0x0000000000000000000000000000000000000007",
+			Balance:         1000,
+			UnsettledUsage:  600,
+			Available:       400,
+			SpendPerHour:    20,
+			TimeToDepletion: 20 * time.Hour,
+			Level:           payerbalance.LevelCritical,
+			ComputedAt:      time.Unix(1_700_000_000, 0),
+		},
+		PreviousLevel: payerbalance.LevelLow,
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	server, requests := newWebhookServer(t, http.StatusNoContent)
+	notifier := payerbalance.NewWebhookNotifier(server.URL, "secret", time.Second)
+
+	require.NoError(t, notifier.Notify(context.Background(), testEvent()))
+
+	req := <-requests
+	require.Equal(t, "sha256="+payerbalance.Sign([]byte("secret"), req.body), req.signature)
+
+	var payload payerbalance.WebhookPayload
+	require.NoError(t, json.Unmarshal(req.body, &payload))
+	require.Equal(t, payerbalance.WebhookPayload{
+		PayerID:                   7,
+		PayerAddress:              "0x0000000000000000000000000000000000000007",
+		Level:                     "critical",
+		PreviousLevel:             "low",
+		BalancePicodollars:        1000,
+		UnsettledUsagePicodollars: 600,
+		AvailablePicodollars:      400,
+		SpendPerHourPicodollars:   20,
+		SecondsToDepletion:        72_000,
+		ComputedAt:                1_700_000_000,
+	}, payload)
+}
+
+func TestWebhookNotifierUnsigned(t *testing.T) {
+	server, requests := newWebhookServer(t, http.StatusOK)
+	notifier := payerbalance.NewWebhookNotifier(server.URL, "", time.Second)
+
+	require.NoError(t, notifier.Notify(context.Background(), testEvent()))
+
+	req := <-requests
+	require.Empty(t, req.signature)
+}
+
+func TestWebhookNotifierErrorStatus(t *testing.T) {
+	server, requests := newWebhookServer(t, http.StatusInternalServerError)
+	notifier := payerbalance.NewWebhookNotifier(server.URL, "secret", time.Second)
+
+	err := notifier.Notify(context.Background(), testEvent())
+	require.ErrorContains(t, err, "webhook returned status 500")
+	<-requests
+}
+
+func TestWebhookNotifierTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+	notifier := payerbalance.NewWebhookNotifier(server.URL, "", 50*time.Millisecond)
+
+	require.Error(t, notifier.Notify(context.Background(), testEvent()))
+}
@@ -0,0 +1,163 @@
+package payerbalance
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/xmtp/xmtpd/pkg/db"
+	"github.com/xmtp/xmtpd/pkg/db/queries"
+	"github.com/xmtp/xmtpd/pkg/tracing"
+	"github.com/xmtp/xmtpd/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// Worker periodically forecasts every payer's balance and notifies when a payer crosses into a
+// different level. The last notified level of each payer is stored, so a change is only
+// notified once, and a failed notification is retried on the next run. Replicas sharing a
+// database coordinate through a session advisory lock, so only one of them notifies, without
+// holding a transaction open while notifications are sent.
+type Worker struct {
+	ctx        context.Context
+	cancel     context.CancelFunc
+	logger     *zap.Logger
+	db         *db.Handler
+	interval   time.Duration
+	forecaster *Forecaster
+	notifier   Notifier
+	wg         sync.WaitGroup
+}
+
+func NewWorker(
+	ctx context.Context,
+	logger *zap.Logger,
+	db *db.Handler,
+	interval time.Duration,
+	forecaster *Forecaster,
+	notifier Notifier,
+) *Worker {
+	if interval <= 0 {
+		logger.Panic("payer balance interval must be greater than zero")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &Worker{
+		ctx:        ctx,
+		cancel:     cancel,
+		logger:     logger.Named(utils.PayerBalanceLoggerName),
+		db:         db,
+		interval:   interval,
+		forecaster: forecaster,
+		notifier:   notifier,
+	}
+}
+
+// Start launches the worker's main loop in a separate goroutine.
+func (w *Worker) Start() {
+	tracing.GoPanicWrap(
+		w.ctx,
+		&w.wg,
+		"payer-balance-worker",
+		func(ctx context.Context) {
+			w.logger.Info("starting payer balance worker", zap.Duration("interval", w.interval))
+
+			ticker := time.NewTicker(w.interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if err := w.Run(); err != nil {
+						w.logger.Error("forecasting payer balances", zap.Error(err))
+					}
+				}
+			}
+		},
+	)
+}
+
+func (w *Worker) Stop() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+// Run forecasts every payer's balance once and notifies about level changes, unless another
+// replica holds the worker lock. A run is bounded by the interval, so a slow notifier cannot hold
+// the lock past the next run.
+func (w *Worker) Run() error {
+	ctx, cancel := context.WithTimeout(w.ctx, w.interval)
+	defer cancel()
+
+	haLock, err := db.NewSessionScopedAdvisoryLocker(ctx, w.db.Write())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = haLock.Release()
+	}()
+
+	locked, err := haLock.TryLockBalanceForecastWorker()
+	if err != nil {
+		return err
+	}
+	if !locked {
+		w.logger.Debug("payer balance lock held by another replica, skipping")
+		return nil
+	}
+
+	rows, err := w.db.WriteQuery().GetPayerBalanceLevels(ctx)
+	if err != nil {
+		return err
+	}
+	levels := make(map[int32]Level, len(rows))
+	for _, row := range rows {
+		levels[row.PayerID] = Level(row.Level)
+	}
+
+	forecasts, err := w.forecaster.ForecastAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, forecast := range forecasts {
+		// Payers that have never been notified about are assumed to be OK.
+		previous, ok := levels[forecast.PayerID]
+		if !ok {
+			previous = LevelOK
+		}
+		forecast.Level = w.forecaster.settleLevel(forecast, previous)
+		if forecast.Level == previous {
+			continue
+		}
+
+		if err := w.notifier.Notify(ctx, Event{
+			Forecast:      forecast,
+			PreviousLevel: previous,
+		}); err != nil {
+			w.logger.Error(
+				"failed to notify payer balance level change",
+				utils.PayerIDField(forecast.PayerID),
+				zap.Error(err),
+			)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			continue
+		}
+
+		if err := w.db.WriteQuery().SetPayerBalanceLevel(
+			ctx,
+			queries.SetPayerBalanceLevelParams{
+				PayerID: forecast.PayerID,
+				Level:   int16(forecast.Level),
+			},
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,226 @@
+package payerbalance_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/currency"
+	"github.com/xmtp/xmtpd/pkg/db"
+	"github.com/xmtp/xmtpd/pkg/ledger"
+	"github.com/xmtp/xmtpd/pkg/payerbalance"
+	"github.com/xmtp/xmtpd/pkg/testutils"
+	"github.com/xmtp/xmtpd/pkg/utils"
+)
+
+type recordingNotifier struct {
+	events []payerbalance.Event
+	err    error
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, event payerbalance.Event) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.events = append(n.events, event)
+	return nil
+}
+
+type workerFixture struct {
+	t        *testing.T
+	ctx      context.Context
+	db       *db.Handler
+	ledger   *ledger.Ledger
+	nextID   int
+	worker   *payerbalance.Worker
+	notifier *recordingNotifier
+}
+
+func setupWorker(t *testing.T) *workerFixture {
+	ctx := context.Background()
+	handler, _ := testutils.NewDB(t, ctx)
+	log := testutils.NewLog(t)
+
+	notifier := &recordingNotifier{}
+	forecaster := payerbalance.NewForecaster(handler, 24*time.Hour, payerbalance.Thresholds{
+		Warning:  72 * time.Hour,
+		Critical: 24 * time.Hour,
+	})
+	worker := payerbalance.NewWorker(ctx, log, handler, time.Hour, forecaster, notifier)
+	t.Cleanup(worker.Stop)
+
+	return &workerFixture{
+		t:        t,
+		ctx:      ctx,
+		db:       handler,
+		ledger:   ledger.NewLedger(log, handler),
+		worker:   worker,
+		notifier: notifier,
+	}
+}
+
+func (f *workerFixture) deposit(payerID int32, amount currency.PicoDollar) {
+	f.nextID++
+	eventID := sha256.Sum256(fmt.Appendf(nil, "payer-balance-test-%d", f.nextID))
+	require.NoError(f.t, f.ledger.Deposit(f.ctx, payerID, amount, eventID, 0))
+}
+
+// spend records usage an hour ago, both as unsettled usage if unsettled is set, and in the
+// usage history the spend rate is computed from.
+func (f *workerFixture) spend(payerID int32, amount int64, unsettled bool) {
+	minute := utils.MinutesSinceEpoch(time.Now().Add(-time.Hour))
+
+	_, err := f.db.DB().ExecContext(f.ctx, `
+		INSERT INTO payer_usage_history (
+			payer_id, minutes_since_epoch, originator_id, topic_kind,
+			base_fee_picodollars, congestion_fee_picodollars, message_count
+		) VALUES ($1, $2, 100, 0, $3, 0, 1)`,
+		payerID, minute, amount,
+	)
+	require.NoError(f.t, err)
+
+	if !unsettled {
+		return
+	}
+	_, err = f.db.DB().ExecContext(f.ctx, `
+		INSERT INTO unsettled_usage (
+			payer_id, originator_id, minutes_since_epoch, spend_picodollars, last_sequence_id
+		) VALUES ($1, 100, $2, $3, 1)`,
+		payerID, minute, amount,
+	)
+	require.NoError(f.t, err)
+}
+
+func (f *workerFixture) levels() map[int32]payerbalance.Level {
+	rows, err := f.db.Query().GetPayerBalanceLevels(f.ctx)
+	require.NoError(f.t, err)
+
+	levels := make(map[int32]payerbalance.Level, len(rows))
+	for _, row := range rows {
+		levels[row.PayerID] = payerbalance.Level(row.Level)
+	}
+	return levels
+}
+
+func TestWorkerNotifiesLevelChanges(t *testing.T) {
+	f := setupWorker(t)
+
+	// Spends 20 an hour, so 1000 lasts 50 hours.
+	low := testutils.CreatePayer(t, f.db.DB())
+	f.deposit(low, 1000)
+	f.spend(low, 480, false)
+
+	// Not spending.
+	steady := testutils.CreatePayer(t, f.db.DB())
+	f.deposit(steady, 1000)
+
+	// The unsettled usage uses up the balance.
+	depleted := testutils.CreatePayer(t, f.db.DB())
+	f.deposit(depleted, 100)
+	f.spend(depleted, 100, true)
+
+	require.NoError(t, f.worker.Run())
+
+	require.Len(t, f.notifier.events, 2)
+	byPayer := make(map[int32]payerbalance.Event)
+	for _, event := range f.notifier.events {
+		byPayer[event.Forecast.PayerID] = event
+	}
+	require.Equal(t, payerbalance.LevelLow, byPayer[low].Forecast.Level)
+	require.Equal(t, payerbalance.LevelOK, byPayer[low].PreviousLevel)
+	require.Equal(t, 50*time.Hour, byPayer[low].Forecast.TimeToDepletion)
+	require.Equal(t, payerbalance.LevelDepleted, byPayer[depleted].Forecast.Level)
+	require.Equal(t, currency.PicoDollar(0), byPayer[depleted].Forecast.Available)
+
+	require.Equal(t, map[int32]payerbalance.Level{
+		low:      payerbalance.LevelLow,
+		depleted: payerbalance.LevelDepleted,
+	}, f.levels())
+
+	// Nothing has changed, so nothing is notified again.
+	require.NoError(t, f.worker.Run())
+	require.Len(t, f.notifier.events, 2)
+
+	// Topping up recovers the payer.
+	f.deposit(depleted, 100_000)
+	require.NoError(t, f.worker.Run())
+	require.Len(t, f.notifier.events, 3)
+	recovered := f.notifier.events[2]
+	require.Equal(t, depleted, recovered.Forecast.PayerID)
+	require.Equal(t, payerbalance.LevelOK, recovered.Forecast.Level)
+	require.Equal(t, payerbalance.LevelDepleted, recovered.PreviousLevel)
+	require.Equal(t, payerbalance.LevelOK, f.levels()[depleted])
+}
+
+func TestWorkerRetriesFailedNotifications(t *testing.T) {
+	f := setupWorker(t)
+
+	payer := testutils.CreatePayer(t, f.db.DB())
+	f.deposit(payer, 100)
+	f.spend(payer, 100, true)
+
+	f.notifier.err = errors.New("webhook unavailable")
+	require.NoError(t, f.worker.Run())
+	require.Empty(t, f.levels())
+
+	f.notifier.err = nil
+	require.NoError(t, f.worker.Run())
+	require.Len(t, f.notifier.events, 1)
+	require.Equal(t, payerbalance.LevelDepleted, f.levels()[payer])
+}
+
+func TestWorkerSkipsWhileLockHeld(t *testing.T) {
+	f := setupWorker(t)
+
+	payer := testutils.CreatePayer(t, f.db.DB())
+	f.deposit(payer, 100)
+	f.spend(payer, 100, true)
+
+	// Another replica is running.
+	haLock, err := db.NewSessionScopedAdvisoryLocker(f.ctx, f.db.DB())
+	require.NoError(t, err)
+	locked, err := haLock.TryLockBalanceForecastWorker()
+	require.NoError(t, err)
+	require.True(t, locked)
+
+	require.NoError(t, f.worker.Run())
+	require.Empty(t, f.notifier.events)
+
+	require.NoError(t, haLock.Release())
+
+	require.NoError(t, f.worker.Run())
+	require.Len(t, f.notifier.events, 1)
+}
+
+func TestForecasterForecast(t *testing.T) {
+	f := setupWorker(t)
+	forecaster := payerbalance.NewForecaster(f.db, 24*time.Hour, payerbalance.Thresholds{
+		Warning:  72 * time.Hour,
+		Critical: 24 * time.Hour,
+	})
+
+	payer := testutils.CreatePayer(t, f.db.DB())
+	f.deposit(payer, 1000)
+	f.spend(payer, 480, true)
+
+	forecast, err := forecaster.Forecast(f.ctx, payer)
+	require.NoError(t, err)
+	require.Equal(t, payer, forecast.PayerID)
+	require.Equal(t, currency.PicoDollar(1000), forecast.Balance)
+	require.Equal(t, currency.PicoDollar(480), forecast.UnsettledUsage)
+	require.Equal(t, currency.PicoDollar(520), forecast.Available)
+	require.Equal(t, currency.PicoDollar(20), forecast.SpendPerHour)
+	require.Equal(t, 26*time.Hour, forecast.TimeToDepletion)
+	require.Equal(t, payerbalance.LevelLow, forecast.Level)
+
+	// A payer without any activity has nothing available.
+	idle := testutils.CreatePayer(t, f.db.DB())
+	forecast, err = forecaster.Forecast(f.ctx, idle)
+	require.NoError(t, err)
+	require.Equal(t, idle, forecast.PayerID)
+	require.Equal(t, payerbalance.LevelDepleted, forecast.Level)
+}
@@ -17,6 +17,9 @@ const (
 	systemErrorReason             = "system error"
 	invalidReportTransitionReason = "invalid report transition"
 	invalidReportContentReason    = "invalid report content"
+
+	// maxWithheldEnvelopes bounds the number of envelopes returned as proof of withholding.
+	maxWithheldEnvelopes = 100
 )
 
 var (
@@ -251,6 +254,114 @@ func (p *PayerReportVerifier) verifyMerkleRoot(
 	}, nil
 }
 
+/*
+FindWithheldEnvelopes looks for envelopes withheld from the final minute of a report.
+
+The end sequence ID of a report must be the last envelope of its end minute. An
+originator can withhold envelopes from that minute until the report is confirmed,
+so they fall between reports and are never paid for. Once those envelopes are
+synced, they have a greater sequence ID than the report's end sequence ID but
+the same originator minute, which proves the misbehavior.
+
+  - @param ctx The context.
+  - @param report A previously submitted report.
+  - @return The withheld envelopes, if any.
+*/
+func (p *PayerReportVerifier) FindWithheldEnvelopes(
+	ctx context.Context,
+	report *PayerReport,
+) ([]*envelopes.OriginatorEnvelope, error) {
+	if report == nil || report.EndSequenceID == 0 {
+		return nil, nil
+	}
+
+	originatorID, err := utils.Uint32ToInt32(report.OriginatorNodeID)
+	if err != nil {
+		return nil, ErrInvalidOriginatorID
+	}
+
+	endSequenceID, err := utils.Uint64ToInt64(report.EndSequenceID)
+	if err != nil {
+		return nil, ErrInvalidSequenceID
+	}
+
+	querier := p.store.Queries()
+
+	endMessage, err := querier.GetGatewayEnvelopeByID(ctx, queries.GetGatewayEnvelopeByIDParams{
+		OriginatorSequenceID: endSequenceID,
+		OriginatorNodeID:     originatorID,
+	})
+	if err != nil {
+		return nil, ErrMessageAtEndSequenceIDNotFound
+	}
+
+	endEnvelope, err := envelopes.NewOriginatorEnvelopeFromBytes(endMessage.OriginatorEnvelope)
+	if err != nil {
+		return nil, err
+	}
+
+	endMinute := getMinuteFromEnvelope(endEnvelope)
+
+	lastSequenceID, err := querier.GetLastSequenceIDForOriginatorMinute(
+		ctx,
+		queries.GetLastSequenceIDForOriginatorMinuteParams{
+			OriginatorID:      originatorID,
+			MinutesSinceEpoch: endMinute,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The last envelope we have for the minute is the report's end, nothing was withheld.
+	// Usage for the minute is cleared once the report settles, so an empty minute still
+	// requires looking at the envelopes themselves.
+	if lastSequenceID != 0 && lastSequenceID <= endSequenceID {
+		return nil, nil
+	}
+
+	rows, err := querier.SelectGatewayEnvelopesBySingleOriginator(
+		ctx,
+		queries.SelectGatewayEnvelopesBySingleOriginatorParams{
+			OriginatorNodeID: originatorID,
+			CursorSequenceID: endSequenceID,
+			RowLimit:         maxWithheldEnvelopes,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var withheld []*envelopes.OriginatorEnvelope
+	for _, row := range rows {
+		env, err := envelopes.NewOriginatorEnvelopeFromBytes(row.OriginatorEnvelope)
+		if err != nil {
+			return nil, err
+		}
+
+		minute := getMinuteFromEnvelope(env)
+		if minute > endMinute {
+			break
+		}
+
+		if minute == endMinute {
+			withheld = append(withheld, env)
+		}
+	}
+
+	if len(withheld) > 0 {
+		p.logger.Debug(
+			"found envelopes withheld from the final minute of a report",
+			utils.OriginatorIDField(report.OriginatorNodeID),
+			utils.LastSequenceIDField(endSequenceID),
+			zap.Int32("minute", endMinute),
+			zap.Int("num_withheld", len(withheld)),
+		)
+	}
+
+	return withheld, nil
+}
+
 // Check if a given sequence ID is the last message in a minute
 func (p *PayerReportVerifier) isAtMinuteEnd(
 	ctx context.Context,
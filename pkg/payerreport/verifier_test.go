@@ -75,6 +75,7 @@ func insertEnvelope(t *testing.T, db *sql.DB, params envelopeCreateParams) {
 			MinutesSinceEpoch: utils.MinutesSinceEpoch(params.timestamp),
 			SpendPicodollars:  int64(params.cost),
 		},
+		0,
 		true,
 	)
 	require.NoError(t, err)
@@ -572,3 +573,51 @@ func TestValidateMinuteBoundaries(t *testing.T) {
 		})
 	}
 }
+
+func TestFindWithheldEnvelopes(t *testing.T) {
+	db, verifier := setupVerifier(t)
+
+	originatorID := uint32(1)
+	payerAddress := testutils.RandomAddress()
+	minute1 := time.Now().UTC().Truncate(time.Minute)
+	minute2 := minute1.Add(time.Minute)
+
+	// Sequence IDs 1-3 are in minute1, 4 is in minute2.
+	timestamps := []time.Time{
+		minute1,
+		minute1.Add(10 * time.Second),
+		minute1.Add(20 * time.Second),
+		minute2,
+	}
+	for i, timestamp := range timestamps {
+		insertEnvelope(
+			t,
+			db,
+			newEnvelopeCreateParams(t, originatorID, payerAddress, timestamp, uint64(i+1)),
+		)
+	}
+
+	report := &payerreport.PayerReport{
+		OriginatorNodeID: originatorID,
+		StartSequenceID:  0,
+		EndSequenceID:    1,
+		ActiveNodeIDs:    []uint32{1},
+	}
+
+	withheld, err := verifier.FindWithheldEnvelopes(t.Context(), report)
+	require.NoError(t, err)
+	require.Len(t, withheld, 2)
+	require.Equal(t, uint64(2), withheld[0].OriginatorSequenceID())
+	require.Equal(t, uint64(3), withheld[1].OriginatorSequenceID())
+
+	// A report ending at the last envelope of the minute withheld nothing.
+	report.EndSequenceID = 3
+	withheld, err = verifier.FindWithheldEnvelopes(t.Context(), report)
+	require.NoError(t, err)
+	require.Empty(t, withheld)
+
+	report.EndSequenceID = 4
+	withheld, err = verifier.FindWithheldEnvelopes(t.Context(), report)
+	require.NoError(t, err)
+	require.Empty(t, withheld)
+}
@@ -11,15 +11,25 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/xmtp/xmtpd/pkg/constants"
 	"github.com/xmtp/xmtpd/pkg/envelopes"
+	"github.com/xmtp/xmtpd/pkg/misbehavior"
 	"github.com/xmtp/xmtpd/pkg/payerreport"
 	"github.com/xmtp/xmtpd/pkg/proto/identity/associations"
 	envelopesProto "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/envelopes"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/message_api"
 	"github.com/xmtp/xmtpd/pkg/registrant"
 	"github.com/xmtp/xmtpd/pkg/tracing"
 	"github.com/xmtp/xmtpd/pkg/utils"
 	"go.uber.org/zap"
 )
 
+// withholdingDetector finds envelopes withheld from the final minute of a previous report.
+type withholdingDetector interface {
+	FindWithheldEnvelopes(
+		ctx context.Context,
+		report *payerreport.PayerReport,
+	) ([]*envelopes.OriginatorEnvelope, error)
+}
+
 // AttestationWorker is responsible for periodically checking for reports that need attestation
 // and signing them with the node's private key.
 type AttestationWorker struct {
@@ -29,6 +39,8 @@ type AttestationWorker struct {
 	registrant      registrant.IRegistrant
 	store           payerreport.IPayerReportStore
 	verifier        payerreport.IPayerReportVerifier
+	detector        withholdingDetector
+	misbehavior     misbehavior.MisbehaviorService
 	wg              sync.WaitGroup
 	pollInterval    time.Duration
 	domainSeparator common.Hash
@@ -39,7 +51,8 @@ var _ stoppable = &AttestationWorker{}
 // NewAttestationWorker creates and starts a new attestation worker that will periodically
 // check for reports that need attestation.
 // It takes a context, logger, registrant for signing, store for accessing reports,
-// and a poll interval that determines how often to check for reports.
+// a poll interval that determines how often to check for reports, and the service
+// misbehavior detected in previous reports is reported to.
 func NewAttestationWorker(
 	ctx context.Context,
 	logger *zap.Logger,
@@ -47,15 +60,24 @@ func NewAttestationWorker(
 	store payerreport.IPayerReportStore,
 	pollInterval time.Duration,
 	domainSeparator common.Hash,
+	misbehaviorService misbehavior.MisbehaviorService,
 ) *AttestationWorker {
 	ctx, cancel := context.WithCancel(ctx)
 
+	verifier := payerreport.NewPayerReportVerifier(logger, store)
+
+	if misbehaviorService == nil {
+		misbehaviorService = misbehavior.NewLoggingMisbehaviorService(logger)
+	}
+
 	worker := &AttestationWorker{
 		ctx:             ctx,
 		logger:          logger.Named(utils.PayerReportAttestationWorkerLoggerName),
 		registrant:      registrant,
 		store:           store,
-		verifier:        payerreport.NewPayerReportVerifier(logger, store),
+		verifier:        verifier,
+		detector:        verifier,
+		misbehavior:     misbehaviorService,
 		wg:              sync.WaitGroup{},
 		cancel:          cancel,
 		pollInterval:    pollInterval,
@@ -199,6 +221,12 @@ func (w *AttestationWorker) attestReport(report *payerreport.PayerReportWithStat
 		return err
 	}
 
+	// Withholding from the previous report can only be detected once the next report arrives.
+	// It doesn't make the current report invalid, so it's reported separately.
+	if prevReport != nil {
+		w.reportWithheldEnvelopes(prevReport)
+	}
+
 	if verifyResult.IsValid {
 		logger.Info(
 			"report is valid, submitting attestation",
@@ -211,6 +239,45 @@ func (w *AttestationWorker) attestReport(report *payerreport.PayerReportWithStat
 	return w.rejectAttestation(report)
 }
 
+// reportWithheldEnvelopes files a misbehavior report against the originator of the previous report
+// if it withheld envelopes from the report's final minute.
+// Failures are logged, as they must not block attestation.
+func (w *AttestationWorker) reportWithheldEnvelopes(prevReport *payerreport.PayerReport) {
+	logger := payerreport.AddReportLogFields(w.logger, prevReport)
+
+	withheld, err := w.detector.FindWithheldEnvelopes(w.ctx, prevReport)
+	if err != nil {
+		logger.Error("failed to check previous report for withheld envelopes", zap.Error(err))
+		return
+	}
+
+	if len(withheld) == 0 {
+		return
+	}
+
+	logger.Warn(
+		"previous report withheld envelopes from its final minute",
+		zap.Int("num_withheld", len(withheld)),
+	)
+
+	report, err := misbehavior.NewSafetyFailureReport(
+		prevReport.OriginatorNodeID,
+		message_api.Misbehavior_MISBEHAVIOR_WITHHELD_MESSAGES,
+		true,
+		misbehavior.LimitEvidence(withheld),
+	)
+	if err != nil {
+		logger.Error("failed to build misbehavior report", zap.Error(err))
+		return
+	}
+
+	// Every report following the previous one, and every retried attestation, finds the
+	// same withheld envelopes. They are reported once per previous report.
+	if err := w.misbehavior.SafetyFailure(report.WithSubject(prevReport.ID[:])); err != nil {
+		logger.Error("failed to report withheld envelopes", zap.Error(err))
+	}
+}
+
 // getPreviousReport retrieves the previous report for a given current report.
 // The previous report should have been submitted or settled and should end
 // at the start sequence ID of the current report.
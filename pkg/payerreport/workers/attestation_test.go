@@ -1,6 +1,7 @@
 package workers
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -8,8 +9,12 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/xmtp/xmtpd/pkg/db/queries"
+	"github.com/xmtp/xmtpd/pkg/envelopes"
+	"github.com/xmtp/xmtpd/pkg/misbehavior"
 	"github.com/xmtp/xmtpd/pkg/payerreport"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/message_api"
 	"github.com/xmtp/xmtpd/pkg/testutils"
+	envelopeTestUtils "github.com/xmtp/xmtpd/pkg/testutils/envelopes"
 	payerreportMocks "github.com/xmtp/xmtpd/pkg/testutils/mocks/payerreport"
 	registrantMocks "github.com/xmtp/xmtpd/pkg/testutils/mocks/registrant"
 )
@@ -36,6 +41,7 @@ func testAttestationWorker(
 			store,
 			pollInterval,
 			domainSeparator,
+			nil,
 		)
 	)
 
@@ -280,3 +286,99 @@ func TestAttestFirstReport(t *testing.T) {
 		fromDB.AttestationStatus,
 	)
 }
+
+type fakeWithholdingDetector struct {
+	withheld []*envelopes.OriginatorEnvelope
+}
+
+func (f *fakeWithholdingDetector) FindWithheldEnvelopes(
+	context.Context,
+	*payerreport.PayerReport,
+) ([]*envelopes.OriginatorEnvelope, error) {
+	return f.withheld, nil
+}
+
+type recordingMisbehaviorService struct {
+	reports []*misbehavior.SafetyFailureReport
+}
+
+func (r *recordingMisbehaviorService) SafetyFailure(report *misbehavior.SafetyFailureReport) error {
+	r.reports = append(r.reports, report)
+	return nil
+}
+
+func (r *recordingMisbehaviorService) LivenessFailure(*misbehavior.LivenessFailureReport) error {
+	return nil
+}
+
+func TestAttestReportsWithheldEnvelopes(t *testing.T) {
+	worker, store, _, mockVerifier := testAttestationWorker(t, time.Second)
+
+	prevReport, err := payerreport.BuildPayerReport(payerreport.BuildPayerReportParams{
+		OriginatorNodeID: originatorNodeID,
+		StartSequenceID:  0,
+		EndSequenceID:    5,
+		NodeIDs:          []uint32{originatorNodeID},
+		DomainSeparator:  domainSeparator,
+	})
+	require.NoError(t, err)
+	storedPrevReport := storeReport(t, store, &prevReport.PayerReport)
+	require.NoError(t, store.SetReportSubmitted(t.Context(), storedPrevReport.ID, 0))
+
+	report, err := payerreport.BuildPayerReport(payerreport.BuildPayerReportParams{
+		OriginatorNodeID: originatorNodeID,
+		StartSequenceID:  5,
+		EndSequenceID:    10,
+		NodeIDs:          []uint32{originatorNodeID},
+		DomainSeparator:  domainSeparator,
+	})
+	require.NoError(t, err)
+	storedReport := storeReport(t, store, &report.PayerReport)
+
+	withheld, err := envelopes.NewOriginatorEnvelope(
+		envelopeTestUtils.CreateOriginatorEnvelope(t, originatorNodeID, 6),
+	)
+	require.NoError(t, err)
+
+	misbehaviorService := &recordingMisbehaviorService{}
+	worker.detector = &fakeWithholdingDetector{
+		withheld: []*envelopes.OriginatorEnvelope{withheld},
+	}
+	worker.misbehavior = misbehaviorService
+
+	mockVerifier.EXPECT().
+		VerifyReport(mock.Anything, mock.Anything, &report.PayerReport).
+		Return(payerreport.VerifyReportResult{
+			IsValid: true,
+			Reason:  "valid report",
+		}, nil)
+
+	require.NoError(t, worker.attestReport(storedReport))
+
+	require.Len(t, misbehaviorService.reports, 1)
+	require.Equal(
+		t,
+		uint32(originatorNodeID),
+		misbehaviorService.reports[0].MisbehavingNodeID(),
+	)
+	require.Equal(
+		t,
+		message_api.Misbehavior_MISBEHAVIOR_WITHHELD_MESSAGES,
+		misbehaviorService.reports[0].MisbehaviorType(),
+	)
+	// Reported once per previous report.
+	require.Equal(
+		t,
+		storedPrevReport.ID[:],
+		misbehaviorService.reports[0].DedupKey()[6:],
+	)
+
+	// Withholding in the previous report does not affect the attestation of the current one.
+	fromDB, err := store.FetchReport(t.Context(), storedReport.ID)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		payerreport.AttestationStatus(payerreport.AttestationApproved),
+		fromDB.AttestationStatus,
+	)
+}
@@ -254,6 +254,7 @@ func setupMultiNodeTest(t *testing.T) multiNodeTestScaffold {
 		payerReportStore1,
 		1*time.Hour,
 		domainSeparator,
+		nil,
 	)
 	attestationWorker2 := workers.NewAttestationWorker(
 		t.Context(),
@@ -262,6 +263,7 @@ func setupMultiNodeTest(t *testing.T) multiNodeTestScaffold {
 		payerReportStore2,
 		1*time.Hour,
 		domainSeparator,
+		nil,
 	)
 
 	submitterWorker1 := workers.NewSubmitterWorker(
@@ -7,6 +7,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/xmtp/xmtpd/pkg/blockchain"
+	"github.com/xmtp/xmtpd/pkg/misbehavior"
 	"github.com/xmtp/xmtpd/pkg/payerreport"
 	"github.com/xmtp/xmtpd/pkg/registrant"
 	"github.com/xmtp/xmtpd/pkg/registry"
@@ -32,6 +33,7 @@ type workerConfig struct {
 	generateOthersPeriod    time.Duration
 	expirySelfPeriod        time.Duration
 	expiryOthersPeriod      time.Duration
+	misbehaviorService      misbehavior.MisbehaviorService
 }
 
 // WorkerConfigBuilder provides a builder pattern for creating WorkerConfig instances.
@@ -49,6 +51,7 @@ type WorkerConfigBuilder struct {
 	generateOthersPeriod    time.Duration
 	expirySelfPeriod        time.Duration
 	expiryOthersPeriod      time.Duration
+	misbehaviorService      misbehavior.MisbehaviorService
 }
 
 // NewWorkerConfigBuilder creates a new WorkerConfigBuilder instance.
@@ -132,6 +135,15 @@ func (b *WorkerConfigBuilder) WithExpiryOthersPeriod(
 	return b
 }
 
+// WithMisbehaviorService sets the service misbehavior detected in payer reports is reported to.
+// It is optional and defaults to a service that only logs the reports.
+func (b *WorkerConfigBuilder) WithMisbehaviorService(
+	misbehaviorService misbehavior.MisbehaviorService,
+) *WorkerConfigBuilder {
+	b.misbehaviorService = misbehaviorService
+	return b
+}
+
 // Build creates a WorkerConfig instance after validating that all required fields are set.
 // Returns an error if any required field is nil or invalid.
 func (b *WorkerConfigBuilder) Build() (*workerConfig, error) {
@@ -195,6 +207,7 @@ func (b *WorkerConfigBuilder) Build() (*workerConfig, error) {
 		generateOthersPeriod:    b.generateOthersPeriod,
 		expirySelfPeriod:        b.expirySelfPeriod,
 		expiryOthersPeriod:      b.expiryOthersPeriod,
+		misbehaviorService:      b.misbehaviorService,
 	}, nil
 }
 
@@ -219,6 +232,7 @@ func RunWorkers(cfg workerConfig) *WorkerWrapper {
 		cfg.store,
 		cfg.attestationPollInterval,
 		cfg.domainSeparator,
+		cfg.misbehaviorService,
 	)
 
 	generatorWorker := NewGeneratorWorker(
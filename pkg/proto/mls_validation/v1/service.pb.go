@@ -300,8 +300,11 @@ func (x *ValidateGroupMessagesResponse) GetResponses() []*ValidateGroupMessagesR
 type GetAssociationStateRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// List of identity updates
-	OldUpdates    []*associations.IdentityUpdate `protobuf:"bytes,1,rep,name=old_updates,json=oldUpdates,proto3" json:"old_updates,omitempty"`
-	NewUpdates    []*associations.IdentityUpdate `protobuf:"bytes,2,rep,name=new_updates,json=newUpdates,proto3" json:"new_updates,omitempty"`
+	OldUpdates []*associations.IdentityUpdate `protobuf:"bytes,1,rep,name=old_updates,json=oldUpdates,proto3" json:"old_updates,omitempty"`
+	NewUpdates []*associations.IdentityUpdate `protobuf:"bytes,2,rep,name=new_updates,json=newUpdates,proto3" json:"new_updates,omitempty"`
+	// Association state the new updates are applied to, in place of replaying
+	// old_updates. Set at most one of old_state and old_updates.
+	OldState      *associations.AssociationState `protobuf:"bytes,3,opt,name=old_state,json=oldState,proto3" json:"old_state,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -350,6 +353,13 @@ func (x *GetAssociationStateRequest) GetNewUpdates() []*associations.IdentityUpd
 	return nil
 }
 
+func (x *GetAssociationStateRequest) GetOldState() *associations.AssociationState {
+	if x != nil {
+		return x.OldState
+	}
+	return nil
+}
+
 // Response to GetAssociationStateRequest, containing the final association
 // state for an InboxID
 type GetAssociationStateResponse struct {
@@ -836,12 +846,13 @@ const file_mls_validation_v1_service_proto_rawDesc = "" +
 	"\x05is_ok\x18\x01 \x01(\bR\x04isOk\x12#\n" +
 	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\x12\x19\n" +
 	"\bgroup_id\x18\x03 \x01(\tR\agroupId\x12\x1b\n" +
-	"\tis_commit\x18\x04 \x01(\bR\bisCommit\"\xb6\x01\n" +
+	"\tis_commit\x18\x04 \x01(\bR\bisCommit\"\x81\x02\n" +
 	"\x1aGetAssociationStateRequest\x12K\n" +
 	"\vold_updates\x18\x01 \x03(\v2*.xmtp.identity.associations.IdentityUpdateR\n" +
 	"oldUpdates\x12K\n" +
 	"\vnew_updates\x18\x02 \x03(\v2*.xmtp.identity.associations.IdentityUpdateR\n" +
-	"newUpdates\"\xc9\x01\n" +
+	"newUpdates\x12I\n" +
+	"\told_state\x18\x03 \x01(\v2,.xmtp.identity.associations.AssociationStateR\boldState\"\xc9\x01\n" +
 	"\x1bGetAssociationStateResponse\x12Y\n" +
 	"\x11association_state\x18\x01 \x01(\v2,.xmtp.identity.associations.AssociationStateR\x10associationState\x12O\n" +
 	"\n" +
@@ -897,22 +908,23 @@ var file_mls_validation_v1_service_proto_depIdxs = []int32{
 	13, // 5: xmtp.mls_validation.v1.ValidateGroupMessagesResponse.responses:type_name -> xmtp.mls_validation.v1.ValidateGroupMessagesResponse.ValidationResponse
 	14, // 6: xmtp.mls_validation.v1.GetAssociationStateRequest.old_updates:type_name -> xmtp.identity.associations.IdentityUpdate
 	14, // 7: xmtp.mls_validation.v1.GetAssociationStateRequest.new_updates:type_name -> xmtp.identity.associations.IdentityUpdate
-	15, // 8: xmtp.mls_validation.v1.GetAssociationStateResponse.association_state:type_name -> xmtp.identity.associations.AssociationState
-	16, // 9: xmtp.mls_validation.v1.GetAssociationStateResponse.state_diff:type_name -> xmtp.identity.associations.AssociationStateDiff
-	17, // 10: xmtp.mls_validation.v1.ValidateInboxIdKeyPackagesResponse.Response.credential:type_name -> xmtp.identity.MlsCredential
-	4,  // 11: xmtp.mls_validation.v1.ValidationApi.ValidateGroupMessages:input_type -> xmtp.mls_validation.v1.ValidateGroupMessagesRequest
-	6,  // 12: xmtp.mls_validation.v1.ValidationApi.GetAssociationState:input_type -> xmtp.mls_validation.v1.GetAssociationStateRequest
-	2,  // 13: xmtp.mls_validation.v1.ValidationApi.ValidateInboxIdKeyPackages:input_type -> xmtp.mls_validation.v1.ValidateKeyPackagesRequest
-	18, // 14: xmtp.mls_validation.v1.ValidationApi.VerifySmartContractWalletSignatures:input_type -> xmtp.identity.api.v1.VerifySmartContractWalletSignaturesRequest
-	5,  // 15: xmtp.mls_validation.v1.ValidationApi.ValidateGroupMessages:output_type -> xmtp.mls_validation.v1.ValidateGroupMessagesResponse
-	7,  // 16: xmtp.mls_validation.v1.ValidationApi.GetAssociationState:output_type -> xmtp.mls_validation.v1.GetAssociationStateResponse
-	1,  // 17: xmtp.mls_validation.v1.ValidationApi.ValidateInboxIdKeyPackages:output_type -> xmtp.mls_validation.v1.ValidateInboxIdKeyPackagesResponse
-	19, // 18: xmtp.mls_validation.v1.ValidationApi.VerifySmartContractWalletSignatures:output_type -> xmtp.identity.api.v1.VerifySmartContractWalletSignaturesResponse
-	15, // [15:19] is the sub-list for method output_type
-	11, // [11:15] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	15, // 8: xmtp.mls_validation.v1.GetAssociationStateRequest.old_state:type_name -> xmtp.identity.associations.AssociationState
+	15, // 9: xmtp.mls_validation.v1.GetAssociationStateResponse.association_state:type_name -> xmtp.identity.associations.AssociationState
+	16, // 10: xmtp.mls_validation.v1.GetAssociationStateResponse.state_diff:type_name -> xmtp.identity.associations.AssociationStateDiff
+	17, // 11: xmtp.mls_validation.v1.ValidateInboxIdKeyPackagesResponse.Response.credential:type_name -> xmtp.identity.MlsCredential
+	4,  // 12: xmtp.mls_validation.v1.ValidationApi.ValidateGroupMessages:input_type -> xmtp.mls_validation.v1.ValidateGroupMessagesRequest
+	6,  // 13: xmtp.mls_validation.v1.ValidationApi.GetAssociationState:input_type -> xmtp.mls_validation.v1.GetAssociationStateRequest
+	2,  // 14: xmtp.mls_validation.v1.ValidationApi.ValidateInboxIdKeyPackages:input_type -> xmtp.mls_validation.v1.ValidateKeyPackagesRequest
+	18, // 15: xmtp.mls_validation.v1.ValidationApi.VerifySmartContractWalletSignatures:input_type -> xmtp.identity.api.v1.VerifySmartContractWalletSignaturesRequest
+	5,  // 16: xmtp.mls_validation.v1.ValidationApi.ValidateGroupMessages:output_type -> xmtp.mls_validation.v1.ValidateGroupMessagesResponse
+	7,  // 17: xmtp.mls_validation.v1.ValidationApi.GetAssociationState:output_type -> xmtp.mls_validation.v1.GetAssociationStateResponse
+	1,  // 18: xmtp.mls_validation.v1.ValidationApi.ValidateInboxIdKeyPackages:output_type -> xmtp.mls_validation.v1.ValidateInboxIdKeyPackagesResponse
+	19, // 19: xmtp.mls_validation.v1.ValidationApi.VerifySmartContractWalletSignatures:output_type -> xmtp.identity.api.v1.VerifySmartContractWalletSignaturesResponse
+	16, // [16:20] is the sub-list for method output_type
+	12, // [12:16] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_mls_validation_v1_service_proto_init() }
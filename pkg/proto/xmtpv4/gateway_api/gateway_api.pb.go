@@ -9,10 +9,13 @@
 package gateway_api
 
 import (
+	envelopes "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/envelopes"
+	metadata_api "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/metadata_api"
 	payer_api "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/payer_api"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
+	sync "sync"
 	unsafe "unsafe"
 )
 
@@ -23,33 +26,312 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type PublishReceiptStatus int32
+
+const (
+	// The receipt is unknown, or expired
+	PublishReceiptStatus_PUBLISH_RECEIPT_STATUS_UNSPECIFIED PublishReceiptStatus = 0
+	PublishReceiptStatus_PUBLISH_RECEIPT_STATUS_PENDING     PublishReceiptStatus = 1
+	PublishReceiptStatus_PUBLISH_RECEIPT_STATUS_PUBLISHED   PublishReceiptStatus = 2
+	PublishReceiptStatus_PUBLISH_RECEIPT_STATUS_FAILED      PublishReceiptStatus = 3
+)
+
+// Enum value maps for PublishReceiptStatus.
+var (
+	PublishReceiptStatus_name = map[int32]string{
+		0: "PUBLISH_RECEIPT_STATUS_UNSPECIFIED",
+		1: "PUBLISH_RECEIPT_STATUS_PENDING",
+		2: "PUBLISH_RECEIPT_STATUS_PUBLISHED",
+		3: "PUBLISH_RECEIPT_STATUS_FAILED",
+	}
+	PublishReceiptStatus_value = map[string]int32{
+		"PUBLISH_RECEIPT_STATUS_UNSPECIFIED": 0,
+		"PUBLISH_RECEIPT_STATUS_PENDING":     1,
+		"PUBLISH_RECEIPT_STATUS_PUBLISHED":   2,
+		"PUBLISH_RECEIPT_STATUS_FAILED":      3,
+	}
+)
+
+func (x PublishReceiptStatus) Enum() *PublishReceiptStatus {
+	p := new(PublishReceiptStatus)
+	*p = x
+	return p
+}
+
+func (x PublishReceiptStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PublishReceiptStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_xmtpv4_gateway_api_gateway_api_proto_enumTypes[0].Descriptor()
+}
+
+func (PublishReceiptStatus) Type() protoreflect.EnumType {
+	return &file_xmtpv4_gateway_api_gateway_api_proto_enumTypes[0]
+}
+
+func (x PublishReceiptStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PublishReceiptStatus.Descriptor instead.
+func (PublishReceiptStatus) EnumDescriptor() ([]byte, []int) {
+	return file_xmtpv4_gateway_api_gateway_api_proto_rawDescGZIP(), []int{0}
+}
+
+type GetPublishReceiptsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReceiptIds    []string               `protobuf:"bytes,1,rep,name=receipt_ids,json=receiptIds,proto3" json:"receipt_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPublishReceiptsRequest) Reset() {
+	*x = GetPublishReceiptsRequest{}
+	mi := &file_xmtpv4_gateway_api_gateway_api_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPublishReceiptsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPublishReceiptsRequest) ProtoMessage() {}
+
+func (x *GetPublishReceiptsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_xmtpv4_gateway_api_gateway_api_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPublishReceiptsRequest.ProtoReflect.Descriptor instead.
+func (*GetPublishReceiptsRequest) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_gateway_api_gateway_api_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetPublishReceiptsRequest) GetReceiptIds() []string {
+	if x != nil {
+		return x.ReceiptIds
+	}
+	return nil
+}
+
+// The outcome of publishing an envelope through the gateway publish outbox
+type PublishReceipt struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ReceiptId string                 `protobuf:"bytes,1,opt,name=receipt_id,json=receiptId,proto3" json:"receipt_id,omitempty"`
+	Status    PublishReceiptStatus   `protobuf:"varint,2,opt,name=status,proto3,enum=xmtp.xmtpv4.gateway_api.PublishReceiptStatus" json:"status,omitempty"`
+	// Set when the envelope was published
+	OriginatorEnvelope *envelopes.OriginatorEnvelope `protobuf:"bytes,3,opt,name=originator_envelope,json=originatorEnvelope,proto3" json:"originator_envelope,omitempty"`
+	// Set when the envelope failed to publish, as a gRPC status code and message
+	ErrorCode    uint32 `protobuf:"varint,4,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	ErrorMessage string `protobuf:"bytes,5,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	// The number of times the gateway attempted to publish the envelope
+	Attempts      uint32 `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishReceipt) Reset() {
+	*x = PublishReceipt{}
+	mi := &file_xmtpv4_gateway_api_gateway_api_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishReceipt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishReceipt) ProtoMessage() {}
+
+func (x *PublishReceipt) ProtoReflect() protoreflect.Message {
+	mi := &file_xmtpv4_gateway_api_gateway_api_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishReceipt.ProtoReflect.Descriptor instead.
+func (*PublishReceipt) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_gateway_api_gateway_api_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PublishReceipt) GetReceiptId() string {
+	if x != nil {
+		return x.ReceiptId
+	}
+	return ""
+}
+
+func (x *PublishReceipt) GetStatus() PublishReceiptStatus {
+	if x != nil {
+		return x.Status
+	}
+	return PublishReceiptStatus_PUBLISH_RECEIPT_STATUS_UNSPECIFIED
+}
+
+func (x *PublishReceipt) GetOriginatorEnvelope() *envelopes.OriginatorEnvelope {
+	if x != nil {
+		return x.OriginatorEnvelope
+	}
+	return nil
+}
+
+func (x *PublishReceipt) GetErrorCode() uint32 {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return 0
+}
+
+func (x *PublishReceipt) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *PublishReceipt) GetAttempts() uint32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+type GetPublishReceiptsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The receipts, in request order
+	Receipts      []*PublishReceipt `protobuf:"bytes,1,rep,name=receipts,proto3" json:"receipts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPublishReceiptsResponse) Reset() {
+	*x = GetPublishReceiptsResponse{}
+	mi := &file_xmtpv4_gateway_api_gateway_api_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPublishReceiptsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPublishReceiptsResponse) ProtoMessage() {}
+
+func (x *GetPublishReceiptsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_xmtpv4_gateway_api_gateway_api_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPublishReceiptsResponse.ProtoReflect.Descriptor instead.
+func (*GetPublishReceiptsResponse) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_gateway_api_gateway_api_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetPublishReceiptsResponse) GetReceipts() []*PublishReceipt {
+	if x != nil {
+		return x.Receipts
+	}
+	return nil
+}
+
 var File_xmtpv4_gateway_api_gateway_api_proto protoreflect.FileDescriptor
 
 const file_xmtpv4_gateway_api_gateway_api_proto_rawDesc = "" +
 	"\n" +
-	"$xmtpv4/gateway_api/gateway_api.proto\x12\x17xmtp.xmtpv4.gateway_api\x1a xmtpv4/payer_api/payer_api.proto2\xf5\x01\n" +
+	"$xmtpv4/gateway_api/gateway_api.proto\x12\x17xmtp.xmtpv4.gateway_api\x1a xmtpv4/envelopes/envelopes.proto\x1a&xmtpv4/metadata_api/metadata_api.proto\x1a xmtpv4/payer_api/payer_api.proto\"<\n" +
+	"\x19GetPublishReceiptsRequest\x12\x1f\n" +
+	"\vreceipt_ids\x18\x01 \x03(\tR\n" +
+	"receiptIds\"\xb2\x02\n" +
+	"\x0ePublishReceipt\x12\x1d\n" +
+	"\n" +
+	"receipt_id\x18\x01 \x01(\tR\treceiptId\x12E\n" +
+	"\x06status\x18\x02 \x01(\x0e2-.xmtp.xmtpv4.gateway_api.PublishReceiptStatusR\x06status\x12Z\n" +
+	"\x13originator_envelope\x18\x03 \x01(\v2).xmtp.xmtpv4.envelopes.OriginatorEnvelopeR\x12originatorEnvelope\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x04 \x01(\rR\terrorCode\x12#\n" +
+	"\rerror_message\x18\x05 \x01(\tR\ferrorMessage\x12\x1a\n" +
+	"\battempts\x18\x06 \x01(\rR\battempts\"a\n" +
+	"\x1aGetPublishReceiptsResponse\x12C\n" +
+	"\breceipts\x18\x01 \x03(\v2'.xmtp.xmtpv4.gateway_api.PublishReceiptR\breceipts*\xab\x01\n" +
+	"\x14PublishReceiptStatus\x12&\n" +
+	"\"PUBLISH_RECEIPT_STATUS_UNSPECIFIED\x10\x00\x12\"\n" +
+	"\x1ePUBLISH_RECEIPT_STATUS_PENDING\x10\x01\x12$\n" +
+	" PUBLISH_RECEIPT_STATUS_PUBLISHED\x10\x02\x12!\n" +
+	"\x1dPUBLISH_RECEIPT_STATUS_FAILED\x10\x032\xe4\x03\n" +
 	"\n" +
 	"GatewayApi\x12\x87\x01\n" +
 	"\x16PublishClientEnvelopes\x124.xmtp.xmtpv4.payer_api.PublishClientEnvelopesRequest\x1a5.xmtp.xmtpv4.payer_api.PublishClientEnvelopesResponse\"\x00\x12]\n" +
-	"\bGetNodes\x12&.xmtp.xmtpv4.payer_api.GetNodesRequest\x1a'.xmtp.xmtpv4.payer_api.GetNodesResponse\"\x00B\xdc\x01\n" +
+	"\bGetNodes\x12&.xmtp.xmtpv4.payer_api.GetNodesRequest\x1a'.xmtp.xmtpv4.payer_api.GetNodesResponse\"\x00\x12\x7f\n" +
+	"\x12GetPublishReceipts\x122.xmtp.xmtpv4.gateway_api.GetPublishReceiptsRequest\x1a3.xmtp.xmtpv4.gateway_api.GetPublishReceiptsResponse\"\x00\x12l\n" +
+	"\vGetFeeQuote\x12,.xmtp.xmtpv4.metadata_api.GetFeeQuoteRequest\x1a-.xmtp.xmtpv4.metadata_api.GetFeeQuoteResponse\"\x00B\xdc\x01\n" +
 	"\x1bcom.xmtp.xmtpv4.gateway_apiB\x0fGatewayApiProtoP\x01Z2github.com/xmtp/xmtpd/pkg/proto/xmtpv4/gateway_api\xa2\x02\x03XXG\xaa\x02\x16Xmtp.Xmtpv4.GatewayApi\xca\x02\x16Xmtp\\Xmtpv4\\GatewayApi\xe2\x02\"Xmtp\\Xmtpv4\\GatewayApi\\GPBMetadata\xea\x02\x18Xmtp::Xmtpv4::GatewayApib\x06proto3"
 
+var (
+	file_xmtpv4_gateway_api_gateway_api_proto_rawDescOnce sync.Once
+	file_xmtpv4_gateway_api_gateway_api_proto_rawDescData []byte
+)
+
+func file_xmtpv4_gateway_api_gateway_api_proto_rawDescGZIP() []byte {
+	file_xmtpv4_gateway_api_gateway_api_proto_rawDescOnce.Do(func() {
+		file_xmtpv4_gateway_api_gateway_api_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_xmtpv4_gateway_api_gateway_api_proto_rawDesc), len(file_xmtpv4_gateway_api_gateway_api_proto_rawDesc)))
+	})
+	return file_xmtpv4_gateway_api_gateway_api_proto_rawDescData
+}
+
+var file_xmtpv4_gateway_api_gateway_api_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_xmtpv4_gateway_api_gateway_api_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_xmtpv4_gateway_api_gateway_api_proto_goTypes = []any{
-	(*payer_api.PublishClientEnvelopesRequest)(nil),  // 0: xmtp.xmtpv4.payer_api.PublishClientEnvelopesRequest
-	(*payer_api.GetNodesRequest)(nil),                // 1: xmtp.xmtpv4.payer_api.GetNodesRequest
-	(*payer_api.PublishClientEnvelopesResponse)(nil), // 2: xmtp.xmtpv4.payer_api.PublishClientEnvelopesResponse
-	(*payer_api.GetNodesResponse)(nil),               // 3: xmtp.xmtpv4.payer_api.GetNodesResponse
+	(PublishReceiptStatus)(0),                        // 0: xmtp.xmtpv4.gateway_api.PublishReceiptStatus
+	(*GetPublishReceiptsRequest)(nil),                // 1: xmtp.xmtpv4.gateway_api.GetPublishReceiptsRequest
+	(*PublishReceipt)(nil),                           // 2: xmtp.xmtpv4.gateway_api.PublishReceipt
+	(*GetPublishReceiptsResponse)(nil),               // 3: xmtp.xmtpv4.gateway_api.GetPublishReceiptsResponse
+	(*envelopes.OriginatorEnvelope)(nil),             // 4: xmtp.xmtpv4.envelopes.OriginatorEnvelope
+	(*payer_api.PublishClientEnvelopesRequest)(nil),  // 5: xmtp.xmtpv4.payer_api.PublishClientEnvelopesRequest
+	(*payer_api.GetNodesRequest)(nil),                // 6: xmtp.xmtpv4.payer_api.GetNodesRequest
+	(*metadata_api.GetFeeQuoteRequest)(nil),          // 7: xmtp.xmtpv4.metadata_api.GetFeeQuoteRequest
+	(*payer_api.PublishClientEnvelopesResponse)(nil), // 8: xmtp.xmtpv4.payer_api.PublishClientEnvelopesResponse
+	(*payer_api.GetNodesResponse)(nil),               // 9: xmtp.xmtpv4.payer_api.GetNodesResponse
+	(*metadata_api.GetFeeQuoteResponse)(nil),         // 10: xmtp.xmtpv4.metadata_api.GetFeeQuoteResponse
 }
 var file_xmtpv4_gateway_api_gateway_api_proto_depIdxs = []int32{
-	0, // 0: xmtp.xmtpv4.gateway_api.GatewayApi.PublishClientEnvelopes:input_type -> xmtp.xmtpv4.payer_api.PublishClientEnvelopesRequest
-	1, // 1: xmtp.xmtpv4.gateway_api.GatewayApi.GetNodes:input_type -> xmtp.xmtpv4.payer_api.GetNodesRequest
-	2, // 2: xmtp.xmtpv4.gateway_api.GatewayApi.PublishClientEnvelopes:output_type -> xmtp.xmtpv4.payer_api.PublishClientEnvelopesResponse
-	3, // 3: xmtp.xmtpv4.gateway_api.GatewayApi.GetNodes:output_type -> xmtp.xmtpv4.payer_api.GetNodesResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: xmtp.xmtpv4.gateway_api.PublishReceipt.status:type_name -> xmtp.xmtpv4.gateway_api.PublishReceiptStatus
+	4,  // 1: xmtp.xmtpv4.gateway_api.PublishReceipt.originator_envelope:type_name -> xmtp.xmtpv4.envelopes.OriginatorEnvelope
+	2,  // 2: xmtp.xmtpv4.gateway_api.GetPublishReceiptsResponse.receipts:type_name -> xmtp.xmtpv4.gateway_api.PublishReceipt
+	5,  // 3: xmtp.xmtpv4.gateway_api.GatewayApi.PublishClientEnvelopes:input_type -> xmtp.xmtpv4.payer_api.PublishClientEnvelopesRequest
+	6,  // 4: xmtp.xmtpv4.gateway_api.GatewayApi.GetNodes:input_type -> xmtp.xmtpv4.payer_api.GetNodesRequest
+	1,  // 5: xmtp.xmtpv4.gateway_api.GatewayApi.GetPublishReceipts:input_type -> xmtp.xmtpv4.gateway_api.GetPublishReceiptsRequest
+	7,  // 6: xmtp.xmtpv4.gateway_api.GatewayApi.GetFeeQuote:input_type -> xmtp.xmtpv4.metadata_api.GetFeeQuoteRequest
+	8,  // 7: xmtp.xmtpv4.gateway_api.GatewayApi.PublishClientEnvelopes:output_type -> xmtp.xmtpv4.payer_api.PublishClientEnvelopesResponse
+	9,  // 8: xmtp.xmtpv4.gateway_api.GatewayApi.GetNodes:output_type -> xmtp.xmtpv4.payer_api.GetNodesResponse
+	3,  // 9: xmtp.xmtpv4.gateway_api.GatewayApi.GetPublishReceipts:output_type -> xmtp.xmtpv4.gateway_api.GetPublishReceiptsResponse
+	10, // 10: xmtp.xmtpv4.gateway_api.GatewayApi.GetFeeQuote:output_type -> xmtp.xmtpv4.metadata_api.GetFeeQuoteResponse
+	7,  // [7:11] is the sub-list for method output_type
+	3,  // [3:7] is the sub-list for method input_type
+	3,  // [3:3] is the sub-list for extension type_name
+	3,  // [3:3] is the sub-list for extension extendee
+	0,  // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_xmtpv4_gateway_api_gateway_api_proto_init() }
@@ -62,13 +344,15 @@ func file_xmtpv4_gateway_api_gateway_api_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_xmtpv4_gateway_api_gateway_api_proto_rawDesc), len(file_xmtpv4_gateway_api_gateway_api_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   0,
+			NumEnums:      1,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_xmtpv4_gateway_api_gateway_api_proto_goTypes,
 		DependencyIndexes: file_xmtpv4_gateway_api_gateway_api_proto_depIdxs,
+		EnumInfos:         file_xmtpv4_gateway_api_gateway_api_proto_enumTypes,
+		MessageInfos:      file_xmtpv4_gateway_api_gateway_api_proto_msgTypes,
 	}.Build()
 	File_xmtpv4_gateway_api_gateway_api_proto = out.File
 	file_xmtpv4_gateway_api_gateway_api_proto_goTypes = nil
@@ -10,6 +10,7 @@ package gateway_api
 
 import (
 	context "context"
+	metadata_api "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/metadata_api"
 	payer_api "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/payer_api"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
@@ -24,16 +25,19 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	GatewayApi_PublishClientEnvelopes_FullMethodName = "/xmtp.xmtpv4.gateway_api.GatewayApi/PublishClientEnvelopes"
 	GatewayApi_GetNodes_FullMethodName               = "/xmtp.xmtpv4.gateway_api.GatewayApi/GetNodes"
+	GatewayApi_GetPublishReceipts_FullMethodName     = "/xmtp.xmtpv4.gateway_api.GatewayApi/GetPublishReceipts"
+	GatewayApi_GetFeeQuote_FullMethodName            = "/xmtp.xmtpv4.gateway_api.GatewayApi/GetFeeQuote"
 )
 
 // GatewayApiClient is the client API for GatewayApi service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
-//
-// Client -> Gateway. Replaces payer_api.PayerApi.
 type GatewayApiClient interface {
 	PublishClientEnvelopes(ctx context.Context, in *payer_api.PublishClientEnvelopesRequest, opts ...grpc.CallOption) (*payer_api.PublishClientEnvelopesResponse, error)
 	GetNodes(ctx context.Context, in *payer_api.GetNodesRequest, opts ...grpc.CallOption) (*payer_api.GetNodesResponse, error)
+	GetPublishReceipts(ctx context.Context, in *GetPublishReceiptsRequest, opts ...grpc.CallOption) (*GetPublishReceiptsResponse, error)
+	// Quote the fees the target originator charges to publish an envelope
+	GetFeeQuote(ctx context.Context, in *metadata_api.GetFeeQuoteRequest, opts ...grpc.CallOption) (*metadata_api.GetFeeQuoteResponse, error)
 }
 
 type gatewayApiClient struct {
@@ -64,14 +68,35 @@ func (c *gatewayApiClient) GetNodes(ctx context.Context, in *payer_api.GetNodesR
 	return out, nil
 }
 
+func (c *gatewayApiClient) GetPublishReceipts(ctx context.Context, in *GetPublishReceiptsRequest, opts ...grpc.CallOption) (*GetPublishReceiptsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPublishReceiptsResponse)
+	err := c.cc.Invoke(ctx, GatewayApi_GetPublishReceipts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gatewayApiClient) GetFeeQuote(ctx context.Context, in *metadata_api.GetFeeQuoteRequest, opts ...grpc.CallOption) (*metadata_api.GetFeeQuoteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(metadata_api.GetFeeQuoteResponse)
+	err := c.cc.Invoke(ctx, GatewayApi_GetFeeQuote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // GatewayApiServer is the server API for GatewayApi service.
 // All implementations should embed UnimplementedGatewayApiServer
 // for forward compatibility.
-//
-// Client -> Gateway. Replaces payer_api.PayerApi.
 type GatewayApiServer interface {
 	PublishClientEnvelopes(context.Context, *payer_api.PublishClientEnvelopesRequest) (*payer_api.PublishClientEnvelopesResponse, error)
 	GetNodes(context.Context, *payer_api.GetNodesRequest) (*payer_api.GetNodesResponse, error)
+	GetPublishReceipts(context.Context, *GetPublishReceiptsRequest) (*GetPublishReceiptsResponse, error)
+	// Quote the fees the target originator charges to publish an envelope
+	GetFeeQuote(context.Context, *metadata_api.GetFeeQuoteRequest) (*metadata_api.GetFeeQuoteResponse, error)
 }
 
 // UnimplementedGatewayApiServer should be embedded to have
@@ -87,6 +112,12 @@ func (UnimplementedGatewayApiServer) PublishClientEnvelopes(context.Context, *pa
 func (UnimplementedGatewayApiServer) GetNodes(context.Context, *payer_api.GetNodesRequest) (*payer_api.GetNodesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetNodes not implemented")
 }
+func (UnimplementedGatewayApiServer) GetPublishReceipts(context.Context, *GetPublishReceiptsRequest) (*GetPublishReceiptsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPublishReceipts not implemented")
+}
+func (UnimplementedGatewayApiServer) GetFeeQuote(context.Context, *metadata_api.GetFeeQuoteRequest) (*metadata_api.GetFeeQuoteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFeeQuote not implemented")
+}
 func (UnimplementedGatewayApiServer) testEmbeddedByValue() {}
 
 // UnsafeGatewayApiServer may be embedded to opt out of forward compatibility for this service.
@@ -143,6 +174,42 @@ func _GatewayApi_GetNodes_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GatewayApi_GetPublishReceipts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPublishReceiptsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayApiServer).GetPublishReceipts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GatewayApi_GetPublishReceipts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayApiServer).GetPublishReceipts(ctx, req.(*GetPublishReceiptsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GatewayApi_GetFeeQuote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(metadata_api.GetFeeQuoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayApiServer).GetFeeQuote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GatewayApi_GetFeeQuote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayApiServer).GetFeeQuote(ctx, req.(*metadata_api.GetFeeQuoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // GatewayApi_ServiceDesc is the grpc.ServiceDesc for GatewayApi service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -158,6 +225,14 @@ var GatewayApi_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetNodes",
 			Handler:    _GatewayApi_GetNodes_Handler,
 		},
+		{
+			MethodName: "GetPublishReceipts",
+			Handler:    _GatewayApi_GetPublishReceipts_Handler,
+		},
+		{
+			MethodName: "GetFeeQuote",
+			Handler:    _GatewayApi_GetFeeQuote_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "xmtpv4/gateway_api/gateway_api.proto",
@@ -11,6 +11,7 @@ import (
 	context "context"
 	errors "errors"
 	gateway_api "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/gateway_api"
+	metadata_api "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/metadata_api"
 	payer_api "github.com/xmtp/xmtpd/pkg/proto/xmtpv4/payer_api"
 	http "net/http"
 	strings "strings"
@@ -41,12 +42,20 @@ const (
 	GatewayApiPublishClientEnvelopesProcedure = "/xmtp.xmtpv4.gateway_api.GatewayApi/PublishClientEnvelopes"
 	// GatewayApiGetNodesProcedure is the fully-qualified name of the GatewayApi's GetNodes RPC.
 	GatewayApiGetNodesProcedure = "/xmtp.xmtpv4.gateway_api.GatewayApi/GetNodes"
+	// GatewayApiGetPublishReceiptsProcedure is the fully-qualified name of the GatewayApi's
+	// GetPublishReceipts RPC.
+	GatewayApiGetPublishReceiptsProcedure = "/xmtp.xmtpv4.gateway_api.GatewayApi/GetPublishReceipts"
+	// GatewayApiGetFeeQuoteProcedure is the fully-qualified name of the GatewayApi's GetFeeQuote RPC.
+	GatewayApiGetFeeQuoteProcedure = "/xmtp.xmtpv4.gateway_api.GatewayApi/GetFeeQuote"
 )
 
 // GatewayApiClient is a client for the xmtp.xmtpv4.gateway_api.GatewayApi service.
 type GatewayApiClient interface {
 	PublishClientEnvelopes(context.Context, *connect.Request[payer_api.PublishClientEnvelopesRequest]) (*connect.Response[payer_api.PublishClientEnvelopesResponse], error)
 	GetNodes(context.Context, *connect.Request[payer_api.GetNodesRequest]) (*connect.Response[payer_api.GetNodesResponse], error)
+	GetPublishReceipts(context.Context, *connect.Request[gateway_api.GetPublishReceiptsRequest]) (*connect.Response[gateway_api.GetPublishReceiptsResponse], error)
+	// Quote the fees the target originator charges to publish an envelope
+	GetFeeQuote(context.Context, *connect.Request[metadata_api.GetFeeQuoteRequest]) (*connect.Response[metadata_api.GetFeeQuoteResponse], error)
 }
 
 // NewGatewayApiClient constructs a client for the xmtp.xmtpv4.gateway_api.GatewayApi service. By
@@ -72,6 +81,18 @@ func NewGatewayApiClient(httpClient connect.HTTPClient, baseURL string, opts ...
 			connect.WithSchema(gatewayApiMethods.ByName("GetNodes")),
 			connect.WithClientOptions(opts...),
 		),
+		getPublishReceipts: connect.NewClient[gateway_api.GetPublishReceiptsRequest, gateway_api.GetPublishReceiptsResponse](
+			httpClient,
+			baseURL+GatewayApiGetPublishReceiptsProcedure,
+			connect.WithSchema(gatewayApiMethods.ByName("GetPublishReceipts")),
+			connect.WithClientOptions(opts...),
+		),
+		getFeeQuote: connect.NewClient[metadata_api.GetFeeQuoteRequest, metadata_api.GetFeeQuoteResponse](
+			httpClient,
+			baseURL+GatewayApiGetFeeQuoteProcedure,
+			connect.WithSchema(gatewayApiMethods.ByName("GetFeeQuote")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
@@ -79,6 +100,8 @@ func NewGatewayApiClient(httpClient connect.HTTPClient, baseURL string, opts ...
 type gatewayApiClient struct {
 	publishClientEnvelopes *connect.Client[payer_api.PublishClientEnvelopesRequest, payer_api.PublishClientEnvelopesResponse]
 	getNodes               *connect.Client[payer_api.GetNodesRequest, payer_api.GetNodesResponse]
+	getPublishReceipts     *connect.Client[gateway_api.GetPublishReceiptsRequest, gateway_api.GetPublishReceiptsResponse]
+	getFeeQuote            *connect.Client[metadata_api.GetFeeQuoteRequest, metadata_api.GetFeeQuoteResponse]
 }
 
 // PublishClientEnvelopes calls xmtp.xmtpv4.gateway_api.GatewayApi.PublishClientEnvelopes.
@@ -91,10 +114,23 @@ func (c *gatewayApiClient) GetNodes(ctx context.Context, req *connect.Request[pa
 	return c.getNodes.CallUnary(ctx, req)
 }
 
+// GetPublishReceipts calls xmtp.xmtpv4.gateway_api.GatewayApi.GetPublishReceipts.
+func (c *gatewayApiClient) GetPublishReceipts(ctx context.Context, req *connect.Request[gateway_api.GetPublishReceiptsRequest]) (*connect.Response[gateway_api.GetPublishReceiptsResponse], error) {
+	return c.getPublishReceipts.CallUnary(ctx, req)
+}
+
+// GetFeeQuote calls xmtp.xmtpv4.gateway_api.GatewayApi.GetFeeQuote.
+func (c *gatewayApiClient) GetFeeQuote(ctx context.Context, req *connect.Request[metadata_api.GetFeeQuoteRequest]) (*connect.Response[metadata_api.GetFeeQuoteResponse], error) {
+	return c.getFeeQuote.CallUnary(ctx, req)
+}
+
 // GatewayApiHandler is an implementation of the xmtp.xmtpv4.gateway_api.GatewayApi service.
 type GatewayApiHandler interface {
 	PublishClientEnvelopes(context.Context, *connect.Request[payer_api.PublishClientEnvelopesRequest]) (*connect.Response[payer_api.PublishClientEnvelopesResponse], error)
 	GetNodes(context.Context, *connect.Request[payer_api.GetNodesRequest]) (*connect.Response[payer_api.GetNodesResponse], error)
+	GetPublishReceipts(context.Context, *connect.Request[gateway_api.GetPublishReceiptsRequest]) (*connect.Response[gateway_api.GetPublishReceiptsResponse], error)
+	// Quote the fees the target originator charges to publish an envelope
+	GetFeeQuote(context.Context, *connect.Request[metadata_api.GetFeeQuoteRequest]) (*connect.Response[metadata_api.GetFeeQuoteResponse], error)
 }
 
 // NewGatewayApiHandler builds an HTTP handler from the service implementation. It returns the path
@@ -116,12 +152,28 @@ func NewGatewayApiHandler(svc GatewayApiHandler, opts ...connect.HandlerOption)
 		connect.WithSchema(gatewayApiMethods.ByName("GetNodes")),
 		connect.WithHandlerOptions(opts...),
 	)
+	gatewayApiGetPublishReceiptsHandler := connect.NewUnaryHandler(
+		GatewayApiGetPublishReceiptsProcedure,
+		svc.GetPublishReceipts,
+		connect.WithSchema(gatewayApiMethods.ByName("GetPublishReceipts")),
+		connect.WithHandlerOptions(opts...),
+	)
+	gatewayApiGetFeeQuoteHandler := connect.NewUnaryHandler(
+		GatewayApiGetFeeQuoteProcedure,
+		svc.GetFeeQuote,
+		connect.WithSchema(gatewayApiMethods.ByName("GetFeeQuote")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/xmtp.xmtpv4.gateway_api.GatewayApi/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case GatewayApiPublishClientEnvelopesProcedure:
 			gatewayApiPublishClientEnvelopesHandler.ServeHTTP(w, r)
 		case GatewayApiGetNodesProcedure:
 			gatewayApiGetNodesHandler.ServeHTTP(w, r)
+		case GatewayApiGetPublishReceiptsProcedure:
+			gatewayApiGetPublishReceiptsHandler.ServeHTTP(w, r)
+		case GatewayApiGetFeeQuoteProcedure:
+			gatewayApiGetFeeQuoteHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -138,3 +190,11 @@ func (UnimplementedGatewayApiHandler) PublishClientEnvelopes(context.Context, *c
 func (UnimplementedGatewayApiHandler) GetNodes(context.Context, *connect.Request[payer_api.GetNodesRequest]) (*connect.Response[payer_api.GetNodesResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("xmtp.xmtpv4.gateway_api.GatewayApi.GetNodes is not implemented"))
 }
+
+func (UnimplementedGatewayApiHandler) GetPublishReceipts(context.Context, *connect.Request[gateway_api.GetPublishReceiptsRequest]) (*connect.Response[gateway_api.GetPublishReceiptsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("xmtp.xmtpv4.gateway_api.GatewayApi.GetPublishReceipts is not implemented"))
+}
+
+func (UnimplementedGatewayApiHandler) GetFeeQuote(context.Context, *connect.Request[metadata_api.GetFeeQuoteRequest]) (*connect.Response[metadata_api.GetFeeQuoteResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("xmtp.xmtpv4.gateway_api.GatewayApi.GetFeeQuote is not implemented"))
+}
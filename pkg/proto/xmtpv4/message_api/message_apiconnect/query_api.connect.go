@@ -47,6 +47,12 @@ const (
 	// QueryApiGetNewestEnvelopeProcedure is the fully-qualified name of the QueryApi's
 	// GetNewestEnvelope RPC.
 	QueryApiGetNewestEnvelopeProcedure = "/xmtp.xmtpv4.message_api.QueryApi/GetNewestEnvelope"
+	// QueryApiGetIdentifiersForInboxProcedure is the fully-qualified name of the QueryApi's
+	// GetIdentifiersForInbox RPC.
+	QueryApiGetIdentifiersForInboxProcedure = "/xmtp.xmtpv4.message_api.QueryApi/GetIdentifiersForInbox"
+	// QueryApiGetInboxAssociationStateProcedure is the fully-qualified name of the QueryApi's
+	// GetInboxAssociationState RPC.
+	QueryApiGetInboxAssociationStateProcedure = "/xmtp.xmtpv4.message_api.QueryApi/GetInboxAssociationState"
 )
 
 // QueryApiClient is a client for the xmtp.xmtpv4.message_api.QueryApi service.
@@ -61,6 +67,11 @@ type QueryApiClient interface {
 	Subscribe(context.Context) *connect.BidiStreamForClient[message_api.SubscribeRequest, message_api.SubscribeResponse]
 	GetInboxIds(context.Context, *connect.Request[message_api.GetInboxIdsRequest]) (*connect.Response[message_api.GetInboxIdsResponse], error)
 	GetNewestEnvelope(context.Context, *connect.Request[message_api.GetNewestEnvelopeRequest]) (*connect.Response[message_api.GetNewestEnvelopeResponse], error)
+	// Returns the identifiers associated with an inbox, along with the identity updates that
+	// associated or revoked them.
+	GetIdentifiersForInbox(context.Context, *connect.Request[message_api.GetIdentifiersForInboxRequest]) (*connect.Response[message_api.GetIdentifiersForInboxResponse], error)
+	// Returns the latest association state of an inbox stored by the node.
+	GetInboxAssociationState(context.Context, *connect.Request[message_api.GetInboxAssociationStateRequest]) (*connect.Response[message_api.GetInboxAssociationStateResponse], error)
 }
 
 // NewQueryApiClient constructs a client for the xmtp.xmtpv4.message_api.QueryApi service. By
@@ -104,16 +115,30 @@ func NewQueryApiClient(httpClient connect.HTTPClient, baseURL string, opts ...co
 			connect.WithSchema(queryApiMethods.ByName("GetNewestEnvelope")),
 			connect.WithClientOptions(opts...),
 		),
+		getIdentifiersForInbox: connect.NewClient[message_api.GetIdentifiersForInboxRequest, message_api.GetIdentifiersForInboxResponse](
+			httpClient,
+			baseURL+QueryApiGetIdentifiersForInboxProcedure,
+			connect.WithSchema(queryApiMethods.ByName("GetIdentifiersForInbox")),
+			connect.WithClientOptions(opts...),
+		),
+		getInboxAssociationState: connect.NewClient[message_api.GetInboxAssociationStateRequest, message_api.GetInboxAssociationStateResponse](
+			httpClient,
+			baseURL+QueryApiGetInboxAssociationStateProcedure,
+			connect.WithSchema(queryApiMethods.ByName("GetInboxAssociationState")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // queryApiClient implements QueryApiClient.
 type queryApiClient struct {
-	queryEnvelopes    *connect.Client[message_api.QueryEnvelopesRequest, message_api.QueryEnvelopesResponse]
-	subscribeTopics   *connect.Client[message_api.SubscribeTopicsRequest, message_api.SubscribeTopicsResponse]
-	subscribe         *connect.Client[message_api.SubscribeRequest, message_api.SubscribeResponse]
-	getInboxIds       *connect.Client[message_api.GetInboxIdsRequest, message_api.GetInboxIdsResponse]
-	getNewestEnvelope *connect.Client[message_api.GetNewestEnvelopeRequest, message_api.GetNewestEnvelopeResponse]
+	queryEnvelopes           *connect.Client[message_api.QueryEnvelopesRequest, message_api.QueryEnvelopesResponse]
+	subscribeTopics          *connect.Client[message_api.SubscribeTopicsRequest, message_api.SubscribeTopicsResponse]
+	subscribe                *connect.Client[message_api.SubscribeRequest, message_api.SubscribeResponse]
+	getInboxIds              *connect.Client[message_api.GetInboxIdsRequest, message_api.GetInboxIdsResponse]
+	getNewestEnvelope        *connect.Client[message_api.GetNewestEnvelopeRequest, message_api.GetNewestEnvelopeResponse]
+	getIdentifiersForInbox   *connect.Client[message_api.GetIdentifiersForInboxRequest, message_api.GetIdentifiersForInboxResponse]
+	getInboxAssociationState *connect.Client[message_api.GetInboxAssociationStateRequest, message_api.GetInboxAssociationStateResponse]
 }
 
 // QueryEnvelopes calls xmtp.xmtpv4.message_api.QueryApi.QueryEnvelopes.
@@ -141,6 +166,16 @@ func (c *queryApiClient) GetNewestEnvelope(ctx context.Context, req *connect.Req
 	return c.getNewestEnvelope.CallUnary(ctx, req)
 }
 
+// GetIdentifiersForInbox calls xmtp.xmtpv4.message_api.QueryApi.GetIdentifiersForInbox.
+func (c *queryApiClient) GetIdentifiersForInbox(ctx context.Context, req *connect.Request[message_api.GetIdentifiersForInboxRequest]) (*connect.Response[message_api.GetIdentifiersForInboxResponse], error) {
+	return c.getIdentifiersForInbox.CallUnary(ctx, req)
+}
+
+// GetInboxAssociationState calls xmtp.xmtpv4.message_api.QueryApi.GetInboxAssociationState.
+func (c *queryApiClient) GetInboxAssociationState(ctx context.Context, req *connect.Request[message_api.GetInboxAssociationStateRequest]) (*connect.Response[message_api.GetInboxAssociationStateResponse], error) {
+	return c.getInboxAssociationState.CallUnary(ctx, req)
+}
+
 // QueryApiHandler is an implementation of the xmtp.xmtpv4.message_api.QueryApi service.
 type QueryApiHandler interface {
 	QueryEnvelopes(context.Context, *connect.Request[message_api.QueryEnvelopesRequest]) (*connect.Response[message_api.QueryEnvelopesResponse], error)
@@ -153,6 +188,11 @@ type QueryApiHandler interface {
 	Subscribe(context.Context, *connect.BidiStream[message_api.SubscribeRequest, message_api.SubscribeResponse]) error
 	GetInboxIds(context.Context, *connect.Request[message_api.GetInboxIdsRequest]) (*connect.Response[message_api.GetInboxIdsResponse], error)
 	GetNewestEnvelope(context.Context, *connect.Request[message_api.GetNewestEnvelopeRequest]) (*connect.Response[message_api.GetNewestEnvelopeResponse], error)
+	// Returns the identifiers associated with an inbox, along with the identity updates that
+	// associated or revoked them.
+	GetIdentifiersForInbox(context.Context, *connect.Request[message_api.GetIdentifiersForInboxRequest]) (*connect.Response[message_api.GetIdentifiersForInboxResponse], error)
+	// Returns the latest association state of an inbox stored by the node.
+	GetInboxAssociationState(context.Context, *connect.Request[message_api.GetInboxAssociationStateRequest]) (*connect.Response[message_api.GetInboxAssociationStateResponse], error)
 }
 
 // NewQueryApiHandler builds an HTTP handler from the service implementation. It returns the path on
@@ -192,6 +232,18 @@ func NewQueryApiHandler(svc QueryApiHandler, opts ...connect.HandlerOption) (str
 		connect.WithSchema(queryApiMethods.ByName("GetNewestEnvelope")),
 		connect.WithHandlerOptions(opts...),
 	)
+	queryApiGetIdentifiersForInboxHandler := connect.NewUnaryHandler(
+		QueryApiGetIdentifiersForInboxProcedure,
+		svc.GetIdentifiersForInbox,
+		connect.WithSchema(queryApiMethods.ByName("GetIdentifiersForInbox")),
+		connect.WithHandlerOptions(opts...),
+	)
+	queryApiGetInboxAssociationStateHandler := connect.NewUnaryHandler(
+		QueryApiGetInboxAssociationStateProcedure,
+		svc.GetInboxAssociationState,
+		connect.WithSchema(queryApiMethods.ByName("GetInboxAssociationState")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/xmtp.xmtpv4.message_api.QueryApi/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case QueryApiQueryEnvelopesProcedure:
@@ -204,6 +256,10 @@ func NewQueryApiHandler(svc QueryApiHandler, opts ...connect.HandlerOption) (str
 			queryApiGetInboxIdsHandler.ServeHTTP(w, r)
 		case QueryApiGetNewestEnvelopeProcedure:
 			queryApiGetNewestEnvelopeHandler.ServeHTTP(w, r)
+		case QueryApiGetIdentifiersForInboxProcedure:
+			queryApiGetIdentifiersForInboxHandler.ServeHTTP(w, r)
+		case QueryApiGetInboxAssociationStateProcedure:
+			queryApiGetInboxAssociationStateHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -232,3 +288,11 @@ func (UnimplementedQueryApiHandler) GetInboxIds(context.Context, *connect.Reques
 func (UnimplementedQueryApiHandler) GetNewestEnvelope(context.Context, *connect.Request[message_api.GetNewestEnvelopeRequest]) (*connect.Response[message_api.GetNewestEnvelopeResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("xmtp.xmtpv4.message_api.QueryApi.GetNewestEnvelope is not implemented"))
 }
+
+func (UnimplementedQueryApiHandler) GetIdentifiersForInbox(context.Context, *connect.Request[message_api.GetIdentifiersForInboxRequest]) (*connect.Response[message_api.GetIdentifiersForInboxResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("xmtp.xmtpv4.message_api.QueryApi.GetIdentifiersForInbox is not implemented"))
+}
+
+func (UnimplementedQueryApiHandler) GetInboxAssociationState(context.Context, *connect.Request[message_api.GetInboxAssociationStateRequest]) (*connect.Response[message_api.GetInboxAssociationStateResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("xmtp.xmtpv4.message_api.QueryApi.GetInboxAssociationState is not implemented"))
+}
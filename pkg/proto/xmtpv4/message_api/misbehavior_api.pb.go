@@ -37,6 +37,7 @@ const (
 	Misbehavior_MISBEHAVIOR_CAUSAL_ORDERING          Misbehavior = 6
 	Misbehavior_MISBEHAVIOR_INVALID_PAYLOAD          Misbehavior = 7
 	Misbehavior_MISBEHAVIOR_BLOCKCHAIN_INCONSISTENCY Misbehavior = 8
+	Misbehavior_MISBEHAVIOR_WITHHELD_MESSAGES        Misbehavior = 9
 )
 
 // Enum value maps for Misbehavior.
@@ -51,6 +52,7 @@ var (
 		6: "MISBEHAVIOR_CAUSAL_ORDERING",
 		7: "MISBEHAVIOR_INVALID_PAYLOAD",
 		8: "MISBEHAVIOR_BLOCKCHAIN_INCONSISTENCY",
+		9: "MISBEHAVIOR_WITHHELD_MESSAGES",
 	}
 	Misbehavior_value = map[string]int32{
 		"MISBEHAVIOR_UNSPECIFIED":              0,
@@ -62,6 +64,7 @@ var (
 		"MISBEHAVIOR_CAUSAL_ORDERING":          6,
 		"MISBEHAVIOR_INVALID_PAYLOAD":          7,
 		"MISBEHAVIOR_BLOCKCHAIN_INCONSISTENCY": 8,
+		"MISBEHAVIOR_WITHHELD_MESSAGES":        9,
 	}
 )
 
@@ -364,7 +367,10 @@ type MisbehaviorReport struct {
 	ServerTimeNs              uint64 `protobuf:"varint,1,opt,name=server_time_ns,json=serverTimeNs,proto3" json:"server_time_ns,omitempty"`
 	UnsignedMisbehaviorReport []byte `protobuf:"bytes,2,opt,name=unsigned_misbehavior_report,json=unsignedMisbehaviorReport,proto3" json:"unsigned_misbehavior_report,omitempty"`
 	// Signed by the node hosting the report
-	Signature     *associations.RecoverableEcdsaSignature `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	Signature *associations.RecoverableEcdsaSignature `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	// ID of the report on the node hosting it, ordering reports stored at the same server time.
+	// Used only for querying reports. This field is not signed.
+	Id            uint64 `protobuf:"varint,4,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -420,6 +426,13 @@ func (x *MisbehaviorReport) GetSignature() *associations.RecoverableEcdsaSignatu
 	return nil
 }
 
+func (x *MisbehaviorReport) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
 type SubmitMisbehaviorReportRequest struct {
 	state         protoimpl.MessageState     `protogen:"open.v1"`
 	Report        *UnsignedMisbehaviorReport `protobuf:"bytes,1,opt,name=report,proto3" json:"report,omitempty"`
@@ -501,8 +514,22 @@ func (*SubmitMisbehaviorReportResponse) Descriptor() ([]byte, []int) {
 }
 
 type QueryMisbehaviorReportsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AfterNs       uint64                 `protobuf:"varint,1,opt,name=after_ns,json=afterNs,proto3" json:"after_ns,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only return reports stored strictly after this server time, or at it with an ID greater
+	// than after_id. Pass the server_time_ns and id of the last report received as after_ns and
+	// after_id to fetch the next page.
+	AfterNs uint64 `protobuf:"varint,1,opt,name=after_ns,json=afterNs,proto3" json:"after_ns,omitempty"`
+	// Only return reports stored strictly before this server time. 0 means no upper bound.
+	BeforeNs uint64 `protobuf:"varint,2,opt,name=before_ns,json=beforeNs,proto3" json:"before_ns,omitempty"`
+	// Only return reports against this node. 0 means any node.
+	MisbehavingNodeId uint32 `protobuf:"varint,3,opt,name=misbehaving_node_id,json=misbehavingNodeId,proto3" json:"misbehaving_node_id,omitempty"`
+	// Only return reports of this type. MISBEHAVIOR_UNSPECIFIED means any type.
+	Type Misbehavior `protobuf:"varint,4,opt,name=type,proto3,enum=xmtp.xmtpv4.message_api.Misbehavior" json:"type,omitempty"`
+	// Maximum number of reports to return. 0 means the server default.
+	Limit uint32 `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Only return reports stored at after_ns with an ID greater than this one. 0 means none of
+	// the reports stored at after_ns.
+	AfterId       uint64 `protobuf:"varint,6,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -544,6 +571,41 @@ func (x *QueryMisbehaviorReportsRequest) GetAfterNs() uint64 {
 	return 0
 }
 
+func (x *QueryMisbehaviorReportsRequest) GetBeforeNs() uint64 {
+	if x != nil {
+		return x.BeforeNs
+	}
+	return 0
+}
+
+func (x *QueryMisbehaviorReportsRequest) GetMisbehavingNodeId() uint32 {
+	if x != nil {
+		return x.MisbehavingNodeId
+	}
+	return 0
+}
+
+func (x *QueryMisbehaviorReportsRequest) GetType() Misbehavior {
+	if x != nil {
+		return x.Type
+	}
+	return Misbehavior_MISBEHAVIOR_UNSPECIFIED
+}
+
+func (x *QueryMisbehaviorReportsRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *QueryMisbehaviorReportsRequest) GetAfterId() uint64 {
+	if x != nil {
+		return x.AfterId
+	}
+	return 0
+}
+
 type QueryMisbehaviorReportsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Reports       []*MisbehaviorReport   `protobuf:"bytes,1,rep,name=reports,proto3" json:"reports,omitempty"`
@@ -608,18 +670,24 @@ const file_xmtpv4_message_api_misbehavior_api_proto_rawDesc = "" +
 	"\bliveness\x18\x04 \x01(\v2(.xmtp.xmtpv4.message_api.LivenessFailureH\x00R\bliveness\x12@\n" +
 	"\x06safety\x18\x05 \x01(\v2&.xmtp.xmtpv4.message_api.SafetyFailureH\x00R\x06safety\x12*\n" +
 	"\x11submitted_by_node\x18\x06 \x01(\bR\x0fsubmittedByNodeB\t\n" +
-	"\afailure\"\xce\x01\n" +
+	"\afailure\"\xde\x01\n" +
 	"\x11MisbehaviorReport\x12$\n" +
 	"\x0eserver_time_ns\x18\x01 \x01(\x04R\fserverTimeNs\x12>\n" +
 	"\x1bunsigned_misbehavior_report\x18\x02 \x01(\fR\x19unsignedMisbehaviorReport\x12S\n" +
-	"\tsignature\x18\x03 \x01(\v25.xmtp.identity.associations.RecoverableEcdsaSignatureR\tsignature\"l\n" +
+	"\tsignature\x18\x03 \x01(\v25.xmtp.identity.associations.RecoverableEcdsaSignatureR\tsignature\x12\x0e\n" +
+	"\x02id\x18\x04 \x01(\x04R\x02id\"l\n" +
 	"\x1eSubmitMisbehaviorReportRequest\x12J\n" +
 	"\x06report\x18\x01 \x01(\v22.xmtp.xmtpv4.message_api.UnsignedMisbehaviorReportR\x06report\"!\n" +
-	"\x1fSubmitMisbehaviorReportResponse\";\n" +
+	"\x1fSubmitMisbehaviorReportResponse\"\xf3\x01\n" +
 	"\x1eQueryMisbehaviorReportsRequest\x12\x19\n" +
-	"\bafter_ns\x18\x01 \x01(\x04R\aafterNs\"g\n" +
+	"\bafter_ns\x18\x01 \x01(\x04R\aafterNs\x12\x1b\n" +
+	"\tbefore_ns\x18\x02 \x01(\x04R\bbeforeNs\x12.\n" +
+	"\x13misbehaving_node_id\x18\x03 \x01(\rR\x11misbehavingNodeId\x128\n" +
+	"\x04type\x18\x04 \x01(\x0e2$.xmtp.xmtpv4.message_api.MisbehaviorR\x04type\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\rR\x05limit\x12\x19\n" +
+	"\bafter_id\x18\x06 \x01(\x04R\aafterId\"g\n" +
 	"\x1fQueryMisbehaviorReportsResponse\x12D\n" +
-	"\areports\x18\x01 \x03(\v2*.xmtp.xmtpv4.message_api.MisbehaviorReportR\areports*\xb9\x02\n" +
+	"\areports\x18\x01 \x03(\v2*.xmtp.xmtpv4.message_api.MisbehaviorReportR\areports*\xdc\x02\n" +
 	"\vMisbehavior\x12\x1b\n" +
 	"\x17MISBEHAVIOR_UNSPECIFIED\x10\x00\x12!\n" +
 	"\x1dMISBEHAVIOR_UNRESPONSIVE_NODE\x10\x01\x12\x19\n" +
@@ -629,7 +697,8 @@ const file_xmtpv4_message_api_misbehavior_api_proto_rawDesc = "" +
 	"!MISBEHAVIOR_DUPLICATE_SEQUENCE_ID\x10\x05\x12\x1f\n" +
 	"\x1bMISBEHAVIOR_CAUSAL_ORDERING\x10\x06\x12\x1f\n" +
 	"\x1bMISBEHAVIOR_INVALID_PAYLOAD\x10\a\x12(\n" +
-	"$MISBEHAVIOR_BLOCKCHAIN_INCONSISTENCY\x10\b2\xb2\x02\n" +
+	"$MISBEHAVIOR_BLOCKCHAIN_INCONSISTENCY\x10\b\x12!\n" +
+	"\x1dMISBEHAVIOR_WITHHELD_MESSAGES\x10\t2\xb2\x02\n" +
 	"\x0eMisbehaviorApi\x12\x8e\x01\n" +
 	"\x17SubmitMisbehaviorReport\x127.xmtp.xmtpv4.message_api.SubmitMisbehaviorReportRequest\x1a8.xmtp.xmtpv4.message_api.SubmitMisbehaviorReportResponse\"\x00\x12\x8e\x01\n" +
 	"\x17QueryMisbehaviorReports\x127.xmtp.xmtpv4.message_api.QueryMisbehaviorReportsRequest\x1a8.xmtp.xmtpv4.message_api.QueryMisbehaviorReportsResponse\"\x00B\xe0\x01\n" +
@@ -675,16 +744,17 @@ var file_xmtpv4_message_api_misbehavior_api_proto_depIdxs = []int32{
 	2,  // 6: xmtp.xmtpv4.message_api.UnsignedMisbehaviorReport.safety:type_name -> xmtp.xmtpv4.message_api.SafetyFailure
 	13, // 7: xmtp.xmtpv4.message_api.MisbehaviorReport.signature:type_name -> xmtp.identity.associations.RecoverableEcdsaSignature
 	3,  // 8: xmtp.xmtpv4.message_api.SubmitMisbehaviorReportRequest.report:type_name -> xmtp.xmtpv4.message_api.UnsignedMisbehaviorReport
-	4,  // 9: xmtp.xmtpv4.message_api.QueryMisbehaviorReportsResponse.reports:type_name -> xmtp.xmtpv4.message_api.MisbehaviorReport
-	5,  // 10: xmtp.xmtpv4.message_api.MisbehaviorApi.SubmitMisbehaviorReport:input_type -> xmtp.xmtpv4.message_api.SubmitMisbehaviorReportRequest
-	7,  // 11: xmtp.xmtpv4.message_api.MisbehaviorApi.QueryMisbehaviorReports:input_type -> xmtp.xmtpv4.message_api.QueryMisbehaviorReportsRequest
-	6,  // 12: xmtp.xmtpv4.message_api.MisbehaviorApi.SubmitMisbehaviorReport:output_type -> xmtp.xmtpv4.message_api.SubmitMisbehaviorReportResponse
-	8,  // 13: xmtp.xmtpv4.message_api.MisbehaviorApi.QueryMisbehaviorReports:output_type -> xmtp.xmtpv4.message_api.QueryMisbehaviorReportsResponse
-	12, // [12:14] is the sub-list for method output_type
-	10, // [10:12] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	0,  // 9: xmtp.xmtpv4.message_api.QueryMisbehaviorReportsRequest.type:type_name -> xmtp.xmtpv4.message_api.Misbehavior
+	4,  // 10: xmtp.xmtpv4.message_api.QueryMisbehaviorReportsResponse.reports:type_name -> xmtp.xmtpv4.message_api.MisbehaviorReport
+	5,  // 11: xmtp.xmtpv4.message_api.MisbehaviorApi.SubmitMisbehaviorReport:input_type -> xmtp.xmtpv4.message_api.SubmitMisbehaviorReportRequest
+	7,  // 12: xmtp.xmtpv4.message_api.MisbehaviorApi.QueryMisbehaviorReports:input_type -> xmtp.xmtpv4.message_api.QueryMisbehaviorReportsRequest
+	6,  // 13: xmtp.xmtpv4.message_api.MisbehaviorApi.SubmitMisbehaviorReport:output_type -> xmtp.xmtpv4.message_api.SubmitMisbehaviorReportResponse
+	8,  // 14: xmtp.xmtpv4.message_api.MisbehaviorApi.QueryMisbehaviorReports:output_type -> xmtp.xmtpv4.message_api.QueryMisbehaviorReportsResponse
+	13, // [13:15] is the sub-list for method output_type
+	11, // [11:13] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_xmtpv4_message_api_misbehavior_api_proto_init() }
@@ -9,9 +9,11 @@
 package message_api
 
 import (
+	associations "github.com/xmtp/xmtpd/pkg/proto/identity/associations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
+	sync "sync"
 	unsafe "unsafe"
 )
 
@@ -22,47 +24,361 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type GetIdentifiersForInboxRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Hex-encoded inbox ID.
+	InboxId string `protobuf:"bytes,1,opt,name=inbox_id,json=inboxId,proto3" json:"inbox_id,omitempty"`
+	// Also return the identifiers that were revoked from the inbox.
+	IncludeRevoked bool `protobuf:"varint,2,opt,name=include_revoked,json=includeRevoked,proto3" json:"include_revoked,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetIdentifiersForInboxRequest) Reset() {
+	*x = GetIdentifiersForInboxRequest{}
+	mi := &file_xmtpv4_message_api_query_api_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIdentifiersForInboxRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIdentifiersForInboxRequest) ProtoMessage() {}
+
+func (x *GetIdentifiersForInboxRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_xmtpv4_message_api_query_api_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIdentifiersForInboxRequest.ProtoReflect.Descriptor instead.
+func (*GetIdentifiersForInboxRequest) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_message_api_query_api_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetIdentifiersForInboxRequest) GetInboxId() string {
+	if x != nil {
+		return x.InboxId
+	}
+	return ""
+}
+
+func (x *GetIdentifiersForInboxRequest) GetIncludeRevoked() bool {
+	if x != nil {
+		return x.IncludeRevoked
+	}
+	return false
+}
+
+type GetIdentifiersForInboxResponse struct {
+	state         protoimpl.MessageState                       `protogen:"open.v1"`
+	Identifiers   []*GetIdentifiersForInboxResponse_Identifier `protobuf:"bytes,1,rep,name=identifiers,proto3" json:"identifiers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetIdentifiersForInboxResponse) Reset() {
+	*x = GetIdentifiersForInboxResponse{}
+	mi := &file_xmtpv4_message_api_query_api_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIdentifiersForInboxResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIdentifiersForInboxResponse) ProtoMessage() {}
+
+func (x *GetIdentifiersForInboxResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_xmtpv4_message_api_query_api_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIdentifiersForInboxResponse.ProtoReflect.Descriptor instead.
+func (*GetIdentifiersForInboxResponse) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_message_api_query_api_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetIdentifiersForInboxResponse) GetIdentifiers() []*GetIdentifiersForInboxResponse_Identifier {
+	if x != nil {
+		return x.Identifiers
+	}
+	return nil
+}
+
+type GetInboxAssociationStateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Hex-encoded inbox ID.
+	InboxId       string `protobuf:"bytes,1,opt,name=inbox_id,json=inboxId,proto3" json:"inbox_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetInboxAssociationStateRequest) Reset() {
+	*x = GetInboxAssociationStateRequest{}
+	mi := &file_xmtpv4_message_api_query_api_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInboxAssociationStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInboxAssociationStateRequest) ProtoMessage() {}
+
+func (x *GetInboxAssociationStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_xmtpv4_message_api_query_api_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInboxAssociationStateRequest.ProtoReflect.Descriptor instead.
+func (*GetInboxAssociationStateRequest) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_message_api_query_api_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetInboxAssociationStateRequest) GetInboxId() string {
+	if x != nil {
+		return x.InboxId
+	}
+	return ""
+}
+
+type GetInboxAssociationStateResponse struct {
+	state            protoimpl.MessageState         `protogen:"open.v1"`
+	AssociationState *associations.AssociationState `protobuf:"bytes,1,opt,name=association_state,json=associationState,proto3" json:"association_state,omitempty"`
+	// Sequence ID of the latest identity update reflected in the association state.
+	SequenceId    uint64 `protobuf:"varint,2,opt,name=sequence_id,json=sequenceId,proto3" json:"sequence_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetInboxAssociationStateResponse) Reset() {
+	*x = GetInboxAssociationStateResponse{}
+	mi := &file_xmtpv4_message_api_query_api_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInboxAssociationStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInboxAssociationStateResponse) ProtoMessage() {}
+
+func (x *GetInboxAssociationStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_xmtpv4_message_api_query_api_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInboxAssociationStateResponse.ProtoReflect.Descriptor instead.
+func (*GetInboxAssociationStateResponse) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_message_api_query_api_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetInboxAssociationStateResponse) GetAssociationState() *associations.AssociationState {
+	if x != nil {
+		return x.AssociationState
+	}
+	return nil
+}
+
+func (x *GetInboxAssociationStateResponse) GetSequenceId() uint64 {
+	if x != nil {
+		return x.SequenceId
+	}
+	return 0
+}
+
+type GetIdentifiersForInboxResponse_Identifier struct {
+	state          protoimpl.MessageState      `protogen:"open.v1"`
+	Identifier     string                      `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	IdentifierKind associations.IdentifierKind `protobuf:"varint,2,opt,name=identifier_kind,json=identifierKind,proto3,enum=xmtp.identity.associations.IdentifierKind" json:"identifier_kind,omitempty"`
+	// Sequence ID of the identity update that associated the identifier with the inbox.
+	AssociationSequenceId uint64 `protobuf:"varint,3,opt,name=association_sequence_id,json=associationSequenceId,proto3" json:"association_sequence_id,omitempty"`
+	// Sequence ID of the identity update that revoked the identifier, 0 if it is current.
+	RevocationSequenceId uint64 `protobuf:"varint,4,opt,name=revocation_sequence_id,json=revocationSequenceId,proto3" json:"revocation_sequence_id,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *GetIdentifiersForInboxResponse_Identifier) Reset() {
+	*x = GetIdentifiersForInboxResponse_Identifier{}
+	mi := &file_xmtpv4_message_api_query_api_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIdentifiersForInboxResponse_Identifier) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIdentifiersForInboxResponse_Identifier) ProtoMessage() {}
+
+func (x *GetIdentifiersForInboxResponse_Identifier) ProtoReflect() protoreflect.Message {
+	mi := &file_xmtpv4_message_api_query_api_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIdentifiersForInboxResponse_Identifier.ProtoReflect.Descriptor instead.
+func (*GetIdentifiersForInboxResponse_Identifier) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_message_api_query_api_proto_rawDescGZIP(), []int{1, 0}
+}
+
+func (x *GetIdentifiersForInboxResponse_Identifier) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *GetIdentifiersForInboxResponse_Identifier) GetIdentifierKind() associations.IdentifierKind {
+	if x != nil {
+		return x.IdentifierKind
+	}
+	return associations.IdentifierKind(0)
+}
+
+func (x *GetIdentifiersForInboxResponse_Identifier) GetAssociationSequenceId() uint64 {
+	if x != nil {
+		return x.AssociationSequenceId
+	}
+	return 0
+}
+
+func (x *GetIdentifiersForInboxResponse_Identifier) GetRevocationSequenceId() uint64 {
+	if x != nil {
+		return x.RevocationSequenceId
+	}
+	return 0
+}
+
 var File_xmtpv4_message_api_query_api_proto protoreflect.FileDescriptor
 
 const file_xmtpv4_message_api_query_api_proto_rawDesc = "" +
 	"\n" +
-	"\"xmtpv4/message_api/query_api.proto\x12\x17xmtp.xmtpv4.message_api\x1a$xmtpv4/message_api/message_api.proto2\xcd\x04\n" +
+	"\"xmtpv4/message_api/query_api.proto\x12\x17xmtp.xmtpv4.message_api\x1a'identity/associations/association.proto\x1a$xmtpv4/message_api/message_api.proto\"c\n" +
+	"\x1dGetIdentifiersForInboxRequest\x12\x19\n" +
+	"\binbox_id\x18\x01 \x01(\tR\ainboxId\x12'\n" +
+	"\x0finclude_revoked\x18\x02 \x01(\bR\x0eincludeRevoked\"\xf8\x02\n" +
+	"\x1eGetIdentifiersForInboxResponse\x12d\n" +
+	"\videntifiers\x18\x01 \x03(\v2B.xmtp.xmtpv4.message_api.GetIdentifiersForInboxResponse.IdentifierR\videntifiers\x1a\xef\x01\n" +
+	"\n" +
+	"Identifier\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\tR\n" +
+	"identifier\x12S\n" +
+	"\x0fidentifier_kind\x18\x02 \x01(\x0e2*.xmtp.identity.associations.IdentifierKindR\x0eidentifierKind\x126\n" +
+	"\x17association_sequence_id\x18\x03 \x01(\x04R\x15associationSequenceId\x124\n" +
+	"\x16revocation_sequence_id\x18\x04 \x01(\x04R\x14revocationSequenceId\"<\n" +
+	"\x1fGetInboxAssociationStateRequest\x12\x19\n" +
+	"\binbox_id\x18\x01 \x01(\tR\ainboxId\"\x9e\x01\n" +
+	" GetInboxAssociationStateResponse\x12Y\n" +
+	"\x11association_state\x18\x01 \x01(\v2,.xmtp.identity.associations.AssociationStateR\x10associationState\x12\x1f\n" +
+	"\vsequence_id\x18\x02 \x01(\x04R\n" +
+	"sequenceId2\xef\x06\n" +
 	"\bQueryApi\x12s\n" +
 	"\x0eQueryEnvelopes\x12..xmtp.xmtpv4.message_api.QueryEnvelopesRequest\x1a/.xmtp.xmtpv4.message_api.QueryEnvelopesResponse\"\x00\x12x\n" +
 	"\x0fSubscribeTopics\x12/.xmtp.xmtpv4.message_api.SubscribeTopicsRequest\x1a0.xmtp.xmtpv4.message_api.SubscribeTopicsResponse\"\x000\x01\x12h\n" +
 	"\tSubscribe\x12).xmtp.xmtpv4.message_api.SubscribeRequest\x1a*.xmtp.xmtpv4.message_api.SubscribeResponse\"\x00(\x010\x01\x12j\n" +
 	"\vGetInboxIds\x12+.xmtp.xmtpv4.message_api.GetInboxIdsRequest\x1a,.xmtp.xmtpv4.message_api.GetInboxIdsResponse\"\x00\x12|\n" +
-	"\x11GetNewestEnvelope\x121.xmtp.xmtpv4.message_api.GetNewestEnvelopeRequest\x1a2.xmtp.xmtpv4.message_api.GetNewestEnvelopeResponse\"\x00B\xda\x01\n" +
+	"\x11GetNewestEnvelope\x121.xmtp.xmtpv4.message_api.GetNewestEnvelopeRequest\x1a2.xmtp.xmtpv4.message_api.GetNewestEnvelopeResponse\"\x00\x12\x8b\x01\n" +
+	"\x16GetIdentifiersForInbox\x126.xmtp.xmtpv4.message_api.GetIdentifiersForInboxRequest\x1a7.xmtp.xmtpv4.message_api.GetIdentifiersForInboxResponse\"\x00\x12\x91\x01\n" +
+	"\x18GetInboxAssociationState\x128.xmtp.xmtpv4.message_api.GetInboxAssociationStateRequest\x1a9.xmtp.xmtpv4.message_api.GetInboxAssociationStateResponse\"\x00B\xda\x01\n" +
 	"\x1bcom.xmtp.xmtpv4.message_apiB\rQueryApiProtoP\x01Z2github.com/xmtp/xmtpd/pkg/proto/xmtpv4/message_api\xa2\x02\x03XXM\xaa\x02\x16Xmtp.Xmtpv4.MessageApi\xca\x02\x16Xmtp\\Xmtpv4\\MessageApi\xe2\x02\"Xmtp\\Xmtpv4\\MessageApi\\GPBMetadata\xea\x02\x18Xmtp::Xmtpv4::MessageApib\x06proto3"
 
+var (
+	file_xmtpv4_message_api_query_api_proto_rawDescOnce sync.Once
+	file_xmtpv4_message_api_query_api_proto_rawDescData []byte
+)
+
+func file_xmtpv4_message_api_query_api_proto_rawDescGZIP() []byte {
+	file_xmtpv4_message_api_query_api_proto_rawDescOnce.Do(func() {
+		file_xmtpv4_message_api_query_api_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_xmtpv4_message_api_query_api_proto_rawDesc), len(file_xmtpv4_message_api_query_api_proto_rawDesc)))
+	})
+	return file_xmtpv4_message_api_query_api_proto_rawDescData
+}
+
+var file_xmtpv4_message_api_query_api_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_xmtpv4_message_api_query_api_proto_goTypes = []any{
-	(*QueryEnvelopesRequest)(nil),     // 0: xmtp.xmtpv4.message_api.QueryEnvelopesRequest
-	(*SubscribeTopicsRequest)(nil),    // 1: xmtp.xmtpv4.message_api.SubscribeTopicsRequest
-	(*SubscribeRequest)(nil),          // 2: xmtp.xmtpv4.message_api.SubscribeRequest
-	(*GetInboxIdsRequest)(nil),        // 3: xmtp.xmtpv4.message_api.GetInboxIdsRequest
-	(*GetNewestEnvelopeRequest)(nil),  // 4: xmtp.xmtpv4.message_api.GetNewestEnvelopeRequest
-	(*QueryEnvelopesResponse)(nil),    // 5: xmtp.xmtpv4.message_api.QueryEnvelopesResponse
-	(*SubscribeTopicsResponse)(nil),   // 6: xmtp.xmtpv4.message_api.SubscribeTopicsResponse
-	(*SubscribeResponse)(nil),         // 7: xmtp.xmtpv4.message_api.SubscribeResponse
-	(*GetInboxIdsResponse)(nil),       // 8: xmtp.xmtpv4.message_api.GetInboxIdsResponse
-	(*GetNewestEnvelopeResponse)(nil), // 9: xmtp.xmtpv4.message_api.GetNewestEnvelopeResponse
+	(*GetIdentifiersForInboxRequest)(nil),             // 0: xmtp.xmtpv4.message_api.GetIdentifiersForInboxRequest
+	(*GetIdentifiersForInboxResponse)(nil),            // 1: xmtp.xmtpv4.message_api.GetIdentifiersForInboxResponse
+	(*GetInboxAssociationStateRequest)(nil),           // 2: xmtp.xmtpv4.message_api.GetInboxAssociationStateRequest
+	(*GetInboxAssociationStateResponse)(nil),          // 3: xmtp.xmtpv4.message_api.GetInboxAssociationStateResponse
+	(*GetIdentifiersForInboxResponse_Identifier)(nil), // 4: xmtp.xmtpv4.message_api.GetIdentifiersForInboxResponse.Identifier
+	(*associations.AssociationState)(nil),             // 5: xmtp.identity.associations.AssociationState
+	(associations.IdentifierKind)(0),                  // 6: xmtp.identity.associations.IdentifierKind
+	(*QueryEnvelopesRequest)(nil),                     // 7: xmtp.xmtpv4.message_api.QueryEnvelopesRequest
+	(*SubscribeTopicsRequest)(nil),                    // 8: xmtp.xmtpv4.message_api.SubscribeTopicsRequest
+	(*SubscribeRequest)(nil),                          // 9: xmtp.xmtpv4.message_api.SubscribeRequest
+	(*GetInboxIdsRequest)(nil),                        // 10: xmtp.xmtpv4.message_api.GetInboxIdsRequest
+	(*GetNewestEnvelopeRequest)(nil),                  // 11: xmtp.xmtpv4.message_api.GetNewestEnvelopeRequest
+	(*QueryEnvelopesResponse)(nil),                    // 12: xmtp.xmtpv4.message_api.QueryEnvelopesResponse
+	(*SubscribeTopicsResponse)(nil),                   // 13: xmtp.xmtpv4.message_api.SubscribeTopicsResponse
+	(*SubscribeResponse)(nil),                         // 14: xmtp.xmtpv4.message_api.SubscribeResponse
+	(*GetInboxIdsResponse)(nil),                       // 15: xmtp.xmtpv4.message_api.GetInboxIdsResponse
+	(*GetNewestEnvelopeResponse)(nil),                 // 16: xmtp.xmtpv4.message_api.GetNewestEnvelopeResponse
 }
 var file_xmtpv4_message_api_query_api_proto_depIdxs = []int32{
-	0, // 0: xmtp.xmtpv4.message_api.QueryApi.QueryEnvelopes:input_type -> xmtp.xmtpv4.message_api.QueryEnvelopesRequest
-	1, // 1: xmtp.xmtpv4.message_api.QueryApi.SubscribeTopics:input_type -> xmtp.xmtpv4.message_api.SubscribeTopicsRequest
-	2, // 2: xmtp.xmtpv4.message_api.QueryApi.Subscribe:input_type -> xmtp.xmtpv4.message_api.SubscribeRequest
-	3, // 3: xmtp.xmtpv4.message_api.QueryApi.GetInboxIds:input_type -> xmtp.xmtpv4.message_api.GetInboxIdsRequest
-	4, // 4: xmtp.xmtpv4.message_api.QueryApi.GetNewestEnvelope:input_type -> xmtp.xmtpv4.message_api.GetNewestEnvelopeRequest
-	5, // 5: xmtp.xmtpv4.message_api.QueryApi.QueryEnvelopes:output_type -> xmtp.xmtpv4.message_api.QueryEnvelopesResponse
-	6, // 6: xmtp.xmtpv4.message_api.QueryApi.SubscribeTopics:output_type -> xmtp.xmtpv4.message_api.SubscribeTopicsResponse
-	7, // 7: xmtp.xmtpv4.message_api.QueryApi.Subscribe:output_type -> xmtp.xmtpv4.message_api.SubscribeResponse
-	8, // 8: xmtp.xmtpv4.message_api.QueryApi.GetInboxIds:output_type -> xmtp.xmtpv4.message_api.GetInboxIdsResponse
-	9, // 9: xmtp.xmtpv4.message_api.QueryApi.GetNewestEnvelope:output_type -> xmtp.xmtpv4.message_api.GetNewestEnvelopeResponse
-	5, // [5:10] is the sub-list for method output_type
-	0, // [0:5] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	4,  // 0: xmtp.xmtpv4.message_api.GetIdentifiersForInboxResponse.identifiers:type_name -> xmtp.xmtpv4.message_api.GetIdentifiersForInboxResponse.Identifier
+	5,  // 1: xmtp.xmtpv4.message_api.GetInboxAssociationStateResponse.association_state:type_name -> xmtp.identity.associations.AssociationState
+	6,  // 2: xmtp.xmtpv4.message_api.GetIdentifiersForInboxResponse.Identifier.identifier_kind:type_name -> xmtp.identity.associations.IdentifierKind
+	7,  // 3: xmtp.xmtpv4.message_api.QueryApi.QueryEnvelopes:input_type -> xmtp.xmtpv4.message_api.QueryEnvelopesRequest
+	8,  // 4: xmtp.xmtpv4.message_api.QueryApi.SubscribeTopics:input_type -> xmtp.xmtpv4.message_api.SubscribeTopicsRequest
+	9,  // 5: xmtp.xmtpv4.message_api.QueryApi.Subscribe:input_type -> xmtp.xmtpv4.message_api.SubscribeRequest
+	10, // 6: xmtp.xmtpv4.message_api.QueryApi.GetInboxIds:input_type -> xmtp.xmtpv4.message_api.GetInboxIdsRequest
+	11, // 7: xmtp.xmtpv4.message_api.QueryApi.GetNewestEnvelope:input_type -> xmtp.xmtpv4.message_api.GetNewestEnvelopeRequest
+	0,  // 8: xmtp.xmtpv4.message_api.QueryApi.GetIdentifiersForInbox:input_type -> xmtp.xmtpv4.message_api.GetIdentifiersForInboxRequest
+	2,  // 9: xmtp.xmtpv4.message_api.QueryApi.GetInboxAssociationState:input_type -> xmtp.xmtpv4.message_api.GetInboxAssociationStateRequest
+	12, // 10: xmtp.xmtpv4.message_api.QueryApi.QueryEnvelopes:output_type -> xmtp.xmtpv4.message_api.QueryEnvelopesResponse
+	13, // 11: xmtp.xmtpv4.message_api.QueryApi.SubscribeTopics:output_type -> xmtp.xmtpv4.message_api.SubscribeTopicsResponse
+	14, // 12: xmtp.xmtpv4.message_api.QueryApi.Subscribe:output_type -> xmtp.xmtpv4.message_api.SubscribeResponse
+	15, // 13: xmtp.xmtpv4.message_api.QueryApi.GetInboxIds:output_type -> xmtp.xmtpv4.message_api.GetInboxIdsResponse
+	16, // 14: xmtp.xmtpv4.message_api.QueryApi.GetNewestEnvelope:output_type -> xmtp.xmtpv4.message_api.GetNewestEnvelopeResponse
+	1,  // 15: xmtp.xmtpv4.message_api.QueryApi.GetIdentifiersForInbox:output_type -> xmtp.xmtpv4.message_api.GetIdentifiersForInboxResponse
+	3,  // 16: xmtp.xmtpv4.message_api.QueryApi.GetInboxAssociationState:output_type -> xmtp.xmtpv4.message_api.GetInboxAssociationStateResponse
+	10, // [10:17] is the sub-list for method output_type
+	3,  // [3:10] is the sub-list for method input_type
+	3,  // [3:3] is the sub-list for extension type_name
+	3,  // [3:3] is the sub-list for extension extendee
+	0,  // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_xmtpv4_message_api_query_api_proto_init() }
@@ -77,12 +393,13 @@ func file_xmtpv4_message_api_query_api_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_xmtpv4_message_api_query_api_proto_rawDesc), len(file_xmtpv4_message_api_query_api_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   0,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_xmtpv4_message_api_query_api_proto_goTypes,
 		DependencyIndexes: file_xmtpv4_message_api_query_api_proto_depIdxs,
+		MessageInfos:      file_xmtpv4_message_api_query_api_proto_msgTypes,
 	}.Build()
 	File_xmtpv4_message_api_query_api_proto = out.File
 	file_xmtpv4_message_api_query_api_proto_goTypes = nil
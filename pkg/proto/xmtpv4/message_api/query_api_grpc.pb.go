@@ -21,11 +21,13 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	QueryApi_QueryEnvelopes_FullMethodName    = "/xmtp.xmtpv4.message_api.QueryApi/QueryEnvelopes"
-	QueryApi_SubscribeTopics_FullMethodName   = "/xmtp.xmtpv4.message_api.QueryApi/SubscribeTopics"
-	QueryApi_Subscribe_FullMethodName         = "/xmtp.xmtpv4.message_api.QueryApi/Subscribe"
-	QueryApi_GetInboxIds_FullMethodName       = "/xmtp.xmtpv4.message_api.QueryApi/GetInboxIds"
-	QueryApi_GetNewestEnvelope_FullMethodName = "/xmtp.xmtpv4.message_api.QueryApi/GetNewestEnvelope"
+	QueryApi_QueryEnvelopes_FullMethodName           = "/xmtp.xmtpv4.message_api.QueryApi/QueryEnvelopes"
+	QueryApi_SubscribeTopics_FullMethodName          = "/xmtp.xmtpv4.message_api.QueryApi/SubscribeTopics"
+	QueryApi_Subscribe_FullMethodName                = "/xmtp.xmtpv4.message_api.QueryApi/Subscribe"
+	QueryApi_GetInboxIds_FullMethodName              = "/xmtp.xmtpv4.message_api.QueryApi/GetInboxIds"
+	QueryApi_GetNewestEnvelope_FullMethodName        = "/xmtp.xmtpv4.message_api.QueryApi/GetNewestEnvelope"
+	QueryApi_GetIdentifiersForInbox_FullMethodName   = "/xmtp.xmtpv4.message_api.QueryApi/GetIdentifiersForInbox"
+	QueryApi_GetInboxAssociationState_FullMethodName = "/xmtp.xmtpv4.message_api.QueryApi/GetInboxAssociationState"
 )
 
 // QueryApiClient is the client API for QueryApi service.
@@ -44,6 +46,11 @@ type QueryApiClient interface {
 	Subscribe(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SubscribeRequest, SubscribeResponse], error)
 	GetInboxIds(ctx context.Context, in *GetInboxIdsRequest, opts ...grpc.CallOption) (*GetInboxIdsResponse, error)
 	GetNewestEnvelope(ctx context.Context, in *GetNewestEnvelopeRequest, opts ...grpc.CallOption) (*GetNewestEnvelopeResponse, error)
+	// Returns the identifiers associated with an inbox, along with the identity updates that
+	// associated or revoked them.
+	GetIdentifiersForInbox(ctx context.Context, in *GetIdentifiersForInboxRequest, opts ...grpc.CallOption) (*GetIdentifiersForInboxResponse, error)
+	// Returns the latest association state of an inbox stored by the node.
+	GetInboxAssociationState(ctx context.Context, in *GetInboxAssociationStateRequest, opts ...grpc.CallOption) (*GetInboxAssociationStateResponse, error)
 }
 
 type queryApiClient struct {
@@ -116,6 +123,26 @@ func (c *queryApiClient) GetNewestEnvelope(ctx context.Context, in *GetNewestEnv
 	return out, nil
 }
 
+func (c *queryApiClient) GetIdentifiersForInbox(ctx context.Context, in *GetIdentifiersForInboxRequest, opts ...grpc.CallOption) (*GetIdentifiersForInboxResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetIdentifiersForInboxResponse)
+	err := c.cc.Invoke(ctx, QueryApi_GetIdentifiersForInbox_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryApiClient) GetInboxAssociationState(ctx context.Context, in *GetInboxAssociationStateRequest, opts ...grpc.CallOption) (*GetInboxAssociationStateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetInboxAssociationStateResponse)
+	err := c.cc.Invoke(ctx, QueryApi_GetInboxAssociationState_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // QueryApiServer is the server API for QueryApi service.
 // All implementations should embed UnimplementedQueryApiServer
 // for forward compatibility.
@@ -132,6 +159,11 @@ type QueryApiServer interface {
 	Subscribe(grpc.BidiStreamingServer[SubscribeRequest, SubscribeResponse]) error
 	GetInboxIds(context.Context, *GetInboxIdsRequest) (*GetInboxIdsResponse, error)
 	GetNewestEnvelope(context.Context, *GetNewestEnvelopeRequest) (*GetNewestEnvelopeResponse, error)
+	// Returns the identifiers associated with an inbox, along with the identity updates that
+	// associated or revoked them.
+	GetIdentifiersForInbox(context.Context, *GetIdentifiersForInboxRequest) (*GetIdentifiersForInboxResponse, error)
+	// Returns the latest association state of an inbox stored by the node.
+	GetInboxAssociationState(context.Context, *GetInboxAssociationStateRequest) (*GetInboxAssociationStateResponse, error)
 }
 
 // UnimplementedQueryApiServer should be embedded to have
@@ -156,6 +188,12 @@ func (UnimplementedQueryApiServer) GetInboxIds(context.Context, *GetInboxIdsRequ
 func (UnimplementedQueryApiServer) GetNewestEnvelope(context.Context, *GetNewestEnvelopeRequest) (*GetNewestEnvelopeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetNewestEnvelope not implemented")
 }
+func (UnimplementedQueryApiServer) GetIdentifiersForInbox(context.Context, *GetIdentifiersForInboxRequest) (*GetIdentifiersForInboxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIdentifiersForInbox not implemented")
+}
+func (UnimplementedQueryApiServer) GetInboxAssociationState(context.Context, *GetInboxAssociationStateRequest) (*GetInboxAssociationStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInboxAssociationState not implemented")
+}
 func (UnimplementedQueryApiServer) testEmbeddedByValue() {}
 
 // UnsafeQueryApiServer may be embedded to opt out of forward compatibility for this service.
@@ -248,6 +286,42 @@ func _QueryApi_GetNewestEnvelope_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _QueryApi_GetIdentifiersForInbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIdentifiersForInboxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryApiServer).GetIdentifiersForInbox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QueryApi_GetIdentifiersForInbox_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryApiServer).GetIdentifiersForInbox(ctx, req.(*GetIdentifiersForInboxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryApi_GetInboxAssociationState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInboxAssociationStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryApiServer).GetInboxAssociationState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QueryApi_GetInboxAssociationState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryApiServer).GetInboxAssociationState(ctx, req.(*GetInboxAssociationStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // QueryApi_ServiceDesc is the grpc.ServiceDesc for QueryApi service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -267,6 +341,14 @@ var QueryApi_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetNewestEnvelope",
 			Handler:    _QueryApi_GetNewestEnvelope_Handler,
 		},
+		{
+			MethodName: "GetIdentifiersForInbox",
+			Handler:    _QueryApi_GetIdentifiersForInbox_Handler,
+		},
+		{
+			MethodName: "GetInboxAssociationState",
+			Handler:    _QueryApi_GetInboxAssociationState_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
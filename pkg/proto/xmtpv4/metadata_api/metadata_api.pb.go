@@ -74,6 +74,281 @@ func (PayerInfoGranularity) EnumDescriptor() ([]byte, []int) {
 	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP(), []int{0}
 }
 
+// A dimension to break payer spend down by, in addition to time
+type PayerSpendDimension int32
+
+const (
+	PayerSpendDimension_PAYER_SPEND_DIMENSION_UNSPECIFIED   PayerSpendDimension = 0
+	PayerSpendDimension_PAYER_SPEND_DIMENSION_ORIGINATOR    PayerSpendDimension = 1
+	PayerSpendDimension_PAYER_SPEND_DIMENSION_TOPIC_KIND    PayerSpendDimension = 2
+	PayerSpendDimension_PAYER_SPEND_DIMENSION_FEE_COMPONENT PayerSpendDimension = 3
+)
+
+// Enum value maps for PayerSpendDimension.
+var (
+	PayerSpendDimension_name = map[int32]string{
+		0: "PAYER_SPEND_DIMENSION_UNSPECIFIED",
+		1: "PAYER_SPEND_DIMENSION_ORIGINATOR",
+		2: "PAYER_SPEND_DIMENSION_TOPIC_KIND",
+		3: "PAYER_SPEND_DIMENSION_FEE_COMPONENT",
+	}
+	PayerSpendDimension_value = map[string]int32{
+		"PAYER_SPEND_DIMENSION_UNSPECIFIED":   0,
+		"PAYER_SPEND_DIMENSION_ORIGINATOR":    1,
+		"PAYER_SPEND_DIMENSION_TOPIC_KIND":    2,
+		"PAYER_SPEND_DIMENSION_FEE_COMPONENT": 3,
+	}
+)
+
+func (x PayerSpendDimension) Enum() *PayerSpendDimension {
+	p := new(PayerSpendDimension)
+	*p = x
+	return p
+}
+
+func (x PayerSpendDimension) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PayerSpendDimension) Descriptor() protoreflect.EnumDescriptor {
+	return file_xmtpv4_metadata_api_metadata_api_proto_enumTypes[1].Descriptor()
+}
+
+func (PayerSpendDimension) Type() protoreflect.EnumType {
+	return &file_xmtpv4_metadata_api_metadata_api_proto_enumTypes[1]
+}
+
+func (x PayerSpendDimension) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PayerSpendDimension.Descriptor instead.
+func (PayerSpendDimension) EnumDescriptor() ([]byte, []int) {
+	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP(), []int{1}
+}
+
+// The part of the fees an amount was charged for
+type FeeComponent int32
+
+const (
+	FeeComponent_FEE_COMPONENT_UNSPECIFIED FeeComponent = 0
+	// The message fee plus the storage fee
+	FeeComponent_FEE_COMPONENT_BASE       FeeComponent = 1
+	FeeComponent_FEE_COMPONENT_CONGESTION FeeComponent = 2
+)
+
+// Enum value maps for FeeComponent.
+var (
+	FeeComponent_name = map[int32]string{
+		0: "FEE_COMPONENT_UNSPECIFIED",
+		1: "FEE_COMPONENT_BASE",
+		2: "FEE_COMPONENT_CONGESTION",
+	}
+	FeeComponent_value = map[string]int32{
+		"FEE_COMPONENT_UNSPECIFIED": 0,
+		"FEE_COMPONENT_BASE":        1,
+		"FEE_COMPONENT_CONGESTION":  2,
+	}
+)
+
+func (x FeeComponent) Enum() *FeeComponent {
+	p := new(FeeComponent)
+	*p = x
+	return p
+}
+
+func (x FeeComponent) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FeeComponent) Descriptor() protoreflect.EnumDescriptor {
+	return file_xmtpv4_metadata_api_metadata_api_proto_enumTypes[2].Descriptor()
+}
+
+func (FeeComponent) Type() protoreflect.EnumType {
+	return &file_xmtpv4_metadata_api_metadata_api_proto_enumTypes[2]
+}
+
+func (x FeeComponent) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FeeComponent.Descriptor instead.
+func (FeeComponent) EnumDescriptor() ([]byte, []int) {
+	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP(), []int{2}
+}
+
+// The file format of a payer spend export
+type PayerSpendExportFormat int32
+
+const (
+	// Defaults to CSV
+	PayerSpendExportFormat_PAYER_SPEND_EXPORT_FORMAT_UNSPECIFIED PayerSpendExportFormat = 0
+	PayerSpendExportFormat_PAYER_SPEND_EXPORT_FORMAT_CSV         PayerSpendExportFormat = 1
+	// Newline-delimited JSON, one PayerSpendRow per line
+	PayerSpendExportFormat_PAYER_SPEND_EXPORT_FORMAT_NDJSON PayerSpendExportFormat = 2
+)
+
+// Enum value maps for PayerSpendExportFormat.
+var (
+	PayerSpendExportFormat_name = map[int32]string{
+		0: "PAYER_SPEND_EXPORT_FORMAT_UNSPECIFIED",
+		1: "PAYER_SPEND_EXPORT_FORMAT_CSV",
+		2: "PAYER_SPEND_EXPORT_FORMAT_NDJSON",
+	}
+	PayerSpendExportFormat_value = map[string]int32{
+		"PAYER_SPEND_EXPORT_FORMAT_UNSPECIFIED": 0,
+		"PAYER_SPEND_EXPORT_FORMAT_CSV":         1,
+		"PAYER_SPEND_EXPORT_FORMAT_NDJSON":      2,
+	}
+)
+
+func (x PayerSpendExportFormat) Enum() *PayerSpendExportFormat {
+	p := new(PayerSpendExportFormat)
+	*p = x
+	return p
+}
+
+func (x PayerSpendExportFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PayerSpendExportFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_xmtpv4_metadata_api_metadata_api_proto_enumTypes[3].Descriptor()
+}
+
+func (PayerSpendExportFormat) Type() protoreflect.EnumType {
+	return &file_xmtpv4_metadata_api_metadata_api_proto_enumTypes[3]
+}
+
+func (x PayerSpendExportFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PayerSpendExportFormat.Descriptor instead.
+func (PayerSpendExportFormat) EnumDescriptor() ([]byte, []int) {
+	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP(), []int{3}
+}
+
+// The kind of a payer ledger event
+type PayerLedgerEventType int32
+
+const (
+	PayerLedgerEventType_PAYER_LEDGER_EVENT_TYPE_UNSPECIFIED         PayerLedgerEventType = 0
+	PayerLedgerEventType_PAYER_LEDGER_EVENT_TYPE_DEPOSIT             PayerLedgerEventType = 1
+	PayerLedgerEventType_PAYER_LEDGER_EVENT_TYPE_WITHDRAWAL          PayerLedgerEventType = 2
+	PayerLedgerEventType_PAYER_LEDGER_EVENT_TYPE_SETTLEMENT          PayerLedgerEventType = 3
+	PayerLedgerEventType_PAYER_LEDGER_EVENT_TYPE_CANCELED_WITHDRAWAL PayerLedgerEventType = 4
+	// Compensates an event whose block was orphaned by a settlement chain reorg
+	PayerLedgerEventType_PAYER_LEDGER_EVENT_TYPE_REORG_REVERSAL PayerLedgerEventType = 5
+)
+
+// Enum value maps for PayerLedgerEventType.
+var (
+	PayerLedgerEventType_name = map[int32]string{
+		0: "PAYER_LEDGER_EVENT_TYPE_UNSPECIFIED",
+		1: "PAYER_LEDGER_EVENT_TYPE_DEPOSIT",
+		2: "PAYER_LEDGER_EVENT_TYPE_WITHDRAWAL",
+		3: "PAYER_LEDGER_EVENT_TYPE_SETTLEMENT",
+		4: "PAYER_LEDGER_EVENT_TYPE_CANCELED_WITHDRAWAL",
+		5: "PAYER_LEDGER_EVENT_TYPE_REORG_REVERSAL",
+	}
+	PayerLedgerEventType_value = map[string]int32{
+		"PAYER_LEDGER_EVENT_TYPE_UNSPECIFIED":         0,
+		"PAYER_LEDGER_EVENT_TYPE_DEPOSIT":             1,
+		"PAYER_LEDGER_EVENT_TYPE_WITHDRAWAL":          2,
+		"PAYER_LEDGER_EVENT_TYPE_SETTLEMENT":          3,
+		"PAYER_LEDGER_EVENT_TYPE_CANCELED_WITHDRAWAL": 4,
+		"PAYER_LEDGER_EVENT_TYPE_REORG_REVERSAL":      5,
+	}
+)
+
+func (x PayerLedgerEventType) Enum() *PayerLedgerEventType {
+	p := new(PayerLedgerEventType)
+	*p = x
+	return p
+}
+
+func (x PayerLedgerEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PayerLedgerEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_xmtpv4_metadata_api_metadata_api_proto_enumTypes[4].Descriptor()
+}
+
+func (PayerLedgerEventType) Type() protoreflect.EnumType {
+	return &file_xmtpv4_metadata_api_metadata_api_proto_enumTypes[4]
+}
+
+func (x PayerLedgerEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PayerLedgerEventType.Descriptor instead.
+func (PayerLedgerEventType) EnumDescriptor() ([]byte, []int) {
+	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP(), []int{4}
+}
+
+// How close a payer is to running out of funds
+type PayerBalanceLevel int32
+
+const (
+	PayerBalanceLevel_PAYER_BALANCE_LEVEL_UNSPECIFIED PayerBalanceLevel = 0
+	PayerBalanceLevel_PAYER_BALANCE_LEVEL_OK          PayerBalanceLevel = 1
+	// Forecast to run out of funds within the node's warning threshold
+	PayerBalanceLevel_PAYER_BALANCE_LEVEL_LOW PayerBalanceLevel = 2
+	// Forecast to run out of funds within the node's critical threshold
+	PayerBalanceLevel_PAYER_BALANCE_LEVEL_CRITICAL PayerBalanceLevel = 3
+	// No funds are available, and publishes are rejected
+	PayerBalanceLevel_PAYER_BALANCE_LEVEL_DEPLETED PayerBalanceLevel = 4
+)
+
+// Enum value maps for PayerBalanceLevel.
+var (
+	PayerBalanceLevel_name = map[int32]string{
+		0: "PAYER_BALANCE_LEVEL_UNSPECIFIED",
+		1: "PAYER_BALANCE_LEVEL_OK",
+		2: "PAYER_BALANCE_LEVEL_LOW",
+		3: "PAYER_BALANCE_LEVEL_CRITICAL",
+		4: "PAYER_BALANCE_LEVEL_DEPLETED",
+	}
+	PayerBalanceLevel_value = map[string]int32{
+		"PAYER_BALANCE_LEVEL_UNSPECIFIED": 0,
+		"PAYER_BALANCE_LEVEL_OK":          1,
+		"PAYER_BALANCE_LEVEL_LOW":         2,
+		"PAYER_BALANCE_LEVEL_CRITICAL":    3,
+		"PAYER_BALANCE_LEVEL_DEPLETED":    4,
+	}
+)
+
+func (x PayerBalanceLevel) Enum() *PayerBalanceLevel {
+	p := new(PayerBalanceLevel)
+	*p = x
+	return p
+}
+
+func (x PayerBalanceLevel) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PayerBalanceLevel) Descriptor() protoreflect.EnumDescriptor {
+	return file_xmtpv4_metadata_api_metadata_api_proto_enumTypes[5].Descriptor()
+}
+
+func (PayerBalanceLevel) Type() protoreflect.EnumType {
+	return &file_xmtpv4_metadata_api_metadata_api_proto_enumTypes[5]
+}
+
+func (x PayerBalanceLevel) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PayerBalanceLevel.Descriptor instead.
+func (PayerBalanceLevel) EnumDescriptor() ([]byte, []int) {
+	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP(), []int{5}
+}
+
 type GetSyncCursorRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -333,29 +608,34 @@ func (x *GetPayerInfoResponse) GetPayerInfo() map[string]*GetPayerInfoResponse_P
 	return nil
 }
 
-type GetPayerInfoResponse_PeriodSummary struct {
-	state                  protoimpl.MessageState `protogen:"open.v1"`
-	AmountSpentPicodollars uint64                 `protobuf:"varint,1,opt,name=amount_spent_picodollars,json=amountSpentPicodollars,proto3" json:"amount_spent_picodollars,omitempty"`
-	NumMessages            uint64                 `protobuf:"varint,2,opt,name=num_messages,json=numMessages,proto3" json:"num_messages,omitempty"`
-	PeriodStartUnixSeconds uint64                 `protobuf:"varint,3,opt,name=period_start_unix_seconds,json=periodStartUnixSeconds,proto3" json:"period_start_unix_seconds,omitempty"`
-	unknownFields          protoimpl.UnknownFields
-	sizeCache              protoimpl.SizeCache
+// Get the fees a node charges to publish an envelope at the current rates
+type GetFeeQuoteRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The kind of the envelope's target topic, as the first byte of the topic
+	TopicKind uint32 `protobuf:"varint,1,opt,name=topic_kind,json=topicKind,proto3" json:"topic_kind,omitempty"`
+	// The size of the payer envelope in bytes
+	PayloadSize   uint64 `protobuf:"varint,2,opt,name=payload_size,json=payloadSize,proto3" json:"payload_size,omitempty"`
+	RetentionDays uint32 `protobuf:"varint,3,opt,name=retention_days,json=retentionDays,proto3" json:"retention_days,omitempty"`
+	// The originator node the envelope would be published to
+	TargetOriginator uint32 `protobuf:"varint,4,opt,name=target_originator,json=targetOriginator,proto3" json:"target_originator,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *GetPayerInfoResponse_PeriodSummary) Reset() {
-	*x = GetPayerInfoResponse_PeriodSummary{}
+func (x *GetFeeQuoteRequest) Reset() {
+	*x = GetFeeQuoteRequest{}
 	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPayerInfoResponse_PeriodSummary) String() string {
+func (x *GetFeeQuoteRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPayerInfoResponse_PeriodSummary) ProtoMessage() {}
+func (*GetFeeQuoteRequest) ProtoMessage() {}
 
-func (x *GetPayerInfoResponse_PeriodSummary) ProtoReflect() protoreflect.Message {
+func (x *GetFeeQuoteRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -367,53 +647,69 @@ func (x *GetPayerInfoResponse_PeriodSummary) ProtoReflect() protoreflect.Message
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPayerInfoResponse_PeriodSummary.ProtoReflect.Descriptor instead.
-func (*GetPayerInfoResponse_PeriodSummary) Descriptor() ([]byte, []int) {
-	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP(), []int{5, 0}
+// Deprecated: Use GetFeeQuoteRequest.ProtoReflect.Descriptor instead.
+func (*GetFeeQuoteRequest) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *GetPayerInfoResponse_PeriodSummary) GetAmountSpentPicodollars() uint64 {
+func (x *GetFeeQuoteRequest) GetTopicKind() uint32 {
 	if x != nil {
-		return x.AmountSpentPicodollars
+		return x.TopicKind
 	}
 	return 0
 }
 
-func (x *GetPayerInfoResponse_PeriodSummary) GetNumMessages() uint64 {
+func (x *GetFeeQuoteRequest) GetPayloadSize() uint64 {
 	if x != nil {
-		return x.NumMessages
+		return x.PayloadSize
 	}
 	return 0
 }
 
-func (x *GetPayerInfoResponse_PeriodSummary) GetPeriodStartUnixSeconds() uint64 {
+func (x *GetFeeQuoteRequest) GetRetentionDays() uint32 {
 	if x != nil {
-		return x.PeriodStartUnixSeconds
+		return x.RetentionDays
 	}
 	return 0
 }
 
-type GetPayerInfoResponse_PayerInfo struct {
-	state           protoimpl.MessageState                `protogen:"open.v1"`
-	PeriodSummaries []*GetPayerInfoResponse_PeriodSummary `protobuf:"bytes,1,rep,name=period_summaries,json=periodSummaries,proto3" json:"period_summaries,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+func (x *GetFeeQuoteRequest) GetTargetOriginator() uint32 {
+	if x != nil {
+		return x.TargetOriginator
+	}
+	return 0
 }
 
-func (x *GetPayerInfoResponse_PayerInfo) Reset() {
-	*x = GetPayerInfoResponse_PayerInfo{}
+// The rates from the rate registry, and the window in which they apply
+type FeeRates struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	MessageFeePicodollars uint64                 `protobuf:"varint,1,opt,name=message_fee_picodollars,json=messageFeePicodollars,proto3" json:"message_fee_picodollars,omitempty"`
+	// Per byte-day of storage
+	StorageFeePicodollars uint64 `protobuf:"varint,2,opt,name=storage_fee_picodollars,json=storageFeePicodollars,proto3" json:"storage_fee_picodollars,omitempty"`
+	// Per unit of congestion
+	CongestionFeePicodollars uint64 `protobuf:"varint,3,opt,name=congestion_fee_picodollars,json=congestionFeePicodollars,proto3" json:"congestion_fee_picodollars,omitempty"`
+	TargetRatePerMinute      uint64 `protobuf:"varint,4,opt,name=target_rate_per_minute,json=targetRatePerMinute,proto3" json:"target_rate_per_minute,omitempty"`
+	ValidFromUnixSeconds     uint64 `protobuf:"varint,5,opt,name=valid_from_unix_seconds,json=validFromUnixSeconds,proto3" json:"valid_from_unix_seconds,omitempty"`
+	// Zero if no later rates are scheduled
+	ValidToUnixSeconds uint64 `protobuf:"varint,6,opt,name=valid_to_unix_seconds,json=validToUnixSeconds,proto3" json:"valid_to_unix_seconds,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *FeeRates) Reset() {
+	*x = FeeRates{}
 	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPayerInfoResponse_PayerInfo) String() string {
+func (x *FeeRates) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPayerInfoResponse_PayerInfo) ProtoMessage() {}
+func (*FeeRates) ProtoMessage() {}
 
-func (x *GetPayerInfoResponse_PayerInfo) ProtoReflect() protoreflect.Message {
+func (x *FeeRates) ProtoReflect() protoreflect.Message {
 	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -425,26 +721,970 @@ func (x *GetPayerInfoResponse_PayerInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPayerInfoResponse_PayerInfo.ProtoReflect.Descriptor instead.
-func (*GetPayerInfoResponse_PayerInfo) Descriptor() ([]byte, []int) {
-	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP(), []int{5, 1}
+// Deprecated: Use FeeRates.ProtoReflect.Descriptor instead.
+func (*FeeRates) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *GetPayerInfoResponse_PayerInfo) GetPeriodSummaries() []*GetPayerInfoResponse_PeriodSummary {
+func (x *FeeRates) GetMessageFeePicodollars() uint64 {
 	if x != nil {
-		return x.PeriodSummaries
+		return x.MessageFeePicodollars
+	}
+	return 0
+}
+
+func (x *FeeRates) GetStorageFeePicodollars() uint64 {
+	if x != nil {
+		return x.StorageFeePicodollars
+	}
+	return 0
+}
+
+func (x *FeeRates) GetCongestionFeePicodollars() uint64 {
+	if x != nil {
+		return x.CongestionFeePicodollars
+	}
+	return 0
+}
+
+func (x *FeeRates) GetTargetRatePerMinute() uint64 {
+	if x != nil {
+		return x.TargetRatePerMinute
+	}
+	return 0
+}
+
+func (x *FeeRates) GetValidFromUnixSeconds() uint64 {
+	if x != nil {
+		return x.ValidFromUnixSeconds
+	}
+	return 0
+}
+
+func (x *FeeRates) GetValidToUnixSeconds() uint64 {
+	if x != nil {
+		return x.ValidToUnixSeconds
+	}
+	return 0
+}
+
+// Response to GetFeeQuoteRequest
+type GetFeeQuoteResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The message fee plus the storage fee
+	BaseFeePicodollars    uint64 `protobuf:"varint,1,opt,name=base_fee_picodollars,json=baseFeePicodollars,proto3" json:"base_fee_picodollars,omitempty"`
+	StorageFeePicodollars uint64 `protobuf:"varint,2,opt,name=storage_fee_picodollars,json=storageFeePicodollars,proto3" json:"storage_fee_picodollars,omitempty"`
+	// The target originator's current congestion fee
+	CongestionFeePicodollars uint64 `protobuf:"varint,3,opt,name=congestion_fee_picodollars,json=congestionFeePicodollars,proto3" json:"congestion_fee_picodollars,omitempty"`
+	// The target originator's current congestion, from 0 to 100
+	CongestionUnits uint32    `protobuf:"varint,4,opt,name=congestion_units,json=congestionUnits,proto3" json:"congestion_units,omitempty"`
+	Rates           *FeeRates `protobuf:"bytes,5,opt,name=rates,proto3" json:"rates,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetFeeQuoteResponse) Reset() {
+	*x = GetFeeQuoteResponse{}
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFeeQuoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFeeQuoteResponse) ProtoMessage() {}
+
+func (x *GetFeeQuoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFeeQuoteResponse.ProtoReflect.Descriptor instead.
+func (*GetFeeQuoteResponse) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetFeeQuoteResponse) GetBaseFeePicodollars() uint64 {
+	if x != nil {
+		return x.BaseFeePicodollars
+	}
+	return 0
+}
+
+func (x *GetFeeQuoteResponse) GetStorageFeePicodollars() uint64 {
+	if x != nil {
+		return x.StorageFeePicodollars
+	}
+	return 0
+}
+
+func (x *GetFeeQuoteResponse) GetCongestionFeePicodollars() uint64 {
+	if x != nil {
+		return x.CongestionFeePicodollars
+	}
+	return 0
+}
+
+func (x *GetFeeQuoteResponse) GetCongestionUnits() uint32 {
+	if x != nil {
+		return x.CongestionUnits
+	}
+	return 0
+}
+
+func (x *GetFeeQuoteResponse) GetRates() *FeeRates {
+	if x != nil {
+		return x.Rates
 	}
 	return nil
 }
 
-var File_xmtpv4_metadata_api_metadata_api_proto protoreflect.FileDescriptor
+// Get a payer's spend over a time range, broken down by the requested dimensions
+type GetPayerSpendRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	PayerAddress string                 `protobuf:"bytes,1,opt,name=payer_address,json=payerAddress,proto3" json:"payer_address,omitempty"`
+	// Inclusive. Zero for the start of the payer's history
+	StartUnixSeconds uint64 `protobuf:"varint,2,opt,name=start_unix_seconds,json=startUnixSeconds,proto3" json:"start_unix_seconds,omitempty"`
+	// Exclusive. Zero for now
+	EndUnixSeconds uint64                `protobuf:"varint,3,opt,name=end_unix_seconds,json=endUnixSeconds,proto3" json:"end_unix_seconds,omitempty"`
+	Granularity    PayerInfoGranularity  `protobuf:"varint,4,opt,name=granularity,proto3,enum=xmtp.xmtpv4.metadata_api.PayerInfoGranularity" json:"granularity,omitempty"`
+	GroupBy        []PayerSpendDimension `protobuf:"varint,5,rep,packed,name=group_by,json=groupBy,proto3,enum=xmtp.xmtpv4.metadata_api.PayerSpendDimension" json:"group_by,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
 
-const file_xmtpv4_metadata_api_metadata_api_proto_rawDesc = "" +
-	"\n" +
-	"&xmtpv4/metadata_api/metadata_api.proto\x12\x18xmtp.xmtpv4.metadata_api\x1a xmtpv4/envelopes/envelopes.proto\"\x16\n" +
-	"\x14GetSyncCursorRequest\"W\n" +
-	"\x15GetSyncCursorResponse\x12>\n" +
-	"\vlatest_sync\x18\x01 \x01(\v2\x1d.xmtp.xmtpv4.envelopes.CursorR\n" +
+func (x *GetPayerSpendRequest) Reset() {
+	*x = GetPayerSpendRequest{}
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPayerSpendRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPayerSpendRequest) ProtoMessage() {}
+
+func (x *GetPayerSpendRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPayerSpendRequest.ProtoReflect.Descriptor instead.
+func (*GetPayerSpendRequest) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetPayerSpendRequest) GetPayerAddress() string {
+	if x != nil {
+		return x.PayerAddress
+	}
+	return ""
+}
+
+func (x *GetPayerSpendRequest) GetStartUnixSeconds() uint64 {
+	if x != nil {
+		return x.StartUnixSeconds
+	}
+	return 0
+}
+
+func (x *GetPayerSpendRequest) GetEndUnixSeconds() uint64 {
+	if x != nil {
+		return x.EndUnixSeconds
+	}
+	return 0
+}
+
+func (x *GetPayerSpendRequest) GetGranularity() PayerInfoGranularity {
+	if x != nil {
+		return x.Granularity
+	}
+	return PayerInfoGranularity_PAYER_INFO_GRANULARITY_UNSPECIFIED
+}
+
+func (x *GetPayerSpendRequest) GetGroupBy() []PayerSpendDimension {
+	if x != nil {
+		return x.GroupBy
+	}
+	return nil
+}
+
+// A payer's spend in one period, for one combination of the grouped dimensions
+type PayerSpendRow struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	PeriodStartUnixSeconds uint64                 `protobuf:"varint,1,opt,name=period_start_unix_seconds,json=periodStartUnixSeconds,proto3" json:"period_start_unix_seconds,omitempty"`
+	// Set when grouping by originator
+	OriginatorId uint32 `protobuf:"varint,2,opt,name=originator_id,json=originatorId,proto3" json:"originator_id,omitempty"`
+	// Set when grouping by topic kind, e.g. group_messages_v1
+	TopicKind string `protobuf:"bytes,3,opt,name=topic_kind,json=topicKind,proto3" json:"topic_kind,omitempty"`
+	// Set when grouping by fee component. Otherwise the amount includes all fees
+	FeeComponent           FeeComponent `protobuf:"varint,4,opt,name=fee_component,json=feeComponent,proto3,enum=xmtp.xmtpv4.metadata_api.FeeComponent" json:"fee_component,omitempty"`
+	AmountSpentPicodollars uint64       `protobuf:"varint,5,opt,name=amount_spent_picodollars,json=amountSpentPicodollars,proto3" json:"amount_spent_picodollars,omitempty"`
+	// When grouping by fee component, messages are counted once for each component
+	NumMessages   uint64 `protobuf:"varint,6,opt,name=num_messages,json=numMessages,proto3" json:"num_messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PayerSpendRow) Reset() {
+	*x = PayerSpendRow{}
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PayerSpendRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayerSpendRow) ProtoMessage() {}
+
+func (x *PayerSpendRow) ProtoReflect() protoreflect.Message {
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayerSpendRow.ProtoReflect.Descriptor instead.
+func (*PayerSpendRow) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PayerSpendRow) GetPeriodStartUnixSeconds() uint64 {
+	if x != nil {
+		return x.PeriodStartUnixSeconds
+	}
+	return 0
+}
+
+func (x *PayerSpendRow) GetOriginatorId() uint32 {
+	if x != nil {
+		return x.OriginatorId
+	}
+	return 0
+}
+
+func (x *PayerSpendRow) GetTopicKind() string {
+	if x != nil {
+		return x.TopicKind
+	}
+	return ""
+}
+
+func (x *PayerSpendRow) GetFeeComponent() FeeComponent {
+	if x != nil {
+		return x.FeeComponent
+	}
+	return FeeComponent_FEE_COMPONENT_UNSPECIFIED
+}
+
+func (x *PayerSpendRow) GetAmountSpentPicodollars() uint64 {
+	if x != nil {
+		return x.AmountSpentPicodollars
+	}
+	return 0
+}
+
+func (x *PayerSpendRow) GetNumMessages() uint64 {
+	if x != nil {
+		return x.NumMessages
+	}
+	return 0
+}
+
+// Response to GetPayerSpendRequest
+type GetPayerSpendResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rows          []*PayerSpendRow       `protobuf:"bytes,1,rep,name=rows,proto3" json:"rows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPayerSpendResponse) Reset() {
+	*x = GetPayerSpendResponse{}
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPayerSpendResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPayerSpendResponse) ProtoMessage() {}
+
+func (x *GetPayerSpendResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPayerSpendResponse.ProtoReflect.Descriptor instead.
+func (*GetPayerSpendResponse) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetPayerSpendResponse) GetRows() []*PayerSpendRow {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+// Export a payer's spend over a time range, without a limit on its length
+type ExportPayerSpendRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         *GetPayerSpendRequest  `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Format        PayerSpendExportFormat `protobuf:"varint,2,opt,name=format,proto3,enum=xmtp.xmtpv4.metadata_api.PayerSpendExportFormat" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportPayerSpendRequest) Reset() {
+	*x = ExportPayerSpendRequest{}
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportPayerSpendRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportPayerSpendRequest) ProtoMessage() {}
+
+func (x *ExportPayerSpendRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportPayerSpendRequest.ProtoReflect.Descriptor instead.
+func (*ExportPayerSpendRequest) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ExportPayerSpendRequest) GetQuery() *GetPayerSpendRequest {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (x *ExportPayerSpendRequest) GetFormat() PayerSpendExportFormat {
+	if x != nil {
+		return x.Format
+	}
+	return PayerSpendExportFormat_PAYER_SPEND_EXPORT_FORMAT_UNSPECIFIED
+}
+
+// A chunk of a payer spend export. The export is the concatenation of the chunks
+type ExportPayerSpendResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportPayerSpendResponse) Reset() {
+	*x = ExportPayerSpendResponse{}
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportPayerSpendResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportPayerSpendResponse) ProtoMessage() {}
+
+func (x *ExportPayerSpendResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportPayerSpendResponse.ProtoReflect.Descriptor instead.
+func (*ExportPayerSpendResponse) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ExportPayerSpendResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// List the events that make up a payer's balance, oldest first
+type GetPayerLedgerEventsRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	PayerAddress string                 `protobuf:"bytes,1,opt,name=payer_address,json=payerAddress,proto3" json:"payer_address,omitempty"`
+	// Return events after this cursor. Zero for the start of the payer's history
+	SinceCursor uint64 `protobuf:"varint,2,opt,name=since_cursor,json=sinceCursor,proto3" json:"since_cursor,omitempty"`
+	// Zero for the default of 100. At most 1000
+	Limit         uint32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPayerLedgerEventsRequest) Reset() {
+	*x = GetPayerLedgerEventsRequest{}
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPayerLedgerEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPayerLedgerEventsRequest) ProtoMessage() {}
+
+func (x *GetPayerLedgerEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPayerLedgerEventsRequest.ProtoReflect.Descriptor instead.
+func (*GetPayerLedgerEventsRequest) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetPayerLedgerEventsRequest) GetPayerAddress() string {
+	if x != nil {
+		return x.PayerAddress
+	}
+	return ""
+}
+
+func (x *GetPayerLedgerEventsRequest) GetSinceCursor() uint64 {
+	if x != nil {
+		return x.SinceCursor
+	}
+	return 0
+}
+
+func (x *GetPayerLedgerEventsRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// A change to a payer's balance
+type PayerLedgerEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Pass as since_cursor to continue listing after this event
+	Cursor    uint64               `protobuf:"varint,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	EventId   []byte               `protobuf:"bytes,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	EventType PayerLedgerEventType `protobuf:"varint,3,opt,name=event_type,json=eventType,proto3,enum=xmtp.xmtpv4.metadata_api.PayerLedgerEventType" json:"event_type,omitempty"`
+	// Negative for events that reduce the balance
+	AmountPicodollars int64 `protobuf:"varint,4,opt,name=amount_picodollars,json=amountPicodollars,proto3" json:"amount_picodollars,omitempty"`
+	// The settlement chain block the event was emitted in. Zero if it was recorded before block
+	// numbers were tracked
+	BlockNumber uint64 `protobuf:"varint,5,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	// For reorg reversals, the ID of the event that was reversed
+	ReversedEventId      []byte `protobuf:"bytes,6,opt,name=reversed_event_id,json=reversedEventId,proto3" json:"reversed_event_id,omitempty"`
+	CreatedAtUnixSeconds uint64 `protobuf:"varint,7,opt,name=created_at_unix_seconds,json=createdAtUnixSeconds,proto3" json:"created_at_unix_seconds,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *PayerLedgerEvent) Reset() {
+	*x = PayerLedgerEvent{}
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PayerLedgerEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayerLedgerEvent) ProtoMessage() {}
+
+func (x *PayerLedgerEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayerLedgerEvent.ProtoReflect.Descriptor instead.
+func (*PayerLedgerEvent) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PayerLedgerEvent) GetCursor() uint64 {
+	if x != nil {
+		return x.Cursor
+	}
+	return 0
+}
+
+func (x *PayerLedgerEvent) GetEventId() []byte {
+	if x != nil {
+		return x.EventId
+	}
+	return nil
+}
+
+func (x *PayerLedgerEvent) GetEventType() PayerLedgerEventType {
+	if x != nil {
+		return x.EventType
+	}
+	return PayerLedgerEventType_PAYER_LEDGER_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *PayerLedgerEvent) GetAmountPicodollars() int64 {
+	if x != nil {
+		return x.AmountPicodollars
+	}
+	return 0
+}
+
+func (x *PayerLedgerEvent) GetBlockNumber() uint64 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *PayerLedgerEvent) GetReversedEventId() []byte {
+	if x != nil {
+		return x.ReversedEventId
+	}
+	return nil
+}
+
+func (x *PayerLedgerEvent) GetCreatedAtUnixSeconds() uint64 {
+	if x != nil {
+		return x.CreatedAtUnixSeconds
+	}
+	return 0
+}
+
+// Response to GetPayerLedgerEventsRequest
+type GetPayerLedgerEventsResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Events []*PayerLedgerEvent    `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	// The cursor to request the next page with. Equal to since_cursor if there are no more events
+	NextCursor uint64 `protobuf:"varint,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	// The payer's current settled balance
+	BalancePicodollars int64 `protobuf:"varint,3,opt,name=balance_picodollars,json=balancePicodollars,proto3" json:"balance_picodollars,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *GetPayerLedgerEventsResponse) Reset() {
+	*x = GetPayerLedgerEventsResponse{}
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPayerLedgerEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPayerLedgerEventsResponse) ProtoMessage() {}
+
+func (x *GetPayerLedgerEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPayerLedgerEventsResponse.ProtoReflect.Descriptor instead.
+func (*GetPayerLedgerEventsResponse) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetPayerLedgerEventsResponse) GetEvents() []*PayerLedgerEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *GetPayerLedgerEventsResponse) GetNextCursor() uint64 {
+	if x != nil {
+		return x.NextCursor
+	}
+	return 0
+}
+
+func (x *GetPayerLedgerEventsResponse) GetBalancePicodollars() int64 {
+	if x != nil {
+		return x.BalancePicodollars
+	}
+	return 0
+}
+
+// Forecast when payers will run out of funds at their recent spend rate
+type GetPayerBalanceForecastRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	PayerAddresses []string               `protobuf:"bytes,1,rep,name=payer_addresses,json=payerAddresses,proto3" json:"payer_addresses,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetPayerBalanceForecastRequest) Reset() {
+	*x = GetPayerBalanceForecastRequest{}
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPayerBalanceForecastRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPayerBalanceForecastRequest) ProtoMessage() {}
+
+func (x *GetPayerBalanceForecastRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPayerBalanceForecastRequest.ProtoReflect.Descriptor instead.
+func (*GetPayerBalanceForecastRequest) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetPayerBalanceForecastRequest) GetPayerAddresses() []string {
+	if x != nil {
+		return x.PayerAddresses
+	}
+	return nil
+}
+
+// A payer's available balance and how long it will last
+type PayerBalanceForecast struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The settled balance from the payer ledger
+	BalancePicodollars int64 `protobuf:"varint,1,opt,name=balance_picodollars,json=balancePicodollars,proto3" json:"balance_picodollars,omitempty"`
+	// Spend that has not been settled yet
+	UnsettledUsagePicodollars int64 `protobuf:"varint,2,opt,name=unsettled_usage_picodollars,json=unsettledUsagePicodollars,proto3" json:"unsettled_usage_picodollars,omitempty"`
+	// The balance minus the unsettled usage, which publishes are checked against
+	AvailablePicodollars int64 `protobuf:"varint,3,opt,name=available_picodollars,json=availablePicodollars,proto3" json:"available_picodollars,omitempty"`
+	// The average spend over the node's spend window
+	SpendPerHourPicodollars int64 `protobuf:"varint,4,opt,name=spend_per_hour_picodollars,json=spendPerHourPicodollars,proto3" json:"spend_per_hour_picodollars,omitempty"`
+	// How long the available balance lasts at the spend rate. Zero if the payer is not spending or
+	// has no funds available
+	SecondsToDepletion    uint64            `protobuf:"varint,5,opt,name=seconds_to_depletion,json=secondsToDepletion,proto3" json:"seconds_to_depletion,omitempty"`
+	Level                 PayerBalanceLevel `protobuf:"varint,6,opt,name=level,proto3,enum=xmtp.xmtpv4.metadata_api.PayerBalanceLevel" json:"level,omitempty"`
+	ComputedAtUnixSeconds uint64            `protobuf:"varint,7,opt,name=computed_at_unix_seconds,json=computedAtUnixSeconds,proto3" json:"computed_at_unix_seconds,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *PayerBalanceForecast) Reset() {
+	*x = PayerBalanceForecast{}
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PayerBalanceForecast) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayerBalanceForecast) ProtoMessage() {}
+
+func (x *PayerBalanceForecast) ProtoReflect() protoreflect.Message {
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayerBalanceForecast.ProtoReflect.Descriptor instead.
+func (*PayerBalanceForecast) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PayerBalanceForecast) GetBalancePicodollars() int64 {
+	if x != nil {
+		return x.BalancePicodollars
+	}
+	return 0
+}
+
+func (x *PayerBalanceForecast) GetUnsettledUsagePicodollars() int64 {
+	if x != nil {
+		return x.UnsettledUsagePicodollars
+	}
+	return 0
+}
+
+func (x *PayerBalanceForecast) GetAvailablePicodollars() int64 {
+	if x != nil {
+		return x.AvailablePicodollars
+	}
+	return 0
+}
+
+func (x *PayerBalanceForecast) GetSpendPerHourPicodollars() int64 {
+	if x != nil {
+		return x.SpendPerHourPicodollars
+	}
+	return 0
+}
+
+func (x *PayerBalanceForecast) GetSecondsToDepletion() uint64 {
+	if x != nil {
+		return x.SecondsToDepletion
+	}
+	return 0
+}
+
+func (x *PayerBalanceForecast) GetLevel() PayerBalanceLevel {
+	if x != nil {
+		return x.Level
+	}
+	return PayerBalanceLevel_PAYER_BALANCE_LEVEL_UNSPECIFIED
+}
+
+func (x *PayerBalanceForecast) GetComputedAtUnixSeconds() uint64 {
+	if x != nil {
+		return x.ComputedAtUnixSeconds
+	}
+	return 0
+}
+
+// Response to GetPayerBalanceForecastRequest
+type GetPayerBalanceForecastResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Map of payer address
+	Forecasts     map[string]*PayerBalanceForecast `protobuf:"bytes,1,rep,name=forecasts,proto3" json:"forecasts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPayerBalanceForecastResponse) Reset() {
+	*x = GetPayerBalanceForecastResponse{}
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPayerBalanceForecastResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPayerBalanceForecastResponse) ProtoMessage() {}
+
+func (x *GetPayerBalanceForecastResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPayerBalanceForecastResponse.ProtoReflect.Descriptor instead.
+func (*GetPayerBalanceForecastResponse) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetPayerBalanceForecastResponse) GetForecasts() map[string]*PayerBalanceForecast {
+	if x != nil {
+		return x.Forecasts
+	}
+	return nil
+}
+
+type GetPayerInfoResponse_PeriodSummary struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	AmountSpentPicodollars uint64                 `protobuf:"varint,1,opt,name=amount_spent_picodollars,json=amountSpentPicodollars,proto3" json:"amount_spent_picodollars,omitempty"`
+	NumMessages            uint64                 `protobuf:"varint,2,opt,name=num_messages,json=numMessages,proto3" json:"num_messages,omitempty"`
+	PeriodStartUnixSeconds uint64                 `protobuf:"varint,3,opt,name=period_start_unix_seconds,json=periodStartUnixSeconds,proto3" json:"period_start_unix_seconds,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *GetPayerInfoResponse_PeriodSummary) Reset() {
+	*x = GetPayerInfoResponse_PeriodSummary{}
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPayerInfoResponse_PeriodSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPayerInfoResponse_PeriodSummary) ProtoMessage() {}
+
+func (x *GetPayerInfoResponse_PeriodSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPayerInfoResponse_PeriodSummary.ProtoReflect.Descriptor instead.
+func (*GetPayerInfoResponse_PeriodSummary) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP(), []int{5, 1}
+}
+
+func (x *GetPayerInfoResponse_PeriodSummary) GetAmountSpentPicodollars() uint64 {
+	if x != nil {
+		return x.AmountSpentPicodollars
+	}
+	return 0
+}
+
+func (x *GetPayerInfoResponse_PeriodSummary) GetNumMessages() uint64 {
+	if x != nil {
+		return x.NumMessages
+	}
+	return 0
+}
+
+func (x *GetPayerInfoResponse_PeriodSummary) GetPeriodStartUnixSeconds() uint64 {
+	if x != nil {
+		return x.PeriodStartUnixSeconds
+	}
+	return 0
+}
+
+type GetPayerInfoResponse_PayerInfo struct {
+	state           protoimpl.MessageState                `protogen:"open.v1"`
+	PeriodSummaries []*GetPayerInfoResponse_PeriodSummary `protobuf:"bytes,1,rep,name=period_summaries,json=periodSummaries,proto3" json:"period_summaries,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetPayerInfoResponse_PayerInfo) Reset() {
+	*x = GetPayerInfoResponse_PayerInfo{}
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPayerInfoResponse_PayerInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPayerInfoResponse_PayerInfo) ProtoMessage() {}
+
+func (x *GetPayerInfoResponse_PayerInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_xmtpv4_metadata_api_metadata_api_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPayerInfoResponse_PayerInfo.ProtoReflect.Descriptor instead.
+func (*GetPayerInfoResponse_PayerInfo) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP(), []int{5, 2}
+}
+
+func (x *GetPayerInfoResponse_PayerInfo) GetPeriodSummaries() []*GetPayerInfoResponse_PeriodSummary {
+	if x != nil {
+		return x.PeriodSummaries
+	}
+	return nil
+}
+
+var File_xmtpv4_metadata_api_metadata_api_proto protoreflect.FileDescriptor
+
+const file_xmtpv4_metadata_api_metadata_api_proto_rawDesc = "" +
+	"\n" +
+	"&xmtpv4/metadata_api/metadata_api.proto\x12\x18xmtp.xmtpv4.metadata_api\x1a xmtpv4/envelopes/envelopes.proto\"\x16\n" +
+	"\x14GetSyncCursorRequest\"W\n" +
+	"\x15GetSyncCursorResponse\x12>\n" +
+	"\vlatest_sync\x18\x01 \x01(\v2\x1d.xmtp.xmtpv4.envelopes.CursorR\n" +
 	"latestSync\"\x13\n" +
 	"\x11GetVersionRequest\".\n" +
 	"\x12GetVersionResponse\x12\x18\n" +
@@ -454,26 +1694,130 @@ const file_xmtpv4_metadata_api_metadata_api_proto_rawDesc = "" +
 	"\vgranularity\x18\x02 \x01(\x0e2..xmtp.xmtpv4.metadata_api.PayerInfoGranularityR\vgranularity\"\x8c\x04\n" +
 	"\x14GetPayerInfoResponse\x12\\\n" +
 	"\n" +
-	"payer_info\x18\x01 \x03(\v2=.xmtp.xmtpv4.metadata_api.GetPayerInfoResponse.PayerInfoEntryR\tpayerInfo\x1a\xa7\x01\n" +
+	"payer_info\x18\x01 \x03(\v2=.xmtp.xmtpv4.metadata_api.GetPayerInfoResponse.PayerInfoEntryR\tpayerInfo\x1av\n" +
+	"\x0ePayerInfoEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12N\n" +
+	"\x05value\x18\x02 \x01(\v28.xmtp.xmtpv4.metadata_api.GetPayerInfoResponse.PayerInfoR\x05value:\x028\x01\x1a\xa7\x01\n" +
 	"\rPeriodSummary\x128\n" +
 	"\x18amount_spent_picodollars\x18\x01 \x01(\x04R\x16amountSpentPicodollars\x12!\n" +
 	"\fnum_messages\x18\x02 \x01(\x04R\vnumMessages\x129\n" +
 	"\x19period_start_unix_seconds\x18\x03 \x01(\x04R\x16periodStartUnixSeconds\x1at\n" +
 	"\tPayerInfo\x12g\n" +
-	"\x10period_summaries\x18\x01 \x03(\v2<.xmtp.xmtpv4.metadata_api.GetPayerInfoResponse.PeriodSummaryR\x0fperiodSummaries\x1av\n" +
-	"\x0ePayerInfoEntry\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12N\n" +
-	"\x05value\x18\x02 \x01(\v28.xmtp.xmtpv4.metadata_api.GetPayerInfoResponse.PayerInfoR\x05value:\x028\x01*\x7f\n" +
+	"\x10period_summaries\x18\x01 \x03(\v2<.xmtp.xmtpv4.metadata_api.GetPayerInfoResponse.PeriodSummaryR\x0fperiodSummaries\"\xaa\x01\n" +
+	"\x12GetFeeQuoteRequest\x12\x1d\n" +
+	"\n" +
+	"topic_kind\x18\x01 \x01(\rR\ttopicKind\x12!\n" +
+	"\fpayload_size\x18\x02 \x01(\x04R\vpayloadSize\x12%\n" +
+	"\x0eretention_days\x18\x03 \x01(\rR\rretentionDays\x12+\n" +
+	"\x11target_originator\x18\x04 \x01(\rR\x10targetOriginator\"\xd7\x02\n" +
+	"\bFeeRates\x126\n" +
+	"\x17message_fee_picodollars\x18\x01 \x01(\x04R\x15messageFeePicodollars\x126\n" +
+	"\x17storage_fee_picodollars\x18\x02 \x01(\x04R\x15storageFeePicodollars\x12<\n" +
+	"\x1acongestion_fee_picodollars\x18\x03 \x01(\x04R\x18congestionFeePicodollars\x123\n" +
+	"\x16target_rate_per_minute\x18\x04 \x01(\x04R\x13targetRatePerMinute\x125\n" +
+	"\x17valid_from_unix_seconds\x18\x05 \x01(\x04R\x14validFromUnixSeconds\x121\n" +
+	"\x15valid_to_unix_seconds\x18\x06 \x01(\x04R\x12validToUnixSeconds\"\xa2\x02\n" +
+	"\x13GetFeeQuoteResponse\x120\n" +
+	"\x14base_fee_picodollars\x18\x01 \x01(\x04R\x12baseFeePicodollars\x126\n" +
+	"\x17storage_fee_picodollars\x18\x02 \x01(\x04R\x15storageFeePicodollars\x12<\n" +
+	"\x1acongestion_fee_picodollars\x18\x03 \x01(\x04R\x18congestionFeePicodollars\x12)\n" +
+	"\x10congestion_units\x18\x04 \x01(\rR\x0fcongestionUnits\x128\n" +
+	"\x05rates\x18\x05 \x01(\v2\".xmtp.xmtpv4.metadata_api.FeeRatesR\x05rates\"\xaf\x02\n" +
+	"\x14GetPayerSpendRequest\x12#\n" +
+	"\rpayer_address\x18\x01 \x01(\tR\fpayerAddress\x12,\n" +
+	"\x12start_unix_seconds\x18\x02 \x01(\x04R\x10startUnixSeconds\x12(\n" +
+	"\x10end_unix_seconds\x18\x03 \x01(\x04R\x0eendUnixSeconds\x12P\n" +
+	"\vgranularity\x18\x04 \x01(\x0e2..xmtp.xmtpv4.metadata_api.PayerInfoGranularityR\vgranularity\x12H\n" +
+	"\bgroup_by\x18\x05 \x03(\x0e2-.xmtp.xmtpv4.metadata_api.PayerSpendDimensionR\agroupBy\"\xb8\x02\n" +
+	"\rPayerSpendRow\x129\n" +
+	"\x19period_start_unix_seconds\x18\x01 \x01(\x04R\x16periodStartUnixSeconds\x12#\n" +
+	"\roriginator_id\x18\x02 \x01(\rR\foriginatorId\x12\x1d\n" +
+	"\n" +
+	"topic_kind\x18\x03 \x01(\tR\ttopicKind\x12K\n" +
+	"\rfee_component\x18\x04 \x01(\x0e2&.xmtp.xmtpv4.metadata_api.FeeComponentR\ffeeComponent\x128\n" +
+	"\x18amount_spent_picodollars\x18\x05 \x01(\x04R\x16amountSpentPicodollars\x12!\n" +
+	"\fnum_messages\x18\x06 \x01(\x04R\vnumMessages\"T\n" +
+	"\x15GetPayerSpendResponse\x12;\n" +
+	"\x04rows\x18\x01 \x03(\v2'.xmtp.xmtpv4.metadata_api.PayerSpendRowR\x04rows\"\xa9\x01\n" +
+	"\x17ExportPayerSpendRequest\x12D\n" +
+	"\x05query\x18\x01 \x01(\v2..xmtp.xmtpv4.metadata_api.GetPayerSpendRequestR\x05query\x12H\n" +
+	"\x06format\x18\x02 \x01(\x0e20.xmtp.xmtpv4.metadata_api.PayerSpendExportFormatR\x06format\".\n" +
+	"\x18ExportPayerSpendResponse\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"{\n" +
+	"\x1bGetPayerLedgerEventsRequest\x12#\n" +
+	"\rpayer_address\x18\x01 \x01(\tR\fpayerAddress\x12!\n" +
+	"\fsince_cursor\x18\x02 \x01(\x04R\vsinceCursor\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\rR\x05limit\"\xc9\x02\n" +
+	"\x10PayerLedgerEvent\x12\x16\n" +
+	"\x06cursor\x18\x01 \x01(\x04R\x06cursor\x12\x19\n" +
+	"\bevent_id\x18\x02 \x01(\fR\aeventId\x12M\n" +
+	"\n" +
+	"event_type\x18\x03 \x01(\x0e2..xmtp.xmtpv4.metadata_api.PayerLedgerEventTypeR\teventType\x12-\n" +
+	"\x12amount_picodollars\x18\x04 \x01(\x03R\x11amountPicodollars\x12!\n" +
+	"\fblock_number\x18\x05 \x01(\x04R\vblockNumber\x12*\n" +
+	"\x11reversed_event_id\x18\x06 \x01(\fR\x0freversedEventId\x125\n" +
+	"\x17created_at_unix_seconds\x18\a \x01(\x04R\x14createdAtUnixSeconds\"\xb4\x01\n" +
+	"\x1cGetPayerLedgerEventsResponse\x12B\n" +
+	"\x06events\x18\x01 \x03(\v2*.xmtp.xmtpv4.metadata_api.PayerLedgerEventR\x06events\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\x04R\n" +
+	"nextCursor\x12/\n" +
+	"\x13balance_picodollars\x18\x03 \x01(\x03R\x12balancePicodollars\"I\n" +
+	"\x1eGetPayerBalanceForecastRequest\x12'\n" +
+	"\x0fpayer_addresses\x18\x01 \x03(\tR\x0epayerAddresses\"\xa7\x03\n" +
+	"\x14PayerBalanceForecast\x12/\n" +
+	"\x13balance_picodollars\x18\x01 \x01(\x03R\x12balancePicodollars\x12>\n" +
+	"\x1bunsettled_usage_picodollars\x18\x02 \x01(\x03R\x19unsettledUsagePicodollars\x123\n" +
+	"\x15available_picodollars\x18\x03 \x01(\x03R\x14availablePicodollars\x12;\n" +
+	"\x1aspend_per_hour_picodollars\x18\x04 \x01(\x03R\x17spendPerHourPicodollars\x120\n" +
+	"\x14seconds_to_depletion\x18\x05 \x01(\x04R\x12secondsToDepletion\x12A\n" +
+	"\x05level\x18\x06 \x01(\x0e2+.xmtp.xmtpv4.metadata_api.PayerBalanceLevelR\x05level\x127\n" +
+	"\x18computed_at_unix_seconds\x18\a \x01(\x04R\x15computedAtUnixSeconds\"\xf7\x01\n" +
+	"\x1fGetPayerBalanceForecastResponse\x12f\n" +
+	"\tforecasts\x18\x01 \x03(\v2H.xmtp.xmtpv4.metadata_api.GetPayerBalanceForecastResponse.ForecastsEntryR\tforecasts\x1al\n" +
+	"\x0eForecastsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12D\n" +
+	"\x05value\x18\x02 \x01(\v2..xmtp.xmtpv4.metadata_api.PayerBalanceForecastR\x05value:\x028\x01*\x7f\n" +
 	"\x14PayerInfoGranularity\x12&\n" +
 	"\"PAYER_INFO_GRANULARITY_UNSPECIFIED\x10\x00\x12\x1f\n" +
 	"\x1bPAYER_INFO_GRANULARITY_HOUR\x10\x01\x12\x1e\n" +
-	"\x1aPAYER_INFO_GRANULARITY_DAY\x10\x022\xd9\x03\n" +
+	"\x1aPAYER_INFO_GRANULARITY_DAY\x10\x02*\xb1\x01\n" +
+	"\x13PayerSpendDimension\x12%\n" +
+	"!PAYER_SPEND_DIMENSION_UNSPECIFIED\x10\x00\x12$\n" +
+	" PAYER_SPEND_DIMENSION_ORIGINATOR\x10\x01\x12$\n" +
+	" PAYER_SPEND_DIMENSION_TOPIC_KIND\x10\x02\x12'\n" +
+	"#PAYER_SPEND_DIMENSION_FEE_COMPONENT\x10\x03*c\n" +
+	"\fFeeComponent\x12\x1d\n" +
+	"\x19FEE_COMPONENT_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12FEE_COMPONENT_BASE\x10\x01\x12\x1c\n" +
+	"\x18FEE_COMPONENT_CONGESTION\x10\x02*\x8c\x01\n" +
+	"\x16PayerSpendExportFormat\x12)\n" +
+	"%PAYER_SPEND_EXPORT_FORMAT_UNSPECIFIED\x10\x00\x12!\n" +
+	"\x1dPAYER_SPEND_EXPORT_FORMAT_CSV\x10\x01\x12$\n" +
+	" PAYER_SPEND_EXPORT_FORMAT_NDJSON\x10\x02*\x91\x02\n" +
+	"\x14PayerLedgerEventType\x12'\n" +
+	"#PAYER_LEDGER_EVENT_TYPE_UNSPECIFIED\x10\x00\x12#\n" +
+	"\x1fPAYER_LEDGER_EVENT_TYPE_DEPOSIT\x10\x01\x12&\n" +
+	"\"PAYER_LEDGER_EVENT_TYPE_WITHDRAWAL\x10\x02\x12&\n" +
+	"\"PAYER_LEDGER_EVENT_TYPE_SETTLEMENT\x10\x03\x12/\n" +
+	"+PAYER_LEDGER_EVENT_TYPE_CANCELED_WITHDRAWAL\x10\x04\x12*\n" +
+	"&PAYER_LEDGER_EVENT_TYPE_REORG_REVERSAL\x10\x05*\xb5\x01\n" +
+	"\x11PayerBalanceLevel\x12#\n" +
+	"\x1fPAYER_BALANCE_LEVEL_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16PAYER_BALANCE_LEVEL_OK\x10\x01\x12\x1b\n" +
+	"\x17PAYER_BALANCE_LEVEL_LOW\x10\x02\x12 \n" +
+	"\x1cPAYER_BALANCE_LEVEL_CRITICAL\x10\x03\x12 \n" +
+	"\x1cPAYER_BALANCE_LEVEL_DEPLETED\x10\x042\xd7\b\n" +
 	"\vMetadataApi\x12r\n" +
 	"\rGetSyncCursor\x12..xmtp.xmtpv4.metadata_api.GetSyncCursorRequest\x1a/.xmtp.xmtpv4.metadata_api.GetSyncCursorResponse\"\x00\x12z\n" +
 	"\x13SubscribeSyncCursor\x12..xmtp.xmtpv4.metadata_api.GetSyncCursorRequest\x1a/.xmtp.xmtpv4.metadata_api.GetSyncCursorResponse\"\x000\x01\x12i\n" +
 	"\n" +
 	"GetVersion\x12+.xmtp.xmtpv4.metadata_api.GetVersionRequest\x1a,.xmtp.xmtpv4.metadata_api.GetVersionResponse\"\x00\x12o\n" +
-	"\fGetPayerInfo\x12-.xmtp.xmtpv4.metadata_api.GetPayerInfoRequest\x1a..xmtp.xmtpv4.metadata_api.GetPayerInfoResponse\"\x00B\xe3\x01\n" +
+	"\fGetPayerInfo\x12-.xmtp.xmtpv4.metadata_api.GetPayerInfoRequest\x1a..xmtp.xmtpv4.metadata_api.GetPayerInfoResponse\"\x00\x12l\n" +
+	"\vGetFeeQuote\x12,.xmtp.xmtpv4.metadata_api.GetFeeQuoteRequest\x1a-.xmtp.xmtpv4.metadata_api.GetFeeQuoteResponse\"\x00\x12r\n" +
+	"\rGetPayerSpend\x12..xmtp.xmtpv4.metadata_api.GetPayerSpendRequest\x1a/.xmtp.xmtpv4.metadata_api.GetPayerSpendResponse\"\x00\x12}\n" +
+	"\x10ExportPayerSpend\x121.xmtp.xmtpv4.metadata_api.ExportPayerSpendRequest\x1a2.xmtp.xmtpv4.metadata_api.ExportPayerSpendResponse\"\x000\x01\x12\x87\x01\n" +
+	"\x14GetPayerLedgerEvents\x125.xmtp.xmtpv4.metadata_api.GetPayerLedgerEventsRequest\x1a6.xmtp.xmtpv4.metadata_api.GetPayerLedgerEventsResponse\"\x00\x12\x90\x01\n" +
+	"\x17GetPayerBalanceForecast\x128.xmtp.xmtpv4.metadata_api.GetPayerBalanceForecastRequest\x1a9.xmtp.xmtpv4.metadata_api.GetPayerBalanceForecastResponse\"\x00B\xe3\x01\n" +
 	"\x1ccom.xmtp.xmtpv4.metadata_apiB\x10MetadataApiProtoP\x01Z3github.com/xmtp/xmtpd/pkg/proto/xmtpv4/metadata_api\xa2\x02\x03XXM\xaa\x02\x17Xmtp.Xmtpv4.MetadataApi\xca\x02\x17Xmtp\\Xmtpv4\\MetadataApi\xe2\x02#Xmtp\\Xmtpv4\\MetadataApi\\GPBMetadata\xea\x02\x19Xmtp::Xmtpv4::MetadataApib\x06proto3"
 
 var (
@@ -488,40 +1832,82 @@ func file_xmtpv4_metadata_api_metadata_api_proto_rawDescGZIP() []byte {
 	return file_xmtpv4_metadata_api_metadata_api_proto_rawDescData
 }
 
-var file_xmtpv4_metadata_api_metadata_api_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_xmtpv4_metadata_api_metadata_api_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_xmtpv4_metadata_api_metadata_api_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_xmtpv4_metadata_api_metadata_api_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
 var file_xmtpv4_metadata_api_metadata_api_proto_goTypes = []any{
 	(PayerInfoGranularity)(0),                  // 0: xmtp.xmtpv4.metadata_api.PayerInfoGranularity
-	(*GetSyncCursorRequest)(nil),               // 1: xmtp.xmtpv4.metadata_api.GetSyncCursorRequest
-	(*GetSyncCursorResponse)(nil),              // 2: xmtp.xmtpv4.metadata_api.GetSyncCursorResponse
-	(*GetVersionRequest)(nil),                  // 3: xmtp.xmtpv4.metadata_api.GetVersionRequest
-	(*GetVersionResponse)(nil),                 // 4: xmtp.xmtpv4.metadata_api.GetVersionResponse
-	(*GetPayerInfoRequest)(nil),                // 5: xmtp.xmtpv4.metadata_api.GetPayerInfoRequest
-	(*GetPayerInfoResponse)(nil),               // 6: xmtp.xmtpv4.metadata_api.GetPayerInfoResponse
-	(*GetPayerInfoResponse_PeriodSummary)(nil), // 7: xmtp.xmtpv4.metadata_api.GetPayerInfoResponse.PeriodSummary
-	(*GetPayerInfoResponse_PayerInfo)(nil),     // 8: xmtp.xmtpv4.metadata_api.GetPayerInfoResponse.PayerInfo
-	nil,                                        // 9: xmtp.xmtpv4.metadata_api.GetPayerInfoResponse.PayerInfoEntry
-	(*envelopes.Cursor)(nil),                   // 10: xmtp.xmtpv4.envelopes.Cursor
+	(PayerSpendDimension)(0),                   // 1: xmtp.xmtpv4.metadata_api.PayerSpendDimension
+	(FeeComponent)(0),                          // 2: xmtp.xmtpv4.metadata_api.FeeComponent
+	(PayerSpendExportFormat)(0),                // 3: xmtp.xmtpv4.metadata_api.PayerSpendExportFormat
+	(PayerLedgerEventType)(0),                  // 4: xmtp.xmtpv4.metadata_api.PayerLedgerEventType
+	(PayerBalanceLevel)(0),                     // 5: xmtp.xmtpv4.metadata_api.PayerBalanceLevel
+	(*GetSyncCursorRequest)(nil),               // 6: xmtp.xmtpv4.metadata_api.GetSyncCursorRequest
+	(*GetSyncCursorResponse)(nil),              // 7: xmtp.xmtpv4.metadata_api.GetSyncCursorResponse
+	(*GetVersionRequest)(nil),                  // 8: xmtp.xmtpv4.metadata_api.GetVersionRequest
+	(*GetVersionResponse)(nil),                 // 9: xmtp.xmtpv4.metadata_api.GetVersionResponse
+	(*GetPayerInfoRequest)(nil),                // 10: xmtp.xmtpv4.metadata_api.GetPayerInfoRequest
+	(*GetPayerInfoResponse)(nil),               // 11: xmtp.xmtpv4.metadata_api.GetPayerInfoResponse
+	(*GetFeeQuoteRequest)(nil),                 // 12: xmtp.xmtpv4.metadata_api.GetFeeQuoteRequest
+	(*FeeRates)(nil),                           // 13: xmtp.xmtpv4.metadata_api.FeeRates
+	(*GetFeeQuoteResponse)(nil),                // 14: xmtp.xmtpv4.metadata_api.GetFeeQuoteResponse
+	(*GetPayerSpendRequest)(nil),               // 15: xmtp.xmtpv4.metadata_api.GetPayerSpendRequest
+	(*PayerSpendRow)(nil),                      // 16: xmtp.xmtpv4.metadata_api.PayerSpendRow
+	(*GetPayerSpendResponse)(nil),              // 17: xmtp.xmtpv4.metadata_api.GetPayerSpendResponse
+	(*ExportPayerSpendRequest)(nil),            // 18: xmtp.xmtpv4.metadata_api.ExportPayerSpendRequest
+	(*ExportPayerSpendResponse)(nil),           // 19: xmtp.xmtpv4.metadata_api.ExportPayerSpendResponse
+	(*GetPayerLedgerEventsRequest)(nil),        // 20: xmtp.xmtpv4.metadata_api.GetPayerLedgerEventsRequest
+	(*PayerLedgerEvent)(nil),                   // 21: xmtp.xmtpv4.metadata_api.PayerLedgerEvent
+	(*GetPayerLedgerEventsResponse)(nil),       // 22: xmtp.xmtpv4.metadata_api.GetPayerLedgerEventsResponse
+	(*GetPayerBalanceForecastRequest)(nil),     // 23: xmtp.xmtpv4.metadata_api.GetPayerBalanceForecastRequest
+	(*PayerBalanceForecast)(nil),               // 24: xmtp.xmtpv4.metadata_api.PayerBalanceForecast
+	(*GetPayerBalanceForecastResponse)(nil),    // 25: xmtp.xmtpv4.metadata_api.GetPayerBalanceForecastResponse
+	nil,                                        // 26: xmtp.xmtpv4.metadata_api.GetPayerInfoResponse.PayerInfoEntry
+	(*GetPayerInfoResponse_PeriodSummary)(nil), // 27: xmtp.xmtpv4.metadata_api.GetPayerInfoResponse.PeriodSummary
+	(*GetPayerInfoResponse_PayerInfo)(nil),     // 28: xmtp.xmtpv4.metadata_api.GetPayerInfoResponse.PayerInfo
+	nil,                                        // 29: xmtp.xmtpv4.metadata_api.GetPayerBalanceForecastResponse.ForecastsEntry
+	(*envelopes.Cursor)(nil),                   // 30: xmtp.xmtpv4.envelopes.Cursor
 }
 var file_xmtpv4_metadata_api_metadata_api_proto_depIdxs = []int32{
-	10, // 0: xmtp.xmtpv4.metadata_api.GetSyncCursorResponse.latest_sync:type_name -> xmtp.xmtpv4.envelopes.Cursor
+	30, // 0: xmtp.xmtpv4.metadata_api.GetSyncCursorResponse.latest_sync:type_name -> xmtp.xmtpv4.envelopes.Cursor
 	0,  // 1: xmtp.xmtpv4.metadata_api.GetPayerInfoRequest.granularity:type_name -> xmtp.xmtpv4.metadata_api.PayerInfoGranularity
-	9,  // 2: xmtp.xmtpv4.metadata_api.GetPayerInfoResponse.payer_info:type_name -> xmtp.xmtpv4.metadata_api.GetPayerInfoResponse.PayerInfoEntry
-	7,  // 3: xmtp.xmtpv4.metadata_api.GetPayerInfoResponse.PayerInfo.period_summaries:type_name -> xmtp.xmtpv4.metadata_api.GetPayerInfoResponse.PeriodSummary
-	8,  // 4: xmtp.xmtpv4.metadata_api.GetPayerInfoResponse.PayerInfoEntry.value:type_name -> xmtp.xmtpv4.metadata_api.GetPayerInfoResponse.PayerInfo
-	1,  // 5: xmtp.xmtpv4.metadata_api.MetadataApi.GetSyncCursor:input_type -> xmtp.xmtpv4.metadata_api.GetSyncCursorRequest
-	1,  // 6: xmtp.xmtpv4.metadata_api.MetadataApi.SubscribeSyncCursor:input_type -> xmtp.xmtpv4.metadata_api.GetSyncCursorRequest
-	3,  // 7: xmtp.xmtpv4.metadata_api.MetadataApi.GetVersion:input_type -> xmtp.xmtpv4.metadata_api.GetVersionRequest
-	5,  // 8: xmtp.xmtpv4.metadata_api.MetadataApi.GetPayerInfo:input_type -> xmtp.xmtpv4.metadata_api.GetPayerInfoRequest
-	2,  // 9: xmtp.xmtpv4.metadata_api.MetadataApi.GetSyncCursor:output_type -> xmtp.xmtpv4.metadata_api.GetSyncCursorResponse
-	2,  // 10: xmtp.xmtpv4.metadata_api.MetadataApi.SubscribeSyncCursor:output_type -> xmtp.xmtpv4.metadata_api.GetSyncCursorResponse
-	4,  // 11: xmtp.xmtpv4.metadata_api.MetadataApi.GetVersion:output_type -> xmtp.xmtpv4.metadata_api.GetVersionResponse
-	6,  // 12: xmtp.xmtpv4.metadata_api.MetadataApi.GetPayerInfo:output_type -> xmtp.xmtpv4.metadata_api.GetPayerInfoResponse
-	9,  // [9:13] is the sub-list for method output_type
-	5,  // [5:9] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	26, // 2: xmtp.xmtpv4.metadata_api.GetPayerInfoResponse.payer_info:type_name -> xmtp.xmtpv4.metadata_api.GetPayerInfoResponse.PayerInfoEntry
+	13, // 3: xmtp.xmtpv4.metadata_api.GetFeeQuoteResponse.rates:type_name -> xmtp.xmtpv4.metadata_api.FeeRates
+	0,  // 4: xmtp.xmtpv4.metadata_api.GetPayerSpendRequest.granularity:type_name -> xmtp.xmtpv4.metadata_api.PayerInfoGranularity
+	1,  // 5: xmtp.xmtpv4.metadata_api.GetPayerSpendRequest.group_by:type_name -> xmtp.xmtpv4.metadata_api.PayerSpendDimension
+	2,  // 6: xmtp.xmtpv4.metadata_api.PayerSpendRow.fee_component:type_name -> xmtp.xmtpv4.metadata_api.FeeComponent
+	16, // 7: xmtp.xmtpv4.metadata_api.GetPayerSpendResponse.rows:type_name -> xmtp.xmtpv4.metadata_api.PayerSpendRow
+	15, // 8: xmtp.xmtpv4.metadata_api.ExportPayerSpendRequest.query:type_name -> xmtp.xmtpv4.metadata_api.GetPayerSpendRequest
+	3,  // 9: xmtp.xmtpv4.metadata_api.ExportPayerSpendRequest.format:type_name -> xmtp.xmtpv4.metadata_api.PayerSpendExportFormat
+	4,  // 10: xmtp.xmtpv4.metadata_api.PayerLedgerEvent.event_type:type_name -> xmtp.xmtpv4.metadata_api.PayerLedgerEventType
+	21, // 11: xmtp.xmtpv4.metadata_api.GetPayerLedgerEventsResponse.events:type_name -> xmtp.xmtpv4.metadata_api.PayerLedgerEvent
+	5,  // 12: xmtp.xmtpv4.metadata_api.PayerBalanceForecast.level:type_name -> xmtp.xmtpv4.metadata_api.PayerBalanceLevel
+	29, // 13: xmtp.xmtpv4.metadata_api.GetPayerBalanceForecastResponse.forecasts:type_name -> xmtp.xmtpv4.metadata_api.GetPayerBalanceForecastResponse.ForecastsEntry
+	28, // 14: xmtp.xmtpv4.metadata_api.GetPayerInfoResponse.PayerInfoEntry.value:type_name -> xmtp.xmtpv4.metadata_api.GetPayerInfoResponse.PayerInfo
+	27, // 15: xmtp.xmtpv4.metadata_api.GetPayerInfoResponse.PayerInfo.period_summaries:type_name -> xmtp.xmtpv4.metadata_api.GetPayerInfoResponse.PeriodSummary
+	24, // 16: xmtp.xmtpv4.metadata_api.GetPayerBalanceForecastResponse.ForecastsEntry.value:type_name -> xmtp.xmtpv4.metadata_api.PayerBalanceForecast
+	6,  // 17: xmtp.xmtpv4.metadata_api.MetadataApi.GetSyncCursor:input_type -> xmtp.xmtpv4.metadata_api.GetSyncCursorRequest
+	6,  // 18: xmtp.xmtpv4.metadata_api.MetadataApi.SubscribeSyncCursor:input_type -> xmtp.xmtpv4.metadata_api.GetSyncCursorRequest
+	8,  // 19: xmtp.xmtpv4.metadata_api.MetadataApi.GetVersion:input_type -> xmtp.xmtpv4.metadata_api.GetVersionRequest
+	10, // 20: xmtp.xmtpv4.metadata_api.MetadataApi.GetPayerInfo:input_type -> xmtp.xmtpv4.metadata_api.GetPayerInfoRequest
+	12, // 21: xmtp.xmtpv4.metadata_api.MetadataApi.GetFeeQuote:input_type -> xmtp.xmtpv4.metadata_api.GetFeeQuoteRequest
+	15, // 22: xmtp.xmtpv4.metadata_api.MetadataApi.GetPayerSpend:input_type -> xmtp.xmtpv4.metadata_api.GetPayerSpendRequest
+	18, // 23: xmtp.xmtpv4.metadata_api.MetadataApi.ExportPayerSpend:input_type -> xmtp.xmtpv4.metadata_api.ExportPayerSpendRequest
+	20, // 24: xmtp.xmtpv4.metadata_api.MetadataApi.GetPayerLedgerEvents:input_type -> xmtp.xmtpv4.metadata_api.GetPayerLedgerEventsRequest
+	23, // 25: xmtp.xmtpv4.metadata_api.MetadataApi.GetPayerBalanceForecast:input_type -> xmtp.xmtpv4.metadata_api.GetPayerBalanceForecastRequest
+	7,  // 26: xmtp.xmtpv4.metadata_api.MetadataApi.GetSyncCursor:output_type -> xmtp.xmtpv4.metadata_api.GetSyncCursorResponse
+	7,  // 27: xmtp.xmtpv4.metadata_api.MetadataApi.SubscribeSyncCursor:output_type -> xmtp.xmtpv4.metadata_api.GetSyncCursorResponse
+	9,  // 28: xmtp.xmtpv4.metadata_api.MetadataApi.GetVersion:output_type -> xmtp.xmtpv4.metadata_api.GetVersionResponse
+	11, // 29: xmtp.xmtpv4.metadata_api.MetadataApi.GetPayerInfo:output_type -> xmtp.xmtpv4.metadata_api.GetPayerInfoResponse
+	14, // 30: xmtp.xmtpv4.metadata_api.MetadataApi.GetFeeQuote:output_type -> xmtp.xmtpv4.metadata_api.GetFeeQuoteResponse
+	17, // 31: xmtp.xmtpv4.metadata_api.MetadataApi.GetPayerSpend:output_type -> xmtp.xmtpv4.metadata_api.GetPayerSpendResponse
+	19, // 32: xmtp.xmtpv4.metadata_api.MetadataApi.ExportPayerSpend:output_type -> xmtp.xmtpv4.metadata_api.ExportPayerSpendResponse
+	22, // 33: xmtp.xmtpv4.metadata_api.MetadataApi.GetPayerLedgerEvents:output_type -> xmtp.xmtpv4.metadata_api.GetPayerLedgerEventsResponse
+	25, // 34: xmtp.xmtpv4.metadata_api.MetadataApi.GetPayerBalanceForecast:output_type -> xmtp.xmtpv4.metadata_api.GetPayerBalanceForecastResponse
+	26, // [26:35] is the sub-list for method output_type
+	17, // [17:26] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
 }
 
 func init() { file_xmtpv4_metadata_api_metadata_api_proto_init() }
@@ -534,8 +1920,8 @@ func file_xmtpv4_metadata_api_metadata_api_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_xmtpv4_metadata_api_metadata_api_proto_rawDesc), len(file_xmtpv4_metadata_api_metadata_api_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   9,
+			NumEnums:      6,
+			NumMessages:   24,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
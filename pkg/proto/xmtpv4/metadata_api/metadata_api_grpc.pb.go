@@ -21,10 +21,15 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	MetadataApi_GetSyncCursor_FullMethodName       = "/xmtp.xmtpv4.metadata_api.MetadataApi/GetSyncCursor"
-	MetadataApi_SubscribeSyncCursor_FullMethodName = "/xmtp.xmtpv4.metadata_api.MetadataApi/SubscribeSyncCursor"
-	MetadataApi_GetVersion_FullMethodName          = "/xmtp.xmtpv4.metadata_api.MetadataApi/GetVersion"
-	MetadataApi_GetPayerInfo_FullMethodName        = "/xmtp.xmtpv4.metadata_api.MetadataApi/GetPayerInfo"
+	MetadataApi_GetSyncCursor_FullMethodName           = "/xmtp.xmtpv4.metadata_api.MetadataApi/GetSyncCursor"
+	MetadataApi_SubscribeSyncCursor_FullMethodName     = "/xmtp.xmtpv4.metadata_api.MetadataApi/SubscribeSyncCursor"
+	MetadataApi_GetVersion_FullMethodName              = "/xmtp.xmtpv4.metadata_api.MetadataApi/GetVersion"
+	MetadataApi_GetPayerInfo_FullMethodName            = "/xmtp.xmtpv4.metadata_api.MetadataApi/GetPayerInfo"
+	MetadataApi_GetFeeQuote_FullMethodName             = "/xmtp.xmtpv4.metadata_api.MetadataApi/GetFeeQuote"
+	MetadataApi_GetPayerSpend_FullMethodName           = "/xmtp.xmtpv4.metadata_api.MetadataApi/GetPayerSpend"
+	MetadataApi_ExportPayerSpend_FullMethodName        = "/xmtp.xmtpv4.metadata_api.MetadataApi/ExportPayerSpend"
+	MetadataApi_GetPayerLedgerEvents_FullMethodName    = "/xmtp.xmtpv4.metadata_api.MetadataApi/GetPayerLedgerEvents"
+	MetadataApi_GetPayerBalanceForecast_FullMethodName = "/xmtp.xmtpv4.metadata_api.MetadataApi/GetPayerBalanceForecast"
 )
 
 // MetadataApiClient is the client API for MetadataApi service.
@@ -37,6 +42,11 @@ type MetadataApiClient interface {
 	SubscribeSyncCursor(ctx context.Context, in *GetSyncCursorRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetSyncCursorResponse], error)
 	GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*GetVersionResponse, error)
 	GetPayerInfo(ctx context.Context, in *GetPayerInfoRequest, opts ...grpc.CallOption) (*GetPayerInfoResponse, error)
+	GetFeeQuote(ctx context.Context, in *GetFeeQuoteRequest, opts ...grpc.CallOption) (*GetFeeQuoteResponse, error)
+	GetPayerSpend(ctx context.Context, in *GetPayerSpendRequest, opts ...grpc.CallOption) (*GetPayerSpendResponse, error)
+	ExportPayerSpend(ctx context.Context, in *ExportPayerSpendRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportPayerSpendResponse], error)
+	GetPayerLedgerEvents(ctx context.Context, in *GetPayerLedgerEventsRequest, opts ...grpc.CallOption) (*GetPayerLedgerEventsResponse, error)
+	GetPayerBalanceForecast(ctx context.Context, in *GetPayerBalanceForecastRequest, opts ...grpc.CallOption) (*GetPayerBalanceForecastResponse, error)
 }
 
 type metadataApiClient struct {
@@ -96,6 +106,65 @@ func (c *metadataApiClient) GetPayerInfo(ctx context.Context, in *GetPayerInfoRe
 	return out, nil
 }
 
+func (c *metadataApiClient) GetFeeQuote(ctx context.Context, in *GetFeeQuoteRequest, opts ...grpc.CallOption) (*GetFeeQuoteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetFeeQuoteResponse)
+	err := c.cc.Invoke(ctx, MetadataApi_GetFeeQuote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metadataApiClient) GetPayerSpend(ctx context.Context, in *GetPayerSpendRequest, opts ...grpc.CallOption) (*GetPayerSpendResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPayerSpendResponse)
+	err := c.cc.Invoke(ctx, MetadataApi_GetPayerSpend_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metadataApiClient) ExportPayerSpend(ctx context.Context, in *ExportPayerSpendRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportPayerSpendResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MetadataApi_ServiceDesc.Streams[1], MetadataApi_ExportPayerSpend_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportPayerSpendRequest, ExportPayerSpendResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MetadataApi_ExportPayerSpendClient = grpc.ServerStreamingClient[ExportPayerSpendResponse]
+
+func (c *metadataApiClient) GetPayerLedgerEvents(ctx context.Context, in *GetPayerLedgerEventsRequest, opts ...grpc.CallOption) (*GetPayerLedgerEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPayerLedgerEventsResponse)
+	err := c.cc.Invoke(ctx, MetadataApi_GetPayerLedgerEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metadataApiClient) GetPayerBalanceForecast(ctx context.Context, in *GetPayerBalanceForecastRequest, opts ...grpc.CallOption) (*GetPayerBalanceForecastResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPayerBalanceForecastResponse)
+	err := c.cc.Invoke(ctx, MetadataApi_GetPayerBalanceForecast_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MetadataApiServer is the server API for MetadataApi service.
 // All implementations should embed UnimplementedMetadataApiServer
 // for forward compatibility.
@@ -106,6 +175,11 @@ type MetadataApiServer interface {
 	SubscribeSyncCursor(*GetSyncCursorRequest, grpc.ServerStreamingServer[GetSyncCursorResponse]) error
 	GetVersion(context.Context, *GetVersionRequest) (*GetVersionResponse, error)
 	GetPayerInfo(context.Context, *GetPayerInfoRequest) (*GetPayerInfoResponse, error)
+	GetFeeQuote(context.Context, *GetFeeQuoteRequest) (*GetFeeQuoteResponse, error)
+	GetPayerSpend(context.Context, *GetPayerSpendRequest) (*GetPayerSpendResponse, error)
+	ExportPayerSpend(*ExportPayerSpendRequest, grpc.ServerStreamingServer[ExportPayerSpendResponse]) error
+	GetPayerLedgerEvents(context.Context, *GetPayerLedgerEventsRequest) (*GetPayerLedgerEventsResponse, error)
+	GetPayerBalanceForecast(context.Context, *GetPayerBalanceForecastRequest) (*GetPayerBalanceForecastResponse, error)
 }
 
 // UnimplementedMetadataApiServer should be embedded to have
@@ -127,6 +201,21 @@ func (UnimplementedMetadataApiServer) GetVersion(context.Context, *GetVersionReq
 func (UnimplementedMetadataApiServer) GetPayerInfo(context.Context, *GetPayerInfoRequest) (*GetPayerInfoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPayerInfo not implemented")
 }
+func (UnimplementedMetadataApiServer) GetFeeQuote(context.Context, *GetFeeQuoteRequest) (*GetFeeQuoteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFeeQuote not implemented")
+}
+func (UnimplementedMetadataApiServer) GetPayerSpend(context.Context, *GetPayerSpendRequest) (*GetPayerSpendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPayerSpend not implemented")
+}
+func (UnimplementedMetadataApiServer) ExportPayerSpend(*ExportPayerSpendRequest, grpc.ServerStreamingServer[ExportPayerSpendResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportPayerSpend not implemented")
+}
+func (UnimplementedMetadataApiServer) GetPayerLedgerEvents(context.Context, *GetPayerLedgerEventsRequest) (*GetPayerLedgerEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPayerLedgerEvents not implemented")
+}
+func (UnimplementedMetadataApiServer) GetPayerBalanceForecast(context.Context, *GetPayerBalanceForecastRequest) (*GetPayerBalanceForecastResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPayerBalanceForecast not implemented")
+}
 func (UnimplementedMetadataApiServer) testEmbeddedByValue() {}
 
 // UnsafeMetadataApiServer may be embedded to opt out of forward compatibility for this service.
@@ -212,6 +301,89 @@ func _MetadataApi_GetPayerInfo_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MetadataApi_GetFeeQuote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFeeQuoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataApiServer).GetFeeQuote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MetadataApi_GetFeeQuote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataApiServer).GetFeeQuote(ctx, req.(*GetFeeQuoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetadataApi_GetPayerSpend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPayerSpendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataApiServer).GetPayerSpend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MetadataApi_GetPayerSpend_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataApiServer).GetPayerSpend(ctx, req.(*GetPayerSpendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetadataApi_ExportPayerSpend_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportPayerSpendRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MetadataApiServer).ExportPayerSpend(m, &grpc.GenericServerStream[ExportPayerSpendRequest, ExportPayerSpendResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MetadataApi_ExportPayerSpendServer = grpc.ServerStreamingServer[ExportPayerSpendResponse]
+
+func _MetadataApi_GetPayerLedgerEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPayerLedgerEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataApiServer).GetPayerLedgerEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MetadataApi_GetPayerLedgerEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataApiServer).GetPayerLedgerEvents(ctx, req.(*GetPayerLedgerEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetadataApi_GetPayerBalanceForecast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPayerBalanceForecastRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataApiServer).GetPayerBalanceForecast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MetadataApi_GetPayerBalanceForecast_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataApiServer).GetPayerBalanceForecast(ctx, req.(*GetPayerBalanceForecastRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MetadataApi_ServiceDesc is the grpc.ServiceDesc for MetadataApi service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -231,6 +403,22 @@ var MetadataApi_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetPayerInfo",
 			Handler:    _MetadataApi_GetPayerInfo_Handler,
 		},
+		{
+			MethodName: "GetFeeQuote",
+			Handler:    _MetadataApi_GetFeeQuote_Handler,
+		},
+		{
+			MethodName: "GetPayerSpend",
+			Handler:    _MetadataApi_GetPayerSpend_Handler,
+		},
+		{
+			MethodName: "GetPayerLedgerEvents",
+			Handler:    _MetadataApi_GetPayerLedgerEvents_Handler,
+		},
+		{
+			MethodName: "GetPayerBalanceForecast",
+			Handler:    _MetadataApi_GetPayerBalanceForecast_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -238,6 +426,11 @@ var MetadataApi_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _MetadataApi_SubscribeSyncCursor_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ExportPayerSpend",
+			Handler:       _MetadataApi_ExportPayerSpend_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "xmtpv4/metadata_api/metadata_api.proto",
 }
@@ -46,6 +46,20 @@ const (
 	// MetadataApiGetPayerInfoProcedure is the fully-qualified name of the MetadataApi's GetPayerInfo
 	// RPC.
 	MetadataApiGetPayerInfoProcedure = "/xmtp.xmtpv4.metadata_api.MetadataApi/GetPayerInfo"
+	// MetadataApiGetFeeQuoteProcedure is the fully-qualified name of the MetadataApi's GetFeeQuote RPC.
+	MetadataApiGetFeeQuoteProcedure = "/xmtp.xmtpv4.metadata_api.MetadataApi/GetFeeQuote"
+	// MetadataApiGetPayerSpendProcedure is the fully-qualified name of the MetadataApi's GetPayerSpend
+	// RPC.
+	MetadataApiGetPayerSpendProcedure = "/xmtp.xmtpv4.metadata_api.MetadataApi/GetPayerSpend"
+	// MetadataApiExportPayerSpendProcedure is the fully-qualified name of the MetadataApi's
+	// ExportPayerSpend RPC.
+	MetadataApiExportPayerSpendProcedure = "/xmtp.xmtpv4.metadata_api.MetadataApi/ExportPayerSpend"
+	// MetadataApiGetPayerLedgerEventsProcedure is the fully-qualified name of the MetadataApi's
+	// GetPayerLedgerEvents RPC.
+	MetadataApiGetPayerLedgerEventsProcedure = "/xmtp.xmtpv4.metadata_api.MetadataApi/GetPayerLedgerEvents"
+	// MetadataApiGetPayerBalanceForecastProcedure is the fully-qualified name of the MetadataApi's
+	// GetPayerBalanceForecast RPC.
+	MetadataApiGetPayerBalanceForecastProcedure = "/xmtp.xmtpv4.metadata_api.MetadataApi/GetPayerBalanceForecast"
 )
 
 // MetadataApiClient is a client for the xmtp.xmtpv4.metadata_api.MetadataApi service.
@@ -54,6 +68,11 @@ type MetadataApiClient interface {
 	SubscribeSyncCursor(context.Context, *connect.Request[metadata_api.GetSyncCursorRequest]) (*connect.ServerStreamForClient[metadata_api.GetSyncCursorResponse], error)
 	GetVersion(context.Context, *connect.Request[metadata_api.GetVersionRequest]) (*connect.Response[metadata_api.GetVersionResponse], error)
 	GetPayerInfo(context.Context, *connect.Request[metadata_api.GetPayerInfoRequest]) (*connect.Response[metadata_api.GetPayerInfoResponse], error)
+	GetFeeQuote(context.Context, *connect.Request[metadata_api.GetFeeQuoteRequest]) (*connect.Response[metadata_api.GetFeeQuoteResponse], error)
+	GetPayerSpend(context.Context, *connect.Request[metadata_api.GetPayerSpendRequest]) (*connect.Response[metadata_api.GetPayerSpendResponse], error)
+	ExportPayerSpend(context.Context, *connect.Request[metadata_api.ExportPayerSpendRequest]) (*connect.ServerStreamForClient[metadata_api.ExportPayerSpendResponse], error)
+	GetPayerLedgerEvents(context.Context, *connect.Request[metadata_api.GetPayerLedgerEventsRequest]) (*connect.Response[metadata_api.GetPayerLedgerEventsResponse], error)
+	GetPayerBalanceForecast(context.Context, *connect.Request[metadata_api.GetPayerBalanceForecastRequest]) (*connect.Response[metadata_api.GetPayerBalanceForecastResponse], error)
 }
 
 // NewMetadataApiClient constructs a client for the xmtp.xmtpv4.metadata_api.MetadataApi service. By
@@ -91,15 +110,50 @@ func NewMetadataApiClient(httpClient connect.HTTPClient, baseURL string, opts ..
 			connect.WithSchema(metadataApiMethods.ByName("GetPayerInfo")),
 			connect.WithClientOptions(opts...),
 		),
+		getFeeQuote: connect.NewClient[metadata_api.GetFeeQuoteRequest, metadata_api.GetFeeQuoteResponse](
+			httpClient,
+			baseURL+MetadataApiGetFeeQuoteProcedure,
+			connect.WithSchema(metadataApiMethods.ByName("GetFeeQuote")),
+			connect.WithClientOptions(opts...),
+		),
+		getPayerSpend: connect.NewClient[metadata_api.GetPayerSpendRequest, metadata_api.GetPayerSpendResponse](
+			httpClient,
+			baseURL+MetadataApiGetPayerSpendProcedure,
+			connect.WithSchema(metadataApiMethods.ByName("GetPayerSpend")),
+			connect.WithClientOptions(opts...),
+		),
+		exportPayerSpend: connect.NewClient[metadata_api.ExportPayerSpendRequest, metadata_api.ExportPayerSpendResponse](
+			httpClient,
+			baseURL+MetadataApiExportPayerSpendProcedure,
+			connect.WithSchema(metadataApiMethods.ByName("ExportPayerSpend")),
+			connect.WithClientOptions(opts...),
+		),
+		getPayerLedgerEvents: connect.NewClient[metadata_api.GetPayerLedgerEventsRequest, metadata_api.GetPayerLedgerEventsResponse](
+			httpClient,
+			baseURL+MetadataApiGetPayerLedgerEventsProcedure,
+			connect.WithSchema(metadataApiMethods.ByName("GetPayerLedgerEvents")),
+			connect.WithClientOptions(opts...),
+		),
+		getPayerBalanceForecast: connect.NewClient[metadata_api.GetPayerBalanceForecastRequest, metadata_api.GetPayerBalanceForecastResponse](
+			httpClient,
+			baseURL+MetadataApiGetPayerBalanceForecastProcedure,
+			connect.WithSchema(metadataApiMethods.ByName("GetPayerBalanceForecast")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // metadataApiClient implements MetadataApiClient.
 type metadataApiClient struct {
-	getSyncCursor       *connect.Client[metadata_api.GetSyncCursorRequest, metadata_api.GetSyncCursorResponse]
-	subscribeSyncCursor *connect.Client[metadata_api.GetSyncCursorRequest, metadata_api.GetSyncCursorResponse]
-	getVersion          *connect.Client[metadata_api.GetVersionRequest, metadata_api.GetVersionResponse]
-	getPayerInfo        *connect.Client[metadata_api.GetPayerInfoRequest, metadata_api.GetPayerInfoResponse]
+	getSyncCursor           *connect.Client[metadata_api.GetSyncCursorRequest, metadata_api.GetSyncCursorResponse]
+	subscribeSyncCursor     *connect.Client[metadata_api.GetSyncCursorRequest, metadata_api.GetSyncCursorResponse]
+	getVersion              *connect.Client[metadata_api.GetVersionRequest, metadata_api.GetVersionResponse]
+	getPayerInfo            *connect.Client[metadata_api.GetPayerInfoRequest, metadata_api.GetPayerInfoResponse]
+	getFeeQuote             *connect.Client[metadata_api.GetFeeQuoteRequest, metadata_api.GetFeeQuoteResponse]
+	getPayerSpend           *connect.Client[metadata_api.GetPayerSpendRequest, metadata_api.GetPayerSpendResponse]
+	exportPayerSpend        *connect.Client[metadata_api.ExportPayerSpendRequest, metadata_api.ExportPayerSpendResponse]
+	getPayerLedgerEvents    *connect.Client[metadata_api.GetPayerLedgerEventsRequest, metadata_api.GetPayerLedgerEventsResponse]
+	getPayerBalanceForecast *connect.Client[metadata_api.GetPayerBalanceForecastRequest, metadata_api.GetPayerBalanceForecastResponse]
 }
 
 // GetSyncCursor calls xmtp.xmtpv4.metadata_api.MetadataApi.GetSyncCursor.
@@ -122,12 +176,42 @@ func (c *metadataApiClient) GetPayerInfo(ctx context.Context, req *connect.Reque
 	return c.getPayerInfo.CallUnary(ctx, req)
 }
 
+// GetFeeQuote calls xmtp.xmtpv4.metadata_api.MetadataApi.GetFeeQuote.
+func (c *metadataApiClient) GetFeeQuote(ctx context.Context, req *connect.Request[metadata_api.GetFeeQuoteRequest]) (*connect.Response[metadata_api.GetFeeQuoteResponse], error) {
+	return c.getFeeQuote.CallUnary(ctx, req)
+}
+
+// GetPayerSpend calls xmtp.xmtpv4.metadata_api.MetadataApi.GetPayerSpend.
+func (c *metadataApiClient) GetPayerSpend(ctx context.Context, req *connect.Request[metadata_api.GetPayerSpendRequest]) (*connect.Response[metadata_api.GetPayerSpendResponse], error) {
+	return c.getPayerSpend.CallUnary(ctx, req)
+}
+
+// ExportPayerSpend calls xmtp.xmtpv4.metadata_api.MetadataApi.ExportPayerSpend.
+func (c *metadataApiClient) ExportPayerSpend(ctx context.Context, req *connect.Request[metadata_api.ExportPayerSpendRequest]) (*connect.ServerStreamForClient[metadata_api.ExportPayerSpendResponse], error) {
+	return c.exportPayerSpend.CallServerStream(ctx, req)
+}
+
+// GetPayerLedgerEvents calls xmtp.xmtpv4.metadata_api.MetadataApi.GetPayerLedgerEvents.
+func (c *metadataApiClient) GetPayerLedgerEvents(ctx context.Context, req *connect.Request[metadata_api.GetPayerLedgerEventsRequest]) (*connect.Response[metadata_api.GetPayerLedgerEventsResponse], error) {
+	return c.getPayerLedgerEvents.CallUnary(ctx, req)
+}
+
+// GetPayerBalanceForecast calls xmtp.xmtpv4.metadata_api.MetadataApi.GetPayerBalanceForecast.
+func (c *metadataApiClient) GetPayerBalanceForecast(ctx context.Context, req *connect.Request[metadata_api.GetPayerBalanceForecastRequest]) (*connect.Response[metadata_api.GetPayerBalanceForecastResponse], error) {
+	return c.getPayerBalanceForecast.CallUnary(ctx, req)
+}
+
 // MetadataApiHandler is an implementation of the xmtp.xmtpv4.metadata_api.MetadataApi service.
 type MetadataApiHandler interface {
 	GetSyncCursor(context.Context, *connect.Request[metadata_api.GetSyncCursorRequest]) (*connect.Response[metadata_api.GetSyncCursorResponse], error)
 	SubscribeSyncCursor(context.Context, *connect.Request[metadata_api.GetSyncCursorRequest], *connect.ServerStream[metadata_api.GetSyncCursorResponse]) error
 	GetVersion(context.Context, *connect.Request[metadata_api.GetVersionRequest]) (*connect.Response[metadata_api.GetVersionResponse], error)
 	GetPayerInfo(context.Context, *connect.Request[metadata_api.GetPayerInfoRequest]) (*connect.Response[metadata_api.GetPayerInfoResponse], error)
+	GetFeeQuote(context.Context, *connect.Request[metadata_api.GetFeeQuoteRequest]) (*connect.Response[metadata_api.GetFeeQuoteResponse], error)
+	GetPayerSpend(context.Context, *connect.Request[metadata_api.GetPayerSpendRequest]) (*connect.Response[metadata_api.GetPayerSpendResponse], error)
+	ExportPayerSpend(context.Context, *connect.Request[metadata_api.ExportPayerSpendRequest], *connect.ServerStream[metadata_api.ExportPayerSpendResponse]) error
+	GetPayerLedgerEvents(context.Context, *connect.Request[metadata_api.GetPayerLedgerEventsRequest]) (*connect.Response[metadata_api.GetPayerLedgerEventsResponse], error)
+	GetPayerBalanceForecast(context.Context, *connect.Request[metadata_api.GetPayerBalanceForecastRequest]) (*connect.Response[metadata_api.GetPayerBalanceForecastResponse], error)
 }
 
 // NewMetadataApiHandler builds an HTTP handler from the service implementation. It returns the path
@@ -161,6 +245,36 @@ func NewMetadataApiHandler(svc MetadataApiHandler, opts ...connect.HandlerOption
 		connect.WithSchema(metadataApiMethods.ByName("GetPayerInfo")),
 		connect.WithHandlerOptions(opts...),
 	)
+	metadataApiGetFeeQuoteHandler := connect.NewUnaryHandler(
+		MetadataApiGetFeeQuoteProcedure,
+		svc.GetFeeQuote,
+		connect.WithSchema(metadataApiMethods.ByName("GetFeeQuote")),
+		connect.WithHandlerOptions(opts...),
+	)
+	metadataApiGetPayerSpendHandler := connect.NewUnaryHandler(
+		MetadataApiGetPayerSpendProcedure,
+		svc.GetPayerSpend,
+		connect.WithSchema(metadataApiMethods.ByName("GetPayerSpend")),
+		connect.WithHandlerOptions(opts...),
+	)
+	metadataApiExportPayerSpendHandler := connect.NewServerStreamHandler(
+		MetadataApiExportPayerSpendProcedure,
+		svc.ExportPayerSpend,
+		connect.WithSchema(metadataApiMethods.ByName("ExportPayerSpend")),
+		connect.WithHandlerOptions(opts...),
+	)
+	metadataApiGetPayerLedgerEventsHandler := connect.NewUnaryHandler(
+		MetadataApiGetPayerLedgerEventsProcedure,
+		svc.GetPayerLedgerEvents,
+		connect.WithSchema(metadataApiMethods.ByName("GetPayerLedgerEvents")),
+		connect.WithHandlerOptions(opts...),
+	)
+	metadataApiGetPayerBalanceForecastHandler := connect.NewUnaryHandler(
+		MetadataApiGetPayerBalanceForecastProcedure,
+		svc.GetPayerBalanceForecast,
+		connect.WithSchema(metadataApiMethods.ByName("GetPayerBalanceForecast")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/xmtp.xmtpv4.metadata_api.MetadataApi/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case MetadataApiGetSyncCursorProcedure:
@@ -171,6 +285,16 @@ func NewMetadataApiHandler(svc MetadataApiHandler, opts ...connect.HandlerOption
 			metadataApiGetVersionHandler.ServeHTTP(w, r)
 		case MetadataApiGetPayerInfoProcedure:
 			metadataApiGetPayerInfoHandler.ServeHTTP(w, r)
+		case MetadataApiGetFeeQuoteProcedure:
+			metadataApiGetFeeQuoteHandler.ServeHTTP(w, r)
+		case MetadataApiGetPayerSpendProcedure:
+			metadataApiGetPayerSpendHandler.ServeHTTP(w, r)
+		case MetadataApiExportPayerSpendProcedure:
+			metadataApiExportPayerSpendHandler.ServeHTTP(w, r)
+		case MetadataApiGetPayerLedgerEventsProcedure:
+			metadataApiGetPayerLedgerEventsHandler.ServeHTTP(w, r)
+		case MetadataApiGetPayerBalanceForecastProcedure:
+			metadataApiGetPayerBalanceForecastHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -195,3 +319,23 @@ func (UnimplementedMetadataApiHandler) GetVersion(context.Context, *connect.Requ
 func (UnimplementedMetadataApiHandler) GetPayerInfo(context.Context, *connect.Request[metadata_api.GetPayerInfoRequest]) (*connect.Response[metadata_api.GetPayerInfoResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("xmtp.xmtpv4.metadata_api.MetadataApi.GetPayerInfo is not implemented"))
 }
+
+func (UnimplementedMetadataApiHandler) GetFeeQuote(context.Context, *connect.Request[metadata_api.GetFeeQuoteRequest]) (*connect.Response[metadata_api.GetFeeQuoteResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("xmtp.xmtpv4.metadata_api.MetadataApi.GetFeeQuote is not implemented"))
+}
+
+func (UnimplementedMetadataApiHandler) GetPayerSpend(context.Context, *connect.Request[metadata_api.GetPayerSpendRequest]) (*connect.Response[metadata_api.GetPayerSpendResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("xmtp.xmtpv4.metadata_api.MetadataApi.GetPayerSpend is not implemented"))
+}
+
+func (UnimplementedMetadataApiHandler) ExportPayerSpend(context.Context, *connect.Request[metadata_api.ExportPayerSpendRequest], *connect.ServerStream[metadata_api.ExportPayerSpendResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("xmtp.xmtpv4.metadata_api.MetadataApi.ExportPayerSpend is not implemented"))
+}
+
+func (UnimplementedMetadataApiHandler) GetPayerLedgerEvents(context.Context, *connect.Request[metadata_api.GetPayerLedgerEventsRequest]) (*connect.Response[metadata_api.GetPayerLedgerEventsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("xmtp.xmtpv4.metadata_api.MetadataApi.GetPayerLedgerEvents is not implemented"))
+}
+
+func (UnimplementedMetadataApiHandler) GetPayerBalanceForecast(context.Context, *connect.Request[metadata_api.GetPayerBalanceForecastRequest]) (*connect.Response[metadata_api.GetPayerBalanceForecastResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("xmtp.xmtpv4.metadata_api.MetadataApi.GetPayerBalanceForecast is not implemented"))
+}
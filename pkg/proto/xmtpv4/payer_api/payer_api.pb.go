@@ -25,8 +25,13 @@ const (
 )
 
 type PublishClientEnvelopesRequest struct {
-	state         protoimpl.MessageState      `protogen:"open.v1"`
-	Envelopes     []*envelopes.ClientEnvelope `protobuf:"bytes,1,rep,name=envelopes,proto3" json:"envelopes,omitempty"`
+	state     protoimpl.MessageState      `protogen:"open.v1"`
+	Envelopes []*envelopes.ClientEnvelope `protobuf:"bytes,1,rep,name=envelopes,proto3" json:"envelopes,omitempty"`
+	// Report envelopes that failed to publish in the response, instead of failing the request
+	AllowPartialFailure bool `protobuf:"varint,2,opt,name=allow_partial_failure,json=allowPartialFailure,proto3" json:"allow_partial_failure,omitempty"`
+	// Return as soon as the envelopes are stored in the gateway publish outbox, without
+	// waiting for them to be published. Requires the outbox to be enabled on the gateway.
+	Async         bool `protobuf:"varint,3,opt,name=async,proto3" json:"async,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -68,11 +73,31 @@ func (x *PublishClientEnvelopesRequest) GetEnvelopes() []*envelopes.ClientEnvelo
 	return nil
 }
 
+func (x *PublishClientEnvelopesRequest) GetAllowPartialFailure() bool {
+	if x != nil {
+		return x.AllowPartialFailure
+	}
+	return false
+}
+
+func (x *PublishClientEnvelopesRequest) GetAsync() bool {
+	if x != nil {
+		return x.Async
+	}
+	return false
+}
+
 type PublishClientEnvelopesResponse struct {
 	state               protoimpl.MessageState          `protogen:"open.v1"`
 	OriginatorEnvelopes []*envelopes.OriginatorEnvelope `protobuf:"bytes,1,rep,name=originator_envelopes,json=originatorEnvelopes,proto3" json:"originator_envelopes,omitempty"`
-	unknownFields       protoimpl.UnknownFields
-	sizeCache           protoimpl.SizeCache
+	// Set when allow_partial_failure is requested: the status of each envelope, in request
+	// order. The originator envelope of an envelope that failed is empty.
+	EnvelopeStatuses []*PublishClientEnvelopesResponse_EnvelopeStatus `protobuf:"bytes,2,rep,name=envelope_statuses,json=envelopeStatuses,proto3" json:"envelope_statuses,omitempty"`
+	// Set when the gateway publish outbox is enabled: the receipt ID of each envelope, in
+	// request order, to poll with GatewayApi.GetPublishReceipts.
+	ReceiptIds    []string `protobuf:"bytes,3,rep,name=receipt_ids,json=receiptIds,proto3" json:"receipt_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PublishClientEnvelopesResponse) Reset() {
@@ -112,6 +137,20 @@ func (x *PublishClientEnvelopesResponse) GetOriginatorEnvelopes() []*envelopes.O
 	return nil
 }
 
+func (x *PublishClientEnvelopesResponse) GetEnvelopeStatuses() []*PublishClientEnvelopesResponse_EnvelopeStatus {
+	if x != nil {
+		return x.EnvelopeStatuses
+	}
+	return nil
+}
+
+func (x *PublishClientEnvelopesResponse) GetReceiptIds() []string {
+	if x != nil {
+		return x.ReceiptIds
+	}
+	return nil
+}
+
 type GetNodesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -192,15 +231,76 @@ func (x *GetNodesResponse) GetNodes() map[uint32]string {
 	return nil
 }
 
+// The outcome of publishing one envelope, as a gRPC status code and message
+type PublishClientEnvelopesResponse_EnvelopeStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          uint32                 `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishClientEnvelopesResponse_EnvelopeStatus) Reset() {
+	*x = PublishClientEnvelopesResponse_EnvelopeStatus{}
+	mi := &file_xmtpv4_payer_api_payer_api_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishClientEnvelopesResponse_EnvelopeStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishClientEnvelopesResponse_EnvelopeStatus) ProtoMessage() {}
+
+func (x *PublishClientEnvelopesResponse_EnvelopeStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_xmtpv4_payer_api_payer_api_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishClientEnvelopesResponse_EnvelopeStatus.ProtoReflect.Descriptor instead.
+func (*PublishClientEnvelopesResponse_EnvelopeStatus) Descriptor() ([]byte, []int) {
+	return file_xmtpv4_payer_api_payer_api_proto_rawDescGZIP(), []int{1, 0}
+}
+
+func (x *PublishClientEnvelopesResponse_EnvelopeStatus) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *PublishClientEnvelopesResponse_EnvelopeStatus) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
 var File_xmtpv4_payer_api_payer_api_proto protoreflect.FileDescriptor
 
 const file_xmtpv4_payer_api_payer_api_proto_rawDesc = "" +
 	"\n" +
-	" xmtpv4/payer_api/payer_api.proto\x12\x15xmtp.xmtpv4.payer_api\x1a xmtpv4/envelopes/envelopes.proto\"d\n" +
+	" xmtpv4/payer_api/payer_api.proto\x12\x15xmtp.xmtpv4.payer_api\x1a xmtpv4/envelopes/envelopes.proto\"\xae\x01\n" +
 	"\x1dPublishClientEnvelopesRequest\x12C\n" +
-	"\tenvelopes\x18\x01 \x03(\v2%.xmtp.xmtpv4.envelopes.ClientEnvelopeR\tenvelopes\"~\n" +
+	"\tenvelopes\x18\x01 \x03(\v2%.xmtp.xmtpv4.envelopes.ClientEnvelopeR\tenvelopes\x122\n" +
+	"\x15allow_partial_failure\x18\x02 \x01(\bR\x13allowPartialFailure\x12\x14\n" +
+	"\x05async\x18\x03 \x01(\bR\x05async\"\xd2\x02\n" +
 	"\x1ePublishClientEnvelopesResponse\x12\\\n" +
-	"\x14originator_envelopes\x18\x01 \x03(\v2).xmtp.xmtpv4.envelopes.OriginatorEnvelopeR\x13originatorEnvelopes\"\x11\n" +
+	"\x14originator_envelopes\x18\x01 \x03(\v2).xmtp.xmtpv4.envelopes.OriginatorEnvelopeR\x13originatorEnvelopes\x12q\n" +
+	"\x11envelope_statuses\x18\x02 \x03(\v2D.xmtp.xmtpv4.payer_api.PublishClientEnvelopesResponse.EnvelopeStatusR\x10envelopeStatuses\x12\x1f\n" +
+	"\vreceipt_ids\x18\x03 \x03(\tR\n" +
+	"receiptIds\x1a>\n" +
+	"\x0eEnvelopeStatus\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\rR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x11\n" +
 	"\x0fGetNodesRequest\"\x96\x01\n" +
 	"\x10GetNodesResponse\x12H\n" +
 	"\x05nodes\x18\x01 \x03(\v22.xmtp.xmtpv4.payer_api.GetNodesResponse.NodesEntryR\x05nodes\x1a8\n" +
@@ -225,29 +325,31 @@ func file_xmtpv4_payer_api_payer_api_proto_rawDescGZIP() []byte {
 	return file_xmtpv4_payer_api_payer_api_proto_rawDescData
 }
 
-var file_xmtpv4_payer_api_payer_api_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_xmtpv4_payer_api_payer_api_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_xmtpv4_payer_api_payer_api_proto_goTypes = []any{
-	(*PublishClientEnvelopesRequest)(nil),  // 0: xmtp.xmtpv4.payer_api.PublishClientEnvelopesRequest
-	(*PublishClientEnvelopesResponse)(nil), // 1: xmtp.xmtpv4.payer_api.PublishClientEnvelopesResponse
-	(*GetNodesRequest)(nil),                // 2: xmtp.xmtpv4.payer_api.GetNodesRequest
-	(*GetNodesResponse)(nil),               // 3: xmtp.xmtpv4.payer_api.GetNodesResponse
-	nil,                                    // 4: xmtp.xmtpv4.payer_api.GetNodesResponse.NodesEntry
-	(*envelopes.ClientEnvelope)(nil),       // 5: xmtp.xmtpv4.envelopes.ClientEnvelope
-	(*envelopes.OriginatorEnvelope)(nil),   // 6: xmtp.xmtpv4.envelopes.OriginatorEnvelope
+	(*PublishClientEnvelopesRequest)(nil),                 // 0: xmtp.xmtpv4.payer_api.PublishClientEnvelopesRequest
+	(*PublishClientEnvelopesResponse)(nil),                // 1: xmtp.xmtpv4.payer_api.PublishClientEnvelopesResponse
+	(*GetNodesRequest)(nil),                               // 2: xmtp.xmtpv4.payer_api.GetNodesRequest
+	(*GetNodesResponse)(nil),                              // 3: xmtp.xmtpv4.payer_api.GetNodesResponse
+	(*PublishClientEnvelopesResponse_EnvelopeStatus)(nil), // 4: xmtp.xmtpv4.payer_api.PublishClientEnvelopesResponse.EnvelopeStatus
+	nil,                                  // 5: xmtp.xmtpv4.payer_api.GetNodesResponse.NodesEntry
+	(*envelopes.ClientEnvelope)(nil),     // 6: xmtp.xmtpv4.envelopes.ClientEnvelope
+	(*envelopes.OriginatorEnvelope)(nil), // 7: xmtp.xmtpv4.envelopes.OriginatorEnvelope
 }
 var file_xmtpv4_payer_api_payer_api_proto_depIdxs = []int32{
-	5, // 0: xmtp.xmtpv4.payer_api.PublishClientEnvelopesRequest.envelopes:type_name -> xmtp.xmtpv4.envelopes.ClientEnvelope
-	6, // 1: xmtp.xmtpv4.payer_api.PublishClientEnvelopesResponse.originator_envelopes:type_name -> xmtp.xmtpv4.envelopes.OriginatorEnvelope
-	4, // 2: xmtp.xmtpv4.payer_api.GetNodesResponse.nodes:type_name -> xmtp.xmtpv4.payer_api.GetNodesResponse.NodesEntry
-	0, // 3: xmtp.xmtpv4.payer_api.PayerApi.PublishClientEnvelopes:input_type -> xmtp.xmtpv4.payer_api.PublishClientEnvelopesRequest
-	2, // 4: xmtp.xmtpv4.payer_api.PayerApi.GetNodes:input_type -> xmtp.xmtpv4.payer_api.GetNodesRequest
-	1, // 5: xmtp.xmtpv4.payer_api.PayerApi.PublishClientEnvelopes:output_type -> xmtp.xmtpv4.payer_api.PublishClientEnvelopesResponse
-	3, // 6: xmtp.xmtpv4.payer_api.PayerApi.GetNodes:output_type -> xmtp.xmtpv4.payer_api.GetNodesResponse
-	5, // [5:7] is the sub-list for method output_type
-	3, // [3:5] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	6, // 0: xmtp.xmtpv4.payer_api.PublishClientEnvelopesRequest.envelopes:type_name -> xmtp.xmtpv4.envelopes.ClientEnvelope
+	7, // 1: xmtp.xmtpv4.payer_api.PublishClientEnvelopesResponse.originator_envelopes:type_name -> xmtp.xmtpv4.envelopes.OriginatorEnvelope
+	4, // 2: xmtp.xmtpv4.payer_api.PublishClientEnvelopesResponse.envelope_statuses:type_name -> xmtp.xmtpv4.payer_api.PublishClientEnvelopesResponse.EnvelopeStatus
+	5, // 3: xmtp.xmtpv4.payer_api.GetNodesResponse.nodes:type_name -> xmtp.xmtpv4.payer_api.GetNodesResponse.NodesEntry
+	0, // 4: xmtp.xmtpv4.payer_api.PayerApi.PublishClientEnvelopes:input_type -> xmtp.xmtpv4.payer_api.PublishClientEnvelopesRequest
+	2, // 5: xmtp.xmtpv4.payer_api.PayerApi.GetNodes:input_type -> xmtp.xmtpv4.payer_api.GetNodesRequest
+	1, // 6: xmtp.xmtpv4.payer_api.PayerApi.PublishClientEnvelopes:output_type -> xmtp.xmtpv4.payer_api.PublishClientEnvelopesResponse
+	3, // 7: xmtp.xmtpv4.payer_api.PayerApi.GetNodes:output_type -> xmtp.xmtpv4.payer_api.GetNodesResponse
+	6, // [6:8] is the sub-list for method output_type
+	4, // [4:6] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_xmtpv4_payer_api_payer_api_proto_init() }
@@ -261,7 +363,7 @@ func file_xmtpv4_payer_api_payer_api_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_xmtpv4_payer_api_payer_api_proto_rawDesc), len(file_xmtpv4_payer_api_payer_api_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
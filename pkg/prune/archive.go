@@ -0,0 +1,226 @@
+package prune
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/xmtp/xmtpd/pkg/prune/archive"
+	"github.com/xmtp/xmtpd/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// archivePartitionPartBytes bounds the envelope bytes of each archive a partition is split into.
+const archivePartitionPartBytes = 64 << 20
+
+// pruneBatch deletes a single batch of expired envelopes from the table. When an archive
+// store is configured, the envelopes are archived before they are deleted. If the deletion
+// fails they are archived again with the next batch, which the import skips.
+func (e *Executor) pruneBatch(tableName string, table deletableTable) (int64, int64, error) {
+	if e.archiveStore == nil {
+		var rows, bytes int64
+		err := e.writerDB.QueryRowContext(
+			e.ctx,
+			constructVariableMetaTableQuery(
+				tableName,
+				e.config.BatchSize,
+				table.ceiling,
+				e.config.RetentionOverrides,
+			),
+		).Scan(&rows, &bytes)
+		return rows, bytes, err
+	}
+
+	records, err := queryRecords(e.writerDB.QueryContext(
+		e.ctx,
+		constructArchivingMetaTableQuery(
+			tableName,
+			e.config.BatchSize,
+			table.ceiling,
+			e.config.RetentionOverrides,
+		),
+	))
+	if err != nil || len(records) == 0 {
+		return 0, 0, err
+	}
+
+	var (
+		sequenceIDs = make([]int64, 0, len(records))
+		archived    = make([]*archive.Record, 0, len(records))
+	)
+	for _, record := range records {
+		sequenceIDs = append(sequenceIDs, int64(record.OriginatorSequenceID))
+
+		// An envelope without a blob cannot be restored, so it is reported instead.
+		if record.OriginatorEnvelope == nil {
+			continue
+		}
+		archived = append(archived, record)
+	}
+	e.warnMissingBlobs(table.originatorID, int64(len(records)-len(archived)), tableName)
+
+	if err := e.archiveRecords(table.originatorID, archived); err != nil {
+		return 0, 0, err
+	}
+
+	var rows, bytes int64
+	err = e.writerDB.QueryRowContext(
+		e.ctx,
+		constructDeleteArchivedQuery(tableName),
+		pq.Array(sequenceIDs),
+	).Scan(&rows, &bytes)
+	return rows, bytes, err
+}
+
+// archivePartition archives any envelopes left in a partition pair before it is dropped. The
+// rows are streamed into archives of at most archivePartitionPartBytes of envelopes each, so
+// that a partition is never held in memory whole.
+func (e *Executor) archivePartition(originatorID uint32, metaTable string, blobTable string) error {
+	if e.archiveStore == nil {
+		return nil
+	}
+
+	rows, err := e.writerDB.QueryContext(
+		e.ctx,
+		constructPartitionExportQuery(metaTable, blobTable),
+	)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var (
+		part      []*archive.Record
+		partBytes int
+		missing   int64
+	)
+	for rows.Next() {
+		record, err := scanRecord(rows)
+		if err != nil {
+			return err
+		}
+
+		// An envelope without a blob cannot be restored, so it is reported instead.
+		if record.OriginatorEnvelope == nil {
+			missing++
+			continue
+		}
+
+		part = append(part, record)
+		partBytes += len(record.Topic) + len(record.OriginatorEnvelope)
+		if partBytes >= archivePartitionPartBytes {
+			if err := e.archiveRecords(originatorID, part); err != nil {
+				return err
+			}
+			part, partBytes = nil, 0
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	e.warnMissingBlobs(originatorID, missing, metaTable)
+
+	return e.archiveRecords(originatorID, part)
+}
+
+// warnMissingBlobs reports envelopes of the table that are pruned without being archived, as
+// they have no blob.
+func (e *Executor) warnMissingBlobs(originatorID uint32, missing int64, table string) {
+	if missing == 0 {
+		return
+	}
+
+	e.logger.Warn(
+		"envelopes without a blob are pruned without being archived",
+		utils.OriginatorIDField(originatorID),
+		utils.CountField(missing),
+		zap.String("table", table),
+	)
+}
+
+func (e *Executor) archiveRecords(originatorID uint32, records []*archive.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	data, err := archive.Encode(records)
+	if err != nil {
+		return fmt.Errorf("encode archive: %w", err)
+	}
+
+	name := archiveName(originatorID, records, time.Now())
+	if err := e.archiveStore.Put(e.ctx, name, data); err != nil {
+		return fmt.Errorf("store archive %s: %w", name, err)
+	}
+
+	e.logger.Info(
+		"archived envelopes",
+		utils.OriginatorIDField(originatorID),
+		utils.CountField(int64(len(records))),
+		zap.String("archive", name),
+	)
+
+	return nil
+}
+
+// archiveName returns a unique name for an archive of the given records, which must be ordered
+// by sequence ID. Names sort by originator and sequence ID.
+func archiveName(originatorID uint32, records []*archive.Record, now time.Time) string {
+	return fmt.Sprintf(
+		"o%d/%020d-%020d-%d.xarc",
+		originatorID,
+		records[0].OriginatorSequenceID,
+		records[len(records)-1].OriginatorSequenceID,
+		now.UnixNano(),
+	)
+}
+
+func queryRecords(rows *sql.Rows, err error) ([]*archive.Record, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var records []*archive.Record
+	for rows.Next() {
+		record, err := scanRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+func scanRecord(rows *sql.Rows) (*archive.Record, error) {
+	var (
+		originatorNodeID     int32
+		originatorSequenceID int64
+		record               archive.Record
+	)
+	if err := rows.Scan(
+		&originatorNodeID,
+		&originatorSequenceID,
+		&record.GatewayTime,
+		&record.Expiry,
+		&record.Topic,
+		&record.OriginatorEnvelope,
+	); err != nil {
+		return nil, err
+	}
+
+	record.OriginatorNodeID = uint32(originatorNodeID)
+	record.OriginatorSequenceID = uint64(originatorSequenceID)
+	return &record, nil
+}
@@ -0,0 +1,138 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const tempFilePrefix = ".tmp-"
+
+// FilesystemStore stores archives as files below a root directory.
+type FilesystemStore struct {
+	root string
+}
+
+var _ Store = (*FilesystemStore)(nil)
+
+func NewFilesystemStore(root string) (*FilesystemStore, error) {
+	if root == "" {
+		return nil, errors.New("archive directory is required")
+	}
+
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("create archive directory: %w", err)
+	}
+
+	return &FilesystemStore{root: root}, nil
+}
+
+// Put writes the archive to a temporary file and links it into place, so that
+// readers never observe a partially written archive and existing archives are
+// never replaced.
+func (s *FilesystemStore) Put(_ context.Context, name string, data []byte) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, tempFilePrefix+"*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Link(tmp.Name(), path); err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return fmt.Errorf("%w: %s", ErrArchiveExists, name)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *FilesystemStore) Get(_ context.Context, name string) (io.ReadCloser, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrArchiveNotFound, name)
+		}
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (s *FilesystemStore) List(_ context.Context, prefix string) ([]string, error) {
+	var names []string
+
+	err := filepath.WalkDir(s.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || strings.HasPrefix(d.Name(), tempFilePrefix) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.root, path)
+		if err != nil {
+			return err
+		}
+
+		name := filepath.ToSlash(rel)
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *FilesystemStore) path(name string) (string, error) {
+	local := filepath.FromSlash(name)
+	if !filepath.IsLocal(local) {
+		return "", fmt.Errorf("invalid archive name %q", name)
+	}
+
+	return filepath.Join(s.root, local), nil
+}
@@ -0,0 +1,51 @@
+package archive_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/prune/archive"
+)
+
+func TestFilesystemStore(t *testing.T) {
+	store, err := archive.NewFilesystemStore(t.TempDir())
+	require.NoError(t, err)
+
+	names := []string{"o100/1-2.xarc", "o100/3-4.xarc", "o200/1-1.xarc"}
+	for _, name := range names {
+		require.NoError(t, store.Put(t.Context(), name, []byte(name)))
+	}
+
+	err = store.Put(t.Context(), names[0], []byte("overwrite"))
+	require.ErrorIs(t, err, archive.ErrArchiveExists)
+
+	body, err := store.Get(t.Context(), names[0])
+	require.NoError(t, err)
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+	require.Equal(t, []byte(names[0]), data)
+
+	_, err = store.Get(t.Context(), "o100/missing.xarc")
+	require.ErrorIs(t, err, archive.ErrArchiveNotFound)
+
+	listed, err := store.List(t.Context(), "o100/")
+	require.NoError(t, err)
+	require.Equal(t, names[:2], listed)
+
+	listed, err = store.List(t.Context(), "")
+	require.NoError(t, err)
+	require.Equal(t, names, listed)
+}
+
+func TestFilesystemStoreRejectsEscapingNames(t *testing.T) {
+	store, err := archive.NewFilesystemStore(t.TempDir())
+	require.NoError(t, err)
+
+	for _, name := range []string{"../escape.xarc", "/abs.xarc", "o100/../../escape.xarc"} {
+		require.Error(t, store.Put(t.Context(), name, []byte("data")), name)
+		_, err := store.Get(t.Context(), name)
+		require.Error(t, err, name)
+	}
+}
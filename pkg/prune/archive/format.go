@@ -0,0 +1,245 @@
+// Package archive implements cold storage for envelopes removed by the pruner.
+//
+// An archive is a header followed by a sequence of records:
+//
+//	header: magic "XMTPARC" | format version (1 byte)
+//	record: body length (uint32) | body | CRC-32C of the body (uint32)
+//	body:   originator node ID (uvarint) | originator sequence ID (uvarint) |
+//	        gateway time in unix nanoseconds (varint) | expiry in unix seconds (varint) |
+//	        topic length (uvarint) | topic | envelope length (uvarint) | envelope
+//
+// Integers in the record framing are big endian. Every record carries its own
+// checksum, so records can be appended to an archive without rewriting it, and
+// a corrupted or truncated archive is detected when it is read back.
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"time"
+)
+
+const (
+	formatVersion = 1
+	// maxRecordSize bounds the allocation made for a single record when reading.
+	maxRecordSize = 64 << 20
+)
+
+var (
+	magic = []byte("XMTPARC")
+
+	crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+	ErrInvalidHeader    = errors.New("invalid archive header")
+	ErrChecksumMismatch = errors.New("archive record checksum mismatch")
+	ErrRecordTooLarge   = errors.New("archive record too large")
+)
+
+// Record is a single archived envelope, along with the metadata needed to
+// re-import it into a node.
+type Record struct {
+	OriginatorNodeID     uint32
+	OriginatorSequenceID uint64
+	GatewayTime          time.Time
+	Expiry               int64
+	Topic                []byte
+	OriginatorEnvelope   []byte
+}
+
+// Writer appends records to an archive.
+type Writer struct {
+	w   io.Writer
+	buf []byte
+}
+
+// NewWriter writes the archive header to w and returns a Writer for the records.
+func NewWriter(w io.Writer) (*Writer, error) {
+	header := append(append([]byte{}, magic...), formatVersion)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &Writer{w: w}, nil
+}
+
+func (w *Writer) Write(record *Record) error {
+	body := w.buf[:0]
+	body = binary.AppendUvarint(body, uint64(record.OriginatorNodeID))
+	body = binary.AppendUvarint(body, record.OriginatorSequenceID)
+	body = binary.AppendVarint(body, record.GatewayTime.UnixNano())
+	body = binary.AppendVarint(body, record.Expiry)
+	body = binary.AppendUvarint(body, uint64(len(record.Topic)))
+	body = append(body, record.Topic...)
+	body = binary.AppendUvarint(body, uint64(len(record.OriginatorEnvelope)))
+	body = append(body, record.OriginatorEnvelope...)
+	w.buf = body
+
+	if len(body) > maxRecordSize {
+		return ErrRecordTooLarge
+	}
+
+	frame := make([]byte, 0, len(body)+8)
+	frame = binary.BigEndian.AppendUint32(frame, uint32(len(body)))
+	frame = append(frame, body...)
+	frame = binary.BigEndian.AppendUint32(frame, crc32.Checksum(body, crcTable))
+
+	_, err := w.w.Write(frame)
+	return err
+}
+
+// Encode returns a complete archive containing the given records.
+func Encode(records []*Record) ([]byte, error) {
+	var buf bytes.Buffer
+
+	w, err := NewWriter(&buf)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Reader reads records from an archive, verifying their checksums.
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader reads and validates the archive header from r.
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+
+	header := make([]byte, len(magic)+1)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidHeader, err)
+	}
+
+	if !bytes.Equal(header[:len(magic)], magic) {
+		return nil, ErrInvalidHeader
+	}
+
+	if version := header[len(magic)]; version != formatVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidHeader, version)
+	}
+
+	return &Reader{r: br}, nil
+}
+
+// Next returns the next record in the archive, or io.EOF once all records have been read.
+// A record cut short by the end of the archive returns io.ErrUnexpectedEOF.
+func (r *Reader) Next() (*Record, error) {
+	var lengthBytes [4]byte
+	if _, err := io.ReadFull(r.r, lengthBytes[:]); err != nil {
+		return nil, err
+	}
+
+	length := binary.BigEndian.Uint32(lengthBytes[:])
+	if length > maxRecordSize {
+		return nil, ErrRecordTooLarge
+	}
+
+	frame := make([]byte, length+4)
+	if _, err := io.ReadFull(r.r, frame); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	body := frame[:length]
+	if crc32.Checksum(body, crcTable) != binary.BigEndian.Uint32(frame[length:]) {
+		return nil, ErrChecksumMismatch
+	}
+
+	return decodeRecord(body)
+}
+
+func decodeRecord(body []byte) (*Record, error) {
+	d := decoder{buf: body}
+
+	originatorNodeID := d.uvarint()
+	if originatorNodeID > math.MaxUint32 {
+		return nil, errors.New("archive record originator node ID out of range")
+	}
+
+	record := &Record{
+		OriginatorNodeID:     uint32(originatorNodeID),
+		OriginatorSequenceID: d.uvarint(),
+		GatewayTime:          time.Unix(0, d.varint()).UTC(),
+		Expiry:               d.varint(),
+	}
+	record.Topic = d.bytes()
+	record.OriginatorEnvelope = d.bytes()
+
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	if len(d.buf) != 0 {
+		return nil, errors.New("trailing bytes in archive record")
+	}
+
+	return record, nil
+}
+
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.err = errors.New("malformed archive record")
+		return 0
+	}
+
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Varint(d.buf)
+	if n <= 0 {
+		d.err = errors.New("malformed archive record")
+		return 0
+	}
+
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *decoder) bytes() []byte {
+	length := d.uvarint()
+	if d.err != nil {
+		return nil
+	}
+
+	if length > uint64(len(d.buf)) {
+		d.err = errors.New("malformed archive record")
+		return nil
+	}
+
+	out := d.buf[:length:length]
+	d.buf = d.buf[length:]
+	return out
+}
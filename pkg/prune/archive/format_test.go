@@ -0,0 +1,112 @@
+package archive_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/prune/archive"
+)
+
+func testRecords() []*archive.Record {
+	return []*archive.Record{
+		{
+			OriginatorNodeID:     100,
+			OriginatorSequenceID: 1,
+			GatewayTime:          time.Unix(1_700_000_000, 123).UTC(),
+			Expiry:               1_700_086_400,
+			Topic:                []byte("topic-1"),
+			OriginatorEnvelope:   []byte("envelope-1"),
+		},
+		{
+			OriginatorNodeID:     100,
+			OriginatorSequenceID: 2,
+			GatewayTime:          time.Unix(1_700_000_001, 0).UTC(),
+			Expiry:               0,
+			Topic:                []byte("topic-2"),
+			OriginatorEnvelope:   []byte{},
+		},
+	}
+}
+
+func readAll(t *testing.T, data []byte) ([]*archive.Record, error) {
+	reader, err := archive.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	var records []*archive.Record
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	records := testRecords()
+
+	data, err := archive.Encode(records)
+	require.NoError(t, err)
+
+	decoded, err := readAll(t, data)
+	require.NoError(t, err)
+	require.Len(t, decoded, len(records))
+
+	for i, record := range records {
+		require.Equal(t, record.OriginatorNodeID, decoded[i].OriginatorNodeID)
+		require.Equal(t, record.OriginatorSequenceID, decoded[i].OriginatorSequenceID)
+		require.True(t, record.GatewayTime.Equal(decoded[i].GatewayTime))
+		require.Equal(t, record.Expiry, decoded[i].Expiry)
+		require.Equal(t, record.Topic, decoded[i].Topic)
+		require.Equal(t, len(record.OriginatorEnvelope), len(decoded[i].OriginatorEnvelope))
+	}
+}
+
+func TestEmptyArchive(t *testing.T) {
+	data, err := archive.Encode(nil)
+	require.NoError(t, err)
+
+	decoded, err := readAll(t, data)
+	require.NoError(t, err)
+	require.Empty(t, decoded)
+}
+
+func TestChecksumMismatch(t *testing.T) {
+	data, err := archive.Encode(testRecords())
+	require.NoError(t, err)
+
+	// Flip a byte of the first record's envelope.
+	i := bytes.Index(data, []byte("envelope-1"))
+	require.Positive(t, i)
+	data[i] ^= 0xff
+
+	_, err = readAll(t, data)
+	require.ErrorIs(t, err, archive.ErrChecksumMismatch)
+}
+
+func TestTruncatedArchive(t *testing.T) {
+	data, err := archive.Encode(testRecords())
+	require.NoError(t, err)
+
+	decoded, err := readAll(t, data[:len(data)-3])
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	require.Len(t, decoded, 1)
+}
+
+func TestInvalidHeader(t *testing.T) {
+	_, err := archive.NewReader(bytes.NewReader([]byte("NOTANARCHIVE")))
+	require.ErrorIs(t, err, archive.ErrInvalidHeader)
+
+	data, err := archive.Encode(testRecords())
+	require.NoError(t, err)
+	data[len("XMTPARC")] = 0xff
+
+	_, err = archive.NewReader(bytes.NewReader(data))
+	require.ErrorIs(t, err, archive.ErrInvalidHeader)
+}
@@ -0,0 +1,78 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/xmtp/xmtpd/pkg/db"
+	"github.com/xmtp/xmtpd/pkg/db/queries"
+	"github.com/xmtp/xmtpd/pkg/utils"
+)
+
+// ImportResult summarizes the import of an archive.
+type ImportResult struct {
+	// Records is the number of records read from the archive.
+	Records int64
+	// Inserted is the number of envelopes that were not already stored by the node.
+	Inserted int64
+}
+
+// Import re-inserts every envelope of the archive into the gateway envelope tables.
+// Envelopes already stored by the node are skipped. Re-imported envelopes expire no
+// earlier than minExpiry, so that they are not pruned again straight away. Payer
+// usage is not re-attributed, as it was settled before the envelopes were pruned.
+func Import(
+	ctx context.Context,
+	querier *queries.Queries,
+	r io.Reader,
+	minExpiry time.Time,
+) (ImportResult, error) {
+	var result ImportResult
+
+	reader, err := NewReader(r)
+	if err != nil {
+		return result, err
+	}
+
+	for {
+		record, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return result, err
+		}
+
+		result.Records++
+
+		originatorNodeID, err := utils.Uint32ToInt32(record.OriginatorNodeID)
+		if err != nil {
+			return result, err
+		}
+
+		originatorSequenceID, err := utils.Uint64ToInt64(record.OriginatorSequenceID)
+		if err != nil {
+			return result, err
+		}
+
+		inserted, err := db.InsertGatewayEnvelopeWithChecksStandalone(
+			ctx,
+			querier,
+			queries.InsertGatewayEnvelopeV3Params{
+				OriginatorNodeID:     originatorNodeID,
+				OriginatorSequenceID: originatorSequenceID,
+				Topic:                record.Topic,
+				GatewayTime:          record.GatewayTime,
+				Expiry:               max(record.Expiry, minExpiry.Unix()),
+				OriginatorEnvelope:   record.OriginatorEnvelope,
+			},
+		)
+		if err != nil {
+			return result, err
+		}
+
+		result.Inserted += inserted.InsertedMetaRows
+	}
+}
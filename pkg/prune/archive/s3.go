@@ -0,0 +1,374 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultS3Region  = "us-east-1"
+	s3Service        = "s3"
+	sigV4Algorithm   = "AWS4-HMAC-SHA256"
+	amzDateFormat    = "20060102T150405Z"
+	amzShortFormat   = "20060102"
+	s3RequestTimeout = 5 * time.Minute
+)
+
+// S3Config configures an S3Store. Requests use path-style addressing, which is
+// supported by AWS S3 as well as S3-compatible stores such as MinIO.
+type S3Config struct {
+	// Endpoint is the base URL of the store, e.g. https://s3.us-east-1.amazonaws.com.
+	Endpoint        string
+	Bucket          string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// HTTPClient defaults to a client with a five minute timeout.
+	HTTPClient *http.Client
+}
+
+// S3Store stores archives as objects in an S3-compatible bucket, using
+// requests signed with AWS Signature Version 4.
+type S3Store struct {
+	endpoint *url.URL
+	bucket   string
+	region   string
+	keyID    string
+	secret   string
+	client   *http.Client
+	now      func() time.Time
+}
+
+var _ Store = (*S3Store)(nil)
+
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	if cfg.Endpoint == "" {
+		return nil, errors.New("s3 endpoint is required")
+	}
+
+	if cfg.Bucket == "" {
+		return nil, errors.New("s3 bucket is required")
+	}
+
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid s3 endpoint: %w", err)
+	}
+
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("invalid s3 endpoint scheme %q", endpoint.Scheme)
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = defaultS3Region
+	}
+
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: s3RequestTimeout}
+	}
+
+	return &S3Store{
+		endpoint: endpoint,
+		bucket:   cfg.Bucket,
+		region:   region,
+		keyID:    cfg.AccessKeyID,
+		secret:   cfg.SecretAccessKey,
+		client:   client,
+		now:      time.Now,
+	}, nil
+}
+
+// Put uploads the archive with If-None-Match: *, so that an existing object is
+// never overwritten.
+func (s *S3Store) Put(ctx context.Context, name string, data []byte) error {
+	req, err := s.newRequest(ctx, http.MethodPut, name, nil, data)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("If-None-Match", "*")
+	s.sign(req, data)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusPreconditionFailed, http.StatusConflict:
+		return fmt.Errorf("%w: %s", ErrArchiveExists, name)
+	default:
+		return s3Error(resp)
+	}
+}
+
+func (s *S3Store) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, name, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, nil)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s", ErrArchiveNotFound, name)
+	default:
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		return nil, s3Error(resp)
+	}
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (s *S3Store) List(ctx context.Context, prefix string) ([]string, error) {
+	var (
+		names []string
+		token string
+	)
+
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", prefix)
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		result, err := s.listPage(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, content := range result.Contents {
+			names = append(names, content.Key)
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *S3Store) listPage(ctx context.Context, query url.Values) (*listBucketResult, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, "", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, nil)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, s3Error(resp)
+	}
+
+	var result listBucketResult
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode list objects response: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (s *S3Store) newRequest(
+	ctx context.Context,
+	method string,
+	name string,
+	query url.Values,
+	body []byte,
+) (*http.Request, error) {
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.bucket
+	if name != "" {
+		u.Path += "/" + name
+	}
+	// Send the path exactly as it is signed.
+	u.RawPath = uriEncode(u.Path, false)
+	u.RawQuery = ""
+	if query != nil {
+		u.RawQuery = canonicalQuery(query)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+
+	return req, nil
+}
+
+// sign adds the AWS Signature Version 4 headers to req.
+func (s *S3Store) sign(req *http.Request, body []byte) {
+	now := s.now().UTC()
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", now.Format(amzDateFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	req.Header.Set(
+		"Authorization",
+		signatureV4(req, payloadHash, now, s.region, s.keyID, s.secret),
+	)
+}
+
+// signatureV4 returns the Authorization header value for req. All headers set
+// on req, plus the host, are signed.
+func signatureV4(
+	req *http.Request,
+	payloadHash string,
+	now time.Time,
+	region string,
+	keyID string,
+	secret string,
+) string {
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join(
+		[]string{now.Format(amzShortFormat), region, s3Service, "aws4_request"},
+		"/",
+	)
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		now.Format(amzDateFormat),
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secret), now.Format(amzShortFormat))
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, s3Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	return fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm,
+		keyID,
+		scope,
+		signedHeaders,
+		signature,
+	)
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		values := append([]string{}, query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes every byte except the unreserved characters, as
+// required by Signature Version 4. Slashes are kept when encoding a path.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func s3Error(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("s3 request failed with status %d: %s", resp.StatusCode, body)
+}
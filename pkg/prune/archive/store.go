@@ -0,0 +1,51 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/xmtp/xmtpd/pkg/config"
+)
+
+const (
+	StoreFilesystem = "filesystem"
+	StoreS3         = "s3"
+)
+
+var (
+	ErrArchiveExists   = errors.New("archive already exists")
+	ErrArchiveNotFound = errors.New("archive not found")
+)
+
+// Store persists archives under slash-separated names.
+// Archives are append-only: Put must fail with ErrArchiveExists rather than
+// overwrite an existing archive.
+type Store interface {
+	Put(ctx context.Context, name string, data []byte) error
+	Get(ctx context.Context, name string) (io.ReadCloser, error)
+	// List returns the names of all archives starting with prefix, in lexical order.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// NewStore builds the store configured in options.
+// It returns a nil Store when archiving is disabled.
+func NewStore(options config.ArchiveOptions) (Store, error) {
+	switch options.Store {
+	case "":
+		return nil, nil
+	case StoreFilesystem:
+		return NewFilesystemStore(options.Dir)
+	case StoreS3:
+		return NewS3Store(S3Config{
+			Endpoint:        options.S3Endpoint,
+			Bucket:          options.S3Bucket,
+			Region:          options.S3Region,
+			AccessKeyID:     options.S3AccessKeyID,
+			SecretAccessKey: options.S3SecretAccessKey,
+		})
+	default:
+		return nil, fmt.Errorf("unknown archive store %q", options.Store)
+	}
+}
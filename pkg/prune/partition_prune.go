@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/xmtp/xmtpd/pkg/constants"
+	"github.com/xmtp/xmtpd/pkg/metrics"
 
 	"github.com/xmtp/xmtpd/pkg/utils"
 
@@ -60,6 +61,20 @@ func (e *Executor) DropPrunablePartitions() error {
 
 		blobName := constructBlobName(droppableMetaRow)
 
+		if err := e.archivePartition(
+			uint32(droppableMetaRow.OriginatorNodeID),
+			droppableMetaRow.Tablename,
+			blobName,
+		); err != nil {
+			e.logger.Error("could not archive partition pair", zap.Error(err))
+			return fmt.Errorf(
+				"archive partition pair (%s, %s): %w",
+				droppableMetaRow.Tablename,
+				blobName,
+				err,
+			)
+		}
+
 		if _, err := e.writerDB.ExecContext(
 			ctx,
 			constructDropQuery(droppableMetaRow.Tablename, blobName),
@@ -73,6 +88,8 @@ func (e *Executor) DropPrunablePartitions() error {
 			)
 		}
 
+		metrics.EmitPrunePartitionDropped()
+
 		e.logger.Info(
 			"dropped partition pair",
 			zap.String("blob_table", blobName),
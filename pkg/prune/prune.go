@@ -6,14 +6,26 @@ import (
 	"database/sql"
 
 	"github.com/xmtp/xmtpd/pkg/config"
+	"github.com/xmtp/xmtpd/pkg/prune/archive"
 	"go.uber.org/zap"
 )
 
 type Executor struct {
-	ctx      context.Context
-	logger   *zap.Logger
-	writerDB *sql.DB
-	config   *config.PruneConfig
+	ctx          context.Context
+	logger       *zap.Logger
+	writerDB     *sql.DB
+	config       *config.PruneConfig
+	archiveStore archive.Store
+}
+
+type ExecutorOption func(*Executor)
+
+// WithArchiveStore archives envelopes to the store before they are pruned.
+// A nil store disables archiving.
+func WithArchiveStore(store archive.Store) ExecutorOption {
+	return func(e *Executor) {
+		e.archiveStore = store
+	}
 }
 
 func NewPruneExecutor(
@@ -21,6 +33,7 @@ func NewPruneExecutor(
 	logger *zap.Logger,
 	writerDB *sql.DB,
 	config *config.PruneConfig,
+	opts ...ExecutorOption,
 ) *Executor {
 	if config.BatchSize <= 0 {
 		logger.Panic("batch size must be greater than zero")
@@ -30,12 +43,18 @@ func NewPruneExecutor(
 		logger.Panic("max cycles must be greater than zero")
 	}
 
-	return &Executor{
+	executor := &Executor{
 		ctx:      ctx,
 		logger:   logger,
 		writerDB: writerDB,
 		config:   config,
 	}
+
+	for _, opt := range opts {
+		opt(executor)
+	}
+
+	return executor
 }
 
 func (e *Executor) Run() error {
@@ -49,5 +68,10 @@ func (e *Executor) Run() error {
 		return err
 	}
 
-	return nil
+	err = e.BackfillUsageHistory()
+	if err != nil {
+		return err
+	}
+
+	return e.PruneUsageHistory()
 }
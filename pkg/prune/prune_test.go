@@ -3,6 +3,8 @@ package prune_test
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"io"
 	"testing"
 	"time"
 
@@ -17,6 +19,8 @@ import (
 	"github.com/xmtp/xmtpd/pkg/config"
 	"github.com/xmtp/xmtpd/pkg/db/queries"
 	"github.com/xmtp/xmtpd/pkg/prune"
+	"github.com/xmtp/xmtpd/pkg/prune/archive"
+	"github.com/xmtp/xmtpd/pkg/topic"
 )
 
 const (
@@ -663,3 +667,162 @@ func TestExecutor_PrunesExpiredMetaAndBlobs(t *testing.T) {
 	require.NoError(t, err)
 	assert.EqualValues(t, 0, total, "Only non-expired envelopes should remain")
 }
+
+func TestExecutor_RetentionOverride(t *testing.T) {
+	ctx := context.Background()
+	dbs := testutils.NewDBs(t, ctx, 1)
+	db := dbs[0]
+
+	keyPackageTopic := topic.NewTopic(topic.TopicKindKeyPackagesV1, []byte("inbox")).Bytes()
+	groupTopic := topic.NewTopic(topic.TopicKindGroupMessagesV1, []byte("group")).Bytes()
+
+	// Neither envelope has expired, but both were received two hours ago.
+	for i, envelopeTopic := range [][]byte{keyPackageTopic, groupTopic} {
+		testutils.InsertGatewayEnvelopes(t, db, []queries.InsertGatewayEnvelopeV3Params{{
+			OriginatorNodeID:     DefaultOriginatorID,
+			OriginatorSequenceID: int64(i + 1),
+			Topic:                envelopeTopic,
+			OriginatorEnvelope:   []byte("payload"),
+			GatewayTime:          time.Now().Add(-2 * time.Hour),
+			Expiry:               time.Now().Add(1 * time.Hour).Unix(),
+		}})
+	}
+	createPrunableReport(t, ctx, db, DefaultOriginatorID, DefaultSubmittedCnt)
+
+	exec := makeTestExecutor(t, ctx, db, &config.PruneConfig{
+		MaxCycles: 5,
+		RetentionOverrides: config.TopicKindRetention{
+			topic.TopicKindKeyPackagesV1: time.Hour,
+		},
+	})
+	require.NoError(t, exec.Run())
+
+	remainingIDs := getRemainingSequenceIds(t, ctx, db)
+	assert.Equal(
+		t,
+		[]int64{2},
+		remainingIDs,
+		"Only envelopes past their topic kind retention should be pruned",
+	)
+}
+
+func TestExecutor_ArchivesPrunedEnvelopes(t *testing.T) {
+	ctx := context.Background()
+	dbs := testutils.NewDBs(t, ctx, 1)
+	db := dbs[0]
+	q := queries.New(db)
+
+	setupTestData(
+		t,
+		ctx,
+		db,
+		DefaultOriginatorID,
+		DefaultExpiredCnt,
+		DefaultValidCnt,
+		DefaultSubmittedCnt,
+	)
+
+	store, err := archive.NewFilesystemStore(t.TempDir())
+	require.NoError(t, err)
+
+	exec := prune.NewPruneExecutor(
+		ctx,
+		testutils.NewLog(t),
+		db,
+		&config.PruneConfig{MaxCycles: 5, BatchSize: 1000},
+		prune.WithArchiveStore(store),
+	)
+	require.NoError(t, exec.Run())
+
+	cnt, err := q.CountExpiredEnvelopes(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, cnt)
+
+	names, err := store.List(ctx, "")
+	require.NoError(t, err)
+	require.NotEmpty(t, names)
+
+	// Re-importing every archive restores the pruned envelopes.
+	var imported int64
+	for _, name := range names {
+		body, err := store.Get(ctx, name)
+		require.NoError(t, err)
+
+		result, err := archive.Import(ctx, q, body, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, body.Close())
+
+		imported += result.Inserted
+	}
+	assert.EqualValues(t, DefaultExpiredCnt, imported)
+
+	remainingIDs := getRemainingSequenceIds(t, ctx, db)
+	assert.Len(t, remainingIDs, DefaultExpiredCnt+DefaultValidCnt)
+
+	cnt, err = q.CountExpiredEnvelopes(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, cnt, "Re-imported envelopes should not be expired")
+}
+
+func TestExecutor_ArchivingPrunesEnvelopesWithoutBlob(t *testing.T) {
+	ctx := context.Background()
+	dbs := testutils.NewDBs(t, ctx, 1)
+	db := dbs[0]
+	q := queries.New(db)
+
+	setupTestData(
+		t,
+		ctx,
+		db,
+		DefaultOriginatorID,
+		DefaultExpiredCnt,
+		DefaultValidCnt,
+		DefaultSubmittedCnt,
+	)
+
+	_, err := db.ExecContext(
+		ctx,
+		`DELETE FROM gateway_envelopes_blob
+		 WHERE originator_node_id = $1 AND originator_sequence_id = 1`,
+		DefaultOriginatorID,
+	)
+	require.NoError(t, err)
+
+	store, err := archive.NewFilesystemStore(t.TempDir())
+	require.NoError(t, err)
+
+	exec := prune.NewPruneExecutor(
+		ctx,
+		testutils.NewLog(t),
+		db,
+		&config.PruneConfig{MaxCycles: 5, BatchSize: 1000},
+		prune.WithArchiveStore(store),
+	)
+	require.NoError(t, exec.Run())
+
+	cnt, err := q.CountExpiredEnvelopes(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, cnt, "The envelope without a blob should be pruned as well")
+
+	var archived int64
+	names, err := store.List(ctx, "")
+	require.NoError(t, err)
+	for _, name := range names {
+		body, err := store.Get(ctx, name)
+		require.NoError(t, err)
+
+		reader, err := archive.NewReader(body)
+		require.NoError(t, err)
+		for {
+			record, err := reader.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(t, err)
+			assert.NotEqualValues(t, 1, record.OriginatorSequenceID)
+			archived++
+		}
+		require.NoError(t, body.Close())
+	}
+	assert.EqualValues(t, DefaultExpiredCnt-1, archived)
+}
@@ -2,28 +2,211 @@ package prune
 
 import (
 	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/lib/pq"
+	"github.com/xmtp/xmtpd/pkg/config"
+	"github.com/xmtp/xmtpd/pkg/topic"
 )
 
+// deletedCountsQuery returns the number of rows deleted by the deleted CTE and the number of
+// envelope and topic bytes reclaimed. The blob rows are deleted by the foreign key cascade, but
+// are still visible to it since all parts of the statement share the same snapshot.
+const deletedCountsQuery = `
+SELECT COUNT(*)::bigint AS deleted_count,
+       COALESCE(
+         SUM(octet_length(d.topic) + COALESCE(octet_length(b.originator_envelope), 0)),
+         0
+       )::bigint AS deleted_bytes
+FROM deleted d
+LEFT JOIN gateway_envelopes_blob b
+  ON b.originator_node_id = d.originator_node_id
+ AND b.originator_sequence_id = d.originator_sequence_id;
+`
+
+// constructVariableMetaTableQuery deletes up to batchSize expired envelopes below maxSequenceId
+// and returns the number of deleted rows and the number of envelope and topic bytes reclaimed.
 func constructVariableMetaTableQuery(
 	tableName string,
 	batchSize int32,
 	maxSequenceId int64,
+	retention config.TopicKindRetention,
+) string {
+	return constructExpiredCTEs(tableName, "ctid", batchSize, maxSequenceId, retention) +
+		fmt.Sprintf(`,
+deleted AS (
+  DELETE FROM %s
+  WHERE ctid IN (SELECT ctid FROM expired)
+  RETURNING originator_node_id, originator_sequence_id, topic
+)`,
+			pq.QuoteIdentifier(tableName),
+		) + deletedCountsQuery
+}
+
+// constructArchivingMetaTableQuery selects the envelopes constructVariableMetaTableQuery would
+// delete, so that they can be archived before constructDeleteArchivedQuery deletes them.
+// Envelopes without a blob are selected with a NULL originator envelope.
+func constructArchivingMetaTableQuery(
+	tableName string,
+	batchSize int32,
+	maxSequenceId int64,
+	retention config.TopicKindRetention,
 ) string {
+	return constructExpiredCTEs(
+		tableName,
+		"originator_node_id, originator_sequence_id, gateway_time, expiry, topic",
+		batchSize,
+		maxSequenceId,
+		retention,
+	) + `
+SELECT e.originator_node_id,
+       e.originator_sequence_id,
+       e.gateway_time,
+       e.expiry,
+       e.topic,
+       b.originator_envelope
+FROM expired e
+LEFT JOIN gateway_envelopes_blob b
+  ON b.originator_node_id = e.originator_node_id
+ AND b.originator_sequence_id = e.originator_sequence_id
+ORDER BY e.originator_sequence_id;
+`
+}
+
+// constructDeleteArchivedQuery deletes the envelopes with the sequence IDs given as $1 once they
+// are archived, and returns the same counts as constructVariableMetaTableQuery.
+func constructDeleteArchivedQuery(tableName string) string {
 	return fmt.Sprintf(`
-WITH to_delete AS (
-  SELECT ctid
+WITH deleted AS (
+  DELETE FROM %s
+  WHERE originator_sequence_id = ANY($1::bigint[])
+  RETURNING originator_node_id, originator_sequence_id, topic
+)`,
+		pq.QuoteIdentifier(tableName),
+	) + deletedCountsQuery
+}
+
+// constructExpiredCTEs selects the columns of up to batchSize expired envelopes below
+// maxSequenceId into the expired CTE, soonest expired first. Each topic kind with a retention
+// override expires relative to its gateway time, every other envelope uses its own expiry.
+// Every rule is selected separately, so that each can be served by the topic or expiry index.
+func constructExpiredCTEs(
+	tableName string,
+	columns string,
+	batchSize int32,
+	maxSequenceId int64,
+	retention config.TopicKindRetention,
+) string {
+	kinds := make([]topic.TopicKind, 0, len(retention))
+	for kind := range retention {
+		kinds = append(kinds, kind)
+	}
+	slices.Sort(kinds)
+
+	var (
+		b          strings.Builder
+		rules      = make([]string, 0, len(kinds)+1)
+		overridden = make([]string, 0, len(kinds))
+	)
+	b.WriteString("\nWITH ")
+
+	for _, kind := range kinds {
+		seconds := int64(retention[kind] / time.Second)
+		rule := fmt.Sprintf("expired_kind_%d", kind)
+		fmt.Fprintf(&b, `%s AS (
+  SELECT %s, EXTRACT(EPOCH FROM gateway_time)::bigint + %d AS expires_at
   FROM %s
-  WHERE expiry < EXTRACT(EPOCH FROM now())::bigint
+  WHERE %s
+    AND gateway_time < to_timestamp(EXTRACT(EPOCH FROM now())::bigint - %d) AT TIME ZONE 'UTC'
     AND originator_sequence_id < %d
+  LIMIT %d
+  FOR UPDATE SKIP LOCKED
+),
+`,
+			rule,
+			columns,
+			seconds,
+			pq.QuoteIdentifier(tableName),
+			constructTopicKindPredicate(kind),
+			seconds,
+			maxSequenceId,
+			batchSize,
+		)
+		rules = append(rules, rule)
+		overridden = append(overridden, "("+constructTopicKindPredicate(kind)+")")
+	}
+
+	excluded := ""
+	if len(overridden) > 0 {
+		excluded = "\n    AND NOT (" + strings.Join(overridden, " OR ") + ")"
+	}
+	fmt.Fprintf(&b, `expired_default AS (
+  SELECT %s, expiry AS expires_at
+  FROM %s
+  WHERE expiry < EXTRACT(EPOCH FROM now())::bigint
+    AND originator_sequence_id < %d%s
   ORDER BY expiry
   LIMIT %d
   FOR UPDATE SKIP LOCKED
-)
-DELETE FROM %s
-WHERE ctid IN (SELECT ctid FROM to_delete);
-`, pq.QuoteIdentifier(tableName), maxSequenceId, batchSize, pq.QuoteIdentifier(tableName))
+),
+`,
+		columns,
+		pq.QuoteIdentifier(tableName),
+		maxSequenceId,
+		excluded,
+		batchSize,
+	)
+	rules = append(rules, "expired_default")
+
+	selects := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		selects = append(selects, "SELECT * FROM "+rule)
+	}
+	fmt.Fprintf(&b, `expired AS (
+  SELECT * FROM (%s) r
+  ORDER BY expires_at
+  LIMIT %d
+)`,
+		strings.Join(selects, " UNION ALL "),
+		batchSize,
+	)
+
+	return b.String()
+}
+
+// constructTopicKindPredicate matches the topics of a topic kind, whose first byte is the kind,
+// as a range that the topic indexes can serve.
+func constructTopicKindPredicate(kind topic.TopicKind) string {
+	if kind == math.MaxUint8 {
+		return fmt.Sprintf(`topic >= '\x%02x'::bytea`, uint8(kind))
+	}
+
+	return fmt.Sprintf(
+		`topic >= '\x%02x'::bytea AND topic < '\x%02x'::bytea`,
+		uint8(kind),
+		uint8(kind)+1,
+	)
+}
+
+// constructPartitionExportQuery selects every envelope in a meta partition and its blob partition.
+// Envelopes missing from the blob partition are selected with a NULL originator envelope.
+func constructPartitionExportQuery(metaTable string, blobTable string) string {
+	return fmt.Sprintf(`
+SELECT m.originator_node_id,
+       m.originator_sequence_id,
+       m.gateway_time,
+       m.expiry,
+       m.topic,
+       b.originator_envelope
+FROM %s m
+LEFT JOIN %s b
+  ON b.originator_node_id = m.originator_node_id
+ AND b.originator_sequence_id = m.originator_sequence_id
+ORDER BY m.originator_sequence_id;
+`, pq.QuoteIdentifier(metaTable), pq.QuoteIdentifier(blobTable))
 }
 
 func constructDropQuery(metaTable string, blobTable string) string {
@@ -5,10 +5,16 @@ import (
 	"time"
 
 	"github.com/xmtp/xmtpd/pkg/db/queries"
+	"github.com/xmtp/xmtpd/pkg/metrics"
 	"github.com/xmtp/xmtpd/pkg/utils"
 	"go.uber.org/zap"
 )
 
+type deletableTable struct {
+	originatorID uint32
+	ceiling      int64
+}
+
 func (e *Executor) PruneRows() error {
 	var (
 		querier = queries.New(e.writerDB)
@@ -23,7 +29,8 @@ func (e *Executor) PruneRows() error {
 		}
 		e.logger.Info("count of envelopes eligible for pruning", utils.CountField(envelopesCount))
 
-		if envelopesCount == 0 {
+		// The count only considers the envelope expiry, so retention overrides may still apply.
+		if envelopesCount == 0 && len(e.config.RetentionOverrides) == 0 {
 			e.logger.Info("no envelopes found for pruning")
 			return nil
 		}
@@ -55,7 +62,7 @@ func (e *Executor) PruneRows() error {
 		deletableCeilings[ceiling.OriginatorNodeID] = ceiling.MaxEndSequenceID
 	}
 
-	deletableTables := make(map[string]int64)
+	deletableTables := make(map[string]deletableTable)
 	for _, t := range latestEnvelopes {
 		if t.OriginatorNodeID == 0 || t.OriginatorNodeID == 1 {
 			e.logger.Debug(
@@ -79,7 +86,11 @@ func (e *Executor) PruneRows() error {
 			"Attempting to prune envelopes for originator",
 			utils.OriginatorIDField(uint32(t.OriginatorNodeID)),
 		)
-		deletableTables[fmt.Sprintf("gateway_envelopes_meta_o%d", t.OriginatorNodeID)] = ceilingForThisOriginator
+		tableName := fmt.Sprintf("gateway_envelopes_meta_o%d", t.OriginatorNodeID)
+		deletableTables[tableName] = deletableTable{
+			originatorID: uint32(t.OriginatorNodeID),
+			ceiling:      ceilingForThisOriginator,
+		}
 	}
 
 	for {
@@ -98,10 +109,8 @@ func (e *Executor) PruneRows() error {
 
 		var deletedThisCycle int64
 
-		for tableName, ceiling := range deletableTables {
-			result, err := e.writerDB.Exec(
-				constructVariableMetaTableQuery(tableName, e.config.BatchSize, ceiling),
-			)
+		for tableName, table := range deletableTables {
+			rows, bytes, err := e.pruneBatch(tableName, table)
 			if err != nil {
 				e.logger.Error(
 					"error pruning envelopes",
@@ -111,16 +120,8 @@ func (e *Executor) PruneRows() error {
 				delete(deletableTables, tableName)
 				continue
 			}
-			rows, err := result.RowsAffected()
-			if err != nil {
-				e.logger.Error(
-					"Unexpected DB error: could not count envelopes",
-					zap.Error(err),
-					zap.String("table", tableName),
-				)
-				delete(deletableTables, tableName)
-				continue
-			}
+
+			metrics.EmitPruneReclaimed(table.originatorID, rows, bytes)
 
 			deletedThisCycle += rows
 
@@ -132,6 +133,7 @@ func (e *Executor) PruneRows() error {
 			e.logger.Debug(
 				"pruned envelopes",
 				zap.Int64("deleted", rows),
+				zap.Int64("deleted_bytes", bytes),
 				zap.String("table", tableName),
 			)
 		}
@@ -0,0 +1,121 @@
+package prune
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/xmtp/xmtpd/pkg/config"
+	"github.com/xmtp/xmtpd/pkg/db"
+	"github.com/xmtp/xmtpd/pkg/metrics"
+	"github.com/xmtp/xmtpd/pkg/tracing"
+	"github.com/xmtp/xmtpd/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// Service runs the prune executor on a schedule from within the node: every interval, within
+// the daily window if one is configured.
+// Replicas sharing a database coordinate through an advisory lock, so only one
+// of them prunes at a time.
+type Service struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	logger   *zap.Logger
+	writerDB *sql.DB
+	interval time.Duration
+	window   config.DailyWindow
+	executor *Executor
+	wg       sync.WaitGroup
+}
+
+func NewPruneService(
+	ctx context.Context,
+	logger *zap.Logger,
+	writerDB *sql.DB,
+	options *config.PruneServiceOptions,
+	opts ...ExecutorOption,
+) *Service {
+	if options.Interval <= 0 {
+		logger.Panic("prune interval must be greater than zero")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	logger = logger.Named(utils.PrunerLoggerName)
+
+	return &Service{
+		ctx:      ctx,
+		cancel:   cancel,
+		logger:   logger,
+		writerDB: writerDB,
+		interval: options.Interval,
+		window:   options.Window,
+		executor: NewPruneExecutor(ctx, logger, writerDB, &options.PruneConfig, opts...),
+	}
+}
+
+// Start launches the service's main loop in a separate goroutine.
+func (s *Service) Start() {
+	tracing.GoPanicWrap(
+		s.ctx,
+		&s.wg,
+		"prune-service",
+		func(ctx context.Context) {
+			s.logger.Info(
+				"starting prune service",
+				zap.Duration("interval", s.interval),
+				zap.Stringer("window", s.window),
+			)
+
+			ticker := time.NewTicker(s.interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if !s.window.Contains(time.Now()) {
+						s.logger.Debug("outside of the prune window, skipping")
+						continue
+					}
+					if err := s.Prune(); err != nil {
+						s.logger.Error("pruning envelopes", zap.Error(err))
+					}
+				}
+			}
+		},
+	)
+}
+
+func (s *Service) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// Prune runs the executor once, unless another replica holds the prune lock.
+func (s *Service) Prune() error {
+	haLock, err := db.NewTransactionScopedAdvisoryLocker(s.ctx, s.writerDB, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = haLock.Release()
+	}()
+
+	locked, err := haLock.TryLockPruneWorker()
+	if err != nil {
+		return err
+	}
+	if !locked {
+		s.logger.Debug("prune lock held by another replica, skipping")
+		return nil
+	}
+
+	start := time.Now()
+	defer func() {
+		metrics.EmitPruneRunDuration(time.Since(start))
+	}()
+
+	return s.executor.Run()
+}
@@ -0,0 +1,47 @@
+package prune_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/config"
+	xmtpdDB "github.com/xmtp/xmtpd/pkg/db"
+	"github.com/xmtp/xmtpd/pkg/prune"
+	"github.com/xmtp/xmtpd/pkg/testutils"
+)
+
+func TestPruneService_SkipsWhileLockHeld(t *testing.T) {
+	ctx := context.Background()
+	dbs := testutils.NewDBs(t, ctx, 1)
+	db := dbs[0]
+
+	setupTestData(t, ctx, db, DefaultOriginatorID, DefaultExpiredCnt, 0, DefaultSubmittedCnt)
+
+	svc := prune.NewPruneService(ctx, testutils.NewLog(t), db, &config.PruneServiceOptions{
+		Interval: time.Hour,
+		PruneConfig: config.PruneConfig{
+			MaxCycles: 5,
+			BatchSize: 1000,
+		},
+	})
+	defer svc.Stop()
+
+	// Another replica is pruning.
+	haLock, err := xmtpdDB.NewTransactionScopedAdvisoryLocker(ctx, db, &sql.TxOptions{})
+	require.NoError(t, err)
+	locked, err := haLock.TryLockPruneWorker()
+	require.NoError(t, err)
+	require.True(t, locked)
+
+	require.NoError(t, svc.Prune())
+	assert.Len(t, getRemainingSequenceIds(t, ctx, db), DefaultExpiredCnt)
+
+	require.NoError(t, haLock.Release())
+
+	require.NoError(t, svc.Prune())
+	assert.Empty(t, getRemainingSequenceIds(t, ctx, db))
+}
@@ -0,0 +1,94 @@
+package prune
+
+import (
+	"time"
+
+	"github.com/xmtp/xmtpd/pkg/db/queries"
+	"github.com/xmtp/xmtpd/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// PruneUsageHistory deletes the payer usage history older than the usage retention, a batch
+// at a time. Usage history is kept forever when the retention is 0.
+func (e *Executor) PruneUsageHistory() error {
+	if e.config.UsageRetention <= 0 {
+		return nil
+	}
+
+	if e.config.DryRun {
+		e.logger.Info("dry run mode enabled, not pruning usage history")
+		return nil
+	}
+
+	var (
+		querier = queries.New(e.writerDB)
+		before  = utils.MinutesSinceEpoch(time.Now().Add(-e.config.UsageRetention))
+		total   int64
+	)
+
+	for range e.config.MaxCycles {
+		deleted, err := querier.DeletePayerUsageHistoryBefore(
+			e.ctx,
+			queries.DeletePayerUsageHistoryBeforeParams{
+				BeforeMinutesSinceEpoch: before,
+				RowLimit:                e.config.BatchSize,
+			},
+		)
+		if err != nil {
+			e.logger.Error("error pruning usage history", zap.Error(err))
+			return err
+		}
+
+		total += deleted
+		if deleted < int64(e.config.BatchSize) {
+			break
+		}
+	}
+
+	if total > 0 {
+		e.logger.Info("pruned usage history", utils.CountField(total))
+	}
+
+	return nil
+}
+
+// BackfillUsageHistory backfills the payer usage history with the usage that was still
+// unsettled before it was recorded, a batch at a time, until the backfill is done.
+func (e *Executor) BackfillUsageHistory() error {
+	if e.config.DryRun {
+		e.logger.Info("dry run mode enabled, not backfilling usage history")
+		return nil
+	}
+
+	var (
+		querier = queries.New(e.writerDB)
+		total   int64
+	)
+
+	for range e.config.MaxCycles {
+		read, err := querier.BackfillPayerUsageHistory(e.ctx, e.config.BatchSize)
+		if err != nil {
+			e.logger.Error("error backfilling usage history", zap.Error(err))
+			return err
+		}
+
+		total += int64(read)
+		if read < e.config.BatchSize {
+			done, err := querier.DeletePayerUsageHistoryBackfill(e.ctx)
+			if err != nil {
+				e.logger.Error("error finishing usage history backfill", zap.Error(err))
+				return err
+			}
+			if done > 0 {
+				e.logger.Info("finished backfilling usage history")
+			}
+			break
+		}
+	}
+
+	if total > 0 {
+		e.logger.Info("backfilled usage history", utils.CountField(total))
+	}
+
+	return nil
+}
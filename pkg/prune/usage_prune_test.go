@@ -0,0 +1,96 @@
+package prune_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/config"
+	"github.com/xmtp/xmtpd/pkg/db/queries"
+	"github.com/xmtp/xmtpd/pkg/testutils"
+	"github.com/xmtp/xmtpd/pkg/utils"
+)
+
+func TestExecutor_PrunesUsageHistoryPastRetention(t *testing.T) {
+	ctx := context.Background()
+	db := testutils.NewDBs(t, ctx, 1)[0]
+	q := queries.New(db)
+	payerID := testutils.CreatePayer(t, db)
+
+	now := time.Now()
+	for _, at := range []time.Time{now.Add(-48 * time.Hour), now.Add(-time.Hour)} {
+		err := q.IncrementPayerUsageHistory(ctx, queries.IncrementPayerUsageHistoryParams{
+			PayerID:            payerID,
+			MinutesSinceEpoch:  utils.MinutesSinceEpoch(at),
+			OriginatorID:       DefaultOriginatorID,
+			BaseFeePicodollars: 100,
+			MessageCount:       1,
+		})
+		require.NoError(t, err)
+	}
+
+	exec := makeTestExecutor(t, ctx, db, &config.PruneConfig{
+		MaxCycles:      5,
+		UsageRetention: 24 * time.Hour,
+	})
+	require.NoError(t, exec.Run())
+
+	start, err := q.GetPayerUsageHistoryStart(ctx, payerID)
+	require.NoError(t, err)
+	require.Equal(t, utils.MinutesSinceEpoch(now.Add(-time.Hour)), start)
+}
+
+func TestExecutor_BackfillsUnsettledUsageHistory(t *testing.T) {
+	ctx := context.Background()
+	db := testutils.NewDBs(t, ctx, 1)[0]
+	q := queries.New(db)
+	payerID := testutils.CreatePayer(t, db)
+
+	var (
+		now       = time.Now()
+		unsettled = utils.MinutesSinceEpoch(now.Add(-48 * time.Hour))
+		recorded  = utils.MinutesSinceEpoch(now.Add(-47 * time.Hour))
+	)
+	for i, minute := range []int32{unsettled, recorded} {
+		err := q.IncrementUnsettledUsage(ctx, queries.IncrementUnsettledUsageParams{
+			PayerID:           payerID,
+			OriginatorID:      DefaultOriginatorID,
+			MinutesSinceEpoch: minute,
+			SpendPicodollars:  100,
+			SequenceID:        int64(i + 1),
+			MessageCount:      2,
+		})
+		require.NoError(t, err)
+	}
+	err := q.IncrementPayerUsageHistory(ctx, queries.IncrementPayerUsageHistoryParams{
+		PayerID:            payerID,
+		MinutesSinceEpoch:  recorded,
+		OriginatorID:       DefaultOriginatorID,
+		TopicKind:          1,
+		BaseFeePicodollars: 100,
+		MessageCount:       2,
+	})
+	require.NoError(t, err)
+
+	exec := makeTestExecutor(t, ctx, db, &config.PruneConfig{MaxCycles: 5})
+	// The backfill is done after the first run, so the second does not backfill twice.
+	require.NoError(t, exec.Run())
+	require.NoError(t, exec.Run())
+
+	rows, err := q.GetPayerUsageHistory(ctx, queries.GetPayerUsageHistoryParams{
+		GroupBy:                "hour",
+		GroupByTopicKind:       true,
+		PayerID:                payerID,
+		StartMinutesSinceEpoch: unsettled,
+		EndMinutesSinceEpoch:   recorded + 1,
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.EqualValues(t, -1, rows[0].TopicKind)
+	require.EqualValues(t, 100, rows[0].TotalBaseFeePicodollars)
+	require.EqualValues(t, 0, rows[0].TotalCongestionFeePicodollars)
+	require.EqualValues(t, 2, rows[0].TotalMessageCount)
+	require.EqualValues(t, 1, rows[1].TopicKind)
+	require.EqualValues(t, 2, rows[1].TotalMessageCount)
+}
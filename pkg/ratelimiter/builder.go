@@ -13,23 +13,38 @@ import (
 	"go.uber.org/zap"
 )
 
+// Rate limiter backends selectable with RateLimitOptions.Backend.
+const (
+	BackendRedis  = "redis"
+	BackendMemory = "memory"
+)
+
 // BuiltLimiter is the result of constructing the rate-limit subsystem at
-// startup. It exposes a query limiter and an opens limiter (separate Redis
-// key prefixes), the parsed trusted-proxy CIDRs, and is consumed by the server
-// when constructing the rate-limit interceptor.
+// startup. It exposes query and opens limiters for Tier 2 (keyed by IP) and
+// Tier 1 (keyed by operator), each under a separate Redis key prefix, the
+// parsed trusted-proxy CIDRs, and is consumed by the server when constructing
+// the rate-limit interceptor.
 type BuiltLimiter struct {
-	QueryLimiter  RateLimiter   // BreakerLimiter wrapping a RedisLimiter([per-minute, per-hour])
-	OpensLimiter  RateLimiter   // BreakerLimiter wrapping a RedisLimiter([opens-per-minute])
-	StreamLimiter StreamLimiter // BreakerStreamLimiter wrapping a RedisStreamLimiter
-	TrustedCIDRs  []*net.IPNet
+	QueryLimiter      RateLimiter   // [per-minute, per-hour] buckets
+	OpensLimiter      RateLimiter   // [opens-per-minute] bucket
+	Tier1QueryLimiter RateLimiter   // Tier 1 counterpart of QueryLimiter
+	Tier1OpensLimiter RateLimiter   // Tier 1 counterpart of OpensLimiter
+	StreamLimiter     StreamLimiter // concurrent stream counters
+	TrustedCIDRs      []*net.IPNet
 }
 
+// limiterFactory constructs a RateLimiter for the given limits. The key suffix
+// separates the state of limiters sharing a backend.
+type limiterFactory func(keySuffix string, limits []Limit) (RateLimiter, error)
+
 // Build constructs the rate-limit subsystem from server configuration. If
 // rlOpts.Enable is false, returns (nil, nil) — the caller should treat the
 // nil result as "rate limiting disabled."
 //
-// When enabled, Build pings Redis and returns an error if it is unreachable.
-// This implements the spec's fail-fast-at-startup behavior.
+// The redis backend (the default) wraps every limiter in a circuit breaker and
+// shares its state across replicas. Build pings Redis and returns an error if
+// it is unreachable, which implements the spec's fail-fast-at-startup behavior.
+// The memory backend keeps state in process and needs no Redis.
 func Build(
 	ctx context.Context,
 	logger *zap.Logger,
@@ -39,56 +54,58 @@ func Build(
 	if !rlOpts.Enable {
 		return nil, nil
 	}
-	if redisOpts.RedisURL == "" {
-		return nil, errors.New("rate limiting enabled but XMTPD_REDIS_URL is empty")
-	}
 
-	parsed, err := redis.ParseURL(redisOpts.RedisURL)
-	if err != nil {
-		return nil, fmt.Errorf("invalid redis URL: %w", err)
-	}
-	client := redis.NewClient(parsed)
+	var (
+		newLimiter    limiterFactory
+		streamLimiter StreamLimiter
+		err           error
+	)
 
-	pingCtx, cancel := context.WithTimeout(ctx, redisOpts.ConnectTimeout)
-	defer cancel()
-	if err := client.Ping(pingCtx).Err(); err != nil {
-		return nil, fmt.Errorf("rate limiting enabled but redis ping failed: %w", err)
+	switch rlOpts.Backend {
+	case BackendRedis, "":
+		newLimiter, streamLimiter, err = buildRedisBackend(ctx, logger, redisOpts, rlOpts)
+		if err != nil {
+			return nil, err
+		}
+	case BackendMemory:
+		newLimiter = func(_ string, limits []Limit) (RateLimiter, error) {
+			return NewMemoryLimiter(limits)
+		}
+		streamLimiter = NewMemoryStreamLimiter(
+			rlOpts.T1MaxConcurrentSubscribeAll,
+			rlOpts.StreamTTL,
+		)
+	default:
+		return nil, fmt.Errorf("unknown rate limit backend %q", rlOpts.Backend)
 	}
 
-	queryInner, err := NewRedisLimiter(client, redisOpts.KeyPrefix+"rl:t2:q", []Limit{
+	queryLimiter, err := newLimiter("rl:t2:q", []Limit{
 		{Capacity: rlOpts.T2PerMinuteCapacity, RefillEvery: time.Minute},
 		{Capacity: rlOpts.T2PerHourCapacity, RefillEvery: time.Hour},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to construct query limiter: %w", err)
 	}
-	opensInner, err := NewRedisLimiter(client, redisOpts.KeyPrefix+"rl:t2:o", []Limit{
+	opensLimiter, err := newLimiter("rl:t2:o", []Limit{
 		{Capacity: rlOpts.T2SubscribeOpensPerMinute, RefillEvery: time.Minute},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to construct opens limiter: %w", err)
 	}
 
-	queryWrapped := NewBreakerLimiter(
-		queryInner,
-		NewCircuitBreaker(rlOpts.BreakerFailureThreshold, rlOpts.BreakerCooldown),
-	)
-	opensWrapped := NewBreakerLimiter(
-		opensInner,
-		NewCircuitBreaker(rlOpts.BreakerFailureThreshold, rlOpts.BreakerCooldown),
-	)
-
-	streamInner := NewRedisStreamLimiter(
-		client,
-		redisOpts.KeyPrefix+"rl:streams:",
-		rlOpts.T1MaxConcurrentSubscribeAll,
-		rlOpts.StreamTTL,
-	)
-	streamLimiter := NewBreakerStreamLimiter(
-		streamInner,
-		NewCircuitBreaker(rlOpts.BreakerFailureThreshold, rlOpts.BreakerCooldown),
-		logger,
-	)
+	t1QueryLimiter, err := newLimiter("rl:t1:q", []Limit{
+		{Capacity: rlOpts.T1PerMinuteCapacity, RefillEvery: time.Minute},
+		{Capacity: rlOpts.T1PerHourCapacity, RefillEvery: time.Hour},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct tier 1 query limiter: %w", err)
+	}
+	t1OpensLimiter, err := newLimiter("rl:t1:o", []Limit{
+		{Capacity: rlOpts.T1SubscribeOpensPerMinute, RefillEvery: time.Minute},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct tier 1 opens limiter: %w", err)
+	}
 
 	cidrs, err := clientip.ParseTrustedProxyCIDRs(rlOpts.TrustedProxyCIDRs)
 	if err != nil {
@@ -96,17 +113,78 @@ func Build(
 	}
 
 	logger.Info("rate limit interceptor enabled",
+		zap.String("backend", rlOpts.Backend),
+		zap.Int("t1_operators_with_api_keys", len(rlOpts.T1APIKeys)),
+		zap.Bool("t1_jwt_enabled", rlOpts.T1JWTSecret != ""),
+		zap.Int("t1_per_minute", rlOpts.T1PerMinuteCapacity),
+		zap.Int("t1_per_hour", rlOpts.T1PerHourCapacity),
+		zap.Int("t1_subscribe_opens_per_minute", rlOpts.T1SubscribeOpensPerMinute),
 		zap.Int("t2_per_minute", rlOpts.T2PerMinuteCapacity),
 		zap.Int("t2_per_hour", rlOpts.T2PerHourCapacity),
 		zap.Int("t2_subscribe_opens_per_minute", rlOpts.T2SubscribeOpensPerMinute),
 		zap.Int("t1_max_concurrent_streams", rlOpts.T1MaxConcurrentSubscribeAll),
 		zap.Duration("stream_ttl", rlOpts.StreamTTL),
 		zap.Duration("stream_refresh_interval", rlOpts.StreamRefreshInterval),
+		zap.Int("stream_bytes_per_token", rlOpts.StreamBytesPerToken),
+		zap.Duration("stream_max_wait", rlOpts.StreamMaxWait),
 	)
 	return &BuiltLimiter{
-		QueryLimiter:  queryWrapped,
-		OpensLimiter:  opensWrapped,
-		StreamLimiter: streamLimiter,
-		TrustedCIDRs:  cidrs,
+		QueryLimiter:      queryLimiter,
+		OpensLimiter:      opensLimiter,
+		Tier1QueryLimiter: t1QueryLimiter,
+		Tier1OpensLimiter: t1OpensLimiter,
+		StreamLimiter:     streamLimiter,
+		TrustedCIDRs:      cidrs,
 	}, nil
 }
+
+// buildRedisBackend connects to Redis and returns a factory of BreakerLimiters
+// wrapping RedisLimiters, along with a BreakerStreamLimiter wrapping a
+// RedisStreamLimiter.
+func buildRedisBackend(
+	ctx context.Context,
+	logger *zap.Logger,
+	redisOpts config.RedisOptions,
+	rlOpts config.RateLimitOptions,
+) (limiterFactory, StreamLimiter, error) {
+	if redisOpts.RedisURL == "" {
+		return nil, nil, errors.New("rate limiting enabled but XMTPD_REDIS_URL is empty")
+	}
+
+	parsed, err := redis.ParseURL(redisOpts.RedisURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+	client := redis.NewClient(parsed)
+
+	pingCtx, cancel := context.WithTimeout(ctx, redisOpts.ConnectTimeout)
+	defer cancel()
+	if err := client.Ping(pingCtx).Err(); err != nil {
+		return nil, nil, fmt.Errorf("rate limiting enabled but redis ping failed: %w", err)
+	}
+
+	newLimiter := func(keySuffix string, limits []Limit) (RateLimiter, error) {
+		inner, err := NewRedisLimiter(client, redisOpts.KeyPrefix+keySuffix, limits)
+		if err != nil {
+			return nil, err
+		}
+		return NewBreakerLimiter(
+			inner,
+			NewCircuitBreaker(rlOpts.BreakerFailureThreshold, rlOpts.BreakerCooldown),
+		), nil
+	}
+
+	streamInner := NewRedisStreamLimiter(
+		client,
+		redisOpts.KeyPrefix+"rl:streams:",
+		rlOpts.T1MaxConcurrentSubscribeAll,
+		rlOpts.StreamTTL,
+	)
+	streamLimiter := NewBreakerStreamLimiter(
+		streamInner,
+		NewCircuitBreaker(rlOpts.BreakerFailureThreshold, rlOpts.BreakerCooldown),
+		logger,
+	)
+
+	return newLimiter, streamLimiter, nil
+}
@@ -3,6 +3,7 @@ package ratelimiter_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/xmtp/xmtpd/pkg/config"
@@ -20,3 +21,47 @@ func TestBuild_DisabledReturnsNil(t *testing.T) {
 	require.NoError(t, err)
 	require.Nil(t, got)
 }
+
+func TestBuild_MemoryBackendNeedsNoRedis(t *testing.T) {
+	got, err := ratelimiter.Build(
+		context.Background(),
+		zap.NewNop(),
+		config.RedisOptions{},
+		config.RateLimitOptions{
+			Enable:                      true,
+			Backend:                     ratelimiter.BackendMemory,
+			T1PerMinuteCapacity:         10,
+			T1PerHourCapacity:           100,
+			T1SubscribeOpensPerMinute:   1,
+			T2PerMinuteCapacity:         1,
+			T2PerHourCapacity:           10,
+			T2SubscribeOpensPerMinute:   1,
+			T1MaxConcurrentSubscribeAll: 1,
+			StreamTTL:                   time.Minute,
+		},
+	)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+
+	res, err := got.QueryLimiter.Allow(context.Background(), "203.0.113.1", 1)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+
+	res, err = got.QueryLimiter.Allow(context.Background(), "203.0.113.1", 1)
+	require.NoError(t, err)
+	require.False(t, res.Allowed)
+
+	allowed, err := got.StreamLimiter.Acquire(context.Background(), "203.0.113.1")
+	require.NoError(t, err)
+	require.True(t, allowed)
+}
+
+func TestBuild_UnknownBackend(t *testing.T) {
+	_, err := ratelimiter.Build(
+		context.Background(),
+		zap.NewNop(),
+		config.RedisOptions{},
+		config.RateLimitOptions{Enable: true, Backend: "memcached"},
+	)
+	require.Error(t, err)
+}
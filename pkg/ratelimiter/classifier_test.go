@@ -21,3 +21,29 @@ func TestClassify_Tier2WhenContextFlagFalse(t *testing.T) {
 	ctx := context.WithValue(context.Background(), constants.VerifiedNodeRequestCtxKey{}, false)
 	require.Equal(t, Tier2, ClassifyTier(ctx))
 }
+
+func TestClassify_Tier1WhenOperatorVerified(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constants.VerifiedOperatorIDCtxKey{}, "acme")
+	require.Equal(t, Tier1, ClassifyTier(ctx))
+
+	operatorID, ok := OperatorID(ctx)
+	require.True(t, ok)
+	require.Equal(t, "acme", operatorID)
+}
+
+func TestClassify_Tier0TakesPrecedenceOverTier1(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constants.VerifiedNodeRequestCtxKey{}, true)
+	ctx = context.WithValue(ctx, constants.VerifiedOperatorIDCtxKey{}, "acme")
+	require.Equal(t, Tier0, ClassifyTier(ctx))
+}
+
+func TestClassify_Tier2WhenOperatorEmpty(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constants.VerifiedOperatorIDCtxKey{}, "")
+	require.Equal(t, Tier2, ClassifyTier(ctx))
+}
+
+func TestTier_String(t *testing.T) {
+	require.Equal(t, "tier0", Tier0.String())
+	require.Equal(t, "tier1", Tier1.String())
+	require.Equal(t, "tier2", Tier2.String())
+}
@@ -0,0 +1,260 @@
+package ratelimiter_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/ratelimiter"
+	redistestutils "github.com/xmtp/xmtpd/pkg/testutils/redis"
+)
+
+// The conformance suites below are run against every RateLimiter and
+// StreamLimiter backend, so that backends stay interchangeable.
+
+type newLimiterFunc func(t *testing.T, limits []ratelimiter.Limit) ratelimiter.RateLimiter
+
+type newStreamLimiterFunc func(
+	t *testing.T,
+	maxCount int,
+	ttl time.Duration,
+) ratelimiter.StreamLimiter
+
+func TestRedisLimiter_Conformance(t *testing.T) {
+	runLimiterConformance(
+		t,
+		func(t *testing.T, limits []ratelimiter.Limit) ratelimiter.RateLimiter {
+			client, keyPrefix := redistestutils.NewRedisForTest(t)
+			limiter, err := ratelimiter.NewRedisLimiter(client, keyPrefix, limits)
+			require.NoError(t, err)
+			return limiter
+		},
+	)
+}
+
+func TestMemoryLimiter_Conformance(t *testing.T) {
+	runLimiterConformance(
+		t,
+		func(t *testing.T, limits []ratelimiter.Limit) ratelimiter.RateLimiter {
+			limiter, err := ratelimiter.NewMemoryLimiter(limits)
+			require.NoError(t, err)
+			return limiter
+		},
+	)
+}
+
+func TestRedisStreamLimiter_Conformance(t *testing.T) {
+	runStreamLimiterConformance(
+		t,
+		func(t *testing.T, maxCount int, ttl time.Duration) ratelimiter.StreamLimiter {
+			client, keyPrefix := redistestutils.NewRedisForTest(t)
+			return ratelimiter.NewRedisStreamLimiter(client, keyPrefix+"streams:", maxCount, ttl)
+		},
+	)
+}
+
+func TestMemoryStreamLimiter_Conformance(t *testing.T) {
+	runStreamLimiterConformance(
+		t,
+		func(_ *testing.T, maxCount int, ttl time.Duration) ratelimiter.StreamLimiter {
+			return ratelimiter.NewMemoryStreamLimiter(maxCount, ttl)
+		},
+	)
+}
+
+func runLimiterConformance(t *testing.T, newLimiter newLimiterFunc) {
+	ctx := context.Background()
+
+	t.Run("allows within and denies over capacity", func(t *testing.T) {
+		limiter := newLimiter(t, []ratelimiter.Limit{
+			{Capacity: 100, RefillEvery: time.Hour},
+			{Capacity: 10, RefillEvery: time.Hour},
+		})
+
+		res, err := limiter.Allow(ctx, "subject", 10)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+		require.Nil(t, res.FailedLimit)
+		require.Nil(t, res.RetryAfter)
+
+		res, err = limiter.Allow(ctx, "subject", 1)
+		require.NoError(t, err)
+		require.False(t, res.Allowed)
+	})
+
+	t.Run("reports post-decrement balances", func(t *testing.T) {
+		limits := []ratelimiter.Limit{
+			{Capacity: 10, RefillEvery: time.Hour},
+			{Capacity: 20, RefillEvery: time.Hour},
+		}
+		limiter := newLimiter(t, limits)
+
+		res, err := limiter.Allow(ctx, "subject", 4)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+		require.Len(t, res.Balances, len(limits))
+		for i, want := range []float64{6, 16} {
+			require.Equal(t, limits[i], res.Balances[i].Limit)
+			require.InDelta(t, want, res.Balances[i].Remaining, 0.01)
+		}
+	})
+
+	t.Run("denials decrement no limit", func(t *testing.T) {
+		limits := []ratelimiter.Limit{
+			{Capacity: 20, RefillEvery: time.Hour},
+			{Capacity: 3, RefillEvery: time.Hour},
+		}
+		limiter := newLimiter(t, limits)
+
+		res, err := limiter.Allow(ctx, "subject", 5)
+		require.NoError(t, err)
+		require.False(t, res.Allowed)
+		require.Equal(t, limits[1], *res.FailedLimit)
+		for i, want := range []float64{20, 3} {
+			require.InDelta(t, want, res.Balances[i].Remaining, 0.01)
+		}
+
+		res, err = limiter.Allow(ctx, "subject", 3)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+		for i, want := range []float64{17, 0} {
+			require.InDelta(t, want, res.Balances[i].Remaining, 0.01)
+		}
+	})
+
+	t.Run("reports the first failed limit and retry after", func(t *testing.T) {
+		limits := []ratelimiter.Limit{
+			{Capacity: 10, RefillEvery: time.Hour},
+			{Capacity: 10, RefillEvery: time.Minute},
+		}
+		limiter := newLimiter(t, limits)
+
+		_, err := limiter.Allow(ctx, "subject", 10)
+		require.NoError(t, err)
+
+		res, err := limiter.Allow(ctx, "subject", 5)
+		require.NoError(t, err)
+		require.False(t, res.Allowed)
+		require.Equal(t, limits[0], *res.FailedLimit)
+		require.NotNil(t, res.RetryAfter)
+		// 5 missing tokens at 10 tokens per hour, minus the little refilled meanwhile.
+		require.InDelta(t, 30*time.Minute, *res.RetryAfter, float64(5*time.Second))
+	})
+
+	t.Run("refills over time", func(t *testing.T) {
+		limiter := newLimiter(t, []ratelimiter.Limit{
+			{Capacity: 10, RefillEvery: 200 * time.Millisecond},
+		})
+
+		res, err := limiter.Allow(ctx, "subject", 10)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+
+		res, err = limiter.Allow(ctx, "subject", 10)
+		require.NoError(t, err)
+		require.False(t, res.Allowed)
+
+		time.Sleep(250 * time.Millisecond)
+
+		res, err = limiter.Allow(ctx, "subject", 10)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+	})
+
+	t.Run("isolates subjects", func(t *testing.T) {
+		limiter := newLimiter(t, []ratelimiter.Limit{{Capacity: 1, RefillEvery: time.Hour}})
+
+		res, err := limiter.Allow(ctx, "subject-a", 1)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+
+		res, err = limiter.Allow(ctx, "subject-b", 1)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+
+		res, err = limiter.Allow(ctx, "subject-a", 1)
+		require.NoError(t, err)
+		require.False(t, res.Allowed)
+	})
+
+	t.Run("rejects zero cost", func(t *testing.T) {
+		limiter := newLimiter(t, []ratelimiter.Limit{{Capacity: 1, RefillEvery: time.Hour}})
+
+		_, err := limiter.Allow(ctx, "subject", 0)
+		require.ErrorIs(t, err, ratelimiter.ErrCostMustBeGreaterThanZero)
+	})
+}
+
+func runStreamLimiterConformance(t *testing.T, newStreamLimiter newStreamLimiterFunc) {
+	ctx := context.Background()
+
+	t.Run("admits up to the maximum", func(t *testing.T) {
+		limiter := newStreamLimiter(t, 2, time.Minute)
+
+		for range 2 {
+			allowed, err := limiter.Acquire(ctx, "10.0.0.1")
+			require.NoError(t, err)
+			require.True(t, allowed)
+		}
+
+		allowed, err := limiter.Acquire(ctx, "10.0.0.1")
+		require.NoError(t, err)
+		require.False(t, allowed)
+
+		allowed, err = limiter.Acquire(ctx, "10.0.0.2")
+		require.NoError(t, err)
+		require.True(t, allowed, "subjects should be independent")
+	})
+
+	t.Run("release frees a slot", func(t *testing.T) {
+		limiter := newStreamLimiter(t, 1, time.Minute)
+
+		allowed, err := limiter.Acquire(ctx, "10.0.0.1")
+		require.NoError(t, err)
+		require.True(t, allowed)
+
+		require.NoError(t, limiter.Release(ctx, "10.0.0.1"))
+
+		allowed, err = limiter.Acquire(ctx, "10.0.0.1")
+		require.NoError(t, err)
+		require.True(t, allowed)
+	})
+
+	t.Run("release never goes negative", func(t *testing.T) {
+		limiter := newStreamLimiter(t, 1, time.Minute)
+
+		require.NoError(t, limiter.Release(ctx, "10.0.0.1"))
+		require.NoError(t, limiter.Release(ctx, "10.0.0.1"))
+
+		allowed, err := limiter.Acquire(ctx, "10.0.0.1")
+		require.NoError(t, err)
+		require.True(t, allowed)
+
+		allowed, err = limiter.Acquire(ctx, "10.0.0.1")
+		require.NoError(t, err)
+		require.False(t, allowed)
+	})
+
+	t.Run("expiry frees slots and refresh keeps them", func(t *testing.T) {
+		limiter := newStreamLimiter(t, 1, 300*time.Millisecond)
+
+		allowed, err := limiter.Acquire(ctx, "10.0.0.1")
+		require.NoError(t, err)
+		require.True(t, allowed)
+
+		time.Sleep(200 * time.Millisecond)
+		require.NoError(t, limiter.RefreshTTL(ctx, "10.0.0.1"))
+		time.Sleep(200 * time.Millisecond)
+
+		allowed, err = limiter.Acquire(ctx, "10.0.0.1")
+		require.NoError(t, err)
+		require.False(t, allowed, "refreshed slot should still be held")
+
+		time.Sleep(400 * time.Millisecond)
+
+		allowed, err = limiter.Acquire(ctx, "10.0.0.1")
+		require.NoError(t, err)
+		require.True(t, allowed, "expired slot should be freed")
+	})
+}
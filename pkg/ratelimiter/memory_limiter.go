@@ -0,0 +1,138 @@
+package ratelimiter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryBucket holds the token balances of a single subject, one per limit,
+// as of lastUpdate.
+type memoryBucket struct {
+	lastUpdate time.Time
+	tokens     []float64
+}
+
+// MemoryLimiter is an in-process RateLimiter with the same semantics as
+// RedisLimiter: continuously refilling token buckets, all limits checked and
+// decremented atomically, and the same Retry-After calculation. State is not
+// shared between processes, so each replica enforces its own limits.
+//
+// Subjects are forgotten once their buckets have refilled to capacity, which
+// mirrors the key expiry of RedisLimiter.
+type MemoryLimiter struct {
+	limits    []Limit
+	maxRefill time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+}
+
+var _ RateLimiter = (*MemoryLimiter)(nil)
+
+func NewMemoryLimiter(limits []Limit) (*MemoryLimiter, error) {
+	if err := validateLimits(limits); err != nil {
+		return nil, err
+	}
+
+	var maxRefill time.Duration
+	for _, lim := range limits {
+		maxRefill = max(maxRefill, lim.RefillEvery)
+	}
+
+	return &MemoryLimiter{
+		limits:    limits,
+		maxRefill: maxRefill,
+		now:       time.Now,
+		buckets:   make(map[string]*memoryBucket),
+	}, nil
+}
+
+func (l *MemoryLimiter) Allow(_ context.Context, subject string, cost uint64) (*Result, error) {
+	if cost == 0 {
+		return nil, ErrCostMustBeGreaterThanZero
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	tokens := l.refill(l.buckets[subject], now)
+
+	failedIndex := -1
+	for i := range l.limits {
+		if tokens[i] < float64(cost) {
+			failedIndex = i
+			break
+		}
+	}
+
+	if failedIndex >= 0 {
+		// Denied requests leave the stored state untouched.
+		limit := l.limits[failedIndex]
+		return &Result{
+			Allowed:     false,
+			FailedLimit: &limit,
+			RetryAfter:  calculateRetryAfter(limit, tokens[failedIndex], cost),
+			Balances:    l.balances(tokens),
+		}, nil
+	}
+
+	for i := range tokens {
+		tokens[i] -= float64(cost)
+	}
+	l.buckets[subject] = &memoryBucket{lastUpdate: now, tokens: tokens}
+
+	return &Result{
+		Allowed:  true,
+		Balances: l.balances(tokens),
+	}, nil
+}
+
+// refill returns the subject's current balances. A new subject starts with
+// full buckets, and time running backwards is treated as no time passing.
+func (l *MemoryLimiter) refill(bucket *memoryBucket, now time.Time) []float64 {
+	tokens := make([]float64, len(l.limits))
+	for i, lim := range l.limits {
+		if bucket == nil {
+			tokens[i] = float64(lim.Capacity)
+			continue
+		}
+
+		elapsed := max(now.Sub(bucket.lastUpdate), 0)
+		rate := float64(lim.Capacity) / float64(lim.RefillEvery)
+		tokens[i] = min(float64(lim.Capacity), bucket.tokens[i]+rate*float64(elapsed))
+	}
+	return tokens
+}
+
+// sweep forgets subjects that have been idle long enough for every bucket to
+// refill, since they are indistinguishable from new subjects. It runs at most
+// once per longest refill period, and must be called with mu held.
+func (l *MemoryLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.maxRefill {
+		return
+	}
+	l.lastSweep = now
+
+	for subject, bucket := range l.buckets {
+		if now.Sub(bucket.lastUpdate) >= l.maxRefill {
+			delete(l.buckets, subject)
+		}
+	}
+}
+
+func (l *MemoryLimiter) balances(tokens []float64) []LimitBalance {
+	balances := make([]LimitBalance, len(l.limits))
+	for i := range l.limits {
+		balances[i] = LimitBalance{
+			Limit:     l.limits[i],
+			Remaining: tokens[i],
+		}
+	}
+	return balances
+}
@@ -0,0 +1,70 @@
+package ratelimiter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLimiter_ForgetsRefilledSubjects(t *testing.T) {
+	limiter, err := NewMemoryLimiter([]Limit{
+		{Capacity: 10, RefillEvery: time.Minute},
+		{Capacity: 100, RefillEvery: time.Hour},
+	})
+	require.NoError(t, err)
+
+	now := time.Unix(1_700_000_000, 0)
+	limiter.now = func() time.Time { return now }
+
+	_, err = limiter.Allow(context.Background(), "idle", 1)
+	require.NoError(t, err)
+
+	now = now.Add(30 * time.Minute)
+	_, err = limiter.Allow(context.Background(), "active", 1)
+	require.NoError(t, err)
+	require.Len(t, limiter.buckets, 2)
+
+	// The idle subject's hourly bucket has refilled, the active one's has not.
+	now = now.Add(45 * time.Minute)
+	res, err := limiter.Allow(context.Background(), "other", 1)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+
+	require.NotContains(t, limiter.buckets, "idle")
+	require.Contains(t, limiter.buckets, "active")
+}
+
+func TestMemoryLimiter_ClockSkew(t *testing.T) {
+	limiter, err := NewMemoryLimiter([]Limit{{Capacity: 10, RefillEvery: time.Minute}})
+	require.NoError(t, err)
+
+	now := time.Unix(1_700_000_000, 0)
+	limiter.now = func() time.Time { return now }
+
+	_, err = limiter.Allow(context.Background(), "subject", 5)
+	require.NoError(t, err)
+
+	// Time running backwards must not refill or drain the bucket.
+	now = now.Add(-time.Minute)
+	res, err := limiter.Allow(context.Background(), "subject", 1)
+	require.NoError(t, err)
+	require.InDelta(t, 4, res.Balances[0].Remaining, 0.001)
+}
+
+func TestMemoryStreamLimiter_Expiry(t *testing.T) {
+	limiter := NewMemoryStreamLimiter(1, time.Minute)
+
+	now := time.Unix(1_700_000_000, 0)
+	limiter.now = func() time.Time { return now }
+
+	allowed, err := limiter.Acquire(context.Background(), "10.0.0.1")
+	require.NoError(t, err)
+	require.True(t, allowed)
+
+	now = now.Add(time.Minute)
+	allowed, err = limiter.Acquire(context.Background(), "10.0.0.1")
+	require.NoError(t, err)
+	require.True(t, allowed, "slot should be freed once the counter expires")
+}
@@ -0,0 +1,106 @@
+package ratelimiter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryStreamCount struct {
+	count     int
+	expiresAt time.Time
+}
+
+// MemoryStreamLimiter is an in-process StreamLimiter with the same semantics as
+// RedisStreamLimiter. Each subject's count expires ttl after it was last
+// acquired, released or refreshed, which frees the slots of streams whose
+// Release was never called.
+type MemoryStreamLimiter struct {
+	maxCount int
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu     sync.Mutex
+	counts map[string]*memoryStreamCount
+}
+
+var _ StreamLimiter = (*MemoryStreamLimiter)(nil)
+
+// NewMemoryStreamLimiter creates a MemoryStreamLimiter.
+//   - maxCount: maximum allowed concurrent streams per subject.
+//   - ttl: counter expiry; acts as the self-heal window for missed releases.
+func NewMemoryStreamLimiter(maxCount int, ttl time.Duration) *MemoryStreamLimiter {
+	return &MemoryStreamLimiter{
+		maxCount: maxCount,
+		ttl:      ttl,
+		now:      time.Now,
+		counts:   make(map[string]*memoryStreamCount),
+	}
+}
+
+// current returns the live counter of subject, dropping it if it expired. It
+// must be called with mu held.
+func (l *MemoryStreamLimiter) current(subject string, now time.Time) *memoryStreamCount {
+	c, ok := l.counts[subject]
+	if !ok {
+		return nil
+	}
+	if !now.Before(c.expiresAt) {
+		delete(l.counts, subject)
+		return nil
+	}
+	return c
+}
+
+// Acquire increments the stream count only if it is below maxCount. Returns
+// allowed=true if the stream was admitted.
+func (l *MemoryStreamLimiter) Acquire(_ context.Context, subject string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	c := l.current(subject, now)
+	if c == nil {
+		c = &memoryStreamCount{}
+	}
+	if c.count >= l.maxCount {
+		return false, nil
+	}
+
+	c.count++
+	c.expiresAt = now.Add(l.ttl)
+	l.counts[subject] = c
+	return true, nil
+}
+
+// Release decrements the stream count, clamped at zero.
+func (l *MemoryStreamLimiter) Release(_ context.Context, subject string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	c := l.current(subject, now)
+	if c == nil || c.count == 0 {
+		return nil
+	}
+
+	c.count--
+	if c.count == 0 {
+		delete(l.counts, subject)
+		return nil
+	}
+	c.expiresAt = now.Add(l.ttl)
+	return nil
+}
+
+// RefreshTTL extends the counter's expiry while a stream is open.
+func (l *MemoryStreamLimiter) RefreshTTL(_ context.Context, subject string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if c := l.current(subject, now); c != nil {
+		c.expiresAt = now.Add(l.ttl)
+	}
+	return nil
+}
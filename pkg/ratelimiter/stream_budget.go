@@ -0,0 +1,101 @@
+package ratelimiter
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrStreamBudgetExhausted is returned by StreamBudget.Charge when the caller's
+// bucket does not refill within the budget's maximum wait.
+var ErrStreamBudgetExhausted = errors.New("stream delivery budget exhausted")
+
+// defaultStreamBudgetRetry is how long Charge waits before retrying a denial
+// that did not report a RetryAfter.
+const defaultStreamBudgetRetry = time.Second
+
+// StreamBudget charges the bytes a long-lived stream delivers against its
+// caller's token bucket, one token per bytesPerToken bytes. Bytes below a whole
+// token are carried over to the next charge, so many small frames cost the same
+// as one large frame.
+//
+// When the bucket is empty, Charge applies backpressure by waiting for it to
+// refill, for at most maxWait per charge; after that the stream has exhausted
+// its budget and should be terminated. Limiter errors fail open, like
+// admission. A StreamBudget is not safe for concurrent use.
+type StreamBudget struct {
+	limiter       RateLimiter
+	subject       string
+	bytesPerToken int
+	maxWait       time.Duration
+	owed          int // delivered bytes not yet billed as a whole token
+}
+
+// NewStreamBudget creates a StreamBudget billing subject on limiter.
+//   - bytesPerToken: delivered bytes that cost one token; must be positive.
+//   - maxWait: longest a single Charge waits for the bucket to refill.
+func NewStreamBudget(
+	limiter RateLimiter,
+	subject string,
+	bytesPerToken int,
+	maxWait time.Duration,
+) *StreamBudget {
+	return &StreamBudget{
+		limiter:       limiter,
+		subject:       subject,
+		bytesPerToken: bytesPerToken,
+		maxWait:       maxWait,
+	}
+}
+
+// Charge bills the whole tokens owed for delivering bytes, waiting for the
+// bucket to refill when it is empty. It returns ErrStreamBudgetExhausted if
+// the bucket has not refilled within maxWait, or ctx's error if ctx is done
+// first.
+func (b *StreamBudget) Charge(ctx context.Context, bytes int) error {
+	b.owed += bytes
+	tokens := uint64(b.owed / b.bytesPerToken)
+	if tokens == 0 {
+		return nil
+	}
+
+	deadline := time.Now().Add(b.maxWait)
+	// A charge larger than a bucket's capacity could never be allowed, so it is
+	// billed in chunks of at most that capacity.
+	chunkCap := tokens
+	for tokens > 0 {
+		chunk := min(tokens, chunkCap)
+		res, err := b.limiter.Allow(ctx, b.subject, chunk)
+		if err != nil {
+			// BreakerLimiter handles errors and fails open; forgive the charge.
+			b.owed %= b.bytesPerToken
+			return nil //nolint:nilerr // intentional fail-open
+		}
+		if res.Allowed {
+			tokens -= chunk
+			b.owed -= int(chunk) * b.bytesPerToken
+			continue
+		}
+		if lim := res.FailedLimit; lim != nil && lim.Capacity > 0 &&
+			chunk > uint64(lim.Capacity) {
+			chunkCap = uint64(lim.Capacity)
+			continue
+		}
+
+		wait := defaultStreamBudgetRetry
+		if res.RetryAfter != nil {
+			wait = *res.RetryAfter
+		}
+		if time.Now().Add(wait).After(deadline) {
+			return ErrStreamBudgetExhausted
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package ratelimiter_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/ratelimiter"
+)
+
+type recordingLimiter struct {
+	inner ratelimiter.RateLimiter
+	costs []uint64
+}
+
+func (l *recordingLimiter) Allow(
+	ctx context.Context,
+	subject string,
+	cost uint64,
+) (*ratelimiter.Result, error) {
+	l.costs = append(l.costs, cost)
+	return l.inner.Allow(ctx, subject, cost)
+}
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string, uint64) (*ratelimiter.Result, error) {
+	return nil, errors.New("backend unavailable")
+}
+
+func newRecordingLimiter(t *testing.T, limits ...ratelimiter.Limit) *recordingLimiter {
+	inner, err := ratelimiter.NewMemoryLimiter(limits)
+	require.NoError(t, err)
+	return &recordingLimiter{inner: inner}
+}
+
+func TestStreamBudget_CarriesPartialTokens(t *testing.T) {
+	limiter := newRecordingLimiter(t, ratelimiter.Limit{Capacity: 100, RefillEvery: time.Hour})
+	budget := ratelimiter.NewStreamBudget(limiter, "subject", 100, time.Second)
+
+	for range 3 {
+		require.NoError(t, budget.Charge(context.Background(), 40))
+	}
+	require.Equal(t, []uint64{1}, limiter.costs, "120 bytes cost one token, 20 carried")
+
+	require.NoError(t, budget.Charge(context.Background(), 250))
+	require.Equal(t, []uint64{1, 2}, limiter.costs, "270 bytes cost two tokens, 70 carried")
+}
+
+func TestStreamBudget_ExhaustedWhenRefillExceedsMaxWait(t *testing.T) {
+	limiter := newRecordingLimiter(t, ratelimiter.Limit{Capacity: 2, RefillEvery: time.Hour})
+	budget := ratelimiter.NewStreamBudget(limiter, "subject", 10, time.Second)
+
+	require.NoError(t, budget.Charge(context.Background(), 20))
+	err := budget.Charge(context.Background(), 10)
+	require.ErrorIs(t, err, ratelimiter.ErrStreamBudgetExhausted)
+}
+
+func TestStreamBudget_WaitsForRefill(t *testing.T) {
+	limiter := newRecordingLimiter(
+		t,
+		ratelimiter.Limit{Capacity: 2, RefillEvery: 200 * time.Millisecond},
+	)
+	budget := ratelimiter.NewStreamBudget(limiter, "subject", 10, time.Second)
+
+	require.NoError(t, budget.Charge(context.Background(), 20))
+
+	start := time.Now()
+	require.NoError(t, budget.Charge(context.Background(), 20))
+	require.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond,
+		"an empty bucket should apply backpressure rather than fail")
+}
+
+func TestStreamBudget_ChunksChargesAboveCapacity(t *testing.T) {
+	limiter := newRecordingLimiter(
+		t,
+		ratelimiter.Limit{Capacity: 3, RefillEvery: 300 * time.Millisecond},
+	)
+	budget := ratelimiter.NewStreamBudget(limiter, "subject", 1, time.Second)
+
+	require.NoError(t, budget.Charge(context.Background(), 5))
+	// 5 is denied outright, then billed as 3 and, once refilled, 2.
+	require.Equal(t, []uint64{5, 3, 2, 2}, limiter.costs)
+}
+
+func TestStreamBudget_StopsWaitingWhenContextDone(t *testing.T) {
+	limiter := newRecordingLimiter(
+		t,
+		ratelimiter.Limit{Capacity: 1, RefillEvery: 500 * time.Millisecond},
+	)
+	budget := ratelimiter.NewStreamBudget(limiter, "subject", 1, time.Second)
+	require.NoError(t, budget.Charge(context.Background(), 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, budget.Charge(ctx, 1), context.DeadlineExceeded)
+}
+
+func TestStreamBudget_FailsOpen(t *testing.T) {
+	budget := ratelimiter.NewStreamBudget(failingLimiter{}, "subject", 1, 0)
+	require.NoError(t, budget.Charge(context.Background(), 100))
+}
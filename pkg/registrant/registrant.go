@@ -135,6 +135,20 @@ func (r *Registrant) SignPayerReportAttestation(
 	}, nil
 }
 
+// SignMisbehaviorReport signs a serialized UnsignedMisbehaviorReport hosted by this node.
+func (r *Registrant) SignMisbehaviorReport(
+	unsignedReport []byte,
+) (*associations.RecoverableEcdsaSignature, error) {
+	sig, err := r.sign(utils.HashMisbehaviorReportInput(unsignedReport))
+	if err != nil {
+		return nil, err
+	}
+
+	return &associations.RecoverableEcdsaSignature{
+		Bytes: sig,
+	}, nil
+}
+
 func (r *Registrant) SignClientEnvelopeToSelf(unsignedClientEnvelope []byte) ([]byte, error) {
 	return utils.SignClientEnvelope(r.record.NodeID, unsignedClientEnvelope, r.privateKey)
 }
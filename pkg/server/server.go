@@ -18,13 +18,18 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/trace/log"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/xmtp/xmtpd/pkg/api/metadata"
+	misbehaviorAPI "github.com/xmtp/xmtpd/pkg/api/misbehavior"
 	"github.com/xmtp/xmtpd/pkg/constants"
 	"github.com/xmtp/xmtpd/pkg/db"
 	"github.com/xmtp/xmtpd/pkg/fees"
 	ledgerPkg "github.com/xmtp/xmtpd/pkg/ledger"
 	"github.com/xmtp/xmtpd/pkg/migrator"
+	"github.com/xmtp/xmtpd/pkg/misbehavior"
+	"github.com/xmtp/xmtpd/pkg/payerbalance"
 	"github.com/xmtp/xmtpd/pkg/payerreport"
 	"github.com/xmtp/xmtpd/pkg/payerreport/workers"
+	"github.com/xmtp/xmtpd/pkg/prune"
+	"github.com/xmtp/xmtpd/pkg/prune/archive"
 
 	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/message_api/message_apiconnect"
 	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/metadata_api/metadata_apiconnect"
@@ -61,6 +66,9 @@ type BaseServerConfig struct {
 	FeeCalculator fees.IFeeCalculator
 	PromReg       *prometheus.Registry
 	Listener      net.Listener
+	// ReadAuthorizers are consulted, after any configured read policy, before
+	// serving topics to QueryApi callers.
+	ReadAuthorizers []message.AuthorizeReadFn
 }
 
 func (cfg BaseServerConfig) Valid() error {
@@ -151,6 +159,12 @@ func WithListener(ln net.Listener) BaseServerOption {
 	}
 }
 
+func WithReadAuthorizers(authorizers ...message.AuthorizeReadFn) BaseServerOption {
+	return func(cfg *BaseServerConfig) {
+		cfg.ReadAuthorizers = append(cfg.ReadAuthorizers, authorizers...)
+	}
+}
+
 type BaseServer struct {
 	// Control mechanisms.
 	ctx    context.Context
@@ -177,6 +191,9 @@ type BaseServer struct {
 	cursorUpdater       metadata.CursorUpdater
 	blockchainPublisher *blockchain.BlockchainPublisher
 	reportWorkers       *workers.WorkerWrapper
+	pruneService        *prune.Service
+	payerBalanceWorker  *payerbalance.Worker
+	misbehaviorService  *misbehavior.PersistentMisbehaviorService
 }
 
 type BaseServerOption func(*BaseServerConfig)
@@ -188,6 +205,8 @@ type BaseServerOption func(*BaseServerConfig)
 // - Indexer service: indexes the blockchain and provides the data to the APIs.
 // - Migration service: migrates an old V3 database to the D14N network.
 // - Payer report service: generates and sends payer reports to the nodes.
+// - Prune service: periodically deletes expired envelopes.
+// - Payer balance worker: notifies when payers are forecast to run out of funds.
 func NewBaseServer(
 	opts ...BaseServerOption,
 ) (*BaseServer, error) {
@@ -257,6 +276,14 @@ func NewBaseServer(
 			cfg.Logger.Error("failed to initialize registrant", zap.Error(err))
 			return nil, err
 		}
+
+		// Misbehavior reports are signed by the node identity and stored in the database.
+		svc.misbehaviorService = misbehavior.NewPersistentMisbehaviorService(
+			svc.ctx,
+			cfg.Logger,
+			cfg.DB,
+			svc.registrant,
+		)
 	}
 
 	// Initialize MLS validation service if needed, which is required for the API and indexer services.
@@ -282,6 +309,7 @@ func NewBaseServer(
 			indexer.WithContext(cfg.Ctx),
 			indexer.WithValidationService(svc.mlsValidation),
 			indexer.WithContractsOptions(&cfg.Options.Contracts),
+			indexer.WithSnapshotValidation(cfg.Options.MlsValidation.SnapshotValidation),
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to initialize indexer: %w", err)
@@ -344,6 +372,31 @@ func NewBaseServer(
 			return nil, err
 		}
 
+		var proofVerifier blockchain.EnvelopeProofVerifier
+		if cfg.Options.Sync.VerifyBlockchainProofs {
+			appChainClient, err := blockchain.NewRPCClient(
+				cfg.Ctx,
+				cfg.Options.Contracts.AppChain.RPCURL,
+			)
+			if err != nil {
+				cfg.Logger.Error(
+					"failed to initialize app chain client for blockchain proof verification",
+					zap.Error(err),
+				)
+				return nil, err
+			}
+
+			proofVerifier, err = blockchain.NewBlockchainProofVerifier(
+				appChainClient,
+				cfg.Options.Contracts.AppChain,
+				cfg.Options.Sync.BlockchainProofCacheSize,
+			)
+			if err != nil {
+				cfg.Logger.Error("failed to initialize blockchain proof verifier", zap.Error(err))
+				return nil, err
+			}
+		}
+
 		svc.sync, err = sync.NewSyncServer(
 			sync.WithContext(svc.ctx),
 			sync.WithLogger(cfg.Logger),
@@ -363,6 +416,8 @@ func NewBaseServer(
 				Enable:     cfg.Options.MigrationClient.Enable,
 				FromNodeID: cfg.Options.MigrationClient.FromNodeID,
 			}),
+			sync.WithMisbehaviorService(svc.misbehaviorService),
+			sync.WithBlockchainProofVerifier(proofVerifier),
 		)
 		if err != nil {
 			cfg.Logger.Error("failed to initialize sync server", zap.Error(err))
@@ -435,6 +490,7 @@ func NewBaseServer(
 			WithGenerationOthersPeriod(cfg.Options.PayerReport.GenerateReportOthersPeriod).
 			WithExpirySelfPeriod(cfg.Options.PayerReport.ExpirySelfPeriod).
 			WithExpiryOthersPeriod(cfg.Options.PayerReport.ExpiryOthersPeriod).
+			WithMisbehaviorService(svc.misbehaviorService).
 			Build()
 		if err != nil {
 			cfg.Logger.Error("failed to build worker config", zap.Error(err))
@@ -444,6 +500,44 @@ func NewBaseServer(
 		svc.reportWorkers = workers.RunWorkers(*workerConfig)
 	}
 
+	if cfg.Options.Prune.Enable {
+		archiveStore, err := archive.NewStore(cfg.Options.Prune.Archive)
+		if err != nil {
+			cfg.Logger.Error("failed to initialize prune archive store", zap.Error(err))
+			return nil, err
+		}
+
+		svc.pruneService = prune.NewPruneService(
+			svc.ctx,
+			cfg.Logger,
+			cfg.DB.Write(),
+			&cfg.Options.Prune,
+			prune.WithArchiveStore(archiveStore),
+		)
+		svc.pruneService.Start()
+	}
+
+	if cfg.Options.PayerBalance.Enable {
+		var notifier payerbalance.Notifier = payerbalance.NewLogNotifier(cfg.Logger)
+		if cfg.Options.PayerBalance.WebhookURL != "" {
+			notifier = payerbalance.NewWebhookNotifier(
+				cfg.Options.PayerBalance.WebhookURL,
+				cfg.Options.PayerBalance.WebhookSecret,
+				cfg.Options.PayerBalance.WebhookTimeout,
+			)
+		}
+
+		svc.payerBalanceWorker = payerbalance.NewWorker(
+			svc.ctx,
+			cfg.Logger,
+			cfg.DB,
+			cfg.Options.PayerBalance.Interval,
+			newBalanceForecaster(cfg),
+			notifier,
+		)
+		svc.payerBalanceWorker.Start()
+	}
+
 	return svc, nil
 }
 
@@ -493,13 +587,33 @@ func startAPIServer(
 		return fmt.Errorf("failed to build rate limiter: %w", err)
 	}
 
-	var msgRateLimiter ratelimiter.RateLimiter
+	var (
+		msgRateLimiter          ratelimiter.RateLimiter
+		operatorAuthInterceptor *server.OperatorAuthInterceptor
+	)
 	rlConfig := message.RateLimitConfig{}
 	if built != nil {
 		ratelimiter.Register(promReg)
 		msgRateLimiter = built.QueryLimiter
-		rlConfig = message.RateLimitConfig{Enabled: true}
+		rlOpts := cfg.Options.RateLimit
+		rlConfig = message.RateLimitConfig{
+			Enabled:             true,
+			Tier1Limiter:        built.Tier1QueryLimiter,
+			StreamBytesPerToken: rlOpts.StreamBytesPerToken,
+			StreamMaxWait:       rlOpts.StreamMaxWait,
+		}
 		message.SetSubscribeTrustedCIDRs(built.TrustedCIDRs)
+
+		if len(rlOpts.T1APIKeys) > 0 || rlOpts.T1JWTSecret != "" {
+			operatorVerifier, err := authn.NewOperatorVerifier(rlOpts.T1APIKeys, rlOpts.T1JWTSecret)
+			if err != nil {
+				return fmt.Errorf("failed to build operator verifier: %w", err)
+			}
+			operatorAuthInterceptor = server.NewOperatorAuthInterceptor(
+				operatorVerifier,
+				cfg.Logger,
+			)
+		}
 	}
 
 	registrationFunc := func(mux *http.ServeMux, interceptors ...connect.Interceptor) (servicePaths []string, err error) {
@@ -510,6 +624,19 @@ func startAPIServer(
 			)
 		}
 
+		if operatorAuthInterceptor != nil {
+			interceptors = append(interceptors, operatorAuthInterceptor)
+		}
+
+		var readAuthorizers []message.AuthorizeReadFn
+		if len(cfg.Options.API.ReadTopicKindPolicy) > 0 {
+			readAuthorizers = append(
+				readAuthorizers,
+				message.NewTopicKindReadAuthorizer(cfg.Options.API.ReadTopicKindPolicy),
+			)
+		}
+		readAuthorizers = append(readAuthorizers, cfg.ReadAuthorizers...)
+
 		// Register replication API.
 		replicationService, err := message.NewReplicationAPIService(
 			svc.ctx,
@@ -529,6 +656,7 @@ func startAPIServer(
 			ledgerPkg.NewLedger(cfg.Logger, cfg.DB),
 			msgRateLimiter,
 			rlConfig,
+			readAuthorizers,
 		)
 		if err != nil {
 			return nil, err
@@ -551,6 +679,8 @@ func startAPIServer(
 				cfg.Logger,
 				built.QueryLimiter,
 				built.OpensLimiter,
+				built.Tier1QueryLimiter,
+				built.Tier1OpensLimiter,
 				built.TrustedCIDRs,
 			)
 			queryHandlerOpts = []connect.HandlerOption{
@@ -613,6 +743,9 @@ func startAPIServer(
 			svc.cursorUpdater,
 			cfg.ServerVersion,
 			metadata.NewPayerInfoFetcher(cfg.DB),
+			metadata.NewFeeQuoter(cfg.DB, cfg.FeeCalculator),
+			ledgerPkg.NewLedger(cfg.Logger, cfg.DB),
+			newBalanceForecaster(cfg),
 		)
 		if err != nil {
 			return nil, err
@@ -631,12 +764,32 @@ func startAPIServer(
 
 		svc.logger.Info("metadata api registered")
 
+		// Register misbehavior API.
+		misbehaviorService, err := misbehaviorAPI.NewMisbehaviorAPIService(
+			svc.ctx,
+			cfg.Logger,
+			cfg.NodeRegistry,
+			svc.misbehaviorService,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		misbehaviorPath, misbehaviorHandler := message_apiconnect.NewMisbehaviorApiHandler(
+			misbehaviorService, handlerOpts...,
+		)
+
+		mux.Handle(misbehaviorPath, misbehaviorHandler)
+
+		svc.logger.Info("misbehavior api registered")
+
 		return []string{
 			metadata_apiconnect.MetadataApiName,
 			message_apiconnect.ReplicationApiName,
 			message_apiconnect.QueryApiName,
 			message_apiconnect.PublishApiName,
 			message_apiconnect.NotificationApiName,
+			message_apiconnect.MisbehaviorApiName,
 		}, nil
 	}
 
@@ -648,6 +801,9 @@ func startAPIServer(
 		api.WithReflection(cfg.Options.Reflection.Enable),
 		api.WithRegistrationFunc(registrationFunc),
 	}...)
+	if jwtVerifier != nil {
+		apiOpts = append(apiOpts, api.WithNodeVerifier(jwtVerifier))
+	}
 
 	svc.api, err = api.NewAPIServer(apiOpts...)
 	if err != nil {
@@ -705,6 +861,14 @@ func (s *BaseServer) Shutdown(timeout time.Duration) {
 		s.reportWorkers.Stop()
 	}
 
+	if s.pruneService != nil {
+		s.pruneService.Stop()
+	}
+
+	if s.payerBalanceWorker != nil {
+		s.payerBalanceWorker.Stop()
+	}
+
 	if s.migrator != nil {
 		if err := s.migrator.Stop(); err != nil {
 			s.logger.Error("failed to stop migator", zap.Error(err))
@@ -720,6 +884,17 @@ func (s *BaseServer) Shutdown(timeout time.Duration) {
 	s.cancel()
 }
 
+func newBalanceForecaster(cfg *BaseServerConfig) *payerbalance.Forecaster {
+	return payerbalance.NewForecaster(
+		cfg.DB,
+		cfg.Options.PayerBalance.SpendWindow,
+		payerbalance.Thresholds{
+			Warning:  cfg.Options.PayerBalance.WarningThreshold,
+			Critical: cfg.Options.PayerBalance.CriticalThreshold,
+		},
+	)
+}
+
 func getDomainSeparator(
 	ctx context.Context,
 	logger *zap.Logger,
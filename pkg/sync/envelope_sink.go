@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/hex"
 	"errors"
+	"math"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -132,15 +133,20 @@ func (s *EnvelopeSink) storeEnvelope(env *envUtils.OriginatorEnvelope) error {
 		return s.storeReservedEnvelope(ctx, env)
 	}
 
+	if isChainOriginatorID(env.OriginatorNodeID()) {
+		return s.storeChainEnvelope(ctx, env)
+	}
+
 	// Calculate the fees independently to verify the originator's calculation
 	feeSpan, _ := tracing.StartSpanFromContext(ctx, tracing.SpanSyncWorkerVerifyFees)
-	ourFeeCalculation, err := s.calculateFees(ctx, env)
+	ourBaseFee, ourCongestionFee, err := s.calculateFees(ctx, env)
 	if err != nil {
 		feeSpan.Finish(tracing.WithError(err))
 		s.logger.Error("failed to calculate fees", zap.Error(err))
 		return err
 	}
 	feeSpan.Finish()
+	ourFeeCalculation := ourBaseFee + ourCongestionFee
 	originatorsFeeCalculation := env.UnsignedOriginatorEnvelope.BaseFee() +
 		env.UnsignedOriginatorEnvelope.CongestionFee()
 
@@ -191,6 +197,7 @@ func (s *EnvelopeSink) storeEnvelope(env *envUtils.OriginatorEnvelope) error {
 			SpendPicodollars:  int64(ourFeeCalculation),
 			MessageCount:      1,
 		},
+		int64(ourCongestionFee),
 		!migrator.IsMigratorOriginatorID(env.OriginatorNodeID()),
 	)
 
@@ -214,6 +221,37 @@ func (s *EnvelopeSink) storeEnvelope(env *envUtils.OriginatorEnvelope) error {
 	return nil
 }
 
+// storeChainEnvelope stores an envelope originated on the app chain the way the indexer
+// does: it was paid for on chain, so it has no payer and never expires.
+func (s *EnvelopeSink) storeChainEnvelope(
+	ctx context.Context,
+	env *envUtils.OriginatorEnvelope,
+) error {
+	originatorBytes, err := env.Bytes()
+	if err != nil {
+		s.logger.Error("failed to marshal originator envelope", zap.Error(err))
+		return err
+	}
+
+	_, err = db.InsertGatewayEnvelopeWithChecksStandalone(
+		ctx,
+		s.db.WriteQuery(),
+		queries.InsertGatewayEnvelopeV3Params{
+			OriginatorNodeID:     int32(env.OriginatorNodeID()),
+			OriginatorSequenceID: int64(env.OriginatorSequenceID()),
+			Topic:                env.TargetTopic().Bytes(),
+			OriginatorEnvelope:   originatorBytes,
+			Expiry:               math.MaxInt64,
+		},
+	)
+	if err != nil {
+		s.logger.Error("failed to insert chain-originated envelope", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
 func (s *EnvelopeSink) storeReservedEnvelope(
 	ctx context.Context,
 	env *envUtils.OriginatorEnvelope,
@@ -277,10 +315,11 @@ func (s *EnvelopeSink) storeReservedEnvelope(
 	}
 }
 
+// calculateFees returns the base and congestion fees we would have charged for the envelope.
 func (s *EnvelopeSink) calculateFees(
 	ctx context.Context,
 	env *envUtils.OriginatorEnvelope,
-) (currency.PicoDollar, error) {
+) (currency.PicoDollar, currency.PicoDollar, error) {
 	var (
 		payerEnvelopeLength = len(env.UnsignedOriginatorEnvelope.PayerEnvelopeBytes())
 		messageTime         = utils.NsToDate(env.OriginatorNs())
@@ -293,11 +332,11 @@ func (s *EnvelopeSink) calculateFees(
 		env.UnsignedOriginatorEnvelope.PayerEnvelope.RetentionDays(),
 	)
 	if err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 
 	if migrator.IsMigratorOriginatorID(env.OriginatorNodeID()) {
-		return baseFee + 0, nil
+		return baseFee, 0, nil
 	}
 
 	// Use WriteQuery so congestion reads come from the primary DB, the same database
@@ -310,10 +349,10 @@ func (s *EnvelopeSink) calculateFees(
 		env.OriginatorNodeID(),
 	)
 	if err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 
-	return baseFee + congestionFee, nil
+	return baseFee, congestionFee, nil
 }
 
 // getPayerID resolves a payer address to its database ID.
@@ -0,0 +1,399 @@
+package sync
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/xmtp/xmtpd/pkg/blockchain"
+	"github.com/xmtp/xmtpd/pkg/db/queries"
+	envUtils "github.com/xmtp/xmtpd/pkg/envelopes"
+	"github.com/xmtp/xmtpd/pkg/metrics"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/envelopes"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/message_api"
+	"github.com/xmtp/xmtpd/pkg/registry"
+	"github.com/xmtp/xmtpd/pkg/tracing"
+	"github.com/xmtp/xmtpd/pkg/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	// gapRepairInterval is how often open sequence gaps are backfilled.
+	gapRepairInterval = 30 * time.Second
+	// gapRepairBatchSize is the number of gaps backfilled per round.
+	gapRepairBatchSize = 100
+	// gapRepairMaxEnvelopes bounds the envelopes requested per QueryEnvelopes call, so a
+	// large gap is backfilled over several rounds.
+	gapRepairMaxEnvelopes = 500
+	// gapRepairMaxAttempts is the number of attempts without progress after which a gap is
+	// abandoned, roughly an hour of rounds.
+	gapRepairMaxAttempts = 120
+)
+
+// gapRecorder records ranges of sequence IDs an originator stream skipped over.
+type gapRecorder interface {
+	recordGap(originatorID uint32, start, end uint64)
+}
+
+// recordGap persists the missing sequence IDs [start, end] of an originator, to be
+// backfilled by the gap repair loop.
+func (s *syncWorker) recordGap(originatorID uint32, start, end uint64) {
+	err := s.store.WriteQuery().InsertSyncGap(s.ctx, queries.InsertSyncGapParams{
+		OriginatorNodeID: int32(originatorID),
+		StartSequenceID:  int64(start),
+		EndSequenceID:    int64(end),
+		DetectedAtNs:     time.Now().UnixNano(),
+	})
+	if err != nil {
+		s.logger.Error(
+			"failed to record sequence gap",
+			utils.OriginatorIDField(originatorID),
+			zap.Uint64("start_sequence_id", start),
+			zap.Uint64("end_sequence_id", end),
+			zap.Error(err),
+		)
+	}
+}
+
+// repairGaps periodically backfills the open sequence gaps until the worker shuts down.
+func (s *syncWorker) repairGaps() {
+	tracing.GoPanicWrap(
+		s.ctx,
+		&s.wg,
+		"sync-gap-repair",
+		func(ctx context.Context) {
+			// The sink is only used to store envelopes synchronously, so that a gap is never
+			// shrunk before the envelopes filling it are written.
+			sink := newEnvelopeSink(
+				ctx,
+				s.store,
+				s.logger,
+				s.feeCalculator,
+				s.payerReportStore,
+				s.payerReportDomainSeparator,
+				nil,
+				1*time.Second,
+			)
+			reported := make(map[uint32]struct{})
+
+			ticker := time.NewTicker(gapRepairInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					s.repairGapsOnce(ctx, sink)
+					s.emitGapStats(ctx, reported)
+				}
+			}
+		})
+}
+
+func (s *syncWorker) repairGapsOnce(ctx context.Context, sink *EnvelopeSink) {
+	gaps, err := s.store.ReadQuery().SelectSyncGaps(ctx, gapRepairBatchSize)
+	if err != nil {
+		s.logger.Error("failed to select sequence gaps", zap.Error(err))
+		return
+	}
+
+	for _, gap := range gaps {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := s.repairGap(ctx, gap, sink); err != nil {
+			s.logger.Warn(
+				"failed to backfill sequence gap",
+				utils.OriginatorIDField(uint32(gap.OriginatorNodeID)),
+				zap.Int64("start_sequence_id", gap.StartSequenceID),
+				zap.Int64("end_sequence_id", gap.EndSequenceID),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// repairGap backfills as much of gap as one QueryEnvelopes call allows. Attempts alternate
+// between the originator and the other healthy nodes in turn, so a gap is repaired from
+// a peer while the originator is down, and from the originator once it is back.
+func (s *syncWorker) repairGap(
+	ctx context.Context,
+	gap queries.SyncGap,
+	sink *EnvelopeSink,
+) error {
+	originatorID := uint32(gap.OriginatorNodeID)
+	key := queries.IncrementSyncGapAttemptsParams{
+		OriginatorNodeID: gap.OriginatorNodeID,
+		StartSequenceID:  gap.StartSequenceID,
+	}
+
+	originator, source, err := s.selectGapSource(originatorID, int(gap.Attempts))
+	if err != nil {
+		return errors.Join(err, s.recordFailedGapAttempt(ctx, gap))
+	}
+
+	next, err := s.backfillGapFrom(ctx, originator, source, gap, sink)
+	if next == uint64(gap.StartSequenceID) {
+		if incErr := s.recordFailedGapAttempt(ctx, gap); incErr != nil {
+			return errors.Join(err, incErr)
+		}
+		if err == nil {
+			err = fmt.Errorf("node %d is missing sequence id %d", source.NodeID, next)
+		}
+		return err
+	}
+
+	metrics.EmitSyncGapEnvelopesBackfilled(originatorID, int(next-uint64(gap.StartSequenceID)))
+	if next > uint64(gap.EndSequenceID) {
+		s.logger.Info(
+			"backfilled sequence gap",
+			utils.OriginatorIDField(originatorID),
+			utils.SourceNodeIDField(source.NodeID),
+			zap.Int64("start_sequence_id", gap.StartSequenceID),
+			zap.Int64("end_sequence_id", gap.EndSequenceID),
+		)
+		return errors.Join(err, s.store.WriteQuery().DeleteSyncGap(
+			ctx,
+			queries.DeleteSyncGapParams(key),
+		))
+	}
+
+	return errors.Join(err, s.store.WriteQuery().UpdateSyncGapStart(
+		ctx,
+		queries.UpdateSyncGapStartParams{
+			NewStartSequenceID: int64(next),
+			OriginatorNodeID:   gap.OriginatorNodeID,
+			StartSequenceID:    gap.StartSequenceID,
+		},
+	))
+}
+
+// recordFailedGapAttempt counts an attempt at gap that made no progress, and abandons the gap
+// once it has failed gapRepairMaxAttempts times in a row.
+func (s *syncWorker) recordFailedGapAttempt(ctx context.Context, gap queries.SyncGap) error {
+	err := s.store.WriteQuery().IncrementSyncGapAttempts(
+		ctx,
+		queries.IncrementSyncGapAttemptsParams{
+			OriginatorNodeID: gap.OriginatorNodeID,
+			StartSequenceID:  gap.StartSequenceID,
+		},
+	)
+	if err != nil || gap.Attempts+1 < gapRepairMaxAttempts {
+		return err
+	}
+
+	s.logger.Warn(
+		"abandoning sequence gap",
+		utils.OriginatorIDField(uint32(gap.OriginatorNodeID)),
+		zap.Int64("start_sequence_id", gap.StartSequenceID),
+		zap.Int64("end_sequence_id", gap.EndSequenceID),
+		zap.Int32("attempts", gap.Attempts+1),
+	)
+	return s.store.WriteQuery().AbandonSyncGap(ctx, queries.AbandonSyncGapParams{
+		AbandonedAtNs:    sql.NullInt64{Int64: time.Now().UnixNano(), Valid: true},
+		OriginatorNodeID: gap.OriginatorNodeID,
+		StartSequenceID:  gap.StartSequenceID,
+	})
+}
+
+// selectGapSource returns the registered originator of a gap and the node to backfill it
+// from. Chain-originated envelopes have no registered originator and are backfilled from the
+// other nodes in turn.
+func (s *syncWorker) selectGapSource(
+	originatorID uint32,
+	attempt int,
+) (*registry.Node, registry.Node, error) {
+	if isChainOriginatorID(originatorID) {
+		peer, ok := s.selectRelayPeer(originatorID, attempt)
+		if !ok {
+			return nil, registry.Node{}, errors.New("no node to backfill envelopes from")
+		}
+		return nil, peer, nil
+	}
+
+	originator, err := s.nodeRegistry.GetNode(originatorID)
+	if err != nil {
+		return nil, registry.Node{}, err
+	}
+
+	source := *originator
+	if attempt%2 == 1 {
+		if peer, ok := s.selectRelayPeer(originatorID, attempt/2); ok {
+			source = peer
+		}
+	}
+	return originator, source, nil
+}
+
+func (s *syncWorker) backfillGapFrom(
+	ctx context.Context,
+	originator *registry.Node,
+	source registry.Node,
+	gap queries.SyncGap,
+	sink *EnvelopeSink,
+) (uint64, error) {
+	start := uint64(gap.StartSequenceID)
+
+	conn, err := s.connectToNode(source)
+	if err != nil {
+		return start, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	return backfillGap(
+		ctx,
+		message_api.NewReplicationApiClient(conn),
+		originator,
+		s.proofVerifier,
+		gap,
+		sink.storeEnvelope,
+	)
+}
+
+// backfillGap queries client for the envelopes of gap and stores them in sequence ID
+// order. Envelopes must be signed by originator, whichever node serves them, or carry a
+// valid blockchain proof if they were originated on the app chain. It returns
+// the first sequence ID of the gap that is still missing, which is past the end of the
+// gap once it is filled.
+func backfillGap(
+	ctx context.Context,
+	client message_api.ReplicationApiClient,
+	originator *registry.Node,
+	proofVerifier blockchain.EnvelopeProofVerifier,
+	gap queries.SyncGap,
+	store func(*envUtils.OriginatorEnvelope) error,
+) (uint64, error) {
+	var (
+		originatorID = uint32(gap.OriginatorNodeID)
+		start        = uint64(gap.StartSequenceID)
+		end          = uint64(gap.EndSequenceID)
+		next         = start
+	)
+
+	resp, err := client.QueryEnvelopes(ctx, &message_api.QueryEnvelopesRequest{
+		Query: &message_api.EnvelopesQuery{
+			OriginatorNodeIds: []uint32{originatorID},
+			LastSeen: &envelopes.Cursor{
+				NodeIdToSequenceId: map[uint32]uint64{originatorID: start - 1},
+			},
+		},
+		Limit: uint32(min(end-start+1, gapRepairMaxEnvelopes)),
+	})
+	if err != nil {
+		return next, err
+	}
+
+	for _, envProto := range resp.GetEnvelopes() {
+		env, err := validateBackfilledEnvelope(
+			ctx,
+			originatorID,
+			originator,
+			proofVerifier,
+			envProto,
+		)
+		if err != nil {
+			return next, err
+		}
+
+		seqID := env.OriginatorSequenceID()
+		if seqID < next {
+			continue
+		}
+		if seqID > next {
+			// The node does not have the next envelope either.
+			return next, nil
+		}
+
+		if err := store(env); err != nil {
+			return next, err
+		}
+		next++
+		if next > end {
+			break
+		}
+	}
+
+	return next, nil
+}
+
+// validateBackfilledEnvelope performs the static validation of the sync stream on an
+// envelope backfilled into a gap of originatorID's sequence IDs. Envelopes of registered
+// originators must be signed by originator. Chain-originated envelopes have no originator,
+// and must carry a valid blockchain proof instead.
+func validateBackfilledEnvelope(
+	ctx context.Context,
+	originatorID uint32,
+	originator *registry.Node,
+	proofVerifier blockchain.EnvelopeProofVerifier,
+	envProto *envelopes.OriginatorEnvelope,
+) (*envUtils.OriginatorEnvelope, error) {
+	env, err := envUtils.NewOriginatorEnvelope(envProto)
+	if err != nil {
+		return nil, err
+	}
+
+	if env.OriginatorNodeID() != originatorID {
+		return nil, fmt.Errorf(
+			"invalid envelope originator: got=%d want=%d",
+			env.OriginatorNodeID(),
+			originatorID,
+		)
+	}
+
+	if !env.UnsignedOriginatorEnvelope.PayerEnvelope.ClientEnvelope.TopicMatchesPayload() {
+		return nil, errors.New("envelope topic does not match payload")
+	}
+
+	if isChainOriginatorID(originatorID) {
+		if err := verifyBlockchainProof(ctx, proofVerifier, env); err != nil {
+			return nil, err
+		}
+		return env, nil
+	}
+
+	if err := verifyOriginatorSignature(originator, env); err != nil {
+		return nil, err
+	}
+
+	if _, err := env.UnsignedOriginatorEnvelope.PayerEnvelope.RecoverSigner(); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// emitGapStats exports the open and abandoned gaps of every originator, clearing the series
+// of the originators whose gaps were all backfilled since the last round.
+func (s *syncWorker) emitGapStats(ctx context.Context, reported map[uint32]struct{}) {
+	stats, err := s.store.ReadQuery().SelectSyncGapStats(ctx)
+	if err != nil {
+		s.logger.Error("failed to select sequence gap stats", zap.Error(err))
+		return
+	}
+
+	now := time.Now()
+	current := make(map[uint32]struct{}, len(stats))
+	for _, stat := range stats {
+		originatorID := uint32(stat.OriginatorNodeID)
+		current[originatorID] = struct{}{}
+		var oldestAge time.Duration
+		if stat.OpenGaps > 0 {
+			oldestAge = now.Sub(time.Unix(0, stat.OldestDetectedAtNs))
+		}
+		metrics.EmitSyncGapStats(originatorID, stat.OpenGaps, stat.AbandonedGaps, oldestAge)
+	}
+
+	for originatorID := range reported {
+		if _, ok := current[originatorID]; !ok {
+			metrics.ClearSyncGapStats(originatorID)
+			delete(reported, originatorID)
+		}
+	}
+	for originatorID := range current {
+		reported[originatorID] = struct{}{}
+	}
+}
@@ -0,0 +1,180 @@
+package sync
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/blockchain"
+	"github.com/xmtp/xmtpd/pkg/constants"
+	"github.com/xmtp/xmtpd/pkg/db/queries"
+	envUtils "github.com/xmtp/xmtpd/pkg/envelopes"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/envelopes"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/message_api"
+	"github.com/xmtp/xmtpd/pkg/testutils"
+	envelopeTestUtils "github.com/xmtp/xmtpd/pkg/testutils/envelopes"
+	messageApiMocks "github.com/xmtp/xmtpd/pkg/testutils/mocks/message_api"
+	registryTestUtils "github.com/xmtp/xmtpd/pkg/testutils/registry"
+)
+
+func mockGapQuery(
+	t *testing.T,
+	originatorID uint32,
+	lastSeen uint64,
+	limit uint32,
+	envs ...*envelopes.OriginatorEnvelope,
+) *messageApiMocks.MockReplicationApiClient {
+	client := messageApiMocks.NewMockReplicationApiClient(t)
+	client.EXPECT().
+		QueryEnvelopes(
+			mock.Anything,
+			mock.MatchedBy(func(req *message_api.QueryEnvelopesRequest) bool {
+				q := req.GetQuery()
+				return len(q.GetOriginatorNodeIds()) == 1 &&
+					q.GetOriginatorNodeIds()[0] == originatorID &&
+					q.GetLastSeen().GetNodeIdToSequenceId()[originatorID] == lastSeen &&
+					req.GetLimit() == limit
+			}),
+		).
+		Return(&message_api.QueryEnvelopesResponse{Envelopes: envs}, nil).
+		Once()
+	return client
+}
+
+func collectStored(stored *[]uint64) func(*envUtils.OriginatorEnvelope) error {
+	return func(env *envUtils.OriginatorEnvelope) error {
+		*stored = append(*stored, env.OriginatorSequenceID())
+		return nil
+	}
+}
+
+func TestBackfillGapFillsRange(t *testing.T) {
+	nodeKey := testutils.RandomPrivateKey(t)
+	nodeID := uint32(200)
+	node := registryTestUtils.CreateNode(nodeID, 999, nodeKey)
+
+	client := mockGapQuery(t, nodeID, 4, 3,
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, nodeID, 5),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, nodeID, 6),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, nodeID, 7),
+	)
+
+	var stored []uint64
+	next, err := backfillGap(
+		t.Context(),
+		client,
+		&node,
+		nil,
+		queries.SyncGap{OriginatorNodeID: int32(nodeID), StartSequenceID: 5, EndSequenceID: 7},
+		collectStored(&stored),
+	)
+	require.NoError(t, err)
+	require.Equal(t, uint64(8), next)
+	require.Equal(t, []uint64{5, 6, 7}, stored)
+}
+
+func TestBackfillGapStopsAtMissingEnvelope(t *testing.T) {
+	nodeKey := testutils.RandomPrivateKey(t)
+	nodeID := uint32(200)
+	node := registryTestUtils.CreateNode(nodeID, 999, nodeKey)
+
+	// The node serving the gap is missing sequence ID 6 as well.
+	client := mockGapQuery(t, nodeID, 4, 4,
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, nodeID, 5),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, nodeID, 7),
+	)
+
+	var stored []uint64
+	next, err := backfillGap(
+		t.Context(),
+		client,
+		&node,
+		nil,
+		queries.SyncGap{OriginatorNodeID: int32(nodeID), StartSequenceID: 5, EndSequenceID: 8},
+		collectStored(&stored),
+	)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), next)
+	require.Equal(t, []uint64{5}, stored)
+}
+
+func TestBackfillGapRejectsEnvelopeNotSignedByOriginator(t *testing.T) {
+	nodeKey := testutils.RandomPrivateKey(t)
+	nodeID := uint32(200)
+	node := registryTestUtils.CreateNode(nodeID, 999, nodeKey)
+
+	client := mockGapQuery(t, nodeID, 4, 2,
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, nodeID, 5),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(
+			t,
+			testutils.RandomPrivateKey(t),
+			nodeID,
+			6,
+		),
+	)
+
+	var stored []uint64
+	next, err := backfillGap(
+		t.Context(),
+		client,
+		&node,
+		nil,
+		queries.SyncGap{OriginatorNodeID: int32(nodeID), StartSequenceID: 5, EndSequenceID: 6},
+		collectStored(&stored),
+	)
+	require.Error(t, err)
+	require.Equal(t, uint64(6), next)
+	require.Equal(t, []uint64{5}, stored)
+}
+
+func TestBackfillGapBoundsQuery(t *testing.T) {
+	nodeKey := testutils.RandomPrivateKey(t)
+	nodeID := uint32(200)
+	node := registryTestUtils.CreateNode(nodeID, 999, nodeKey)
+
+	client := mockGapQuery(t, nodeID, 0, gapRepairMaxEnvelopes,
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, nodeID, 1),
+	)
+
+	var stored []uint64
+	next, err := backfillGap(
+		t.Context(),
+		client,
+		&node,
+		nil,
+		queries.SyncGap{OriginatorNodeID: int32(nodeID), StartSequenceID: 1, EndSequenceID: 10_000},
+		collectStored(&stored),
+	)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), next)
+}
+
+func TestBackfillGapVerifiesChainOriginatedEnvelopes(t *testing.T) {
+	originatorID := uint32(constants.GroupMessageOriginatorID)
+
+	client := mockGapQuery(t, originatorID, 4, 2,
+		newChainOriginatedEnvelope(t, originatorID, 5),
+		newChainOriginatedEnvelope(t, originatorID, 6),
+	)
+	verifier := &fakeProofVerifier{
+		errs: []error{nil, fmt.Errorf("%w: bad", blockchain.ErrInvalidBlockchainProof)},
+	}
+
+	var stored []uint64
+	next, err := backfillGap(
+		t.Context(),
+		client,
+		nil,
+		verifier,
+		queries.SyncGap{
+			OriginatorNodeID: int32(originatorID),
+			StartSequenceID:  5,
+			EndSequenceID:    6,
+		},
+		collectStored(&stored),
+	)
+	require.ErrorIs(t, err, blockchain.ErrInvalidBlockchainProof)
+	require.Equal(t, uint64(6), next)
+	require.Equal(t, []uint64{5}, stored)
+}
@@ -1,6 +1,7 @@
 package sync
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"errors"
@@ -11,9 +12,15 @@ import (
 	"sync"
 
 	"github.com/cenkalti/backoff/v5"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/xmtp/xmtpd/pkg/blockchain"
+	"github.com/xmtp/xmtpd/pkg/constants"
 	envUtils "github.com/xmtp/xmtpd/pkg/envelopes"
 	"github.com/xmtp/xmtpd/pkg/metrics"
+	"github.com/xmtp/xmtpd/pkg/migrator"
+	"github.com/xmtp/xmtpd/pkg/misbehavior"
 	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/envelopes"
+	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/message_api"
 	"github.com/xmtp/xmtpd/pkg/registry"
 	"github.com/xmtp/xmtpd/pkg/tracing"
 	"github.com/xmtp/xmtpd/pkg/utils"
@@ -24,33 +31,57 @@ type originatorStream struct {
 	ctx                  context.Context
 	logger               *zap.Logger
 	node                 *registry.Node
+	source               *registry.Node
 	lastSequenceIdsMu    sync.Mutex
 	lastSequenceIds      map[uint32]uint64
 	permittedOriginators map[uint32]struct{}
 	stream               envelopeRecvStream
 	writeQueue           chan *envUtils.OriginatorEnvelope
+	misbehaviorService   misbehavior.MisbehaviorService
+	gaps                 gapRecorder
+	proofVerifier        blockchain.EnvelopeProofVerifier
+	// lastEnvelopes holds the latest envelope received for each originator on this stream.
+	// It is the evidence used when the node later sends a conflicting or out-of-order envelope.
+	lastEnvelopes map[uint32]*envUtils.OriginatorEnvelope
 }
 
+// newOriginatorStream creates a stream of node's envelopes received from source. The source is
+// node itself, or another node relaying node's envelopes while node is unreachable. Envelopes
+// are verified against node's signing key either way.
 func newOriginatorStream(
 	ctx context.Context,
 	logger *zap.Logger,
 	node *registry.Node,
+	source *registry.Node,
 	lastSequenceIds map[uint32]uint64,
 	permittedOriginators map[uint32]struct{},
 	stream envelopeRecvStream,
 	writeQueue chan *envUtils.OriginatorEnvelope,
+	misbehaviorService misbehavior.MisbehaviorService,
+	gaps gapRecorder,
+	proofVerifier blockchain.EnvelopeProofVerifier,
 ) *originatorStream {
+	logger = logger.With(
+		utils.OriginatorIDField(node.NodeID),
+		utils.NodeHTTPAddressField(source.HTTPAddress),
+	)
+	if source.NodeID != node.NodeID {
+		logger = logger.With(utils.SourceNodeIDField(source.NodeID))
+	}
+
 	return &originatorStream{
-		ctx: ctx,
-		logger: logger.With(
-			utils.OriginatorIDField(node.NodeID),
-			utils.NodeHTTPAddressField(node.HTTPAddress),
-		),
+		ctx:                  ctx,
+		logger:               logger,
 		node:                 node,
+		source:               source,
 		lastSequenceIds:      lastSequenceIds,
 		permittedOriginators: permittedOriginators,
 		stream:               stream,
 		writeQueue:           writeQueue,
+		misbehaviorService:   misbehaviorService,
+		gaps:                 gaps,
+		proofVerifier:        proofVerifier,
+		lastEnvelopes:        make(map[uint32]*envUtils.OriginatorEnvelope),
 	}
 }
 
@@ -92,7 +123,7 @@ func (s *originatorStream) listen() error {
 
 			// Create span for processing this batch of envelopes
 			batchSpan, batchCtx := tracing.StartSpanFromContext(s.ctx, tracing.SpanSyncReceiveBatch)
-			tracing.SpanTag(batchSpan, tracing.TagSourceNode, s.node.NodeID)
+			tracing.SpanTag(batchSpan, tracing.TagSourceNode, s.source.NodeID)
 			tracing.SpanTag(batchSpan, tracing.TagNumEnvelopes, len(envs))
 
 			s.logger.Debug(
@@ -163,7 +194,7 @@ func (s *originatorStream) validateEnvelope(
 ) (*envUtils.OriginatorEnvelope, error) {
 	// Create span as child of the batch span
 	span, _ := tracing.StartSpanFromContext(ctx, tracing.SpanSyncValidateEnvelope)
-	tracing.SpanTag(span, tracing.TagSourceNode, s.node.NodeID)
+	tracing.SpanTag(span, tracing.TagSourceNode, s.source.NodeID)
 
 	env, err := envUtils.NewOriginatorEnvelope(envProto)
 	if err != nil {
@@ -177,7 +208,6 @@ func (s *originatorStream) validateEnvelope(
 	tracing.SpanTag(span, tracing.TagSequenceID, env.OriginatorSequenceID())
 	tracing.SpanTag(span, tracing.TagTopic, hex.EncodeToString(env.TargetTopic().Bytes()))
 
-	// TODO:(nm) Handle fetching envelopes from other nodes
 	originatorID := env.OriginatorNodeID()
 	seqID := env.OriginatorSequenceID()
 	if _, permitted := s.permittedOriginators[originatorID]; !permitted {
@@ -205,6 +235,62 @@ func (s *originatorStream) validateEnvelope(
 		return nil, err
 	}
 
+	// Envelopes originated on the app chain carry a blockchain proof instead of signatures.
+	chainOriginated := isChainOriginatorID(originatorID)
+	if chainOriginated {
+		if err = s.verifyBlockchainProof(ctx, env); err != nil {
+			metrics.EmitSyncOriginatorErrorMessages(s.node.NodeID, 1)
+			s.logger.Error("invalid blockchain proof", zap.Error(err))
+			span.Finish(tracing.WithError(err))
+			return nil, err
+		}
+	} else if err = verifyOriginatorSignature(s.node, env); err != nil {
+		// Envelopes must be signed by the node we are syncing, even when relayed by another
+		// node. This includes migrated envelopes, which are signed by the migrating node under
+		// the migrator originator IDs.
+		metrics.EmitSyncOriginatorErrorMessages(s.node.NodeID, 1)
+		s.logger.Error("invalid originator signature", zap.Error(err))
+		// A relay can tamper with an envelope, so a bad signature only proves the
+		// originator misbehaved when received from the originator itself.
+		if !s.relayed() {
+			s.reportSafetyFailure(message_api.Misbehavior_MISBEHAVIOR_INVALID_PAYLOAD, env)
+		}
+		span.Finish(tracing.WithError(err))
+		return nil, err
+	}
+
+	if !env.UnsignedOriginatorEnvelope.PayerEnvelope.ClientEnvelope.TopicMatchesPayload() {
+		err = errors.New("envelope topic does not match payload")
+		metrics.EmitSyncOriginatorErrorMessages(s.node.NodeID, 1)
+		s.logger.Error("received envelope with invalid payload", zap.Error(err))
+		s.reportSafetyFailure(message_api.Misbehavior_MISBEHAVIOR_INVALID_PAYLOAD, env)
+		span.Finish(tracing.WithError(err))
+		return nil, err
+	}
+
+	// A node that signs two different envelopes with the same sequence ID is equivocating.
+	lastEnv := s.lastEnvelopes[originatorID]
+	if lastEnv != nil && lastEnv.OriginatorSequenceID() == seqID &&
+		!bytes.Equal(
+			lastEnv.Proto().GetUnsignedOriginatorEnvelope(),
+			env.Proto().GetUnsignedOriginatorEnvelope(),
+		) {
+		err = fmt.Errorf("conflicting envelope for sequence id %d", seqID)
+		metrics.EmitSyncOriginatorErrorMessages(s.node.NodeID, 1)
+		s.logger.Error(
+			"received conflicting envelope for an existing sequence id",
+			utils.OriginatorIDField(originatorID),
+			utils.SequenceIDField(int64(seqID)),
+		)
+		s.reportSafetyFailure(
+			message_api.Misbehavior_MISBEHAVIOR_DUPLICATE_SEQUENCE_ID,
+			lastEnv,
+			env,
+		)
+		span.Finish(tracing.WithError(err))
+		return nil, err
+	}
+
 	metrics.EmitSyncLastSeenOriginatorSequenceID(env.OriginatorNodeID(), env.OriginatorSequenceID())
 	metrics.EmitSyncOriginatorReceivedMessagesCount(env.OriginatorNodeID(), 1)
 
@@ -219,6 +305,10 @@ func (s *originatorStream) validateEnvelope(
 	}
 	s.lastSequenceIdsMu.Unlock()
 
+	if lastEnv == nil || seqID > lastEnv.OriginatorSequenceID() {
+		s.lastEnvelopes[originatorID] = env
+	}
+
 	expectedSID := lastSID + 1
 
 	if seqID != expectedSID {
@@ -233,6 +323,17 @@ func (s *originatorStream) validateEnvelope(
 			)
 
 			tracing.SpanTag(span, tracing.TagOutOfOrder, true)
+
+			// Only envelopes received on this stream prove the ordering. The last sequence ID
+			// may come from the database, in which case there is nothing to report. A relay
+			// chooses the order it sends envelopes in, so relayed streams prove nothing either.
+			if lastEnv != nil && lastEnv.OriginatorSequenceID() > seqID && !s.relayed() {
+				s.reportSafetyFailure(
+					message_api.Misbehavior_MISBEHAVIOR_OUT_OF_ORDER,
+					lastEnv,
+					env,
+				)
+			}
 		} else {
 			s.logger.Info(
 				"envelope gap detected",
@@ -242,18 +343,139 @@ func (s *originatorStream) validateEnvelope(
 			)
 
 			tracing.SpanTag(span, tracing.TagGapDetected, true)
+
+			// Without a last sequence ID, the originator may have pruned the envelopes
+			// before it, so there is nothing to backfill. Migrated envelopes may be sparse.
+			if lastSID > 0 && s.gaps != nil && !migrator.IsMigratorOriginatorID(originatorID) {
+				s.gaps.recordGap(originatorID, expectedSID, seqID-1)
+			}
 		}
 	}
 
-	// Validate that there is a valid payer signature
-	_, err = env.UnsignedOriginatorEnvelope.PayerEnvelope.RecoverSigner()
-	if err != nil {
-		metrics.EmitSyncOriginatorErrorMessages(s.node.NodeID, 1)
-		s.logger.Error("failed to recover payer address", zap.Error(err))
-		span.Finish(tracing.WithError(err))
-		return nil, err
+	// Validate that there is a valid payer signature. Chain-originated envelopes are paid for
+	// on chain and have none.
+	if !chainOriginated {
+		_, err = env.UnsignedOriginatorEnvelope.PayerEnvelope.RecoverSigner()
+		if err != nil {
+			metrics.EmitSyncOriginatorErrorMessages(s.node.NodeID, 1)
+			s.logger.Error("failed to recover payer address", zap.Error(err))
+			span.Finish(tracing.WithError(err))
+			return nil, err
+		}
 	}
 
 	span.Finish()
 	return env, nil
 }
+
+// relayed reports whether the stream's envelopes are received from a node other than
+// their originator.
+func (s *originatorStream) relayed() bool {
+	return s.source.NodeID != s.node.NodeID
+}
+
+// verifyBlockchainProof verifies the blockchain proof of a chain-originated envelope received
+// on the stream. Failing to read the app chain says nothing about the proof, so it is retried
+// until the stream shuts down rather than dropping a valid envelope.
+func (s *originatorStream) verifyBlockchainProof(
+	ctx context.Context,
+	env *envUtils.OriginatorEnvelope,
+) error {
+	_, err := backoff.Retry(
+		ctx,
+		func() (struct{}, error) {
+			err := verifyBlockchainProof(ctx, s.proofVerifier, env)
+			if err != nil && !isRetryableProofError(err) {
+				return struct{}{}, backoff.Permanent(err)
+			}
+			if err != nil {
+				s.logger.Warn("failed to verify blockchain proof, retrying", zap.Error(err))
+			}
+			return struct{}{}, err
+		},
+		backoff.WithBackOff(backoff.NewExponentialBackOff()),
+		backoff.WithMaxElapsedTime(0),
+	)
+	return err
+}
+
+// isChainOriginatorID reports whether originatorID is one of the originator IDs of envelopes
+// indexed from the app chain.
+func isChainOriginatorID(originatorID uint32) bool {
+	return originatorID == constants.GroupMessageOriginatorID ||
+		originatorID == constants.IdentityUpdateOriginatorID
+}
+
+// isRetryableProofError reports whether a proof verification error was caused by failing to
+// read the app chain, rather than by the proof itself.
+func isRetryableProofError(err error) bool {
+	return !errors.Is(err, errProofVerificationDisabled) &&
+		!errors.Is(err, blockchain.ErrInvalidBlockchainProof)
+}
+
+var errProofVerificationDisabled = errors.New(
+	"envelope has a blockchain proof, but proof verification is disabled",
+)
+
+// verifyBlockchainProof verifies the blockchain proof of a chain-originated envelope. Without
+// a verifier, the envelope cannot be trusted and is rejected.
+func verifyBlockchainProof(
+	ctx context.Context,
+	verifier blockchain.EnvelopeProofVerifier,
+	env *envUtils.OriginatorEnvelope,
+) error {
+	if verifier == nil {
+		return errProofVerificationDisabled
+	}
+	return verifier.VerifyEnvelope(ctx, env)
+}
+
+// verifyOriginatorSignature checks that env is signed by node's registered signing key.
+func verifyOriginatorSignature(node *registry.Node, env *envUtils.OriginatorEnvelope) error {
+	if node.SigningKey == nil {
+		return fmt.Errorf("node %d has no signing key", node.NodeID)
+	}
+
+	signer, err := env.RecoverSigner()
+	if err != nil {
+		return err
+	}
+
+	if *signer != ethcrypto.PubkeyToAddress(*node.SigningKey) {
+		return fmt.Errorf(
+			"envelope signed by %s, not by node %d",
+			signer.Hex(),
+			node.NodeID,
+		)
+	}
+
+	return nil
+}
+
+// reportSafetyFailure files a misbehavior report against the node this stream is syncing from.
+// Envelopes from the migrator originators are only logged, as they are not attributable
+// to the node they were synced from.
+func (s *originatorStream) reportSafetyFailure(
+	misbehaviorType message_api.Misbehavior,
+	envs ...*envUtils.OriginatorEnvelope,
+) {
+	for _, env := range envs {
+		if env.OriginatorNodeID() != s.node.NodeID {
+			return
+		}
+	}
+
+	report, err := misbehavior.NewSafetyFailureReport(s.node.NodeID, misbehaviorType, true, envs)
+	if err != nil {
+		s.logger.Error("failed to build misbehavior report", zap.Error(err))
+		return
+	}
+
+	if err := s.misbehaviorService.SafetyFailure(report); err != nil {
+		s.logger.Error(
+			"failed to report misbehavior",
+			zap.String("misbehavior_type", misbehaviorType.String()),
+			zap.Error(err),
+		)
+	}
+}
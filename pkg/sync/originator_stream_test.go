@@ -2,6 +2,8 @@ package sync
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"sync"
 	"testing"
@@ -11,11 +13,14 @@ import (
 	"go.uber.org/zap/zaptest/observer"
 
 	"github.com/xmtp/xmtpd/pkg/migrator"
+	"github.com/xmtp/xmtpd/pkg/misbehavior"
 
 	"github.com/xmtp/xmtpd/pkg/db"
 
 	"github.com/cenkalti/backoff/v5"
 	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/blockchain"
+	"github.com/xmtp/xmtpd/pkg/constants"
 	"github.com/xmtp/xmtpd/pkg/db/queries"
 	envUtils "github.com/xmtp/xmtpd/pkg/envelopes"
 	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/envelopes"
@@ -27,6 +32,7 @@ import (
 	messageApiMocks "github.com/xmtp/xmtpd/pkg/testutils/mocks/message_api"
 	payerreportMocks "github.com/xmtp/xmtpd/pkg/testutils/mocks/payerreport"
 	registryTestUtils "github.com/xmtp/xmtpd/pkg/testutils/registry"
+	"github.com/xmtp/xmtpd/pkg/topic"
 )
 
 var payerReportDomainSeparator = testutils.RandomDomainSeparator()
@@ -101,10 +107,14 @@ func newTestOriginatorStream(
 		t.Context(),
 		log,
 		node,
+		node,
 		lastSequenceID,
 		permittedOriginators,
 		stream,
 		writeQueue,
+		misbehavior.NewLoggingMisbehaviorService(log),
+		nil,
+		nil,
 	)
 }
 
@@ -145,12 +155,13 @@ func tryGetAllMessagesForOriginator(
 }
 
 func TestSyncWorkerSuccess(t *testing.T) {
+	nodeKey := testutils.RandomPrivateKey(t)
 	nodeID := uint32(200)
 	sequenceID := uint64(1)
-	envelope := envelopeTestUtils.CreateOriginatorEnvelope(t, nodeID, sequenceID)
+	envelope := envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, nodeID, sequenceID)
 	stream := mockSubscriptionOnePage(t, []*envelopes.OriginatorEnvelope{envelope})
 
-	node := registryTestUtils.CreateNode(nodeID, 999, testutils.RandomPrivateKey(t))
+	node := registryTestUtils.CreateNode(nodeID, 999, nodeKey)
 
 	writeQueue := make(chan *envUtils.OriginatorEnvelope, 10)
 	defer close(writeQueue)
@@ -250,24 +261,34 @@ func newTestOriginatorStreamWithPermitted(
 		t.Context(),
 		log,
 		node,
+		node,
 		lastSequenceIds,
 		permitted,
 		stream,
 		writeQueue,
+		misbehavior.NewLoggingMisbehaviorService(log),
+		nil,
+		nil,
 	)
 }
 
 func TestSyncWorkerRejectsEnvelopeFromUnpermittedOriginator(t *testing.T) {
+	nodeKey := testutils.RandomPrivateKey(t)
 	// Node we are syncing *from*
 	nodeID := uint32(200)
 
 	// Envelope claims it was authored by a different originator
 	badOriginatorID := uint32(201)
 	sequenceID := uint64(1)
-	envelope := envelopeTestUtils.CreateOriginatorEnvelope(t, badOriginatorID, sequenceID)
+	envelope := envelopeTestUtils.CreateSignedOriginatorEnvelope(
+		t,
+		nodeKey,
+		badOriginatorID,
+		sequenceID,
+	)
 
 	stream := mockSubscriptionOnePage(t, []*envelopes.OriginatorEnvelope{envelope})
-	node := registryTestUtils.CreateNode(nodeID, 999, testutils.RandomPrivateKey(t))
+	node := registryTestUtils.CreateNode(nodeID, 999, nodeKey)
 
 	writeQueue := make(chan *envUtils.OriginatorEnvelope, 10)
 	defer close(writeQueue)
@@ -315,16 +336,22 @@ func TestSyncWorkerRejectsEnvelopeFromUnpermittedOriginator(t *testing.T) {
 }
 
 func TestSyncWorkerAcceptsEnvelopeFromPermittedOriginator(t *testing.T) {
+	nodeKey := testutils.RandomPrivateKey(t)
 	// Node we are syncing *from*
 	nodeID := uint32(200)
 
 	// Envelope comes from an additional permitted originator
 	otherPermittedID := migrator.KeyPackagesOriginatorID
 	sequenceID := uint64(1)
-	envelope := envelopeTestUtils.CreateOriginatorEnvelope(t, otherPermittedID, sequenceID)
+	envelope := envelopeTestUtils.CreateSignedOriginatorEnvelope(
+		t,
+		nodeKey,
+		otherPermittedID,
+		sequenceID,
+	)
 
 	stream := mockSubscriptionOnePage(t, []*envelopes.OriginatorEnvelope{envelope})
-	node := registryTestUtils.CreateNode(nodeID, 999, testutils.RandomPrivateKey(t))
+	node := registryTestUtils.CreateNode(nodeID, 999, nodeKey)
 
 	writeQueue := make(chan *envUtils.OriginatorEnvelope, 10)
 	defer close(writeQueue)
@@ -360,14 +387,15 @@ func TestSyncWorkerAcceptsEnvelopeFromPermittedOriginator(t *testing.T) {
 }
 
 func TestSyncWorkerGapStillAdvancesLastSequenceId(t *testing.T) {
+	nodeKey := testutils.RandomPrivateKey(t)
 	nodeID := uint32(200)
 
 	// Create seq=3 then seq=1, this should never happen in production and is a violation of system invariants
-	env1 := envelopeTestUtils.CreateOriginatorEnvelope(t, nodeID, uint64(1))
-	env3 := envelopeTestUtils.CreateOriginatorEnvelope(t, nodeID, uint64(3))
+	env1 := envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, nodeID, uint64(1))
+	env3 := envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, nodeID, uint64(3))
 
 	stream := mockSubscriptionOnePage(t, []*envelopes.OriginatorEnvelope{env3, env1})
-	node := registryTestUtils.CreateNode(nodeID, 999, testutils.RandomPrivateKey(t))
+	node := registryTestUtils.CreateNode(nodeID, 999, nodeKey)
 
 	writeQueue := make(chan *envUtils.OriginatorEnvelope, 10)
 	defer close(writeQueue)
@@ -389,10 +417,14 @@ func TestSyncWorkerGapStillAdvancesLastSequenceId(t *testing.T) {
 		t.Context(),
 		logger, // use observed logger
 		&node,
+		&node,
 		lastSequenceIds,
 		permitted,
 		stream,
 		writeQueue,
+		misbehavior.NewLoggingMisbehaviorService(logger),
+		nil,
+		nil,
 	)
 
 	_ = origStream.listen()
@@ -415,14 +447,15 @@ func TestSyncWorkerGapStillAdvancesLastSequenceId(t *testing.T) {
 }
 
 func TestSyncWorkerOutOfOrderStillAdvancesLastSequenceId(t *testing.T) {
+	nodeKey := testutils.RandomPrivateKey(t)
 	nodeID := uint32(200)
 
 	// Create seq=1 then seq=3 (skip 2 to force oa warning)
-	env1 := envelopeTestUtils.CreateOriginatorEnvelope(t, nodeID, uint64(1))
-	env3 := envelopeTestUtils.CreateOriginatorEnvelope(t, nodeID, uint64(3))
+	env1 := envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, nodeID, uint64(1))
+	env3 := envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, nodeID, uint64(3))
 
 	stream := mockSubscriptionOnePage(t, []*envelopes.OriginatorEnvelope{env1, env3})
-	node := registryTestUtils.CreateNode(nodeID, 999, testutils.RandomPrivateKey(t))
+	node := registryTestUtils.CreateNode(nodeID, 999, nodeKey)
 
 	writeQueue := make(chan *envUtils.OriginatorEnvelope, 10)
 	defer close(writeQueue)
@@ -444,10 +477,14 @@ func TestSyncWorkerOutOfOrderStillAdvancesLastSequenceId(t *testing.T) {
 		t.Context(),
 		logger, // use observed logger
 		&node,
+		&node,
 		lastSequenceIds,
 		permitted,
 		stream,
 		writeQueue,
+		misbehavior.NewLoggingMisbehaviorService(logger),
+		nil,
+		nil,
 	)
 
 	_ = origStream.listen()
@@ -475,6 +512,7 @@ func TestSyncWorkerOutOfOrderStillAdvancesLastSequenceId(t *testing.T) {
 // stream must log an "out-of-order" error but still accept and forward all
 // envelopes — not silently drop them.
 func TestSyncWorkerMigratedEnvelopesStartFromOne(t *testing.T) {
+	nodeKey := testutils.RandomPrivateKey(t)
 	const (
 		migratorOriginatorID = migrator.GroupMessageOriginatorID // 10
 		priorLastSeenSeq     = uint64(220249)
@@ -484,14 +522,14 @@ func TestSyncWorkerMigratedEnvelopesStartFromOne(t *testing.T) {
 	// (fresh migration run), while our lastSequenceIds[10] = 220249
 	// (left over from a previous migration run).
 	envs := []*envelopes.OriginatorEnvelope{
-		envelopeTestUtils.CreateOriginatorEnvelope(t, migratorOriginatorID, 1),
-		envelopeTestUtils.CreateOriginatorEnvelope(t, migratorOriginatorID, 2),
-		envelopeTestUtils.CreateOriginatorEnvelope(t, migratorOriginatorID, 3),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, migratorOriginatorID, 1),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, migratorOriginatorID, 2),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, migratorOriginatorID, 3),
 	}
 
 	stream := mockSubscriptionOnePage(t, envs)
 	nodeID := uint32(200)
-	node := registryTestUtils.CreateNode(nodeID, 999, testutils.RandomPrivateKey(t))
+	node := registryTestUtils.CreateNode(nodeID, 999, nodeKey)
 
 	writeQueue := make(chan *envUtils.OriginatorEnvelope, 10)
 	defer close(writeQueue)
@@ -517,10 +555,14 @@ func TestSyncWorkerMigratedEnvelopesStartFromOne(t *testing.T) {
 		t.Context(),
 		logger,
 		&node,
+		&node,
 		lastSequenceIds,
 		permitted,
 		stream,
 		writeQueue,
+		misbehavior.NewLoggingMisbehaviorService(logger),
+		nil,
+		nil,
 	)
 
 	_ = origStream.listen()
@@ -555,15 +597,31 @@ func TestSyncWorkerMigratedEnvelopesStartFromOne(t *testing.T) {
 // database-bound migrator originator IDs (10, 11, 13) are accepted by the
 // stream when they are listed as permitted originators.
 func TestSyncWorkerMigratedEnvelopesAllOriginatorIDs(t *testing.T) {
+	nodeKey := testutils.RandomPrivateKey(t)
 	envs := []*envelopes.OriginatorEnvelope{
-		envelopeTestUtils.CreateOriginatorEnvelope(t, migrator.GroupMessageOriginatorID, 1),
-		envelopeTestUtils.CreateOriginatorEnvelope(t, migrator.WelcomeMessageOriginatorID, 1),
-		envelopeTestUtils.CreateOriginatorEnvelope(t, migrator.KeyPackagesOriginatorID, 1),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(
+			t,
+			nodeKey,
+			migrator.GroupMessageOriginatorID,
+			1,
+		),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(
+			t,
+			nodeKey,
+			migrator.WelcomeMessageOriginatorID,
+			1,
+		),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(
+			t,
+			nodeKey,
+			migrator.KeyPackagesOriginatorID,
+			1,
+		),
 	}
 
 	stream := mockSubscriptionOnePage(t, envs)
 	nodeID := uint32(200)
-	node := registryTestUtils.CreateNode(nodeID, 999, testutils.RandomPrivateKey(t))
+	node := registryTestUtils.CreateNode(nodeID, 999, nodeKey)
 
 	writeQueue := make(chan *envUtils.OriginatorEnvelope, 10)
 	defer close(writeQueue)
@@ -587,10 +645,14 @@ func TestSyncWorkerMigratedEnvelopesAllOriginatorIDs(t *testing.T) {
 		t.Context(),
 		logger,
 		&node,
+		&node,
 		lastSequenceIds,
 		permitted,
 		stream,
 		writeQueue,
+		misbehavior.NewLoggingMisbehaviorService(logger),
+		nil,
+		nil,
 	)
 
 	_ = origStream.listen()
@@ -607,25 +669,26 @@ func TestSyncWorkerMigratedEnvelopesAllOriginatorIDs(t *testing.T) {
 }
 
 func TestSyncWorkerNoOutOfOrderErrorForMultipleOriginatorsInOrder(t *testing.T) {
+	nodeKey := testutils.RandomPrivateKey(t)
 	envs := []*envelopes.OriginatorEnvelope{
-		envelopeTestUtils.CreateOriginatorEnvelope(t, 200, 1),
-		envelopeTestUtils.CreateOriginatorEnvelope(t, 10, 1),
-		envelopeTestUtils.CreateOriginatorEnvelope(t, 10, 2),
-		envelopeTestUtils.CreateOriginatorEnvelope(t, 10, 3),
-		envelopeTestUtils.CreateOriginatorEnvelope(t, 13, 1),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, 200, 1),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, 10, 1),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, 10, 2),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, 10, 3),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, 13, 1),
 
-		envelopeTestUtils.CreateOriginatorEnvelope(t, 200, 2),
-		envelopeTestUtils.CreateOriginatorEnvelope(t, 13, 2),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, 200, 2),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, 13, 2),
 
-		envelopeTestUtils.CreateOriginatorEnvelope(t, 200, 3),
-		envelopeTestUtils.CreateOriginatorEnvelope(t, 13, 3),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, 200, 3),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, 13, 3),
 	}
 
 	stream := mockSubscriptionOnePage(t, envs)
 
 	// "Node we are syncing from" (doesn't have to match all originators, but must be permitted)
 	nodeID := uint32(200)
-	node := registryTestUtils.CreateNode(nodeID, 999, testutils.RandomPrivateKey(t))
+	node := registryTestUtils.CreateNode(nodeID, 999, nodeKey)
 
 	writeQueue := make(chan *envUtils.OriginatorEnvelope, 50)
 	defer close(writeQueue)
@@ -649,10 +712,14 @@ func TestSyncWorkerNoOutOfOrderErrorForMultipleOriginatorsInOrder(t *testing.T)
 		t.Context(),
 		logger,
 		&node,
+		&node,
 		lastSequenceIds,
 		permitted,
 		stream,
 		writeQueue,
+		misbehavior.NewLoggingMisbehaviorService(logger),
+		nil,
+		nil,
 	)
 
 	_ = origStream.listen()
@@ -674,3 +741,392 @@ func TestSyncWorkerNoOutOfOrderErrorForMultipleOriginatorsInOrder(t *testing.T)
 	// even though we encountered 1, 1,2,3 2,3 2,3 we should not complain
 	require.Empty(t, recorded.FilterMessage("received out-of-order envelope").All())
 }
+
+type recordingMisbehaviorService struct {
+	reports []*misbehavior.SafetyFailureReport
+}
+
+func (r *recordingMisbehaviorService) SafetyFailure(report *misbehavior.SafetyFailureReport) error {
+	r.reports = append(r.reports, report)
+	return nil
+}
+
+func (r *recordingMisbehaviorService) LivenessFailure(*misbehavior.LivenessFailureReport) error {
+	return nil
+}
+
+func newValidationTestStream(
+	t *testing.T,
+	node *registry.Node,
+) (*originatorStream, *recordingMisbehaviorService) {
+	return newRelayedValidationTestStream(t, node, node)
+}
+
+func newRelayedValidationTestStream(
+	t *testing.T,
+	node *registry.Node,
+	source *registry.Node,
+) (*originatorStream, *recordingMisbehaviorService) {
+	misbehaviorService := &recordingMisbehaviorService{}
+
+	return newOriginatorStream(
+		t.Context(),
+		testutils.NewLog(t),
+		node,
+		source,
+		make(map[uint32]uint64),
+		map[uint32]struct{}{node.NodeID: {}},
+		nil,
+		nil,
+		misbehaviorService,
+		nil,
+		nil,
+	), misbehaviorService
+}
+
+func TestValidateEnvelopeReportsConflictingSequenceID(t *testing.T) {
+	nodeKey := testutils.RandomPrivateKey(t)
+	nodeID := uint32(200)
+	node := registryTestUtils.CreateNode(nodeID, 999, nodeKey)
+	origStream, misbehaviorService := newValidationTestStream(t, &node)
+
+	original := envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, nodeID, 1)
+	_, err := origStream.validateEnvelope(t.Context(), original)
+	require.NoError(t, err)
+
+	// Re-delivering the same envelope is not misbehavior.
+	_, err = origStream.validateEnvelope(t.Context(), original)
+	require.NoError(t, err)
+	require.Empty(t, misbehaviorService.reports)
+
+	// A different payer envelope under the same sequence ID.
+	conflicting := envelopeTestUtils.CreateSignedOriginatorEnvelope(
+		t,
+		nodeKey,
+		nodeID,
+		1,
+		envelopeTestUtils.CreatePayerEnvelope(t, nodeID),
+	)
+
+	_, err = origStream.validateEnvelope(t.Context(), conflicting)
+	require.Error(t, err)
+	require.Len(t, misbehaviorService.reports, 1)
+	require.Equal(t, nodeID, misbehaviorService.reports[0].MisbehavingNodeID())
+	require.Equal(
+		t,
+		message_api.Misbehavior_MISBEHAVIOR_DUPLICATE_SEQUENCE_ID,
+		misbehaviorService.reports[0].MisbehaviorType(),
+	)
+}
+
+func TestValidateEnvelopeReportsOutOfOrder(t *testing.T) {
+	nodeKey := testutils.RandomPrivateKey(t)
+	nodeID := uint32(200)
+	node := registryTestUtils.CreateNode(nodeID, 999, nodeKey)
+	origStream, misbehaviorService := newValidationTestStream(t, &node)
+
+	_, err := origStream.validateEnvelope(
+		t.Context(),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, nodeID, 3),
+	)
+	require.NoError(t, err)
+
+	_, err = origStream.validateEnvelope(
+		t.Context(),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, nodeID, 1),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, misbehaviorService.reports, 1)
+	require.Equal(
+		t,
+		message_api.Misbehavior_MISBEHAVIOR_OUT_OF_ORDER,
+		misbehaviorService.reports[0].MisbehaviorType(),
+	)
+	require.Equal(t, uint64(3), origStream.lastSequenceID(nodeID))
+}
+
+func TestValidateEnvelopeReportsInvalidOriginatorSignature(t *testing.T) {
+	nodeID := uint32(200)
+	node := registryTestUtils.CreateNode(nodeID, 999, testutils.RandomPrivateKey(t))
+	origStream, misbehaviorService := newValidationTestStream(t, &node)
+
+	_, err := origStream.validateEnvelope(
+		t.Context(),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(
+			t,
+			testutils.RandomPrivateKey(t),
+			nodeID,
+			1,
+		),
+	)
+	require.Error(t, err)
+	require.Len(t, misbehaviorService.reports, 1)
+	require.Equal(
+		t,
+		message_api.Misbehavior_MISBEHAVIOR_INVALID_PAYLOAD,
+		misbehaviorService.reports[0].MisbehaviorType(),
+	)
+	require.Zero(t, origStream.lastSequenceID(nodeID))
+}
+
+func TestValidateEnvelopeReportsTopicMismatch(t *testing.T) {
+	nodeKey := testutils.RandomPrivateKey(t)
+	nodeID := uint32(200)
+	node := registryTestUtils.CreateNode(nodeID, 999, nodeKey)
+	origStream, misbehaviorService := newValidationTestStream(t, &node)
+
+	// A group message published to a welcome message topic.
+	clientEnv := envelopeTestUtils.CreateClientEnvelope(&envelopeTestUtils.ClientEnvelopeOptions{
+		Aad: &envelopes.AuthenticatedData{
+			TargetTopic: topic.NewTopic(topic.TopicKindWelcomeMessagesV1, []byte{1, 2, 3}).
+				Bytes(),
+		},
+	})
+
+	_, err := origStream.validateEnvelope(
+		t.Context(),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(
+			t,
+			nodeKey,
+			nodeID,
+			1,
+			envelopeTestUtils.CreatePayerEnvelope(t, nodeID, clientEnv),
+		),
+	)
+	require.Error(t, err)
+	require.Len(t, misbehaviorService.reports, 1)
+	require.Equal(
+		t,
+		message_api.Misbehavior_MISBEHAVIOR_INVALID_PAYLOAD,
+		misbehaviorService.reports[0].MisbehaviorType(),
+	)
+}
+
+func TestValidateEnvelopeDoesNotReportMigratedEnvelopes(t *testing.T) {
+	nodeKey := testutils.RandomPrivateKey(t)
+	nodeID := uint32(200)
+	node := registryTestUtils.CreateNode(nodeID, 999, nodeKey)
+	origStream, misbehaviorService := newValidationTestStream(t, &node)
+	origStream.permittedOriginators[migrator.GroupMessageOriginatorID] = struct{}{}
+
+	for _, seqID := range []uint64{3, 1} {
+		_, err := origStream.validateEnvelope(
+			t.Context(),
+			envelopeTestUtils.CreateSignedOriginatorEnvelope(
+				t,
+				nodeKey,
+				migrator.GroupMessageOriginatorID,
+				seqID,
+			),
+		)
+		require.NoError(t, err)
+	}
+
+	require.Empty(t, misbehaviorService.reports)
+}
+
+func TestValidateRelayedEnvelopeVerifiesOriginatorSignature(t *testing.T) {
+	nodeKey := testutils.RandomPrivateKey(t)
+	relayKey := testutils.RandomPrivateKey(t)
+	nodeID := uint32(200)
+	node := registryTestUtils.CreateNode(nodeID, 999, nodeKey)
+	relay := registryTestUtils.CreateNode(300, 998, relayKey)
+	origStream, misbehaviorService := newRelayedValidationTestStream(t, &node, &relay)
+
+	_, err := origStream.validateEnvelope(
+		t.Context(),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, nodeID, 1),
+	)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), origStream.lastSequenceID(nodeID))
+
+	// An envelope re-signed by the relay is rejected, but the originator is not blamed.
+	_, err = origStream.validateEnvelope(
+		t.Context(),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, relayKey, nodeID, 2),
+	)
+	require.Error(t, err)
+	require.Empty(t, misbehaviorService.reports)
+	require.Equal(t, uint64(1), origStream.lastSequenceID(nodeID))
+}
+
+func TestValidateRelayedEnvelopeDoesNotReportOutOfOrder(t *testing.T) {
+	nodeKey := testutils.RandomPrivateKey(t)
+	nodeID := uint32(200)
+	node := registryTestUtils.CreateNode(nodeID, 999, nodeKey)
+	relay := registryTestUtils.CreateNode(300, 998, testutils.RandomPrivateKey(t))
+	origStream, misbehaviorService := newRelayedValidationTestStream(t, &node, &relay)
+
+	_, err := origStream.validateEnvelope(
+		t.Context(),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, nodeID, 3),
+	)
+	require.NoError(t, err)
+
+	_, err = origStream.validateEnvelope(
+		t.Context(),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, nodeID, 1),
+	)
+	require.NoError(t, err)
+	require.Empty(t, misbehaviorService.reports)
+}
+
+func TestValidateRelayedEnvelopeReportsConflictingSequenceID(t *testing.T) {
+	nodeKey := testutils.RandomPrivateKey(t)
+	nodeID := uint32(200)
+	node := registryTestUtils.CreateNode(nodeID, 999, nodeKey)
+	relay := registryTestUtils.CreateNode(300, 998, testutils.RandomPrivateKey(t))
+	origStream, misbehaviorService := newRelayedValidationTestStream(t, &node, &relay)
+
+	_, err := origStream.validateEnvelope(
+		t.Context(),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, nodeID, 1),
+	)
+	require.NoError(t, err)
+
+	// Both envelopes are signed by the originator, so the equivocation is its own.
+	_, err = origStream.validateEnvelope(
+		t.Context(),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(
+			t,
+			nodeKey,
+			nodeID,
+			1,
+			envelopeTestUtils.CreatePayerEnvelope(t, nodeID),
+		),
+	)
+	require.Error(t, err)
+	require.Len(t, misbehaviorService.reports, 1)
+	require.Equal(t, nodeID, misbehaviorService.reports[0].MisbehavingNodeID())
+}
+
+type recordingGapRecorder struct {
+	gaps [][3]uint64
+}
+
+func (r *recordingGapRecorder) recordGap(originatorID uint32, start, end uint64) {
+	r.gaps = append(r.gaps, [3]uint64{uint64(originatorID), start, end})
+}
+
+func TestValidateEnvelopeRecordsSequenceGap(t *testing.T) {
+	nodeKey := testutils.RandomPrivateKey(t)
+	nodeID := uint32(200)
+	node := registryTestUtils.CreateNode(nodeID, 999, nodeKey)
+	origStream, _ := newValidationTestStream(t, &node)
+	gaps := &recordingGapRecorder{}
+	origStream.gaps = gaps
+
+	// The first envelope has no predecessor on the stream or in the database.
+	for _, seqID := range []uint64{3, 4, 8, 9} {
+		_, err := origStream.validateEnvelope(
+			t.Context(),
+			envelopeTestUtils.CreateSignedOriginatorEnvelope(t, nodeKey, nodeID, seqID),
+		)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, [][3]uint64{{uint64(nodeID), 5, 7}}, gaps.gaps)
+}
+
+type fakeProofVerifier struct {
+	// errs are returned by the first calls, in order.
+	errs  []error
+	calls int
+}
+
+func (f *fakeProofVerifier) VerifyEnvelope(context.Context, *envUtils.OriginatorEnvelope) error {
+	f.calls++
+	if f.calls <= len(f.errs) {
+		return f.errs[f.calls-1]
+	}
+	return nil
+}
+
+func newChainOriginatedEnvelope(
+	t *testing.T,
+	originatorID uint32,
+	seqID uint64,
+) *envelopes.OriginatorEnvelope {
+	envProto := envelopeTestUtils.CreateOriginatorEnvelope(t, originatorID, seqID)
+	envProto.Proof = &envelopes.OriginatorEnvelope_BlockchainProof{
+		BlockchainProof: &envelopes.BlockchainProof{TransactionHash: make([]byte, 32)},
+	}
+	return envProto
+}
+
+func TestValidateEnvelopeVerifiesBlockchainProof(t *testing.T) {
+	nodeKey := testutils.RandomPrivateKey(t)
+	nodeID := uint32(200)
+	node := registryTestUtils.CreateNode(nodeID, 999, nodeKey)
+
+	for _, originatorID := range []uint32{
+		constants.GroupMessageOriginatorID,
+		constants.IdentityUpdateOriginatorID,
+	} {
+		t.Run(fmt.Sprintf("originator %d", originatorID), func(t *testing.T) {
+			newStream := func(verifier blockchain.EnvelopeProofVerifier) *originatorStream {
+				origStream, _ := newValidationTestStream(t, &node)
+				origStream.permittedOriginators[originatorID] = struct{}{}
+				origStream.proofVerifier = verifier
+				return origStream
+			}
+			envProto := newChainOriginatedEnvelope(t, originatorID, 1)
+
+			// Without a verifier, a blockchain proof cannot be trusted.
+			_, err := newStream(nil).validateEnvelope(t.Context(), envProto)
+			require.Error(t, err)
+
+			verifier := &fakeProofVerifier{
+				errs: []error{fmt.Errorf("%w: bad", blockchain.ErrInvalidBlockchainProof)},
+			}
+			_, err = newStream(verifier).validateEnvelope(t.Context(), envProto)
+			require.ErrorIs(t, err, blockchain.ErrInvalidBlockchainProof)
+			require.Equal(t, 1, verifier.calls)
+
+			// Failing to read the app chain is retried.
+			verifier = &fakeProofVerifier{errs: []error{errors.New("rpc unavailable")}}
+			env, err := newStream(verifier).validateEnvelope(t.Context(), envProto)
+			require.NoError(t, err)
+			require.Equal(t, originatorID, env.OriginatorNodeID())
+			require.Equal(t, 2, verifier.calls)
+		})
+	}
+}
+
+func TestValidateEnvelopeRejectsChainOriginatedEnvelopeWithoutProof(t *testing.T) {
+	nodeKey := testutils.RandomPrivateKey(t)
+	nodeID := uint32(200)
+	node := registryTestUtils.CreateNode(nodeID, 999, nodeKey)
+	origStream, _ := newValidationTestStream(t, &node)
+	origStream.permittedOriginators[constants.GroupMessageOriginatorID] = struct{}{}
+	origStream.proofVerifier = blockchainProofVerifierFunc(
+		func(_ context.Context, env *envUtils.OriginatorEnvelope) error {
+			if env.Proto().GetBlockchainProof() == nil {
+				return blockchain.ErrInvalidBlockchainProof
+			}
+			return nil
+		},
+	)
+
+	// Signed by the node relaying it, which cannot originate group messages.
+	_, err := origStream.validateEnvelope(
+		t.Context(),
+		envelopeTestUtils.CreateSignedOriginatorEnvelope(
+			t,
+			nodeKey,
+			constants.GroupMessageOriginatorID,
+			1,
+		),
+	)
+	require.ErrorIs(t, err, blockchain.ErrInvalidBlockchainProof)
+}
+
+type blockchainProofVerifierFunc func(context.Context, *envUtils.OriginatorEnvelope) error
+
+func (f blockchainProofVerifierFunc) VerifyEnvelope(
+	ctx context.Context,
+	env *envUtils.OriginatorEnvelope,
+) error {
+	return f(ctx, env)
+}
@@ -7,8 +7,10 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	grpcprom "github.com/grpc-ecosystem/go-grpc-middleware/providers/prometheus"
+	"github.com/xmtp/xmtpd/pkg/blockchain"
 	"github.com/xmtp/xmtpd/pkg/db"
 	"github.com/xmtp/xmtpd/pkg/fees"
+	"github.com/xmtp/xmtpd/pkg/misbehavior"
 	"github.com/xmtp/xmtpd/pkg/payerreport"
 	"github.com/xmtp/xmtpd/pkg/registrant"
 	"github.com/xmtp/xmtpd/pkg/registry"
@@ -23,11 +25,13 @@ type MigrationConfig struct {
 
 type SyncServerConfig struct {
 	Ctx                        context.Context
+	BlockchainProofVerifier    blockchain.EnvelopeProofVerifier
 	ClientMetrics              *grpcprom.ClientMetrics
 	DB                         *db.Handler
 	FeeCalculator              fees.IFeeCalculator
 	Logger                     *zap.Logger
 	Migration                  MigrationConfig
+	MisbehaviorService         misbehavior.MisbehaviorService
 	NodeRegistry               registry.NodeRegistry
 	PayerReportDomainSeparator common.Hash
 	PayerReportStore           payerreport.IPayerReportStore
@@ -76,6 +80,18 @@ func WithMigration(migration MigrationConfig) SyncServerOption {
 	return func(cfg *SyncServerConfig) { cfg.Migration = migration }
 }
 
+// WithMisbehaviorService sets the service safety failures detected while syncing are reported to.
+// Defaults to a service that only logs the reports.
+func WithMisbehaviorService(svc misbehavior.MisbehaviorService) SyncServerOption {
+	return func(cfg *SyncServerConfig) { cfg.MisbehaviorService = svc }
+}
+
+// WithBlockchainProofVerifier sets the verifier of synced envelopes originated on the app
+// chain. Without one, such envelopes are rejected, as they carry no originator signature.
+func WithBlockchainProofVerifier(verifier blockchain.EnvelopeProofVerifier) SyncServerOption {
+	return func(cfg *SyncServerConfig) { cfg.BlockchainProofVerifier = verifier }
+}
+
 type SyncServer struct {
 	ctx        context.Context
 	logger     *zap.Logger
@@ -118,6 +134,10 @@ func NewSyncServer(opts ...SyncServerOption) (*SyncServer, error) {
 		return nil, errors.New("syncserver: fee calculator is required")
 	}
 
+	if cfg.MisbehaviorService == nil {
+		cfg.MisbehaviorService = misbehavior.NewLoggingMisbehaviorService(cfg.Logger)
+	}
+
 	worker, err := startSyncWorker(cfg)
 	if err != nil {
 		return nil, err
@@ -1,20 +1,25 @@
 package sync
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v5"
 	"github.com/ethereum/go-ethereum/common"
 	grpcprom "github.com/grpc-ecosystem/go-grpc-middleware/providers/prometheus"
+	"github.com/xmtp/xmtpd/pkg/blockchain"
 	"github.com/xmtp/xmtpd/pkg/db"
 	envUtils "github.com/xmtp/xmtpd/pkg/envelopes"
 	"github.com/xmtp/xmtpd/pkg/fees"
 	clientInterceptors "github.com/xmtp/xmtpd/pkg/interceptors/client"
 	"github.com/xmtp/xmtpd/pkg/metrics"
 	"github.com/xmtp/xmtpd/pkg/migrator"
+	"github.com/xmtp/xmtpd/pkg/misbehavior"
 	"github.com/xmtp/xmtpd/pkg/payerreport"
 	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/envelopes"
 	"github.com/xmtp/xmtpd/pkg/proto/xmtpv4/message_api"
@@ -26,6 +31,15 @@ import (
 	"google.golang.org/grpc"
 )
 
+const (
+	// relayFailureThreshold is the number of consecutive failed attempts to stream from an
+	// originator after which its envelopes are streamed from another node instead.
+	relayFailureThreshold = 3
+	// relayProbeInterval bounds how long a relayed stream runs before the originator is
+	// tried again.
+	relayProbeInterval = 5 * time.Minute
+)
+
 type syncWorker struct {
 	ctx                        context.Context
 	logger                     *zap.Logger
@@ -41,6 +55,9 @@ type syncWorker struct {
 	payerReportDomainSeparator common.Hash
 	migration                  MigrationConfig
 	clientMetrics              *grpcprom.ClientMetrics
+	misbehaviorService         misbehavior.MisbehaviorService
+	livenessTracker            *misbehavior.LivenessTracker
+	proofVerifier              blockchain.EnvelopeProofVerifier
 }
 
 func startSyncWorker(
@@ -61,6 +78,13 @@ func startSyncWorker(
 		migration:                  cfg.Migration,
 		cancel:                     cancel,
 		clientMetrics:              cfg.ClientMetrics,
+		misbehaviorService:         cfg.MisbehaviorService,
+		proofVerifier:              cfg.BlockchainProofVerifier,
+		livenessTracker: misbehavior.NewLivenessTracker(
+			cfg.Logger,
+			cfg.MisbehaviorService,
+			misbehavior.DefaultLivenessConfig(),
+		),
 	}
 	if err := s.start(); err != nil {
 		return nil, err
@@ -89,6 +113,8 @@ func (s *syncWorker) start() error {
 		s.subscribeToNode(node.NodeID)
 	}
 
+	s.repairGaps()
+
 	return nil
 }
 
@@ -223,13 +249,37 @@ func (s *syncWorker) subscribeToNodeRegistration(
 	expBackoff := backoff.NewExponentialBackOff()
 	expBackoff.InitialInterval = 1 * time.Second
 
+	// While the originator keeps failing, its envelopes are streamed from the other nodes in
+	// turn. Cursors come from the local vector clock, so a stream resumes where the last one
+	// stopped whichever node serves it.
+	var (
+		originatorFailures int
+		relayAttempts      int
+	)
+
 	operation := func() (string, error) {
 		var (
-			conn   *grpc.ClientConn
-			stream *originatorStream
-			err    error
+			conn      *grpc.ClientConn
+			stream    *originatorStream
+			err       error
+			start     = time.Now()
+			source    = *node
+			streamCtx = registration.ctx
 		)
 
+		if originatorFailures >= relayFailureThreshold {
+			if peer, ok := s.selectRelayPeer(node.NodeID, relayAttempts); ok {
+				source = peer
+			}
+			relayAttempts++
+		}
+		relayed := source.NodeID != node.NodeID
+		if relayed {
+			var cancel context.CancelFunc
+			streamCtx, cancel = context.WithTimeout(registration.ctx, relayProbeInterval)
+			defer cancel()
+		}
+
 		defer func() {
 			if stream != nil {
 				_ = stream.stream.CloseSend()
@@ -243,7 +293,7 @@ func (s *syncWorker) subscribeToNodeRegistration(
 			if err != nil && s.ctx.Err() == nil {
 				s.logger.Error(
 					"error connecting to node, retrying",
-					utils.NodeHTTPAddressField(node.HTTPAddress),
+					utils.NodeHTTPAddressField(source.HTTPAddress),
 					zap.Error(err),
 				)
 				connectionsStatusCounter.MarkFailure()
@@ -254,19 +304,40 @@ func (s *syncWorker) subscribeToNodeRegistration(
 			return "", backoff.Permanent(registration.ctx.Err())
 		}
 
-		conn, err = s.connectToNode(*node)
-		if err != nil {
-			return "", err
+		conn, err = s.connectToNode(source)
+		if err == nil {
+			stream, err = s.setupStream(streamCtx, *node, source, conn, writeQueue)
 		}
-
-		stream, err = s.setupStream(registration.ctx, *node, conn, writeQueue)
 		if err != nil {
+			s.recordConnectionFailure(registration, source.NodeID, time.Since(start), err)
+			if !relayed {
+				originatorFailures++
+			}
 			return "", err
 		}
 
 		connectionsStatusCounter.MarkSuccess()
+		s.livenessTracker.RecordSuccess(source.NodeID, time.Since(start))
+		if !relayed {
+			originatorFailures = 0
+			relayAttempts = 0
+		} else {
+			metrics.EmitSyncRelayedStream(node.NodeID, source.NodeID)
+			// Try the originator once the relayed stream ends, then fall back right away
+			// if it is still down.
+			originatorFailures = relayFailureThreshold - 1
+		}
 
 		err = stream.listen()
+		if relayed && streamCtx.Err() != nil && registration.ctx.Err() == nil {
+			s.logger.Info(
+				"relayed stream reached its probe interval, retrying originator",
+				utils.OriginatorIDField(node.NodeID),
+				utils.SourceNodeIDField(source.NodeID),
+			)
+			err = nil
+			return "", backoff.RetryAfter(1)
+		}
 		return "", err
 	}
 
@@ -279,12 +350,60 @@ func (s *syncWorker) subscribeToNodeRegistration(
 }
 
 func (s *syncWorker) handleUnhealthyNode(registration NodeRegistration) {
+	s.livenessTracker.RecordFailure(
+		registration.nodeID,
+		0,
+		errors.New("node is unhealthy in the registry"),
+	)
+
 	// keep the goroutine idle
 	// this will exit the goroutine during shutdown or if the config changed
 	<-registration.ctx.Done()
 	s.logger.Debug("node configuration has changed, closing stream and connection")
 }
 
+// recordConnectionFailure feeds failed connection attempts to nodeID to the liveness
+// tracker, unless they were caused by the worker or the registration shutting down.
+func (s *syncWorker) recordConnectionFailure(
+	registration NodeRegistration,
+	nodeID uint32,
+	elapsed time.Duration,
+	err error,
+) {
+	if s.ctx.Err() != nil || registration.ctx.Err() != nil {
+		return
+	}
+
+	s.livenessTracker.RecordFailure(nodeID, elapsed, err)
+}
+
+// selectRelayPeer picks a node to stream originatorID's envelopes from, rotating through
+// the other healthy nodes in the registry by attempt. Every node replicates every
+// originator, so any of them may hold the envelopes the local node is missing.
+func (s *syncWorker) selectRelayPeer(originatorID uint32, attempt int) (registry.Node, bool) {
+	nodes, err := s.nodeRegistry.GetNodes()
+	if err != nil {
+		s.logger.Error("failed to get nodes from registry", zap.Error(err))
+		return registry.Node{}, false
+	}
+
+	localNodeID := s.registrant.NodeID()
+	peers := make([]registry.Node, 0, len(nodes))
+	for _, n := range nodes {
+		if n.NodeID != localNodeID && n.NodeID != originatorID && n.IsValidConfig {
+			peers = append(peers, n)
+		}
+	}
+	if len(peers) == 0 {
+		return registry.Node{}, false
+	}
+
+	slices.SortFunc(peers, func(a, b registry.Node) int {
+		return cmp.Compare(a.NodeID, b.NodeID)
+	})
+	return peers[attempt%len(peers)], true
+}
+
 type NodeRegistration struct {
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -314,14 +433,17 @@ func (s *syncWorker) connectToNode(
 		node.NodeID,
 	)
 
-	// Execute first the auth interceptor, then metrics.
+	// Execute first the auth interceptor, then tracing, then metrics.
+	// Tracing runs after auth, which replaces the outgoing metadata.
 	dialOpts := []grpc.DialOption{
 		grpc.WithChainUnaryInterceptor(
 			interceptor.Unary(),
+			tracing.UnaryClientInterceptor(),
 			s.clientMetrics.UnaryClientInterceptor(),
 		),
 		grpc.WithChainStreamInterceptor(
 			interceptor.Stream(),
+			tracing.StreamClientInterceptor(),
 			s.clientMetrics.StreamClientInterceptor(),
 		),
 	}
@@ -346,9 +468,12 @@ func (s *syncWorker) connectToNode(
 	return conn, nil
 }
 
+// setupStream subscribes to node's envelopes on source, which is either node itself or
+// another node relaying them.
 func (s *syncWorker) setupStream(
 	ctx context.Context,
 	node registry.Node,
+	source registry.Node,
 	conn *grpc.ClientConn,
 	writeQueue chan *envUtils.OriginatorEnvelope,
 ) (_ *originatorStream, retErr error) {
@@ -362,7 +487,7 @@ func (s *syncWorker) setupStream(
 		}
 	}()
 
-	tracing.SpanTag(span, tracing.TagTargetNode, node.NodeID)
+	tracing.SpanTag(span, tracing.TagTargetNode, source.NodeID)
 
 	result, err := s.store.ReadQuery().SelectVectorClock(ctx)
 	if err != nil {
@@ -409,19 +534,20 @@ func (s *syncWorker) setupStream(
 		originatorNodeIDs,
 		&envelopes.Cursor{NodeIdToSequenceId: vc},
 		s.logger,
-		&node,
+		&source,
 	)
 	if err != nil {
 		subscribeSpan.Finish(tracing.WithError(err))
 		s.logger.Error(
 			"failed to batch subscribe to node",
 			utils.OriginatorIDField(node.NodeID),
-			utils.NodeHTTPAddressField(node.HTTPAddress),
+			utils.SourceNodeIDField(source.NodeID),
+			utils.NodeHTTPAddressField(source.HTTPAddress),
 			zap.Error(err),
 		)
 		return nil, fmt.Errorf(
 			"failed to batch subscribe to peer at %s: %w",
-			node.HTTPAddress,
+			source.HTTPAddress,
 			err,
 		)
 	}
@@ -438,9 +564,13 @@ func (s *syncWorker) setupStream(
 		s.ctx,
 		s.logger,
 		&node,
+		&source,
 		lastSequenceIDs,
 		permittedOriginators,
 		stream,
 		writeQueue,
+		s.misbehaviorService,
+		s,
+		s.proofVerifier,
 	), nil
 }
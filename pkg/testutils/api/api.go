@@ -15,6 +15,7 @@ import (
 	dbPkg "github.com/xmtp/xmtpd/pkg/db"
 	"github.com/xmtp/xmtpd/pkg/interceptors/server"
 	ledgerPkg "github.com/xmtp/xmtpd/pkg/ledger"
+	"github.com/xmtp/xmtpd/pkg/payerbalance"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/require"
@@ -213,6 +214,7 @@ type APIServerTestConfig struct {
 	registryNodes               []registry.Node
 	requirePayerPositiveBalance bool
 	sendKeepAliveInterval       time.Duration
+	publishDedupWindow          time.Duration
 }
 
 type TestAPIOption func(*APIServerTestConfig)
@@ -237,6 +239,13 @@ func WithSendKeepAliveInterval(d time.Duration) TestAPIOption {
 	}
 }
 
+// WithPublishDedupWindow enables deduplicating republished payer envelopes.
+func WithPublishDedupWindow(d time.Duration) TestAPIOption {
+	return func(cfg *APIServerTestConfig) {
+		cfg.publishDedupWindow = d
+	}
+}
+
 func createMockRegistry(t *testing.T, nodes []registry.Node) *registryMocks.MockNodeRegistry {
 	reg := registryMocks.NewMockNodeRegistry(t)
 
@@ -321,6 +330,7 @@ func NewTestAPIServer(
 			config.APIOptions{
 				SendKeepAliveInterval:       cfg.sendKeepAliveInterval,
 				RequirePayerPositiveBalance: cfg.requirePayerPositiveBalance,
+				PublishDedupWindow:          cfg.publishDedupWindow,
 			},
 			false,
 			10*time.Millisecond,
@@ -328,6 +338,7 @@ func NewTestAPIServer(
 			ledgerPkg.NewLedger(log, db),
 			nil,
 			message.RateLimitConfig{},
+			nil,
 		)
 		require.NoError(t, err)
 
@@ -359,6 +370,12 @@ func NewTestAPIServer(
 			metadata.NewCursorUpdater(ctx, log, db),
 			testutils.GetLatestVersion(t),
 			metadata.NewPayerInfoFetcher(db),
+			metadata.NewFeeQuoter(db, fees.NewTestFeeCalculator()),
+			ledgerPkg.NewLedger(log, db),
+			payerbalance.NewForecaster(db, 24*time.Hour, payerbalance.Thresholds{
+				Warning:  72 * time.Hour,
+				Critical: 24 * time.Hour,
+			}),
 		)
 		require.NoError(t, err)
 
@@ -239,6 +239,35 @@ func CreateOriginatorEnvelope(
 		payerEnv...)
 }
 
+// CreateSignedOriginatorEnvelope creates an originator envelope signed by the originator's private key.
+func CreateSignedOriginatorEnvelope(
+	t *testing.T,
+	originatorPrivateKey *ecdsa.PrivateKey,
+	originatorNodeID uint32,
+	originatorSequenceID uint64,
+	payerEnv ...*envelopes.PayerEnvelope,
+) *envelopes.OriginatorEnvelope {
+	originatorEnv := CreateOriginatorEnvelope(
+		t,
+		originatorNodeID,
+		originatorSequenceID,
+		payerEnv...)
+
+	signature, err := crypto.Sign(
+		utils.HashOriginatorSignatureInput(originatorEnv.GetUnsignedOriginatorEnvelope()),
+		originatorPrivateKey,
+	)
+	require.NoError(t, err)
+
+	originatorEnv.Proof = &envelopes.OriginatorEnvelope_OriginatorSignature{
+		OriginatorSignature: &associations.RecoverableEcdsaSignature{
+			Bytes: signature,
+		},
+	}
+
+	return originatorEnv
+}
+
 func CreateOriginatorEnvelopeWithTopic(
 	t *testing.T,
 	originatorNodeID uint32,
@@ -671,6 +671,65 @@ func (_c *MockChainClient_TransactionInBlock_Call) RunAndReturn(run func(context
 	return _c
 }
 
+// TransactionReceipt provides a mock function with given fields: ctx, txHash
+func (_m *MockChainClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	ret := _m.Called(ctx, txHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TransactionReceipt")
+	}
+
+	var r0 *types.Receipt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash) (*types.Receipt, error)); ok {
+		return rf(ctx, txHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash) *types.Receipt); ok {
+		r0 = rf(ctx, txHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Receipt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Hash) error); ok {
+		r1 = rf(ctx, txHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockChainClient_TransactionReceipt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TransactionReceipt'
+type MockChainClient_TransactionReceipt_Call struct {
+	*mock.Call
+}
+
+// TransactionReceipt is a helper method to define mock.On call
+//   - ctx context.Context
+//   - txHash common.Hash
+func (_e *MockChainClient_Expecter) TransactionReceipt(ctx interface{}, txHash interface{}) *MockChainClient_TransactionReceipt_Call {
+	return &MockChainClient_TransactionReceipt_Call{Call: _e.mock.On("TransactionReceipt", ctx, txHash)}
+}
+
+func (_c *MockChainClient_TransactionReceipt_Call) Run(run func(ctx context.Context, txHash common.Hash)) *MockChainClient_TransactionReceipt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(common.Hash))
+	})
+	return _c
+}
+
+func (_c *MockChainClient_TransactionReceipt_Call) Return(_a0 *types.Receipt, _a1 error) *MockChainClient_TransactionReceipt_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockChainClient_TransactionReceipt_Call) RunAndReturn(run func(context.Context, common.Hash) (*types.Receipt, error)) *MockChainClient_TransactionReceipt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockChainClient creates a new instance of MockChainClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockChainClient(t interface {
@@ -237,6 +237,66 @@ func (_c *MockIBlockchainPublisher_PublishGroupMessage_Call) RunAndReturn(run fu
 	return _c
 }
 
+// PublishGroupMessages provides a mock function with given fields: ctx, groupIDs, messages
+func (_m *MockIBlockchainPublisher) PublishGroupMessages(ctx context.Context, groupIDs [][16]byte, messages [][]byte) ([]*groupmessagebroadcaster.GroupMessageBroadcasterMessageSent, error) {
+	ret := _m.Called(ctx, groupIDs, messages)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishGroupMessages")
+	}
+
+	var r0 []*groupmessagebroadcaster.GroupMessageBroadcasterMessageSent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, [][16]byte, [][]byte) ([]*groupmessagebroadcaster.GroupMessageBroadcasterMessageSent, error)); ok {
+		return rf(ctx, groupIDs, messages)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, [][16]byte, [][]byte) []*groupmessagebroadcaster.GroupMessageBroadcasterMessageSent); ok {
+		r0 = rf(ctx, groupIDs, messages)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*groupmessagebroadcaster.GroupMessageBroadcasterMessageSent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, [][16]byte, [][]byte) error); ok {
+		r1 = rf(ctx, groupIDs, messages)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIBlockchainPublisher_PublishGroupMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PublishGroupMessages'
+type MockIBlockchainPublisher_PublishGroupMessages_Call struct {
+	*mock.Call
+}
+
+// PublishGroupMessages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - groupIDs [][16]byte
+//   - messages [][]byte
+func (_e *MockIBlockchainPublisher_Expecter) PublishGroupMessages(ctx interface{}, groupIDs interface{}, messages interface{}) *MockIBlockchainPublisher_PublishGroupMessages_Call {
+	return &MockIBlockchainPublisher_PublishGroupMessages_Call{Call: _e.mock.On("PublishGroupMessages", ctx, groupIDs, messages)}
+}
+
+func (_c *MockIBlockchainPublisher_PublishGroupMessages_Call) Run(run func(ctx context.Context, groupIDs [][16]byte, messages [][]byte)) *MockIBlockchainPublisher_PublishGroupMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([][16]byte), args[2].([][]byte))
+	})
+	return _c
+}
+
+func (_c *MockIBlockchainPublisher_PublishGroupMessages_Call) Return(_a0 []*groupmessagebroadcaster.GroupMessageBroadcasterMessageSent, _a1 error) *MockIBlockchainPublisher_PublishGroupMessages_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIBlockchainPublisher_PublishGroupMessages_Call) RunAndReturn(run func(context.Context, [][16]byte, [][]byte) ([]*groupmessagebroadcaster.GroupMessageBroadcasterMessageSent, error)) *MockIBlockchainPublisher_PublishGroupMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // PublishIdentityUpdate provides a mock function with given fields: ctx, inboxID, identityUpdate
 func (_m *MockIBlockchainPublisher) PublishIdentityUpdate(ctx context.Context, inboxID [32]byte, identityUpdate []byte) (*identityupdatebroadcaster.IdentityUpdateBroadcasterIdentityUpdateCreated, error) {
 	ret := _m.Called(ctx, inboxID, identityUpdate)
@@ -297,6 +357,66 @@ func (_c *MockIBlockchainPublisher_PublishIdentityUpdate_Call) RunAndReturn(run
 	return _c
 }
 
+// PublishIdentityUpdates provides a mock function with given fields: ctx, inboxIDs, identityUpdates
+func (_m *MockIBlockchainPublisher) PublishIdentityUpdates(ctx context.Context, inboxIDs [][32]byte, identityUpdates [][]byte) ([]*identityupdatebroadcaster.IdentityUpdateBroadcasterIdentityUpdateCreated, error) {
+	ret := _m.Called(ctx, inboxIDs, identityUpdates)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishIdentityUpdates")
+	}
+
+	var r0 []*identityupdatebroadcaster.IdentityUpdateBroadcasterIdentityUpdateCreated
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, [][32]byte, [][]byte) ([]*identityupdatebroadcaster.IdentityUpdateBroadcasterIdentityUpdateCreated, error)); ok {
+		return rf(ctx, inboxIDs, identityUpdates)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, [][32]byte, [][]byte) []*identityupdatebroadcaster.IdentityUpdateBroadcasterIdentityUpdateCreated); ok {
+		r0 = rf(ctx, inboxIDs, identityUpdates)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*identityupdatebroadcaster.IdentityUpdateBroadcasterIdentityUpdateCreated)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, [][32]byte, [][]byte) error); ok {
+		r1 = rf(ctx, inboxIDs, identityUpdates)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIBlockchainPublisher_PublishIdentityUpdates_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PublishIdentityUpdates'
+type MockIBlockchainPublisher_PublishIdentityUpdates_Call struct {
+	*mock.Call
+}
+
+// PublishIdentityUpdates is a helper method to define mock.On call
+//   - ctx context.Context
+//   - inboxIDs [][32]byte
+//   - identityUpdates [][]byte
+func (_e *MockIBlockchainPublisher_Expecter) PublishIdentityUpdates(ctx interface{}, inboxIDs interface{}, identityUpdates interface{}) *MockIBlockchainPublisher_PublishIdentityUpdates_Call {
+	return &MockIBlockchainPublisher_PublishIdentityUpdates_Call{Call: _e.mock.On("PublishIdentityUpdates", ctx, inboxIDs, identityUpdates)}
+}
+
+func (_c *MockIBlockchainPublisher_PublishIdentityUpdates_Call) Run(run func(ctx context.Context, inboxIDs [][32]byte, identityUpdates [][]byte)) *MockIBlockchainPublisher_PublishIdentityUpdates_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([][32]byte), args[2].([][]byte))
+	})
+	return _c
+}
+
+func (_c *MockIBlockchainPublisher_PublishIdentityUpdates_Call) Return(_a0 []*identityupdatebroadcaster.IdentityUpdateBroadcasterIdentityUpdateCreated, _a1 error) *MockIBlockchainPublisher_PublishIdentityUpdates_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIBlockchainPublisher_PublishIdentityUpdates_Call) RunAndReturn(run func(context.Context, [][32]byte, [][]byte) ([]*identityupdatebroadcaster.IdentityUpdateBroadcasterIdentityUpdateCreated, error)) *MockIBlockchainPublisher_PublishIdentityUpdates_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockIBlockchainPublisher creates a new instance of MockIBlockchainPublisher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockIBlockchainPublisher(t interface {
@@ -23,6 +23,228 @@ func (_m *MockMetadataApiClient) EXPECT() *MockMetadataApiClient_Expecter {
 	return &MockMetadataApiClient_Expecter{mock: &_m.Mock}
 }
 
+// ExportPayerSpend provides a mock function with given fields: ctx, in, opts
+func (_m *MockMetadataApiClient) ExportPayerSpend(ctx context.Context, in *metadata_api.ExportPayerSpendRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[metadata_api.ExportPayerSpendResponse], error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportPayerSpend")
+	}
+
+	var r0 grpc.ServerStreamingClient[metadata_api.ExportPayerSpendResponse]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metadata_api.ExportPayerSpendRequest, ...grpc.CallOption) (grpc.ServerStreamingClient[metadata_api.ExportPayerSpendResponse], error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metadata_api.ExportPayerSpendRequest, ...grpc.CallOption) grpc.ServerStreamingClient[metadata_api.ExportPayerSpendResponse]); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(grpc.ServerStreamingClient[metadata_api.ExportPayerSpendResponse])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metadata_api.ExportPayerSpendRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMetadataApiClient_ExportPayerSpend_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExportPayerSpend'
+type MockMetadataApiClient_ExportPayerSpend_Call struct {
+	*mock.Call
+}
+
+// ExportPayerSpend is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *metadata_api.ExportPayerSpendRequest
+//   - opts ...grpc.CallOption
+func (_e *MockMetadataApiClient_Expecter) ExportPayerSpend(ctx interface{}, in interface{}, opts ...interface{}) *MockMetadataApiClient_ExportPayerSpend_Call {
+	return &MockMetadataApiClient_ExportPayerSpend_Call{Call: _e.mock.On("ExportPayerSpend",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockMetadataApiClient_ExportPayerSpend_Call) Run(run func(ctx context.Context, in *metadata_api.ExportPayerSpendRequest, opts ...grpc.CallOption)) *MockMetadataApiClient_ExportPayerSpend_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*metadata_api.ExportPayerSpendRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockMetadataApiClient_ExportPayerSpend_Call) Return(_a0 grpc.ServerStreamingClient[metadata_api.ExportPayerSpendResponse], _a1 error) *MockMetadataApiClient_ExportPayerSpend_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMetadataApiClient_ExportPayerSpend_Call) RunAndReturn(run func(context.Context, *metadata_api.ExportPayerSpendRequest, ...grpc.CallOption) (grpc.ServerStreamingClient[metadata_api.ExportPayerSpendResponse], error)) *MockMetadataApiClient_ExportPayerSpend_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetFeeQuote provides a mock function with given fields: ctx, in, opts
+func (_m *MockMetadataApiClient) GetFeeQuote(ctx context.Context, in *metadata_api.GetFeeQuoteRequest, opts ...grpc.CallOption) (*metadata_api.GetFeeQuoteResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFeeQuote")
+	}
+
+	var r0 *metadata_api.GetFeeQuoteResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metadata_api.GetFeeQuoteRequest, ...grpc.CallOption) (*metadata_api.GetFeeQuoteResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metadata_api.GetFeeQuoteRequest, ...grpc.CallOption) *metadata_api.GetFeeQuoteResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metadata_api.GetFeeQuoteResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metadata_api.GetFeeQuoteRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMetadataApiClient_GetFeeQuote_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFeeQuote'
+type MockMetadataApiClient_GetFeeQuote_Call struct {
+	*mock.Call
+}
+
+// GetFeeQuote is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *metadata_api.GetFeeQuoteRequest
+//   - opts ...grpc.CallOption
+func (_e *MockMetadataApiClient_Expecter) GetFeeQuote(ctx interface{}, in interface{}, opts ...interface{}) *MockMetadataApiClient_GetFeeQuote_Call {
+	return &MockMetadataApiClient_GetFeeQuote_Call{Call: _e.mock.On("GetFeeQuote",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockMetadataApiClient_GetFeeQuote_Call) Run(run func(ctx context.Context, in *metadata_api.GetFeeQuoteRequest, opts ...grpc.CallOption)) *MockMetadataApiClient_GetFeeQuote_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*metadata_api.GetFeeQuoteRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockMetadataApiClient_GetFeeQuote_Call) Return(_a0 *metadata_api.GetFeeQuoteResponse, _a1 error) *MockMetadataApiClient_GetFeeQuote_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMetadataApiClient_GetFeeQuote_Call) RunAndReturn(run func(context.Context, *metadata_api.GetFeeQuoteRequest, ...grpc.CallOption) (*metadata_api.GetFeeQuoteResponse, error)) *MockMetadataApiClient_GetFeeQuote_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPayerBalanceForecast provides a mock function with given fields: ctx, in, opts
+func (_m *MockMetadataApiClient) GetPayerBalanceForecast(ctx context.Context, in *metadata_api.GetPayerBalanceForecastRequest, opts ...grpc.CallOption) (*metadata_api.GetPayerBalanceForecastResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPayerBalanceForecast")
+	}
+
+	var r0 *metadata_api.GetPayerBalanceForecastResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metadata_api.GetPayerBalanceForecastRequest, ...grpc.CallOption) (*metadata_api.GetPayerBalanceForecastResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metadata_api.GetPayerBalanceForecastRequest, ...grpc.CallOption) *metadata_api.GetPayerBalanceForecastResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metadata_api.GetPayerBalanceForecastResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metadata_api.GetPayerBalanceForecastRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMetadataApiClient_GetPayerBalanceForecast_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPayerBalanceForecast'
+type MockMetadataApiClient_GetPayerBalanceForecast_Call struct {
+	*mock.Call
+}
+
+// GetPayerBalanceForecast is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *metadata_api.GetPayerBalanceForecastRequest
+//   - opts ...grpc.CallOption
+func (_e *MockMetadataApiClient_Expecter) GetPayerBalanceForecast(ctx interface{}, in interface{}, opts ...interface{}) *MockMetadataApiClient_GetPayerBalanceForecast_Call {
+	return &MockMetadataApiClient_GetPayerBalanceForecast_Call{Call: _e.mock.On("GetPayerBalanceForecast",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockMetadataApiClient_GetPayerBalanceForecast_Call) Run(run func(ctx context.Context, in *metadata_api.GetPayerBalanceForecastRequest, opts ...grpc.CallOption)) *MockMetadataApiClient_GetPayerBalanceForecast_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*metadata_api.GetPayerBalanceForecastRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockMetadataApiClient_GetPayerBalanceForecast_Call) Return(_a0 *metadata_api.GetPayerBalanceForecastResponse, _a1 error) *MockMetadataApiClient_GetPayerBalanceForecast_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMetadataApiClient_GetPayerBalanceForecast_Call) RunAndReturn(run func(context.Context, *metadata_api.GetPayerBalanceForecastRequest, ...grpc.CallOption) (*metadata_api.GetPayerBalanceForecastResponse, error)) *MockMetadataApiClient_GetPayerBalanceForecast_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetPayerInfo provides a mock function with given fields: ctx, in, opts
 func (_m *MockMetadataApiClient) GetPayerInfo(ctx context.Context, in *metadata_api.GetPayerInfoRequest, opts ...grpc.CallOption) (*metadata_api.GetPayerInfoResponse, error) {
 	_va := make([]interface{}, len(opts))
@@ -97,6 +319,154 @@ func (_c *MockMetadataApiClient_GetPayerInfo_Call) RunAndReturn(run func(context
 	return _c
 }
 
+// GetPayerLedgerEvents provides a mock function with given fields: ctx, in, opts
+func (_m *MockMetadataApiClient) GetPayerLedgerEvents(ctx context.Context, in *metadata_api.GetPayerLedgerEventsRequest, opts ...grpc.CallOption) (*metadata_api.GetPayerLedgerEventsResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPayerLedgerEvents")
+	}
+
+	var r0 *metadata_api.GetPayerLedgerEventsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metadata_api.GetPayerLedgerEventsRequest, ...grpc.CallOption) (*metadata_api.GetPayerLedgerEventsResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metadata_api.GetPayerLedgerEventsRequest, ...grpc.CallOption) *metadata_api.GetPayerLedgerEventsResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metadata_api.GetPayerLedgerEventsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metadata_api.GetPayerLedgerEventsRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMetadataApiClient_GetPayerLedgerEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPayerLedgerEvents'
+type MockMetadataApiClient_GetPayerLedgerEvents_Call struct {
+	*mock.Call
+}
+
+// GetPayerLedgerEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *metadata_api.GetPayerLedgerEventsRequest
+//   - opts ...grpc.CallOption
+func (_e *MockMetadataApiClient_Expecter) GetPayerLedgerEvents(ctx interface{}, in interface{}, opts ...interface{}) *MockMetadataApiClient_GetPayerLedgerEvents_Call {
+	return &MockMetadataApiClient_GetPayerLedgerEvents_Call{Call: _e.mock.On("GetPayerLedgerEvents",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockMetadataApiClient_GetPayerLedgerEvents_Call) Run(run func(ctx context.Context, in *metadata_api.GetPayerLedgerEventsRequest, opts ...grpc.CallOption)) *MockMetadataApiClient_GetPayerLedgerEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*metadata_api.GetPayerLedgerEventsRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockMetadataApiClient_GetPayerLedgerEvents_Call) Return(_a0 *metadata_api.GetPayerLedgerEventsResponse, _a1 error) *MockMetadataApiClient_GetPayerLedgerEvents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMetadataApiClient_GetPayerLedgerEvents_Call) RunAndReturn(run func(context.Context, *metadata_api.GetPayerLedgerEventsRequest, ...grpc.CallOption) (*metadata_api.GetPayerLedgerEventsResponse, error)) *MockMetadataApiClient_GetPayerLedgerEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPayerSpend provides a mock function with given fields: ctx, in, opts
+func (_m *MockMetadataApiClient) GetPayerSpend(ctx context.Context, in *metadata_api.GetPayerSpendRequest, opts ...grpc.CallOption) (*metadata_api.GetPayerSpendResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPayerSpend")
+	}
+
+	var r0 *metadata_api.GetPayerSpendResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metadata_api.GetPayerSpendRequest, ...grpc.CallOption) (*metadata_api.GetPayerSpendResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metadata_api.GetPayerSpendRequest, ...grpc.CallOption) *metadata_api.GetPayerSpendResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metadata_api.GetPayerSpendResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metadata_api.GetPayerSpendRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMetadataApiClient_GetPayerSpend_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPayerSpend'
+type MockMetadataApiClient_GetPayerSpend_Call struct {
+	*mock.Call
+}
+
+// GetPayerSpend is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *metadata_api.GetPayerSpendRequest
+//   - opts ...grpc.CallOption
+func (_e *MockMetadataApiClient_Expecter) GetPayerSpend(ctx interface{}, in interface{}, opts ...interface{}) *MockMetadataApiClient_GetPayerSpend_Call {
+	return &MockMetadataApiClient_GetPayerSpend_Call{Call: _e.mock.On("GetPayerSpend",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockMetadataApiClient_GetPayerSpend_Call) Run(run func(ctx context.Context, in *metadata_api.GetPayerSpendRequest, opts ...grpc.CallOption)) *MockMetadataApiClient_GetPayerSpend_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*metadata_api.GetPayerSpendRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockMetadataApiClient_GetPayerSpend_Call) Return(_a0 *metadata_api.GetPayerSpendResponse, _a1 error) *MockMetadataApiClient_GetPayerSpend_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMetadataApiClient_GetPayerSpend_Call) RunAndReturn(run func(context.Context, *metadata_api.GetPayerSpendRequest, ...grpc.CallOption) (*metadata_api.GetPayerSpendResponse, error)) *MockMetadataApiClient_GetPayerSpend_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetSyncCursor provides a mock function with given fields: ctx, in, opts
 func (_m *MockMetadataApiClient) GetSyncCursor(ctx context.Context, in *metadata_api.GetSyncCursorRequest, opts ...grpc.CallOption) (*metadata_api.GetSyncCursorResponse, error) {
 	_va := make([]interface{}, len(opts))
@@ -145,6 +145,66 @@ func (_c *MockMLSValidationService_GetAssociationStateFromEnvelopes_Call) RunAnd
 	return _c
 }
 
+// GetAssociationStateFromSnapshot provides a mock function with given fields: ctx, snapshot, newIdentityUpdate
+func (_m *MockMLSValidationService) GetAssociationStateFromSnapshot(ctx context.Context, snapshot *associations.AssociationState, newIdentityUpdate *associations.IdentityUpdate) (*mlsvalidate.AssociationStateResult, error) {
+	ret := _m.Called(ctx, snapshot, newIdentityUpdate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAssociationStateFromSnapshot")
+	}
+
+	var r0 *mlsvalidate.AssociationStateResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *associations.AssociationState, *associations.IdentityUpdate) (*mlsvalidate.AssociationStateResult, error)); ok {
+		return rf(ctx, snapshot, newIdentityUpdate)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *associations.AssociationState, *associations.IdentityUpdate) *mlsvalidate.AssociationStateResult); ok {
+		r0 = rf(ctx, snapshot, newIdentityUpdate)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*mlsvalidate.AssociationStateResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *associations.AssociationState, *associations.IdentityUpdate) error); ok {
+		r1 = rf(ctx, snapshot, newIdentityUpdate)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMLSValidationService_GetAssociationStateFromSnapshot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAssociationStateFromSnapshot'
+type MockMLSValidationService_GetAssociationStateFromSnapshot_Call struct {
+	*mock.Call
+}
+
+// GetAssociationStateFromSnapshot is a helper method to define mock.On call
+//   - ctx context.Context
+//   - snapshot *associations.AssociationState
+//   - newIdentityUpdate *associations.IdentityUpdate
+func (_e *MockMLSValidationService_Expecter) GetAssociationStateFromSnapshot(ctx interface{}, snapshot interface{}, newIdentityUpdate interface{}) *MockMLSValidationService_GetAssociationStateFromSnapshot_Call {
+	return &MockMLSValidationService_GetAssociationStateFromSnapshot_Call{Call: _e.mock.On("GetAssociationStateFromSnapshot", ctx, snapshot, newIdentityUpdate)}
+}
+
+func (_c *MockMLSValidationService_GetAssociationStateFromSnapshot_Call) Run(run func(ctx context.Context, snapshot *associations.AssociationState, newIdentityUpdate *associations.IdentityUpdate)) *MockMLSValidationService_GetAssociationStateFromSnapshot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*associations.AssociationState), args[2].(*associations.IdentityUpdate))
+	})
+	return _c
+}
+
+func (_c *MockMLSValidationService_GetAssociationStateFromSnapshot_Call) Return(_a0 *mlsvalidate.AssociationStateResult, _a1 error) *MockMLSValidationService_GetAssociationStateFromSnapshot_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMLSValidationService_GetAssociationStateFromSnapshot_Call) RunAndReturn(run func(context.Context, *associations.AssociationState, *associations.IdentityUpdate) (*mlsvalidate.AssociationStateResult, error)) *MockMLSValidationService_GetAssociationStateFromSnapshot_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ValidateGroupMessages provides a mock function with given fields: ctx, groupMessages
 func (_m *MockMLSValidationService) ValidateGroupMessages(ctx context.Context, groupMessages []*apiv1.GroupMessageInput) ([]mlsvalidate.GroupMessageValidationResult, error) {
 	ret := _m.Called(ctx, groupMessages)
@@ -36,6 +36,17 @@ func (k TopicKind) String() string {
 	}
 }
 
+// ParseTopicKind returns the topic kind with the given name, as returned by TopicKind.String.
+func ParseTopicKind(name string) (TopicKind, error) {
+	for kind := TopicKindGroupMessagesV1; kind <= TopicKindPayerReportAttestationsV1; kind++ {
+		if kind.String() == name {
+			return kind, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown topic kind %q", name)
+}
+
 type Topic struct {
 	kind       TopicKind
 	identifier []byte
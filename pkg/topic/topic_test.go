@@ -52,3 +52,16 @@ func TestTopicString(t *testing.T) {
 		identityUpdatesTopic.String(),
 	)
 }
+
+func TestParseTopicKind(t *testing.T) {
+	kind, err := topic.ParseTopicKind("key_packages_v1")
+	require.NoError(t, err)
+	require.Equal(t, topic.TopicKindKeyPackagesV1, kind)
+
+	kind, err = topic.ParseTopicKind("payer_report_attestations_v1")
+	require.NoError(t, err)
+	require.Equal(t, topic.TopicKindPayerReportAttestationsV1, kind)
+
+	_, err = topic.ParseTopicKind("unknown")
+	require.Error(t, err)
+}
@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+// Backend records spans for a tracing system. The package-level functions delegate to the
+// backend selected by Start.
+type Backend interface {
+	// StartSpanFromContext starts a child of the context's active span, or of the remote span
+	// extracted into the context, and returns a context holding the new span.
+	StartSpanFromContext(ctx context.Context, operationName string) (Span, context.Context)
+	// StartSpan starts a child of parent, or a root span if parent is nil.
+	StartSpan(operationName string, parent SpanContext) Span
+	// ContextWithSpan returns a copy of ctx with span as its active span.
+	ContextWithSpan(ctx context.Context, span Span) context.Context
+	// Inject writes the context's active span into carrier.
+	Inject(ctx context.Context, carrier Carrier)
+	// Extract returns a copy of ctx holding the remote span read from carrier, if any.
+	Extract(ctx context.Context, carrier Carrier) context.Context
+	// LogFields returns the fields correlating a log line with the span.
+	LogFields(spanCtx SpanContext) []zap.Field
+	// Stop flushes pending spans and shuts the backend down.
+	Stop()
+}
+
+// backend is the active backend. Datadog is used until Start selects one, so tests driving
+// the global Datadog mocktracer keep working.
+var backend atomic.Pointer[Backend]
+
+func currentBackend() Backend {
+	if b := backend.Load(); b != nil {
+		return *b
+	}
+	return datadogBackend{}
+}
+
+// SetBackendForTesting enables tracing with b as the active backend.
+// Returns a cleanup function that restores the previous state.
+// This must only be called from test code.
+func SetBackendForTesting(b Backend) func() {
+	prev := backend.Swap(&b)
+	restoreEnabled := SetEnabledForTesting(true)
+	return func() {
+		backend.Store(prev)
+		restoreEnabled()
+	}
+}
@@ -3,13 +3,11 @@ package tracing
 import (
 	"sync"
 	"time"
-
-	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace"
 )
 
 // traceContextEntry holds a span context with its creation time for TTL cleanup.
 type traceContextEntry struct {
-	ctx       ddtrace.SpanContext
+	ctx       SpanContext
 	createdAt time.Time
 }
 
@@ -85,7 +83,7 @@ func (s *TraceContextStore) Store(stagedID int64, span Span) {
 
 // Retrieve gets and removes the span context for a staged envelope ID.
 // Returns nil if no context was stored for this ID or if it expired.
-func (s *TraceContextStore) Retrieve(stagedID int64) ddtrace.SpanContext {
+func (s *TraceContextStore) Retrieve(stagedID int64) SpanContext {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -0,0 +1,160 @@
+package tracing
+
+import (
+	"context"
+	"strconv"
+
+	"go.uber.org/zap"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
+)
+
+// datadogBackend records spans with the global dd-trace-go tracer. It propagates span
+// context in the styles the tracer is configured with (DD_TRACE_PROPAGATION_STYLE), which
+// include W3C tracecontext by default.
+type datadogBackend struct{}
+
+var _ Backend = datadogBackend{}
+
+type logger struct{ *zap.Logger }
+
+func (l logger) Log(msg string) {
+	l.Error(msg)
+}
+
+// startDatadog boots the global Datadog tracer.
+func startDatadog(env, version string, sampleRate float64, l *zap.Logger) Backend {
+	opts := []tracer.StartOption{
+		tracer.WithEnv(env),
+		tracer.WithService("xmtpd"),
+		tracer.WithServiceVersion(version),
+		tracer.WithLogger(logger{l}),
+		tracer.WithRuntimeMetrics(),
+	}
+
+	// Add sampler if not 100%
+	if sampleRate < 1.0 {
+		opts = append(opts, tracer.WithSampler(tracer.NewRateSampler(sampleRate)))
+	}
+
+	tracer.Start(opts...)
+
+	return datadogBackend{}
+}
+
+// datadogRemoteSpanKey holds the span context extracted from a remote caller.
+type datadogRemoteSpanKey struct{}
+
+func (datadogBackend) StartSpanFromContext(
+	ctx context.Context,
+	operationName string,
+) (Span, context.Context) {
+	var opts []ddtrace.StartSpanOption
+	if _, ok := tracer.SpanFromContext(ctx); !ok {
+		if remote, ok := ctx.Value(datadogRemoteSpanKey{}).(ddtrace.SpanContext); ok {
+			opts = append(opts, tracer.ChildOf(remote))
+		}
+	}
+
+	span, ctx := tracer.StartSpanFromContext(ctx, operationName, opts...)
+	return &datadogSpan{span: span}, ctx
+}
+
+func (datadogBackend) StartSpan(operationName string, parent SpanContext) Span {
+	if parent, ok := parent.(datadogSpanContext); ok {
+		return &datadogSpan{span: tracer.StartSpan(operationName, tracer.ChildOf(parent.ctx))}
+	}
+	return &datadogSpan{span: tracer.StartSpan(operationName)}
+}
+
+func (datadogBackend) ContextWithSpan(ctx context.Context, span Span) context.Context {
+	if span, ok := span.(*datadogSpan); ok {
+		return tracer.ContextWithSpan(ctx, span.span)
+	}
+	return ctx
+}
+
+func (datadogBackend) Inject(ctx context.Context, carrier Carrier) {
+	span, ok := tracer.SpanFromContext(ctx)
+	if !ok {
+		return
+	}
+	// Inject only fails on an unsupported carrier or an empty span context, which leaves
+	// the callee to start a new trace.
+	_ = tracer.Inject(span.Context(), datadogCarrier{carrier})
+}
+
+func (datadogBackend) Extract(ctx context.Context, carrier Carrier) context.Context {
+	remote, err := tracer.Extract(datadogCarrier{carrier})
+	if err != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, datadogRemoteSpanKey{}, remote)
+}
+
+func (datadogBackend) LogFields(spanCtx SpanContext) []zap.Field {
+	ctx, ok := spanCtx.(datadogSpanContext)
+	if !ok {
+		return nil
+	}
+	return []zap.Field{
+		zap.Uint64("dd.trace_id", ctx.ctx.TraceID()),
+		zap.Uint64("dd.span_id", ctx.ctx.SpanID()),
+	}
+}
+
+func (datadogBackend) Stop() {
+	tracer.Stop()
+}
+
+// datadogSpan adapts a ddtrace.Span to Span.
+type datadogSpan struct {
+	span ddtrace.Span
+}
+
+func (s *datadogSpan) SetTag(key string, value any) {
+	s.span.SetTag(key, value)
+}
+
+func (s *datadogSpan) Finish(opts ...FinishOption) {
+	if cfg := newFinishConfig(opts); cfg.err != nil {
+		s.span.Finish(tracer.WithError(cfg.err))
+		return
+	}
+	s.span.Finish()
+}
+
+func (s *datadogSpan) Context() SpanContext {
+	return datadogSpanContext{ctx: s.span.Context()}
+}
+
+// datadogSpanContext adapts a ddtrace.SpanContext to SpanContext.
+type datadogSpanContext struct {
+	ctx ddtrace.SpanContext
+}
+
+func (c datadogSpanContext) TraceID() string {
+	return strconv.FormatUint(c.ctx.TraceID(), 10)
+}
+
+func (c datadogSpanContext) SpanID() string {
+	return strconv.FormatUint(c.ctx.SpanID(), 10)
+}
+
+// datadogCarrier adapts a Carrier to the dd-trace-go TextMapWriter and TextMapReader.
+type datadogCarrier struct {
+	carrier Carrier
+}
+
+func (c datadogCarrier) Set(key, value string) {
+	c.carrier.Set(key, value)
+}
+
+func (c datadogCarrier) ForeachKey(handler func(key, value string) error) error {
+	for _, key := range c.carrier.Keys() {
+		if err := handler(key, c.carrier.Get(key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// otelShutdownTimeout bounds how long Stop waits for pending spans to be exported.
+const otelShutdownTimeout = 5 * time.Second
+
+// otelBackend records spans with an OpenTelemetry tracer provider, and propagates span
+// context as W3C traceparent and tracestate headers.
+type otelBackend struct {
+	provider   trace.TracerProvider
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+var _ Backend = (*otelBackend)(nil)
+
+// NewOTelBackend returns a Backend recording spans with provider. Stop shuts the provider
+// down when it is an SDK provider.
+//
+// Tests can pass an SDK provider with an in-memory tracetest.SpanRecorder.
+func NewOTelBackend(provider trace.TracerProvider) Backend {
+	return &otelBackend{
+		provider:   provider,
+		tracer:     provider.Tracer("github.com/xmtp/xmtpd/pkg/tracing"),
+		propagator: propagation.TraceContext{},
+	}
+}
+
+// startOTLP creates a backend exporting spans to the OTLP gRPC collector at
+// options.OTLPEndpoint.
+func startOTLP(
+	ctx context.Context,
+	env string,
+	version string,
+	sampleRate float64,
+	options Options,
+) (Backend, error) {
+	if options.OTLPEndpoint == "" {
+		return nil, fmt.Errorf("an OTLP endpoint is required")
+	}
+
+	exporterOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(options.OTLPEndpoint)}
+	if options.OTLPInsecure {
+		exporterOpts = append(exporterOpts, otlptracegrpc.WithInsecure())
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, exporterOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not create OTLP exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRate))),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", "xmtpd"),
+			attribute.String("service.version", version),
+			attribute.String("deployment.environment", env),
+		)),
+	)
+
+	return NewOTelBackend(provider), nil
+}
+
+func (b *otelBackend) StartSpanFromContext(
+	ctx context.Context,
+	operationName string,
+) (Span, context.Context) {
+	ctx, span := b.tracer.Start(ctx, operationName)
+	return &otelSpan{span: span}, ctx
+}
+
+func (b *otelBackend) StartSpan(operationName string, parent SpanContext) Span {
+	if parent, ok := parent.(otelSpanContext); ok {
+		ctx := trace.ContextWithSpanContext(context.Background(), parent.ctx)
+		_, span := b.tracer.Start(ctx, operationName)
+		return &otelSpan{span: span}
+	}
+
+	_, span := b.tracer.Start(context.Background(), operationName, trace.WithNewRoot())
+	return &otelSpan{span: span}
+}
+
+func (b *otelBackend) ContextWithSpan(ctx context.Context, span Span) context.Context {
+	if span, ok := span.(*otelSpan); ok {
+		return trace.ContextWithSpan(ctx, span.span)
+	}
+	return ctx
+}
+
+func (b *otelBackend) Inject(ctx context.Context, carrier Carrier) {
+	b.propagator.Inject(ctx, carrier)
+}
+
+func (b *otelBackend) Extract(ctx context.Context, carrier Carrier) context.Context {
+	return b.propagator.Extract(ctx, carrier)
+}
+
+func (b *otelBackend) LogFields(spanCtx SpanContext) []zap.Field {
+	if _, ok := spanCtx.(otelSpanContext); !ok {
+		return nil
+	}
+	return []zap.Field{
+		zap.String("trace_id", spanCtx.TraceID()),
+		zap.String("span_id", spanCtx.SpanID()),
+	}
+}
+
+func (b *otelBackend) Stop() {
+	provider, ok := b.provider.(*sdktrace.TracerProvider)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), otelShutdownTimeout)
+	defer cancel()
+	// Spans still pending at shutdown are dropped.
+	_ = provider.Shutdown(ctx)
+}
+
+// otelSpan adapts an OpenTelemetry span to Span.
+type otelSpan struct {
+	span trace.Span
+}
+
+// SetTag records the tag as an attribute. The error tags also set the span status.
+func (s *otelSpan) SetTag(key string, value any) {
+	switch key {
+	case TagError:
+		if failed, ok := value.(bool); ok && failed {
+			s.span.SetStatus(codes.Error, "")
+		}
+	case TagErrorMsg:
+		s.span.SetStatus(codes.Error, fmt.Sprint(value))
+	}
+	s.span.SetAttributes(otelAttribute(key, value))
+}
+
+func (s *otelSpan) Finish(opts ...FinishOption) {
+	if cfg := newFinishConfig(opts); cfg.err != nil {
+		s.span.RecordError(cfg.err)
+		s.span.SetStatus(codes.Error, cfg.err.Error())
+	}
+	s.span.End()
+}
+
+func (s *otelSpan) Context() SpanContext {
+	return otelSpanContext{ctx: s.span.SpanContext()}
+}
+
+// otelSpanContext adapts an OpenTelemetry span context to SpanContext.
+type otelSpanContext struct {
+	ctx trace.SpanContext
+}
+
+func (c otelSpanContext) TraceID() string {
+	return c.ctx.TraceID().String()
+}
+
+func (c otelSpanContext) SpanID() string {
+	return c.ctx.SpanID().String()
+}
+
+// otelAttribute converts a tag value to an attribute, formatting unsupported types.
+func otelAttribute(key string, value any) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int32:
+		return attribute.Int64(key, int64(v))
+	case int64:
+		return attribute.Int64(key, v)
+	case uint32:
+		return attribute.Int64(key, int64(v))
+	case float64:
+		return attribute.Float64(key, v)
+	case error:
+		return attribute.String(key, v.Error())
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,162 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmtp/xmtpd/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// startOTelRecorder enables tracing with the OpenTelemetry backend, recording finished
+// spans in memory.
+func startOTelRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(tracing.SetBackendForTesting(tracing.NewOTelBackend(provider)))
+	return recorder
+}
+
+func endedSpan(t *testing.T, recorder *tracetest.SpanRecorder, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	for _, span := range recorder.Ended() {
+		if span.Name() == name {
+			return span
+		}
+	}
+	require.FailNow(t, "span not found", name)
+	return nil
+}
+
+func TestOTel_SpanHierarchy(t *testing.T) {
+	recorder := startOTelRecorder(t)
+
+	parentSpan, ctx := tracing.StartSpanFromContext(
+		context.Background(),
+		tracing.SpanNodePublishPayerEnvelopes,
+	)
+	tracing.SpanTag(parentSpan, tracing.TagNumEnvelopes, 1)
+
+	childSpan, _ := tracing.StartSpanFromContext(ctx, tracing.SpanNodeStageTransaction)
+	childSpan.Finish()
+	parentSpan.Finish()
+
+	parent := endedSpan(t, recorder, tracing.SpanNodePublishPayerEnvelopes)
+	child := endedSpan(t, recorder, tracing.SpanNodeStageTransaction)
+
+	assert.Equal(t, parent.SpanContext().SpanID(), child.Parent().SpanID())
+	assert.Equal(t, parent.SpanContext().TraceID(), child.SpanContext().TraceID())
+	assert.False(t, parent.Parent().IsValid())
+	assert.Contains(t, parent.Attributes(), attribute.Int(tracing.TagNumEnvelopes, 1))
+}
+
+func TestOTel_AsyncContextPropagation(t *testing.T) {
+	recorder := startOTelRecorder(t)
+
+	store := tracing.NewTraceContextStore()
+	stagedID := int64(67890)
+
+	apiSpan := tracing.StartSpan(tracing.SpanNodePublishPayerEnvelopes)
+	store.Store(stagedID, apiSpan)
+	apiSpan.Finish()
+
+	parentCtx := store.Retrieve(stagedID)
+	require.NotNil(t, parentCtx)
+	assert.Equal(t, apiSpan.Context().TraceID(), parentCtx.TraceID())
+
+	workerSpan := tracing.StartSpanWithParent(tracing.SpanPublishWorkerProcess, parentCtx)
+	workerSpan.Finish()
+
+	api := endedSpan(t, recorder, tracing.SpanNodePublishPayerEnvelopes)
+	worker := endedSpan(t, recorder, tracing.SpanPublishWorkerProcess)
+
+	assert.Equal(t, api.SpanContext().SpanID(), worker.Parent().SpanID())
+	assert.Equal(t, api.SpanContext().TraceID(), worker.SpanContext().TraceID())
+}
+
+func TestOTel_ErrorStatus(t *testing.T) {
+	recorder := startOTelRecorder(t)
+
+	span := tracing.StartSpan("test.error_operation")
+	span.Finish(tracing.WithError(assert.AnError))
+
+	tagged := tracing.StartSpan("test.error_tag")
+	tagged.SetTag(tracing.TagError, true)
+	tagged.SetTag(tracing.TagErrorMsg, "failed")
+	tagged.Finish()
+
+	ended := endedSpan(t, recorder, "test.error_operation")
+	assert.Equal(t, codes.Error, ended.Status().Code)
+	assert.Equal(t, assert.AnError.Error(), ended.Status().Description)
+	require.Len(t, ended.Events(), 1)
+
+	ended = endedSpan(t, recorder, "test.error_tag")
+	assert.Equal(t, codes.Error, ended.Status().Code)
+	assert.Equal(t, "failed", ended.Status().Description)
+}
+
+func TestOTel_TraceparentPropagation(t *testing.T) {
+	recorder := startOTelRecorder(t)
+
+	callerSpan, ctx := tracing.StartSpanFromContext(context.Background(), "test.caller")
+
+	header := make(tracing.HeaderCarrier)
+	tracing.Inject(ctx, header)
+	require.NotEmpty(t, header.Get("traceparent"))
+
+	// The callee continues the caller's trace.
+	calleeCtx := tracing.Extract(context.Background(), header)
+	calleeSpan, _ := tracing.StartSpanFromContext(calleeCtx, "test.callee")
+	calleeSpan.Finish()
+	callerSpan.Finish()
+
+	caller := endedSpan(t, recorder, "test.caller")
+	callee := endedSpan(t, recorder, "test.callee")
+
+	assert.Equal(t, caller.SpanContext().TraceID(), callee.SpanContext().TraceID())
+	assert.Equal(t, caller.SpanContext().SpanID(), callee.Parent().SpanID())
+	assert.True(t, callee.Parent().IsRemote())
+}
+
+func TestOTel_UnaryClientInterceptor(t *testing.T) {
+	startOTelRecorder(t)
+
+	span, ctx := tracing.StartSpanFromContext(context.Background(), "test.caller")
+	defer span.Finish()
+
+	// Metadata set by earlier interceptors is kept.
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "token")
+
+	var outgoing metadata.MD
+	err := tracing.UnaryClientInterceptor()(
+		ctx,
+		"/test/Method",
+		nil,
+		nil,
+		nil,
+		func(
+			ctx context.Context,
+			_ string,
+			_, _ any,
+			_ *grpc.ClientConn,
+			_ ...grpc.CallOption,
+		) error {
+			outgoing, _ = metadata.FromOutgoingContext(ctx)
+			return nil
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"token"}, outgoing.Get("authorization"))
+	traceparent := outgoing.Get("traceparent")
+	require.Len(t, traceparent, 1)
+	assert.Contains(t, traceparent[0], span.Context().TraceID())
+}
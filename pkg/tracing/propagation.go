@@ -0,0 +1,81 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// Carrier transports span context between processes, as W3C traceparent and tracestate
+// headers. Its method set matches OpenTelemetry's propagation.TextMapCarrier.
+type Carrier interface {
+	Get(key string) string
+	Set(key, value string)
+	Keys() []string
+}
+
+// HeaderCarrier adapts HTTP headers, as seen by Connect handlers, to a Carrier.
+type HeaderCarrier http.Header
+
+var _ Carrier = HeaderCarrier(nil)
+
+func (c HeaderCarrier) Get(key string) string {
+	return http.Header(c).Get(key)
+}
+
+func (c HeaderCarrier) Set(key, value string) {
+	http.Header(c).Set(key, value)
+}
+
+func (c HeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// MetadataCarrier adapts gRPC metadata to a Carrier.
+type MetadataCarrier metadata.MD
+
+var _ Carrier = MetadataCarrier(nil)
+
+func (c MetadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c MetadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c MetadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Inject writes the context's active span into carrier, so the receiving node continues
+// the trace. No-ops when tracing is disabled.
+func Inject(ctx context.Context, carrier Carrier) {
+	if !apmEnabled.Load() {
+		return
+	}
+	currentBackend().Inject(ctx, carrier)
+}
+
+// Extract returns a copy of ctx holding the remote span read from carrier, so spans started
+// from it join the caller's trace.
+// Returns the unchanged context when tracing is disabled.
+func Extract(ctx context.Context, carrier Carrier) context.Context {
+	if !apmEnabled.Load() {
+		return ctx
+	}
+	return currentBackend().Extract(ctx, carrier)
+}
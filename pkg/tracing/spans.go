@@ -3,7 +3,7 @@
 package tracing
 
 // Span operation names follow the pattern: xmtpd.{component}.{operation}
-// This provides clear hierarchy in Datadog APM service maps.
+// This provides clear hierarchy in APM service maps.
 const (
 	// Node API spans - incoming request handling
 	SpanNodePublishPayerEnvelopes = "xmtpd.node.publish_payer_envelopes"
@@ -86,6 +86,14 @@ const (
 	TagDBRowsAffected = "db.rows_affected"
 	TagDBSystem       = "db.system"
 	TagDBService      = "db.service"
+
+	// Standard tags, named after their Datadog equivalents. The OpenTelemetry backend maps
+	// the error tags to the span status.
+	TagSpanType     = "span.type"
+	TagResourceName = "resource.name"
+	TagRPCSystem    = "rpc.system"
+	TagError        = "error"
+	TagErrorMsg     = "error.message"
 )
 
 // Trigger values for the trigger tag
@@ -1,5 +1,7 @@
-// Package tracing enables [Datadog APM tracing](https://docs.datadoghq.com/tracing/) capabilities,
-// focusing specifically on [Error Tracking](https://docs.datadoghq.com/tracing/error_tracking/)
+// Package tracing enables APM tracing capabilities, focusing specifically on error tracking.
+//
+// Spans are recorded through a Backend: [Datadog APM](https://docs.datadoghq.com/tracing/)
+// by default, or OpenTelemetry with an OTLP exporter.
 package tracing
 
 import (
@@ -12,80 +14,100 @@ import (
 	"sync/atomic"
 
 	"go.uber.org/zap"
-	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace"
-	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/ext"
-	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
 )
 
 // EnvAPMSampleRate is the environment variable for configuring APM sample rate.
 const EnvAPMSampleRate = "APM_SAMPLE_RATE"
 
-// Re-export tracer types and options that don't need gating.
-var (
-	ChildOf         = tracer.ChildOf
-	WithError       = tracer.WithError
-	ContextWithSpan = tracer.ContextWithSpan
-)
+// Span is a unit of traced work.
+type Span interface {
+	// SetTag sets a tag on the span. Prefer SpanTag, which bounds the tag size.
+	SetTag(key string, value any)
+	// Finish ends the span.
+	Finish(opts ...FinishOption)
+	// Context returns the span context, used to link spans across async boundaries.
+	Context() SpanContext
+}
+
+// SpanContext identifies a span within its trace.
+type SpanContext interface {
+	TraceID() string
+	SpanID() string
+}
 
-// Span is the tracer span type (ddtrace.Span interface).
-type Span = ddtrace.Span
+// FinishOption configures how a span is finished.
+type FinishOption func(*finishConfig)
 
-// noopSpan implements ddtrace.Span with zero-cost no-ops.
+type finishConfig struct {
+	err error
+}
+
+// WithError marks the finished span as failed with err.
+func WithError(err error) FinishOption {
+	return func(cfg *finishConfig) {
+		cfg.err = err
+	}
+}
+
+func newFinishConfig(opts []FinishOption) finishConfig {
+	var cfg finishConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// noopSpan implements Span with zero-cost no-ops.
 // A single instance is shared across all callers when tracing is disabled.
 type noopSpan struct{}
 
 var noopSpanInstance Span = &noopSpan{}
 
-// noopSpanContext satisfies ddtrace.SpanContext for the no-op span.
+// noopSpanContext satisfies SpanContext for the no-op span.
 // A single instance is shared to avoid heap allocations.
 type noopSpanContext struct{}
 
-var noopSpanCtxInstance ddtrace.SpanContext = &noopSpanContext{}
+var noopSpanCtxInstance SpanContext = &noopSpanContext{}
 
-func (*noopSpan) SetTag(string, any)             {}
-func (*noopSpan) SetOperationName(string)        {}
-func (*noopSpan) BaggageItem(string) string      { return "" }
-func (*noopSpan) SetBaggageItem(string, string)  {}
-func (*noopSpan) Finish(...ddtrace.FinishOption) {}
+func (*noopSpan) SetTag(string, any)     {}
+func (*noopSpan) Finish(...FinishOption) {}
 
-func (*noopSpan) Context() ddtrace.SpanContext {
+func (*noopSpan) Context() SpanContext {
 	return noopSpanCtxInstance
 }
 
-func (*noopSpanContext) SpanID() uint64  { return 0 }
-func (*noopSpanContext) TraceID() uint64 { return 0 }
+func (*noopSpanContext) SpanID() string  { return "" }
+func (*noopSpanContext) TraceID() string { return "" }
 
-func (*noopSpanContext) ForeachBaggageItem(
-	func(k, v string) bool,
-) {
-}
-
-// StartSpanFromContext creates a span as a child of the context's active span.
+// StartSpanFromContext creates a span as a child of the context's active span, or of the
+// remote span extracted into the context.
 // Returns a no-op span and the unchanged context when tracing is disabled.
 func StartSpanFromContext(
 	ctx context.Context,
 	operationName string,
-	opts ...ddtrace.StartSpanOption,
 ) (Span, context.Context) {
 	if !apmEnabled.Load() {
 		return noopSpanInstance, ctx
 	}
-	return tracer.StartSpanFromContext(ctx, operationName, opts...)
+	return currentBackend().StartSpanFromContext(ctx, operationName)
 }
 
 // StartSpan creates a new root span.
 // Returns a no-op span when tracing is disabled.
-func StartSpan(operationName string, opts ...ddtrace.StartSpanOption) Span {
+func StartSpan(operationName string) Span {
 	if !apmEnabled.Load() {
 		return noopSpanInstance
 	}
-	return tracer.StartSpan(operationName, opts...)
+	return currentBackend().StartSpan(operationName, nil)
 }
 
-type logger struct{ *zap.Logger }
-
-func (l logger) Log(msg string) {
-	l.Error(msg)
+// ContextWithSpan returns a copy of ctx with span as its active span.
+// Returns the unchanged context when tracing is disabled.
+func ContextWithSpan(ctx context.Context, span Span) context.Context {
+	if !apmEnabled.Load() {
+		return ctx
+	}
+	return currentBackend().ContextWithSpan(ctx, span)
 }
 
 // apmEnabled tracks whether tracing was started (for conditional span creation).
@@ -96,18 +118,32 @@ func (l logger) Log(msg string) {
 // don't race with concurrent readers in tracing hot paths.
 var apmEnabled atomic.Bool
 
-// Start boots the datadog tracer, run this once early in the startup sequence.
+// Backend names accepted by Options.Backend.
+const (
+	BackendDatadog = "datadog"
+	BackendOTLP    = "otlp"
+)
+
+// Options select and configure the tracing backend.
+type Options struct {
+	// Backend is BackendDatadog or BackendOTLP. Empty selects Datadog.
+	Backend string
+	// OTLPEndpoint is the host:port of the OTLP gRPC collector.
+	OTLPEndpoint string
+	// OTLPInsecure disables TLS towards the OTLP collector.
+	OTLPInsecure bool
+}
+
+// Start boots the tracer, run this once early in the startup sequence.
 // Tracing is gated by XMTPD_TRACING_ENABLE at the application config level;
 // callers should only invoke Start() when the feature flag is on.
 //
 // Configuration via environment variables:
 //   - ENV: Environment name (default: "test")
+//   - APM_SAMPLE_RATE: Sample rate 0.0-1.0 (default: 1.0 dev/test, 0.1 prod)
 //   - DD_AGENT_HOST: Datadog agent host (standard DD env var, default: "localhost")
 //   - DD_TRACE_AGENT_PORT: Datadog agent port (standard DD env var, default: "8126")
-//   - APM_SAMPLE_RATE: Sample rate 0.0-1.0 (default: 1.0 dev/test, 0.1 prod)
-func Start(version string, l *zap.Logger) {
-	apmEnabled.Store(true)
-
+func Start(ctx context.Context, version string, l *zap.Logger, options Options) error {
 	env := os.Getenv("ENV")
 	if env == "" {
 		env = "test"
@@ -115,28 +151,43 @@ func Start(version string, l *zap.Logger) {
 
 	sampleRate := getSampleRate(env, l)
 
-	opts := []tracer.StartOption{
-		tracer.WithEnv(env),
-		tracer.WithService("xmtpd"),
-		tracer.WithServiceVersion(version),
-		tracer.WithLogger(logger{l}),
-		tracer.WithRuntimeMetrics(),
+	if options.Backend == "" {
+		options.Backend = BackendDatadog
+	}
+
+	var (
+		b   Backend
+		err error
+	)
+	switch options.Backend {
+	case BackendDatadog:
+		b = startDatadog(env, version, sampleRate, l)
+	case BackendOTLP:
+		b, err = startOTLP(ctx, env, version, sampleRate, options)
+	default:
+		err = fmt.Errorf("unknown tracing backend %q", options.Backend)
 	}
+	if err != nil {
+		return err
+	}
+
+	backend.Store(&b)
+	apmEnabled.Store(true)
 
-	// Add sampler if not 100%
 	if sampleRate < 1.0 {
-		opts = append(opts, tracer.WithSampler(tracer.NewRateSampler(sampleRate)))
 		l.Info("APM tracing enabled with sampling",
+			zap.String("backend", options.Backend),
 			zap.Float64("sample_rate", sampleRate),
 			zap.String("env", env),
 		)
 	} else {
 		l.Info("APM tracing enabled (100% sampling)",
+			zap.String("backend", options.Backend),
 			zap.String("env", env),
 		)
 	}
 
-	tracer.Start(opts...)
+	return nil
 }
 
 // getSampleRate returns the configured sample rate.
@@ -177,9 +228,9 @@ func SetEnabledForTesting(enabled bool) func() {
 	return func() { apmEnabled.Store(prev) }
 }
 
-// Stop shuts down the datadog tracer, defer this right after Start().
+// Stop flushes and shuts down the tracer, defer this right after Start().
 func Stop() {
-	tracer.Stop()
+	currentBackend().Stop()
 }
 
 // Wrap executes action in the context of a span.
@@ -220,7 +271,7 @@ func Wrap(
 
 // PanicWrap executes the body guarding for panics.
 // If panic happens it emits a span with the error attached.
-// This should trigger DD APM's Error Tracking to record the error.
+// This should trigger the APM's error tracking to record the error.
 func PanicWrap(ctx context.Context, name string, body func(context.Context)) {
 	defer func() {
 		r := recover()
@@ -238,29 +289,27 @@ func PanicWrap(ctx context.Context, name string, body func(context.Context)) {
 }
 
 // Link connects a logger to a particular trace and span.
-// DD APM should provide some additional functionality based on that.
+// The log fields are those the backend correlates logs with.
 // Returns the logger unchanged when tracing is disabled.
 func Link(span Span, l *zap.Logger) *zap.Logger {
 	if !apmEnabled.Load() {
 		return l
 	}
-	return l.With(
-		zap.Uint64("dd.trace_id", span.Context().TraceID()),
-		zap.Uint64("dd.span_id", span.Context().SpanID()))
+	return l.With(currentBackend().LogFields(span.Context())...)
 }
 
 func SpanType(span Span, typ string) {
 	if !apmEnabled.Load() {
 		return
 	}
-	span.SetTag(ext.SpanType, typ)
+	span.SetTag(TagSpanType, typ)
 }
 
 func SpanResource(span Span, resource string) {
 	if !apmEnabled.Load() {
 		return
 	}
-	span.SetTag(ext.ResourceName, resource)
+	span.SetTag(TagResourceName, resource)
 }
 
 // SpanTag sets a tag on a span with production safety limits.
@@ -304,12 +353,9 @@ func GoPanicWrap(
 // If parentCtx is nil, creates a new root span. This is useful for async
 // workflows where the parent context may or may not be available.
 // Returns a no-op span when tracing is disabled.
-func StartSpanWithParent(operationName string, parentCtx ddtrace.SpanContext) Span {
+func StartSpanWithParent(operationName string, parentCtx SpanContext) Span {
 	if !apmEnabled.Load() {
 		return noopSpanInstance
 	}
-	if parentCtx != nil {
-		return tracer.StartSpan(operationName, tracer.ChildOf(parentCtx))
-	}
-	return tracer.StartSpan(operationName)
+	return currentBackend().StartSpan(operationName, parentCtx)
 }
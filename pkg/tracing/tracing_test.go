@@ -172,15 +172,21 @@ func TestNoopSpan_WhenDisabled(t *testing.T) {
 	// StartSpan should return no-op
 	span := StartSpan("test.should_be_noop")
 	assert.NotNil(t, span)
-	assert.Equal(t, uint64(0), span.Context().TraceID())
-	assert.Equal(t, uint64(0), span.Context().SpanID())
+	assert.Empty(t, span.Context().TraceID())
+	assert.Empty(t, span.Context().SpanID())
 
 	// All operations should be safe no-ops
 	span.SetTag("key", "value")
-	span.SetOperationName("noop")
-	span.SetBaggageItem("k", "v")
-	assert.Empty(t, span.BaggageItem("k"))
-	span.Finish() // must not panic
+	SpanResource(span, "noop")
+	assert.Empty(t, span.Context().TraceID(), "tags must not give a no-op span an identity")
+
+	// Nothing is propagated from a no-op span
+	carrier := HeaderCarrier{}
+	Inject(ContextWithSpan(context.Background(), span), carrier)
+	assert.Empty(t, carrier.Keys())
+
+	span.Finish(WithError(assert.AnError)) // must not panic
+	span.Finish()                          // finishing twice must not panic either
 
 	// StartSpanFromContext should return no-op and unchanged context
 	ctx := context.Background()
@@ -192,7 +198,7 @@ func TestNoopSpan_WhenDisabled(t *testing.T) {
 	// StartSpanWithParent should return no-op
 	span3 := StartSpanWithParent("test.noop_parent", nil)
 	assert.NotNil(t, span3)
-	assert.Equal(t, uint64(0), span3.Context().TraceID())
+	assert.Empty(t, span3.Context().TraceID())
 	span3.Finish()
 
 	// SpanTag, SpanType, SpanResource should not panic
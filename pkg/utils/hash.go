@@ -36,6 +36,13 @@ func HashOriginatorSignatureInput(unsignedOriginatorEnvelope []byte) []byte {
 	)
 }
 
+func HashMisbehaviorReportInput(unsignedMisbehaviorReport []byte) []byte {
+	return ethcrypto.Keccak256(
+		[]byte(constants.MisbehaviorDomainSeparationLabel),
+		unsignedMisbehaviorReport,
+	)
+}
+
 func HashPayerReportInput(packedBytes []byte, domainSeparator common.Hash) common.Hash {
 	return common.BytesToHash(ethcrypto.Keccak256(
 		[]byte("\x19\x01"),
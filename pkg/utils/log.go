@@ -89,6 +89,7 @@ const (
 
 	// Misc and utilities.
 	PrunerLoggerName             = "pruner-worker"
+	PayerBalanceLoggerName       = "payer-balance-worker"
 	StressChainWatcherLoggerName = "chain-watcher"
 	BlockchainOracleLoggerName   = "blockchain-oracle"
 	DatabaseWorkerLoggerName     = "database-worker"
@@ -302,6 +303,10 @@ func SettlementChainChainIDField(chainID int64) zap.Field {
 	return zap.Int64("settlement_chain_id", chainID)
 }
 
+func SourceNodeIDField(nodeID uint32) zap.Field {
+	return zap.Uint32("source_node_id", nodeID)
+}
+
 func StartingNonceField(startingNonce uint64) zap.Field {
 	return zap.Uint64("starting_nonce", startingNonce)
 }